@@ -11,18 +11,25 @@ import (
 
 	"github.com/autobrr/autobrr/internal/action"
 	"github.com/autobrr/autobrr/internal/api"
+	"github.com/autobrr/autobrr/internal/audit"
 	"github.com/autobrr/autobrr/internal/auth"
+	"github.com/autobrr/autobrr/internal/backup"
+	"github.com/autobrr/autobrr/internal/blocklist"
 	"github.com/autobrr/autobrr/internal/config"
 	"github.com/autobrr/autobrr/internal/database"
 	"github.com/autobrr/autobrr/internal/download_client"
+	"github.com/autobrr/autobrr/internal/enrichment"
 	"github.com/autobrr/autobrr/internal/events"
 	"github.com/autobrr/autobrr/internal/feed"
 	"github.com/autobrr/autobrr/internal/filter"
 	"github.com/autobrr/autobrr/internal/http"
 	"github.com/autobrr/autobrr/internal/indexer"
+	"github.com/autobrr/autobrr/internal/instance"
 	"github.com/autobrr/autobrr/internal/irc"
+	"github.com/autobrr/autobrr/internal/list"
 	"github.com/autobrr/autobrr/internal/logger"
 	"github.com/autobrr/autobrr/internal/notification"
+	"github.com/autobrr/autobrr/internal/quota"
 	"github.com/autobrr/autobrr/internal/release"
 	"github.com/autobrr/autobrr/internal/scheduler"
 	"github.com/autobrr/autobrr/internal/server"
@@ -46,6 +53,10 @@ func main() {
 	// init new logger
 	log := logger.New(cfg.Config)
 
+	for _, e := range cfg.Config.Errors {
+		log.Error().Msgf("config: %v", e)
+	}
+
 	// init dynamic config
 	cfg.DynamicReload(log)
 
@@ -53,6 +64,8 @@ func main() {
 	serverEvents := sse.New()
 	serverEvents.AutoReplay = false
 	serverEvents.CreateStream("logs")
+	serverEvents.CreateStream("releases")
+	serverEvents.CreateStream("irc")
 
 	// register SSE hook on logger
 	log.RegisterSSEHook(serverEvents)
@@ -61,7 +74,10 @@ func main() {
 	bus := EventBus.New()
 
 	// open database connection
-	db, _ := database.NewDB(cfg.Config, log)
+	db, err := database.NewDB(cfg.Config, log)
+	if err != nil {
+		log.Fatal().Err(err).Msg("could not create db connection")
+	}
 	if err := db.Open(); err != nil {
 		log.Fatal().Err(err).Msg("could not open db connection")
 	}
@@ -75,44 +91,59 @@ func main() {
 
 	// setup repos
 	var (
-		apikeyRepo         = database.NewAPIRepo(log, db)
-		downloadClientRepo = database.NewDownloadClientRepo(log, db)
-		actionRepo         = database.NewActionRepo(log, db, downloadClientRepo)
-		filterRepo         = database.NewFilterRepo(log, db)
-		feedRepo           = database.NewFeedRepo(log, db)
-		feedCacheRepo      = database.NewFeedCacheRepo(log, db)
-		indexerRepo        = database.NewIndexerRepo(log, db)
-		ircRepo            = database.NewIrcRepo(log, db)
-		notificationRepo   = database.NewNotificationRepo(log, db)
-		releaseRepo        = database.NewReleaseRepo(log, db)
-		userRepo           = database.NewUserRepo(log, db)
+		apikeyRepo           = database.NewAPIRepo(log, db)
+		auditRepo            = database.NewAuditRepo(log, db)
+		downloadClientRepo   = database.NewDownloadClientRepo(log, db)
+		actionRepo           = database.NewActionRepo(log, db, downloadClientRepo)
+		actionRetryRepo      = database.NewActionRetryRepo(log, db)
+		filterRepo           = database.NewFilterRepo(log, db)
+		feedRepo             = database.NewFeedRepo(log, db)
+		feedCacheRepo        = database.NewFeedCacheRepo(log, db)
+		indexerRepo          = database.NewIndexerRepo(log, db)
+		instanceRepo         = database.NewInstanceRepo(log, db)
+		listRepo             = database.NewListRepo(log, db)
+		ircRepo              = database.NewIrcRepo(log, db)
+		notificationRepo     = database.NewNotificationRepo(log, db)
+		quotaRepo            = database.NewQuotaRepo(log, db)
+		releaseRepo          = database.NewReleaseRepo(log, db)
+		releaseApprovalRepo  = database.NewReleaseApprovalRepo(log, db)
+		userRepo             = database.NewUserRepo(log, db)
+		torrentBlocklistRepo = database.NewTorrentBlocklistRepo(log, db)
+		blocklistRepo        = database.NewAnnounceBlocklistRepo(log, db)
 	)
 
 	// setup services
 	var (
-		apiService            = api.NewService(log, apikeyRepo)
-		notificationService   = notification.NewService(log, notificationRepo)
+		auditService          = audit.NewService(log, auditRepo)
+		apiService            = api.NewService(log, apikeyRepo, auditService)
+		notificationService   = notification.NewService(log, notificationRepo, auditService)
 		schedulingService     = scheduler.NewService(log, version, notificationService)
 		indexerAPIService     = indexer.NewAPIService(log)
 		userService           = user.NewService(userRepo)
-		authService           = auth.NewService(log, userService)
-		downloadClientService = download_client.NewService(log, downloadClientRepo)
-		actionService         = action.NewService(log, actionRepo, downloadClientService, bus)
-		indexerService        = indexer.NewService(log, cfg.Config, indexerRepo, indexerAPIService, schedulingService)
-		filterService         = filter.NewService(log, filterRepo, actionRepo, indexerAPIService, indexerService)
-		releaseService        = release.NewService(log, releaseRepo, actionService, filterService)
-		ircService            = irc.NewService(log, ircRepo, releaseService, indexerService, notificationService)
-		feedService           = feed.NewService(log, feedRepo, feedCacheRepo, releaseService, schedulingService)
+		authService           = auth.NewService(log, cfg.Config, userService)
+		downloadClientService = download_client.NewService(log, downloadClientRepo, schedulingService, notificationService, auditService)
+		actionService         = action.NewService(log, actionRepo, downloadClientService, torrentBlocklistRepo, bus)
+		indexerService        = indexer.NewService(log, cfg.Config, indexerRepo, indexerAPIService, schedulingService, bus, auditService)
+		quotaService          = quota.NewService(log, quotaRepo, auditService)
+		blocklistService      = blocklist.NewService(log, blocklistRepo, auditService)
+		listService           = list.NewService(log, listRepo, downloadClientService, schedulingService, auditService)
+		filterService         = filter.NewService(log, cfg.Config, filterRepo, actionRepo, indexerAPIService, indexerService, quotaService, listService, auditService)
+		instanceService       = instance.NewService(log, cfg.Config, instanceRepo)
+		backupService         = backup.NewService(log, cfg.Config, db, instanceService)
+		enrichmentService     = enrichment.NewService(log, enrichment.NewTorrentFileEnricher())
+		releaseService        = release.NewService(log, cfg.Config, releaseRepo, actionRetryRepo, releaseApprovalRepo, actionService, filterService, blocklistService, instanceService, enrichmentService, notificationService, bus)
+		ircService            = irc.NewService(log, cfg.Config, ircRepo, releaseService, indexerService, notificationService, schedulingService, bus, auditService)
+		feedService           = feed.NewService(log, cfg.Config, feedRepo, feedCacheRepo, releaseService, filterService, downloadClientService, schedulingService, auditService)
 	)
 
 	// register event subscribers
-	events.NewSubscribers(log, bus, notificationService, releaseService)
+	events.NewSubscribers(log, bus, serverEvents, notificationService, releaseService, ircService)
 
 	errorChannel := make(chan error)
 
 	go func() {
 		httpServer := http.NewServer(
-			cfg.Config,
+			cfg,
 			serverEvents,
 			db,
 			version,
@@ -120,19 +151,24 @@ func main() {
 			date,
 			actionService,
 			apiService,
+			auditService,
 			authService,
+			backupService,
+			blocklistService,
 			downloadClientService,
 			filterService,
 			feedService,
 			indexerService,
 			ircService,
+			listService,
 			notificationService,
+			quotaService,
 			releaseService,
 		)
 		errorChannel <- httpServer.Open()
 	}()
 
-	srv := server.NewServer(log, ircService, indexerService, feedService, schedulingService)
+	srv := server.NewServer(log, ircService, indexerService, feedService, instanceService, schedulingService, downloadClientService, releaseService, backupService, filterService, listService, blocklistService)
 	srv.Hostname = cfg.Config.Host
 	srv.Port = cfg.Config.Port
 
@@ -147,10 +183,8 @@ func main() {
 	for sig := range sigCh {
 		switch sig {
 		case syscall.SIGHUP:
-			log.Log().Msg("shutting down server sighup")
-			srv.Shutdown()
-			db.Close()
-			os.Exit(1)
+			log.Log().Msg("reloading config on sighup")
+			cfg.Reload(log)
 		case syscall.SIGINT, syscall.SIGQUIT:
 			srv.Shutdown()
 			db.Close()
@@ -0,0 +1,111 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/pkg/errors"
+)
+
+// apiKeyEnv can hold the api key instead of passing --api-key
+const apiKeyEnv = "AUTOBRRCTL_API_KEY"
+
+// apiClient talks to the api of a running autobrr, authenticated with an api key
+type apiClient struct {
+	baseURL string
+	apiKey  string
+	http    *http.Client
+}
+
+func newAPIClient(baseURL string, apiKey string) (*apiClient, error) {
+	if baseURL == "" {
+		return nil, errors.New("--host or --config is required")
+	}
+
+	if apiKey == "" {
+		return nil, errors.New("--api-key or %v is required, create one under Settings > API keys", apiKeyEnv)
+	}
+
+	return &apiClient{
+		baseURL: strings.TrimSuffix(baseURL, "/") + "/",
+		apiKey:  apiKey,
+		http:    &http.Client{Timeout: 60 * time.Second},
+	}, nil
+}
+
+// configBaseURL is the address autobrr listens on according to the config, wildcard hosts are reached on localhost
+func configBaseURL(cfg *domain.Config) string {
+	host := cfg.Host
+	if host == "" || host == "0.0.0.0" || host == "::" {
+		host = "127.0.0.1"
+	}
+
+	base := "/" + strings.Trim(cfg.BaseURL, "/")
+	if base != "/" {
+		base += "/"
+	}
+
+	return fmt.Sprintf("http://%s:%d%s", host, cfg.Port, base)
+}
+
+// do sends the request and decodes the response into out, error responses are returned as errors with their message
+func (c *apiClient) do(ctx context.Context, method string, path string, body interface{}, out interface{}) error {
+	var reqBody io.Reader
+	if body != nil {
+		b, err := json.Marshal(body)
+		if err != nil {
+			return errors.Wrap(err, "could not encode request")
+		}
+		reqBody = bytes.NewReader(b)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, reqBody)
+	if err != nil {
+		return errors.Wrap(err, "could not create request")
+	}
+
+	req.Header.Set("X-API-Token", c.apiKey)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	res, err := c.http.Do(req)
+	if err != nil {
+		return errors.Wrap(err, "could not reach autobrr")
+	}
+
+	defer res.Body.Close()
+
+	if res.StatusCode >= http.StatusMultipleChoices {
+		var e struct {
+			Message string `json:"message"`
+		}
+
+		if json.NewDecoder(res.Body).Decode(&e) == nil && e.Message != "" {
+			return errors.New("%v", e.Message)
+		}
+
+		if res.StatusCode == http.StatusUnauthorized {
+			return errors.New("unauthorized, check the api key")
+		}
+
+		return errors.New("unexpected status: %v", res.Status)
+	}
+
+	if out == nil || res.StatusCode == http.StatusNoContent {
+		return nil
+	}
+
+	if err := json.NewDecoder(res.Body).Decode(out); err != nil {
+		return errors.Wrap(err, "could not decode response")
+	}
+
+	return nil
+}
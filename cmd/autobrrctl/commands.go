@@ -0,0 +1,417 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"net/url"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/pkg/errors"
+)
+
+// eventPollInterval is how often new release events are requested when following them
+const eventPollInterval = 2 * time.Second
+
+// errUsage is returned for unknown commands and missing arguments, the usage is printed instead of the error
+var errUsage = errors.New("invalid usage")
+
+// runAPICommand runs a management command against the api of a running autobrr
+func runAPICommand(ctx context.Context, c *apiClient, out io.Writer, cmd string, args []string) error {
+	var sub string
+	if len(args) > 0 {
+		sub, args = args[0], args[1:]
+	}
+
+	switch cmd {
+	case "filter":
+		switch sub {
+		case "list":
+			return listFilters(ctx, c, out)
+		case "enable", "disable":
+			return toggleFilters(ctx, c, out, args, sub == "enable")
+		}
+
+	case "indexer":
+		switch sub {
+		case "list":
+			return listIndexers(ctx, c, out)
+		case "test":
+			return testIndexer(ctx, c, out, args)
+		}
+
+	case "client":
+		switch sub {
+		case "list":
+			return listClients(ctx, c, out)
+		case "test":
+			return testClient(ctx, c, out, args)
+		case "enable", "disable":
+			return toggleClient(ctx, c, out, args, sub == "enable")
+		}
+
+	case "feed":
+		switch sub {
+		case "list":
+			return listFeeds(ctx, c, out)
+		case "fetch":
+			return fetchFeed(ctx, c, out, args)
+		}
+
+	case "events":
+		// events takes its flags directly, eg. events -f -type MATCH
+		return tailEvents(ctx, c, out, append([]string{sub}, args...))
+	}
+
+	return errUsage
+}
+
+// matches reports whether ref is the id or, case-insensitive, one of the names
+func matches(ref string, id int64, names ...string) bool {
+	if n, err := strconv.ParseInt(ref, 10, 64); err == nil {
+		return n == id
+	}
+
+	for _, name := range names {
+		if strings.EqualFold(ref, name) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func enabledString(enabled bool) string {
+	if enabled {
+		return "enabled"
+	}
+	return "disabled"
+}
+
+func listFilters(ctx context.Context, c *apiClient, out io.Writer) error {
+	var filters []domain.Filter
+	if err := c.do(ctx, "GET", "api/filters", nil, &filters); err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tNAME\tSTATUS\tPRIORITY\tINDEXERS")
+
+	for _, f := range filters {
+		indexers := make([]string, 0, len(f.Indexers))
+		for _, i := range f.Indexers {
+			indexers = append(indexers, i.Identifier)
+		}
+
+		fmt.Fprintf(w, "%d\t%s\t%s\t%d\t%s\n", f.ID, f.Name, enabledString(f.Enabled), f.Priority, strings.Join(indexers, ","))
+	}
+
+	return w.Flush()
+}
+
+// toggleFilters enables or disables the filters given by id or name
+func toggleFilters(ctx context.Context, c *apiClient, out io.Writer, refs []string, enabled bool) error {
+	if len(refs) == 0 {
+		return errUsage
+	}
+
+	var filters []domain.Filter
+	if err := c.do(ctx, "GET", "api/filters", nil, &filters); err != nil {
+		return err
+	}
+
+	for _, ref := range refs {
+		var filter *domain.Filter
+		for i := range filters {
+			if matches(ref, int64(filters[i].ID), filters[i].Name) {
+				filter = &filters[i]
+				break
+			}
+		}
+
+		if filter == nil {
+			return errors.New("filter not found: %v", ref)
+		}
+
+		body := map[string]bool{"enabled": enabled}
+		if err := c.do(ctx, "PUT", fmt.Sprintf("api/filters/%d/enabled", filter.ID), body, nil); err != nil {
+			return errors.Wrap(err, "could not update filter: %v", filter.Name)
+		}
+
+		fmt.Fprintf(out, "filter %s %s\n", filter.Name, enabledString(enabled))
+	}
+
+	return nil
+}
+
+func listIndexers(ctx context.Context, c *apiClient, out io.Writer) error {
+	var indexers []domain.Indexer
+	if err := c.do(ctx, "GET", "api/indexer/options", nil, &indexers); err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tIDENTIFIER\tNAME\tIMPLEMENTATION\tSTATUS")
+
+	for _, i := range indexers {
+		fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%s\n", i.ID, i.Identifier, i.Name, i.Implementation, enabledString(i.Enabled))
+	}
+
+	return w.Flush()
+}
+
+// testIndexer tests the feed of torznab, rss and api feed indexers and the api of the other indexers
+func testIndexer(ctx context.Context, c *apiClient, out io.Writer, refs []string) error {
+	if len(refs) != 1 {
+		return errUsage
+	}
+
+	var indexers []domain.Indexer
+	if err := c.do(ctx, "GET", "api/indexer/options", nil, &indexers); err != nil {
+		return err
+	}
+
+	var indexer *domain.Indexer
+	for i := range indexers {
+		if matches(refs[0], indexers[i].ID, indexers[i].Identifier, indexers[i].Name) {
+			indexer = &indexers[i]
+			break
+		}
+	}
+
+	if indexer == nil {
+		return errors.New("indexer not found: %v", refs[0])
+	}
+
+	var feeds []domain.Feed
+	if err := c.do(ctx, "GET", "api/feeds", nil, &feeds); err != nil {
+		return err
+	}
+
+	for _, feed := range feeds {
+		if feed.Indexer != indexer.Identifier {
+			continue
+		}
+
+		if err := c.do(ctx, "POST", "api/feeds/test", feed, nil); err != nil {
+			return errors.Wrap(err, "feed test failed for indexer: %v", indexer.Name)
+		}
+
+		fmt.Fprintf(out, "indexer %s: feed %s OK\n", indexer.Name, feed.Name)
+		return nil
+	}
+
+	if err := c.do(ctx, "POST", fmt.Sprintf("api/indexer/%d/api/test", indexer.ID), nil, nil); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(out, "indexer %s: api OK\n", indexer.Name)
+
+	return nil
+}
+
+func listClients(ctx context.Context, c *apiClient, out io.Writer) error {
+	var clients []domain.DownloadClient
+	if err := c.do(ctx, "GET", "api/download_clients", nil, &clients); err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tNAME\tTYPE\tHOST\tSTATUS")
+
+	for _, dc := range clients {
+		host := dc.Host
+		if dc.Port > 0 {
+			host = fmt.Sprintf("%s:%d", dc.Host, dc.Port)
+		}
+
+		fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%s\n", dc.ID, dc.Name, dc.Type, host, enabledString(dc.Enabled))
+	}
+
+	return w.Flush()
+}
+
+func findClient(ctx context.Context, c *apiClient, ref string) (*domain.DownloadClient, error) {
+	var clients []domain.DownloadClient
+	if err := c.do(ctx, "GET", "api/download_clients", nil, &clients); err != nil {
+		return nil, err
+	}
+
+	for i := range clients {
+		if matches(ref, int64(clients[i].ID), clients[i].Name) {
+			return &clients[i], nil
+		}
+	}
+
+	return nil, errors.New("download client not found: %v", ref)
+}
+
+func testClient(ctx context.Context, c *apiClient, out io.Writer, refs []string) error {
+	if len(refs) != 1 {
+		return errUsage
+	}
+
+	client, err := findClient(ctx, c, refs[0])
+	if err != nil {
+		return err
+	}
+
+	if err := c.do(ctx, "POST", "api/download_clients/test", client, nil); err != nil {
+		return errors.Wrap(err, "test failed for download client: %v", client.Name)
+	}
+
+	fmt.Fprintf(out, "download client %s OK\n", client.Name)
+
+	return nil
+}
+
+func toggleClient(ctx context.Context, c *apiClient, out io.Writer, refs []string, enabled bool) error {
+	if len(refs) != 1 {
+		return errUsage
+	}
+
+	client, err := findClient(ctx, c, refs[0])
+	if err != nil {
+		return err
+	}
+
+	client.Enabled = enabled
+	if err := c.do(ctx, "PUT", "api/download_clients", client, nil); err != nil {
+		return errors.Wrap(err, "could not update download client: %v", client.Name)
+	}
+
+	fmt.Fprintf(out, "download client %s %s\n", client.Name, enabledString(enabled))
+
+	return nil
+}
+
+func listFeeds(ctx context.Context, c *apiClient, out io.Writer) error {
+	var feeds []domain.Feed
+	if err := c.do(ctx, "GET", "api/feeds", nil, &feeds); err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tNAME\tINDEXER\tTYPE\tINTERVAL\tSTATUS")
+
+	for _, f := range feeds {
+		fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%dm\t%s\n", f.ID, f.Name, f.Indexer, f.Type, f.Interval, enabledString(f.Enabled))
+	}
+
+	return w.Flush()
+}
+
+func fetchFeed(ctx context.Context, c *apiClient, out io.Writer, refs []string) error {
+	if len(refs) != 1 {
+		return errUsage
+	}
+
+	var feeds []domain.Feed
+	if err := c.do(ctx, "GET", "api/feeds", nil, &feeds); err != nil {
+		return err
+	}
+
+	for _, f := range feeds {
+		if !matches(refs[0], int64(f.ID), f.Name, f.Indexer) {
+			continue
+		}
+
+		if err := c.do(ctx, "POST", fmt.Sprintf("api/feeds/%d/fetch", f.ID), nil, nil); err != nil {
+			return err
+		}
+
+		fmt.Fprintf(out, "feed %s fetching\n", f.Name)
+		return nil
+	}
+
+	return errors.New("feed not found: %v", refs[0])
+}
+
+// tailEvents prints the latest release events, oldest first, and with -f keeps printing new events
+func tailEvents(ctx context.Context, c *apiClient, out io.Writer, args []string) error {
+	fs := flag.NewFlagSet("events", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+
+	limit := fs.Int("n", 20, "number of events to print")
+	follow := fs.Bool("f", false, "keep printing new events")
+	types := fs.String("type", "", "comma separated event types: ANNOUNCE, MATCH, ACTION, ERROR, BLOCKED")
+	indexers := fs.String("indexer", "", "comma separated indexer identifiers")
+
+	// args start with an empty sub command when events is run without flags
+	if len(args) > 0 && args[0] == "" {
+		args = args[1:]
+	}
+
+	if err := fs.Parse(args); err != nil || fs.NArg() > 0 {
+		return errUsage
+	}
+
+	params := url.Values{}
+	for _, t := range strings.Split(*types, ",") {
+		if t = strings.TrimSpace(t); t != "" {
+			params.Add("type", strings.ToUpper(t))
+		}
+	}
+	for _, i := range strings.Split(*indexers, ",") {
+		if i = strings.TrimSpace(i); i != "" {
+			params.Add("indexer", i)
+		}
+	}
+
+	params.Set("limit", strconv.Itoa(*limit))
+
+	var since time.Time
+
+	for {
+		var events []domain.ReleaseEvent
+		if err := c.do(ctx, "GET", "api/release/events?"+params.Encode(), nil, &events); err != nil {
+			return err
+		}
+
+		// events are returned newest first
+		for i := len(events) - 1; i >= 0; i-- {
+			printEvent(out, events[i])
+		}
+
+		if len(events) > 0 {
+			since = events[0].Timestamp
+		}
+
+		if !*follow {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(eventPollInterval):
+		}
+
+		// the buffer holds the recent events, when following only the ones after the last printed are requested
+		params.Del("limit")
+		if !since.IsZero() {
+			params.Set("since", since.Format(time.RFC3339Nano))
+		}
+	}
+}
+
+func printEvent(out io.Writer, e domain.ReleaseEvent) {
+	line := fmt.Sprintf("%s %-8s %s %s", e.Timestamp.Local().Format("2006-01-02 15:04:05"), e.Type, e.Indexer, e.TorrentName)
+
+	if e.Filter != "" {
+		line += " filter=" + e.Filter
+	}
+	if e.Action != "" {
+		line += " action=" + e.Action
+	}
+	if e.Message != "" {
+		line += " " + e.Message
+	}
+
+	fmt.Fprintln(out, line)
+}
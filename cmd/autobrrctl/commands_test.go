@@ -0,0 +1,121 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/autobrr/autobrr/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type apiRequest struct {
+	method string
+	path   string
+	body   string
+}
+
+// newTestAPI serves the responses by method and path and records the requests
+func newTestAPI(t *testing.T, responses map[string]interface{}) (*apiClient, *[]apiRequest) {
+	var requests []apiRequest
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "secret", r.Header.Get("X-API-Token"))
+
+		body, _ := io.ReadAll(r.Body)
+		requests = append(requests, apiRequest{method: r.Method, path: r.URL.RequestURI(), body: string(body)})
+
+		res, ok := responses[r.Method+" "+r.URL.Path]
+		if !ok {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+
+		if status, ok := res.(int); ok {
+			w.WriteHeader(status)
+			json.NewEncoder(w).Encode(map[string]string{"message": "something failed"})
+			return
+		}
+
+		json.NewEncoder(w).Encode(res)
+	}))
+	t.Cleanup(srv.Close)
+
+	c, err := newAPIClient(srv.URL+"/autobrr", "secret")
+	assert.NoError(t, err)
+
+	return c, &requests
+}
+
+func Test_configBaseURL(t *testing.T) {
+	tests := []struct {
+		name   string
+		config domain.Config
+		want   string
+	}{
+		{name: "default", config: domain.Config{Host: "localhost", Port: 7474}, want: "http://localhost:7474/"},
+		{name: "wildcard", config: domain.Config{Host: "0.0.0.0", Port: 7474, BaseURL: "/autobrr/"}, want: "http://127.0.0.1:7474/autobrr/"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, configBaseURL(&tt.config))
+		})
+	}
+}
+
+func Test_runAPICommand_filter(t *testing.T) {
+	c, requests := newTestAPI(t, map[string]interface{}{
+		"GET /autobrr/api/filters": []domain.Filter{{ID: 1, Name: "movies", Enabled: true}, {ID: 2, Name: "shows"}},
+	})
+
+	var out bytes.Buffer
+	assert.NoError(t, runAPICommand(context.Background(), c, &out, "filter", []string{"disable", "Movies"}))
+	assert.NoError(t, runAPICommand(context.Background(), c, &out, "filter", []string{"enable", "2"}))
+	assert.EqualError(t, runAPICommand(context.Background(), c, &out, "filter", []string{"enable", "other"}), "filter not found: other")
+
+	assert.Equal(t, "filter movies disabled\nfilter shows enabled\n", out.String())
+	assert.Contains(t, *requests, apiRequest{method: "PUT", path: "/autobrr/api/filters/1/enabled", body: "{\"enabled\":false}"})
+	assert.Contains(t, *requests, apiRequest{method: "PUT", path: "/autobrr/api/filters/2/enabled", body: "{\"enabled\":true}"})
+}
+
+func Test_runAPICommand_indexerTest(t *testing.T) {
+	c, requests := newTestAPI(t, map[string]interface{}{
+		"GET /autobrr/api/indexer/options":     []domain.Indexer{{ID: 1, Name: "Torznab", Identifier: "torznab-mock"}, {ID: 2, Name: "Mock", Identifier: "mock"}},
+		"GET /autobrr/api/feeds":               []domain.Feed{{ID: 5, Name: "Torznab feed", Indexer: "torznab-mock"}},
+		"POST /autobrr/api/indexer/2/api/test": http.StatusBadRequest,
+	})
+
+	var out bytes.Buffer
+	assert.NoError(t, runAPICommand(context.Background(), c, &out, "indexer", []string{"test", "torznab-mock"}))
+	assert.EqualError(t, runAPICommand(context.Background(), c, &out, "indexer", []string{"test", "mock"}), "something failed")
+
+	assert.Equal(t, "indexer Torznab: feed Torznab feed OK\n", out.String())
+	assert.Equal(t, "POST", (*requests)[2].method)
+	assert.Equal(t, "/autobrr/api/feeds/test", (*requests)[2].path)
+}
+
+func Test_runAPICommand_events(t *testing.T) {
+	now := time.Date(2022, 10, 1, 12, 0, 0, 0, time.Local)
+
+	c, requests := newTestAPI(t, map[string]interface{}{
+		"GET /autobrr/api/release/events": []domain.ReleaseEvent{
+			{Timestamp: now.Add(time.Second), Type: domain.ReleaseEventMatch, Indexer: "mock", TorrentName: "Second", Filter: "movies"},
+			{Timestamp: now, Type: domain.ReleaseEventAnnounce, Indexer: "mock", TorrentName: "First"},
+		},
+	})
+
+	var out bytes.Buffer
+	assert.NoError(t, runAPICommand(context.Background(), c, &out, "events", []string{"-n", "2", "-type", "announce,match"}))
+
+	assert.Equal(t, "2022-10-01 12:00:00 ANNOUNCE mock First\n2022-10-01 12:00:01 MATCH    mock Second filter=movies\n", out.String())
+	assert.Equal(t, "/autobrr/api/release/events?limit=2&type=ANNOUNCE&type=MATCH", (*requests)[0].path)
+
+	assert.ErrorIs(t, runAPICommand(context.Background(), c, &out, "events", []string{"-x"}), errUsage)
+	assert.ErrorIs(t, runAPICommand(context.Background(), c, &out, "filter", nil), errUsage)
+}
@@ -20,11 +20,25 @@ import (
 )
 
 const usage = `usage: autobrrctl --config path <action>
+       autobrrctl [--config path | --host url] [--api-key key] <command>
 
   create-user		 <username>		Create user
   change-password	 <username>		Change password for user
   version					Print version info
   help						Show this help message
+
+Commands using the api of the running autobrr, the api key can be set in AUTOBRRCTL_API_KEY:
+
+  filter list					List filters
+  filter enable|disable	 <id|name>...		Enable or disable filters
+  indexer list					List indexers
+  indexer test		 <id|identifier>		Test the feed or api connection of an indexer
+  client list					List download clients
+  client test		 <id|name>		Test a download client
+  client enable|disable	 <id|name>		Enable or disable a download client
+  feed list					List feeds
+  feed fetch		 <id|name>		Fetch a feed now
+  events		 [-n 20] [-f] [-type t] [-indexer i]	Print release events, -f keeps following
 `
 
 func init() {
@@ -40,29 +54,30 @@ var (
 )
 
 func main() {
-	var configPath string
+	var configPath, host, apiKey string
 	flag.StringVar(&configPath, "config", "", "path to configuration file")
+	flag.StringVar(&host, "host", "", "url of autobrr, read from the config by default")
+	flag.StringVar(&apiKey, "api-key", os.Getenv(apiKeyEnv), "api key for the api commands")
 	flag.Parse()
 
-	if configPath == "" {
-		log.Fatal("--config required")
-	}
-
-	// read config
-	cfg := config.New(configPath, version)
-
-	// init new logger
-	l := logger.New(cfg.Config)
-
-	// open database connection
-	db, _ := database.NewDB(cfg.Config, l)
-	if err := db.Open(); err != nil {
-		log.Fatal("could not open db connection")
-	}
+	switch cmd := flag.Arg(0); cmd {
+	case "filter", "indexer", "client", "feed", "events":
+		if host == "" && configPath != "" {
+			host = configBaseURL(config.New(configPath, version).Config)
+		}
 
-	userRepo := database.NewUserRepo(l, db)
+		c, err := newAPIClient(host, apiKey)
+		if err != nil {
+			log.Fatal(err)
+		}
 
-	switch cmd := flag.Arg(0); cmd {
+		if err := runAPICommand(context.Background(), c, os.Stdout, cmd, flag.Args()[1:]); err != nil {
+			if errors.Is(err, errUsage) {
+				flag.Usage()
+				os.Exit(1)
+			}
+			log.Fatal(err)
+		}
 	case "version":
 		fmt.Fprintf(flag.CommandLine.Output(), "Version: %v\nCommit: %v\nBuild: %v\n", version, commit, date)
 	case "create-user":
@@ -72,6 +87,8 @@ func main() {
 			os.Exit(1)
 		}
 
+		userRepo := openUserRepo(configPath)
+
 		password, err := readPassword()
 		if err != nil {
 			log.Fatalf("failed to read password: %v", err)
@@ -95,6 +112,8 @@ func main() {
 			os.Exit(1)
 		}
 
+		userRepo := openUserRepo(configPath)
+
 		user, err := userRepo.FindByUsername(context.Background(), username)
 		if err != nil {
 			log.Fatalf("failed to get user: %v", err)
@@ -125,6 +144,27 @@ func main() {
 	}
 }
 
+// openUserRepo opens the database of the config, users are managed without a running autobrr
+func openUserRepo(configPath string) domain.UserRepo {
+	if configPath == "" {
+		log.Fatal("--config required")
+	}
+
+	// read config
+	cfg := config.New(configPath, version)
+
+	// init new logger
+	l := logger.New(cfg.Config)
+
+	// open database connection
+	db, _ := database.NewDB(cfg.Config, l)
+	if err := db.Open(); err != nil {
+		log.Fatal("could not open db connection")
+	}
+
+	return database.NewUserRepo(l, db)
+}
+
 func readPassword() ([]byte, error) {
 	var password []byte
 	var err error
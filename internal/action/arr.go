@@ -0,0 +1,123 @@
+package action
+
+import (
+	"strings"
+
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/pkg/arr"
+	"github.com/autobrr/autobrr/pkg/errors"
+	"github.com/autobrr/autobrr/pkg/radarr"
+	"github.com/autobrr/autobrr/pkg/sonarr"
+)
+
+// arrDuplicateHistorySize is the number of history events checked for duplicates
+const arrDuplicateHistorySize = 100
+
+// arrHistoryClient is implemented by the sonarr, radarr, lidarr and whisparr clients
+type arrHistoryClient interface {
+	GetQueue() ([]arr.QueueRecord, error)
+	GetHistory(pageSize int) ([]arr.HistoryRecord, error)
+}
+
+// arrDuplicateRejections returns a rejection if the release is already queued, grabbed or imported.
+// Lookup errors are logged and the push goes ahead, a slow or broken history endpoint should not block grabs.
+func (s *service) arrDuplicateRejections(client arrHistoryClient, name string, title string) []string {
+	queue, err := client.GetQueue()
+	if err != nil {
+		s.log.Warn().Err(err).Msgf("%v: could not check queue for duplicates: %v", name, title)
+		return nil
+	}
+
+	history, err := client.GetHistory(arrDuplicateHistorySize)
+	if err != nil {
+		s.log.Warn().Err(err).Msgf("%v: could not check history for duplicates: %v", name, title)
+		return nil
+	}
+
+	if reason := arr.FindDuplicate(title, queue, history); reason != "" {
+		return []string{reason}
+	}
+
+	return nil
+}
+
+// arrDownloadClient returns the download client override of the action, the name can use macros
+func arrDownloadClient(action domain.Action, release domain.Release) (int32, string, error) {
+	if action.ExternalDownloadClient == "" {
+		return action.ExternalDownloadClientID, "", nil
+	}
+
+	name, err := domain.NewMacro(release).Parse(action.ExternalDownloadClient)
+	if err != nil {
+		return 0, "", errors.Wrap(err, "could not parse download client macro: %v", action.ExternalDownloadClient)
+	}
+
+	return action.ExternalDownloadClientID, name, nil
+}
+
+// arrIndexerFlagValues are the bits an arr uses for each flag
+type arrIndexerFlagValues struct {
+	freeleech   int
+	halfleech   int
+	freeleech75 int
+	freeleech25 int
+	internal    int
+	scene       int
+}
+
+var (
+	radarrIndexerFlags = arrIndexerFlagValues{
+		freeleech:   radarr.IndexerFlagFreeleech,
+		halfleech:   radarr.IndexerFlagHalfleech,
+		freeleech75: radarr.IndexerFlagFreeleech75,
+		freeleech25: radarr.IndexerFlagFreeleech25,
+		internal:    radarr.IndexerFlagInternal,
+		scene:       radarr.IndexerFlagScene,
+	}
+	sonarrIndexerFlags = arrIndexerFlagValues{
+		freeleech:   sonarr.IndexerFlagFreeleech,
+		halfleech:   sonarr.IndexerFlagHalfleech,
+		freeleech75: sonarr.IndexerFlagFreeleech75,
+		freeleech25: sonarr.IndexerFlagFreeleech25,
+		internal:    sonarr.IndexerFlagInternal,
+		scene:       sonarr.IndexerFlagScene,
+	}
+)
+
+// arrIndexerFlags derives the indexer flags of the release the way an indexer would report them to the arr
+func arrIndexerFlags(release domain.Release, values arrIndexerFlagValues) int {
+	flags := 0
+
+	switch release.FreeleechPercent {
+	case 75:
+		flags |= values.freeleech75
+	case 50:
+		flags |= values.halfleech
+	case 25:
+		flags |= values.freeleech25
+	default:
+		if release.FreeleechPercent == 100 || (release.Freeleech && release.FreeleechPercent == 0) {
+			flags |= values.freeleech
+		}
+	}
+
+	if strings.EqualFold(release.Origin, "INTERNAL") || containsFold(release.Other, "INTERNAL") {
+		flags |= values.internal
+	}
+
+	if strings.EqualFold(release.Origin, "SCENE") {
+		flags |= values.scene
+	}
+
+	return flags
+}
+
+func containsFold(values []string, s string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, s) {
+			return true
+		}
+	}
+
+	return false
+}
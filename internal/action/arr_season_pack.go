@@ -0,0 +1,133 @@
+package action
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/pkg/arr"
+)
+
+// arrSeriesTTL is how long the series list is reused before the arr is asked again
+const arrSeriesTTL = 10 * time.Minute
+
+// arrEpisodeFileClient is implemented by the sonarr and whisparr clients
+type arrEpisodeFileClient interface {
+	GetSeries(tvdbID int) ([]arr.Series, error)
+	GetEpisodeFiles(seriesID int) ([]arr.EpisodeFile, error)
+}
+
+// arrSeasonPackRejections returns a rejection if the release is a season pack and at least threshold percent
+// of the episodes of that season already have a file on disk. Series the arr does not know are left for the
+// arr to decide on push. Lookup errors are logged and the push goes ahead.
+func (s *service) arrSeasonPackRejections(clientID int, client arrEpisodeFileClient, name string, release domain.Release, threshold int) []string {
+	if threshold <= 0 || release.Season == 0 || release.Episode > 0 || release.Title == "" {
+		return nil
+	}
+
+	// the series list is cached per download client, episode files are always fetched fresh
+	list, err := s.arrSeries.get(clientID, time.Now(), func() ([]arr.Series, error) {
+		return client.GetSeries(0)
+	})
+	if err != nil {
+		s.log.Warn().Err(err).Msgf("%v: could not check series for season pack: %v", name, release.TorrentName)
+		return nil
+	}
+
+	series := findArrSeries(list, release.Title, release.Year)
+	if series == nil {
+		return nil
+	}
+
+	total := 0
+	for _, season := range series.Seasons {
+		if season.SeasonNumber != release.Season {
+			continue
+		}
+
+		total = season.Statistics.TotalEpisodeCount
+		if total == 0 {
+			total = season.Statistics.EpisodeCount
+		}
+	}
+
+	if total == 0 {
+		return nil
+	}
+
+	files, err := client.GetEpisodeFiles(series.ID)
+	if err != nil {
+		s.log.Warn().Err(err).Msgf("%v: could not check episode files for season pack: %v", name, release.TorrentName)
+		return nil
+	}
+
+	onDisk := 0
+	for _, file := range files {
+		if file.SeasonNumber == release.Season {
+			onDisk++
+		}
+	}
+
+	// a file can hold more than one episode, the count is a lower bound
+	if percent := onDisk * 100 / total; percent >= threshold {
+		return []string{fmt.Sprintf("season pack: %d of %d episodes of %v season %d already on disk in %v (%d%%)", onDisk, total, series.Title, release.Season, name, percent)}
+	}
+
+	return nil
+}
+
+// findArrSeries finds the series by title or alternate title, the year decides between series with the same title
+func findArrSeries(list []arr.Series, title string, year int) *arr.Series {
+	normalized := normalizeSeriesTitle(title)
+
+	var found *arr.Series
+	for i := range list {
+		if !arrSeriesHasTitle(list[i], normalized) {
+			continue
+		}
+
+		if year > 0 && list[i].Year == year {
+			return &list[i]
+		}
+
+		if found == nil {
+			found = &list[i]
+		}
+	}
+
+	return found
+}
+
+func arrSeriesHasTitle(series arr.Series, normalized string) bool {
+	if normalizeSeriesTitle(series.Title) == normalized {
+		return true
+	}
+
+	for _, alt := range series.AlternateTitles {
+		if normalizeSeriesTitle(alt.Title) == normalized {
+			return true
+		}
+	}
+
+	return false
+}
+
+var (
+	seriesYearSuffix = regexp.MustCompile(`\s*\(\d{4}\)$`)
+	seriesReplacer   = strings.NewReplacer("'", "", "’", "", "&", " and ")
+)
+
+// normalizeSeriesTitle makes arr series titles and parsed release titles comparable.
+// The year suffix of arr titles is dropped, apostrophes are removed without splitting the word
+// as release names do (Grey's Anatomy is Greys.Anatomy), & is and, and only letters and digits
+// are kept separated by single spaces.
+func normalizeSeriesTitle(title string) string {
+	title = seriesReplacer.Replace(strings.ToLower(seriesYearSuffix.ReplaceAllString(title, "")))
+
+	return strings.Join(strings.FieldsFunc(title, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}), " ")
+}
@@ -0,0 +1,149 @@
+package action
+
+import (
+	"testing"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/pkg/arr"
+	"github.com/autobrr/autobrr/pkg/errors"
+)
+
+type mockArrEpisodeFiles struct {
+	series []arr.Series
+	files  map[int][]arr.EpisodeFile
+	err    error
+
+	seriesCalls int
+}
+
+func (m *mockArrEpisodeFiles) GetSeries(tvdbID int) ([]arr.Series, error) {
+	m.seriesCalls++
+	return m.series, m.err
+}
+
+func (m *mockArrEpisodeFiles) GetEpisodeFiles(seriesID int) ([]arr.EpisodeFile, error) {
+	return m.files[seriesID], m.err
+}
+
+func Test_service_arrSeasonPackRejections(t *testing.T) {
+	files := func(seriesID int, season int, n int) []arr.EpisodeFile {
+		ret := make([]arr.EpisodeFile, 0, n)
+		for i := 0; i < n; i++ {
+			ret = append(ret, arr.EpisodeFile{ID: len(ret) + 1, SeriesID: seriesID, SeasonNumber: season})
+		}
+		return ret
+	}
+
+	library := func() *mockArrEpisodeFiles {
+		return &mockArrEpisodeFiles{
+			series: []arr.Series{
+				{ID: 1, Title: "That Show", Year: 2020, Seasons: []arr.Season{
+					{SeasonNumber: 1, Statistics: arr.SeasonStatistics{TotalEpisodeCount: 10}},
+					{SeasonNumber: 2, Statistics: arr.SeasonStatistics{TotalEpisodeCount: 8}},
+				}},
+				{ID: 2, Title: "Other Show (2015)", Year: 2015, AlternateTitles: []arr.AlternateTitle{{Title: "Another Show"}}, Seasons: []arr.Season{
+					{SeasonNumber: 1, Statistics: arr.SeasonStatistics{TotalEpisodeCount: 4}},
+				}},
+				{ID: 3, Title: "Other Show (2022)", Year: 2022, Seasons: []arr.Season{
+					{SeasonNumber: 1, Statistics: arr.SeasonStatistics{TotalEpisodeCount: 6}},
+				}},
+			},
+			files: map[int][]arr.EpisodeFile{
+				1: append(files(1, 1, 9), files(1, 2, 2)...),
+				2: files(2, 1, 4),
+				3: files(3, 1, 1),
+			},
+		}
+	}
+
+	tests := []struct {
+		name      string
+		release   domain.Release
+		threshold int
+		err       error
+		want      []string
+	}{
+		{
+			name:      "mostly_on_disk",
+			release:   domain.Release{TorrentName: "That.Show.S01.1080p.WEB-DL-GROUP", Title: "That Show", Season: 1},
+			threshold: 80,
+			want:      []string{"season pack: 9 of 10 episodes of That Show season 1 already on disk in sonarr (90%)"},
+		},
+		{
+			name:      "below_threshold",
+			release:   domain.Release{TorrentName: "That.Show.S02.1080p.WEB-DL-GROUP", Title: "That Show", Season: 2},
+			threshold: 80,
+		},
+		{
+			name:      "episode",
+			release:   domain.Release{TorrentName: "That.Show.S01E01.1080p.WEB-DL-GROUP", Title: "That Show", Season: 1, Episode: 1},
+			threshold: 80,
+		},
+		{
+			name:      "disabled",
+			release:   domain.Release{TorrentName: "That.Show.S01.1080p.WEB-DL-GROUP", Title: "That Show", Season: 1},
+			threshold: 0,
+		},
+		{
+			name:      "alternate_title",
+			release:   domain.Release{TorrentName: "Another.Show.S01.1080p.WEB-DL-GROUP", Title: "Another Show", Season: 1},
+			threshold: 100,
+			want:      []string{"season pack: 4 of 4 episodes of Other Show (2015) season 1 already on disk in sonarr (100%)"},
+		},
+		{
+			name:      "year",
+			release:   domain.Release{TorrentName: "Other.Show.2022.S01.1080p.WEB-DL-GROUP", Title: "Other Show", Year: 2022, Season: 1},
+			threshold: 50,
+		},
+		{
+			name:      "unknown_series",
+			release:   domain.Release{TorrentName: "Unknown.Show.S01.1080p.WEB-DL-GROUP", Title: "Unknown Show", Season: 1},
+			threshold: 50,
+		},
+		{
+			name:      "unknown_season",
+			release:   domain.Release{TorrentName: "That.Show.S05.1080p.WEB-DL-GROUP", Title: "That Show", Season: 5},
+			threshold: 50,
+		},
+		{
+			name:      "lookup_error",
+			release:   domain.Release{TorrentName: "That.Show.S01.1080p.WEB-DL-GROUP", Title: "That Show", Season: 1},
+			threshold: 50,
+			err:       errors.New("connection refused"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &service{log: zerolog.Nop(), arrSeries: newTTLCache[int, []arr.Series](arrSeriesTTL)}
+
+			client := library()
+			client.err = tt.err
+
+			got := s.arrSeasonPackRejections(1, client, "sonarr", tt.release, tt.threshold)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_normalizeSeriesTitle(t *testing.T) {
+	tests := []struct {
+		title string
+		want  string
+	}{
+		{title: "That Show", want: "that show"},
+		{title: "Other Show (2015)", want: "other show"},
+		{title: "Grey's Anatomy", want: "greys anatomy"},
+		{title: "Greys Anatomy", want: "greys anatomy"},
+		{title: "Law & Order: SVU", want: "law and order svu"},
+		{title: "Law and Order SVU", want: "law and order svu"},
+		{title: "Marvel’s Agents of S.H.I.E.L.D.", want: "marvels agents of s h i e l d"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.title, func(t *testing.T) {
+			assert.Equal(t, tt.want, normalizeSeriesTitle(tt.title))
+		})
+	}
+}
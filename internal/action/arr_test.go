@@ -0,0 +1,51 @@
+package action
+
+import (
+	"testing"
+
+	"github.com/autobrr/autobrr/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_arrIndexerFlags(t *testing.T) {
+	tests := []struct {
+		name    string
+		release domain.Release
+		values  arrIndexerFlagValues
+		want    int
+	}{
+		{name: "none", release: domain.Release{}, values: radarrIndexerFlags, want: 0},
+		{name: "freeleech", release: domain.Release{Freeleech: true}, values: radarrIndexerFlags, want: 1},
+		{name: "freeleech_100", release: domain.Release{Freeleech: true, FreeleechPercent: 100}, values: sonarrIndexerFlags, want: 1},
+		{name: "halfleech", release: domain.Release{Freeleech: true, FreeleechPercent: 50}, values: radarrIndexerFlags, want: 2},
+		{name: "freeleech_75", release: domain.Release{Freeleech: true, FreeleechPercent: 75}, values: radarrIndexerFlags, want: 256},
+		{name: "freeleech_75_sonarr", release: domain.Release{Freeleech: true, FreeleechPercent: 75}, values: sonarrIndexerFlags, want: 32},
+		{name: "other_percent", release: domain.Release{Freeleech: true, FreeleechPercent: 30}, values: radarrIndexerFlags, want: 0},
+		{name: "internal_origin", release: domain.Release{Origin: "INTERNAL"}, values: radarrIndexerFlags, want: 32},
+		{name: "internal_other", release: domain.Release{Other: []string{"INTERNAL"}}, values: sonarrIndexerFlags, want: 8},
+		{name: "scene_freeleech", release: domain.Release{Origin: "SCENE", Freeleech: true}, values: sonarrIndexerFlags, want: 17},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, arrIndexerFlags(tt.release, tt.values))
+		})
+	}
+}
+
+func Test_arrDownloadClient(t *testing.T) {
+	release := domain.Release{Indexer: "mock"}
+
+	id, name, err := arrDownloadClient(domain.Action{ExternalDownloadClientID: 3}, release)
+	assert.NoError(t, err)
+	assert.Equal(t, int32(3), id)
+	assert.Equal(t, "", name)
+
+	id, name, err = arrDownloadClient(domain.Action{ExternalDownloadClient: "qbit-{{ .Indexer }}"}, release)
+	assert.NoError(t, err)
+	assert.Equal(t, int32(0), id)
+	assert.Equal(t, "qbit-mock", name)
+
+	_, _, err = arrDownloadClient(domain.Action{ExternalDownloadClient: "qbit-{{ .Indexer "}, release)
+	assert.Error(t, err)
+}
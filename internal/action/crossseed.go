@@ -0,0 +1,253 @@
+package action
+
+import (
+	"context"
+	"encoding/base64"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/anacrolix/torrent/metainfo"
+	delugeClient "github.com/gdm85/go-libdeluge"
+
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/pkg/errors"
+	"github.com/autobrr/autobrr/pkg/qbittorrent"
+)
+
+// crossSeedTorrent is a torrent already in the download client that a release can be matched against
+type crossSeedTorrent struct {
+	Hash     string
+	Name     string
+	Size     int64
+	SavePath string
+	Category string
+	Complete bool
+}
+
+// crossSeedMatch finds a completed torrent with the same content name and size.
+// If the exact torrent is already in the client, exists is true.
+func crossSeedMatch(hashes domain.InfoHashes, name string, size int64, torrents []crossSeedTorrent) (match *crossSeedTorrent, exists bool) {
+	for i := range torrents {
+		t := torrents[i]
+
+		if hashes.Matches(t.Hash) {
+			return nil, true
+		}
+
+		if match != nil || !t.Complete || t.Size != size {
+			continue
+		}
+
+		if strings.EqualFold(t.Name, name) {
+			match = &torrents[i]
+		}
+	}
+
+	return match, false
+}
+
+func (s *service) crossSeed(action domain.Action, release domain.Release) ([]string, error) {
+	s.log.Debug().Msgf("action Cross-seed: %v", action.Name)
+
+	// get client for action
+	client, err := s.clientSvc.FindByID(context.TODO(), action.ClientID)
+	if err != nil {
+		return nil, errors.Wrap(err, "error finding client: %v", action.ClientID)
+	}
+
+	if client == nil {
+		return nil, errors.New("could not find client by id: %v", action.ClientID)
+	}
+
+	if release.TorrentTmpFile == "" {
+		if err := release.DownloadTorrentFile(); err != nil {
+			return nil, errors.Wrap(err, "error downloading torrent file for release: %v", release.TorrentName)
+		}
+	}
+
+	meta, err := metainfo.LoadFromFile(release.TorrentTmpFile)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not load torrent file: %v", release.TorrentTmpFile)
+	}
+
+	info, err := meta.UnmarshalInfo()
+	if err != nil {
+		return nil, errors.Wrap(err, "could not unmarshal info from torrent: %v", release.TorrentTmpFile)
+	}
+
+	switch client.Type {
+	case domain.DownloadClientTypeQbittorrent:
+		return s.crossSeedQbittorrent(client, action, release, info.Name, info.TotalLength())
+
+	case domain.DownloadClientTypeDelugeV2:
+		return s.crossSeedDeluge(client, action, release, info.Name, info.TotalLength())
+
+	default:
+		return nil, errors.New("cross-seed: unsupported client type: %v", client.Type)
+	}
+}
+
+func (s *service) crossSeedQbittorrent(client *domain.DownloadClient, action domain.Action, release domain.Release, name string, size int64) ([]string, error) {
+	qbt, err := s.qbittorrentClient(client)
+	if err != nil {
+		return nil, err
+	}
+
+	completed, err := qbt.GetTorrentsFilter(qbittorrent.TorrentFilterCompleted)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not get completed torrents from client: %v", client.Name)
+	}
+
+	torrents := make([]crossSeedTorrent, 0, len(completed))
+	for _, t := range completed {
+		torrents = append(torrents, crossSeedTorrent{
+			Hash:     t.Hash,
+			Name:     t.Name,
+			Size:     int64(t.TotalSize),
+			SavePath: t.SavePath,
+			Category: t.Category,
+			Complete: t.Progress >= 1,
+		})
+	}
+
+	match, exists := crossSeedMatch(release.InfoHashes(), name, size, torrents)
+	if exists {
+		return []string{"torrent already in client"}, nil
+	}
+	if match == nil {
+		return []string{"no matching completed torrent in client"}, nil
+	}
+
+	s.log.Debug().Msgf("action Cross-seed: %v matched %v (%v) in %v", release.TorrentName, match.Name, match.Hash, match.SavePath)
+
+	m := domain.NewMacro(release)
+
+	// inject next to the existing data and skip checking since the content is already complete
+	layout := qbittorrent.ContentLayoutOriginal
+	opts := &qbittorrent.TorrentAddOptions{
+		SavePath:      &match.SavePath,
+		AutoTMM:       BoolPointer(false),
+		SkipHashCheck: BoolPointer(true),
+		ContentLayout: &layout,
+		Category:      &match.Category,
+	}
+
+	if action.Paused {
+		opts.Paused = BoolPointer(true)
+	}
+	if action.Category != "" {
+		categoryArgs, err := m.Parse(action.Category)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not parse category macro: %v", action.Category)
+		}
+
+		opts.Category = &categoryArgs
+	}
+	if action.Tags != "" {
+		tagsArgs, err := m.Parse(action.Tags)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not parse tags macro: %v", action.Tags)
+		}
+
+		opts.Tags = &tagsArgs
+	}
+
+	options := opts.Prepare()
+
+	s.log.Trace().Msgf("action Cross-seed options: %+v", options)
+
+	if err := qbt.AddTorrentFromFile(release.TorrentTmpFile, options); err != nil {
+		return nil, errors.Wrap(err, "could not add torrent %v to client: %v", release.TorrentTmpFile, client.Name)
+	}
+
+	s.log.Info().Msgf("cross-seed torrent with hash %v successfully injected to client: '%v'", release.TorrentHash, client.Name)
+
+	return nil, nil
+}
+
+func (s *service) crossSeedDeluge(client *domain.DownloadClient, action domain.Action, release domain.Release, name string, size int64) ([]string, error) {
+	settings := delugeClient.Settings{
+		Hostname:             client.Host,
+		Port:                 uint(client.Port),
+		Login:                client.Username,
+		Password:             client.Password,
+		DebugServerResponses: true,
+		ReadWriteTimeout:     time.Second * 20,
+	}
+
+	deluge := delugeClient.NewV2(settings)
+
+	if err := deluge.Connect(); err != nil {
+		return nil, errors.Wrap(err, "could not connect to client %v at %v", client.Name, client.Host)
+	}
+
+	defer deluge.Close()
+
+	status, err := deluge.TorrentsStatus(delugeClient.StateUnspecified, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not get torrents from client: %v", client.Name)
+	}
+
+	torrents := make([]crossSeedTorrent, 0, len(status))
+	for hash, t := range status {
+		torrents = append(torrents, crossSeedTorrent{
+			Hash:     hash,
+			Name:     t.Name,
+			Size:     t.TotalSize,
+			SavePath: t.DownloadLocation,
+			Complete: t.IsFinished,
+		})
+	}
+
+	match, exists := crossSeedMatch(release.InfoHashes(), name, size, torrents)
+	if exists {
+		return []string{"torrent already in client"}, nil
+	}
+	if match == nil {
+		return []string{"no matching completed torrent in client"}, nil
+	}
+
+	s.log.Debug().Msgf("action Cross-seed: %v matched %v (%v) in %v", release.TorrentName, match.Name, match.Hash, match.SavePath)
+
+	t, err := os.ReadFile(release.TorrentTmpFile)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not read torrent file: %v", release.TorrentTmpFile)
+	}
+
+	// deluge has no skip checking option, the existing data is verified when added
+	options := delugeClient.Options{
+		DownloadLocation: &match.SavePath,
+	}
+
+	if action.Paused {
+		options.AddPaused = &action.Paused
+	}
+
+	torrentHash, err := deluge.AddTorrentFile(release.TorrentTmpFile, base64.StdEncoding.EncodeToString(t), &options)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not add torrent %v to client: %v", release.TorrentTmpFile, client.Name)
+	}
+
+	if action.Label != "" {
+		labelPluginActive, err := deluge.LabelPlugin()
+		if err != nil {
+			return nil, errors.Wrap(err, "could not load label plugin for client: %v", client.Name)
+		}
+
+		labelArgs, err := domain.NewMacro(release).Parse(action.Label)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not parse macro label: %v", action.Label)
+		}
+
+		if labelPluginActive != nil {
+			if err := labelPluginActive.SetTorrentLabel(torrentHash, labelArgs); err != nil {
+				return nil, errors.Wrap(err, "could not set label: %v on client: %v", action.Label, client.Name)
+			}
+		}
+	}
+
+	s.log.Info().Msgf("cross-seed torrent with hash %v successfully injected to client: '%v'", torrentHash, client.Name)
+
+	return nil, nil
+}
@@ -0,0 +1,73 @@
+package action
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/autobrr/autobrr/internal/domain"
+)
+
+func Test_crossSeedMatch(t *testing.T) {
+	const (
+		hashA = "aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa"
+		hashB = "bbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbb"
+		hashC = "cccccccccccccccccccccccccccccccccccccccc"
+	)
+
+	torrents := []crossSeedTorrent{
+		{Hash: hashA, Name: "That.Show.S01.1080p.WEB-DL-GROUP", Size: 1000, SavePath: "/downloads/tv", Complete: true},
+		{Hash: hashB, Name: "That.Movie.2022.1080p.BluRay-GROUP", Size: 2000, SavePath: "/downloads/movies", Complete: false},
+	}
+
+	type args struct {
+		hashes domain.InfoHashes
+		name   string
+		size   int64
+	}
+	tests := []struct {
+		name       string
+		args       args
+		wantHash   string
+		wantExists bool
+	}{
+		{
+			name:     "match_name_and_size",
+			args:     args{hashes: domain.InfoHashes{V1: hashC}, name: "That.Show.S01.1080p.WEB-DL-GROUP", size: 1000},
+			wantHash: hashA,
+		},
+		{
+			name:     "match_case_insensitive",
+			args:     args{hashes: domain.InfoHashes{V1: hashC}, name: "that.show.s01.1080p.web-dl-group", size: 1000},
+			wantHash: hashA,
+		},
+		{
+			name: "size_mismatch",
+			args: args{hashes: domain.InfoHashes{V1: hashC}, name: "That.Show.S01.1080p.WEB-DL-GROUP", size: 1001},
+		},
+		{
+			name: "not_complete",
+			args: args{hashes: domain.InfoHashes{V1: hashC}, name: "That.Movie.2022.1080p.BluRay-GROUP", size: 2000},
+		},
+		{
+			name:       "already_in_client",
+			args:       args{hashes: domain.InfoHashes{V1: hashA}, name: "That.Show.S01.1080p.WEB-DL-GROUP", size: 1000},
+			wantExists: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			match, exists := crossSeedMatch(tt.args.hashes, tt.args.name, tt.args.size, torrents)
+			assert.Equal(t, tt.wantExists, exists)
+
+			if tt.wantHash == "" {
+				assert.Nil(t, match)
+				return
+			}
+
+			if assert.NotNil(t, match) {
+				assert.Equal(t, tt.wantHash, match.Hash)
+			}
+		})
+	}
+}
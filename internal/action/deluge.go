@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/base64"
 	"os"
+	"strings"
 	"time"
 
 	"github.com/autobrr/autobrr/internal/domain"
@@ -86,8 +87,29 @@ func (s *service) delugeCheckRulesCanDownload(deluge delugeClient.DelugeClient,
 	return nil, nil
 }
 
+// delugeRPCClient is implemented by both the v1 and v2 clients, v2 embeds v1 for the label plugin
+type delugeRPCClient interface {
+	delugeClient.DelugeClient
+	LabelPlugin() (*delugeClient.LabelPlugin, error)
+}
+
+// delugeLabeler is the part of the label plugin used to set labels
+type delugeLabeler interface {
+	GetLabels() ([]string, error)
+	AddLabel(label string) error
+	SetTorrentLabel(hash, label string) error
+}
+
 func (s *service) delugeV1(client *domain.DownloadClient, action domain.Action, release domain.Release) ([]string, error) {
-	settings := delugeClient.Settings{
+	return s.delugeAddTorrent(delugeClient.NewV1(delugeSettings(client)), client, action, release)
+}
+
+func (s *service) delugeV2(client *domain.DownloadClient, action domain.Action, release domain.Release) ([]string, error) {
+	return s.delugeAddTorrent(delugeClient.NewV2(delugeSettings(client)), client, action, release)
+}
+
+func delugeSettings(client *domain.DownloadClient) delugeClient.Settings {
+	return delugeClient.Settings{
 		Hostname:             client.Host,
 		Port:                 uint(client.Port),
 		Login:                client.Username,
@@ -95,9 +117,9 @@ func (s *service) delugeV1(client *domain.DownloadClient, action domain.Action,
 		DebugServerResponses: true,
 		ReadWriteTimeout:     time.Second * 20,
 	}
+}
 
-	deluge := delugeClient.NewV1(settings)
-
+func (s *service) delugeAddTorrent(deluge delugeRPCClient, client *domain.DownloadClient, action domain.Action, release domain.Release) ([]string, error) {
 	// perform connection to Deluge server
 	err := deluge.Connect()
 	if err != nil {
@@ -106,7 +128,6 @@ func (s *service) delugeV1(client *domain.DownloadClient, action domain.Action,
 
 	defer deluge.Close()
 
-	// perform connection to Deluge server
 	rejections, err := s.delugeCheckRulesCanDownload(deluge, client, action)
 	if err != nil {
 		s.log.Error().Err(err).Msgf("error checking client rules: %v", action.Name)
@@ -123,6 +144,18 @@ func (s *service) delugeV1(client *domain.DownloadClient, action domain.Action,
 		}
 	}
 
+	rejections, err = s.checkTorrent(client, &release, func(hash string) (bool, error) {
+		torrents, err := deluge.TorrentsStatus(delugeClient.StateUnspecified, []string{hash})
+		return len(torrents) > 0, err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if rejections != nil {
+		return rejections, nil
+	}
+
 	t, err := os.ReadFile(release.TorrentTmpFile)
 	if err != nil {
 		return nil, errors.Wrap(err, "could not read torrent file: %v", release.TorrentTmpFile)
@@ -150,23 +183,21 @@ func (s *service) delugeV1(client *domain.DownloadClient, action domain.Action,
 	}
 
 	if action.Label != "" {
-		labelPluginActive, err := deluge.LabelPlugin()
-		if err != nil {
-			return nil, errors.Wrap(err, "could not load label plugin for client: %v", client.Name)
-		}
-
 		// parse and replace values in argument string before continuing
 		labelArgs, err := m.Parse(action.Label)
 		if err != nil {
 			return nil, errors.Wrap(err, "could not parse macro label: %v", action.Label)
 		}
 
-		if labelPluginActive != nil {
-			// TODO first check if label exists, if not, add it, otherwise set
-			err = labelPluginActive.SetTorrentLabel(torrentHash, labelArgs)
-			if err != nil {
-				return nil, errors.Wrap(err, "could not set label: %v on client: %v", action.Label, client.Name)
-			}
+		labelPlugin, err := deluge.LabelPlugin()
+		if err != nil {
+			return nil, errors.Wrap(err, "could not load label plugin for client: %v", client.Name)
+		}
+
+		if labelPlugin == nil {
+			s.log.Warn().Msgf("action Deluge: %v label plugin not enabled on client: %v, skipping label %v", action.Name, client.Name, labelArgs)
+		} else if err := delugeSetLabel(labelPlugin, torrentHash, labelArgs); err != nil {
+			return nil, errors.Wrap(err, "could not set label: %v on client: %v", labelArgs, client.Name)
 		}
 	}
 
@@ -175,94 +206,34 @@ func (s *service) delugeV1(client *domain.DownloadClient, action domain.Action,
 	return nil, nil
 }
 
-func (s *service) delugeV2(client *domain.DownloadClient, action domain.Action, release domain.Release) ([]string, error) {
-	settings := delugeClient.Settings{
-		Hostname:             client.Host,
-		Port:                 uint(client.Port),
-		Login:                client.Username,
-		Password:             client.Password,
-		DebugServerResponses: true,
-		ReadWriteTimeout:     time.Second * 20,
-	}
-
-	deluge := delugeClient.NewV2(settings)
-
-	// perform connection to Deluge server
-	err := deluge.Connect()
-	if err != nil {
-		return nil, errors.Wrap(err, "could not connect to client %v at %v", client.Name, client.Host)
+// delugeSetLabel sets the label on the torrent and creates it first if it does not exist.
+// The label plugin only accepts lowercase labels so the label is lowercased.
+func delugeSetLabel(labels delugeLabeler, hash string, label string) error {
+	label = strings.ToLower(strings.TrimSpace(label))
+	if label == "" {
+		return nil
 	}
 
-	defer deluge.Close()
-
-	// perform connection to Deluge server
-	rejections, err := s.delugeCheckRulesCanDownload(deluge, client, action)
+	existing, err := labels.GetLabels()
 	if err != nil {
-		s.log.Error().Err(err).Msgf("error checking client rules: %v", action.Name)
-		return nil, err
-	}
-	if rejections != nil {
-		return rejections, nil
+		return errors.Wrap(err, "could not get labels")
 	}
 
-	if release.TorrentTmpFile == "" {
-		if err = release.DownloadTorrentFile(); err != nil {
-			s.log.Error().Err(err).Msgf("could not download torrent file for release: %v", release.TorrentName)
-			return nil, err
+	found := false
+	for _, l := range existing {
+		if l == label {
+			found = true
+			break
 		}
 	}
 
-	t, err := os.ReadFile(release.TorrentTmpFile)
-	if err != nil {
-		return nil, errors.Wrap(err, "could not read torrent file: %v", release.TorrentTmpFile)
-	}
-
-	// encode file to base64 before sending to deluge
-	encodedFile := base64.StdEncoding.EncodeToString(t)
-	if encodedFile == "" {
-		return nil, errors.Wrap(err, "could not encode torrent file: %v", release.TorrentTmpFile)
-	}
-
-	// macros handle args and replace vars
-	m := domain.NewMacro(release)
-
-	// set options
-	options, err := s.prepareDelugeOptions(action, m)
-	if err != nil {
-		return nil, errors.Wrap(err, "could not prepare options")
-	}
-
-	s.log.Trace().Msgf("action Deluge options: %+v", options)
-
-	torrentHash, err := deluge.AddTorrentFile(release.TorrentTmpFile, encodedFile, &options)
-	if err != nil {
-		return nil, errors.Wrap(err, "could not add torrent %v to client: %v", release.TorrentTmpFile, client.Name)
-	}
-
-	if action.Label != "" {
-		labelPluginActive, err := deluge.LabelPlugin()
-		if err != nil {
-			return nil, errors.Wrap(err, "could not load label plugin for client: %v", client.Name)
-		}
-
-		// parse and replace values in argument string before continuing
-		labelArgs, err := m.Parse(action.Label)
-		if err != nil {
-			return nil, errors.Wrap(err, "could not parse macro label: %v", action.Label)
-		}
-
-		if labelPluginActive != nil {
-			// TODO first check if label exists, if not, add it, otherwise set
-			err = labelPluginActive.SetTorrentLabel(torrentHash, labelArgs)
-			if err != nil {
-				return nil, errors.Wrap(err, "could not set label: %v on client: %v", action.Label, client.Name)
-			}
+	if !found {
+		if err := labels.AddLabel(label); err != nil {
+			return errors.Wrap(err, "could not add label: %v", label)
 		}
 	}
 
-	s.log.Info().Msgf("torrent with hash %v successfully added to client: '%v'", torrentHash, client.Name)
-
-	return nil, nil
+	return labels.SetTorrentLabel(hash, label)
 }
 
 func (s *service) prepareDelugeOptions(action domain.Action, m domain.Macro) (delugeClient.Options, error) {
@@ -270,9 +241,10 @@ func (s *service) prepareDelugeOptions(action domain.Action, m domain.Macro) (de
 	// set options
 	options := delugeClient.Options{}
 
-	if action.Paused {
-		options.AddPaused = &action.Paused
-	}
+	// always send paused so the daemon default does not decide, v1 and v2 behave the same way
+	paused := action.Paused
+	options.AddPaused = &paused
+
 	if action.SavePath != "" {
 		// parse and replace values in argument string before continuing
 		savePathArgs, err := m.Parse(action.SavePath)
@@ -282,6 +254,17 @@ func (s *service) prepareDelugeOptions(action domain.Action, m domain.Macro) (de
 
 		options.DownloadLocation = &savePathArgs
 	}
+	if action.MoveCompletedPath != "" {
+		// parse and replace values in argument string before continuing
+		moveCompletedPathArgs, err := m.Parse(action.MoveCompletedPath)
+		if err != nil {
+			return options, errors.Wrap(err, "could not parse move completed path macro: %v", action.MoveCompletedPath)
+		}
+
+		moveCompleted := true
+		options.MoveCompleted = &moveCompleted
+		options.MoveCompletedPath = &moveCompletedPathArgs
+	}
 	if action.LimitDownloadSpeed > 0 {
 		maxDL := int(action.LimitDownloadSpeed)
 		options.MaxDownloadSpeed = &maxDL
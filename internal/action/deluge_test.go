@@ -0,0 +1,122 @@
+package action
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/autobrr/autobrr/internal/domain"
+
+	delugeClient "github.com/gdm85/go-libdeluge"
+)
+
+func Test_service_prepareDelugeOptions(t *testing.T) {
+	release := domain.Release{
+		Indexer:     "mock",
+		TorrentName: "That.Show.S01E01.1080p.WEB-DL-GROUP",
+	}
+
+	boolPtr := func(b bool) *bool { return &b }
+	strPtr := func(s string) *string { return &s }
+	intPtr := func(i int) *int { return &i }
+
+	tests := []struct {
+		name   string
+		action domain.Action
+		want   delugeClient.Options
+	}{
+		{
+			name:   "not_paused_is_sent",
+			action: domain.Action{},
+			want:   delugeClient.Options{AddPaused: boolPtr(false)},
+		},
+		{
+			name:   "paused_with_limits",
+			action: domain.Action{Paused: true, LimitDownloadSpeed: 1000, LimitUploadSpeed: 500},
+			want: delugeClient.Options{
+				AddPaused:        boolPtr(true),
+				MaxDownloadSpeed: intPtr(1000),
+				MaxUploadSpeed:   intPtr(500),
+			},
+		},
+		{
+			name:   "move_completed_with_macros",
+			action: domain.Action{SavePath: "/downloads/{{ .Indexer }}", MoveCompletedPath: "/media/{{ .Indexer }}/done"},
+			want: delugeClient.Options{
+				AddPaused:         boolPtr(false),
+				DownloadLocation:  strPtr("/downloads/mock"),
+				MoveCompleted:     boolPtr(true),
+				MoveCompletedPath: strPtr("/media/mock/done"),
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &service{}
+
+			got, err := s.prepareDelugeOptions(tt.action, domain.NewMacro(release))
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+type mockDelugeLabeler struct {
+	labels []string
+	added  []string
+	set    map[string]string
+}
+
+func (m *mockDelugeLabeler) GetLabels() ([]string, error) {
+	return m.labels, nil
+}
+
+func (m *mockDelugeLabeler) AddLabel(label string) error {
+	m.added = append(m.added, label)
+	m.labels = append(m.labels, label)
+	return nil
+}
+
+func (m *mockDelugeLabeler) SetTorrentLabel(hash, label string) error {
+	m.set[hash] = label
+	return nil
+}
+
+func Test_delugeSetLabel(t *testing.T) {
+	tests := []struct {
+		name      string
+		existing  []string
+		label     string
+		wantAdded []string
+		wantSet   map[string]string
+	}{
+		{
+			name:     "existing_label",
+			existing: []string{"tv"},
+			label:    "tv",
+			wantSet:  map[string]string{"abc": "tv"},
+		},
+		{
+			name:      "missing_label_is_created",
+			existing:  []string{"tv"},
+			label:     "Movies",
+			wantAdded: []string{"movies"},
+			wantSet:   map[string]string{"abc": "movies"},
+		},
+		{
+			name:    "empty_label",
+			label:   " ",
+			wantSet: map[string]string{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			labeler := &mockDelugeLabeler{labels: tt.existing, set: map[string]string{}}
+
+			err := delugeSetLabel(labeler, "abc", tt.label)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantAdded, labeler.added)
+			assert.Equal(t, tt.wantSet, labeler.set)
+		})
+	}
+}
@@ -3,7 +3,9 @@ package action
 import (
 	"os"
 	"os/exec"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/autobrr/autobrr/internal/domain"
@@ -12,12 +14,12 @@ import (
 	"github.com/mattn/go-shellwords"
 )
 
-func (s *service) execCmd(action domain.Action, release domain.Release) error {
+func (s *service) execCmd(action domain.Action, release domain.Release) (string, error) {
 	s.log.Debug().Msgf("action exec: %v release: %v", action.Name, release.TorrentName)
 
 	if release.TorrentTmpFile == "" && strings.Contains(action.ExecArgs, "TorrentPathName") {
 		if err := release.DownloadTorrentFile(); err != nil {
-			return errors.Wrap(err, "error downloading torrent file for release: %v", release.TorrentName)
+			return "", errors.Wrap(err, "error downloading torrent file for release: %v", release.TorrentName)
 		}
 	}
 
@@ -25,7 +27,7 @@ func (s *service) execCmd(action domain.Action, release domain.Release) error {
 	if len(release.TorrentDataRawBytes) == 0 && release.TorrentTmpFile != "" {
 		t, err := os.ReadFile(release.TorrentTmpFile)
 		if err != nil {
-			return errors.Wrap(err, "could not read torrent file: %v", release.TorrentTmpFile)
+			return "", errors.Wrap(err, "could not read torrent file: %v", release.TorrentTmpFile)
 		}
 
 		release.TorrentDataRawBytes = t
@@ -34,35 +36,197 @@ func (s *service) execCmd(action domain.Action, release domain.Release) error {
 	// check if program exists
 	cmd, err := exec.LookPath(action.ExecCmd)
 	if err != nil {
-		return errors.Wrap(err, "exec failed, could not find program: %v", action.ExecCmd)
+		return "", errors.Wrap(err, "exec failed, could not find program: %v", action.ExecCmd)
 	}
 
 	args, err := s.parseExecArgs(release, action.ExecArgs)
 	if err != nil {
-		return errors.Wrap(err, "could not parse exec args: %v", action.ExecArgs)
+		return "", errors.Wrap(err, "could not parse exec args: %v", action.ExecArgs)
 	}
 
-	// we need to split on space into a string slice, so we can spread the args into exec
+	timeout := domain.ActionExecDefaultTimeout
+	if action.ExecTimeout > 0 {
+		timeout = time.Duration(action.ExecTimeout) * time.Second
+	}
 
 	start := time.Now()
 
-	// setup command and args
+	// setup command and args, release fields are passed as environment variables as well
 	command := exec.Command(cmd, args...)
+	command.Env = append(os.Environ(), execEnv(release)...)
+
+	stdout := &tailBuffer{limit: execOutputLimit}
+	stderr := &tailBuffer{limit: execOutputLimit}
+	command.Stdout = stdout
+	command.Stderr = stderr
+
+	setProcessGroup(command)
+
+	if err := command.Start(); err != nil {
+		return "", errors.Wrap(err, "error starting command: %v args: %v", cmd, args)
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		done <- command.Wait()
+	}()
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	select {
+	case err = <-done:
+	case <-timer.C:
+		if killErr := killProcessGroup(command); killErr != nil {
+			s.log.Error().Err(killErr).Msgf("could not kill command: %v", cmd)
+		}
+
+		// wait for the output to be copied, but not forever if a process outside the group keeps the pipes open
+		select {
+		case <-done:
+		case <-time.After(execKillWait):
+		}
+
+		return execLog(stdout, stderr), errors.New("command timed out after %v: %v args: %v", timeout, cmd, args)
+	}
+
+	output := execLog(stdout, stderr)
+
+	s.log.Trace().Msgf("executed command: '%v'", output)
 
-	// execute command
-	output, err := command.CombinedOutput()
 	if err != nil {
 		// everything other than exit 0 is considered an error
-		return errors.Wrap(err, "error executing command: %v args: %v", cmd, args)
-	}
+		if exitErr, ok := err.(*exec.ExitError); ok {
+			return output, errors.New("command exited with code %d: %v args: %v%v", exitErr.ExitCode(), cmd, args, lastLine(stderr.String()))
+		}
 
-	s.log.Trace().Msgf("executed command: '%v'", string(output))
+		return output, errors.Wrap(err, "error executing command: %v args: %v", cmd, args)
+	}
 
 	duration := time.Since(start)
 
 	s.log.Info().Msgf("executed command: '%v', args: '%v' %v,%v, total time %v", cmd, args, release.TorrentName, release.Indexer, duration)
 
-	return nil
+	return output, nil
+}
+
+// execEnv returns the release fields as AUTOBRR_ prefixed environment variables
+func execEnv(release domain.Release) []string {
+	var filterName string
+	if release.Filter != nil {
+		filterName = release.Filter.Name
+	}
+
+	vars := []struct {
+		key   string
+		value string
+	}{
+		{"TORRENT_NAME", release.TorrentName},
+		{"TORRENT_URL", release.TorrentURL},
+		{"TORRENT_PATH", release.TorrentTmpFile},
+		{"TORRENT_HASH", release.TorrentHash},
+		{"TORRENT_ID", release.TorrentID},
+		{"INDEXER", release.Indexer},
+		{"FILTER", filterName},
+		{"TITLE", release.Title},
+		{"CATEGORY", release.Category},
+		{"SIZE", strconv.FormatUint(release.Size, 10)},
+		{"YEAR", strconv.Itoa(release.Year)},
+		{"SEASON", strconv.Itoa(release.Season)},
+		{"EPISODE", strconv.Itoa(release.Episode)},
+		{"RESOLUTION", release.Resolution},
+		{"SOURCE", release.Source},
+		{"GROUP", release.Group},
+		{"UPLOADER", release.Uploader},
+		{"FREELEECH", strconv.FormatBool(release.Freeleech)},
+		{"FREELEECH_PERCENT", strconv.Itoa(release.FreeleechPercent)},
+	}
+
+	env := make([]string, 0, len(vars))
+	for _, v := range vars {
+		env = append(env, "AUTOBRR_"+v.key+"="+v.value)
+	}
+
+	return env
+}
+
+// execOutputLimit is the number of bytes kept from the end of stdout and stderr each
+const execOutputLimit = 4096
+
+// execKillWait is how long a timed out command gets to exit after it is killed
+const execKillWait = 5 * time.Second
+
+// tailBuffer keeps the last limit bytes written to it. It is written by the os/exec copy goroutines
+// and can be read while they still run, when a command times out.
+type tailBuffer struct {
+	mu        sync.Mutex
+	limit     int
+	buf       []byte
+	truncated bool
+}
+
+func (b *tailBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.buf = append(b.buf, p...)
+	if over := len(b.buf) - b.limit; over > 0 {
+		b.buf = b.buf[over:]
+		b.truncated = true
+	}
+
+	return len(p), nil
+}
+
+func (b *tailBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return string(b.buf)
+}
+
+// tail returns the kept output and whether earlier output was dropped
+func (b *tailBuffer) tail() (string, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return string(b.buf), b.truncated
+}
+
+// execLog formats the captured output for the release action log
+func execLog(stdout, stderr *tailBuffer) string {
+	var sb strings.Builder
+
+	for _, stream := range []struct {
+		name string
+		buf  *tailBuffer
+	}{{"stdout", stdout}, {"stderr", stderr}} {
+		out, truncated := stream.buf.tail()
+		if out == "" {
+			continue
+		}
+
+		if sb.Len() > 0 {
+			sb.WriteString("\n")
+		}
+
+		sb.WriteString(stream.name + ":\n")
+		if truncated {
+			sb.WriteString("...")
+		}
+		sb.WriteString(strings.TrimRight(out, "\n"))
+	}
+
+	return sb.String()
+}
+
+func lastLine(output string) string {
+	lines := strings.Split(strings.TrimSpace(output), "\n")
+	if last := strings.TrimSpace(lines[len(lines)-1]); last != "" {
+		return ": " + last
+	}
+
+	return ""
 }
 
 func (s *service) parseExecArgs(release domain.Release, execArgs string) ([]string, error) {
@@ -1,7 +1,9 @@
 package action
 
 import (
+	"runtime"
 	"testing"
+	"time"
 
 	"github.com/autobrr/autobrr/internal/domain"
 	"github.com/autobrr/autobrr/internal/logger"
@@ -112,3 +114,75 @@ func Test_service_execCmd(t *testing.T) {
 		})
 	}
 }
+
+func Test_service_execCmd_output(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("requires sh")
+	}
+
+	tests := []struct {
+		name    string
+		action  domain.Action
+		wantLog string
+		wantErr string
+	}{
+		{
+			name:    "env",
+			action:  domain.Action{Name: "env", ExecCmd: "sh", ExecArgs: `-c 'echo "$AUTOBRR_TORRENT_NAME $AUTOBRR_INDEXER $AUTOBRR_SIZE"'`},
+			wantLog: "stdout:\nThat Show S01E01 1080p WEB-DL-GROUP mock 1024",
+		},
+		{
+			name:    "exit_code",
+			action:  domain.Action{Name: "exit", ExecCmd: "sh", ExecArgs: `-c 'echo checking; echo "not wanted" >&2; exit 3'`},
+			wantLog: "stdout:\nchecking\nstderr:\nnot wanted",
+			wantErr: "command exited with code 3",
+		},
+		{
+			name:    "timeout",
+			action:  domain.Action{Name: "sleep", ExecCmd: "sh", ExecArgs: `-c 'echo waiting; sleep 10'`, ExecTimeout: 1},
+			wantLog: "stdout:\nwaiting",
+			wantErr: "command timed out after 1s",
+		},
+		{
+			name:    "timeout_children",
+			action:  domain.Action{Name: "children", ExecCmd: "sh", ExecArgs: `-c '(sleep 10; echo late) & echo waiting; wait'`, ExecTimeout: 1},
+			wantLog: "stdout:\nwaiting",
+			wantErr: "command timed out after 1s",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &service{log: logger.Mock().With().Logger()}
+
+			release := domain.Release{TorrentName: "That Show S01E01 1080p WEB-DL-GROUP", Indexer: "mock", Size: 1024}
+
+			start := time.Now()
+
+			got, err := s.execCmd(tt.action, release)
+			assert.Equal(t, tt.wantLog, got)
+
+			// children are killed with the command and don't keep it waiting on the output
+			assert.Less(t, time.Since(start), execKillWait)
+
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func Test_tailBuffer(t *testing.T) {
+	b := &tailBuffer{limit: 8}
+
+	b.Write([]byte("hello "))
+	assert.Equal(t, "hello ", b.String())
+	assert.False(t, b.truncated)
+
+	b.Write([]byte("world"))
+	assert.Equal(t, "lo world", b.String())
+	assert.True(t, b.truncated)
+
+	assert.Equal(t, "stdout:\n...lo world", execLog(b, &tailBuffer{limit: 8}))
+}
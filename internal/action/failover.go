@@ -0,0 +1,74 @@
+package action
+
+import (
+	"context"
+
+	"github.com/autobrr/autobrr/internal/domain"
+)
+
+// failover returns a copy of the action using its fallback client when the primary client is known to be down
+func (s *service) failover(action *domain.Action) *domain.Action {
+	if !hasFallbackClient(action) {
+		return action
+	}
+
+	primary, err := s.clientSvc.FindByID(context.TODO(), action.ClientID)
+	if err != nil || primary == nil || !primary.Health.Down() {
+		return action
+	}
+
+	if fallback, ok := s.fallbackAction(action); ok {
+		return fallback
+	}
+
+	return action
+}
+
+// failoverOnError checks the primary client after a failed action and returns a copy of
+// the action using the fallback client if the primary client turns out to be down
+func (s *service) failoverOnError(action *domain.Action) (*domain.Action, bool) {
+	if !hasFallbackClient(action) {
+		return nil, false
+	}
+
+	primary, err := s.clientSvc.FindByID(context.TODO(), action.ClientID)
+	if err != nil || primary == nil {
+		return nil, false
+	}
+
+	if health := s.clientSvc.CheckHealth(context.TODO(), *primary); !health.Down() {
+		return nil, false
+	}
+
+	return s.fallbackAction(action)
+}
+
+func (s *service) fallbackAction(action *domain.Action) (*domain.Action, bool) {
+	fallback, err := s.clientSvc.FindByID(context.TODO(), action.FallbackClientID)
+	if err != nil || fallback == nil {
+		s.log.Error().Err(err).Msgf("could not find fallback client by id: %v", action.FallbackClientID)
+		return nil, false
+	}
+
+	if string(fallback.Type) != string(action.Type) {
+		s.log.Warn().Msgf("fallback client %v of type %v can not be used for action %v of type %v", fallback.Name, fallback.Type, action.Name, action.Type)
+		return nil, false
+	}
+
+	if !fallback.Enabled || fallback.Health.Down() {
+		s.log.Warn().Msgf("fallback client %v for action %v is not available", fallback.Name, action.Name)
+		return nil, false
+	}
+
+	s.log.Warn().Msgf("client for action %v is down, using fallback client %v", action.Name, fallback.Name)
+
+	a := *action
+	a.ClientID = action.FallbackClientID
+	a.Client = *fallback
+
+	return &a, true
+}
+
+func hasFallbackClient(action *domain.Action) bool {
+	return action.FallbackClientID != 0 && action.FallbackClientID != action.ClientID
+}
@@ -0,0 +1,93 @@
+package action
+
+import (
+	"context"
+	"testing"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/internal/download_client"
+	"github.com/autobrr/autobrr/pkg/errors"
+)
+
+type mockClientService struct {
+	download_client.Service
+
+	clients map[int32]*domain.DownloadClient
+}
+
+func (m *mockClientService) FindByID(ctx context.Context, id int32) (*domain.DownloadClient, error) {
+	client, ok := m.clients[id]
+	if !ok {
+		return nil, errors.New("client not found: %v", id)
+	}
+
+	return client, nil
+}
+
+func (m *mockClientService) CheckHealth(ctx context.Context, client domain.DownloadClient) domain.DownloadClientHealth {
+	return m.clients[int32(client.ID)].Health
+}
+
+func Test_service_failover(t *testing.T) {
+	down := domain.DownloadClientHealth{Status: domain.DownloadClientHealthDown}
+	ok := domain.DownloadClientHealth{Status: domain.DownloadClientHealthOK}
+
+	tests := []struct {
+		name       string
+		primary    domain.DownloadClient
+		fallback   domain.DownloadClient
+		wantClient int32
+	}{
+		{
+			name:       "primary_up",
+			primary:    domain.DownloadClient{ID: 1, Type: domain.DownloadClientTypeQbittorrent, Enabled: true, Health: ok},
+			fallback:   domain.DownloadClient{ID: 2, Type: domain.DownloadClientTypeQbittorrent, Enabled: true, Health: ok},
+			wantClient: 1,
+		},
+		{
+			name:       "primary_down",
+			primary:    domain.DownloadClient{ID: 1, Type: domain.DownloadClientTypeQbittorrent, Enabled: true, Health: down},
+			fallback:   domain.DownloadClient{ID: 2, Type: domain.DownloadClientTypeQbittorrent, Enabled: true, Health: ok},
+			wantClient: 2,
+		},
+		{
+			name:       "fallback_down",
+			primary:    domain.DownloadClient{ID: 1, Type: domain.DownloadClientTypeQbittorrent, Enabled: true, Health: down},
+			fallback:   domain.DownloadClient{ID: 2, Type: domain.DownloadClientTypeQbittorrent, Enabled: true, Health: down},
+			wantClient: 1,
+		},
+		{
+			name:       "fallback_other_type",
+			primary:    domain.DownloadClient{ID: 1, Type: domain.DownloadClientTypeQbittorrent, Enabled: true, Health: down},
+			fallback:   domain.DownloadClient{ID: 2, Type: domain.DownloadClientTypeDelugeV2, Enabled: true, Health: ok},
+			wantClient: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &service{
+				log: zerolog.Nop(),
+				clientSvc: &mockClientService{clients: map[int32]*domain.DownloadClient{
+					1: &tt.primary,
+					2: &tt.fallback,
+				}},
+			}
+
+			action := &domain.Action{Name: "qbit", Type: domain.ActionTypeQbittorrent, ClientID: 1, FallbackClientID: 2}
+
+			assert.Equal(t, tt.wantClient, s.failover(action).ClientID)
+
+			if fallback, ok := s.failoverOnError(action); ok {
+				assert.Equal(t, tt.wantClient, fallback.ClientID)
+			} else {
+				assert.Equal(t, int32(1), tt.wantClient)
+			}
+
+			// the action of the filter is left untouched
+			assert.Equal(t, int32(1), action.ClientID)
+		})
+	}
+}
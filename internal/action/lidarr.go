@@ -53,12 +53,31 @@ func (s *service) lidarr(action domain.Action, release domain.Release) ([]string
 		PublishDate:      time.Now().Format(time.RFC3339),
 	}
 
+	r.DownloadClientId, r.DownloadClient, err = arrDownloadClient(action, release)
+	if err != nil {
+		return nil, err
+	}
+
 	// special handling for RED and OPS because their torrent names contain to little info
 	// "Artist - Album" is not enough for Lidarr to make a decision. It needs year like "Artist - Album 2022"
 	if release.Indexer == "redacted" || release.Indexer == "ops" {
 		r.Title = fmt.Sprintf("%v (%d)", release.TorrentName, release.Year)
 	}
 
+	if release.Filter != nil && release.Filter.ArrOnlyMonitored {
+		if rejections := s.lidarrMonitoredRejections(client.ID, arr, release); rejections != nil {
+			s.log.Debug().Msgf("lidarr: skipping push of unmonitored release: %v to %v reasons: '%v'", r.Title, client.Host, rejections)
+			return rejections, nil
+		}
+	}
+
+	if release.Filter != nil && release.Filter.ArrSkipDuplicates {
+		if rejections := s.arrDuplicateRejections(arr, "lidarr", r.Title); rejections != nil {
+			s.log.Debug().Msgf("lidarr: skipping push of duplicate release: %v to %v reasons: '%v'", r.Title, client.Host, rejections)
+			return rejections, nil
+		}
+	}
+
 	rejections, err := arr.Push(r)
 	if err != nil {
 		s.log.Error().Stack().Err(err).Msgf("lidarr: failed to push release: %v", r)
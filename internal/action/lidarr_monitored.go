@@ -0,0 +1,133 @@
+package action
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/pkg/lidarr"
+)
+
+// lidarrLibraryTTL is how long artist and album lookups are reused before lidarr is asked again
+const lidarrLibraryTTL = 10 * time.Minute
+
+// lidarrLibraryClient is implemented by the lidarr client
+type lidarrLibraryClient interface {
+	GetArtists() ([]lidarr.Artist, error)
+	GetAlbums(artistID int) ([]lidarr.Album, error)
+}
+
+type lidarrAlbumsKey struct {
+	clientID int
+	artistID int
+}
+
+// lidarrLibraryCache caches artist and album lookups per download client,
+// every matching announce would otherwise fetch the full artist list.
+type lidarrLibraryCache struct {
+	artists *ttlCache[int, []lidarr.Artist]
+	albums  *ttlCache[lidarrAlbumsKey, []lidarr.Album]
+}
+
+func newLidarrLibraryCache(ttl time.Duration) *lidarrLibraryCache {
+	return &lidarrLibraryCache{
+		artists: newTTLCache[int, []lidarr.Artist](ttl),
+		albums:  newTTLCache[lidarrAlbumsKey, []lidarr.Album](ttl),
+	}
+}
+
+func (c *lidarrLibraryCache) getArtists(clientID int, client lidarrLibraryClient, now time.Time) ([]lidarr.Artist, error) {
+	return c.artists.get(clientID, now, client.GetArtists)
+}
+
+func (c *lidarrLibraryCache) getAlbums(clientID int, artistID int, client lidarrLibraryClient, now time.Time) ([]lidarr.Album, error) {
+	return c.albums.get(lidarrAlbumsKey{clientID: clientID, artistID: artistID}, now, func() ([]lidarr.Album, error) {
+		return client.GetAlbums(artistID)
+	})
+}
+
+// lidarrMonitoredRejections returns a rejection if the artist is missing or unmonitored in lidarr,
+// or if the album is known to lidarr but unmonitored. Albums lidarr does not know about yet are left
+// for lidarr to decide on push. Lookup errors are logged and the push goes ahead.
+func (s *service) lidarrMonitoredRejections(clientID int, client lidarrLibraryClient, release domain.Release) []string {
+	artistName, albumTitle := musicArtistAlbum(release)
+	if artistName == "" {
+		return []string{fmt.Sprintf("could not parse artist: %v", release.TorrentName)}
+	}
+
+	artists, err := s.lidarrLibraries.getArtists(clientID, client, time.Now())
+	if err != nil {
+		s.log.Warn().Err(err).Msgf("lidarr: could not check monitored artists: %v", release.TorrentName)
+		return nil
+	}
+
+	var artist *lidarr.Artist
+	for i := range artists {
+		if normalizeMusicName(artists[i].ArtistName) == normalizeMusicName(artistName) {
+			artist = &artists[i]
+			break
+		}
+	}
+
+	if artist == nil {
+		return []string{fmt.Sprintf("artist not found in lidarr: %v", artistName)}
+	}
+
+	if !artist.Monitored {
+		return []string{fmt.Sprintf("artist not monitored in lidarr: %v", artist.ArtistName)}
+	}
+
+	if albumTitle == "" {
+		return nil
+	}
+
+	albums, err := s.lidarrLibraries.getAlbums(clientID, artist.ID, client, time.Now())
+	if err != nil {
+		s.log.Warn().Err(err).Msgf("lidarr: could not check monitored albums: %v", release.TorrentName)
+		return nil
+	}
+
+	// pick the longest matching title so "Album" does not shadow "Album Deluxe"
+	var album *lidarr.Album
+	normalizedAlbum := normalizeMusicName(albumTitle)
+	for i := range albums {
+		title := normalizeMusicName(albums[i].Title)
+		if title == "" || !strings.HasPrefix(normalizedAlbum, title) {
+			continue
+		}
+
+		if album == nil || len(title) > len(normalizeMusicName(album.Title)) {
+			album = &albums[i]
+		}
+	}
+
+	if album != nil && !album.Monitored {
+		return []string{fmt.Sprintf("album not monitored in lidarr: %v - %v", artist.ArtistName, album.Title)}
+	}
+
+	return nil
+}
+
+// musicArtistAlbum splits "Artist - Album [2022] [Album] - FLAC" style names,
+// preferring the parsed artist when there is one.
+func musicArtistAlbum(release domain.Release) (string, string) {
+	artist, album, found := strings.Cut(release.TorrentName, " - ")
+	if !found {
+		return release.Artists, ""
+	}
+
+	if release.Artists != "" {
+		artist = release.Artists
+	}
+
+	return strings.TrimSpace(artist), strings.TrimSpace(album)
+}
+
+// normalizeMusicName lowercases and keeps only letters and digits separated by single spaces
+func normalizeMusicName(name string) string {
+	return strings.Join(strings.FieldsFunc(strings.ToLower(name), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}), " ")
+}
@@ -0,0 +1,140 @@
+package action
+
+import (
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/pkg/errors"
+	"github.com/autobrr/autobrr/pkg/lidarr"
+)
+
+type mockLidarrLibrary struct {
+	artists []lidarr.Artist
+	albums  map[int][]lidarr.Album
+	err     error
+
+	artistCalls int
+	albumCalls  int
+}
+
+func (m *mockLidarrLibrary) GetArtists() ([]lidarr.Artist, error) {
+	m.artistCalls++
+	return m.artists, m.err
+}
+
+func (m *mockLidarrLibrary) GetAlbums(artistID int) ([]lidarr.Album, error) {
+	m.albumCalls++
+	return m.albums[artistID], m.err
+}
+
+func Test_service_lidarrMonitoredRejections(t *testing.T) {
+	library := func() *mockLidarrLibrary {
+		return &mockLidarrLibrary{
+			artists: []lidarr.Artist{
+				{ID: 1, ArtistName: "JR Get Money", Monitored: true},
+				{ID: 2, ArtistName: "Some Band", Monitored: false},
+			},
+			albums: map[int][]lidarr.Album{
+				1: {
+					{ID: 10, ArtistID: 1, Title: "Nobody But You", Monitored: true},
+					{ID: 11, ArtistID: 1, Title: "Nobody But You (Deluxe)", Monitored: false},
+					{ID: 12, ArtistID: 1, Title: "Old Record", Monitored: false},
+				},
+			},
+		}
+	}
+
+	tests := []struct {
+		name        string
+		torrentName string
+		err         error
+		want        []string
+	}{
+		{
+			name:        "monitored",
+			torrentName: "JR Get Money - Nobody But You [2008] [Single] - FLAC / Lossless / Log / 100% / Cue / CD",
+		},
+		{
+			name:        "unknown_album",
+			torrentName: "JR Get Money - Brand New [2022] [Album] - FLAC / Lossless / Log / 100% / Cue / CD",
+		},
+		{
+			name:        "unmonitored_album",
+			torrentName: "JR Get Money - Old Record [2001] [Album] - MP3 / 320 / WEB",
+			want:        []string{"album not monitored in lidarr: JR Get Money - Old Record"},
+		},
+		{
+			name:        "longest_album_match",
+			torrentName: "JR Get Money - Nobody But You (Deluxe) [2009] [Album] - MP3 / 320 / WEB",
+			want:        []string{"album not monitored in lidarr: JR Get Money - Nobody But You (Deluxe)"},
+		},
+		{
+			name:        "unmonitored_artist",
+			torrentName: "Some Band - Some Album [2020] [Album] - FLAC / 24bit Lossless / WEB",
+			want:        []string{"artist not monitored in lidarr: Some Band"},
+		},
+		{
+			name:        "unknown_artist",
+			torrentName: "Other Band - Some Album [2020] [Album] - FLAC / 24bit Lossless / WEB",
+			want:        []string{"artist not found in lidarr: Other Band"},
+		},
+		{
+			name:        "lookup_error",
+			torrentName: "Other Band - Some Album [2020] [Album] - FLAC / 24bit Lossless / WEB",
+			err:         errors.New("connection refused"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &service{log: zerolog.Nop(), lidarrLibraries: newLidarrLibraryCache(lidarrLibraryTTL)}
+
+			client := library()
+			client.err = tt.err
+
+			got := s.lidarrMonitoredRejections(1, client, domain.Release{TorrentName: tt.torrentName})
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_lidarrLibraryCache(t *testing.T) {
+	now := time.Now()
+	client := &mockLidarrLibrary{artists: []lidarr.Artist{{ID: 1, ArtistName: "JR Get Money"}}}
+
+	cache := newLidarrLibraryCache(time.Minute)
+
+	_, err := cache.getArtists(1, client, now)
+	assert.NoError(t, err)
+	_, err = cache.getArtists(1, client, now.Add(30*time.Second))
+	assert.NoError(t, err)
+	assert.Equal(t, 1, client.artistCalls)
+
+	// cached per client
+	_, err = cache.getArtists(2, client, now)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, client.artistCalls)
+
+	// expired
+	_, err = cache.getArtists(1, client, now.Add(2*time.Minute))
+	assert.NoError(t, err)
+	assert.Equal(t, 3, client.artistCalls)
+
+	_, err = cache.getAlbums(1, 1, client, now)
+	assert.NoError(t, err)
+	_, err = cache.getAlbums(1, 1, client, now)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, client.albumCalls)
+
+	// errors are not cached
+	client.err = errors.New("connection refused")
+	_, err = cache.getAlbums(1, 2, client, now)
+	assert.Error(t, err)
+	client.err = nil
+	_, err = cache.getAlbums(1, 2, client, now)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, client.albumCalls)
+}
@@ -0,0 +1,107 @@
+package action
+
+import (
+	"context"
+	"encoding/base64"
+	"os"
+	"strings"
+
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/pkg/errors"
+	"github.com/autobrr/autobrr/pkg/porla"
+)
+
+func (s *service) porla(action domain.Action, release domain.Release) ([]string, error) {
+	s.log.Debug().Msgf("action Porla: %v", action.Name)
+
+	// get client for action
+	client, err := s.clientSvc.FindByID(context.TODO(), action.ClientID)
+	if err != nil {
+		s.log.Error().Stack().Err(err).Msgf("error finding client: %v", action.ClientID)
+		return nil, err
+	}
+
+	if client == nil {
+		return nil, errors.New("could not find client by id: %v", action.ClientID)
+	}
+
+	var rejections []string
+
+	req := &porla.TorrentsAddReq{}
+
+	// magnet links are added as is, porla fetches the metadata from peers
+	if strings.HasPrefix(release.TorrentURL, "magnet:") {
+		req.MagnetURI = release.TorrentURL
+	} else {
+		if release.TorrentTmpFile == "" {
+			if err := release.DownloadTorrentFile(); err != nil {
+				s.log.Error().Err(err).Msgf("could not download torrent file for release: %v", release.TorrentName)
+				return nil, err
+			}
+		}
+
+		data, err := os.ReadFile(release.TorrentTmpFile)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not read torrent file: %v", release.TorrentTmpFile)
+		}
+
+		req.Ti = base64.StdEncoding.EncodeToString(data)
+	}
+
+	// porla has no api to look up torrents by hash, only the blocklist is checked
+	rejections, err = s.checkTorrent(client, &release, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if rejections != nil {
+		return rejections, nil
+	}
+
+	m := domain.NewMacro(release)
+
+	// the label is used as the porla preset
+	if action.Label != "" {
+		preset, err := m.Parse(action.Label)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not parse preset macro: %v", action.Label)
+		}
+		req.Preset = preset
+	}
+
+	if action.SavePath != "" {
+		savePath, err := m.Parse(action.SavePath)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not parse save path macro: %v", action.SavePath)
+		}
+		req.SavePath = savePath
+	}
+
+	if action.Tags != "" {
+		tags, err := m.Parse(action.Tags)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not parse tags macro: %v", action.Tags)
+		}
+
+		for _, tag := range strings.Split(tags, ",") {
+			if tag = strings.TrimSpace(tag); tag != "" {
+				req.Tags = append(req.Tags, tag)
+			}
+		}
+	}
+
+	p := porla.New(porla.Config{
+		Hostname:      client.Host,
+		AuthToken:     client.Settings.APIKey,
+		TLSSkipVerify: client.TLSSkipVerify,
+	})
+
+	res, err := p.TorrentsAdd(req)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not add torrent %v to client: %v", release.TorrentName, client.Name)
+	}
+
+	s.log.Info().Msgf("torrent with hash %v successfully added to client: '%v'", res.Hash(), client.Name)
+
+	return rejections, nil
+}
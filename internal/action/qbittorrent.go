@@ -29,33 +29,9 @@ func (s *service) qbittorrent(action domain.Action, release domain.Release) ([]s
 		return nil, errors.New("could not find client by id: %v", action.ClientID)
 	}
 
-	qbtSettings := qbittorrent.Settings{
-		Name:          client.Name,
-		Hostname:      client.Host,
-		Port:          uint(client.Port),
-		Username:      client.Username,
-		Password:      client.Password,
-		TLS:           client.TLS,
-		TLSSkipVerify: client.TLSSkipVerify,
-	}
-
-	// setup sub logger adapter which is compatible with *log.Logger
-	qbtSettings.Log = zstdlog.NewStdLoggerWithLevel(s.log.With().Str("type", "qBittorrent").Str("client", client.Name).Logger(), zerolog.TraceLevel)
-
-	// only set basic auth if enabled
-	if client.Settings.Basic.Auth {
-		qbtSettings.BasicAuth = client.Settings.Basic.Auth
-		qbtSettings.Basic.Username = client.Settings.Basic.Username
-		qbtSettings.Basic.Password = client.Settings.Basic.Password
-	}
-
-	qbt := qbittorrent.NewClient(qbtSettings)
-
-	// only login if we have a password
-	if qbtSettings.Password != "" {
-		if err = qbt.Login(); err != nil {
-			return nil, errors.Wrap(err, "could not log into client: %v at %v", client.Name, client.Host)
-		}
+	qbt, err := s.qbittorrentClient(client)
+	if err != nil {
+		return nil, err
 	}
 
 	rejections, err := s.qbittorrentCheckRulesCanDownload(action, client, qbt)
@@ -74,10 +50,24 @@ func (s *service) qbittorrent(action domain.Action, release domain.Release) ([]s
 		}
 	}
 
+	rejections, err = s.checkTorrent(client, &release, func(hash string) (bool, error) {
+		torrents, err := qbt.GetTorrentsByHashes([]string{hash})
+		return len(torrents) > 0, err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if rejections != nil {
+		return rejections, nil
+	}
+
 	// macros handle args and replace vars
 	m := domain.NewMacro(release)
 
-	options, err := s.prepareQbitOptions(action, m)
+	rule := client.Settings.Rules.TrackerRule(release.Indexer)
+
+	options, err := s.prepareQbitOptions(action, m, rule)
 	if err != nil {
 		return nil, errors.Wrap(err, "could not prepare options")
 	}
@@ -88,7 +78,9 @@ func (s *service) qbittorrent(action domain.Action, release domain.Release) ([]s
 		return nil, errors.Wrap(err, "could not add torrent %v to client: %v", release.TorrentTmpFile, client.Name)
 	}
 
-	if !action.Paused && !action.ReAnnounceSkip && release.TorrentHash != "" {
+	paused := action.Paused || (rule != nil && rule.Paused)
+
+	if !paused && !action.ReAnnounceSkip && release.TorrentHash != "" {
 		if err := s.reannounceTorrent(qbt, action, release.TorrentHash); err != nil {
 			return nil, errors.Wrap(err, "could not reannounce torrent: %v", release.TorrentHash)
 		}
@@ -99,7 +91,41 @@ func (s *service) qbittorrent(action domain.Action, release domain.Release) ([]s
 	return nil, nil
 }
 
-func (s *service) prepareQbitOptions(action domain.Action, m domain.Macro) (map[string]string, error) {
+// qbittorrentClient sets up a client and logs in if a password is set
+func (s *service) qbittorrentClient(client *domain.DownloadClient) (*qbittorrent.Client, error) {
+	qbtSettings := qbittorrent.Settings{
+		Name:          client.Name,
+		Hostname:      client.Host,
+		Port:          uint(client.Port),
+		Username:      client.Username,
+		Password:      client.Password,
+		TLS:           client.TLS,
+		TLSSkipVerify: client.TLSSkipVerify,
+	}
+
+	// setup sub logger adapter which is compatible with *log.Logger
+	qbtSettings.Log = zstdlog.NewStdLoggerWithLevel(s.log.With().Str("type", "qBittorrent").Str("client", client.Name).Logger(), zerolog.TraceLevel)
+
+	// only set basic auth if enabled
+	if client.Settings.Basic.Auth {
+		qbtSettings.BasicAuth = client.Settings.Basic.Auth
+		qbtSettings.Basic.Username = client.Settings.Basic.Username
+		qbtSettings.Basic.Password = client.Settings.Basic.Password
+	}
+
+	qbt := qbittorrent.NewClient(qbtSettings)
+
+	// only login if we have a password
+	if qbtSettings.Password != "" {
+		if err := qbt.Login(); err != nil {
+			return nil, errors.Wrap(err, "could not log into client: %v at %v", client.Name, client.Host)
+		}
+	}
+
+	return qbt, nil
+}
+
+func (s *service) prepareQbitOptions(action domain.Action, m domain.Macro, rule *domain.DownloadClientTrackerRule) (map[string]string, error) {
 
 	opts := &qbittorrent.TorrentAddOptions{}
 
@@ -160,9 +186,48 @@ func (s *service) prepareQbitOptions(action domain.Action, m domain.Macro) (map[
 		opts.LimitSeedTime = &action.LimitSeedTime
 	}
 
+	if rule != nil {
+		if err := applyQbitTrackerRule(opts, rule, m); err != nil {
+			return nil, err
+		}
+	}
+
 	return opts.Prepare(), nil
 }
 
+// applyQbitTrackerRule fills in the options not set by the action from the client tracker rule
+func applyQbitTrackerRule(opts *qbittorrent.TorrentAddOptions, rule *domain.DownloadClientTrackerRule, m domain.Macro) error {
+	if rule.Paused {
+		opts.Paused = BoolPointer(true)
+	}
+	if rule.Tags != "" {
+		tagsArgs, err := m.Parse(rule.Tags)
+		if err != nil {
+			return errors.Wrap(err, "could not parse tracker rule tags macro: %v", rule.Tags)
+		}
+
+		if opts.Tags != nil && *opts.Tags != "" {
+			tagsArgs = *opts.Tags + "," + tagsArgs
+		}
+
+		opts.Tags = &tagsArgs
+	}
+	if opts.LimitUploadSpeed == nil && rule.LimitUploadSpeed > 0 {
+		opts.LimitUploadSpeed = &rule.LimitUploadSpeed
+	}
+	if opts.LimitDownloadSpeed == nil && rule.LimitDownloadSpeed > 0 {
+		opts.LimitDownloadSpeed = &rule.LimitDownloadSpeed
+	}
+	if opts.LimitRatio == nil && rule.LimitRatio > 0 {
+		opts.LimitRatio = &rule.LimitRatio
+	}
+	if opts.LimitSeedTime == nil && rule.LimitSeedTime > 0 {
+		opts.LimitSeedTime = &rule.LimitSeedTime
+	}
+
+	return nil
+}
+
 func BoolPointer(b bool) *bool {
 	return &b
 }
@@ -0,0 +1,64 @@
+package action
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/autobrr/autobrr/internal/domain"
+)
+
+func Test_service_prepareQbitOptions(t *testing.T) {
+	release := domain.Release{
+		Indexer:     "mock",
+		TorrentName: "That.Show.S01E01.1080p.WEB-DL-GROUP",
+	}
+
+	tests := []struct {
+		name   string
+		action domain.Action
+		rule   *domain.DownloadClientTrackerRule
+		want   map[string]string
+	}{
+		{
+			name:   "action_only",
+			action: domain.Action{Tags: "tv", LimitRatio: 2},
+			want: map[string]string{
+				"tags":       "tv",
+				"ratioLimit": "2.00",
+			},
+		},
+		{
+			name:   "tracker_rule_defaults",
+			action: domain.Action{Category: "tv"},
+			rule:   &domain.DownloadClientTrackerRule{Indexer: "mock", Tags: "{{ .Indexer }}", Paused: true, LimitRatio: 1.5, LimitSeedTime: 600},
+			want: map[string]string{
+				"category":         "tv",
+				"tags":             "mock",
+				"paused":           "true",
+				"ratioLimit":       "1.50",
+				"seedingTimeLimit": "600",
+			},
+		},
+		{
+			name:   "action_limits_take_precedence",
+			action: domain.Action{Tags: "tv", LimitRatio: 3, LimitUploadSpeed: 100},
+			rule:   &domain.DownloadClientTrackerRule{Indexer: "mock", Tags: "mock", LimitRatio: 1, LimitUploadSpeed: 50, LimitDownloadSpeed: 200},
+			want: map[string]string{
+				"tags":       "tv,mock",
+				"ratioLimit": "3.00",
+				"upLimit":    "100000",
+				"dlLimit":    "200000",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &service{}
+
+			got, err := s.prepareQbitOptions(tt.action, domain.NewMacro(release), tt.rule)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
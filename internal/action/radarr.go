@@ -49,6 +49,19 @@ func (s *service) radarr(action domain.Action, release domain.Release) ([]string
 		DownloadProtocol: "torrent",
 		Protocol:         "torrent",
 		PublishDate:      time.Now().Format(time.RFC3339),
+		IndexerFlags:     arrIndexerFlags(release, radarrIndexerFlags),
+	}
+
+	r.DownloadClientId, r.DownloadClient, err = arrDownloadClient(action, release)
+	if err != nil {
+		return nil, err
+	}
+
+	if release.Filter != nil && release.Filter.ArrSkipDuplicates {
+		if rejections := s.arrDuplicateRejections(arr, "radarr", r.Title); rejections != nil {
+			s.log.Debug().Msgf("radarr: skipping push of duplicate release: %v to %v reasons: '%v'", r.Title, client.Host, rejections)
+			return rejections, nil
+		}
 	}
 
 	rejections, err := arr.Push(r)
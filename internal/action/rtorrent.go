@@ -2,9 +2,11 @@ package action
 
 import (
 	"context"
+	"os"
+	"strings"
+
 	"github.com/autobrr/autobrr/internal/domain"
 	"github.com/autobrr/autobrr/pkg/errors"
-	"os"
 
 	"github.com/mrobinsn/go-rtorrent/rtorrent"
 )
@@ -37,27 +39,81 @@ func (s *service) rtorrent(action domain.Action, release domain.Release) ([]stri
 	// create client
 	rt := rtorrent.New(client.Host, true)
 
+	rejections, err = s.checkTorrent(client, &release, func(hash string) (bool, error) {
+		torrents, err := rt.GetTorrents(rtorrent.ViewMain)
+		if err != nil {
+			return false, err
+		}
+
+		for _, t := range torrents {
+			if strings.EqualFold(t.Hash, hash) {
+				return true, nil
+			}
+		}
+
+		return false, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if rejections != nil {
+		return rejections, nil
+	}
+
 	tmpFile, err := os.ReadFile(release.TorrentTmpFile)
 	if err != nil {
 		return nil, errors.Wrap(err, "could not read torrent file: %v", release.TorrentTmpFile)
 	}
 
+	// macros handle args and replace vars
+	m := domain.NewMacro(release)
+
 	var args []*rtorrent.FieldValue
 
 	if action.Label != "" {
+		label, err := m.Parse(action.Label)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not parse macro label: %v", action.Label)
+		}
+
 		args = append(args, &rtorrent.FieldValue{
 			Field: rtorrent.DLabel,
-			Value: action.Label,
+			Value: label,
 		})
 	}
+
+	var savePath string
 	if action.SavePath != "" {
+		savePath, err = m.Parse(action.SavePath)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not parse save path macro: %v", action.SavePath)
+		}
+
 		args = append(args, &rtorrent.FieldValue{
 			Field: rtorrent.DDirectory,
-			Value: action.SavePath,
+			Value: savePath,
 		})
 	}
 
-	if err := rt.AddTorrent(tmpFile, args...); err != nil {
+	// fast resume lets rTorrent seed content that is already on disk without checking it, eg. for cross seeding.
+	// When the content is not there the torrent is added as usual.
+	if action.SkipHashCheck {
+		if savePath == "" {
+			s.log.Warn().Msgf("fast resume needs a save path, adding torrent without it: %v", release.TorrentName)
+		} else if resumed, err := rtorrentFastResume(tmpFile, savePath); err != nil {
+			s.log.Warn().Err(err).Msgf("could not add fast resume data, adding torrent without it: %v", release.TorrentName)
+		} else {
+			tmpFile = resumed
+		}
+	}
+
+	if action.Paused {
+		err = rt.AddTorrentStopped(tmpFile, args...)
+	} else {
+		err = rt.AddTorrent(tmpFile, args...)
+	}
+	if err != nil {
 		return nil, errors.Wrap(err, "could not add torrent file: %v", release.TorrentTmpFile)
 	}
 
@@ -0,0 +1,94 @@
+package action
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/autobrr/autobrr/pkg/errors"
+
+	"github.com/anacrolix/torrent/bencode"
+	"github.com/anacrolix/torrent/metainfo"
+)
+
+type rtorrentResume struct {
+	Bitfield int                  `bencode:"bitfield"`
+	Files    []rtorrentResumeFile `bencode:"files"`
+}
+
+type rtorrentResumeFile struct {
+	Priority  int   `bencode:"priority"`
+	MTime     int64 `bencode:"mtime"`
+	Completed int   `bencode:"completed"`
+}
+
+// rtorrentFastResume adds libtorrent resume data to the torrent so rTorrent takes the content in dir
+// as complete and starts seeding without hashing it, like rtorrent_fast_resume.pl does.
+// The content has to be reachable by autobrr at the same path rTorrent uses.
+func rtorrentFastResume(data []byte, dir string) ([]byte, error) {
+	// keep the info dictionary as is so the info hash does not change
+	var torrent map[string]bencode.Bytes
+	if err := bencode.Unmarshal(data, &torrent); err != nil {
+		return nil, errors.Wrap(err, "could not decode torrent")
+	}
+
+	infoBytes, ok := torrent["info"]
+	if !ok {
+		return nil, errors.New("torrent has no info dictionary")
+	}
+
+	var info metainfo.Info
+	if err := bencode.Unmarshal(infoBytes, &info); err != nil {
+		return nil, errors.Wrap(err, "could not decode torrent info")
+	}
+
+	if info.PieceLength <= 0 {
+		return nil, errors.New("invalid piece length: %d", info.PieceLength)
+	}
+
+	base := filepath.Join(dir, info.Name)
+
+	resume := rtorrentResume{
+		Bitfield: info.NumPieces(),
+		Files:    make([]rtorrentResumeFile, 0, len(info.UpvertedFiles())),
+	}
+
+	var offset int64
+	for _, f := range info.UpvertedFiles() {
+		path := base
+		if info.IsDir() {
+			path = filepath.Join(append([]string{base}, f.Path...)...)
+		}
+
+		stat, err := os.Stat(path)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not find content file: %v", path)
+		}
+
+		if stat.Size() != f.Length {
+			return nil, errors.New("size of %v is %d, torrent expects %d", path, stat.Size(), f.Length)
+		}
+
+		// number of pieces the file touches
+		completed := 0
+		if f.Length > 0 {
+			completed = int((offset+f.Length-1)/info.PieceLength - offset/info.PieceLength + 1)
+		}
+
+		resume.Files = append(resume.Files, rtorrentResumeFile{
+			Priority:  1,
+			MTime:     stat.ModTime().Unix(),
+			Completed: completed,
+		})
+
+		offset += f.Length
+	}
+
+	resumeBytes, err := bencode.Marshal(resume)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not encode resume data")
+	}
+
+	torrent["libtorrent_resume"] = resumeBytes
+
+	return bencode.Marshal(torrent)
+}
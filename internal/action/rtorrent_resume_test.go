@@ -0,0 +1,79 @@
+package action
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/anacrolix/torrent/bencode"
+	"github.com/anacrolix/torrent/metainfo"
+	"github.com/stretchr/testify/assert"
+)
+
+// newResumeTestTorrent builds a torrent of the content at root with 16 KiB pieces
+func newResumeTestTorrent(t *testing.T, root string) []byte {
+	info := metainfo.Info{PieceLength: 16 * 1024}
+	assert.NoError(t, info.BuildFromFilePath(root))
+
+	infoBytes, err := bencode.Marshal(info)
+	assert.NoError(t, err)
+
+	mi := metainfo.MetaInfo{Announce: "https://tracker.test/announce", InfoBytes: infoBytes}
+
+	var buf bytes.Buffer
+	assert.NoError(t, mi.Write(&buf))
+
+	return buf.Bytes()
+}
+
+func Test_rtorrentFastResume(t *testing.T) {
+	dir := t.TempDir()
+	content := filepath.Join(dir, "That.Show.S01.1080p.WEB-DL-GROUP")
+
+	assert.NoError(t, os.MkdirAll(filepath.Join(content, "Subs"), 0755))
+	assert.NoError(t, os.WriteFile(filepath.Join(content, "episode1.mkv"), bytes.Repeat([]byte("a"), 40*1024), 0644))
+	assert.NoError(t, os.WriteFile(filepath.Join(content, "Subs", "episode1.srt"), bytes.Repeat([]byte("b"), 1024), 0644))
+
+	data := newResumeTestTorrent(t, content)
+
+	t.Run("content_exists", func(t *testing.T) {
+		resumed, err := rtorrentFastResume(data, dir)
+		assert.NoError(t, err)
+
+		before, err := metainfo.Load(bytes.NewReader(data))
+		assert.NoError(t, err)
+
+		after, err := metainfo.Load(bytes.NewReader(resumed))
+		assert.NoError(t, err)
+
+		assert.Equal(t, before.HashInfoBytes(), after.HashInfoBytes())
+
+		var torrent struct {
+			Resume rtorrentResume `bencode:"libtorrent_resume"`
+		}
+		assert.NoError(t, bencode.Unmarshal(resumed, &torrent))
+
+		// 41 KiB in 16 KiB pieces, the srt shares the last piece with the end of the mkv
+		assert.Equal(t, 3, torrent.Resume.Bitfield)
+		if assert.Len(t, torrent.Resume.Files, 2) {
+			completed := []int{torrent.Resume.Files[0].Completed, torrent.Resume.Files[1].Completed}
+			assert.ElementsMatch(t, []int{3, 1}, completed)
+		}
+	})
+
+	t.Run("content_missing", func(t *testing.T) {
+		_, err := rtorrentFastResume(data, t.TempDir())
+		assert.Error(t, err)
+	})
+
+	t.Run("size_mismatch", func(t *testing.T) {
+		other := t.TempDir()
+		assert.NoError(t, os.MkdirAll(filepath.Join(other, "That.Show.S01.1080p.WEB-DL-GROUP", "Subs"), 0755))
+		assert.NoError(t, os.WriteFile(filepath.Join(other, "That.Show.S01.1080p.WEB-DL-GROUP", "episode1.mkv"), []byte("partial"), 0644))
+		assert.NoError(t, os.WriteFile(filepath.Join(other, "That.Show.S01.1080p.WEB-DL-GROUP", "Subs", "episode1.srt"), bytes.Repeat([]byte("b"), 1024), 0644))
+
+		_, err := rtorrentFastResume(data, other)
+		assert.Error(t, err)
+	})
+}
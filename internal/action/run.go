@@ -2,8 +2,8 @@ package action
 
 import (
 	"bytes"
+	"context"
 	"crypto/tls"
-	"io"
 	"net/http"
 	"os"
 	"path/filepath"
@@ -19,6 +19,7 @@ func (s *service) RunAction(action *domain.Action, release domain.Release) ([]st
 	var (
 		err        error
 		rejections []string
+		actionLog  string
 	)
 
 	defer func() {
@@ -29,12 +30,15 @@ func (s *service) RunAction(action *domain.Action, release domain.Release) ([]st
 		}
 	}()
 
+	// use the fallback client if the client of the action is down
+	action = s.failover(action)
+
 	switch action.Type {
 	case domain.ActionTypeTest:
 		s.test(action.Name)
 
 	case domain.ActionTypeExec:
-		err = s.execCmd(*action, release)
+		actionLog, err = s.execCmd(*action, release)
 
 	case domain.ActionTypeWatchFolder:
 		err = s.watchFolder(*action, release)
@@ -54,6 +58,9 @@ func (s *service) RunAction(action *domain.Action, release domain.Release) ([]st
 	case domain.ActionTypeTransmission:
 		rejections, err = s.transmission(*action, release)
 
+	case domain.ActionTypePorla:
+		rejections, err = s.porla(*action, release)
+
 	case domain.ActionTypeRadarr:
 		rejections, err = s.radarr(*action, release)
 
@@ -66,11 +73,20 @@ func (s *service) RunAction(action *domain.Action, release domain.Release) ([]st
 	case domain.ActionTypeWhisparr:
 		rejections, err = s.whisparr(*action, release)
 
+	case domain.ActionTypeCrossSeed:
+		rejections, err = s.crossSeed(*action, release)
+
 	default:
 		s.log.Warn().Msgf("unsupported action type: %v", action.Type)
 		return rejections, err
 	}
 
+	if err != nil && rejections == nil {
+		if fallback, ok := s.failoverOnError(action); ok {
+			return s.RunAction(fallback, release)
+		}
+	}
+
 	rlsActionStatus := &domain.ReleaseActionStatus{
 		ReleaseID:  release.ID,
 		Status:     domain.ReleasePushStatusApproved,
@@ -79,6 +95,7 @@ func (s *service) RunAction(action *domain.Action, release domain.Release) ([]st
 		Client:     action.Client.Name,
 		Filter:     release.Filter.Name,
 		Rejections: []string{},
+		Log:        actionLog,
 		Timestamp:  time.Now(),
 	}
 
@@ -97,6 +114,7 @@ func (s *service) RunAction(action *domain.Action, release domain.Release) ([]st
 		Protocol:       domain.ReleaseProtocolTorrent,
 		Implementation: domain.ReleaseImplementationIRC,
 		Timestamp:      time.Now(),
+		Release:        &release,
 	}
 
 	if err != nil {
@@ -148,12 +166,23 @@ func (s *service) watchFolder(action domain.Action, release domain.Release) erro
 		release.TorrentDataRawBytes = t
 	}
 
-	m := domain.NewMacro(release)
+	// the torrent file goes to the watch folder of the client if the action has one
+	var client *domain.DownloadClient
+	if action.ClientID != 0 {
+		var err error
+		client, err = s.clientSvc.FindByID(context.TODO(), action.ClientID)
+		if err != nil {
+			return errors.Wrap(err, "could not find client by id: %v", action.ClientID)
+		}
 
-	// parse and replace values in argument string before continuing
-	watchFolderArgs, err := m.Parse(action.WatchFolder)
+		if client == nil {
+			return errors.New("could not find client by id: %v", action.ClientID)
+		}
+	}
+
+	watchFolderArgs, err := watchFolderDir(action, client, release)
 	if err != nil {
-		return errors.Wrap(err, "could not parse watch folder macro: %v", action.WatchFolder)
+		return err
 	}
 
 	s.log.Trace().Msgf("action WATCH_FOLDER: %v file: %v", watchFolderArgs, release.TorrentTmpFile)
@@ -173,15 +202,8 @@ func (s *service) watchFolder(action domain.Action, release domain.Release) erro
 		return errors.Wrap(err, "could not create new folders %v", fullFileName)
 	}
 
-	// Create new file
-	newFile, err := os.Create(fullFileName)
-	if err != nil {
-		return errors.Wrap(err, "could not create new file %v", fullFileName)
-	}
-	defer newFile.Close()
-
-	// Copy file
-	if _, err := io.Copy(newFile, original); err != nil {
+	// Write file atomically so the client does not pick up a partial file
+	if err := writeFileAtomic(fullFileName, original); err != nil {
 		return errors.Wrap(err, "could not copy file %v to watch folder", fullFileName)
 	}
 
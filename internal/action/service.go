@@ -7,6 +7,8 @@ import (
 	"github.com/autobrr/autobrr/internal/domain"
 	"github.com/autobrr/autobrr/internal/download_client"
 	"github.com/autobrr/autobrr/internal/logger"
+	"github.com/autobrr/autobrr/pkg/arr"
+	"github.com/autobrr/autobrr/pkg/errors"
 	"github.com/autobrr/autobrr/pkg/qbittorrent"
 
 	"github.com/asaskevich/EventBus"
@@ -17,11 +19,16 @@ import (
 type Service interface {
 	Store(ctx context.Context, action domain.Action) (*domain.Action, error)
 	List(ctx context.Context) ([]domain.Action, error)
+	FindFilterDependencies(ctx context.Context) (domain.FilterDependencies, error)
 	Delete(actionID int) error
 	DeleteByFilterID(ctx context.Context, filterID int) error
 	ToggleEnabled(actionID int) error
 
 	RunAction(action *domain.Action, release domain.Release) ([]string, error)
+
+	ListBlocklist(ctx context.Context) ([]domain.TorrentBlocklistEntry, error)
+	StoreBlocklistEntry(ctx context.Context, entry *domain.TorrentBlocklistEntry) error
+	DeleteBlocklistEntry(ctx context.Context, id int) error
 }
 
 type qbitKey struct {
@@ -36,16 +43,24 @@ type service struct {
 	clientSvc download_client.Service
 	bus       EventBus.Bus
 
+	blocklistRepo domain.TorrentBlocklistRepo
+
 	qbitClients map[qbitKey]qbittorrent.Client
+
+	lidarrLibraries *lidarrLibraryCache
+	arrSeries       *ttlCache[int, []arr.Series]
 }
 
-func NewService(log logger.Logger, repo domain.ActionRepo, clientSvc download_client.Service, bus EventBus.Bus) Service {
+func NewService(log logger.Logger, repo domain.ActionRepo, clientSvc download_client.Service, blocklistRepo domain.TorrentBlocklistRepo, bus EventBus.Bus) Service {
 	s := &service{
-		log:         log.With().Str("module", "action").Logger(),
-		repo:        repo,
-		clientSvc:   clientSvc,
-		bus:         bus,
-		qbitClients: map[qbitKey]qbittorrent.Client{},
+		log:             log.With().Str("module", "action").Logger(),
+		repo:            repo,
+		clientSvc:       clientSvc,
+		blocklistRepo:   blocklistRepo,
+		bus:             bus,
+		qbitClients:     map[qbitKey]qbittorrent.Client{},
+		lidarrLibraries: newLidarrLibraryCache(lidarrLibraryTTL),
+		arrSeries:       newTTLCache[int, []arr.Series](arrSeriesTTL),
 	}
 
 	s.subLogger = zstdlog.NewStdLoggerWithLevel(s.log.With().Logger(), zerolog.TraceLevel)
@@ -54,6 +69,22 @@ func NewService(log logger.Logger, repo domain.ActionRepo, clientSvc download_cl
 }
 
 func (s *service) Store(ctx context.Context, action domain.Action) (*domain.Action, error) {
+	if err := action.ValidateRunCondition(); err != nil {
+		return nil, errors.Wrap(err, "validation")
+	}
+
+	if action.Enabled && action.DependsOnFilterID != 0 {
+		deps, err := s.repo.FindFilterDependencies(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		deps[action.FilterID] = append(deps[action.FilterID], action.DependsOnFilterID)
+		if cycle := deps.FindCycle(); cycle != nil {
+			return nil, errors.New("validation: action dependencies form a cycle between filters: %v", cycle)
+		}
+	}
+
 	return s.repo.Store(ctx, action)
 }
 
@@ -65,6 +96,10 @@ func (s *service) DeleteByFilterID(ctx context.Context, filterID int) error {
 	return s.repo.DeleteByFilterID(ctx, filterID)
 }
 
+func (s *service) FindFilterDependencies(ctx context.Context) (domain.FilterDependencies, error) {
+	return s.repo.FindFilterDependencies(ctx)
+}
+
 func (s *service) List(ctx context.Context) ([]domain.Action, error) {
 	return s.repo.List(ctx)
 }
@@ -49,6 +49,26 @@ func (s *service) sonarr(action domain.Action, release domain.Release) ([]string
 		DownloadProtocol: "torrent",
 		Protocol:         "torrent",
 		PublishDate:      time.Now().Format(time.RFC3339),
+		IndexerFlags:     arrIndexerFlags(release, sonarrIndexerFlags),
+	}
+
+	r.DownloadClientId, r.DownloadClient, err = arrDownloadClient(action, release)
+	if err != nil {
+		return nil, err
+	}
+
+	if release.Filter != nil && release.Filter.ArrSeasonPackThreshold > 0 {
+		if rejections := s.arrSeasonPackRejections(client.ID, arr, "sonarr", release, release.Filter.ArrSeasonPackThreshold); rejections != nil {
+			s.log.Debug().Msgf("sonarr: skipping push of season pack: %v to %v reasons: '%v'", r.Title, client.Host, rejections)
+			return rejections, nil
+		}
+	}
+
+	if release.Filter != nil && release.Filter.ArrSkipDuplicates {
+		if rejections := s.arrDuplicateRejections(arr, "sonarr", r.Title); rejections != nil {
+			s.log.Debug().Msgf("sonarr: skipping push of duplicate release: %v to %v reasons: '%v'", r.Title, client.Host, rejections)
+			return rejections, nil
+		}
 	}
 
 	rejections, err := arr.Push(r)
@@ -0,0 +1,81 @@
+package action
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/pkg/errors"
+)
+
+// torrentInClientFunc reports whether a torrent with the info hash is already in the download client
+type torrentInClientFunc func(hash string) (bool, error)
+
+// checkTorrent rejects a downloaded torrent that is blocklisted or already in the client.
+// Clients error or silently ignore duplicate adds, rejecting gives a clear reason instead.
+// A failed client lookup is only logged and the torrent is still sent to the client.
+func (s *service) checkTorrent(client *domain.DownloadClient, release *domain.Release, inClient torrentInClientFunc) ([]string, error) {
+	rejections, err := s.checkTorrentBlocklist(context.TODO(), release)
+	if err != nil {
+		return nil, err
+	}
+
+	if rejections != nil {
+		return rejections, nil
+	}
+
+	hash := release.InfoHashes().Primary()
+	if hash == "" || inClient == nil {
+		return nil, nil
+	}
+
+	exists, err := inClient(hash)
+	if err != nil {
+		s.log.Warn().Err(err).Msgf("could not check if torrent %v is already in client: %v", hash, client.Name)
+		return nil, nil
+	}
+
+	if exists {
+		s.log.Debug().Msgf("torrent %v already in client: %v", hash, client.Name)
+		return []string{fmt.Sprintf("torrent already in client %v: %v", client.Name, hash)}, nil
+	}
+
+	return nil, nil
+}
+
+// checkTorrentBlocklist returns the rejection of the first blocklist entry matching the release
+func (s *service) checkTorrentBlocklist(ctx context.Context, release *domain.Release) ([]string, error) {
+	if s.blocklistRepo == nil {
+		return nil, nil
+	}
+
+	entries, err := s.blocklistRepo.List(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not get torrent blocklist")
+	}
+
+	for _, entry := range entries {
+		if entry.Matches(release) {
+			s.log.Debug().Msgf("release %v rejected by blocklist entry: %v", release.TorrentName, entry.ID)
+			return []string{entry.Rejection()}, nil
+		}
+	}
+
+	return nil, nil
+}
+
+func (s *service) ListBlocklist(ctx context.Context) ([]domain.TorrentBlocklistEntry, error) {
+	return s.blocklistRepo.List(ctx)
+}
+
+func (s *service) StoreBlocklistEntry(ctx context.Context, entry *domain.TorrentBlocklistEntry) error {
+	if err := entry.Validate(); err != nil {
+		return err
+	}
+
+	return s.blocklistRepo.Store(ctx, entry)
+}
+
+func (s *service) DeleteBlocklistEntry(ctx context.Context, id int) error {
+	return s.blocklistRepo.Delete(ctx, id)
+}
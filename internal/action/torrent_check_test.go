@@ -0,0 +1,76 @@
+package action
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/autobrr/autobrr/internal/domain"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockBlocklistRepo struct {
+	entries []domain.TorrentBlocklistEntry
+}
+
+func (m *mockBlocklistRepo) List(ctx context.Context) ([]domain.TorrentBlocklistEntry, error) {
+	return m.entries, nil
+}
+
+func (m *mockBlocklistRepo) Store(ctx context.Context, entry *domain.TorrentBlocklistEntry) error {
+	m.entries = append(m.entries, *entry)
+	return nil
+}
+
+func (m *mockBlocklistRepo) Delete(ctx context.Context, id int) error {
+	return nil
+}
+
+func Test_service_checkTorrent(t *testing.T) {
+	const hash = "0123456789abcdef0123456789abcdef01234567"
+
+	client := &domain.DownloadClient{Name: "qbit"}
+	release := &domain.Release{TorrentName: "Show.S01E01.1080p.WEB-DL-GRP", Group: "GRP", InfoHashV1: hash}
+
+	inClient := func(exists bool, err error) torrentInClientFunc {
+		return func(h string) (bool, error) {
+			assert.Equal(t, hash, h)
+			return exists, err
+		}
+	}
+
+	tests := []struct {
+		name      string
+		blocklist []domain.TorrentBlocklistEntry
+		inClient  torrentInClientFunc
+		want      []string
+	}{
+		{name: "accepted", inClient: inClient(false, nil), want: nil},
+		{name: "in_client", inClient: inClient(true, nil), want: []string{"torrent already in client qbit: " + hash}},
+		{name: "lookup_error", inClient: inClient(false, errors.New("timeout")), want: nil},
+		{name: "no_lookup", inClient: nil, want: nil},
+		{
+			name:      "blocklisted_hash",
+			blocklist: []domain.TorrentBlocklistEntry{{ID: 1, Type: domain.TorrentBlocklistTypeInfoHash, Value: hash, Reason: "fake"}},
+			inClient:  inClient(true, nil),
+			want:      []string{"info hash blocklisted: " + hash + " (fake)"},
+		},
+		{
+			name:      "blocklisted_group",
+			blocklist: []domain.TorrentBlocklistEntry{{ID: 1, Type: domain.TorrentBlocklistTypeGroup, Value: "grp"}},
+			inClient:  inClient(false, nil),
+			want:      []string{"group blocklisted: grp"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &service{log: zerolog.Nop(), blocklistRepo: &mockBlocklistRepo{entries: tt.blocklist}}
+
+			got, err := s.checkTorrent(client, release, tt.inClient)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
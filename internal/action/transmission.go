@@ -2,9 +2,11 @@ package action
 
 import (
 	"context"
+	"strings"
 
 	"github.com/autobrr/autobrr/internal/domain"
 	"github.com/autobrr/autobrr/pkg/errors"
+	"github.com/autobrr/autobrr/pkg/transmission"
 
 	"github.com/hekmon/transmissionrpc/v2"
 )
@@ -43,20 +45,42 @@ func (s *service) transmission(action domain.Action, release domain.Release) ([]
 		return nil, errors.Wrap(err, "error logging into client: %v", client.Host)
 	}
 
+	rejections, err = s.checkTorrent(client, &release, func(hash string) (bool, error) {
+		torrents, err := tbt.TorrentGetHashes(context.TODO(), []string{"hashString"}, []string{hash})
+		return len(torrents) > 0, err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if rejections != nil {
+		return rejections, nil
+	}
+
 	b64, err := transmissionrpc.File2Base64(release.TorrentTmpFile)
 	if err != nil {
 		return nil, errors.Wrap(err, "cant encode file %v into base64", release.TorrentTmpFile)
 	}
 
+	m := domain.NewMacro(release)
+
 	payload := transmissionrpc.TorrentAddPayload{
 		MetaInfo: &b64,
 	}
 	if action.SavePath != "" {
-		payload.DownloadDir = &action.SavePath
+		savePath, err := m.Parse(action.SavePath)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not parse save path macro: %v", action.SavePath)
+		}
+		payload.DownloadDir = &savePath
 	}
 	if action.Paused {
 		payload.Paused = &action.Paused
 	}
+	if action.BandwidthPriority != 0 {
+		priority := int64(action.BandwidthPriority)
+		payload.BandwidthPriority = &priority
+	}
 
 	// Prepare and send payload
 	torrent, err := tbt.TorrentAdd(context.TODO(), payload)
@@ -66,5 +90,46 @@ func (s *service) transmission(action domain.Action, release domain.Release) ([]
 
 	s.log.Info().Msgf("torrent with hash %v successfully added to client: '%v'", torrent.HashString, client.Name)
 
+	// labels, bandwidth groups and sequential download can only be set after the torrent is added
+	args := map[string]interface{}{}
+
+	if action.Label != "" {
+		labelArgs, err := m.Parse(action.Label)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not parse label macro: %v", action.Label)
+		}
+
+		var labels []string
+		for _, label := range strings.Split(labelArgs, ",") {
+			if label = strings.TrimSpace(label); label != "" {
+				labels = append(labels, label)
+			}
+		}
+
+		if len(labels) > 0 {
+			args["labels"] = labels
+		}
+	}
+	if action.BandwidthGroup != "" {
+		args["group"] = action.BandwidthGroup
+	}
+	if action.SequentialDownload {
+		args["sequentialDownload"] = true
+	}
+
+	if len(args) > 0 {
+		tc := transmission.New(transmission.Config{
+			Host:     client.Host,
+			Port:     uint16(client.Port),
+			TLS:      client.TLS,
+			Username: client.Username,
+			Password: client.Password,
+		})
+
+		if err := tc.TorrentSet(context.TODO(), []string{*torrent.HashString}, args); err != nil {
+			s.log.Warn().Err(err).Msgf("could not set torrent options for hash %v on client: '%v'", *torrent.HashString, client.Name)
+		}
+	}
+
 	return rejections, nil
 }
@@ -0,0 +1,46 @@
+package action
+
+import (
+	"sync"
+	"time"
+)
+
+type ttlCacheEntry[V any] struct {
+	value     V
+	fetchedAt time.Time
+}
+
+// ttlCache caches a value per key for ttl, arr library lookups use it so every matching announce
+// doesn't fetch the full library again.
+type ttlCache[K comparable, V any] struct {
+	mu      sync.Mutex
+	ttl     time.Duration
+	entries map[K]ttlCacheEntry[V]
+}
+
+func newTTLCache[K comparable, V any](ttl time.Duration) *ttlCache[K, V] {
+	return &ttlCache[K, V]{
+		ttl:     ttl,
+		entries: map[K]ttlCacheEntry[V]{},
+	}
+}
+
+// get returns the cached value of the key, or fetches and caches it when it is missing or expired.
+// Errors are not cached.
+func (c *ttlCache[K, V]) get(key K, now time.Time, fetch func() (V, error)) (V, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if entry, ok := c.entries[key]; ok && now.Sub(entry.fetchedAt) < c.ttl {
+		return entry.value, nil
+	}
+
+	value, err := fetch()
+	if err != nil {
+		return value, err
+	}
+
+	c.entries[key] = ttlCacheEntry[V]{value: value, fetchedAt: now}
+
+	return value, nil
+}
@@ -0,0 +1,46 @@
+package action
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/autobrr/autobrr/pkg/errors"
+)
+
+func Test_ttlCache_get(t *testing.T) {
+	now := time.Now()
+	cache := newTTLCache[int, string](time.Minute)
+
+	calls := 0
+	var fetchErr error
+	fetch := func() (string, error) {
+		calls++
+		return "value", fetchErr
+	}
+
+	v, err := cache.get(1, now, fetch)
+	assert.NoError(t, err)
+	assert.Equal(t, "value", v)
+
+	_, _ = cache.get(1, now.Add(30*time.Second), fetch)
+	assert.Equal(t, 1, calls)
+
+	// cached per key
+	_, _ = cache.get(2, now, fetch)
+	assert.Equal(t, 2, calls)
+
+	// expired
+	_, _ = cache.get(1, now.Add(2*time.Minute), fetch)
+	assert.Equal(t, 3, calls)
+
+	// errors are not cached
+	fetchErr = errors.New("connection refused")
+	_, err = cache.get(3, now, fetch)
+	assert.Error(t, err)
+	fetchErr = nil
+	_, err = cache.get(3, now, fetch)
+	assert.NoError(t, err)
+	assert.Equal(t, 5, calls)
+}
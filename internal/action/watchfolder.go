@@ -0,0 +1,80 @@
+package action
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/pkg/errors"
+)
+
+// watchFolderDir returns the watch folder of the action. With a client it is the watch folder of the client,
+// the watch folder of the action is then an optional subfolder in it. Both can use macros.
+func watchFolderDir(action domain.Action, client *domain.DownloadClient, release domain.Release) (string, error) {
+	m := domain.NewMacro(release)
+
+	// parse and replace values in argument string before continuing
+	dir, err := m.Parse(action.WatchFolder)
+	if err != nil {
+		return "", errors.Wrap(err, "could not parse watch folder macro: %v", action.WatchFolder)
+	}
+
+	if client != nil {
+		if client.Settings.WatchFolder == "" {
+			return "", errors.New("download client %v has no watch folder", client.Name)
+		}
+
+		clientDir, err := m.Parse(client.Settings.WatchFolder)
+		if err != nil {
+			return "", errors.Wrap(err, "could not parse watch folder macro: %v", client.Settings.WatchFolder)
+		}
+
+		dir = filepath.Join(clientDir, dir)
+	}
+
+	if dir == "" {
+		return "", errors.New("no watch folder for action: %v", action.Name)
+	}
+
+	return dir, nil
+}
+
+// writeFileAtomic writes to a hidden temp file in the same directory and renames it into place,
+// so watchers never see a partially written file
+func writeFileAtomic(fileName string, r io.Reader) error {
+	dir, name := filepath.Split(fileName)
+
+	tmp, err := os.CreateTemp(dir, "."+name+".*.tmp")
+	if err != nil {
+		return errors.Wrap(err, "could not create temp file in %v", dir)
+	}
+
+	// clean up the temp file if anything fails before the rename
+	defer os.Remove(tmp.Name())
+
+	if _, err := io.Copy(tmp, r); err != nil {
+		tmp.Close()
+		return errors.Wrap(err, "could not write temp file %v", tmp.Name())
+	}
+
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return errors.Wrap(err, "could not sync temp file %v", tmp.Name())
+	}
+
+	if err := tmp.Close(); err != nil {
+		return errors.Wrap(err, "could not close temp file %v", tmp.Name())
+	}
+
+	// CreateTemp uses 0600, make the file readable for clients running as another user
+	if err := os.Chmod(tmp.Name(), 0644); err != nil {
+		return errors.Wrap(err, "could not set permissions on %v", tmp.Name())
+	}
+
+	if err := os.Rename(tmp.Name(), fileName); err != nil {
+		return errors.Wrap(err, "could not rename %v to %v", tmp.Name(), fileName)
+	}
+
+	return nil
+}
@@ -0,0 +1,135 @@
+package action
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/internal/logger"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_watchFolderDir(t *testing.T) {
+	qbit := &domain.DownloadClient{Name: "qbit", Settings: domain.DownloadClientSettings{WatchFolder: "/watch/qbit"}}
+	rtorrent := &domain.DownloadClient{Name: "rtorrent", Settings: domain.DownloadClientSettings{WatchFolder: "/watch/rtorrent/{{ .Indexer }}"}}
+
+	tests := []struct {
+		name    string
+		action  domain.Action
+		client  *domain.DownloadClient
+		release domain.Release
+		want    string
+		wantErr bool
+	}{
+		{
+			name:    "action_folder",
+			action:  domain.Action{WatchFolder: "/watch/{{ .FilterName }}"},
+			release: domain.Release{FilterName: "Music"},
+			want:    "/watch/Music",
+		},
+		{
+			name:    "client_folder",
+			action:  domain.Action{},
+			client:  qbit,
+			release: domain.Release{FilterName: "Movies"},
+			want:    "/watch/qbit",
+		},
+		{
+			name:    "client_folder_with_macro",
+			action:  domain.Action{},
+			client:  rtorrent,
+			release: domain.Release{Indexer: "mock"},
+			want:    "/watch/rtorrent/mock",
+		},
+		{
+			name:    "client_subfolder",
+			action:  domain.Action{WatchFolder: "{{ .FilterName }}"},
+			client:  qbit,
+			release: domain.Release{FilterName: "Movies"},
+			want:    "/watch/qbit/Movies",
+		},
+		{
+			name:    "client_without_folder",
+			action:  domain.Action{WatchFolder: "/watch/default"},
+			client:  &domain.DownloadClient{Name: "deluge"},
+			wantErr: true,
+		},
+		{
+			name:    "no_watch_folder",
+			action:  domain.Action{},
+			release: domain.Release{FilterName: "Music"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := watchFolderDir(tt.action, tt.client, tt.release)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_writeFileAtomic(t *testing.T) {
+	dir := t.TempDir()
+	fileName := filepath.Join(dir, "release.torrent")
+
+	assert.NoError(t, os.WriteFile(fileName, []byte("old"), 0644))
+	assert.NoError(t, writeFileAtomic(fileName, strings.NewReader("new torrent data")))
+
+	data, err := os.ReadFile(fileName)
+	assert.NoError(t, err)
+	assert.Equal(t, "new torrent data", string(data))
+
+	// no temp files left behind
+	entries, err := os.ReadDir(dir)
+	assert.NoError(t, err)
+	assert.Len(t, entries, 1)
+}
+
+func Test_writeFileAtomic_missingDir(t *testing.T) {
+	fileName := filepath.Join(t.TempDir(), "missing", "release.torrent")
+
+	assert.Error(t, writeFileAtomic(fileName, strings.NewReader("data")))
+
+	_, err := os.Stat(fileName)
+	assert.True(t, os.IsNotExist(err))
+}
+
+func Test_service_watchFolder(t *testing.T) {
+	dir := t.TempDir()
+
+	tmpFile := filepath.Join(dir, "autobrr-123")
+	assert.NoError(t, os.WriteFile(tmpFile, []byte("torrent"), 0644))
+
+	s := &service{
+		log: logger.Mock().With().Logger(),
+		clientSvc: &mockClientService{clients: map[int32]*domain.DownloadClient{
+			1: {ID: 1, Name: "qbit", Settings: domain.DownloadClientSettings{WatchFolder: filepath.Join(dir, "qbit")}},
+		}},
+	}
+
+	err := s.watchFolder(domain.Action{
+		WatchFolder: "{{ .FilterName }}",
+		ClientID:    1,
+	}, domain.Release{FilterName: "Movies", TorrentTmpFile: tmpFile})
+	assert.NoError(t, err)
+
+	data, err := os.ReadFile(filepath.Join(dir, "qbit", "Movies", "autobrr-123.torrent"))
+	assert.NoError(t, err)
+	assert.Equal(t, "torrent", string(data))
+
+	// unknown clients are an error, not a fallback to the action folder
+	err = s.watchFolder(domain.Action{WatchFolder: filepath.Join(dir, "default"), ClientID: 2}, domain.Release{FilterName: "Movies", TorrentTmpFile: tmpFile})
+	assert.Error(t, err)
+
+	_, err = os.Stat(filepath.Join(dir, "default"))
+	assert.True(t, os.IsNotExist(err))
+}
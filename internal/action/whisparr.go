@@ -51,6 +51,25 @@ func (s *service) whisparr(action domain.Action, release domain.Release) ([]stri
 		PublishDate:      time.Now().Format(time.RFC3339),
 	}
 
+	r.DownloadClientId, r.DownloadClient, err = arrDownloadClient(action, release)
+	if err != nil {
+		return nil, err
+	}
+
+	if release.Filter != nil && release.Filter.ArrSeasonPackThreshold > 0 {
+		if rejections := s.arrSeasonPackRejections(client.ID, arr, "whisparr", release, release.Filter.ArrSeasonPackThreshold); rejections != nil {
+			s.log.Debug().Msgf("whisparr: skipping push of season pack: %v to %v reasons: '%v'", r.Title, client.Host, rejections)
+			return rejections, nil
+		}
+	}
+
+	if release.Filter != nil && release.Filter.ArrSkipDuplicates {
+		if rejections := s.arrDuplicateRejections(arr, "whisparr", r.Title); rejections != nil {
+			s.log.Debug().Msgf("whisparr: skipping push of duplicate release: %v to %v reasons: '%v'", r.Title, client.Host, rejections)
+			return rejections, nil
+		}
+	}
+
 	rejections, err := arr.Push(r)
 	if err != nil {
 		return nil, errors.Wrap(err, "whisparr: failed to push release: %v", r)
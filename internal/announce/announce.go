@@ -5,7 +5,9 @@ import (
 	"net/url"
 	"regexp"
 	"strings"
+	"sync"
 	"text/template"
+	"time"
 
 	"github.com/autobrr/autobrr/internal/domain"
 	"github.com/autobrr/autobrr/internal/release"
@@ -16,10 +18,12 @@ import (
 
 type Processor interface {
 	AddLineToQueue(channel string, line string) error
+	UpdateIndexer(indexer *domain.IndexerDefinition)
 }
 
 type announceProcessor struct {
 	log     zerolog.Logger
+	m       sync.RWMutex
 	indexer *domain.IndexerDefinition
 
 	releaseSvc release.Service
@@ -63,65 +67,132 @@ func (a *announceProcessor) setupQueueConsumers() {
 	}
 }
 
+// UpdateIndexer swaps the definition used to parse announces, eg. when definitions are reloaded
+func (a *announceProcessor) UpdateIndexer(indexer *domain.IndexerDefinition) {
+	a.m.Lock()
+	defer a.m.Unlock()
+
+	a.indexer = indexer
+}
+
+func (a *announceProcessor) definition() *domain.IndexerDefinition {
+	a.m.RLock()
+	defer a.m.RUnlock()
+
+	return a.indexer
+}
+
+// announceState holds a multi line announce while its lines come in
+type announceState struct {
+	indexer *domain.IndexerDefinition
+	vars    map[string]string
+	// index of the next expected line
+	next int
+}
+
+func (st *announceState) reset() {
+	st.indexer = nil
+	st.vars = nil
+	st.next = 0
+}
+
+// processQueue assembles announces line by line. A line that does not continue the current announce drops it
+// and is tried as the start of a new one, and announces missing lines are dropped after the line timeout.
 func (a *announceProcessor) processQueue(queue chan string) {
+	var (
+		state   announceState
+		timeout <-chan time.Time
+	)
+
 	for {
-		tmpVars := map[string]string{}
-		parseFailed := false
-		//patternParsed := false
-
-		for _, pattern := range a.indexer.Parse.Lines {
-			line, err := a.getNextLine(queue)
-			if err != nil {
-				a.log.Error().Stack().Err(err).Msg("could not get line from queue")
+		select {
+		case line, ok := <-queue:
+			if !ok {
+				a.log.Error().Msg("could not get line from queue")
 				return
 			}
+
 			a.log.Trace().Msgf("announce: process line: %v", line)
 
-			// check should ignore
+			if a.processLine(&state, line) {
+				timeout = time.After(state.indexer.Parse.LineTimeoutDuration())
+			} else {
+				timeout = nil
+			}
 
-			match, err := a.parseExtract(pattern.Pattern, pattern.Vars, tmpVars, line)
-			if err != nil {
-				a.log.Debug().Msgf("error parsing extract: %v", line)
+		case <-timeout:
+			a.log.Debug().Msgf("announce: dropped incomplete announce, got %d of %d lines", state.next, len(state.indexer.Parse.Lines))
+			state.reset()
+			timeout = nil
+		}
+	}
+}
 
-				parseFailed = true
-				break
-			}
+// processLine adds the line to the announce in progress and returns true while more lines are expected
+func (a *announceProcessor) processLine(state *announceState, line string) bool {
+	if state.next > 0 {
+		lines := state.indexer.Parse.Lines
 
-			if !match {
-				a.log.Debug().Msgf("line not matching expected regex pattern: %v", line)
-				parseFailed = true
-				break
+		match, err := a.parseExtract(lines[state.next].Pattern, lines[state.next].Vars, state.vars, line)
+		if err == nil && match {
+			state.next++
+			if state.next < len(lines) {
+				return true
 			}
-		}
 
-		if parseFailed {
-			a.log.Trace().Msg("announce: parse failed")
-			continue
+			a.release(state.indexer, state.vars)
+			state.reset()
+			return false
 		}
 
-		rls := domain.NewRelease(a.indexer.Identifier)
+		a.log.Debug().Msgf("announce: dropped incomplete announce, line %d not matching: %v", state.next+1, line)
+		state.reset()
+	}
 
-		// on lines matched
-		err := a.onLinesMatched(a.indexer, tmpVars, rls)
-		if err != nil {
-			a.log.Debug().Msgf("error match line: %v", "")
-			continue
-		}
+	// keep the same definition for all lines of an announce
+	indexer := a.definition()
+	if indexer.Parse == nil || len(indexer.Parse.Lines) == 0 {
+		return false
+	}
+
+	vars := map[string]string{}
+
+	match, err := a.parseExtract(indexer.Parse.Lines[0].Pattern, indexer.Parse.Lines[0].Vars, vars, line)
+	if err != nil {
+		a.log.Debug().Msgf("error parsing extract: %v", line)
+		return false
+	}
+
+	if !match {
+		a.log.Debug().Msgf("line not matching expected regex pattern: %v", line)
+		return false
+	}
 
-		// process release in a new go routine
-		go a.releaseSvc.Process(rls)
+	if len(indexer.Parse.Lines) == 1 {
+		a.release(indexer, vars)
+		return false
 	}
+
+	state.indexer = indexer
+	state.vars = vars
+	state.next = 1
+
+	return true
 }
 
-func (a *announceProcessor) getNextLine(queue chan string) (string, error) {
-	for {
-		line, ok := <-queue
-		if !ok {
-			return "", errors.New("could not queue line")
-		}
+func (a *announceProcessor) release(indexer *domain.IndexerDefinition, vars map[string]string) {
+	rls := domain.NewRelease(indexer.Identifier)
+	rls.Proxy = indexer.Proxy
 
-		return line, nil
+	// on lines matched
+	if err := a.onLinesMatched(indexer, vars, rls); err != nil {
+		a.log.Debug().Msgf("error match line: %v", "")
+		return
 	}
+
+	// process release on a worker of the indexer, announces don't wait when the queue is full to not hold up the irc connection.
+	// Dropped releases are logged by the release service.
+	_ = a.releaseSvc.Enqueue(rls)
 }
 
 func (a *announceProcessor) AddLineToQueue(channel string, line string) error {
@@ -131,7 +202,7 @@ func (a *announceProcessor) AddLineToQueue(channel string, line string) error {
 		return errors.New("no queue for channel (%v) found", channel)
 	}
 
-	queue <- line
+	queue <- domain.StripIRCFormatting(line)
 	a.log.Trace().Msgf("announce: queued line: %v", line)
 
 	return nil
@@ -182,6 +253,13 @@ func (a *announceProcessor) onLinesMatched(def *domain.IndexerDefinition, vars m
 		return err
 	}
 
+	// a broken token setup must not lose the release, it is only grabbed without a token
+	if def.FreeleechToken != nil {
+		if err := def.FreeleechToken.Apply(vars, def.SettingsMap, rls); err != nil {
+			a.log.Warn().Err(err).Msgf("announce: could not set up freeleech token for indexer: %v", def.Identifier)
+		}
+	}
+
 	return nil
 }
 
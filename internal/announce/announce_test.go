@@ -2,7 +2,12 @@ package announce
 
 import (
 	"testing"
+	"time"
 
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/internal/release"
+
+	"github.com/rs/zerolog"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -66,3 +71,130 @@ func Test_announceProcessor_processTorrentUrl(t *testing.T) {
 		})
 	}
 }
+
+func Test_announceProcessor_UpdateIndexer(t *testing.T) {
+	a := &announceProcessor{
+		indexer: &domain.IndexerDefinition{Identifier: "mock", Name: "Mock"},
+	}
+
+	a.UpdateIndexer(&domain.IndexerDefinition{Identifier: "mock", Name: "Mock Custom"})
+
+	assert.Equal(t, "Mock Custom", a.definition().Name)
+}
+
+type mockReleaseService struct {
+	release.Service
+	released chan *domain.Release
+}
+
+func (m *mockReleaseService) Enqueue(rls *domain.Release) error {
+	m.released <- rls
+	return nil
+}
+
+func multiLineDefinition(lineTimeout int) *domain.IndexerDefinition {
+	return &domain.IndexerDefinition{
+		Identifier: "mock",
+		IRC:        &domain.IndexerIRC{Channels: []string{"#announce"}},
+		Parse: &domain.IndexerParse{
+			Type:        "multi",
+			LineTimeout: lineTimeout,
+			Lines: []domain.IndexerParseExtract{
+				{Pattern: `^New: (.+) - https://mock\.test/t/(\d+)$`, Vars: []string{"torrentName", "torrentId"}},
+				{Pattern: `^Size: (\S+) Tags: (.*)$`, Vars: []string{"torrentSize", "tags"}},
+			},
+			Match: domain.IndexerParseMatch{
+				TorrentURL: "https://mock.test/dl/{{ .torrentId }}",
+			},
+		},
+	}
+}
+
+func Test_announceProcessor_processLine(t *testing.T) {
+	tests := []struct {
+		name  string
+		lines []string
+		want  []string
+	}{
+		{
+			name:  "complete",
+			lines: []string{"New: That.Show.S01E01.1080p.WEB-DL-GROUP - https://mock.test/t/1", "Size: 1.5GiB Tags: tv"},
+			want:  []string{"That.Show.S01E01.1080p.WEB-DL-GROUP"},
+		},
+		{
+			name: "missing_second_line_restarts",
+			lines: []string{
+				"New: First.Release-GROUP - https://mock.test/t/1",
+				"New: Second.Release-GROUP - https://mock.test/t/2",
+				"Size: 700MiB Tags: tv",
+			},
+			want: []string{"Second.Release-GROUP"},
+		},
+		{
+			name:  "continuation_without_start",
+			lines: []string{"Size: 700MiB Tags: tv", "New: That.Release-GROUP - https://mock.test/t/1", "Size: 700MiB Tags: tv"},
+			want:  []string{"That.Release-GROUP"},
+		},
+		{
+			name:  "unrelated_line_drops_announce",
+			lines: []string{"New: That.Release-GROUP - https://mock.test/t/1", "Welcome to #announce", "Size: 700MiB Tags: tv"},
+			want:  nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			releaseSvc := &mockReleaseService{released: make(chan *domain.Release, 10)}
+			a := &announceProcessor{indexer: multiLineDefinition(0), releaseSvc: releaseSvc}
+
+			var state announceState
+			for _, line := range tt.lines {
+				a.processLine(&state, line)
+			}
+
+			var got []string
+			for len(got) < len(tt.want) {
+				select {
+				case rls := <-releaseSvc.released:
+					got = append(got, rls.TorrentName)
+				case <-time.After(time.Second):
+					t.Fatalf("expected %d releases, got %v", len(tt.want), got)
+				}
+			}
+
+			select {
+			case rls := <-releaseSvc.released:
+				t.Fatalf("unexpected release: %v", rls.TorrentName)
+			case <-time.After(50 * time.Millisecond):
+			}
+
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_announceProcessor_lineTimeout(t *testing.T) {
+	releaseSvc := &mockReleaseService{released: make(chan *domain.Release, 10)}
+	a := NewAnnounceProcessor(zerolog.Nop(), releaseSvc, multiLineDefinition(1))
+
+	assert.NoError(t, a.AddLineToQueue("#announce", "New: Late.Release-GROUP - https://mock.test/t/1"))
+	time.Sleep(1200 * time.Millisecond)
+	assert.NoError(t, a.AddLineToQueue("#announce", "Size: 700MiB Tags: tv"))
+
+	// the partial announce expired so the late second line is not used
+	select {
+	case rls := <-releaseSvc.released:
+		t.Fatalf("unexpected release: %v", rls.TorrentName)
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	assert.NoError(t, a.AddLineToQueue("#announce", "\x02New:\x02 \x0304Colored.Release-GROUP\x03 - https://mock.test/t/2"))
+	assert.NoError(t, a.AddLineToQueue("#announce", "Size: 700MiB Tags: tv"))
+
+	select {
+	case rls := <-releaseSvc.released:
+		assert.Equal(t, "Colored.Release-GROUP", rls.TorrentName)
+		assert.Equal(t, "https://mock.test/dl/2", rls.TorrentURL)
+	case <-time.After(time.Second):
+		t.Fatal("expected release")
+	}
+}
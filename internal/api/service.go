@@ -4,7 +4,10 @@ import (
 	"context"
 	"crypto/rand"
 	"encoding/hex"
+	"sync"
+	"time"
 
+	"github.com/autobrr/autobrr/internal/audit"
 	"github.com/autobrr/autobrr/internal/domain"
 	"github.com/autobrr/autobrr/internal/logger"
 
@@ -17,42 +20,62 @@ type Service interface {
 	Update(ctx context.Context, key *domain.APIKey) error
 	Delete(ctx context.Context, key string) error
 	ValidateAPIKey(ctx context.Context, token string) bool
+	FindByKey(ctx context.Context, token string) (*domain.APIKey, error)
 }
 
 type service struct {
-	log  zerolog.Logger
-	repo domain.APIRepo
+	log      zerolog.Logger
+	repo     domain.APIRepo
+	auditSvc audit.Service
 
-	keyCache []domain.APIKey
+	// keys are checked on every api request, they are loaded once and reloaded after a change
+	cacheMu  sync.RWMutex
+	keyCache map[string]domain.APIKey
 }
 
-func NewService(log logger.Logger, repo domain.APIRepo) Service {
+func NewService(log logger.Logger, repo domain.APIRepo, auditSvc audit.Service) Service {
 	return &service{
 		log:      log.With().Str("module", "api").Logger(),
 		repo:     repo,
-		keyCache: []domain.APIKey{},
+		auditSvc: auditSvc,
 	}
 }
 
+// List returns the keys without their values
 func (s *service) List(ctx context.Context) ([]domain.APIKey, error) {
-	if len(s.keyCache) > 0 {
-		return s.keyCache, nil
+	keys, err := s.repo.GetKeys(ctx)
+	if err != nil {
+		return nil, err
 	}
 
-	return s.repo.GetKeys(ctx)
+	for i := range keys {
+		keys[i] = keys[i].Redacted()
+	}
+
+	return keys, nil
 }
 
+// Store creates the key, the returned key is the only time its value is shown
 func (s *service) Store(ctx context.Context, key *domain.APIKey) error {
+	if err := key.Validate(); err != nil {
+		return err
+	}
+
+	if key.Scopes == nil {
+		key.Scopes = []string{}
+	}
+
 	key.Key = GenerateSecureToken(16)
 
 	if err := s.repo.Store(ctx, key); err != nil {
 		return err
 	}
 
-	if len(s.keyCache) > 0 {
-		// set new key
-		s.keyCache = append(s.keyCache, *key)
-	}
+	key.ID = domain.APIKeyID(key.Key)
+
+	s.resetCache()
+
+	s.auditSvc.Record(ctx, domain.AuditEntityAPIKey, key.Name, key.Name, nil, key.Redacted())
 
 	return nil
 }
@@ -61,25 +84,97 @@ func (s *service) Update(ctx context.Context, key *domain.APIKey) error {
 	return nil
 }
 
-func (s *service) Delete(ctx context.Context, key string) error {
-	// reset
-	s.keyCache = []domain.APIKey{}
+// Delete removes the key by its id, the key value itself is accepted as well
+func (s *service) Delete(ctx context.Context, id string) error {
+	keys, err := s.keys(ctx)
+	if err != nil {
+		return err
+	}
+
+	var before *domain.APIKey
+	for _, k := range keys {
+		if k.Key == id || domain.APIKeyID(k.Key) == id {
+			k := k
+			before = &k
+			break
+		}
+	}
+
+	if before == nil {
+		return nil
+	}
+
+	if err := s.repo.Delete(ctx, before.Key); err != nil {
+		return err
+	}
 
-	return s.repo.Delete(ctx, key)
+	s.resetCache()
+
+	s.auditSvc.Record(ctx, domain.AuditEntityAPIKey, before.Name, before.Name, before.Redacted(), nil)
+
+	return nil
 }
 
 func (s *service) ValidateAPIKey(ctx context.Context, key string) bool {
-	keys, err := s.repo.GetKeys(ctx)
-	if err != nil {
+	k, err := s.FindByKey(ctx, key)
+	if err != nil || k == nil {
 		return false
 	}
 
-	for _, k := range keys {
-		if k.Key == key {
-			return true
-		}
+	return !k.Expired(time.Now())
+}
+
+// FindByKey returns the api key or nil if it does not exist
+func (s *service) FindByKey(ctx context.Context, key string) (*domain.APIKey, error) {
+	keys, err := s.keys(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if k, ok := keys[key]; ok {
+		return &k, nil
+	}
+
+	return nil, nil
+}
+
+// keys returns the cached keys by value and loads them when the cache is empty
+func (s *service) keys(ctx context.Context) (map[string]domain.APIKey, error) {
+	s.cacheMu.RLock()
+	keys := s.keyCache
+	s.cacheMu.RUnlock()
+
+	if keys != nil {
+		return keys, nil
 	}
-	return false
+
+	// load under the write lock so a reset during the load is not overwritten with stale keys
+	s.cacheMu.Lock()
+	defer s.cacheMu.Unlock()
+
+	if s.keyCache != nil {
+		return s.keyCache, nil
+	}
+
+	stored, err := s.repo.GetKeys(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	keys = make(map[string]domain.APIKey, len(stored))
+	for _, k := range stored {
+		keys[k.Key] = k
+	}
+
+	s.keyCache = keys
+
+	return keys, nil
+}
+
+func (s *service) resetCache() {
+	s.cacheMu.Lock()
+	s.keyCache = nil
+	s.cacheMu.Unlock()
 }
 
 func GenerateSecureToken(length int) string {
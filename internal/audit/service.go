@@ -0,0 +1,87 @@
+package audit
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/internal/logger"
+
+	"github.com/rs/zerolog"
+)
+
+type Service interface {
+	Record(ctx context.Context, entityType domain.AuditEntityType, entityID interface{}, entityName string, before, after interface{})
+	Find(ctx context.Context, params domain.AuditQueryParams) ([]domain.AuditEntry, error)
+}
+
+type service struct {
+	log  zerolog.Logger
+	repo domain.AuditRepo
+}
+
+func NewService(log logger.Logger, repo domain.AuditRepo) Service {
+	return &service{
+		log:  log.With().Str("module", "audit").Logger(),
+		repo: repo,
+	}
+}
+
+// Record stores a change of an entity by the actor of ctx. Before is nil for created and after is nil for deleted entities.
+// Updates without changed fields are not stored. Errors are only logged, a failed audit never fails the change itself.
+func (s *service) Record(ctx context.Context, entityType domain.AuditEntityType, entityID interface{}, entityName string, before, after interface{}) {
+	action := domain.AuditActionUpdate
+	switch {
+	case isNil(before):
+		action = domain.AuditActionCreate
+	case isNil(after):
+		action = domain.AuditActionDelete
+	}
+
+	changes, err := domain.AuditDiff(before, after)
+	if err != nil {
+		s.log.Error().Err(err).Msgf("could not diff %v %v", entityType, entityID)
+		return
+	}
+
+	if action == domain.AuditActionUpdate && len(changes) == 0 {
+		return
+	}
+
+	entry := &domain.AuditEntry{
+		Actor:      domain.AuditActor(ctx),
+		Action:     action,
+		EntityType: entityType,
+		EntityID:   fmt.Sprint(entityID),
+		EntityName: entityName,
+		Changes:    changes,
+	}
+
+	// the change is done, store the entry even if the request was cancelled
+	if err := s.repo.Store(context.Background(), entry); err != nil {
+		s.log.Error().Err(err).Msgf("could not store audit entry for %v %v", entityType, entityID)
+		return
+	}
+
+	s.log.Debug().Msgf("audit: %v %v %v (%v) by %v: %d changes", action, entityType, entityID, entityName, entry.Actor, len(changes))
+}
+
+func (s *service) Find(ctx context.Context, params domain.AuditQueryParams) ([]domain.AuditEntry, error) {
+	if params.Limit == 0 {
+		params.Limit = 100
+	} else if params.Limit > 1000 {
+		params.Limit = 1000
+	}
+
+	return s.repo.Find(ctx, params)
+}
+
+func isNil(v interface{}) bool {
+	if v == nil {
+		return true
+	}
+
+	value := reflect.ValueOf(v)
+	return value.Kind() == reflect.Ptr && value.IsNil()
+}
@@ -0,0 +1,432 @@
+package auth
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"math/big"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/autobrr/autobrr/pkg/errors"
+)
+
+// oidcClockSkew is the leeway allowed when checking token expiry
+const oidcClockSkew = time.Minute
+
+type oidcDiscovery struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JwksURI               string `json:"jwks_uri"`
+}
+
+type oidcJWK struct {
+	Kid string `json:"kid"`
+	Kty string `json:"kty"`
+	Crv string `json:"crv"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+// oidcProvider implements the authorization code flow with PKCE against an OpenID Connect issuer.
+// Discovery and signing keys are fetched on first use so a provider that is down does not block startup.
+//
+// The id token is verified here rather than with go-oidc, which would add go-oidc, go-jose and oauth2 for a single
+// login flow. The checks follow the go-oidc verifier: the token must be signed with RS256/384/512 or ES256/384/512 by a
+// key from the issuer key set, the algorithm must match the key type so "none" and HMAC tokens signed with a public key
+// are rejected, and iss, aud, exp, nbf and nonce are checked. Test_oidcProvider_Exchange covers each rejection.
+type oidcProvider struct {
+	issuer       string
+	clientID     string
+	clientSecret string
+	redirectURL  string
+
+	httpClient *http.Client
+
+	mu        sync.Mutex
+	discovery *oidcDiscovery
+	keys      map[string]crypto.PublicKey
+
+	now func() time.Time
+}
+
+func newOIDCProvider(issuer, clientID, clientSecret, redirectURL string) *oidcProvider {
+	return &oidcProvider{
+		issuer:       strings.TrimSuffix(issuer, "/"),
+		clientID:     clientID,
+		clientSecret: clientSecret,
+		redirectURL:  redirectURL,
+		httpClient:   &http.Client{Timeout: 30 * time.Second},
+		keys:         map[string]crypto.PublicKey{},
+		now:          time.Now,
+	}
+}
+
+func (p *oidcProvider) getDiscovery(ctx context.Context) (*oidcDiscovery, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.discovery != nil {
+		return p.discovery, nil
+	}
+
+	var discovery oidcDiscovery
+	if err := p.getJSON(ctx, p.issuer+"/.well-known/openid-configuration", &discovery); err != nil {
+		return nil, errors.Wrap(err, "could not fetch openid configuration")
+	}
+
+	// some providers like authentik publish the issuer with a trailing slash
+	if strings.TrimSuffix(discovery.Issuer, "/") != p.issuer {
+		return nil, errors.New("issuer mismatch: got %v want %v", discovery.Issuer, p.issuer)
+	}
+
+	p.discovery = &discovery
+
+	return p.discovery, nil
+}
+
+// AuthCodeURL returns the url to redirect the user to for login
+func (p *oidcProvider) AuthCodeURL(ctx context.Context, state, nonce, verifier string) (string, error) {
+	discovery, err := p.getDiscovery(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	u, err := url.Parse(discovery.AuthorizationEndpoint)
+	if err != nil {
+		return "", errors.Wrap(err, "could not parse authorization endpoint")
+	}
+
+	q := u.Query()
+	q.Set("response_type", "code")
+	q.Set("client_id", p.clientID)
+	q.Set("redirect_uri", p.redirectURL)
+	q.Set("scope", "openid profile email")
+	q.Set("state", state)
+	q.Set("nonce", nonce)
+	q.Set("code_challenge", pkceChallenge(verifier))
+	q.Set("code_challenge_method", "S256")
+	u.RawQuery = q.Encode()
+
+	return u.String(), nil
+}
+
+// Exchange trades the authorization code for tokens and returns the verified id token claims
+func (p *oidcProvider) Exchange(ctx context.Context, code, verifier, nonce string) (map[string]interface{}, error) {
+	discovery, err := p.getDiscovery(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	form := url.Values{}
+	form.Set("grant_type", "authorization_code")
+	form.Set("code", code)
+	form.Set("redirect_uri", p.redirectURL)
+	form.Set("code_verifier", verifier)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, discovery.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, errors.Wrap(err, "could not build token request")
+	}
+
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	req.SetBasicAuth(url.QueryEscape(p.clientID), url.QueryEscape(p.clientSecret))
+
+	res, err := p.httpClient.Do(req)
+	if err != nil {
+		return nil, errors.Wrap(err, "token request failed")
+	}
+	defer res.Body.Close()
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not read token response")
+	}
+
+	if res.StatusCode != http.StatusOK {
+		return nil, errors.New("token request unexpected status: %v body: %s", res.StatusCode, body)
+	}
+
+	var token struct {
+		IDToken string `json:"id_token"`
+	}
+	if err := json.Unmarshal(body, &token); err != nil {
+		return nil, errors.Wrap(err, "could not unmarshal token response")
+	}
+
+	if token.IDToken == "" {
+		return nil, errors.New("token response is missing id_token")
+	}
+
+	return p.verify(ctx, token.IDToken, nonce)
+}
+
+// verify checks the id token signature, issuer, audience, expiry, not before and nonce
+func (p *oidcProvider) verify(ctx context.Context, idToken, nonce string) (map[string]interface{}, error) {
+	parts := strings.Split(idToken, ".")
+	if len(parts) != 3 {
+		return nil, errors.New("malformed id token")
+	}
+
+	var header struct {
+		Alg string `json:"alg"`
+		Kid string `json:"kid"`
+	}
+	if err := decodeSegment(parts[0], &header); err != nil {
+		return nil, errors.Wrap(err, "could not decode id token header")
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, errors.Wrap(err, "could not decode id token signature")
+	}
+
+	key, err := p.getKey(ctx, header.Kid)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := verifySignature(header.Alg, key, []byte(parts[0]+"."+parts[1]), signature); err != nil {
+		return nil, err
+	}
+
+	claims := map[string]interface{}{}
+	if err := decodeSegment(parts[1], &claims); err != nil {
+		return nil, errors.Wrap(err, "could not decode id token claims")
+	}
+
+	discovery, err := p.getDiscovery(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if iss, _ := claims["iss"].(string); iss != discovery.Issuer {
+		return nil, errors.New("id token issuer mismatch: %v", iss)
+	}
+
+	if !audienceContains(claims["aud"], p.clientID) {
+		return nil, errors.New("id token audience does not contain client id")
+	}
+
+	exp, ok := claims["exp"].(float64)
+	if !ok || p.now().After(time.Unix(int64(exp), 0).Add(oidcClockSkew)) {
+		return nil, errors.New("id token expired")
+	}
+
+	if nbf, ok := claims["nbf"].(float64); ok && p.now().Add(oidcClockSkew).Before(time.Unix(int64(nbf), 0)) {
+		return nil, errors.New("id token not valid yet")
+	}
+
+	if n, _ := claims["nonce"].(string); n != nonce {
+		return nil, errors.New("id token nonce mismatch")
+	}
+
+	return claims, nil
+}
+
+// getKey returns the signing key by id, the key set is fetched again once when the id is unknown to handle key rotation
+func (p *oidcProvider) getKey(ctx context.Context, kid string) (crypto.PublicKey, error) {
+	p.mu.Lock()
+	key, ok := p.keys[kid]
+	p.mu.Unlock()
+
+	if ok {
+		return key, nil
+	}
+
+	discovery, err := p.getDiscovery(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var jwks struct {
+		Keys []oidcJWK `json:"keys"`
+	}
+	if err := p.getJSON(ctx, discovery.JwksURI, &jwks); err != nil {
+		return nil, errors.Wrap(err, "could not fetch signing keys")
+	}
+
+	keys := map[string]crypto.PublicKey{}
+	for _, k := range jwks.Keys {
+		pub, err := k.publicKey()
+		if err != nil {
+			continue
+		}
+		keys[k.Kid] = pub
+	}
+
+	p.mu.Lock()
+	p.keys = keys
+	p.mu.Unlock()
+
+	key, ok = keys[kid]
+	if !ok {
+		return nil, errors.New("unknown signing key: %v", kid)
+	}
+
+	return key, nil
+}
+
+func (p *oidcProvider) getJSON(ctx context.Context, endpoint string, v interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return errors.Wrap(err, "could not build request: %v", endpoint)
+	}
+
+	req.Header.Set("Accept", "application/json")
+
+	res, err := p.httpClient.Do(req)
+	if err != nil {
+		return errors.Wrap(err, "request failed: %v", endpoint)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return errors.New("unexpected status: %v (%v)", res.StatusCode, endpoint)
+	}
+
+	if err := json.NewDecoder(res.Body).Decode(v); err != nil {
+		return errors.Wrap(err, "could not decode response: %v", endpoint)
+	}
+
+	return nil
+}
+
+func (k oidcJWK) publicKey() (crypto.PublicKey, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := decodeBigInt(k.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := decodeBigInt(k.E)
+		if err != nil {
+			return nil, err
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+
+	case "EC":
+		var curve elliptic.Curve
+		switch k.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, errors.New("unsupported curve: %v", k.Crv)
+		}
+
+		x, err := decodeBigInt(k.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := decodeBigInt(k.Y)
+		if err != nil {
+			return nil, err
+		}
+		return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+
+	default:
+		return nil, errors.New("unsupported key type: %v", k.Kty)
+	}
+}
+
+func verifySignature(alg string, key crypto.PublicKey, signed []byte, signature []byte) error {
+	var hash crypto.Hash
+	switch alg {
+	case "RS256", "ES256":
+		hash = crypto.SHA256
+	case "RS384", "ES384":
+		hash = crypto.SHA384
+	case "RS512", "ES512":
+		hash = crypto.SHA512
+	default:
+		return errors.New("unsupported signing algorithm: %v", alg)
+	}
+
+	h := hash.New()
+	h.Write(signed)
+	digest := h.Sum(nil)
+
+	switch pub := key.(type) {
+	case *rsa.PublicKey:
+		if !strings.HasPrefix(alg, "RS") {
+			return errors.New("signing algorithm %v does not match rsa key", alg)
+		}
+		if err := rsa.VerifyPKCS1v15(pub, hash, digest, signature); err != nil {
+			return errors.Wrap(err, "invalid id token signature")
+		}
+
+	case *ecdsa.PublicKey:
+		if !strings.HasPrefix(alg, "ES") {
+			return errors.New("signing algorithm %v does not match ec key", alg)
+		}
+		size := (pub.Curve.Params().BitSize + 7) / 8
+		if len(signature) != 2*size {
+			return errors.New("invalid id token signature")
+		}
+		r := new(big.Int).SetBytes(signature[:size])
+		s := new(big.Int).SetBytes(signature[size:])
+		if !ecdsa.Verify(pub, digest, r, s) {
+			return errors.New("invalid id token signature")
+		}
+
+	default:
+		return errors.New("unsupported signing key")
+	}
+
+	return nil
+}
+
+func audienceContains(aud interface{}, clientID string) bool {
+	switch v := aud.(type) {
+	case string:
+		return v == clientID
+	case []interface{}:
+		for _, a := range v {
+			if s, ok := a.(string); ok && s == clientID {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func decodeSegment(segment string, v interface{}) error {
+	data, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(data, v)
+}
+
+func decodeBigInt(s string) (*big.Int, error) {
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not decode key")
+	}
+
+	return new(big.Int).SetBytes(data), nil
+}
+
+func pkceChallenge(verifier string) string {
+	sum := sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
@@ -0,0 +1,304 @@
+package auth
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type mockIssuer struct {
+	server *httptest.Server
+	key    *rsa.PrivateKey
+	kid    string
+
+	idToken string
+}
+
+func newMockIssuer(t *testing.T) *mockIssuer {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	m := &mockIssuer{key: key, kid: "key-1"}
+
+	mux := http.NewServeMux()
+	m.server = httptest.NewServer(mux)
+	t.Cleanup(m.server.Close)
+
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(oidcDiscovery{
+			Issuer:                m.server.URL,
+			AuthorizationEndpoint: m.server.URL + "/authorize",
+			TokenEndpoint:         m.server.URL + "/token",
+			JwksURI:               m.server.URL + "/jwks",
+		})
+	})
+	mux.HandleFunc("/jwks", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"keys": []oidcJWK{{
+				Kid: m.kid,
+				Kty: "RSA",
+				N:   base64.RawURLEncoding.EncodeToString(m.key.N.Bytes()),
+				E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(m.key.E)).Bytes()),
+			}},
+		})
+	})
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		clientID, clientSecret, _ := r.BasicAuth()
+		if clientID != "autobrr" || clientSecret != "secret" || r.FormValue("code") != "mock-code" || r.FormValue("code_verifier") != "mock-verifier" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]string{"id_token": m.idToken})
+	})
+
+	return m
+}
+
+func (m *mockIssuer) sign(t *testing.T, kid string, claims map[string]interface{}) string {
+	return signToken(t, m.key, map[string]string{"alg": "RS256", "kid": kid, "typ": "JWT"}, claims)
+}
+
+// signToken signs with RS256 whatever alg the header claims, or not at all for alg none
+func signToken(t *testing.T, key *rsa.PrivateKey, header map[string]string, claims map[string]interface{}) string {
+	h, _ := json.Marshal(header)
+	payload, _ := json.Marshal(claims)
+
+	signed := base64.RawURLEncoding.EncodeToString(h) + "." + base64.RawURLEncoding.EncodeToString(payload)
+	if header["alg"] == "none" {
+		return signed + "."
+	}
+
+	digest := sha256.Sum256([]byte(signed))
+
+	signature, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return signed + "." + base64.RawURLEncoding.EncodeToString(signature)
+}
+
+func Test_oidcProvider_Exchange(t *testing.T) {
+	issuer := newMockIssuer(t)
+	now := time.Now()
+
+	otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	claims := func(modify func(c map[string]interface{})) map[string]interface{} {
+		c := map[string]interface{}{
+			"iss":                issuer.server.URL,
+			"aud":                "autobrr",
+			"exp":                now.Add(5 * time.Minute).Unix(),
+			"nonce":              "mock-nonce",
+			"preferred_username": "admin",
+		}
+		if modify != nil {
+			modify(c)
+		}
+		return c
+	}
+
+	tests := []struct {
+		name    string
+		idToken func() string
+		wantErr string
+	}{
+		{
+			name:    "valid",
+			idToken: func() string { return issuer.sign(t, "key-1", claims(nil)) },
+		},
+		{
+			name: "audience_list",
+			idToken: func() string {
+				return issuer.sign(t, "key-1", claims(func(c map[string]interface{}) { c["aud"] = []string{"other", "autobrr"} }))
+			},
+		},
+		{
+			name: "wrong_audience",
+			idToken: func() string {
+				return issuer.sign(t, "key-1", claims(func(c map[string]interface{}) { c["aud"] = "other" }))
+			},
+			wantErr: "id token audience does not contain client id",
+		},
+		{
+			name: "wrong_issuer",
+			idToken: func() string {
+				return issuer.sign(t, "key-1", claims(func(c map[string]interface{}) { c["iss"] = "https://evil.example.com" }))
+			},
+			wantErr: "id token issuer mismatch: https://evil.example.com",
+		},
+		{
+			name: "expired",
+			idToken: func() string {
+				return issuer.sign(t, "key-1", claims(func(c map[string]interface{}) { c["exp"] = now.Add(-5 * time.Minute).Unix() }))
+			},
+			wantErr: "id token expired",
+		},
+		{
+			name: "wrong_nonce",
+			idToken: func() string {
+				return issuer.sign(t, "key-1", claims(func(c map[string]interface{}) { c["nonce"] = "replayed" }))
+			},
+			wantErr: "id token nonce mismatch",
+		},
+		{
+			name: "expired_within_clock_skew",
+			idToken: func() string {
+				return issuer.sign(t, "key-1", claims(func(c map[string]interface{}) { c["exp"] = now.Add(-30 * time.Second).Unix() }))
+			},
+		},
+		{
+			name: "missing_expiry",
+			idToken: func() string {
+				return issuer.sign(t, "key-1", claims(func(c map[string]interface{}) { delete(c, "exp") }))
+			},
+			wantErr: "id token expired",
+		},
+		{
+			name: "not_valid_yet",
+			idToken: func() string {
+				return issuer.sign(t, "key-1", claims(func(c map[string]interface{}) { c["nbf"] = now.Add(5 * time.Minute).Unix() }))
+			},
+			wantErr: "id token not valid yet",
+		},
+		{
+			name: "missing_nonce",
+			idToken: func() string {
+				return issuer.sign(t, "key-1", claims(func(c map[string]interface{}) { delete(c, "nonce") }))
+			},
+			wantErr: "id token nonce mismatch",
+		},
+		{
+			name:    "unknown_key",
+			idToken: func() string { return issuer.sign(t, "key-2", claims(nil)) },
+			wantErr: "unknown signing key: key-2",
+		},
+		{
+			name: "tampered",
+			idToken: func() string {
+				token := issuer.sign(t, "key-1", claims(nil))
+				forged, _ := json.Marshal(claims(func(c map[string]interface{}) { c["preferred_username"] = "someone-else" }))
+				parts := strings.Split(token, ".")
+				return parts[0] + "." + base64.RawURLEncoding.EncodeToString(forged) + "." + parts[2]
+			},
+			wantErr: "invalid id token signature: crypto/rsa: verification error",
+		},
+		{
+			name: "bad_signature",
+			idToken: func() string {
+				return signToken(t, otherKey, map[string]string{"alg": "RS256", "kid": "key-1"}, claims(nil))
+			},
+			wantErr: "invalid id token signature: crypto/rsa: verification error",
+		},
+		{
+			name: "alg_none",
+			idToken: func() string {
+				return signToken(t, issuer.key, map[string]string{"alg": "none", "kid": "key-1"}, claims(nil))
+			},
+			wantErr: "unsupported signing algorithm: none",
+		},
+		{
+			name: "alg_hmac",
+			idToken: func() string {
+				return signToken(t, issuer.key, map[string]string{"alg": "HS256", "kid": "key-1"}, claims(nil))
+			},
+			wantErr: "unsupported signing algorithm: HS256",
+		},
+		{
+			name: "alg_key_type_mismatch",
+			idToken: func() string {
+				return signToken(t, issuer.key, map[string]string{"alg": "ES256", "kid": "key-1"}, claims(nil))
+			},
+			wantErr: "signing algorithm ES256 does not match rsa key",
+		},
+		{
+			name:    "malformed",
+			idToken: func() string { return "not-a-jwt" },
+			wantErr: "malformed id token",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			issuer.idToken = tt.idToken()
+
+			p := newOIDCProvider(issuer.server.URL+"/", "autobrr", "secret", "http://localhost:7474/api/auth/oidc/callback")
+
+			got, err := p.Exchange(context.Background(), "mock-code", "mock-verifier", "mock-nonce")
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, "admin", got["preferred_username"])
+		})
+	}
+}
+
+func Test_oidcProvider_Exchange_keyRotation(t *testing.T) {
+	issuer := newMockIssuer(t)
+
+	claims := map[string]interface{}{
+		"iss":   issuer.server.URL,
+		"aud":   "autobrr",
+		"exp":   time.Now().Add(5 * time.Minute).Unix(),
+		"nonce": "mock-nonce",
+	}
+
+	p := newOIDCProvider(issuer.server.URL, "autobrr", "secret", "http://localhost:7474/api/auth/oidc/callback")
+
+	issuer.idToken = issuer.sign(t, "key-1", claims)
+	_, err := p.Exchange(context.Background(), "mock-code", "mock-verifier", "mock-nonce")
+	assert.NoError(t, err)
+
+	// the issuer rotates its key, the unknown kid makes the provider fetch the key set again
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	issuer.key, issuer.kid = key, "key-2"
+
+	issuer.idToken = issuer.sign(t, "key-2", claims)
+	_, err = p.Exchange(context.Background(), "mock-code", "mock-verifier", "mock-nonce")
+	assert.NoError(t, err)
+}
+
+func Test_oidcProvider_AuthCodeURL(t *testing.T) {
+	issuer := newMockIssuer(t)
+
+	p := newOIDCProvider(issuer.server.URL, "autobrr", "secret", "http://localhost:7474/api/auth/oidc/callback")
+
+	got, err := p.AuthCodeURL(context.Background(), "mock-state", "mock-nonce", "mock-verifier")
+	assert.NoError(t, err)
+
+	u, err := url.Parse(got)
+	assert.NoError(t, err)
+	assert.Equal(t, "/authorize", u.Path)
+
+	q := u.Query()
+	assert.Equal(t, "code", q.Get("response_type"))
+	assert.Equal(t, "autobrr", q.Get("client_id"))
+	assert.Equal(t, "http://localhost:7474/api/auth/oidc/callback", q.Get("redirect_uri"))
+	assert.Equal(t, "mock-state", q.Get("state"))
+	assert.Equal(t, "mock-nonce", q.Get("nonce"))
+	assert.Equal(t, pkceChallenge("mock-verifier"), q.Get("code_challenge"))
+	assert.Equal(t, "S256", q.Get("code_challenge_method"))
+}
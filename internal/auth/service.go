@@ -16,18 +16,35 @@ type Service interface {
 	GetUserCount(ctx context.Context) (int, error)
 	Login(ctx context.Context, username, password string) (*domain.User, error)
 	CreateUser(ctx context.Context, username, password string) error
+
+	OIDCEnabled() bool
+	OIDCAuthURL(ctx context.Context, state, nonce, verifier string) (string, error)
+	OIDCLogin(ctx context.Context, code, verifier, nonce string) (*domain.User, error)
 }
 
 type service struct {
 	log     zerolog.Logger
 	userSvc user.Service
+
+	oidc              *oidcProvider
+	oidcUsernameClaim string
 }
 
-func NewService(log logger.Logger, userSvc user.Service) Service {
-	return &service{
+func NewService(log logger.Logger, config *domain.Config, userSvc user.Service) Service {
+	s := &service{
 		log:     log.With().Str("module", "auth").Logger(),
 		userSvc: userSvc,
 	}
+
+	if config.OIDCEnabled {
+		s.oidc = newOIDCProvider(config.OIDCIssuer, config.OIDCClientID, config.OIDCClientSecret, config.OIDCRedirectURL)
+		s.oidcUsernameClaim = config.OIDCUsernameClaim
+		if s.oidcUsernameClaim == "" {
+			s.oidcUsernameClaim = "preferred_username"
+		}
+	}
+
+	return s
 }
 
 func (s *service) GetUserCount(ctx context.Context) (int, error) {
@@ -94,3 +111,47 @@ func (s *service) CreateUser(ctx context.Context, username, password string) err
 
 	return nil
 }
+
+func (s *service) OIDCEnabled() bool {
+	return s.oidc != nil
+}
+
+func (s *service) OIDCAuthURL(ctx context.Context, state, nonce, verifier string) (string, error) {
+	if s.oidc == nil {
+		return "", errors.New("oidc is not enabled")
+	}
+
+	return s.oidc.AuthCodeURL(ctx, state, nonce, verifier)
+}
+
+// OIDCLogin exchanges the authorization code and maps the configured username claim to the local user
+func (s *service) OIDCLogin(ctx context.Context, code, verifier, nonce string) (*domain.User, error) {
+	if s.oidc == nil {
+		return nil, errors.New("oidc is not enabled")
+	}
+
+	claims, err := s.oidc.Exchange(ctx, code, verifier, nonce)
+	if err != nil {
+		s.log.Error().Err(err).Msg("oidc login failed")
+		return nil, err
+	}
+
+	username, _ := claims[s.oidcUsernameClaim].(string)
+	if username == "" {
+		s.log.Error().Msgf("oidc login failed: id token is missing claim: %v", s.oidcUsernameClaim)
+		return nil, errors.New("missing username claim")
+	}
+
+	u, err := s.userSvc.FindByUsername(ctx, username)
+	if err != nil {
+		s.log.Error().Err(err).Msgf("could not find user by username: %v", username)
+		return nil, err
+	}
+
+	if u == nil {
+		s.log.Error().Msgf("oidc login failed: no user matching %v: %v", s.oidcUsernameClaim, username)
+		return nil, errors.New("bad credentials")
+	}
+
+	return u, nil
+}
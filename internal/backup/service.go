@@ -0,0 +1,231 @@
+package backup
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/internal/instance"
+	"github.com/autobrr/autobrr/internal/logger"
+	"github.com/autobrr/autobrr/pkg/errors"
+
+	"github.com/rs/zerolog"
+)
+
+const (
+	backupPrefix     = "autobrr-"
+	backupTimeFormat = "20060102-150405"
+)
+
+// Database is the part of database.DB used to make backups
+type Database interface {
+	Backup(ctx context.Context, dst string) error
+	BackupExtension() string
+}
+
+type Service interface {
+	Start()
+	Settings() domain.DatabaseBackupSettings
+	Create(ctx context.Context) (*domain.DatabaseBackup, error)
+	List() ([]domain.DatabaseBackup, error)
+	Path(name string) (string, error)
+	Delete(name string) error
+}
+
+type service struct {
+	log         zerolog.Logger
+	db          Database
+	instanceSvc instance.Service
+
+	dir      string
+	interval time.Duration
+	retain   int
+
+	// one backup at a time, a scheduled and a manual backup in the same second would share a name
+	mu sync.Mutex
+
+	now func() time.Time
+}
+
+func NewService(log logger.Logger, config *domain.Config, db Database, instanceSvc instance.Service) Service {
+	dir := config.BackupDir
+	if dir == "" {
+		dir = filepath.Join(config.ConfigPath, "backups")
+	}
+
+	return &service{
+		log:         log.With().Str("module", "backup").Logger(),
+		db:          db,
+		instanceSvc: instanceSvc,
+		dir:         dir,
+		interval:    time.Duration(config.BackupInterval) * time.Hour,
+		retain:      config.BackupRetain,
+		now:         time.Now,
+	}
+}
+
+// Start makes a backup every interval, the first one after one interval so a restart loop does not fill the disk
+func (s *service) Start() {
+	if s.interval <= 0 {
+		s.log.Debug().Msg("scheduled database backups disabled")
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(s.interval)
+		defer ticker.Stop()
+
+		for range ticker.C {
+			// several instances share a postgres database, one backup is enough
+			if !s.instanceSvc.IsLeader() {
+				continue
+			}
+
+			if _, err := s.Create(context.Background()); err != nil {
+				s.log.Error().Err(err).Msg("scheduled database backup failed")
+			}
+		}
+	}()
+}
+
+func (s *service) Settings() domain.DatabaseBackupSettings {
+	return domain.DatabaseBackupSettings{
+		Dir:      s.dir,
+		Interval: int(s.interval / time.Hour),
+		Retain:   s.retain,
+	}
+}
+
+// Create writes a new backup and removes the oldest backups beyond the retain count
+func (s *service) Create(ctx context.Context) (*domain.DatabaseBackup, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := os.MkdirAll(s.dir, 0755); err != nil {
+		return nil, errors.Wrap(err, "could not create backup dir: %v", s.dir)
+	}
+
+	start := s.now()
+	name := backupPrefix + start.Format(backupTimeFormat) + s.db.BackupExtension()
+	dst := filepath.Join(s.dir, name)
+
+	if err := s.db.Backup(ctx, dst); err != nil {
+		return nil, err
+	}
+
+	info, err := os.Stat(dst)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not stat backup: %v", dst)
+	}
+
+	s.log.Info().Msgf("database backup %v written in %v", name, time.Since(start).Round(time.Millisecond))
+
+	if err := s.rotate(); err != nil {
+		s.log.Error().Err(err).Msg("could not remove old database backups")
+	}
+
+	return &domain.DatabaseBackup{
+		Name:      name,
+		Size:      info.Size(),
+		CreatedAt: info.ModTime(),
+	}, nil
+}
+
+// List returns the backups in the backup dir, newest first
+func (s *service) List() ([]domain.DatabaseBackup, error) {
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return []domain.DatabaseBackup{}, nil
+		}
+		return nil, errors.Wrap(err, "could not read backup dir: %v", s.dir)
+	}
+
+	backups := make([]domain.DatabaseBackup, 0)
+	for _, entry := range entries {
+		if entry.IsDir() || !s.isBackup(entry.Name()) {
+			continue
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+
+		backups = append(backups, domain.DatabaseBackup{
+			Name:      entry.Name(),
+			Size:      info.Size(),
+			CreatedAt: info.ModTime(),
+		})
+	}
+
+	// names hold the timestamp so they sort by age
+	sort.Slice(backups, func(i, j int) bool {
+		return backups[i].Name > backups[j].Name
+	})
+
+	return backups, nil
+}
+
+// Path returns the file path of a backup by name, names that are not backups or point outside the backup dir are rejected
+func (s *service) Path(name string) (string, error) {
+	if name != filepath.Base(name) || !s.isBackup(name) {
+		return "", errors.New("invalid backup name: %v", name)
+	}
+
+	path := filepath.Join(s.dir, name)
+	if _, err := os.Stat(path); err != nil {
+		return "", errors.Wrap(err, "backup not found: %v", name)
+	}
+
+	return path, nil
+}
+
+func (s *service) Delete(name string) error {
+	path, err := s.Path(name)
+	if err != nil {
+		return err
+	}
+
+	if err := os.Remove(path); err != nil {
+		return errors.Wrap(err, "could not delete backup: %v", name)
+	}
+
+	s.log.Info().Msgf("database backup %v deleted", name)
+
+	return nil
+}
+
+func (s *service) rotate() error {
+	if s.retain <= 0 {
+		return nil
+	}
+
+	backups, err := s.List()
+	if err != nil {
+		return err
+	}
+
+	if len(backups) <= s.retain {
+		return nil
+	}
+
+	for _, b := range backups[s.retain:] {
+		if err := os.Remove(filepath.Join(s.dir, b.Name)); err != nil {
+			return errors.Wrap(err, "could not remove backup: %v", b.Name)
+		}
+
+		s.log.Debug().Msgf("removed old database backup %v", b.Name)
+	}
+
+	return nil
+}
+
+func (s *service) isBackup(name string) bool {
+	return strings.HasPrefix(name, backupPrefix) && strings.HasSuffix(name, s.db.BackupExtension())
+}
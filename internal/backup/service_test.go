@@ -0,0 +1,83 @@
+package backup
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/internal/logger"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type mockDatabase struct{}
+
+func (d *mockDatabase) Backup(ctx context.Context, dst string) error {
+	return os.WriteFile(dst, []byte("backup"), 0644)
+}
+
+func (d *mockDatabase) BackupExtension() string {
+	return ".db"
+}
+
+func Test_service_Create(t *testing.T) {
+	dir := t.TempDir()
+
+	svc := NewService(logger.Mock(), &domain.Config{BackupDir: dir, BackupRetain: 2}, &mockDatabase{}, nil).(*service)
+
+	// unrelated files in the backup dir are left alone
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "notes.txt"), []byte("keep"), 0644))
+
+	now := time.Date(2022, 10, 14, 12, 0, 0, 0, time.UTC)
+	for i := 0; i < 3; i++ {
+		svc.now = func() time.Time { return now.Add(time.Duration(i) * time.Hour) }
+
+		b, err := svc.Create(context.Background())
+		assert.NoError(t, err)
+		assert.Equal(t, int64(6), b.Size)
+	}
+
+	backups, err := svc.List()
+	assert.NoError(t, err)
+
+	var names []string
+	for _, b := range backups {
+		names = append(names, b.Name)
+	}
+	assert.Equal(t, []string{"autobrr-20221014-140000.db", "autobrr-20221014-130000.db"}, names)
+
+	assert.FileExists(t, filepath.Join(dir, "notes.txt"))
+}
+
+func Test_service_Path(t *testing.T) {
+	dir := t.TempDir()
+
+	svc := NewService(logger.Mock(), &domain.Config{BackupDir: dir}, &mockDatabase{}, nil)
+
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "autobrr-20221014-120000.db"), []byte("backup"), 0644))
+
+	tests := []struct {
+		name    string
+		backup  string
+		wantErr bool
+	}{
+		{name: "backup", backup: "autobrr-20221014-120000.db"},
+		{name: "missing", backup: "autobrr-20221014-130000.db", wantErr: true},
+		{name: "not_backup", backup: "config.toml", wantErr: true},
+		{name: "traversal", backup: "../autobrr-20221014-120000.db", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := svc.Path(tt.backup)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, filepath.Join(dir, tt.backup), got)
+		})
+	}
+}
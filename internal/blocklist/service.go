@@ -0,0 +1,225 @@
+// Package blocklist drops announces matching the announce blocklist rules before they reach the filters.
+package blocklist
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/autobrr/autobrr/internal/audit"
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/internal/logger"
+	"github.com/autobrr/autobrr/pkg/errors"
+
+	"github.com/rs/zerolog"
+)
+
+const hitFlushInterval = time.Minute
+
+type Service interface {
+	List(ctx context.Context) ([]domain.AnnounceBlocklistRule, error)
+	FindByID(ctx context.Context, id int) (*domain.AnnounceBlocklistRule, error)
+	Store(ctx context.Context, rule *domain.AnnounceBlocklistRule) error
+	Update(ctx context.Context, rule *domain.AnnounceBlocklistRule) error
+	Delete(ctx context.Context, id int) error
+
+	// Check returns the first enabled rule blocking the release
+	Check(release *domain.Release) (*domain.AnnounceBlocklistRule, bool)
+	// RecordHit counts an announce blocked by the rule
+	RecordHit(rule *domain.AnnounceBlocklistRule)
+	// TotalHits is the number of announces blocked by the current rules
+	TotalHits(ctx context.Context) (int64, error)
+
+	Start()
+}
+
+type service struct {
+	log      zerolog.Logger
+	repo     domain.AnnounceBlocklistRepo
+	auditSvc audit.Service
+	now      func() time.Time
+
+	// rules are checked on every announce, they are kept in memory and reloaded on change
+	rulesMu sync.RWMutex
+	rules   []*domain.AnnounceBlocklistRule
+
+	hitsMu sync.Mutex
+	hits   map[int]*domain.AnnounceBlocklistHits
+}
+
+func NewService(log logger.Logger, repo domain.AnnounceBlocklistRepo, auditSvc audit.Service) Service {
+	return &service{
+		log:      log.With().Str("module", "blocklist").Logger(),
+		repo:     repo,
+		auditSvc: auditSvc,
+		now:      time.Now,
+		hits:     map[int]*domain.AnnounceBlocklistHits{},
+	}
+}
+
+// Start loads the rules and periodically writes the counted hits to the database
+func (s *service) Start() {
+	if err := s.reload(context.Background()); err != nil {
+		s.log.Error().Err(err).Msg("could not load announce blocklist rules")
+	}
+
+	go func() {
+		ticker := time.NewTicker(hitFlushInterval)
+		defer ticker.Stop()
+
+		for range ticker.C {
+			s.flushHits(context.Background())
+		}
+	}()
+}
+
+func (s *service) List(ctx context.Context) ([]domain.AnnounceBlocklistRule, error) {
+	// include the hits counted since the last flush
+	s.flushHits(ctx)
+
+	return s.repo.List(ctx)
+}
+
+func (s *service) FindByID(ctx context.Context, id int) (*domain.AnnounceBlocklistRule, error) {
+	s.flushHits(ctx)
+
+	return s.repo.FindByID(ctx, id)
+}
+
+func (s *service) Store(ctx context.Context, rule *domain.AnnounceBlocklistRule) error {
+	if err := rule.Validate(); err != nil {
+		return errors.Wrap(err, "invalid announce blocklist rule")
+	}
+
+	if err := s.repo.Store(ctx, rule); err != nil {
+		return err
+	}
+
+	s.auditSvc.Record(ctx, domain.AuditEntityBlocklistRule, rule.ID, rule.Name, nil, rule)
+
+	return s.reload(ctx)
+}
+
+func (s *service) Update(ctx context.Context, rule *domain.AnnounceBlocklistRule) error {
+	if err := rule.Validate(); err != nil {
+		return errors.Wrap(err, "invalid announce blocklist rule")
+	}
+
+	before, err := s.repo.FindByID(ctx, rule.ID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.repo.Update(ctx, rule); err != nil {
+		return err
+	}
+
+	s.auditSvc.Record(ctx, domain.AuditEntityBlocklistRule, rule.ID, rule.Name, before, rule)
+
+	return s.reload(ctx)
+}
+
+func (s *service) Delete(ctx context.Context, id int) error {
+	before, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if err := s.repo.Delete(ctx, id); err != nil {
+		return err
+	}
+
+	s.auditSvc.Record(ctx, domain.AuditEntityBlocklistRule, before.ID, before.Name, before, nil)
+
+	return s.reload(ctx)
+}
+
+// reload reads the enabled rules, rules that no longer validate are skipped
+func (s *service) reload(ctx context.Context) error {
+	stored, err := s.repo.List(ctx)
+	if err != nil {
+		return err
+	}
+
+	rules := make([]*domain.AnnounceBlocklistRule, 0, len(stored))
+	for i := range stored {
+		rule := &stored[i]
+		if !rule.Enabled {
+			continue
+		}
+
+		if err := rule.Validate(); err != nil {
+			s.log.Warn().Err(err).Msgf("skipping announce blocklist rule: %v", rule.Name)
+			continue
+		}
+
+		rules = append(rules, rule)
+	}
+
+	s.rulesMu.Lock()
+	s.rules = rules
+	s.rulesMu.Unlock()
+
+	return nil
+}
+
+func (s *service) Check(release *domain.Release) (*domain.AnnounceBlocklistRule, bool) {
+	s.rulesMu.RLock()
+	defer s.rulesMu.RUnlock()
+
+	for _, rule := range s.rules {
+		if rule.Matches(release) {
+			return rule, true
+		}
+	}
+
+	return nil, false
+}
+
+func (s *service) RecordHit(rule *domain.AnnounceBlocklistRule) {
+	s.hitsMu.Lock()
+	defer s.hitsMu.Unlock()
+
+	h, ok := s.hits[rule.ID]
+	if !ok {
+		h = &domain.AnnounceBlocklistHits{RuleID: rule.ID}
+		s.hits[rule.ID] = h
+	}
+
+	h.Count++
+	h.LastHitAt = s.now()
+}
+
+func (s *service) flushHits(ctx context.Context) {
+	s.hitsMu.Lock()
+	pending := s.hits
+	s.hits = map[int]*domain.AnnounceBlocklistHits{}
+	s.hitsMu.Unlock()
+
+	if len(pending) == 0 {
+		return
+	}
+
+	hits := make([]domain.AnnounceBlocklistHits, 0, len(pending))
+	for _, h := range pending {
+		hits = append(hits, *h)
+	}
+
+	if err := s.repo.AddHits(ctx, hits); err != nil {
+		s.log.Error().Err(err).Msgf("could not store hits of %d announce blocklist rules", len(hits))
+	}
+}
+
+func (s *service) TotalHits(ctx context.Context) (int64, error) {
+	rules, err := s.List(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	var total int64
+	for _, rule := range rules {
+		total += rule.Hits
+	}
+
+	return total, nil
+}
@@ -0,0 +1,100 @@
+package blocklist
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/autobrr/autobrr/internal/audit"
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/internal/logger"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type mockRepo struct {
+	domain.AnnounceBlocklistRepo
+
+	rules []domain.AnnounceBlocklistRule
+	hits  []domain.AnnounceBlocklistHits
+}
+
+func (r *mockRepo) List(ctx context.Context) ([]domain.AnnounceBlocklistRule, error) {
+	rules := make([]domain.AnnounceBlocklistRule, len(r.rules))
+	copy(rules, r.rules)
+	return rules, nil
+}
+
+func (r *mockRepo) Store(ctx context.Context, rule *domain.AnnounceBlocklistRule) error {
+	rule.ID = len(r.rules) + 1
+	r.rules = append(r.rules, *rule)
+	return nil
+}
+
+func (r *mockRepo) AddHits(ctx context.Context, hits []domain.AnnounceBlocklistHits) error {
+	r.hits = append(r.hits, hits...)
+	for _, h := range hits {
+		for i := range r.rules {
+			if r.rules[i].ID == h.RuleID {
+				r.rules[i].Hits += h.Count
+			}
+		}
+	}
+	return nil
+}
+
+type mockAuditService struct {
+	audit.Service
+}
+
+func (mockAuditService) Record(ctx context.Context, entityType domain.AuditEntityType, entityID interface{}, entityName string, before, after interface{}) {
+}
+
+func Test_service_Check(t *testing.T) {
+	repo := &mockRepo{rules: []domain.AnnounceBlocklistRule{
+		{ID: 1, Name: "disabled", Type: domain.AnnounceBlocklistTypeGroup, Value: "GRP"},
+		{ID: 2, Name: "invalid", Enabled: true, Type: domain.AnnounceBlocklistTypeTitleRegex, Value: "(cam"},
+	}}
+
+	s := NewService(logger.Mock(), repo, mockAuditService{}).(*service)
+	assert.NoError(t, s.reload(context.Background()))
+
+	release := &domain.Release{Indexer: "mock", TorrentName: "That.Movie.2022.CAM.x264-GRP", Group: "GRP"}
+
+	_, blocked := s.Check(release)
+	assert.False(t, blocked)
+
+	assert.NoError(t, s.Store(context.Background(), &domain.AnnounceBlocklistRule{Name: "cam", Enabled: true, Type: domain.AnnounceBlocklistTypeTitleRegex, Value: `\.cam\.`}))
+
+	rule, blocked := s.Check(release)
+	assert.True(t, blocked)
+	assert.Equal(t, "cam", rule.Name)
+}
+
+func Test_service_RecordHit(t *testing.T) {
+	repo := &mockRepo{rules: []domain.AnnounceBlocklistRule{
+		{ID: 1, Name: "a", Enabled: true, Type: domain.AnnounceBlocklistTypeGroup, Value: "GRP", Hits: 10},
+		{ID: 2, Name: "b", Enabled: true, Type: domain.AnnounceBlocklistTypeUploader, Value: "spam"},
+	}}
+
+	now := time.Date(2022, 10, 1, 12, 0, 0, 0, time.UTC)
+
+	s := NewService(logger.Mock(), repo, mockAuditService{}).(*service)
+	s.now = func() time.Time { return now }
+
+	s.RecordHit(&repo.rules[0])
+	s.RecordHit(&repo.rules[0])
+	s.RecordHit(&repo.rules[1])
+
+	// hits are written on flush, the total includes the ones not flushed yet
+	assert.Empty(t, repo.hits)
+
+	total, err := s.TotalHits(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, int64(13), total)
+	assert.ElementsMatch(t, []domain.AnnounceBlocklistHits{{RuleID: 1, Count: 2, LastHitAt: now}, {RuleID: 2, Count: 1, LastHitAt: now}}, repo.hits)
+
+	// nothing left to flush
+	s.flushHits(context.Background())
+	assert.Len(t, repo.hits, 2)
+}
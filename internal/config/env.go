@@ -0,0 +1,77 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/autobrr/autobrr/internal/domain"
+)
+
+// envPrefix of the environment variables overriding config.toml, eg. AUTOBRR__LOG_LEVEL for logLevel
+const envPrefix = "AUTOBRR__"
+
+// envName turns a config key like oidcClientId into its environment variable AUTOBRR__OIDC_CLIENT_ID
+func envName(key string) string {
+	var b strings.Builder
+	b.WriteString(envPrefix)
+
+	for i, r := range key {
+		if i > 0 && unicode.IsUpper(r) && !unicode.IsUpper(rune(key[i-1])) {
+			b.WriteByte('_')
+		}
+		b.WriteRune(unicode.ToUpper(r))
+	}
+
+	return b.String()
+}
+
+// applyEnv overrides the settings of cfg with the AUTOBRR__ environment variables that are set.
+// Values that don't parse are left as they are and reported.
+func applyEnv(cfg *domain.Config, lookup func(string) (string, bool)) []string {
+	var errs []string
+
+	v := reflect.ValueOf(cfg).Elem()
+	t := v.Type()
+
+	for i := 0; i < t.NumField(); i++ {
+		key := t.Field(i).Tag.Get("toml")
+		if key == "" || key == "-" {
+			continue
+		}
+
+		name := envName(key)
+
+		value, ok := lookup(name)
+		if !ok {
+			continue
+		}
+
+		field := v.Field(i)
+
+		switch field.Kind() {
+		case reflect.String:
+			field.SetString(value)
+
+		case reflect.Int, reflect.Int64:
+			n, err := strconv.ParseInt(strings.TrimSpace(value), 10, 64)
+			if err != nil {
+				errs = append(errs, fmt.Sprintf("%v: invalid number %q", name, value))
+				continue
+			}
+			field.SetInt(n)
+
+		case reflect.Bool:
+			b, err := strconv.ParseBool(strings.TrimSpace(value))
+			if err != nil {
+				errs = append(errs, fmt.Sprintf("%v: invalid boolean %q", name, value))
+				continue
+			}
+			field.SetBool(b)
+		}
+	}
+
+	return errs
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"encoding/json"
+	"sync"
 
 	"github.com/autobrr/autobrr/internal/domain"
 	"github.com/autobrr/autobrr/internal/logger"
@@ -17,6 +18,10 @@ type ActionRepo struct {
 	log        zerolog.Logger
 	db         *DB
 	clientRepo domain.DownloadClientRepo
+
+	// filter dependencies are read for every announce, they are cached until an action is written
+	depsMu sync.Mutex
+	deps   domain.FilterDependencies
 }
 
 func NewActionRepo(log logger.Logger, db *DB, clientRepo domain.DownloadClientRepo) domain.ActionRepo {
@@ -63,11 +68,16 @@ func (r *ActionRepo) findByFilterID(ctx context.Context, tx *Tx, filterID int) (
 			"enabled",
 			"exec_cmd",
 			"exec_args",
+			"exec_timeout",
+			"bandwidth_priority",
+			"bandwidth_group",
+			"sequential_download",
 			"watch_folder",
 			"category",
 			"tags",
 			"label",
 			"save_path",
+			"move_completed_path",
 			"paused",
 			"ignore_rules",
 			"skip_hash_check",
@@ -85,6 +95,11 @@ func (r *ActionRepo) findByFilterID(ctx context.Context, tx *Tx, filterID int) (
 			"webhook_method",
 			"webhook_data",
 			"client_id",
+			"depends_on_filter_id",
+			"fallback_client_id",
+			"external_download_client_id",
+			"external_download_client",
+			"run_condition",
 		).
 		From("action").
 		Where("filter_id = ?", filterID)
@@ -105,25 +120,34 @@ func (r *ActionRepo) findByFilterID(ctx context.Context, tx *Tx, filterID int) (
 	for rows.Next() {
 		var a domain.Action
 
-		var execCmd, execArgs, watchFolder, category, tags, label, savePath, contentLayout, webhookHost, webhookType, webhookMethod, webhookData sql.NullString
+		var execCmd, execArgs, watchFolder, category, tags, label, savePath, moveCompletedPath, contentLayout, webhookHost, webhookType, webhookMethod, webhookData sql.NullString
 		var limitUl, limitDl, limitSeedTime sql.NullInt64
 		var limitRatio sql.NullFloat64
 
-		var clientID sql.NullInt32
+		var clientID, dependsOnFilterID, fallbackClientID, externalDownloadClientID sql.NullInt32
+		var externalDownloadClient, runCondition sql.NullString
+		var execTimeout, bandwidthPriority sql.NullInt64
+		var bandwidthGroup sql.NullString
+		var sequentialDownload sql.NullBool
 		// filterID
 		var paused, ignoreRules sql.NullBool
 
-		if err := rows.Scan(&a.ID, &a.Name, &a.Type, &a.Enabled, &execCmd, &execArgs, &watchFolder, &category, &tags, &label, &savePath, &paused, &ignoreRules, &a.SkipHashCheck, &contentLayout, &limitDl, &limitUl, &limitRatio, &limitSeedTime, &a.ReAnnounceSkip, &a.ReAnnounceDelete, &a.ReAnnounceInterval, &a.ReAnnounceMaxAttempts, &webhookHost, &webhookType, &webhookMethod, &webhookData, &clientID); err != nil {
+		if err := rows.Scan(&a.ID, &a.Name, &a.Type, &a.Enabled, &execCmd, &execArgs, &execTimeout, &bandwidthPriority, &bandwidthGroup, &sequentialDownload, &watchFolder, &category, &tags, &label, &savePath, &moveCompletedPath, &paused, &ignoreRules, &a.SkipHashCheck, &contentLayout, &limitDl, &limitUl, &limitRatio, &limitSeedTime, &a.ReAnnounceSkip, &a.ReAnnounceDelete, &a.ReAnnounceInterval, &a.ReAnnounceMaxAttempts, &webhookHost, &webhookType, &webhookMethod, &webhookData, &clientID, &dependsOnFilterID, &fallbackClientID, &externalDownloadClientID, &externalDownloadClient, &runCondition); err != nil {
 			return nil, errors.Wrap(err, "error scanning row")
 		}
 
 		a.ExecCmd = execCmd.String
 		a.ExecArgs = execArgs.String
+		a.ExecTimeout = int(execTimeout.Int64)
+		a.BandwidthPriority = int(bandwidthPriority.Int64)
+		a.BandwidthGroup = bandwidthGroup.String
+		a.SequentialDownload = sequentialDownload.Bool
 		a.WatchFolder = watchFolder.String
 		a.Category = category.String
 		a.Tags = tags.String
 		a.Label = label.String
 		a.SavePath = savePath.String
+		a.MoveCompletedPath = moveCompletedPath.String
 		a.Paused = paused.Bool
 		a.IgnoreRules = ignoreRules.Bool
 		a.ContentLayout = domain.ActionContentLayout(contentLayout.String)
@@ -139,6 +163,11 @@ func (r *ActionRepo) findByFilterID(ctx context.Context, tx *Tx, filterID int) (
 		a.WebhookData = webhookData.String
 
 		a.ClientID = clientID.Int32
+		a.DependsOnFilterID = int(dependsOnFilterID.Int32)
+		a.FallbackClientID = fallbackClientID.Int32
+		a.ExternalDownloadClientID = externalDownloadClientID.Int32
+		a.ExternalDownloadClient = externalDownloadClient.String
+		a.RunCondition = runCondition.String
 
 		actions = append(actions, &a)
 	}
@@ -202,6 +231,10 @@ func (r *ActionRepo) List(ctx context.Context) ([]domain.Action, error) {
 			"enabled",
 			"exec_cmd",
 			"exec_args",
+			"exec_timeout",
+			"bandwidth_priority",
+			"bandwidth_group",
+			"sequential_download",
 			"watch_folder",
 			"category",
 			"tags",
@@ -222,6 +255,11 @@ func (r *ActionRepo) List(ctx context.Context) ([]domain.Action, error) {
 			"webhook_method",
 			"webhook_data",
 			"client_id",
+			"depends_on_filter_id",
+			"fallback_client_id",
+			"external_download_client_id",
+			"external_download_client",
+			"run_condition",
 		).
 		From("action")
 
@@ -244,10 +282,14 @@ func (r *ActionRepo) List(ctx context.Context) ([]domain.Action, error) {
 		var execCmd, execArgs, watchFolder, category, tags, label, savePath, webhookHost, webhookType, webhookMethod, webhookData sql.NullString
 		var limitUl, limitDl, limitSeedTime sql.NullInt64
 		var limitRatio sql.NullFloat64
-		var clientID sql.NullInt32
+		var clientID, dependsOnFilterID, fallbackClientID, externalDownloadClientID sql.NullInt32
+		var externalDownloadClient, runCondition sql.NullString
+		var execTimeout, bandwidthPriority sql.NullInt64
+		var bandwidthGroup sql.NullString
+		var sequentialDownload sql.NullBool
 		var paused, ignoreRules sql.NullBool
 
-		if err := rows.Scan(&a.ID, &a.Name, &a.Type, &a.Enabled, &execCmd, &execArgs, &watchFolder, &category, &tags, &label, &savePath, &paused, &ignoreRules, &limitDl, &limitUl, &limitRatio, &limitSeedTime, &a.ReAnnounceSkip, &a.ReAnnounceDelete, &a.ReAnnounceInterval, &a.ReAnnounceMaxAttempts, &webhookHost, &webhookType, &webhookMethod, &webhookData, &clientID); err != nil {
+		if err := rows.Scan(&a.ID, &a.Name, &a.Type, &a.Enabled, &execCmd, &execArgs, &execTimeout, &bandwidthPriority, &bandwidthGroup, &sequentialDownload, &watchFolder, &category, &tags, &label, &savePath, &paused, &ignoreRules, &limitDl, &limitUl, &limitRatio, &limitSeedTime, &a.ReAnnounceSkip, &a.ReAnnounceDelete, &a.ReAnnounceInterval, &a.ReAnnounceMaxAttempts, &webhookHost, &webhookType, &webhookMethod, &webhookData, &clientID, &dependsOnFilterID, &fallbackClientID, &externalDownloadClientID, &externalDownloadClient, &runCondition); err != nil {
 			return nil, errors.Wrap(err, "error scanning row")
 		}
 
@@ -257,6 +299,10 @@ func (r *ActionRepo) List(ctx context.Context) ([]domain.Action, error) {
 		a.SavePath = savePath.String
 		a.Paused = paused.Bool
 		a.IgnoreRules = ignoreRules.Bool
+		a.ExecTimeout = int(execTimeout.Int64)
+		a.BandwidthPriority = int(bandwidthPriority.Int64)
+		a.BandwidthGroup = bandwidthGroup.String
+		a.SequentialDownload = sequentialDownload.Bool
 
 		a.LimitDownloadSpeed = limitDl.Int64
 		a.LimitUploadSpeed = limitUl.Int64
@@ -269,6 +315,11 @@ func (r *ActionRepo) List(ctx context.Context) ([]domain.Action, error) {
 		a.WebhookData = webhookData.String
 
 		a.ClientID = clientID.Int32
+		a.DependsOnFilterID = int(dependsOnFilterID.Int32)
+		a.FallbackClientID = fallbackClientID.Int32
+		a.ExternalDownloadClientID = externalDownloadClientID.Int32
+		a.ExternalDownloadClient = externalDownloadClient.String
+		a.RunCondition = runCondition.String
 
 		actions = append(actions, a)
 	}
@@ -279,7 +330,75 @@ func (r *ActionRepo) List(ctx context.Context) ([]domain.Action, error) {
 	return actions, nil
 }
 
+// FindFilterDependencies returns the filters each filter depends on through its enabled actions.
+// The result is a copy of the cached dependencies, callers may change it.
+func (r *ActionRepo) FindFilterDependencies(ctx context.Context) (domain.FilterDependencies, error) {
+	r.depsMu.Lock()
+	defer r.depsMu.Unlock()
+
+	if r.deps == nil {
+		deps, err := r.findFilterDependencies(ctx)
+		if err != nil {
+			return nil, err
+		}
+		r.deps = deps
+	}
+
+	deps := make(domain.FilterDependencies, len(r.deps))
+	for id, dependsOn := range r.deps {
+		deps[id] = append([]int{}, dependsOn...)
+	}
+
+	return deps, nil
+}
+
+// resetFilterDependencies drops the cached dependencies, they are read again on next use
+func (r *ActionRepo) resetFilterDependencies() {
+	r.depsMu.Lock()
+	r.deps = nil
+	r.depsMu.Unlock()
+}
+
+func (r *ActionRepo) findFilterDependencies(ctx context.Context) (domain.FilterDependencies, error) {
+	queryBuilder := r.db.squirrel.
+		Select("filter_id", "depends_on_filter_id").
+		From("action").
+		Where("enabled = ?", true).
+		Where("filter_id IS NOT NULL").
+		Where("depends_on_filter_id IS NOT NULL")
+
+	query, args, err := queryBuilder.ToSql()
+	if err != nil {
+		return nil, errors.Wrap(err, "error building query")
+	}
+
+	rows, err := r.db.handler.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, errors.Wrap(err, "error executing query")
+	}
+
+	defer rows.Close()
+
+	deps := make(domain.FilterDependencies)
+	for rows.Next() {
+		var filterID, dependsOnFilterID int
+
+		if err := rows.Scan(&filterID, &dependsOnFilterID); err != nil {
+			return nil, errors.Wrap(err, "error scanning row")
+		}
+
+		deps[filterID] = append(deps[filterID], dependsOnFilterID)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errors.Wrap(err, "rows error")
+	}
+
+	return deps, nil
+}
+
 func (r *ActionRepo) Delete(actionID int) error {
+	defer r.resetFilterDependencies()
+
 	queryBuilder := r.db.squirrel.
 		Delete("action").
 		Where("id = ?", actionID)
@@ -300,6 +419,8 @@ func (r *ActionRepo) Delete(actionID int) error {
 }
 
 func (r *ActionRepo) DeleteByFilterID(ctx context.Context, filterID int) error {
+	defer r.resetFilterDependencies()
+
 	queryBuilder := r.db.squirrel.
 		Delete("action").
 		Where("filter_id = ?", filterID)
@@ -320,6 +441,8 @@ func (r *ActionRepo) DeleteByFilterID(ctx context.Context, filterID int) error {
 }
 
 func (r *ActionRepo) Store(ctx context.Context, action domain.Action) (*domain.Action, error) {
+	defer r.resetFilterDependencies()
+
 	execCmd := toNullString(action.ExecCmd)
 	execArgs := toNullString(action.ExecArgs)
 	watchFolder := toNullString(action.WatchFolder)
@@ -327,6 +450,7 @@ func (r *ActionRepo) Store(ctx context.Context, action domain.Action) (*domain.A
 	tags := toNullString(action.Tags)
 	label := toNullString(action.Label)
 	savePath := toNullString(action.SavePath)
+	moveCompletedPath := toNullString(action.MoveCompletedPath)
 	contentLayout := toNullString(string(action.ContentLayout))
 	webhookHost := toNullString(action.WebhookHost)
 	webhookData := toNullString(action.WebhookData)
@@ -339,6 +463,8 @@ func (r *ActionRepo) Store(ctx context.Context, action domain.Action) (*domain.A
 	limitSeedTime := toNullInt64(action.LimitSeedTime)
 	clientID := toNullInt32(action.ClientID)
 	filterID := toNullInt32(int32(action.FilterID))
+	dependsOnFilterID := toNullInt32(int32(action.DependsOnFilterID))
+	fallbackClientID := toNullInt32(action.FallbackClientID)
 
 	queryBuilder := r.db.squirrel.
 		Insert("action").
@@ -348,11 +474,16 @@ func (r *ActionRepo) Store(ctx context.Context, action domain.Action) (*domain.A
 			"enabled",
 			"exec_cmd",
 			"exec_args",
+			"exec_timeout",
+			"bandwidth_priority",
+			"bandwidth_group",
+			"sequential_download",
 			"watch_folder",
 			"category",
 			"tags",
 			"label",
 			"save_path",
+			"move_completed_path",
 			"paused",
 			"ignore_rules",
 			"skip_hash_check",
@@ -371,6 +502,11 @@ func (r *ActionRepo) Store(ctx context.Context, action domain.Action) (*domain.A
 			"webhook_data",
 			"client_id",
 			"filter_id",
+			"depends_on_filter_id",
+			"fallback_client_id",
+			"external_download_client_id",
+			"external_download_client",
+			"run_condition",
 		).
 		Values(
 			action.Name,
@@ -378,11 +514,16 @@ func (r *ActionRepo) Store(ctx context.Context, action domain.Action) (*domain.A
 			action.Enabled,
 			execCmd,
 			execArgs,
+			action.ExecTimeout,
+			action.BandwidthPriority,
+			toNullString(action.BandwidthGroup),
+			action.SequentialDownload,
 			watchFolder,
 			category,
 			tags,
 			label,
 			savePath,
+			moveCompletedPath,
 			action.Paused,
 			action.IgnoreRules,
 			action.SkipHashCheck,
@@ -401,6 +542,11 @@ func (r *ActionRepo) Store(ctx context.Context, action domain.Action) (*domain.A
 			webhookData,
 			clientID,
 			filterID,
+			dependsOnFilterID,
+			fallbackClientID,
+			toNullInt32(action.ExternalDownloadClientID),
+			toNullString(action.ExternalDownloadClient),
+			toNullString(action.RunCondition),
 		).
 		Suffix("RETURNING id").RunWith(r.db.handler)
 
@@ -419,6 +565,8 @@ func (r *ActionRepo) Store(ctx context.Context, action domain.Action) (*domain.A
 }
 
 func (r *ActionRepo) Update(ctx context.Context, action domain.Action) (*domain.Action, error) {
+	defer r.resetFilterDependencies()
+
 	execCmd := toNullString(action.ExecCmd)
 	execArgs := toNullString(action.ExecArgs)
 	watchFolder := toNullString(action.WatchFolder)
@@ -426,6 +574,7 @@ func (r *ActionRepo) Update(ctx context.Context, action domain.Action) (*domain.
 	tags := toNullString(action.Tags)
 	label := toNullString(action.Label)
 	savePath := toNullString(action.SavePath)
+	moveCompletedPath := toNullString(action.MoveCompletedPath)
 	contentLayout := toNullString(string(action.ContentLayout))
 	webhookHost := toNullString(action.WebhookHost)
 	webhookType := toNullString(action.WebhookType)
@@ -439,6 +588,8 @@ func (r *ActionRepo) Update(ctx context.Context, action domain.Action) (*domain.
 
 	clientID := toNullInt32(action.ClientID)
 	filterID := toNullInt32(int32(action.FilterID))
+	dependsOnFilterID := toNullInt32(int32(action.DependsOnFilterID))
+	fallbackClientID := toNullInt32(action.FallbackClientID)
 
 	var err error
 
@@ -449,11 +600,16 @@ func (r *ActionRepo) Update(ctx context.Context, action domain.Action) (*domain.
 		Set("enabled", action.Enabled).
 		Set("exec_cmd", execCmd).
 		Set("exec_args", execArgs).
+		Set("exec_timeout", action.ExecTimeout).
+		Set("bandwidth_priority", action.BandwidthPriority).
+		Set("bandwidth_group", toNullString(action.BandwidthGroup)).
+		Set("sequential_download", action.SequentialDownload).
 		Set("watch_folder", watchFolder).
 		Set("category", category).
 		Set("tags", tags).
 		Set("label", label).
 		Set("save_path", savePath).
+		Set("move_completed_path", moveCompletedPath).
 		Set("paused", action.Paused).
 		Set("ignore_rules", action.IgnoreRules).
 		Set("skip_hash_check", action.SkipHashCheck).
@@ -472,6 +628,11 @@ func (r *ActionRepo) Update(ctx context.Context, action domain.Action) (*domain.
 		Set("webhook_data", webhookData).
 		Set("client_id", clientID).
 		Set("filter_id", filterID).
+		Set("depends_on_filter_id", dependsOnFilterID).
+		Set("fallback_client_id", fallbackClientID).
+		Set("external_download_client_id", toNullInt32(action.ExternalDownloadClientID)).
+		Set("external_download_client", toNullString(action.ExternalDownloadClient)).
+		Set("run_condition", toNullString(action.RunCondition)).
 		Where("id = ?", action.ID)
 
 	query, args, err := queryBuilder.ToSql()
@@ -490,6 +651,8 @@ func (r *ActionRepo) Update(ctx context.Context, action domain.Action) (*domain.
 }
 
 func (r *ActionRepo) StoreFilterActions(ctx context.Context, actions []*domain.Action, filterID int64) ([]*domain.Action, error) {
+	defer r.resetFilterDependencies()
+
 	tx, err := r.db.handler.BeginTx(ctx, nil)
 	if err != nil {
 		return nil, errors.Wrap(err, "error begin transaction")
@@ -518,6 +681,7 @@ func (r *ActionRepo) StoreFilterActions(ctx context.Context, actions []*domain.A
 		tags := toNullString(action.Tags)
 		label := toNullString(action.Label)
 		savePath := toNullString(action.SavePath)
+		moveCompletedPath := toNullString(action.MoveCompletedPath)
 		contentLayout := toNullString(string(action.ContentLayout))
 		webhookHost := toNullString(action.WebhookHost)
 		webhookType := toNullString(action.WebhookType)
@@ -529,6 +693,8 @@ func (r *ActionRepo) StoreFilterActions(ctx context.Context, actions []*domain.A
 		limitRatio := toNullFloat64(action.LimitRatio)
 		limitSeedTime := toNullInt64(action.LimitSeedTime)
 		clientID := toNullInt32(action.ClientID)
+		dependsOnFilterID := toNullInt32(int32(action.DependsOnFilterID))
+		fallbackClientID := toNullInt32(action.FallbackClientID)
 
 		queryBuilder := r.db.squirrel.
 			Insert("action").
@@ -538,11 +704,16 @@ func (r *ActionRepo) StoreFilterActions(ctx context.Context, actions []*domain.A
 				"enabled",
 				"exec_cmd",
 				"exec_args",
+				"exec_timeout",
+				"bandwidth_priority",
+				"bandwidth_group",
+				"sequential_download",
 				"watch_folder",
 				"category",
 				"tags",
 				"label",
 				"save_path",
+				"move_completed_path",
 				"paused",
 				"ignore_rules",
 				"skip_hash_check",
@@ -561,6 +732,11 @@ func (r *ActionRepo) StoreFilterActions(ctx context.Context, actions []*domain.A
 				"webhook_data",
 				"client_id",
 				"filter_id",
+				"depends_on_filter_id",
+				"fallback_client_id",
+				"external_download_client_id",
+				"external_download_client",
+				"run_condition",
 			).
 			Values(
 				action.Name,
@@ -568,11 +744,16 @@ func (r *ActionRepo) StoreFilterActions(ctx context.Context, actions []*domain.A
 				action.Enabled,
 				execCmd,
 				execArgs,
+				action.ExecTimeout,
+				action.BandwidthPriority,
+				toNullString(action.BandwidthGroup),
+				action.SequentialDownload,
 				watchFolder,
 				category,
 				tags,
 				label,
 				savePath,
+				moveCompletedPath,
 				action.Paused,
 				action.IgnoreRules,
 				action.SkipHashCheck,
@@ -591,6 +772,11 @@ func (r *ActionRepo) StoreFilterActions(ctx context.Context, actions []*domain.A
 				webhookData,
 				clientID,
 				filterID,
+				dependsOnFilterID,
+				fallbackClientID,
+				toNullInt32(action.ExternalDownloadClientID),
+				toNullString(action.ExternalDownloadClient),
+				toNullString(action.RunCondition),
 			).
 			Suffix("RETURNING id").RunWith(tx)
 
@@ -617,6 +803,8 @@ func (r *ActionRepo) StoreFilterActions(ctx context.Context, actions []*domain.A
 }
 
 func (r *ActionRepo) ToggleEnabled(actionID int) error {
+	defer r.resetFilterDependencies()
+
 	var err error
 
 	queryBuilder := r.db.squirrel.
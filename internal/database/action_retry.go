@@ -0,0 +1,231 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/internal/logger"
+	"github.com/autobrr/autobrr/pkg/errors"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/rs/zerolog"
+)
+
+type ActionRetryRepo struct {
+	log zerolog.Logger
+	db  *DB
+}
+
+func NewActionRetryRepo(log logger.Logger, db *DB) domain.ActionRetryRepo {
+	return &ActionRetryRepo{
+		log: log.With().Str("repo", "action_retry").Logger(),
+		db:  db,
+	}
+}
+
+func (r *ActionRetryRepo) selectRetry() sq.SelectBuilder {
+	return r.db.squirrel.
+		Select(
+			"id",
+			"release_id",
+			"filter_id",
+			"action_id",
+			"torrent_name",
+			"indexer",
+			"filter",
+			"action",
+			"action_type",
+			"status",
+			"attempts",
+			"last_error",
+			"next_attempt_at",
+			"expires_at",
+			"created_at",
+		).
+		From("action_retry")
+}
+
+func (r *ActionRetryRepo) List(ctx context.Context) ([]*domain.ActionRetry, error) {
+	return r.find(ctx, r.selectRetry().OrderBy("created_at DESC"))
+}
+
+// FindDue returns the pending retries with their next attempt in the past
+func (r *ActionRetryRepo) FindDue(ctx context.Context) ([]*domain.ActionRetry, error) {
+	queryBuilder := r.selectRetry().
+		Where(sq.Eq{"status": domain.ActionRetryStatusPending}).
+		Where(sq.LtOrEq{"next_attempt_at": time.Now()}).
+		OrderBy("next_attempt_at")
+
+	return r.find(ctx, queryBuilder)
+}
+
+func (r *ActionRetryRepo) find(ctx context.Context, queryBuilder sq.SelectBuilder) ([]*domain.ActionRetry, error) {
+	query, args, err := queryBuilder.ToSql()
+	if err != nil {
+		return nil, errors.Wrap(err, "error building query")
+	}
+
+	rows, err := r.db.handler.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, errors.Wrap(err, "error executing query")
+	}
+
+	defer rows.Close()
+
+	retries := make([]*domain.ActionRetry, 0)
+	for rows.Next() {
+		retry, err := scanActionRetry(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		retries = append(retries, retry)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errors.Wrap(err, "error rows list")
+	}
+
+	return retries, nil
+}
+
+func (r *ActionRetryRepo) FindByID(ctx context.Context, id int) (*domain.ActionRetry, error) {
+	query, args, err := r.selectRetry().Where(sq.Eq{"id": id}).ToSql()
+	if err != nil {
+		return nil, errors.Wrap(err, "error building query")
+	}
+
+	row := r.db.handler.QueryRowContext(ctx, query, args...)
+	if err := row.Err(); err != nil {
+		return nil, errors.Wrap(err, "error executing query")
+	}
+
+	retry, err := scanActionRetry(row)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errors.New("action retry not found: %v", id)
+		}
+
+		return nil, err
+	}
+
+	return retry, nil
+}
+
+type actionRetryScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanActionRetry(row actionRetryScanner) (*domain.ActionRetry, error) {
+	var a domain.ActionRetry
+
+	var filterID sql.NullInt32
+	var torrentName, indexer, filter, action, actionType, lastError sql.NullString
+
+	if err := row.Scan(&a.ID, &a.ReleaseID, &filterID, &a.ActionID, &torrentName, &indexer, &filter, &action, &actionType, &a.Status, &a.Attempts, &lastError, &a.NextAttemptAt, &a.ExpiresAt, &a.CreatedAt); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, err
+		}
+
+		return nil, errors.Wrap(err, "error scanning row")
+	}
+
+	a.FilterID = int(filterID.Int32)
+	a.TorrentName = torrentName.String
+	a.Indexer = indexer.String
+	a.Filter = filter.String
+	a.Action = action.String
+	a.ActionType = domain.ActionType(actionType.String)
+	a.LastError = lastError.String
+
+	return &a, nil
+}
+
+func (r *ActionRetryRepo) Store(ctx context.Context, retry *domain.ActionRetry) error {
+	queryBuilder := r.db.squirrel.
+		Insert("action_retry").
+		Columns(
+			"release_id",
+			"filter_id",
+			"action_id",
+			"torrent_name",
+			"indexer",
+			"filter",
+			"action",
+			"action_type",
+			"status",
+			"attempts",
+			"last_error",
+			"next_attempt_at",
+			"expires_at",
+		).
+		Values(
+			retry.ReleaseID,
+			toNullInt32(int32(retry.FilterID)),
+			retry.ActionID,
+			toNullString(retry.TorrentName),
+			toNullString(retry.Indexer),
+			toNullString(retry.Filter),
+			toNullString(retry.Action),
+			toNullString(string(retry.ActionType)),
+			retry.Status,
+			retry.Attempts,
+			toNullString(retry.LastError),
+			retry.NextAttemptAt,
+			retry.ExpiresAt,
+		).
+		Suffix("RETURNING id").RunWith(r.db.handler)
+
+	var retID int
+
+	if err := queryBuilder.QueryRowContext(ctx).Scan(&retID); err != nil {
+		return errors.Wrap(err, "error executing query")
+	}
+
+	retry.ID = retID
+
+	r.log.Debug().Msgf("action_retry.store: added new %v", retID)
+
+	return nil
+}
+
+func (r *ActionRetryRepo) Update(ctx context.Context, retry *domain.ActionRetry) error {
+	queryBuilder := r.db.squirrel.
+		Update("action_retry").
+		Set("status", retry.Status).
+		Set("attempts", retry.Attempts).
+		Set("last_error", toNullString(retry.LastError)).
+		Set("next_attempt_at", retry.NextAttemptAt).
+		Set("expires_at", retry.ExpiresAt).
+		Where(sq.Eq{"id": retry.ID})
+
+	query, args, err := queryBuilder.ToSql()
+	if err != nil {
+		return errors.Wrap(err, "error building query")
+	}
+
+	if _, err = r.db.handler.ExecContext(ctx, query, args...); err != nil {
+		return errors.Wrap(err, "error executing query")
+	}
+
+	return nil
+}
+
+func (r *ActionRetryRepo) Delete(ctx context.Context, id int) error {
+	query, args, err := r.db.squirrel.
+		Delete("action_retry").
+		Where(sq.Eq{"id": id}).
+		ToSql()
+	if err != nil {
+		return errors.Wrap(err, "error building query")
+	}
+
+	if _, err = r.db.handler.ExecContext(ctx, query, args...); err != nil {
+		return errors.Wrap(err, "error executing query")
+	}
+
+	r.log.Debug().Msgf("action_retry.delete: %v", id)
+
+	return nil
+}
@@ -0,0 +1,213 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/internal/logger"
+	"github.com/autobrr/autobrr/pkg/errors"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/rs/zerolog"
+)
+
+type AnnounceBlocklistRepo struct {
+	log zerolog.Logger
+	db  *DB
+}
+
+func NewAnnounceBlocklistRepo(log logger.Logger, db *DB) domain.AnnounceBlocklistRepo {
+	return &AnnounceBlocklistRepo{
+		log: log.With().Str("repo", "announce_blocklist").Logger(),
+		db:  db,
+	}
+}
+
+func (r *AnnounceBlocklistRepo) selectRule() sq.SelectBuilder {
+	return r.db.squirrel.
+		Select(
+			"id",
+			"name",
+			"enabled",
+			"type",
+			"value",
+			"indexer",
+			"hits",
+			"last_hit_at",
+			"created_at",
+			"updated_at",
+		).
+		From("announce_blocklist")
+}
+
+func (r *AnnounceBlocklistRepo) List(ctx context.Context) ([]domain.AnnounceBlocklistRule, error) {
+	query, args, err := r.selectRule().OrderBy("name").ToSql()
+	if err != nil {
+		return nil, errors.Wrap(err, "error building query")
+	}
+
+	rows, err := r.db.handler.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, errors.Wrap(err, "error executing query")
+	}
+
+	defer rows.Close()
+
+	rules := make([]domain.AnnounceBlocklistRule, 0)
+	for rows.Next() {
+		rule, err := scanAnnounceBlocklistRule(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		rules = append(rules, *rule)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errors.Wrap(err, "error rows list")
+	}
+
+	return rules, nil
+}
+
+func (r *AnnounceBlocklistRepo) FindByID(ctx context.Context, id int) (*domain.AnnounceBlocklistRule, error) {
+	query, args, err := r.selectRule().Where(sq.Eq{"id": id}).ToSql()
+	if err != nil {
+		return nil, errors.Wrap(err, "error building query")
+	}
+
+	row := r.db.handler.QueryRowContext(ctx, query, args...)
+	if err := row.Err(); err != nil {
+		return nil, errors.Wrap(err, "error executing query")
+	}
+
+	rule, err := scanAnnounceBlocklistRule(row)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errors.New("announce blocklist rule not found: %v", id)
+		}
+
+		return nil, err
+	}
+
+	return rule, nil
+}
+
+type announceBlocklistScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanAnnounceBlocklistRule(row announceBlocklistScanner) (*domain.AnnounceBlocklistRule, error) {
+	var rule domain.AnnounceBlocklistRule
+
+	var indexer sql.NullString
+	var lastHitAt sql.NullTime
+
+	if err := row.Scan(&rule.ID, &rule.Name, &rule.Enabled, &rule.Type, &rule.Value, &indexer, &rule.Hits, &lastHitAt, &rule.CreatedAt, &rule.UpdatedAt); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, err
+		}
+
+		return nil, errors.Wrap(err, "error scanning row")
+	}
+
+	rule.Indexer = indexer.String
+	if lastHitAt.Valid {
+		rule.LastHitAt = &lastHitAt.Time
+	}
+
+	return &rule, nil
+}
+
+func (r *AnnounceBlocklistRepo) Store(ctx context.Context, rule *domain.AnnounceBlocklistRule) error {
+	queryBuilder := r.db.squirrel.
+		Insert("announce_blocklist").
+		Columns("name", "enabled", "type", "value", "indexer").
+		Values(rule.Name, rule.Enabled, rule.Type, rule.Value, toNullString(rule.Indexer)).
+		Suffix("RETURNING id, created_at, updated_at").RunWith(r.db.handler)
+
+	if err := queryBuilder.QueryRowContext(ctx).Scan(&rule.ID, &rule.CreatedAt, &rule.UpdatedAt); err != nil {
+		return errors.Wrap(err, "error executing query")
+	}
+
+	r.log.Debug().Msgf("announce_blocklist.store: added new %v", rule.ID)
+
+	return nil
+}
+
+func (r *AnnounceBlocklistRepo) Update(ctx context.Context, rule *domain.AnnounceBlocklistRule) error {
+	query, args, err := r.db.squirrel.
+		Update("announce_blocklist").
+		Set("name", rule.Name).
+		Set("enabled", rule.Enabled).
+		Set("type", rule.Type).
+		Set("value", rule.Value).
+		Set("indexer", toNullString(rule.Indexer)).
+		Set("updated_at", sq.Expr("CURRENT_TIMESTAMP")).
+		Where(sq.Eq{"id": rule.ID}).
+		ToSql()
+	if err != nil {
+		return errors.Wrap(err, "error building query")
+	}
+
+	if _, err = r.db.handler.ExecContext(ctx, query, args...); err != nil {
+		return errors.Wrap(err, "error executing query")
+	}
+
+	r.log.Debug().Msgf("announce_blocklist.update: %v", rule.Name)
+
+	return nil
+}
+
+func (r *AnnounceBlocklistRepo) Delete(ctx context.Context, id int) error {
+	query, args, err := r.db.squirrel.
+		Delete("announce_blocklist").
+		Where(sq.Eq{"id": id}).
+		ToSql()
+	if err != nil {
+		return errors.Wrap(err, "error building query")
+	}
+
+	if _, err = r.db.handler.ExecContext(ctx, query, args...); err != nil {
+		return errors.Wrap(err, "error executing query")
+	}
+
+	r.log.Debug().Msgf("announce_blocklist.delete: %v", id)
+
+	return nil
+}
+
+func (r *AnnounceBlocklistRepo) AddHits(ctx context.Context, hits []domain.AnnounceBlocklistHits) error {
+	if len(hits) == 0 {
+		return nil
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return errors.Wrap(err, "error begin transaction")
+	}
+
+	defer tx.Rollback()
+
+	for _, h := range hits {
+		query, args, err := r.db.squirrel.
+			Update("announce_blocklist").
+			Set("hits", sq.Expr("hits + ?", h.Count)).
+			Set("last_hit_at", h.LastHitAt).
+			Where(sq.Eq{"id": h.RuleID}).
+			ToSql()
+		if err != nil {
+			return errors.Wrap(err, "error building query")
+		}
+
+		if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+			return errors.Wrap(err, "error executing query")
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return errors.Wrap(err, "error commit transaction")
+	}
+
+	return nil
+}
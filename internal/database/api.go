@@ -33,11 +33,13 @@ func (r *APIRepo) Store(ctx context.Context, key *domain.APIKey) error {
 			"name",
 			"key",
 			"scopes",
+			"expires_at",
 		).
 		Values(
 			key.Name,
 			key.Key,
 			pq.Array(key.Scopes),
+			key.ExpiresAt,
 		).
 		Suffix("RETURNING created_at").RunWith(r.db.handler)
 
@@ -78,6 +80,7 @@ func (r *APIRepo) GetKeys(ctx context.Context) ([]domain.APIKey, error) {
 			"name",
 			"key",
 			"scopes",
+			"expires_at",
 			"created_at",
 		).
 		From("api_key")
@@ -99,13 +102,17 @@ func (r *APIRepo) GetKeys(ctx context.Context) ([]domain.APIKey, error) {
 		var a domain.APIKey
 
 		var name sql.NullString
+		var expiresAt sql.NullTime
 
-		if err := rows.Scan(&name, &a.Key, pq.Array(&a.Scopes), &a.CreatedAt); err != nil {
+		if err := rows.Scan(&name, &a.Key, pq.Array(&a.Scopes), &expiresAt, &a.CreatedAt); err != nil {
 			return nil, errors.Wrap(err, "error scanning row")
 
 		}
 
 		a.Name = name.String
+		if expiresAt.Valid {
+			a.ExpiresAt = &expiresAt.Time
+		}
 
 		keys = append(keys, a)
 	}
@@ -0,0 +1,109 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/internal/logger"
+	"github.com/autobrr/autobrr/pkg/errors"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/rs/zerolog"
+)
+
+type AuditRepo struct {
+	log zerolog.Logger
+	db  *DB
+}
+
+func NewAuditRepo(log logger.Logger, db *DB) domain.AuditRepo {
+	return &AuditRepo{
+		log: log.With().Str("repo", "audit").Logger(),
+		db:  db,
+	}
+}
+
+func (r *AuditRepo) Store(ctx context.Context, entry *domain.AuditEntry) error {
+	changes, err := json.Marshal(entry.Changes)
+	if err != nil {
+		return errors.Wrap(err, "could not marshal changes")
+	}
+
+	queryBuilder := r.db.squirrel.
+		Insert("audit_log").
+		Columns("actor", "action", "entity_type", "entity_id", "entity_name", "changes").
+		Values(entry.Actor, entry.Action, entry.EntityType, toNullString(entry.EntityID), toNullString(entry.EntityName), string(changes)).
+		Suffix("RETURNING id, created_at").RunWith(r.db.handler)
+
+	if err := queryBuilder.QueryRowContext(ctx).Scan(&entry.ID, &entry.CreatedAt); err != nil {
+		return errors.Wrap(err, "error executing query")
+	}
+
+	return nil
+}
+
+// Find returns the entries matching the params, newest first
+func (r *AuditRepo) Find(ctx context.Context, params domain.AuditQueryParams) ([]domain.AuditEntry, error) {
+	queryBuilder := r.db.squirrel.
+		Select("id", "actor", "action", "entity_type", "entity_id", "entity_name", "changes", "created_at").
+		From("audit_log").
+		OrderBy("created_at DESC", "id DESC")
+
+	if params.EntityType != "" {
+		queryBuilder = queryBuilder.Where(sq.Eq{"entity_type": params.EntityType})
+	}
+	if params.EntityID != "" {
+		queryBuilder = queryBuilder.Where(sq.Eq{"entity_id": params.EntityID})
+	}
+	if params.Actor != "" {
+		queryBuilder = queryBuilder.Where(sq.Eq{"actor": params.Actor})
+	}
+	if params.Limit > 0 {
+		queryBuilder = queryBuilder.Limit(params.Limit)
+	}
+	if params.Offset > 0 {
+		queryBuilder = queryBuilder.Offset(params.Offset)
+	}
+
+	query, args, err := queryBuilder.ToSql()
+	if err != nil {
+		return nil, errors.Wrap(err, "error building query")
+	}
+
+	rows, err := r.db.handler.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, errors.Wrap(err, "error executing query")
+	}
+
+	defer rows.Close()
+
+	entries := make([]domain.AuditEntry, 0)
+	for rows.Next() {
+		var e domain.AuditEntry
+		var entityID, entityName, changes sql.NullString
+
+		if err := rows.Scan(&e.ID, &e.Actor, &e.Action, &e.EntityType, &entityID, &entityName, &changes, &e.CreatedAt); err != nil {
+			return nil, errors.Wrap(err, "error scanning row")
+		}
+
+		e.EntityID = entityID.String
+		e.EntityName = entityName.String
+		e.Changes = []domain.AuditChange{}
+
+		if changes.String != "" {
+			if err := json.Unmarshal([]byte(changes.String), &e.Changes); err != nil {
+				return nil, errors.Wrap(err, "could not unmarshal changes")
+			}
+		}
+
+		entries = append(entries, e)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, errors.Wrap(err, "row error")
+	}
+
+	return entries, nil
+}
@@ -0,0 +1,145 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"time"
+
+	"github.com/autobrr/autobrr/pkg/errors"
+)
+
+// BackupExtension is the file extension of backups made by Backup
+func (db *DB) BackupExtension() string {
+	if db.Driver == "postgres" {
+		return ".sql"
+	}
+
+	return ".db"
+}
+
+// Backup writes a consistent copy of the database to dst. Sqlite uses VACUUM INTO which is safe while
+// the database is in use, postgres shells out to pg_dump and writes a plain sql export.
+// The copy is written next to dst first and renamed so a failed backup never leaves a partial file.
+func (db *DB) Backup(ctx context.Context, dst string) error {
+	tmp := dst + ".tmp"
+
+	var err error
+	switch db.Driver {
+	case "sqlite":
+		_, err = db.handler.ExecContext(ctx, `VACUUM INTO ?`, tmp)
+	case "postgres":
+		err = db.pgDump(ctx, tmp)
+	default:
+		err = errors.New("unsupported database: %v", db.Driver)
+	}
+
+	if err != nil {
+		os.Remove(tmp)
+		return errors.Wrap(err, "could not backup database")
+	}
+
+	if err := os.Rename(tmp, dst); err != nil {
+		os.Remove(tmp)
+		return errors.Wrap(err, "could not move backup into place")
+	}
+
+	return nil
+}
+
+func (db *DB) pgDump(ctx context.Context, dst string) error {
+	u, err := url.Parse(db.DSN)
+	if err != nil {
+		return errors.Wrap(err, "could not parse dsn")
+	}
+
+	// pass the password through the environment to keep it out of the process list
+	password, _ := u.User.Password()
+	u.User = url.User(u.User.Username())
+
+	cmd := exec.CommandContext(ctx, "pg_dump", "--format=plain", "--no-owner", "--no-privileges", "--file="+dst, "--dbname="+u.String())
+	cmd.Env = append(os.Environ(), "PGPASSWORD="+password)
+
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return errors.Wrap(err, "pg_dump failed: %s", out)
+	}
+
+	return nil
+}
+
+// restoreSQLite replaces the database with autobrr.db.restore when it exists in the config directory.
+// The restore file is checked before anything is touched and the current database is kept as autobrr.db.pre-restore-<time>.
+func (db *DB) restoreSQLite() error {
+	restore := db.DSN + ".restore"
+
+	if _, err := os.Stat(restore); err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return errors.Wrap(err, "could not stat restore file: %v", restore)
+	}
+
+	db.log.Info().Msgf("found database restore file: %v", restore)
+
+	if err := validateSQLiteBackup(restore, len(sqliteMigrations)); err != nil {
+		return errors.Wrap(err, "invalid restore file %v, remove or replace it to start", restore)
+	}
+
+	if _, err := os.Stat(db.DSN); err == nil {
+		previous := fmt.Sprintf("%v.pre-restore-%v", db.DSN, time.Now().Format("20060102-150405"))
+
+		// the wal and shared memory files belong to the old database and must move with it
+		for _, suffix := range []string{"", "-wal", "-shm"} {
+			if err := os.Rename(db.DSN+suffix, previous+suffix); err != nil && !os.IsNotExist(err) {
+				return errors.Wrap(err, "could not move current database")
+			}
+		}
+
+		db.log.Info().Msgf("current database moved to: %v", filepath.Base(previous))
+	}
+
+	if err := os.Rename(restore, db.DSN); err != nil {
+		return errors.Wrap(err, "could not move restore file into place")
+	}
+
+	db.log.Info().Msg("database restored from backup")
+
+	return nil
+}
+
+// validateSQLiteBackup checks that path is an intact autobrr database that this version can migrate
+func validateSQLiteBackup(path string, maxVersion int) error {
+	conn, err := sql.Open("sqlite", path)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	var result string
+	if err := conn.QueryRow(`PRAGMA integrity_check`).Scan(&result); err != nil {
+		return errors.Wrap(err, "integrity check failed")
+	}
+
+	if result != "ok" {
+		return errors.New("integrity check failed: %v", result)
+	}
+
+	var version int
+	if err := conn.QueryRow(`PRAGMA user_version`).Scan(&version); err != nil {
+		return errors.Wrap(err, "could not read schema version")
+	}
+
+	if version == 0 {
+		return errors.New("not an autobrr database")
+	}
+
+	if version > maxVersion {
+		return errors.New("schema version %d is newer than this autobrr (version %d)", version, maxVersion)
+	}
+
+	return nil
+}
@@ -2,6 +2,7 @@ package database
 
 import (
 	"context"
+	"database/sql"
 	"encoding/json"
 	"sync"
 
@@ -75,6 +76,9 @@ func (r *DownloadClientRepo) List(ctx context.Context) ([]domain.DownloadClient,
 			"username",
 			"password",
 			"settings",
+			"health_status",
+			"health_error",
+			"health_checked_at",
 		).
 		From("client")
 
@@ -93,11 +97,15 @@ func (r *DownloadClientRepo) List(ctx context.Context) ([]domain.DownloadClient,
 	for rows.Next() {
 		var f domain.DownloadClient
 		var settingsJsonStr string
+		var healthStatus, healthError sql.NullString
+		var healthCheckedAt sql.NullTime
 
-		if err := rows.Scan(&f.ID, &f.Name, &f.Type, &f.Enabled, &f.Host, &f.Port, &f.TLS, &f.TLSSkipVerify, &f.Username, &f.Password, &settingsJsonStr); err != nil {
+		if err := rows.Scan(&f.ID, &f.Name, &f.Type, &f.Enabled, &f.Host, &f.Port, &f.TLS, &f.TLSSkipVerify, &f.Username, &f.Password, &settingsJsonStr, &healthStatus, &healthError, &healthCheckedAt); err != nil {
 			return clients, errors.Wrap(err, "error scanning row")
 		}
 
+		f.Health = toDownloadClientHealth(healthStatus, healthError, healthCheckedAt)
+
 		if settingsJsonStr != "" {
 			if err := json.Unmarshal([]byte(settingsJsonStr), &f.Settings); err != nil {
 				return clients, errors.Wrap(err, "could not unmarshal download client settings: %v", settingsJsonStr)
@@ -133,6 +141,9 @@ func (r *DownloadClientRepo) FindByID(ctx context.Context, id int32) (*domain.Do
 			"username",
 			"password",
 			"settings",
+			"health_status",
+			"health_error",
+			"health_checked_at",
 		).
 		From("client").
 		Where("id = ?", id)
@@ -149,11 +160,15 @@ func (r *DownloadClientRepo) FindByID(ctx context.Context, id int32) (*domain.Do
 
 	var client domain.DownloadClient
 	var settingsJsonStr string
+	var healthStatus, healthError sql.NullString
+	var healthCheckedAt sql.NullTime
 
-	if err := row.Scan(&client.ID, &client.Name, &client.Type, &client.Enabled, &client.Host, &client.Port, &client.TLS, &client.TLSSkipVerify, &client.Username, &client.Password, &settingsJsonStr); err != nil {
+	if err := row.Scan(&client.ID, &client.Name, &client.Type, &client.Enabled, &client.Host, &client.Port, &client.TLS, &client.TLSSkipVerify, &client.Username, &client.Password, &settingsJsonStr, &healthStatus, &healthError, &healthCheckedAt); err != nil {
 		return nil, errors.Wrap(err, "error scanning row")
 	}
 
+	client.Health = toDownloadClientHealth(healthStatus, healthError, healthCheckedAt)
+
 	if settingsJsonStr != "" {
 		if err := json.Unmarshal([]byte(settingsJsonStr), &client.Settings); err != nil {
 			return nil, errors.Wrap(err, "could not unmarshal download client settings: %v", settingsJsonStr)
@@ -170,6 +185,8 @@ func (r *DownloadClientRepo) Store(ctx context.Context, client domain.DownloadCl
 		APIKey: client.Settings.APIKey,
 		Basic:  client.Settings.Basic,
 		Rules:  client.Settings.Rules,
+
+		WatchFolder: client.Settings.WatchFolder,
 	}
 
 	settingsJson, err := json.Marshal(&settings)
@@ -208,6 +225,8 @@ func (r *DownloadClientRepo) Update(ctx context.Context, client domain.DownloadC
 		APIKey: client.Settings.APIKey,
 		Basic:  client.Settings.Basic,
 		Rules:  client.Settings.Rules,
+
+		WatchFolder: client.Settings.WatchFolder,
 	}
 
 	settingsJson, err := json.Marshal(&settings)
@@ -241,8 +260,8 @@ func (r *DownloadClientRepo) Update(ctx context.Context, client domain.DownloadC
 
 	r.log.Debug().Msgf("download_client.update: %d", client.ID)
 
-	// save to cache
-	r.cache.Set(client.ID, &client)
+	// remove from cache, the client is loaded again with its health on next use
+	r.cache.Pop(client.ID)
 
 	return &client, nil
 }
@@ -274,3 +293,43 @@ func (r *DownloadClientRepo) Delete(ctx context.Context, clientID int) error {
 
 	return nil
 }
+
+func (r *DownloadClientRepo) UpdateHealth(ctx context.Context, clientID int, health domain.DownloadClientHealth) error {
+	queryBuilder := r.db.squirrel.
+		Update("client").
+		Set("health_status", health.Status).
+		Set("health_error", toNullString(health.Error)).
+		Set("health_checked_at", health.CheckedAt).
+		Where("id = ?", clientID)
+
+	query, args, err := queryBuilder.ToSql()
+	if err != nil {
+		return errors.Wrap(err, "error building query")
+	}
+
+	if _, err = r.db.handler.ExecContext(ctx, query, args...); err != nil {
+		return errors.Wrap(err, "error executing query")
+	}
+
+	// remove from cache so the new health is picked up
+	r.cache.Pop(clientID)
+
+	return nil
+}
+
+func toDownloadClientHealth(status sql.NullString, healthError sql.NullString, checkedAt sql.NullTime) domain.DownloadClientHealth {
+	health := domain.DownloadClientHealth{
+		Status: domain.DownloadClientHealthUnknown,
+		Error:  healthError.String,
+	}
+
+	if status.Valid && status.String != "" {
+		health.Status = domain.DownloadClientHealthStatus(status.String)
+	}
+
+	if checkedAt.Valid {
+		health.CheckedAt = &checkedAt.Time
+	}
+
+	return health
+}
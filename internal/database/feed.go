@@ -3,6 +3,7 @@ package database
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 
 	"github.com/autobrr/autobrr/internal/domain"
 	"github.com/autobrr/autobrr/internal/logger"
@@ -34,9 +35,19 @@ func (r *FeedRepo) FindByID(ctx context.Context, id int) (*domain.Feed, error) {
 			"enabled",
 			"url",
 			"interval",
+			"backfill",
 			"api_key",
+			"cookie",
+			"headers",
+			"username",
+			"password",
+			"last_success_at",
+			"last_error",
+			"last_error_at",
 			"created_at",
 			"updated_at",
+			"settings",
+			"(SELECT proxy FROM indexer WHERE indexer.id = feed.indexer_id)",
 		).
 		From("feed").
 		Where("id = ?", id)
@@ -53,14 +64,31 @@ func (r *FeedRepo) FindByID(ctx context.Context, id int) (*domain.Feed, error) {
 
 	var f domain.Feed
 
-	var apiKey sql.NullString
+	var apiKey, cookie, headers, username, password, lastError, settings, proxy sql.NullString
+	var lastSuccess, lastErrorAt sql.NullTime
 
-	if err := row.Scan(&f.ID, &f.Indexer, &f.Name, &f.Type, &f.Enabled, &f.URL, &f.Interval, &apiKey, &f.CreatedAt, &f.UpdatedAt); err != nil {
+	if err := row.Scan(&f.ID, &f.Indexer, &f.Name, &f.Type, &f.Enabled, &f.URL, &f.Interval, &f.Backfill, &apiKey, &cookie, &headers, &username, &password, &lastSuccess, &lastError, &lastErrorAt, &f.CreatedAt, &f.UpdatedAt, &settings, &proxy); err != nil {
 		return nil, errors.Wrap(err, "error scanning row")
 
 	}
 
 	f.ApiKey = apiKey.String
+	f.Cookie = cookie.String
+	f.Headers = headers.String
+	f.Username = username.String
+	f.Password = password.String
+	f.Proxy = proxy.String
+
+	if err := unmarshalFeedSettings(settings.String, &f); err != nil {
+		return nil, err
+	}
+	f.LastError = lastError.String
+	if lastSuccess.Valid {
+		f.LastSuccess = &lastSuccess.Time
+	}
+	if lastErrorAt.Valid {
+		f.LastErrorAt = &lastErrorAt.Time
+	}
 
 	return &f, nil
 }
@@ -75,9 +103,19 @@ func (r *FeedRepo) FindByIndexerIdentifier(ctx context.Context, indexer string)
 			"enabled",
 			"url",
 			"interval",
+			"backfill",
 			"api_key",
+			"cookie",
+			"headers",
+			"username",
+			"password",
+			"last_success_at",
+			"last_error",
+			"last_error_at",
 			"created_at",
 			"updated_at",
+			"settings",
+			"(SELECT proxy FROM indexer WHERE indexer.id = feed.indexer_id)",
 		).
 		From("feed").
 		Where("indexer = ?", indexer)
@@ -94,14 +132,31 @@ func (r *FeedRepo) FindByIndexerIdentifier(ctx context.Context, indexer string)
 
 	var f domain.Feed
 
-	var apiKey sql.NullString
+	var apiKey, cookie, headers, username, password, lastError, settings, proxy sql.NullString
+	var lastSuccess, lastErrorAt sql.NullTime
 
-	if err := row.Scan(&f.ID, &f.Indexer, &f.Name, &f.Type, &f.Enabled, &f.URL, &f.Interval, &apiKey, &f.CreatedAt, &f.UpdatedAt); err != nil {
+	if err := row.Scan(&f.ID, &f.Indexer, &f.Name, &f.Type, &f.Enabled, &f.URL, &f.Interval, &f.Backfill, &apiKey, &cookie, &headers, &username, &password, &lastSuccess, &lastError, &lastErrorAt, &f.CreatedAt, &f.UpdatedAt, &settings, &proxy); err != nil {
 		return nil, errors.Wrap(err, "error scanning row")
 
 	}
 
 	f.ApiKey = apiKey.String
+	f.Cookie = cookie.String
+	f.Headers = headers.String
+	f.Username = username.String
+	f.Password = password.String
+	f.Proxy = proxy.String
+
+	if err := unmarshalFeedSettings(settings.String, &f); err != nil {
+		return nil, err
+	}
+	f.LastError = lastError.String
+	if lastSuccess.Valid {
+		f.LastSuccess = &lastSuccess.Time
+	}
+	if lastErrorAt.Valid {
+		f.LastErrorAt = &lastErrorAt.Time
+	}
 
 	return &f, nil
 }
@@ -116,9 +171,19 @@ func (r *FeedRepo) Find(ctx context.Context) ([]domain.Feed, error) {
 			"enabled",
 			"url",
 			"interval",
+			"backfill",
 			"api_key",
+			"cookie",
+			"headers",
+			"username",
+			"password",
+			"last_success_at",
+			"last_error",
+			"last_error_at",
 			"created_at",
 			"updated_at",
+			"settings",
+			"(SELECT proxy FROM indexer WHERE indexer.id = feed.indexer_id)",
 		).
 		From("feed").
 		OrderBy("name ASC")
@@ -139,14 +204,31 @@ func (r *FeedRepo) Find(ctx context.Context) ([]domain.Feed, error) {
 	for rows.Next() {
 		var f domain.Feed
 
-		var apiKey sql.NullString
+		var apiKey, cookie, headers, username, password, lastError, settings, proxy sql.NullString
+		var lastSuccess, lastErrorAt sql.NullTime
 
-		if err := rows.Scan(&f.ID, &f.Indexer, &f.Name, &f.Type, &f.Enabled, &f.URL, &f.Interval, &apiKey, &f.CreatedAt, &f.UpdatedAt); err != nil {
+		if err := rows.Scan(&f.ID, &f.Indexer, &f.Name, &f.Type, &f.Enabled, &f.URL, &f.Interval, &f.Backfill, &apiKey, &cookie, &headers, &username, &password, &lastSuccess, &lastError, &lastErrorAt, &f.CreatedAt, &f.UpdatedAt, &settings, &proxy); err != nil {
 			return nil, errors.Wrap(err, "error scanning row")
 
 		}
 
 		f.ApiKey = apiKey.String
+		f.Cookie = cookie.String
+		f.Headers = headers.String
+		f.Username = username.String
+		f.Password = password.String
+		f.Proxy = proxy.String
+
+		if err := unmarshalFeedSettings(settings.String, &f); err != nil {
+			return nil, err
+		}
+		f.LastError = lastError.String
+		if lastSuccess.Valid {
+			f.LastSuccess = &lastSuccess.Time
+		}
+		if lastErrorAt.Valid {
+			f.LastErrorAt = &lastErrorAt.Time
+		}
 
 		feeds = append(feeds, f)
 	}
@@ -155,6 +237,11 @@ func (r *FeedRepo) Find(ctx context.Context) ([]domain.Feed, error) {
 }
 
 func (r *FeedRepo) Store(ctx context.Context, feed *domain.Feed) error {
+	settings, err := json.Marshal(feed.Settings)
+	if err != nil {
+		return errors.Wrap(err, "error marshaling settings")
+	}
+
 	queryBuilder := r.db.squirrel.
 		Insert("feed").
 		Columns(
@@ -164,7 +251,13 @@ func (r *FeedRepo) Store(ctx context.Context, feed *domain.Feed) error {
 			"enabled",
 			"url",
 			"interval",
+			"backfill",
 			"api_key",
+			"cookie",
+			"headers",
+			"username",
+			"password",
+			"settings",
 			"indexer_id",
 		).
 		Values(
@@ -174,7 +267,13 @@ func (r *FeedRepo) Store(ctx context.Context, feed *domain.Feed) error {
 			feed.Enabled,
 			feed.URL,
 			feed.Interval,
+			feed.Backfill,
 			feed.ApiKey,
+			feed.Cookie,
+			feed.Headers,
+			feed.Username,
+			feed.Password,
+			settings,
 			feed.IndexerID,
 		).
 		Suffix("RETURNING id").RunWith(r.db.handler)
@@ -191,6 +290,11 @@ func (r *FeedRepo) Store(ctx context.Context, feed *domain.Feed) error {
 }
 
 func (r *FeedRepo) Update(ctx context.Context, feed *domain.Feed) error {
+	settings, err := json.Marshal(feed.Settings)
+	if err != nil {
+		return errors.Wrap(err, "error marshaling settings")
+	}
+
 	queryBuilder := r.db.squirrel.
 		Update("feed").
 		Set("name", feed.Name).
@@ -199,7 +303,13 @@ func (r *FeedRepo) Update(ctx context.Context, feed *domain.Feed) error {
 		Set("enabled", feed.Enabled).
 		Set("url", feed.URL).
 		Set("interval", feed.Interval).
+		Set("backfill", feed.Backfill).
 		Set("api_key", feed.ApiKey).
+		Set("cookie", feed.Cookie).
+		Set("headers", feed.Headers).
+		Set("username", feed.Username).
+		Set("password", feed.Password).
+		Set("settings", settings).
 		Where("id = ?", feed.ID)
 
 	query, args, err := queryBuilder.ToSql()
@@ -236,6 +346,34 @@ func (r *FeedRepo) ToggleEnabled(ctx context.Context, id int, enabled bool) erro
 	return nil
 }
 
+// UpdateLastRun records the result of the latest poll, an empty error marks it successful
+func (r *FeedRepo) UpdateLastRun(ctx context.Context, id int, lastError string) error {
+	queryBuilder := r.db.squirrel.
+		Update("feed").
+		Where("id = ?", id)
+
+	if lastError == "" {
+		queryBuilder = queryBuilder.
+			Set("last_success_at", sq.Expr("CURRENT_TIMESTAMP")).
+			Set("last_error", "")
+	} else {
+		queryBuilder = queryBuilder.
+			Set("last_error", lastError).
+			Set("last_error_at", sq.Expr("CURRENT_TIMESTAMP"))
+	}
+
+	query, args, err := queryBuilder.ToSql()
+	if err != nil {
+		return errors.Wrap(err, "error building query")
+	}
+
+	if _, err = r.db.handler.ExecContext(ctx, query, args...); err != nil {
+		return errors.Wrap(err, "error executing query")
+	}
+
+	return nil
+}
+
 func (r *FeedRepo) Delete(ctx context.Context, id int) error {
 	queryBuilder := r.db.squirrel.
 		Delete("feed").
@@ -255,3 +393,15 @@ func (r *FeedRepo) Delete(ctx context.Context, id int) error {
 
 	return nil
 }
+
+func unmarshalFeedSettings(settings string, f *domain.Feed) error {
+	if settings == "" {
+		return nil
+	}
+
+	if err := json.Unmarshal([]byte(settings), &f.Settings); err != nil {
+		return errors.Wrap(err, "error unmarshal settings")
+	}
+
+	return nil
+}
@@ -188,6 +188,8 @@ func (r *FilterRepo) FindByID(ctx context.Context, filterID int) (*domain.Filter
 			"freeleech",
 			"freeleech_percent",
 			"shows",
+			"match_list_id",
+			"except_list_id",
 			"seasons",
 			"episodes",
 			"resolutions",
@@ -202,6 +204,7 @@ func (r *FilterRepo) FindByID(ctx context.Context, filterID int) (*domain.Filter
 			"artists",
 			"albums",
 			"release_types_match",
+			"release_types_ignore",
 			"formats",
 			"quality",
 			"media",
@@ -215,6 +218,22 @@ func (r *FilterRepo) FindByID(ctx context.Context, filterID int) (*domain.Filter
 			"except_uploaders",
 			"tags",
 			"except_tags",
+			"capture_patterns",
+			"smart_delay",
+			"smart_delay_indexers",
+			"smart_delay_prefer_size",
+			"dedup_key",
+			"dedup_window",
+			"arr_skip_duplicates",
+			"arr_only_monitored",
+			"arr_season_pack_threshold",
+			"schedule",
+			"torrent_file_check",
+			"require_approval",
+			"min_files",
+			"max_files",
+			"match_file_extensions",
+			"except_file_extensions",
 			"origins",
 			"except_origins",
 			"external_script_enabled",
@@ -225,6 +244,8 @@ func (r *FilterRepo) FindByID(ctx context.Context, filterID int) (*domain.Filter
 			"external_webhook_host",
 			"external_webhook_data",
 			"external_webhook_expect_status",
+			"external_webhook_type",
+			"external_webhook_parse_body",
 			"created_at",
 			"updated_at",
 		).
@@ -242,11 +263,12 @@ func (r *FilterRepo) FindByID(ctx context.Context, filterID int) (*domain.Filter
 	}
 
 	var f domain.Filter
-	var minSize, maxSize, maxDownloadsUnit, matchReleases, exceptReleases, matchReleaseGroups, exceptReleaseGroups, freeleechPercent, shows, seasons, episodes, years, artists, albums, matchCategories, exceptCategories, matchUploaders, exceptUploaders, tags, exceptTags, extScriptCmd, extScriptArgs, extWebhookHost, extWebhookData sql.NullString
-	var useRegex, scene, freeleech, hasLog, hasCue, perfectFlac, extScriptEnabled, extWebhookEnabled sql.NullBool
-	var delay, maxDownloads, logScore, extWebhookStatus, extScriptStatus sql.NullInt32
+	var minSize, maxSize, maxDownloadsUnit, matchReleases, exceptReleases, matchReleaseGroups, exceptReleaseGroups, freeleechPercent, shows, seasons, episodes, years, artists, albums, matchCategories, exceptCategories, matchUploaders, exceptUploaders, tags, exceptTags, capturePatterns, smartDelayIndexers, smartDelayPreferSize, dedupKey, extScriptCmd, extScriptArgs, extWebhookHost, extWebhookData, extWebhookType, matchFileExtensions, exceptFileExtensions, schedule sql.NullString
+	var useRegex, scene, freeleech, hasLog, hasCue, perfectFlac, extScriptEnabled, extWebhookEnabled, extWebhookParseBody, arrSkipDuplicates, arrOnlyMonitored, torrentFileCheck, requireApproval sql.NullBool
+	var delay, maxDownloads, logScore, smartDelay, dedupWindow, arrSeasonPackThreshold, extWebhookStatus, extScriptStatus, minFiles, maxFiles sql.NullInt32
+	var matchListID, exceptListID sql.NullInt64
 
-	if err := row.Scan(&f.ID, &f.Enabled, &f.Name, &minSize, &maxSize, &delay, &f.Priority, &maxDownloads, &maxDownloadsUnit, &matchReleases, &exceptReleases, &useRegex, &matchReleaseGroups, &exceptReleaseGroups, &scene, &freeleech, &freeleechPercent, &shows, &seasons, &episodes, pq.Array(&f.Resolutions), pq.Array(&f.Codecs), pq.Array(&f.Sources), pq.Array(&f.Containers), pq.Array(&f.MatchHDR), pq.Array(&f.ExceptHDR), pq.Array(&f.MatchOther), pq.Array(&f.ExceptOther), &years, &artists, &albums, pq.Array(&f.MatchReleaseTypes), pq.Array(&f.Formats), pq.Array(&f.Quality), pq.Array(&f.Media), &logScore, &hasLog, &hasCue, &perfectFlac, &matchCategories, &exceptCategories, &matchUploaders, &exceptUploaders, &tags, &exceptTags, pq.Array(&f.Origins), pq.Array(&f.ExceptOrigins), &extScriptEnabled, &extScriptCmd, &extScriptArgs, &extScriptStatus, &extWebhookEnabled, &extWebhookHost, &extWebhookData, &extWebhookStatus, &f.CreatedAt, &f.UpdatedAt); err != nil {
+	if err := row.Scan(&f.ID, &f.Enabled, &f.Name, &minSize, &maxSize, &delay, &f.Priority, &maxDownloads, &maxDownloadsUnit, &matchReleases, &exceptReleases, &useRegex, &matchReleaseGroups, &exceptReleaseGroups, &scene, &freeleech, &freeleechPercent, &shows, &matchListID, &exceptListID, &seasons, &episodes, pq.Array(&f.Resolutions), pq.Array(&f.Codecs), pq.Array(&f.Sources), pq.Array(&f.Containers), pq.Array(&f.MatchHDR), pq.Array(&f.ExceptHDR), pq.Array(&f.MatchOther), pq.Array(&f.ExceptOther), &years, &artists, &albums, pq.Array(&f.MatchReleaseTypes), pq.Array(&f.ExceptReleaseTypes), pq.Array(&f.Formats), pq.Array(&f.Quality), pq.Array(&f.Media), &logScore, &hasLog, &hasCue, &perfectFlac, &matchCategories, &exceptCategories, &matchUploaders, &exceptUploaders, &tags, &exceptTags, &capturePatterns, &smartDelay, &smartDelayIndexers, &smartDelayPreferSize, &dedupKey, &dedupWindow, &arrSkipDuplicates, &arrOnlyMonitored, &arrSeasonPackThreshold, &schedule, &torrentFileCheck, &requireApproval, &minFiles, &maxFiles, &matchFileExtensions, &exceptFileExtensions, pq.Array(&f.Origins), pq.Array(&f.ExceptOrigins), &extScriptEnabled, &extScriptCmd, &extScriptArgs, &extScriptStatus, &extWebhookEnabled, &extWebhookHost, &extWebhookData, &extWebhookStatus, &extWebhookType, &extWebhookParseBody, &f.CreatedAt, &f.UpdatedAt); err != nil {
 		return nil, errors.Wrap(err, "error scanning row")
 	}
 
@@ -261,6 +283,8 @@ func (r *FilterRepo) FindByID(ctx context.Context, filterID int) (*domain.Filter
 	f.ExceptReleaseGroups = exceptReleaseGroups.String
 	f.FreeleechPercent = freeleechPercent.String
 	f.Shows = shows.String
+	f.MatchListID = matchListID.Int64
+	f.ExceptListID = exceptListID.Int64
 	f.Seasons = seasons.String
 	f.Episodes = episodes.String
 	f.Years = years.String
@@ -276,6 +300,22 @@ func (r *FilterRepo) FindByID(ctx context.Context, filterID int) (*domain.Filter
 	f.ExceptUploaders = exceptUploaders.String
 	f.Tags = tags.String
 	f.ExceptTags = exceptTags.String
+	f.CapturePatterns = capturePatterns.String
+	f.SmartDelay = int(smartDelay.Int32)
+	f.SmartDelayIndexers = smartDelayIndexers.String
+	f.SmartDelayPreferSize = domain.FilterSizePreference(smartDelayPreferSize.String)
+	f.DedupKey = domain.FilterDedupKey(dedupKey.String)
+	f.DedupWindow = int(dedupWindow.Int32)
+	f.ArrSkipDuplicates = arrSkipDuplicates.Bool
+	f.ArrOnlyMonitored = arrOnlyMonitored.Bool
+	f.ArrSeasonPackThreshold = int(arrSeasonPackThreshold.Int32)
+	f.Schedule = schedule.String
+	f.TorrentFileCheck = torrentFileCheck.Bool
+	f.RequireApproval = requireApproval.Bool
+	f.MinFiles = int(minFiles.Int32)
+	f.MaxFiles = int(maxFiles.Int32)
+	f.MatchFileExtensions = matchFileExtensions.String
+	f.ExceptFileExtensions = exceptFileExtensions.String
 	f.UseRegex = useRegex.Bool
 	f.Scene = scene.Bool
 	f.Freeleech = freeleech.Bool
@@ -289,6 +329,8 @@ func (r *FilterRepo) FindByID(ctx context.Context, filterID int) (*domain.Filter
 	f.ExternalWebhookHost = extWebhookHost.String
 	f.ExternalWebhookData = extWebhookData.String
 	f.ExternalWebhookExpectStatus = int(extWebhookStatus.Int32)
+	f.ExternalWebhookType = domain.FilterWebhookType(extWebhookType.String)
+	f.ExternalWebhookParseBody = extWebhookParseBody.Bool
 
 	return &f, nil
 }
@@ -339,6 +381,8 @@ func (r *FilterRepo) findByIndexerIdentifier(ctx context.Context, tx *Tx, indexe
 			"f.freeleech",
 			"f.freeleech_percent",
 			"f.shows",
+			"f.match_list_id",
+			"f.except_list_id",
 			"f.seasons",
 			"f.episodes",
 			"f.resolutions",
@@ -353,6 +397,7 @@ func (r *FilterRepo) findByIndexerIdentifier(ctx context.Context, tx *Tx, indexe
 			"f.artists",
 			"f.albums",
 			"f.release_types_match",
+			"f.release_types_ignore",
 			"f.formats",
 			"f.quality",
 			"f.media",
@@ -366,6 +411,22 @@ func (r *FilterRepo) findByIndexerIdentifier(ctx context.Context, tx *Tx, indexe
 			"f.except_uploaders",
 			"f.tags",
 			"f.except_tags",
+			"f.capture_patterns",
+			"f.smart_delay",
+			"f.smart_delay_indexers",
+			"f.smart_delay_prefer_size",
+			"f.dedup_key",
+			"f.dedup_window",
+			"f.arr_skip_duplicates",
+			"f.arr_only_monitored",
+			"f.arr_season_pack_threshold",
+			"f.schedule",
+			"f.torrent_file_check",
+			"f.require_approval",
+			"f.min_files",
+			"f.max_files",
+			"f.match_file_extensions",
+			"f.except_file_extensions",
 			"f.origins",
 			"f.except_origins",
 			"f.external_script_enabled",
@@ -376,6 +437,8 @@ func (r *FilterRepo) findByIndexerIdentifier(ctx context.Context, tx *Tx, indexe
 			"f.external_webhook_host",
 			"f.external_webhook_data",
 			"f.external_webhook_expect_status",
+			"f.external_webhook_type",
+			"f.external_webhook_parse_body",
 			"f.created_at",
 			"f.updated_at",
 		).
@@ -403,11 +466,12 @@ func (r *FilterRepo) findByIndexerIdentifier(ctx context.Context, tx *Tx, indexe
 	for rows.Next() {
 		var f domain.Filter
 
-		var minSize, maxSize, maxDownloadsUnit, matchReleases, exceptReleases, matchReleaseGroups, exceptReleaseGroups, freeleechPercent, shows, seasons, episodes, years, artists, albums, matchCategories, exceptCategories, matchUploaders, exceptUploaders, tags, exceptTags, extScriptCmd, extScriptArgs, extWebhookHost, extWebhookData sql.NullString
-		var useRegex, scene, freeleech, hasLog, hasCue, perfectFlac, extScriptEnabled, extWebhookEnabled sql.NullBool
-		var delay, maxDownloads, logScore, extWebhookStatus, extScriptStatus sql.NullInt32
+		var minSize, maxSize, maxDownloadsUnit, matchReleases, exceptReleases, matchReleaseGroups, exceptReleaseGroups, freeleechPercent, shows, seasons, episodes, years, artists, albums, matchCategories, exceptCategories, matchUploaders, exceptUploaders, tags, exceptTags, capturePatterns, smartDelayIndexers, smartDelayPreferSize, dedupKey, extScriptCmd, extScriptArgs, extWebhookHost, extWebhookData, extWebhookType, matchFileExtensions, exceptFileExtensions, schedule sql.NullString
+		var useRegex, scene, freeleech, hasLog, hasCue, perfectFlac, extScriptEnabled, extWebhookEnabled, extWebhookParseBody, arrSkipDuplicates, arrOnlyMonitored, torrentFileCheck, requireApproval sql.NullBool
+		var delay, maxDownloads, logScore, smartDelay, dedupWindow, arrSeasonPackThreshold, extWebhookStatus, extScriptStatus, minFiles, maxFiles sql.NullInt32
+		var matchListID, exceptListID sql.NullInt64
 
-		if err := rows.Scan(&f.ID, &f.Enabled, &f.Name, &minSize, &maxSize, &delay, &f.Priority, &maxDownloads, &maxDownloadsUnit, &matchReleases, &exceptReleases, &useRegex, &matchReleaseGroups, &exceptReleaseGroups, &scene, &freeleech, &freeleechPercent, &shows, &seasons, &episodes, pq.Array(&f.Resolutions), pq.Array(&f.Codecs), pq.Array(&f.Sources), pq.Array(&f.Containers), pq.Array(&f.MatchHDR), pq.Array(&f.ExceptHDR), pq.Array(&f.MatchOther), pq.Array(&f.ExceptOther), &years, &artists, &albums, pq.Array(&f.MatchReleaseTypes), pq.Array(&f.Formats), pq.Array(&f.Quality), pq.Array(&f.Media), &logScore, &hasLog, &hasCue, &perfectFlac, &matchCategories, &exceptCategories, &matchUploaders, &exceptUploaders, &tags, &exceptTags, pq.Array(&f.Origins), pq.Array(&f.ExceptOrigins), &extScriptEnabled, &extScriptCmd, &extScriptArgs, &extScriptStatus, &extWebhookEnabled, &extWebhookHost, &extWebhookData, &extWebhookStatus, &f.CreatedAt, &f.UpdatedAt); err != nil {
+		if err := rows.Scan(&f.ID, &f.Enabled, &f.Name, &minSize, &maxSize, &delay, &f.Priority, &maxDownloads, &maxDownloadsUnit, &matchReleases, &exceptReleases, &useRegex, &matchReleaseGroups, &exceptReleaseGroups, &scene, &freeleech, &freeleechPercent, &shows, &matchListID, &exceptListID, &seasons, &episodes, pq.Array(&f.Resolutions), pq.Array(&f.Codecs), pq.Array(&f.Sources), pq.Array(&f.Containers), pq.Array(&f.MatchHDR), pq.Array(&f.ExceptHDR), pq.Array(&f.MatchOther), pq.Array(&f.ExceptOther), &years, &artists, &albums, pq.Array(&f.MatchReleaseTypes), pq.Array(&f.ExceptReleaseTypes), pq.Array(&f.Formats), pq.Array(&f.Quality), pq.Array(&f.Media), &logScore, &hasLog, &hasCue, &perfectFlac, &matchCategories, &exceptCategories, &matchUploaders, &exceptUploaders, &tags, &exceptTags, &capturePatterns, &smartDelay, &smartDelayIndexers, &smartDelayPreferSize, &dedupKey, &dedupWindow, &arrSkipDuplicates, &arrOnlyMonitored, &arrSeasonPackThreshold, &schedule, &torrentFileCheck, &requireApproval, &minFiles, &maxFiles, &matchFileExtensions, &exceptFileExtensions, pq.Array(&f.Origins), pq.Array(&f.ExceptOrigins), &extScriptEnabled, &extScriptCmd, &extScriptArgs, &extScriptStatus, &extWebhookEnabled, &extWebhookHost, &extWebhookData, &extWebhookStatus, &extWebhookType, &extWebhookParseBody, &f.CreatedAt, &f.UpdatedAt); err != nil {
 			return nil, errors.Wrap(err, "error scanning row")
 		}
 
@@ -422,6 +486,8 @@ func (r *FilterRepo) findByIndexerIdentifier(ctx context.Context, tx *Tx, indexe
 		f.ExceptReleaseGroups = exceptReleaseGroups.String
 		f.FreeleechPercent = freeleechPercent.String
 		f.Shows = shows.String
+		f.MatchListID = matchListID.Int64
+		f.ExceptListID = exceptListID.Int64
 		f.Seasons = seasons.String
 		f.Episodes = episodes.String
 		f.Years = years.String
@@ -437,6 +503,22 @@ func (r *FilterRepo) findByIndexerIdentifier(ctx context.Context, tx *Tx, indexe
 		f.ExceptUploaders = exceptUploaders.String
 		f.Tags = tags.String
 		f.ExceptTags = exceptTags.String
+		f.CapturePatterns = capturePatterns.String
+		f.SmartDelay = int(smartDelay.Int32)
+		f.SmartDelayIndexers = smartDelayIndexers.String
+		f.SmartDelayPreferSize = domain.FilterSizePreference(smartDelayPreferSize.String)
+		f.DedupKey = domain.FilterDedupKey(dedupKey.String)
+		f.DedupWindow = int(dedupWindow.Int32)
+		f.ArrSkipDuplicates = arrSkipDuplicates.Bool
+		f.ArrOnlyMonitored = arrOnlyMonitored.Bool
+		f.ArrSeasonPackThreshold = int(arrSeasonPackThreshold.Int32)
+		f.Schedule = schedule.String
+		f.TorrentFileCheck = torrentFileCheck.Bool
+		f.RequireApproval = requireApproval.Bool
+		f.MinFiles = int(minFiles.Int32)
+		f.MaxFiles = int(maxFiles.Int32)
+		f.MatchFileExtensions = matchFileExtensions.String
+		f.ExceptFileExtensions = exceptFileExtensions.String
 		f.UseRegex = useRegex.Bool
 		f.Scene = scene.Bool
 		f.Freeleech = freeleech.Bool
@@ -450,6 +532,8 @@ func (r *FilterRepo) findByIndexerIdentifier(ctx context.Context, tx *Tx, indexe
 		f.ExternalWebhookHost = extWebhookHost.String
 		f.ExternalWebhookData = extWebhookData.String
 		f.ExternalWebhookExpectStatus = int(extWebhookStatus.Int32)
+		f.ExternalWebhookType = domain.FilterWebhookType(extWebhookType.String)
+		f.ExternalWebhookParseBody = extWebhookParseBody.Bool
 
 		filters = append(filters, f)
 	}
@@ -478,6 +562,8 @@ func (r *FilterRepo) Store(ctx context.Context, filter domain.Filter) (*domain.F
 			"freeleech",
 			"freeleech_percent",
 			"shows",
+			"match_list_id",
+			"except_list_id",
 			"seasons",
 			"episodes",
 			"resolutions",
@@ -495,9 +581,26 @@ func (r *FilterRepo) Store(ctx context.Context, filter domain.Filter) (*domain.F
 			"except_uploaders",
 			"tags",
 			"except_tags",
+			"capture_patterns",
+			"smart_delay",
+			"smart_delay_indexers",
+			"smart_delay_prefer_size",
+			"dedup_key",
+			"dedup_window",
+			"arr_skip_duplicates",
+			"arr_only_monitored",
+			"arr_season_pack_threshold",
+			"schedule",
+			"torrent_file_check",
+			"require_approval",
+			"min_files",
+			"max_files",
+			"match_file_extensions",
+			"except_file_extensions",
 			"artists",
 			"albums",
 			"release_types_match",
+			"release_types_ignore",
 			"formats",
 			"quality",
 			"media",
@@ -515,6 +618,8 @@ func (r *FilterRepo) Store(ctx context.Context, filter domain.Filter) (*domain.F
 			"external_webhook_host",
 			"external_webhook_data",
 			"external_webhook_expect_status",
+			"external_webhook_type",
+			"external_webhook_parse_body",
 		).
 		Values(
 			filter.Name,
@@ -534,6 +639,8 @@ func (r *FilterRepo) Store(ctx context.Context, filter domain.Filter) (*domain.F
 			filter.Freeleech,
 			filter.FreeleechPercent,
 			filter.Shows,
+			toNullInt64(filter.MatchListID),
+			toNullInt64(filter.ExceptListID),
 			filter.Seasons,
 			filter.Episodes,
 			pq.Array(filter.Resolutions),
@@ -551,9 +658,26 @@ func (r *FilterRepo) Store(ctx context.Context, filter domain.Filter) (*domain.F
 			filter.ExceptUploaders,
 			filter.Tags,
 			filter.ExceptTags,
+			filter.CapturePatterns,
+			filter.SmartDelay,
+			filter.SmartDelayIndexers,
+			filter.SmartDelayPreferSize,
+			filter.DedupKey,
+			filter.DedupWindow,
+			filter.ArrSkipDuplicates,
+			filter.ArrOnlyMonitored,
+			filter.ArrSeasonPackThreshold,
+			toNullString(filter.Schedule),
+			filter.TorrentFileCheck,
+			filter.RequireApproval,
+			filter.MinFiles,
+			filter.MaxFiles,
+			filter.MatchFileExtensions,
+			filter.ExceptFileExtensions,
 			filter.Artists,
 			filter.Albums,
 			pq.Array(filter.MatchReleaseTypes),
+			pq.Array(filter.ExceptReleaseTypes),
 			pq.Array(filter.Formats),
 			pq.Array(filter.Quality),
 			pq.Array(filter.Media),
@@ -571,6 +695,8 @@ func (r *FilterRepo) Store(ctx context.Context, filter domain.Filter) (*domain.F
 			filter.ExternalWebhookHost,
 			filter.ExternalWebhookData,
 			filter.ExternalWebhookExpectStatus,
+			filter.ExternalWebhookType,
+			filter.ExternalWebhookParseBody,
 		).
 		Suffix("RETURNING id").RunWith(r.db.handler)
 
@@ -609,6 +735,8 @@ func (r *FilterRepo) Update(ctx context.Context, filter domain.Filter) (*domain.
 		Set("freeleech", filter.Freeleech).
 		Set("freeleech_percent", filter.FreeleechPercent).
 		Set("shows", filter.Shows).
+		Set("match_list_id", toNullInt64(filter.MatchListID)).
+		Set("except_list_id", toNullInt64(filter.ExceptListID)).
 		Set("seasons", filter.Seasons).
 		Set("episodes", filter.Episodes).
 		Set("resolutions", pq.Array(filter.Resolutions)).
@@ -626,9 +754,26 @@ func (r *FilterRepo) Update(ctx context.Context, filter domain.Filter) (*domain.
 		Set("except_uploaders", filter.ExceptUploaders).
 		Set("tags", filter.Tags).
 		Set("except_tags", filter.ExceptTags).
+		Set("capture_patterns", filter.CapturePatterns).
+		Set("smart_delay", filter.SmartDelay).
+		Set("smart_delay_indexers", filter.SmartDelayIndexers).
+		Set("smart_delay_prefer_size", filter.SmartDelayPreferSize).
+		Set("dedup_key", filter.DedupKey).
+		Set("dedup_window", filter.DedupWindow).
+		Set("arr_skip_duplicates", filter.ArrSkipDuplicates).
+		Set("arr_only_monitored", filter.ArrOnlyMonitored).
+		Set("arr_season_pack_threshold", filter.ArrSeasonPackThreshold).
+		Set("schedule", toNullString(filter.Schedule)).
+		Set("torrent_file_check", filter.TorrentFileCheck).
+		Set("require_approval", filter.RequireApproval).
+		Set("min_files", filter.MinFiles).
+		Set("max_files", filter.MaxFiles).
+		Set("match_file_extensions", filter.MatchFileExtensions).
+		Set("except_file_extensions", filter.ExceptFileExtensions).
 		Set("artists", filter.Artists).
 		Set("albums", filter.Albums).
 		Set("release_types_match", pq.Array(filter.MatchReleaseTypes)).
+		Set("release_types_ignore", pq.Array(filter.ExceptReleaseTypes)).
 		Set("formats", pq.Array(filter.Formats)).
 		Set("quality", pq.Array(filter.Quality)).
 		Set("media", pq.Array(filter.Media)).
@@ -646,6 +791,8 @@ func (r *FilterRepo) Update(ctx context.Context, filter domain.Filter) (*domain.
 		Set("external_webhook_host", filter.ExternalWebhookHost).
 		Set("external_webhook_data", filter.ExternalWebhookData).
 		Set("external_webhook_expect_status", filter.ExternalWebhookExpectStatus).
+		Set("external_webhook_type", filter.ExternalWebhookType).
+		Set("external_webhook_parse_body", filter.ExternalWebhookParseBody).
 		Set("updated_at", time.Now().Format(time.RFC3339)).
 		Where("id = ?", filter.ID)
 
@@ -718,6 +865,12 @@ func (r *FilterRepo) UpdatePartial(ctx context.Context, filter domain.FilterUpda
 	if filter.Shows != nil {
 		q = q.Set("shows", filter.Shows)
 	}
+	if filter.MatchListID != nil {
+		q = q.Set("match_list_id", toNullInt64(*filter.MatchListID))
+	}
+	if filter.ExceptListID != nil {
+		q = q.Set("except_list_id", toNullInt64(*filter.ExceptListID))
+	}
 	if filter.Seasons != nil {
 		q = q.Set("seasons", filter.Seasons)
 	}
@@ -769,6 +922,54 @@ func (r *FilterRepo) UpdatePartial(ctx context.Context, filter domain.FilterUpda
 	if filter.ExceptTags != nil {
 		q = q.Set("except_tags", filter.ExceptTags)
 	}
+	if filter.CapturePatterns != nil {
+		q = q.Set("capture_patterns", filter.CapturePatterns)
+	}
+	if filter.SmartDelay != nil {
+		q = q.Set("smart_delay", filter.SmartDelay)
+	}
+	if filter.SmartDelayIndexers != nil {
+		q = q.Set("smart_delay_indexers", filter.SmartDelayIndexers)
+	}
+	if filter.SmartDelayPreferSize != nil {
+		q = q.Set("smart_delay_prefer_size", filter.SmartDelayPreferSize)
+	}
+	if filter.DedupKey != nil {
+		q = q.Set("dedup_key", filter.DedupKey)
+	}
+	if filter.DedupWindow != nil {
+		q = q.Set("dedup_window", filter.DedupWindow)
+	}
+	if filter.ArrSkipDuplicates != nil {
+		q = q.Set("arr_skip_duplicates", filter.ArrSkipDuplicates)
+	}
+	if filter.ArrOnlyMonitored != nil {
+		q = q.Set("arr_only_monitored", filter.ArrOnlyMonitored)
+	}
+	if filter.ArrSeasonPackThreshold != nil {
+		q = q.Set("arr_season_pack_threshold", filter.ArrSeasonPackThreshold)
+	}
+	if filter.Schedule != nil {
+		q = q.Set("schedule", toNullString(*filter.Schedule))
+	}
+	if filter.TorrentFileCheck != nil {
+		q = q.Set("torrent_file_check", filter.TorrentFileCheck)
+	}
+	if filter.RequireApproval != nil {
+		q = q.Set("require_approval", filter.RequireApproval)
+	}
+	if filter.MinFiles != nil {
+		q = q.Set("min_files", filter.MinFiles)
+	}
+	if filter.MaxFiles != nil {
+		q = q.Set("max_files", filter.MaxFiles)
+	}
+	if filter.MatchFileExtensions != nil {
+		q = q.Set("match_file_extensions", filter.MatchFileExtensions)
+	}
+	if filter.ExceptFileExtensions != nil {
+		q = q.Set("except_file_extensions", filter.ExceptFileExtensions)
+	}
 	if filter.Artists != nil {
 		q = q.Set("artists", filter.Artists)
 	}
@@ -778,6 +979,9 @@ func (r *FilterRepo) UpdatePartial(ctx context.Context, filter domain.FilterUpda
 	if filter.MatchReleaseTypes != nil {
 		q = q.Set("release_types_match", pq.Array(filter.MatchReleaseTypes))
 	}
+	if filter.ExceptReleaseTypes != nil {
+		q = q.Set("release_types_ignore", pq.Array(filter.ExceptReleaseTypes))
+	}
 	if filter.Formats != nil {
 		q = q.Set("formats", pq.Array(filter.Formats))
 	}
@@ -829,6 +1033,12 @@ func (r *FilterRepo) UpdatePartial(ctx context.Context, filter domain.FilterUpda
 	if filter.ExternalWebhookExpectStatus != nil {
 		q = q.Set("external_webhook_expect_status", filter.ExternalWebhookExpectStatus)
 	}
+	if filter.ExternalWebhookType != nil {
+		q = q.Set("external_webhook_type", filter.ExternalWebhookType)
+	}
+	if filter.ExternalWebhookParseBody != nil {
+		q = q.Set("external_webhook_parse_body", filter.ExternalWebhookParseBody)
+	}
 
 	q = q.Where("id = ?", filter.ID)
 
@@ -875,6 +1085,33 @@ func (r *FilterRepo) ToggleEnabled(ctx context.Context, filterID int, enabled bo
 	return nil
 }
 
+func (r *FilterRepo) UpdateBulk(ctx context.Context, update domain.FilterBulkUpdate) error {
+	var err error
+
+	queryBuilder := r.db.squirrel.
+		Update("filter").
+		Set("updated_at", sq.Expr("CURRENT_TIMESTAMP")).
+		Where(sq.Eq{"id": update.IDs})
+
+	if update.Enabled != nil {
+		queryBuilder = queryBuilder.Set("enabled", *update.Enabled)
+	}
+	if update.Priority != nil {
+		queryBuilder = queryBuilder.Set("priority", *update.Priority)
+	}
+
+	query, args, err := queryBuilder.ToSql()
+	if err != nil {
+		return errors.Wrap(err, "error building query")
+	}
+	_, err = r.db.handler.ExecContext(ctx, query, args...)
+	if err != nil {
+		return errors.Wrap(err, "error executing query")
+	}
+
+	return nil
+}
+
 func (r *FilterRepo) StoreIndexerConnections(ctx context.Context, filterID int, indexers []domain.Indexer) error {
 	tx, err := r.db.handler.BeginTx(ctx, nil)
 	if err != nil {
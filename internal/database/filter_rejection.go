@@ -0,0 +1,101 @@
+package database
+
+import (
+	"context"
+	"time"
+
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/pkg/errors"
+
+	sq "github.com/Masterminds/squirrel"
+)
+
+// StoreRejectionCounts adds the counts to the stored hourly counts, every filter, reason and bucket must be unique
+func (r *FilterRepo) StoreRejectionCounts(ctx context.Context, counts []domain.FilterRejectionCount) error {
+	if len(counts) == 0 {
+		return nil
+	}
+
+	queryBuilder := r.db.squirrel.
+		Insert("filter_rejection").
+		Columns("filter_id", "reason", "bucket", "count").
+		Suffix("ON CONFLICT (filter_id, reason, bucket) DO UPDATE SET count = filter_rejection.count + excluded.count")
+
+	for _, c := range counts {
+		// times are written by us in UTC so they compare the same on sqlite and postgres
+		queryBuilder = queryBuilder.Values(c.FilterID, c.Reason, c.Bucket.UTC(), c.Count)
+	}
+
+	query, args, err := queryBuilder.ToSql()
+	if err != nil {
+		return errors.Wrap(err, "error building query")
+	}
+
+	if _, err := r.db.handler.ExecContext(ctx, query, args...); err != nil {
+		return errors.Wrap(err, "error executing query")
+	}
+
+	return nil
+}
+
+// GetRejectionReasons sums the rejections of a filter per reason since the given time, most common first
+func (r *FilterRepo) GetRejectionReasons(ctx context.Context, filterID int, since time.Time) ([]domain.FilterRejectionReason, error) {
+	queryBuilder := r.db.squirrel.
+		Select("reason", "SUM(count) AS total").
+		From("filter_rejection").
+		Where(sq.Eq{"filter_id": filterID}).
+		Where(sq.GtOrEq{"bucket": since.UTC()}).
+		GroupBy("reason").
+		OrderBy("total DESC", "reason ASC")
+
+	query, args, err := queryBuilder.ToSql()
+	if err != nil {
+		return nil, errors.Wrap(err, "error building query")
+	}
+
+	rows, err := r.db.handler.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, errors.Wrap(err, "error executing query")
+	}
+
+	defer rows.Close()
+
+	reasons := make([]domain.FilterRejectionReason, 0)
+	for rows.Next() {
+		var reason domain.FilterRejectionReason
+		if err := rows.Scan(&reason.Reason, &reason.Count); err != nil {
+			return nil, errors.Wrap(err, "error scanning row")
+		}
+
+		reasons = append(reasons, reason)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, errors.Wrap(err, "row error")
+	}
+
+	return reasons, nil
+}
+
+// DeleteRejectionCounts deletes the counts of buckets before the given time
+func (r *FilterRepo) DeleteRejectionCounts(ctx context.Context, before time.Time) (int64, error) {
+	query, args, err := r.db.squirrel.
+		Delete("filter_rejection").
+		Where(sq.Lt{"bucket": before.UTC()}).
+		ToSql()
+	if err != nil {
+		return 0, errors.Wrap(err, "error building query")
+	}
+
+	res, err := r.db.handler.ExecContext(ctx, query, args...)
+	if err != nil {
+		return 0, errors.Wrap(err, "error executing query")
+	}
+
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return 0, errors.Wrap(err, "error getting rows affected")
+	}
+
+	return rows, nil
+}
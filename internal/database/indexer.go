@@ -32,8 +32,8 @@ func (r *IndexerRepo) Store(ctx context.Context, indexer domain.Indexer) (*domai
 	}
 
 	queryBuilder := r.db.squirrel.
-		Insert("indexer").Columns("enabled", "name", "identifier", "implementation", "settings").
-		Values(indexer.Enabled, indexer.Name, indexer.Identifier, indexer.Implementation, settings).
+		Insert("indexer").Columns("enabled", "name", "identifier", "implementation", "settings", "proxy").
+		Values(indexer.Enabled, indexer.Name, indexer.Identifier, indexer.Implementation, settings, indexer.Proxy).
 		Suffix("RETURNING id").RunWith(r.db.handler)
 
 	// return values
@@ -60,6 +60,7 @@ func (r *IndexerRepo) Update(ctx context.Context, indexer domain.Indexer) (*doma
 		Set("enabled", indexer.Enabled).
 		Set("name", indexer.Name).
 		Set("settings", settings).
+		Set("proxy", indexer.Proxy).
 		Set("updated_at", time.Now().Format(time.RFC3339)).
 		Where("id = ?", indexer.ID)
 
@@ -77,7 +78,7 @@ func (r *IndexerRepo) Update(ctx context.Context, indexer domain.Indexer) (*doma
 }
 
 func (r *IndexerRepo) List(ctx context.Context) ([]domain.Indexer, error) {
-	rows, err := r.db.handler.QueryContext(ctx, "SELECT id, enabled, name, identifier, implementation, settings FROM indexer ORDER BY name ASC")
+	rows, err := r.db.handler.QueryContext(ctx, "SELECT id, enabled, name, identifier, implementation, settings, proxy FROM indexer ORDER BY name ASC")
 	if err != nil {
 		return nil, errors.Wrap(err, "error executing query")
 	}
@@ -88,15 +89,16 @@ func (r *IndexerRepo) List(ctx context.Context) ([]domain.Indexer, error) {
 	for rows.Next() {
 		var f domain.Indexer
 
-		var implementation sql.NullString
+		var implementation, proxy sql.NullString
 		var settings string
 		var settingsMap map[string]string
 
-		if err := rows.Scan(&f.ID, &f.Enabled, &f.Name, &f.Identifier, &implementation, &settings); err != nil {
+		if err := rows.Scan(&f.ID, &f.Enabled, &f.Name, &f.Identifier, &implementation, &settings, &proxy); err != nil {
 			return nil, errors.Wrap(err, "error scanning row")
 		}
 
 		f.Implementation = implementation.String
+		f.Proxy = proxy.String
 
 		err = json.Unmarshal([]byte(settings), &settingsMap)
 		if err != nil {
@@ -116,7 +118,7 @@ func (r *IndexerRepo) List(ctx context.Context) ([]domain.Indexer, error) {
 
 func (r *IndexerRepo) FindByID(ctx context.Context, id int) (*domain.Indexer, error) {
 	queryBuilder := r.db.squirrel.
-		Select("id", "enabled", "name", "identifier", "implementation", "settings").
+		Select("id", "enabled", "name", "identifier", "implementation", "settings", "proxy").
 		From("indexer").
 		Where("id = ?", id)
 
@@ -132,13 +134,14 @@ func (r *IndexerRepo) FindByID(ctx context.Context, id int) (*domain.Indexer, er
 
 	var i domain.Indexer
 
-	var implementation, settings sql.NullString
+	var implementation, settings, proxy sql.NullString
 
-	if err := row.Scan(&i.ID, &i.Enabled, &i.Name, &i.Identifier, &implementation, &settings); err != nil {
+	if err := row.Scan(&i.ID, &i.Enabled, &i.Name, &i.Identifier, &implementation, &settings, &proxy); err != nil {
 		return nil, errors.Wrap(err, "error scanning row")
 	}
 
 	i.Implementation = implementation.String
+	i.Proxy = proxy.String
 
 	var settingsMap map[string]string
 	if err = json.Unmarshal([]byte(settings.String), &settingsMap); err != nil {
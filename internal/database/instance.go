@@ -0,0 +1,130 @@
+package database
+
+import (
+	"context"
+	"time"
+
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/internal/logger"
+	"github.com/autobrr/autobrr/pkg/errors"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/rs/zerolog"
+)
+
+type InstanceRepo struct {
+	log zerolog.Logger
+	db  *DB
+}
+
+func NewInstanceRepo(log logger.Logger, db *DB) domain.InstanceRepo {
+	return &InstanceRepo{
+		log: log.With().Str("repo", "instance").Logger(),
+		db:  db,
+	}
+}
+
+func (r *InstanceRepo) AcquireLease(ctx context.Context, name string, holder string, ttl time.Duration) (bool, error) {
+	// times are written by us in UTC so they compare the same on sqlite and postgres
+	now := time.Now().UTC()
+
+	queryBuilder := r.db.squirrel.
+		Insert("instance_lease").
+		Columns("name", "holder", "expires_at").
+		Values(name, holder, now.Add(ttl)).
+		Suffix("ON CONFLICT (name) DO UPDATE SET holder = excluded.holder, expires_at = excluded.expires_at WHERE instance_lease.holder = excluded.holder OR instance_lease.expires_at < ?", now)
+
+	query, args, err := queryBuilder.ToSql()
+	if err != nil {
+		return false, errors.Wrap(err, "error building query")
+	}
+
+	res, err := r.db.handler.ExecContext(ctx, query, args...)
+	if err != nil {
+		return false, errors.Wrap(err, "error executing query")
+	}
+
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return false, errors.Wrap(err, "error getting rows affected")
+	}
+
+	return rows > 0, nil
+}
+
+func (r *InstanceRepo) ReleaseLease(ctx context.Context, name string, holder string) error {
+	query, args, err := r.db.squirrel.
+		Delete("instance_lease").
+		Where(sq.Eq{"name": name, "holder": holder}).
+		ToSql()
+	if err != nil {
+		return errors.Wrap(err, "error building query")
+	}
+
+	if _, err := r.db.handler.ExecContext(ctx, query, args...); err != nil {
+		return errors.Wrap(err, "error executing query")
+	}
+
+	return nil
+}
+
+func (r *InstanceRepo) ClaimRelease(ctx context.Context, key string, holder string) (bool, error) {
+	query, args, err := r.db.squirrel.
+		Insert("release_claim").
+		Columns("key", "holder", "created_at").
+		Values(key, holder, time.Now().UTC()).
+		Suffix("ON CONFLICT (key) DO NOTHING").
+		ToSql()
+	if err != nil {
+		return false, errors.Wrap(err, "error building query")
+	}
+
+	res, err := r.db.handler.ExecContext(ctx, query, args...)
+	if err != nil {
+		return false, errors.Wrap(err, "error executing query")
+	}
+
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return false, errors.Wrap(err, "error getting rows affected")
+	}
+
+	return rows > 0, nil
+}
+
+func (r *InstanceRepo) DeleteReleaseClaim(ctx context.Context, key string, holder string) error {
+	query, args, err := r.db.squirrel.
+		Delete("release_claim").
+		Where(sq.Eq{"key": key, "holder": holder}).
+		ToSql()
+	if err != nil {
+		return errors.Wrap(err, "error building query")
+	}
+
+	if _, err := r.db.handler.ExecContext(ctx, query, args...); err != nil {
+		return errors.Wrap(err, "error executing query")
+	}
+
+	return nil
+}
+
+func (r *InstanceRepo) DeleteReleaseClaims(ctx context.Context, before time.Time) error {
+	query, args, err := r.db.squirrel.
+		Delete("release_claim").
+		Where(sq.Lt{"created_at": before.UTC()}).
+		ToSql()
+	if err != nil {
+		return errors.Wrap(err, "error building query")
+	}
+
+	res, err := r.db.handler.ExecContext(ctx, query, args...)
+	if err != nil {
+		return errors.Wrap(err, "error executing query")
+	}
+
+	rows, _ := res.RowsAffected()
+
+	r.log.Debug().Msgf("instance.DeleteReleaseClaims: deleted %d claims", rows)
+
+	return nil
+}
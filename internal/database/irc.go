@@ -26,7 +26,7 @@ func NewIrcRepo(log logger.Logger, db *DB) domain.IrcRepo {
 
 func (r *IrcRepo) GetNetworkByID(ctx context.Context, id int64) (*domain.IrcNetwork, error) {
 	queryBuilder := r.db.squirrel.
-		Select("id", "enabled", "name", "server", "port", "tls", "pass", "invite_command", "nickserv_account", "nickserv_password").
+		Select("id", "enabled", "name", "server", "port", "tls", "pass", "invite_command", "nickserv_account", "nickserv_password", "nickserv_regain", "sasl_mechanism", "tls_client_cert", "tls_client_key", "bouncer", "proxy").
 		From("irc_network").
 		Where("id = ?", id)
 
@@ -39,19 +39,26 @@ func (r *IrcRepo) GetNetworkByID(ctx context.Context, id int64) (*domain.IrcNetw
 	var n domain.IrcNetwork
 
 	var pass, inviteCmd sql.NullString
-	var nsAccount, nsPassword sql.NullString
-	var tls sql.NullBool
+	var nsAccount, nsPassword, nsRegain sql.NullString
+	var saslMech, tlsCert, tlsKey, proxy sql.NullString
+	var tls, bouncer sql.NullBool
 
 	row := r.db.handler.QueryRowContext(ctx, query, args...)
-	if err := row.Scan(&n.ID, &n.Enabled, &n.Name, &n.Server, &n.Port, &tls, &pass, &inviteCmd, &nsAccount, &nsPassword); err != nil {
+	if err := row.Scan(&n.ID, &n.Enabled, &n.Name, &n.Server, &n.Port, &tls, &pass, &inviteCmd, &nsAccount, &nsPassword, &nsRegain, &saslMech, &tlsCert, &tlsKey, &bouncer, &proxy); err != nil {
 		return nil, errors.Wrap(err, "error scanning row")
 	}
 
 	n.TLS = tls.Bool
+	n.Bouncer = bouncer.Bool
+	n.Proxy = proxy.String
 	n.Pass = pass.String
 	n.InviteCommand = inviteCmd.String
+	n.SASLMechanism = domain.IrcSASLMechanism(saslMech.String)
+	n.TLSClientCert = tlsCert.String
+	n.TLSClientKey = tlsKey.String
 	n.NickServ.Account = nsAccount.String
 	n.NickServ.Password = nsPassword.String
+	n.NickServ.Regain = nsRegain.String
 
 	return &n, nil
 }
@@ -103,7 +110,7 @@ func (r *IrcRepo) DeleteNetwork(ctx context.Context, id int64) error {
 
 func (r *IrcRepo) FindActiveNetworks(ctx context.Context) ([]domain.IrcNetwork, error) {
 	queryBuilder := r.db.squirrel.
-		Select("id", "enabled", "name", "server", "port", "tls", "pass", "invite_command", "nickserv_account", "nickserv_password").
+		Select("id", "enabled", "name", "server", "port", "tls", "pass", "invite_command", "nickserv_account", "nickserv_password", "nickserv_regain", "sasl_mechanism", "tls_client_cert", "tls_client_key", "bouncer", "proxy").
 		From("irc_network").
 		Where("enabled = ?", true)
 
@@ -124,19 +131,26 @@ func (r *IrcRepo) FindActiveNetworks(ctx context.Context) ([]domain.IrcNetwork,
 		var net domain.IrcNetwork
 
 		var pass, inviteCmd sql.NullString
-		var nsAccount, nsPassword sql.NullString
-		var tls sql.NullBool
+		var nsAccount, nsPassword, nsRegain sql.NullString
+		var saslMech, tlsCert, tlsKey, proxy sql.NullString
+		var tls, bouncer sql.NullBool
 
-		if err := rows.Scan(&net.ID, &net.Enabled, &net.Name, &net.Server, &net.Port, &tls, &pass, &inviteCmd, &nsAccount, &nsPassword); err != nil {
+		if err := rows.Scan(&net.ID, &net.Enabled, &net.Name, &net.Server, &net.Port, &tls, &pass, &inviteCmd, &nsAccount, &nsPassword, &nsRegain, &saslMech, &tlsCert, &tlsKey, &bouncer, &proxy); err != nil {
 			return nil, errors.Wrap(err, "error scanning row")
 		}
 
 		net.TLS = tls.Bool
+		net.Bouncer = bouncer.Bool
+		net.Proxy = proxy.String
 		net.Pass = pass.String
 		net.InviteCommand = inviteCmd.String
+		net.SASLMechanism = domain.IrcSASLMechanism(saslMech.String)
+		net.TLSClientCert = tlsCert.String
+		net.TLSClientKey = tlsKey.String
 
 		net.NickServ.Account = nsAccount.String
 		net.NickServ.Password = nsPassword.String
+		net.NickServ.Regain = nsRegain.String
 
 		networks = append(networks, net)
 	}
@@ -149,7 +163,7 @@ func (r *IrcRepo) FindActiveNetworks(ctx context.Context) ([]domain.IrcNetwork,
 
 func (r *IrcRepo) ListNetworks(ctx context.Context) ([]domain.IrcNetwork, error) {
 	queryBuilder := r.db.squirrel.
-		Select("id", "enabled", "name", "server", "port", "tls", "pass", "invite_command", "nickserv_account", "nickserv_password").
+		Select("id", "enabled", "name", "server", "port", "tls", "pass", "invite_command", "nickserv_account", "nickserv_password", "nickserv_regain", "sasl_mechanism", "tls_client_cert", "tls_client_key", "bouncer", "proxy").
 		From("irc_network").
 		OrderBy("name ASC")
 
@@ -170,19 +184,26 @@ func (r *IrcRepo) ListNetworks(ctx context.Context) ([]domain.IrcNetwork, error)
 		var net domain.IrcNetwork
 
 		var pass, inviteCmd sql.NullString
-		var nsAccount, nsPassword sql.NullString
-		var tls sql.NullBool
+		var nsAccount, nsPassword, nsRegain sql.NullString
+		var saslMech, tlsCert, tlsKey, proxy sql.NullString
+		var tls, bouncer sql.NullBool
 
-		if err := rows.Scan(&net.ID, &net.Enabled, &net.Name, &net.Server, &net.Port, &tls, &pass, &inviteCmd, &nsAccount, &nsPassword); err != nil {
+		if err := rows.Scan(&net.ID, &net.Enabled, &net.Name, &net.Server, &net.Port, &tls, &pass, &inviteCmd, &nsAccount, &nsPassword, &nsRegain, &saslMech, &tlsCert, &tlsKey, &bouncer, &proxy); err != nil {
 			return nil, errors.Wrap(err, "error scanning row")
 		}
 
 		net.TLS = tls.Bool
+		net.Bouncer = bouncer.Bool
+		net.Proxy = proxy.String
 		net.Pass = pass.String
 		net.InviteCommand = inviteCmd.String
+		net.SASLMechanism = domain.IrcSASLMechanism(saslMech.String)
+		net.TLSClientCert = tlsCert.String
+		net.TLSClientKey = tlsKey.String
 
 		net.NickServ.Account = nsAccount.String
 		net.NickServ.Password = nsPassword.String
+		net.NickServ.Regain = nsRegain.String
 
 		networks = append(networks, net)
 	}
@@ -232,7 +253,7 @@ func (r *IrcRepo) ListChannels(networkID int64) ([]domain.IrcChannel, error) {
 
 func (r *IrcRepo) CheckExistingNetwork(ctx context.Context, network *domain.IrcNetwork) (*domain.IrcNetwork, error) {
 	queryBuilder := r.db.squirrel.
-		Select("id", "enabled", "name", "server", "port", "tls", "pass", "invite_command", "nickserv_account", "nickserv_password").
+		Select("id", "enabled", "name", "server", "port", "tls", "pass", "invite_command", "nickserv_account", "nickserv_password", "nickserv_regain", "sasl_mechanism", "tls_client_cert", "tls_client_key", "bouncer", "proxy").
 		From("irc_network").
 		Where("server = ?", network.Server).
 		Where("nickserv_account = ?", network.NickServ.Account)
@@ -247,10 +268,11 @@ func (r *IrcRepo) CheckExistingNetwork(ctx context.Context, network *domain.IrcN
 
 	var net domain.IrcNetwork
 
-	var pass, inviteCmd, nickPass sql.NullString
-	var tls sql.NullBool
+	var pass, inviteCmd, nickPass, nickRegain sql.NullString
+	var saslMech, tlsCert, tlsKey, proxy sql.NullString
+	var tls, bouncer sql.NullBool
 
-	err = row.Scan(&net.ID, &net.Enabled, &net.Name, &net.Server, &net.Port, &tls, &pass, &inviteCmd, &net.NickServ.Account, &nickPass)
+	err = row.Scan(&net.ID, &net.Enabled, &net.Name, &net.Server, &net.Port, &tls, &pass, &inviteCmd, &net.NickServ.Account, &nickPass, &nickRegain, &saslMech, &tlsCert, &tlsKey, &bouncer, &proxy)
 	if err == sql.ErrNoRows {
 		// no result is not an error in our case
 		return nil, nil
@@ -259,9 +281,15 @@ func (r *IrcRepo) CheckExistingNetwork(ctx context.Context, network *domain.IrcN
 	}
 
 	net.TLS = tls.Bool
+	net.Bouncer = bouncer.Bool
+	net.Proxy = proxy.String
 	net.Pass = pass.String
 	net.InviteCommand = inviteCmd.String
+	net.SASLMechanism = domain.IrcSASLMechanism(saslMech.String)
+	net.TLSClientCert = tlsCert.String
+	net.TLSClientKey = tlsKey.String
 	net.NickServ.Password = nickPass.String
+	net.NickServ.Regain = nickRegain.String
 
 	return &net, nil
 }
@@ -273,6 +301,11 @@ func (r *IrcRepo) StoreNetwork(network *domain.IrcNetwork) error {
 
 	nsAccount := toNullString(network.NickServ.Account)
 	nsPassword := toNullString(network.NickServ.Password)
+	nsRegain := toNullString(network.NickServ.Regain)
+	saslMech := toNullString(string(network.SASLMechanism))
+	tlsCert := toNullString(network.TLSClientCert)
+	tlsKey := toNullString(network.TLSClientKey)
+	proxy := toNullString(network.Proxy)
 
 	var err error
 	var retID int64
@@ -289,6 +322,12 @@ func (r *IrcRepo) StoreNetwork(network *domain.IrcNetwork) error {
 			"invite_command",
 			"nickserv_account",
 			"nickserv_password",
+			"nickserv_regain",
+			"sasl_mechanism",
+			"tls_client_cert",
+			"tls_client_key",
+			"bouncer",
+			"proxy",
 		).
 		Values(
 			network.Enabled,
@@ -300,6 +339,12 @@ func (r *IrcRepo) StoreNetwork(network *domain.IrcNetwork) error {
 			inviteCmd,
 			nsAccount,
 			nsPassword,
+			nsRegain,
+			saslMech,
+			tlsCert,
+			tlsKey,
+			network.Bouncer,
+			proxy,
 		).
 		Suffix("RETURNING id").
 		RunWith(r.db.handler)
@@ -321,6 +366,11 @@ func (r *IrcRepo) UpdateNetwork(ctx context.Context, network *domain.IrcNetwork)
 
 	nsAccount := toNullString(network.NickServ.Account)
 	nsPassword := toNullString(network.NickServ.Password)
+	nsRegain := toNullString(network.NickServ.Regain)
+	saslMech := toNullString(string(network.SASLMechanism))
+	tlsCert := toNullString(network.TLSClientCert)
+	tlsKey := toNullString(network.TLSClientKey)
+	proxy := toNullString(network.Proxy)
 
 	var err error
 
@@ -335,6 +385,12 @@ func (r *IrcRepo) UpdateNetwork(ctx context.Context, network *domain.IrcNetwork)
 		Set("invite_command", inviteCmd).
 		Set("nickserv_account", nsAccount).
 		Set("nickserv_password", nsPassword).
+		Set("nickserv_regain", nsRegain).
+		Set("sasl_mechanism", saslMech).
+		Set("tls_client_cert", tlsCert).
+		Set("tls_client_key", tlsKey).
+		Set("bouncer", network.Bouncer).
+		Set("proxy", proxy).
 		Set("updated_at", time.Now().Format(time.RFC3339)).
 		Where("id = ?", network.ID)
 
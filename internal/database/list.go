@@ -0,0 +1,310 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/internal/logger"
+	"github.com/autobrr/autobrr/pkg/errors"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/rs/zerolog"
+)
+
+type ListRepo struct {
+	log zerolog.Logger
+	db  *DB
+}
+
+func NewListRepo(log logger.Logger, db *DB) domain.ListRepo {
+	return &ListRepo{
+		log: log.With().Str("repo", "list").Logger(),
+		db:  db,
+	}
+}
+
+func (r *ListRepo) selectLists() sq.SelectBuilder {
+	return r.db.squirrel.
+		Select(
+			"id",
+			"name",
+			"type",
+			"enabled",
+			"client_id",
+			"url",
+			"api_key",
+			"include_unmonitored",
+			"item_count",
+			"last_refresh_time",
+			"last_refresh_status",
+			"last_refresh_error",
+			"created_at",
+			"updated_at",
+		).
+		From("list")
+}
+
+type listScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanList(row listScanner) (*domain.List, error) {
+	var l domain.List
+	var url, apiKey, lastRefreshStatus, lastRefreshError sql.NullString
+	var clientID sql.NullInt32
+	var lastRefreshTime sql.NullTime
+
+	if err := row.Scan(&l.ID, &l.Name, &l.Type, &l.Enabled, &clientID, &url, &apiKey, &l.IncludeUnmonitored, &l.ItemCount, &lastRefreshTime, &lastRefreshStatus, &lastRefreshError, &l.CreatedAt, &l.UpdatedAt); err != nil {
+		return nil, err
+	}
+
+	l.ClientID = int(clientID.Int32)
+	l.URL = url.String
+	l.APIKey = apiKey.String
+	l.LastRefreshStatus = domain.ListRefreshStatus(lastRefreshStatus.String)
+	l.LastRefreshError = lastRefreshError.String
+
+	if lastRefreshTime.Valid {
+		l.LastRefreshTime = &lastRefreshTime.Time
+	}
+
+	return &l, nil
+}
+
+func (r *ListRepo) List(ctx context.Context) ([]domain.List, error) {
+	query, args, err := r.selectLists().OrderBy("name ASC").ToSql()
+	if err != nil {
+		return nil, errors.Wrap(err, "error building query")
+	}
+
+	rows, err := r.db.handler.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, errors.Wrap(err, "error executing query")
+	}
+
+	defer rows.Close()
+
+	lists := make([]domain.List, 0)
+	for rows.Next() {
+		l, err := scanList(rows)
+		if err != nil {
+			return nil, errors.Wrap(err, "error scanning row")
+		}
+
+		lists = append(lists, *l)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, errors.Wrap(err, "row error")
+	}
+
+	return lists, nil
+}
+
+func (r *ListRepo) FindByID(ctx context.Context, id int64) (*domain.List, error) {
+	query, args, err := r.selectLists().Where(sq.Eq{"id": id}).ToSql()
+	if err != nil {
+		return nil, errors.Wrap(err, "error building query")
+	}
+
+	l, err := scanList(r.db.handler.QueryRowContext(ctx, query, args...))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errors.New("list not found: %v", id)
+		}
+		return nil, errors.Wrap(err, "error scanning row")
+	}
+
+	return l, nil
+}
+
+func (r *ListRepo) Store(ctx context.Context, list *domain.List) error {
+	queryBuilder := r.db.squirrel.
+		Insert("list").
+		Columns("name", "type", "enabled", "client_id", "url", "api_key", "include_unmonitored").
+		Values(list.Name, list.Type, list.Enabled, toNullInt32(int32(list.ClientID)), toNullString(list.URL), toNullString(list.APIKey), list.IncludeUnmonitored).
+		Suffix("RETURNING id, created_at, updated_at").RunWith(r.db.handler)
+
+	if err := queryBuilder.QueryRowContext(ctx).Scan(&list.ID, &list.CreatedAt, &list.UpdatedAt); err != nil {
+		return errors.Wrap(err, "error executing query")
+	}
+
+	r.log.Debug().Msgf("list.store: %v %v", list.ID, list.Name)
+
+	return nil
+}
+
+func (r *ListRepo) Update(ctx context.Context, list *domain.List) error {
+	query, args, err := r.db.squirrel.
+		Update("list").
+		Set("name", list.Name).
+		Set("type", list.Type).
+		Set("enabled", list.Enabled).
+		Set("client_id", toNullInt32(int32(list.ClientID))).
+		Set("url", toNullString(list.URL)).
+		Set("api_key", toNullString(list.APIKey)).
+		Set("include_unmonitored", list.IncludeUnmonitored).
+		Set("updated_at", sq.Expr("CURRENT_TIMESTAMP")).
+		Where(sq.Eq{"id": list.ID}).
+		ToSql()
+	if err != nil {
+		return errors.Wrap(err, "error building query")
+	}
+
+	res, err := r.db.handler.ExecContext(ctx, query, args...)
+	if err != nil {
+		return errors.Wrap(err, "error executing query")
+	}
+
+	if rows, _ := res.RowsAffected(); rows == 0 {
+		return errors.New("list not found: %v", list.ID)
+	}
+
+	return nil
+}
+
+// Delete removes the list and its items and clears it from the filters using it
+func (r *ListRepo) Delete(ctx context.Context, id int64) error {
+	tx, err := r.db.handler.BeginTx(ctx, nil)
+	if err != nil {
+		return errors.Wrap(err, "error begin transaction")
+	}
+
+	defer tx.Rollback()
+
+	statements := []sq.Sqlizer{
+		r.db.squirrel.Update("filter").Set("match_list_id", nil).Where(sq.Eq{"match_list_id": id}),
+		r.db.squirrel.Update("filter").Set("except_list_id", nil).Where(sq.Eq{"except_list_id": id}),
+		r.db.squirrel.Delete("list_item").Where(sq.Eq{"list_id": id}),
+		r.db.squirrel.Delete("list").Where(sq.Eq{"id": id}),
+	}
+
+	for _, statement := range statements {
+		query, args, err := statement.ToSql()
+		if err != nil {
+			return errors.Wrap(err, "error building query")
+		}
+
+		if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+			return errors.Wrap(err, "error executing query")
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return errors.Wrap(err, "error deleting list: %v", id)
+	}
+
+	r.log.Debug().Msgf("list.delete: %v", id)
+
+	return nil
+}
+
+// UpdateLastRefresh stores the item count and the time, status and error of the last refresh
+func (r *ListRepo) UpdateLastRefresh(ctx context.Context, list *domain.List) error {
+	query, args, err := r.db.squirrel.
+		Update("list").
+		Set("item_count", list.ItemCount).
+		Set("last_refresh_time", list.LastRefreshTime).
+		Set("last_refresh_status", list.LastRefreshStatus).
+		Set("last_refresh_error", toNullString(list.LastRefreshError)).
+		Where(sq.Eq{"id": list.ID}).
+		ToSql()
+	if err != nil {
+		return errors.Wrap(err, "error building query")
+	}
+
+	if _, err := r.db.handler.ExecContext(ctx, query, args...); err != nil {
+		return errors.Wrap(err, "error executing query")
+	}
+
+	return nil
+}
+
+// StoreItems replaces the titles of the list
+func (r *ListRepo) StoreItems(ctx context.Context, listID int64, titles []string) error {
+	tx, err := r.db.handler.BeginTx(ctx, nil)
+	if err != nil {
+		return errors.Wrap(err, "error begin transaction")
+	}
+
+	defer tx.Rollback()
+
+	query, args, err := r.db.squirrel.
+		Delete("list_item").
+		Where(sq.Eq{"list_id": listID}).
+		ToSql()
+	if err != nil {
+		return errors.Wrap(err, "error building query")
+	}
+
+	if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+		return errors.Wrap(err, "error executing query")
+	}
+
+	// insert in batches to stay below the variable limit of sqlite
+	const batchSize = 400
+
+	for start := 0; start < len(titles); start += batchSize {
+		end := start + batchSize
+		if end > len(titles) {
+			end = len(titles)
+		}
+
+		insert := r.db.squirrel.Insert("list_item").Columns("list_id", "title")
+		for _, title := range titles[start:end] {
+			insert = insert.Values(listID, title)
+		}
+
+		query, args, err := insert.ToSql()
+		if err != nil {
+			return errors.Wrap(err, "error building query")
+		}
+
+		if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+			return errors.Wrap(err, "error executing query")
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return errors.Wrap(err, "error storing items for list: %v", listID)
+	}
+
+	r.log.Debug().Msgf("list.storeItems: stored %d items for list %v", len(titles), listID)
+
+	return nil
+}
+
+func (r *ListRepo) GetItems(ctx context.Context, listID int64) ([]string, error) {
+	query, args, err := r.db.squirrel.
+		Select("title").
+		From("list_item").
+		Where(sq.Eq{"list_id": listID}).
+		ToSql()
+	if err != nil {
+		return nil, errors.Wrap(err, "error building query")
+	}
+
+	rows, err := r.db.handler.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, errors.Wrap(err, "error executing query")
+	}
+
+	defer rows.Close()
+
+	titles := make([]string, 0)
+	for rows.Next() {
+		var title string
+		if err := rows.Scan(&title); err != nil {
+			return nil, errors.Wrap(err, "error scanning row")
+		}
+
+		titles = append(titles, title)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, errors.Wrap(err, "row error")
+	}
+
+	return titles, nil
+}
@@ -3,6 +3,7 @@ package database
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 
 	"github.com/autobrr/autobrr/internal/domain"
 	"github.com/autobrr/autobrr/internal/logger"
@@ -28,7 +29,7 @@ func NewNotificationRepo(log logger.Logger, db *DB) domain.NotificationRepo {
 func (r *NotificationRepo) Find(ctx context.Context, params domain.NotificationQueryParams) ([]domain.Notification, int, error) {
 
 	queryBuilder := r.db.squirrel.
-		Select("id", "name", "type", "enabled", "events", "webhook", "token", "api_key", "channel", "created_at", "updated_at", "COUNT(*) OVER() AS total_count").
+		Select("id", "name", "type", "enabled", "events", "webhook", "token", "api_key", "channel", "host", "username", "password", "targets", "topic", "priority", "click_url", "templates", "created_at", "updated_at", "COUNT(*) OVER() AS total_count").
 		From("notification").
 		OrderBy("name")
 
@@ -49,11 +50,12 @@ func (r *NotificationRepo) Find(ctx context.Context, params domain.NotificationQ
 	for rows.Next() {
 		var n domain.Notification
 
-		var webhook, token, apiKey, channel sql.NullString
+		var webhook, token, apiKey, channel, host, username, password, targets, topic, clickURL, templates sql.NullString
+		var priority sql.NullInt32
 		//var token, apiKey, webhook, title, icon, host, username, password, channel, targets, devices sql.NullString
 		//if err := rows.Scan(&n.ID, &n.Name, &n.Type, &n.Enabled, pq.Array(&n.Events), &token, &apiKey, &webhook, &title, &icon, &host, &username, &password, &channel, &targets, &devices, &n.CreatedAt, &n.UpdatedAt); err != nil {
 		//var token, apiKey, webhook, title, icon, host, username, password, channel, targets, devices sql.NullString
-		if err := rows.Scan(&n.ID, &n.Name, &n.Type, &n.Enabled, pq.Array(&n.Events), &webhook, &token, &apiKey, &channel, &n.CreatedAt, &n.UpdatedAt, &totalCount); err != nil {
+		if err := rows.Scan(&n.ID, &n.Name, &n.Type, &n.Enabled, pq.Array(&n.Events), &webhook, &token, &apiKey, &channel, &host, &username, &password, &targets, &topic, &priority, &clickURL, &templates, &n.CreatedAt, &n.UpdatedAt, &totalCount); err != nil {
 			return nil, 0, errors.Wrap(err, "error scanning row")
 		}
 
@@ -61,6 +63,18 @@ func (r *NotificationRepo) Find(ctx context.Context, params domain.NotificationQ
 		n.Webhook = webhook.String
 		n.Token = token.String
 		n.Channel = channel.String
+		n.Host = host.String
+		n.Username = username.String
+		n.Password = password.String
+		n.Targets = targets.String
+		n.Topic = topic.String
+		n.Priority = priority.Int32
+		n.ClickURL = clickURL.String
+
+		if n.Templates, err = notificationTemplates(templates); err != nil {
+			return nil, 0, err
+		}
+
 		//n.Title = title.String
 		//n.Icon = icon.String
 		//n.Host = host.String
@@ -81,7 +95,7 @@ func (r *NotificationRepo) Find(ctx context.Context, params domain.NotificationQ
 
 func (r *NotificationRepo) List(ctx context.Context) ([]domain.Notification, error) {
 
-	rows, err := r.db.handler.QueryContext(ctx, "SELECT id, name, type, enabled, events, token, api_key, webhook, title, icon, host, username, password, channel, targets, devices, created_at, updated_at FROM notification ORDER BY name ASC")
+	rows, err := r.db.handler.QueryContext(ctx, "SELECT id, name, type, enabled, events, token, api_key, webhook, title, icon, host, username, password, channel, targets, devices, topic, priority, click_url, templates, created_at, updated_at FROM notification ORDER BY name ASC")
 	if err != nil {
 		return nil, errors.Wrap(err, "error executing query")
 	}
@@ -93,8 +107,9 @@ func (r *NotificationRepo) List(ctx context.Context) ([]domain.Notification, err
 		var n domain.Notification
 		//var eventsSlice []string
 
-		var token, apiKey, webhook, title, icon, host, username, password, channel, targets, devices sql.NullString
-		if err := rows.Scan(&n.ID, &n.Name, &n.Type, &n.Enabled, pq.Array(&n.Events), &token, &apiKey, &webhook, &title, &icon, &host, &username, &password, &channel, &targets, &devices, &n.CreatedAt, &n.UpdatedAt); err != nil {
+		var token, apiKey, webhook, title, icon, host, username, password, channel, targets, devices, topic, clickURL, templates sql.NullString
+		var priority sql.NullInt32
+		if err := rows.Scan(&n.ID, &n.Name, &n.Type, &n.Enabled, pq.Array(&n.Events), &token, &apiKey, &webhook, &title, &icon, &host, &username, &password, &channel, &targets, &devices, &topic, &priority, &clickURL, &templates, &n.CreatedAt, &n.UpdatedAt); err != nil {
 			return nil, errors.Wrap(err, "error scanning row")
 		}
 
@@ -110,6 +125,13 @@ func (r *NotificationRepo) List(ctx context.Context) ([]domain.Notification, err
 		n.Channel = channel.String
 		n.Targets = targets.String
 		n.Devices = devices.String
+		n.Topic = topic.String
+		n.Priority = priority.Int32
+		n.ClickURL = clickURL.String
+
+		if n.Templates, err = notificationTemplates(templates); err != nil {
+			return nil, err
+		}
 
 		notifications = append(notifications, n)
 	}
@@ -140,6 +162,10 @@ func (r *NotificationRepo) FindByID(ctx context.Context, id int) (*domain.Notifi
 			"channel",
 			"targets",
 			"devices",
+			"topic",
+			"priority",
+			"click_url",
+			"templates",
 			"created_at",
 			"updated_at",
 		).
@@ -159,8 +185,9 @@ func (r *NotificationRepo) FindByID(ctx context.Context, id int) (*domain.Notifi
 
 	var n domain.Notification
 
-	var token, apiKey, webhook, title, icon, host, username, password, channel, targets, devices sql.NullString
-	if err := row.Scan(&n.ID, &n.Name, &n.Type, &n.Enabled, pq.Array(&n.Events), &token, &apiKey, &webhook, &title, &icon, &host, &username, &password, &channel, &targets, &devices, &n.CreatedAt, &n.UpdatedAt); err != nil {
+	var token, apiKey, webhook, title, icon, host, username, password, channel, targets, devices, topic, clickURL, templates sql.NullString
+	var priority sql.NullInt32
+	if err := row.Scan(&n.ID, &n.Name, &n.Type, &n.Enabled, pq.Array(&n.Events), &token, &apiKey, &webhook, &title, &icon, &host, &username, &password, &channel, &targets, &devices, &topic, &priority, &clickURL, &templates, &n.CreatedAt, &n.UpdatedAt); err != nil {
 		return nil, errors.Wrap(err, "error scanning row")
 	}
 
@@ -175,6 +202,13 @@ func (r *NotificationRepo) FindByID(ctx context.Context, id int) (*domain.Notifi
 	n.Channel = channel.String
 	n.Targets = targets.String
 	n.Devices = devices.String
+	n.Topic = topic.String
+	n.Priority = priority.Int32
+	n.ClickURL = clickURL.String
+
+	if n.Templates, err = notificationTemplates(templates); err != nil {
+		return nil, err
+	}
 
 	return &n, nil
 }
@@ -184,6 +218,17 @@ func (r *NotificationRepo) Store(ctx context.Context, notification domain.Notifi
 	token := toNullString(notification.Token)
 	apiKey := toNullString(notification.APIKey)
 	channel := toNullString(notification.Channel)
+	host := toNullString(notification.Host)
+	username := toNullString(notification.Username)
+	password := toNullString(notification.Password)
+	targets := toNullString(notification.Targets)
+	topic := toNullString(notification.Topic)
+	clickURL := toNullString(notification.ClickURL)
+
+	templates, err := notificationTemplatesJSON(notification.Templates)
+	if err != nil {
+		return nil, err
+	}
 
 	queryBuilder := r.db.squirrel.
 		Insert("notification").
@@ -196,6 +241,14 @@ func (r *NotificationRepo) Store(ctx context.Context, notification domain.Notifi
 			"token",
 			"api_key",
 			"channel",
+			"host",
+			"username",
+			"password",
+			"targets",
+			"topic",
+			"priority",
+			"click_url",
+			"templates",
 		).
 		Values(
 			notification.Name,
@@ -206,13 +259,21 @@ func (r *NotificationRepo) Store(ctx context.Context, notification domain.Notifi
 			token,
 			apiKey,
 			channel,
+			host,
+			username,
+			password,
+			targets,
+			topic,
+			notification.Priority,
+			clickURL,
+			templates,
 		).
 		Suffix("RETURNING id").RunWith(r.db.handler)
 
 	// return values
 	var retID int64
 
-	err := queryBuilder.QueryRowContext(ctx).Scan(&retID)
+	err = queryBuilder.QueryRowContext(ctx).Scan(&retID)
 	if err != nil {
 		return nil, errors.Wrap(err, "error executing query")
 	}
@@ -228,6 +289,17 @@ func (r *NotificationRepo) Update(ctx context.Context, notification domain.Notif
 	token := toNullString(notification.Token)
 	apiKey := toNullString(notification.APIKey)
 	channel := toNullString(notification.Channel)
+	host := toNullString(notification.Host)
+	username := toNullString(notification.Username)
+	password := toNullString(notification.Password)
+	targets := toNullString(notification.Targets)
+	topic := toNullString(notification.Topic)
+	clickURL := toNullString(notification.ClickURL)
+
+	templates, err := notificationTemplatesJSON(notification.Templates)
+	if err != nil {
+		return nil, err
+	}
 
 	queryBuilder := r.db.squirrel.
 		Update("notification").
@@ -239,6 +311,14 @@ func (r *NotificationRepo) Update(ctx context.Context, notification domain.Notif
 		Set("token", token).
 		Set("api_key", apiKey).
 		Set("channel", channel).
+		Set("host", host).
+		Set("username", username).
+		Set("password", password).
+		Set("targets", targets).
+		Set("topic", topic).
+		Set("priority", notification.Priority).
+		Set("click_url", clickURL).
+		Set("templates", templates).
 		Set("updated_at", sq.Expr("CURRENT_TIMESTAMP")).
 		Where("id = ?", notification.ID)
 
@@ -276,3 +356,30 @@ func (r *NotificationRepo) Delete(ctx context.Context, notificationID int) error
 
 	return nil
 }
+
+// notificationTemplates reads the templates stored as json
+func notificationTemplates(s sql.NullString) (map[domain.NotificationEvent]string, error) {
+	if !s.Valid || s.String == "" {
+		return nil, nil
+	}
+
+	var templates map[domain.NotificationEvent]string
+	if err := json.Unmarshal([]byte(s.String), &templates); err != nil {
+		return nil, errors.Wrap(err, "could not unmarshal templates")
+	}
+
+	return templates, nil
+}
+
+func notificationTemplatesJSON(templates map[domain.NotificationEvent]string) (sql.NullString, error) {
+	if len(templates) == 0 {
+		return sql.NullString{}, nil
+	}
+
+	b, err := json.Marshal(templates)
+	if err != nil {
+		return sql.NullString{}, errors.Wrap(err, "could not marshal templates")
+	}
+
+	return toNullString(string(b)), nil
+}
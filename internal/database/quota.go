@@ -0,0 +1,292 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/internal/logger"
+	"github.com/autobrr/autobrr/pkg/errors"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/rs/zerolog"
+)
+
+type QuotaRepo struct {
+	log zerolog.Logger
+	db  *DB
+}
+
+func NewQuotaRepo(log logger.Logger, db *DB) domain.QuotaRepo {
+	return &QuotaRepo{
+		log: log.With().Str("repo", "quota").Logger(),
+		db:  db,
+	}
+}
+
+func (r *QuotaRepo) selectQuota() sq.SelectBuilder {
+	return r.db.squirrel.
+		Select(
+			"id",
+			"name",
+			"enabled",
+			"scope",
+			"filter_id",
+			"indexer",
+			"max_downloads",
+			"max_size",
+			"period",
+			"created_at",
+			"updated_at",
+		).
+		From("quota")
+}
+
+func (r *QuotaRepo) List(ctx context.Context) ([]domain.Quota, error) {
+	return r.find(ctx, r.selectQuota().OrderBy("name"))
+}
+
+func (r *QuotaRepo) FindActive(ctx context.Context, filterID int, indexer string) ([]domain.Quota, error) {
+	queryBuilder := r.selectQuota().
+		Where(sq.Eq{"enabled": true}).
+		Where(sq.Or{
+			sq.And{sq.Eq{"scope": domain.QuotaScopeFilter}, sq.Eq{"filter_id": filterID}},
+			sq.And{sq.Eq{"scope": domain.QuotaScopeIndexer}, sq.Eq{"indexer": indexer}},
+		})
+
+	return r.find(ctx, queryBuilder)
+}
+
+func (r *QuotaRepo) find(ctx context.Context, queryBuilder sq.SelectBuilder) ([]domain.Quota, error) {
+	query, args, err := queryBuilder.ToSql()
+	if err != nil {
+		return nil, errors.Wrap(err, "error building query")
+	}
+
+	rows, err := r.db.handler.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, errors.Wrap(err, "error executing query")
+	}
+
+	defer rows.Close()
+
+	quotas := make([]domain.Quota, 0)
+	for rows.Next() {
+		q, err := scanQuota(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		quotas = append(quotas, *q)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errors.Wrap(err, "error rows list")
+	}
+
+	return quotas, nil
+}
+
+func (r *QuotaRepo) FindByID(ctx context.Context, id int) (*domain.Quota, error) {
+	query, args, err := r.selectQuota().Where(sq.Eq{"id": id}).ToSql()
+	if err != nil {
+		return nil, errors.Wrap(err, "error building query")
+	}
+
+	row := r.db.handler.QueryRowContext(ctx, query, args...)
+	if err := row.Err(); err != nil {
+		return nil, errors.Wrap(err, "error executing query")
+	}
+
+	q, err := scanQuota(row)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errors.New("quota not found: %v", id)
+		}
+
+		return nil, err
+	}
+
+	return q, nil
+}
+
+type quotaScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanQuota(row quotaScanner) (*domain.Quota, error) {
+	var q domain.Quota
+
+	var filterID sql.NullInt32
+	var indexer, maxSize, period sql.NullString
+
+	if err := row.Scan(&q.ID, &q.Name, &q.Enabled, &q.Scope, &filterID, &indexer, &q.MaxDownloads, &maxSize, &period, &q.CreatedAt, &q.UpdatedAt); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, err
+		}
+
+		return nil, errors.Wrap(err, "error scanning row")
+	}
+
+	q.FilterID = int(filterID.Int32)
+	q.Indexer = indexer.String
+	q.MaxSize = maxSize.String
+	q.Period = domain.FilterMaxDownloadsUnit(period.String)
+
+	return &q, nil
+}
+
+// Usage counts the releases grabbed, and their total size, in the current quota period
+func (r *QuotaRepo) Usage(ctx context.Context, quota domain.Quota) (*domain.QuotaUsage, error) {
+	queryBuilder := r.db.squirrel.
+		Select("COUNT(*)", "COALESCE(SUM(size), 0)").
+		From(`"release"`)
+
+	switch quota.Scope {
+	case domain.QuotaScopeFilter:
+		queryBuilder = queryBuilder.Where(sq.Eq{"filter_id": quota.FilterID})
+	case domain.QuotaScopeIndexer:
+		queryBuilder = queryBuilder.Where(sq.Eq{"indexer": quota.Indexer}).Where(sq.NotEq{"filter_id": nil})
+	default:
+		return nil, errors.New("invalid quota scope: %v", quota.Scope)
+	}
+
+	if since := r.periodStart(quota.Period); since != "" {
+		queryBuilder = queryBuilder.Where(`"release".timestamp >= ` + since)
+	}
+
+	query, args, err := queryBuilder.ToSql()
+	if err != nil {
+		return nil, errors.Wrap(err, "error building query")
+	}
+
+	row := r.db.handler.QueryRowContext(ctx, query, args...)
+	if err := row.Err(); err != nil {
+		return nil, errors.Wrap(err, "error executing query")
+	}
+
+	var usage domain.QuotaUsage
+
+	var size int64
+	if err := row.Scan(&usage.Downloads, &size); err != nil {
+		return nil, errors.Wrap(err, "error scanning row")
+	}
+
+	usage.Size = uint64(size)
+
+	return &usage, nil
+}
+
+// periodStart returns the sql expression for the start of the period, same as the filter max downloads counts
+func (r *QuotaRepo) periodStart(period domain.FilterMaxDownloadsUnit) string {
+	if r.db.Driver == "sqlite" {
+		switch period {
+		case domain.FilterMaxDownloadsHour:
+			return "datetime('now', '-1 hour')"
+		case domain.FilterMaxDownloadsDay:
+			return "datetime('now', 'start of day')"
+		case domain.FilterMaxDownloadsWeek:
+			return "datetime('now', 'weekday 0', '-7 days')"
+		case domain.FilterMaxDownloadsMonth:
+			return "datetime('now', 'start of month')"
+		}
+
+		return ""
+	}
+
+	switch period {
+	case domain.FilterMaxDownloadsHour:
+		return "date_trunc('hour', CURRENT_TIMESTAMP)"
+	case domain.FilterMaxDownloadsDay:
+		return "date_trunc('day', CURRENT_DATE)"
+	case domain.FilterMaxDownloadsWeek:
+		return "date_trunc('week', CURRENT_DATE)"
+	case domain.FilterMaxDownloadsMonth:
+		return "date_trunc('month', CURRENT_DATE)"
+	}
+
+	return ""
+}
+
+func (r *QuotaRepo) Store(ctx context.Context, quota *domain.Quota) error {
+	queryBuilder := r.db.squirrel.
+		Insert("quota").
+		Columns(
+			"name",
+			"enabled",
+			"scope",
+			"filter_id",
+			"indexer",
+			"max_downloads",
+			"max_size",
+			"period",
+		).
+		Values(
+			quota.Name,
+			quota.Enabled,
+			quota.Scope,
+			toNullInt32(int32(quota.FilterID)),
+			toNullString(quota.Indexer),
+			quota.MaxDownloads,
+			toNullString(quota.MaxSize),
+			quota.Period,
+		).
+		Suffix("RETURNING id").RunWith(r.db.handler)
+
+	var retID int
+
+	if err := queryBuilder.QueryRowContext(ctx).Scan(&retID); err != nil {
+		return errors.Wrap(err, "error executing query")
+	}
+
+	quota.ID = retID
+
+	r.log.Debug().Msgf("quota.store: added new %v", retID)
+
+	return nil
+}
+
+func (r *QuotaRepo) Update(ctx context.Context, quota *domain.Quota) error {
+	queryBuilder := r.db.squirrel.
+		Update("quota").
+		Set("name", quota.Name).
+		Set("enabled", quota.Enabled).
+		Set("scope", quota.Scope).
+		Set("filter_id", toNullInt32(int32(quota.FilterID))).
+		Set("indexer", toNullString(quota.Indexer)).
+		Set("max_downloads", quota.MaxDownloads).
+		Set("max_size", toNullString(quota.MaxSize)).
+		Set("period", quota.Period).
+		Set("updated_at", sq.Expr("CURRENT_TIMESTAMP")).
+		Where(sq.Eq{"id": quota.ID})
+
+	query, args, err := queryBuilder.ToSql()
+	if err != nil {
+		return errors.Wrap(err, "error building query")
+	}
+
+	if _, err = r.db.handler.ExecContext(ctx, query, args...); err != nil {
+		return errors.Wrap(err, "error executing query")
+	}
+
+	r.log.Debug().Msgf("quota.update: %v", quota.Name)
+
+	return nil
+}
+
+func (r *QuotaRepo) Delete(ctx context.Context, id int) error {
+	query, args, err := r.db.squirrel.
+		Delete("quota").
+		Where(sq.Eq{"id": id}).
+		ToSql()
+	if err != nil {
+		return errors.Wrap(err, "error building query")
+	}
+
+	if _, err = r.db.handler.ExecContext(ctx, query, args...); err != nil {
+		return errors.Wrap(err, "error executing query")
+	}
+
+	r.log.Info().Msgf("quota.delete: successfully deleted: %v", id)
+
+	return nil
+}
@@ -5,6 +5,7 @@ import (
 	"database/sql"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/autobrr/autobrr/internal/domain"
 	"github.com/autobrr/autobrr/internal/logger"
@@ -33,8 +34,8 @@ func (repo *ReleaseRepo) Store(ctx context.Context, r *domain.Release) (*domain.
 
 	queryBuilder := repo.db.squirrel.
 		Insert("release").
-		Columns("filter_status", "rejections", "indexer", "filter", "protocol", "implementation", "timestamp", "group_id", "torrent_id", "torrent_name", "size", "title", "category", "season", "episode", "year", "resolution", "source", "codec", "container", "hdr", "release_group", "proper", "repack", "website", "type", "origin", "tags", "uploader", "pre_time", "filter_id").
-		Values(r.FilterStatus, pq.Array(r.Rejections), r.Indexer, r.FilterName, r.Protocol, r.Implementation, r.Timestamp, r.GroupID, r.TorrentID, r.TorrentName, r.Size, r.Title, r.Category, r.Season, r.Episode, r.Year, r.Resolution, r.Source, codecStr, r.Container, hdrStr, r.Group, r.Proper, r.Repack, r.Website, r.Type, r.Origin, pq.Array(r.Tags), r.Uploader, r.PreTime, r.FilterID).
+		Columns("filter_status", "rejections", "indexer", "filter", "protocol", "implementation", "timestamp", "group_id", "torrent_id", "torrent_url", "torrent_name", "size", "title", "category", "season", "episode", "year", "resolution", "source", "codec", "container", "hdr", "release_group", "proper", "repack", "website", "type", "origin", "tags", "uploader", "pre_time", "filter_id").
+		Values(r.FilterStatus, pq.Array(r.Rejections), r.Indexer, r.FilterName, r.Protocol, r.Implementation, r.Timestamp, r.GroupID, r.TorrentID, r.TorrentURL, r.TorrentName, r.Size, r.Title, r.Category, r.Season, r.Episode, r.Year, r.Resolution, r.Source, codecStr, r.Container, hdrStr, r.Group, r.Proper, r.Repack, r.Website, r.Type, r.Origin, pq.Array(r.Tags), r.Uploader, r.PreTime, r.FilterID).
 		Suffix("RETURNING id").RunWith(repo.db.handler)
 
 	// return values
@@ -58,6 +59,7 @@ func (repo *ReleaseRepo) StoreReleaseActionStatus(ctx context.Context, a *domain
 			Update("release_action_status").
 			Set("status", a.Status).
 			Set("rejections", pq.Array(a.Rejections)).
+			Set("log", a.Log).
 			Set("timestamp", a.Timestamp).
 			Where("id = ?", a.ID).
 			Where("release_id = ?", a.ReleaseID)
@@ -75,8 +77,8 @@ func (repo *ReleaseRepo) StoreReleaseActionStatus(ctx context.Context, a *domain
 	} else {
 		queryBuilder := repo.db.squirrel.
 			Insert("release_action_status").
-			Columns("status", "action", "type", "client", "filter", "rejections", "timestamp", "release_id").
-			Values(a.Status, a.Action, a.Type, a.Client, a.Filter, pq.Array(a.Rejections), a.Timestamp, a.ReleaseID).
+			Columns("status", "action", "type", "client", "filter", "rejections", "log", "timestamp", "release_id").
+			Values(a.Status, a.Action, a.Type, a.Client, a.Filter, pq.Array(a.Rejections), a.Log, a.Timestamp, a.ReleaseID).
 			Suffix("RETURNING id").RunWith(repo.db.handler)
 
 		// return values
@@ -95,6 +97,23 @@ func (repo *ReleaseRepo) StoreReleaseActionStatus(ctx context.Context, a *domain
 	return nil
 }
 
+func (repo *ReleaseRepo) UpdateFilterStatus(ctx context.Context, id int64, status domain.ReleaseFilterStatus) error {
+	query, args, err := repo.db.squirrel.
+		Update(`"release"`).
+		Set("filter_status", status).
+		Where(sq.Eq{"id": id}).
+		ToSql()
+	if err != nil {
+		return errors.Wrap(err, "error building query")
+	}
+
+	if _, err := repo.db.handler.ExecContext(ctx, query, args...); err != nil {
+		return errors.Wrap(err, "error executing query")
+	}
+
+	return nil
+}
+
 func (repo *ReleaseRepo) Find(ctx context.Context, params domain.ReleaseQueryParams) ([]*domain.Release, int64, int64, error) {
 	tx, err := repo.db.BeginTx(ctx, &sql.TxOptions{})
 	if err != nil {
@@ -126,37 +145,54 @@ func (repo *ReleaseRepo) findReleases(ctx context.Context, tx *Tx, params domain
 	queryBuilder := repo.db.squirrel.
 		Select("r.id", "r.filter_status", "r.rejections", "r.indexer", "r.filter", "r.protocol", "r.title", "r.torrent_name", "r.size", "r.timestamp", "COUNT(*) OVER() AS total_count").
 		From("release r").
-		OrderBy("r.timestamp DESC")
+		OrderBy("r.id DESC")
 
-	if params.Limit > 0 {
-		queryBuilder = queryBuilder.Limit(params.Limit)
-	} else {
-		queryBuilder = queryBuilder.Limit(20)
+	limit := params.Limit
+	if limit == 0 {
+		limit = 20
 	}
+	queryBuilder = queryBuilder.Limit(limit)
 
 	if params.Offset > 0 {
 		queryBuilder = queryBuilder.Offset(params.Offset)
 	}
 
+	// ids only go up so the cursor keeps its place while new releases come in
 	if params.Cursor > 0 {
 		queryBuilder = queryBuilder.Where(sq.Lt{"r.id": params.Cursor})
 	}
 
-	if params.Search != "" {
-		queryBuilder = queryBuilder.Where("r.torrent_name LIKE ?", fmt.Sprint("%", params.Search, "%"))
+	if terms := domain.SearchTerms(params.Search); len(terms) > 0 {
+		queryBuilder = repo.searchTorrentName(queryBuilder, terms)
 	}
 
-	if params.Filters.Indexers != nil {
-		filter := sq.And{}
-		for _, v := range params.Filters.Indexers {
-			filter = append(filter, sq.Eq{"r.indexer": v})
-		}
+	if len(params.Filters.Indexers) > 0 {
+		queryBuilder = queryBuilder.Where(sq.Eq{"r.indexer": params.Filters.Indexers})
+	}
 
-		queryBuilder = queryBuilder.Where(filter)
+	if len(params.Filters.Filters) > 0 {
+		queryBuilder = queryBuilder.Where(sq.Eq{"r.filter": params.Filters.Filters})
 	}
 
 	if params.Filters.PushStatus != "" {
-		queryBuilder = queryBuilder.InnerJoin("release_action_status ras ON r.id = ras.release_id").Where(sq.Eq{"ras.status": params.Filters.PushStatus})
+		queryBuilder = queryBuilder.Where(sq.Expr("EXISTS (SELECT 1 FROM release_action_status ras WHERE ras.release_id = r.id AND ras.status = ?)", params.Filters.PushStatus))
+	}
+
+	// sqlite compares the timestamps as text, they are stored in local time like time.Now()
+	if !params.Filters.From.IsZero() {
+		queryBuilder = queryBuilder.Where(sq.GtOrEq{"r.timestamp": params.Filters.From.Local()})
+	}
+
+	if !params.Filters.To.IsZero() {
+		queryBuilder = queryBuilder.Where(sq.Lt{"r.timestamp": params.Filters.To.Local()})
+	}
+
+	if params.Filters.MinSize > 0 {
+		queryBuilder = queryBuilder.Where(sq.GtOrEq{"r.size": params.Filters.MinSize})
+	}
+
+	if params.Filters.MaxSize > 0 {
+		queryBuilder = queryBuilder.Where(sq.LtOrEq{"r.size": params.Filters.MaxSize})
 	}
 
 	query, args, err := queryBuilder.ToSql()
@@ -196,15 +232,88 @@ func (repo *ReleaseRepo) findReleases(ctx context.Context, tx *Tx, params domain
 		res = append(res, &rls)
 	}
 
+	// a short page is the last one
 	nextCursor := int64(0)
-	if len(res) > 0 {
-		lastID := res[len(res)-1].ID
-		nextCursor = lastID
+	if len(res) > 0 && uint64(len(res)) == limit {
+		nextCursor = res[len(res)-1].ID
 	}
 
 	return res, nextCursor, countItems, nil
 }
 
+// searchTorrentName matches releases whose name contains all terms, the last letters of a term can be left out.
+// Sqlite searches the release_fts table and postgres the tsvector index of the torrent name.
+func (repo *ReleaseRepo) searchTorrentName(queryBuilder sq.SelectBuilder, terms []string) sq.SelectBuilder {
+	if repo.db.Driver == "sqlite" {
+		return queryBuilder.Where("r.id IN (SELECT rowid FROM release_fts WHERE release_fts MATCH ?)", ftsMatchQuery(terms))
+	}
+
+	return queryBuilder.Where("to_tsvector('simple', translate(coalesce(r.torrent_name, ''), '._-', '   ')) @@ to_tsquery('simple', ?)", tsQuery(terms))
+}
+
+// ftsMatchQuery builds a sqlite fts5 query matching all terms as prefixes
+func ftsMatchQuery(terms []string) string {
+	parts := make([]string, 0, len(terms))
+	for _, t := range terms {
+		parts = append(parts, `"`+t+`"*`)
+	}
+
+	return strings.Join(parts, " ")
+}
+
+// tsQuery builds a postgres tsquery matching all terms as prefixes
+func tsQuery(terms []string) string {
+	parts := make([]string, 0, len(terms))
+	for _, t := range terms {
+		parts = append(parts, t+":*")
+	}
+
+	return strings.Join(parts, " & ")
+}
+
+func (repo *ReleaseRepo) FindByID(ctx context.Context, id int64) (*domain.Release, error) {
+	queryBuilder := repo.db.squirrel.
+		Select("r.id", "r.filter_status", "r.rejections", "r.indexer", "r.filter", "r.protocol", "r.implementation", "r.timestamp", "r.group_id", "r.torrent_id", "r.torrent_url", "r.torrent_name", "r.size", "r.category", "r.tags", "r.uploader", "r.pre_time", "r.filter_id").
+		From("release r").
+		Where(sq.Eq{"r.id": id})
+
+	query, args, err := queryBuilder.ToSql()
+	if err != nil {
+		return nil, errors.Wrap(err, "error building query")
+	}
+
+	row := repo.db.handler.QueryRowContext(ctx, query, args...)
+	if err := row.Err(); err != nil {
+		return nil, errors.Wrap(err, "error executing query")
+	}
+
+	var rls domain.Release
+
+	var indexer, filter, implementation, groupID, torrentID, torrentURL, category, uploader, preTime sql.NullString
+	var filterID sql.NullInt64
+
+	if err := row.Scan(&rls.ID, &rls.FilterStatus, pq.Array(&rls.Rejections), &indexer, &filter, &rls.Protocol, &implementation, &rls.Timestamp, &groupID, &torrentID, &torrentURL, &rls.TorrentName, &rls.Size, &category, pq.Array(&rls.Tags), &uploader, &preTime, &filterID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errors.New("release not found: %v", id)
+		}
+
+		return nil, errors.Wrap(err, "error scanning row")
+	}
+
+	rls.Indexer = indexer.String
+	rls.FilterName = filter.String
+	rls.Implementation = domain.ReleaseImplementation(implementation.String)
+	rls.GroupID = groupID.String
+	rls.TorrentID = torrentID.String
+	rls.TorrentURL = torrentURL.String
+	rls.Category = category.String
+	rls.Uploader = uploader.String
+	rls.PreTime = preTime.String
+	rls.FilterID = int(filterID.Int64)
+
+	return &rls, nil
+}
+
 func (repo *ReleaseRepo) FindRecent(ctx context.Context) ([]*domain.Release, error) {
 	tx, err := repo.db.BeginTx(ctx, &sql.TxOptions{})
 	if err != nil {
@@ -312,7 +421,7 @@ func (repo *ReleaseRepo) GetIndexerOptions(ctx context.Context) ([]string, error
 func (repo *ReleaseRepo) GetActionStatusByReleaseID(ctx context.Context, releaseID int64) ([]domain.ReleaseActionStatus, error) {
 
 	queryBuilder := repo.db.squirrel.
-		Select("id", "status", "action", "type", "client", "filter", "rejections", "timestamp").
+		Select("id", "status", "action", "type", "client", "filter", "rejections", "log", "timestamp").
 		From("release_action_status").
 		Where("release_id = ?", releaseID)
 
@@ -338,14 +447,15 @@ func (repo *ReleaseRepo) GetActionStatusByReleaseID(ctx context.Context, release
 	for rows.Next() {
 		var rls domain.ReleaseActionStatus
 
-		var client, filter sql.NullString
+		var client, filter, log sql.NullString
 
-		if err := rows.Scan(&rls.ID, &rls.Status, &rls.Action, &rls.Type, &client, &filter, pq.Array(&rls.Rejections), &rls.Timestamp); err != nil {
+		if err := rows.Scan(&rls.ID, &rls.Status, &rls.Action, &rls.Type, &client, &filter, pq.Array(&rls.Rejections), &log, &rls.Timestamp); err != nil {
 			return res, errors.Wrap(err, "error scanning row")
 		}
 
 		rls.Client = client.String
 		rls.Filter = filter.String
+		rls.Log = log.String
 
 		res = append(res, rls)
 	}
@@ -356,7 +466,7 @@ func (repo *ReleaseRepo) GetActionStatusByReleaseID(ctx context.Context, release
 func (repo *ReleaseRepo) attachActionStatus(ctx context.Context, tx *Tx, releaseID int64) ([]domain.ReleaseActionStatus, error) {
 
 	queryBuilder := repo.db.squirrel.
-		Select("id", "status", "action", "type", "client", "filter", "rejections", "timestamp").
+		Select("id", "status", "action", "type", "client", "filter", "rejections", "log", "timestamp").
 		From("release_action_status").
 		Where("release_id = ?", releaseID)
 
@@ -381,14 +491,15 @@ func (repo *ReleaseRepo) attachActionStatus(ctx context.Context, tx *Tx, release
 	for rows.Next() {
 		var rls domain.ReleaseActionStatus
 
-		var client, filter sql.NullString
+		var client, filter, log sql.NullString
 
-		if err := rows.Scan(&rls.ID, &rls.Status, &rls.Action, &rls.Type, &client, &filter, pq.Array(&rls.Rejections), &rls.Timestamp); err != nil {
+		if err := rows.Scan(&rls.ID, &rls.Status, &rls.Action, &rls.Type, &client, &filter, pq.Array(&rls.Rejections), &log, &rls.Timestamp); err != nil {
 			return res, errors.Wrap(err, "error scanning row")
 		}
 
 		rls.Client = client.String
 		rls.Filter = filter.String
+		rls.Log = log.String
 
 		res = append(res, rls)
 	}
@@ -446,3 +557,92 @@ func (repo *ReleaseRepo) Delete(ctx context.Context) error {
 
 	return nil
 }
+
+// Prune deletes releases outside of the retention limits and returns how many were deleted.
+// Releases with a pending action retry are kept until the retry is done.
+func (repo *ReleaseRepo) Prune(ctx context.Context, retention domain.ReleaseRetention) (int64, error) {
+	tx, err := repo.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+
+	defer tx.Rollback()
+
+	var deleted int64
+
+	prune := func(cond sq.Sqlizer) error {
+		// subqueries are built without placeholder format, the outer query numbers all placeholders
+		pendingRetries := sq.
+			Select("release_id").
+			From("action_retry").
+			Where(sq.Eq{"status": domain.ActionRetryStatusPending})
+
+		pendingApprovals := sq.
+			Select("release_id").
+			From("release_approval").
+			Where(sq.Eq{"status": domain.ReleaseApprovalStatusPending})
+
+		queryBuilder := repo.db.squirrel.
+			Delete(`"release"`).
+			Where(cond).
+			Where(sq.Expr("id NOT IN (?)", pendingRetries)).
+			Where(sq.Expr("id NOT IN (?)", pendingApprovals))
+
+		if retention.KeepApproved {
+			queryBuilder = queryBuilder.Where(sq.NotEq{"filter_status": domain.ReleaseStatusFilterApproved})
+		}
+
+		query, args, err := queryBuilder.ToSql()
+		if err != nil {
+			return errors.Wrap(err, "error building query")
+		}
+
+		res, err := tx.ExecContext(ctx, query, args...)
+		if err != nil {
+			return errors.Wrap(err, "error executing query")
+		}
+
+		rows, err := res.RowsAffected()
+		if err != nil {
+			return errors.Wrap(err, "error getting rows affected")
+		}
+
+		deleted += rows
+
+		return nil
+	}
+
+	if retention.MaxAgeDays > 0 {
+		if err := prune(sq.Lt{"timestamp": time.Now().Add(-retention.MaxAge())}); err != nil {
+			return 0, err
+		}
+	}
+
+	if retention.MaxRows > 0 {
+		// id of the newest release past the limit, it and everything older is pruned
+		newest := sq.
+			Select("id").
+			From(`"release"`).
+			OrderBy("id DESC").
+			Limit(1).
+			Offset(uint64(retention.MaxRows))
+
+		if err := prune(sq.Expr("id <= (?)", newest)); err != nil {
+			return 0, err
+		}
+	}
+
+	// foreign keys are not enforced in sqlite so clean up after the pruned releases
+	for _, table := range []string{"release_action_status", "action_retry", "release_approval"} {
+		query := fmt.Sprintf(`DELETE FROM %s WHERE release_id NOT IN (SELECT id FROM "release")`, table)
+		if _, err := tx.ExecContext(ctx, query); err != nil {
+			return 0, errors.Wrap(err, "error executing query")
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, errors.Wrap(err, "error commit transaction prune")
+	}
+
+	return deleted, nil
+}
@@ -0,0 +1,223 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/internal/logger"
+	"github.com/autobrr/autobrr/pkg/errors"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/rs/zerolog"
+)
+
+type ReleaseApprovalRepo struct {
+	log zerolog.Logger
+	db  *DB
+}
+
+func NewReleaseApprovalRepo(log logger.Logger, db *DB) domain.ReleaseApprovalRepo {
+	return &ReleaseApprovalRepo{
+		log: log.With().Str("repo", "release_approval").Logger(),
+		db:  db,
+	}
+}
+
+func (r *ReleaseApprovalRepo) selectApproval() sq.SelectBuilder {
+	return r.db.squirrel.
+		Select(
+			"id",
+			"release_id",
+			"filter_id",
+			"torrent_name",
+			"indexer",
+			"filter",
+			"size",
+			"status",
+			"decided_by",
+			"decided_at",
+			"expires_at",
+			"created_at",
+		).
+		From("release_approval")
+}
+
+// List returns the approvals with the status, newest first. An empty status returns all approvals.
+func (r *ReleaseApprovalRepo) List(ctx context.Context, status domain.ReleaseApprovalStatus) ([]*domain.ReleaseApproval, error) {
+	queryBuilder := r.selectApproval().OrderBy("created_at DESC")
+	if status != "" {
+		queryBuilder = queryBuilder.Where(sq.Eq{"status": status})
+	}
+
+	query, args, err := queryBuilder.ToSql()
+	if err != nil {
+		return nil, errors.Wrap(err, "error building query")
+	}
+
+	rows, err := r.db.handler.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, errors.Wrap(err, "error executing query")
+	}
+
+	defer rows.Close()
+
+	approvals := make([]*domain.ReleaseApproval, 0)
+	for rows.Next() {
+		approval, err := scanReleaseApproval(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		approvals = append(approvals, approval)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errors.Wrap(err, "error rows list")
+	}
+
+	return approvals, nil
+}
+
+func (r *ReleaseApprovalRepo) FindByID(ctx context.Context, id int) (*domain.ReleaseApproval, error) {
+	query, args, err := r.selectApproval().Where(sq.Eq{"id": id}).ToSql()
+	if err != nil {
+		return nil, errors.Wrap(err, "error building query")
+	}
+
+	row := r.db.handler.QueryRowContext(ctx, query, args...)
+	if err := row.Err(); err != nil {
+		return nil, errors.Wrap(err, "error executing query")
+	}
+
+	approval, err := scanReleaseApproval(row)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errors.New("release approval not found: %v", id)
+		}
+
+		return nil, err
+	}
+
+	return approval, nil
+}
+
+type releaseApprovalScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanReleaseApproval(row releaseApprovalScanner) (*domain.ReleaseApproval, error) {
+	var a domain.ReleaseApproval
+
+	var filterID sql.NullInt32
+	var size sql.NullInt64
+	var torrentName, indexer, filter, decidedBy sql.NullString
+	var decidedAt sql.NullTime
+
+	if err := row.Scan(&a.ID, &a.ReleaseID, &filterID, &torrentName, &indexer, &filter, &size, &a.Status, &decidedBy, &decidedAt, &a.ExpiresAt, &a.CreatedAt); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, err
+		}
+
+		return nil, errors.Wrap(err, "error scanning row")
+	}
+
+	a.FilterID = int(filterID.Int32)
+	a.TorrentName = torrentName.String
+	a.Indexer = indexer.String
+	a.Filter = filter.String
+	a.Size = uint64(size.Int64)
+	a.DecidedBy = decidedBy.String
+
+	if decidedAt.Valid {
+		a.DecidedAt = &decidedAt.Time
+	}
+
+	return &a, nil
+}
+
+func (r *ReleaseApprovalRepo) Store(ctx context.Context, approval *domain.ReleaseApproval) error {
+	queryBuilder := r.db.squirrel.
+		Insert("release_approval").
+		Columns(
+			"release_id",
+			"filter_id",
+			"torrent_name",
+			"indexer",
+			"filter",
+			"size",
+			"status",
+			"expires_at",
+		).
+		Values(
+			approval.ReleaseID,
+			toNullInt32(int32(approval.FilterID)),
+			toNullString(approval.TorrentName),
+			toNullString(approval.Indexer),
+			toNullString(approval.Filter),
+			approval.Size,
+			approval.Status,
+			approval.ExpiresAt,
+		).
+		Suffix("RETURNING id, created_at").RunWith(r.db.handler)
+
+	if err := queryBuilder.QueryRowContext(ctx).Scan(&approval.ID, &approval.CreatedAt); err != nil {
+		return errors.Wrap(err, "error executing query")
+	}
+
+	r.log.Debug().Msgf("release_approval.store: added new %v", approval.ID)
+
+	return nil
+}
+
+func (r *ReleaseApprovalRepo) Update(ctx context.Context, approval *domain.ReleaseApproval) (bool, error) {
+	// only a pending approval can be decided, concurrent decisions of the same approval update it once
+	queryBuilder := r.db.squirrel.
+		Update("release_approval").
+		Set("status", approval.Status).
+		Set("decided_by", toNullString(approval.DecidedBy)).
+		Set("decided_at", approval.DecidedAt).
+		Where(sq.Eq{"id": approval.ID}).
+		Where(sq.Eq{"status": domain.ReleaseApprovalStatusPending})
+
+	query, args, err := queryBuilder.ToSql()
+	if err != nil {
+		return false, errors.Wrap(err, "error building query")
+	}
+
+	res, err := r.db.handler.ExecContext(ctx, query, args...)
+	if err != nil {
+		return false, errors.Wrap(err, "error executing query")
+	}
+
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return false, errors.Wrap(err, "error getting rows affected")
+	}
+
+	return rows == 1, nil
+}
+
+func (r *ReleaseApprovalRepo) Expire(ctx context.Context, now time.Time) (int64, error) {
+	query, args, err := r.db.squirrel.
+		Update("release_approval").
+		Set("status", domain.ReleaseApprovalStatusExpired).
+		Where(sq.Eq{"status": domain.ReleaseApprovalStatusPending}).
+		Where(sq.Lt{"expires_at": now}).
+		ToSql()
+	if err != nil {
+		return 0, errors.Wrap(err, "error building query")
+	}
+
+	res, err := r.db.handler.ExecContext(ctx, query, args...)
+	if err != nil {
+		return 0, errors.Wrap(err, "error executing query")
+	}
+
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return 0, errors.Wrap(err, "error getting rows affected")
+	}
+
+	return rows, nil
+}
@@ -0,0 +1,103 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/pkg/errors"
+
+	sq "github.com/Masterminds/squirrel"
+)
+
+func (repo *ReleaseRepo) ClaimDedup(ctx context.Context, key string, since time.Time, release *domain.Release) (*domain.ReleaseDedup, error) {
+	// the insert only replaces a row older than the window so concurrent announces can't both claim the key
+	query, args, err := repo.db.squirrel.
+		Insert("release_dedup").
+		Columns("key", "indexer", "torrent_name", "created_at").
+		Values(key, release.Indexer, release.TorrentName, time.Now().UTC()).
+		Suffix("ON CONFLICT (key) DO UPDATE SET indexer = excluded.indexer, torrent_name = excluded.torrent_name, created_at = excluded.created_at WHERE release_dedup.created_at < ?", since.UTC()).
+		ToSql()
+	if err != nil {
+		return nil, errors.Wrap(err, "error building query")
+	}
+
+	res, err := repo.db.handler.ExecContext(ctx, query, args...)
+	if err != nil {
+		return nil, errors.Wrap(err, "error executing query")
+	}
+
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return nil, errors.Wrap(err, "error getting rows affected")
+	}
+
+	if rows > 0 {
+		return nil, nil
+	}
+
+	query, args, err = repo.db.squirrel.
+		Select("key", "indexer", "torrent_name", "created_at").
+		From("release_dedup").
+		Where(sq.Eq{"key": key}).
+		ToSql()
+	if err != nil {
+		return nil, errors.Wrap(err, "error building query")
+	}
+
+	var dedup domain.ReleaseDedup
+	if err := repo.db.handler.QueryRowContext(ctx, query, args...).Scan(&dedup.Key, &dedup.Indexer, &dedup.TorrentName, &dedup.CreatedAt); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			// pruned in between, nothing to be a duplicate of
+			return nil, nil
+		}
+
+		return nil, errors.Wrap(err, "error scanning row")
+	}
+
+	// the same announce matched by another filter is not a duplicate
+	if dedup.SameRelease(release) {
+		return nil, nil
+	}
+
+	return &dedup, nil
+}
+
+func (repo *ReleaseRepo) DeleteDedupKey(ctx context.Context, key string, release *domain.Release) error {
+	query, args, err := repo.db.squirrel.
+		Delete("release_dedup").
+		Where(sq.Eq{"key": key, "indexer": release.Indexer, "torrent_name": release.TorrentName}).
+		ToSql()
+	if err != nil {
+		return errors.Wrap(err, "error building query")
+	}
+
+	if _, err := repo.db.handler.ExecContext(ctx, query, args...); err != nil {
+		return errors.Wrap(err, "error executing query")
+	}
+
+	return nil
+}
+
+func (repo *ReleaseRepo) DeleteDedup(ctx context.Context, before time.Time) (int64, error) {
+	query, args, err := repo.db.squirrel.
+		Delete("release_dedup").
+		Where(sq.Lt{"created_at": before.UTC()}).
+		ToSql()
+	if err != nil {
+		return 0, errors.Wrap(err, "error building query")
+	}
+
+	res, err := repo.db.handler.ExecContext(ctx, query, args...)
+	if err != nil {
+		return 0, errors.Wrap(err, "error executing query")
+	}
+
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return 0, errors.Wrap(err, "error getting rows affected")
+	}
+
+	return rows, nil
+}
@@ -15,6 +15,12 @@ func (db *DB) openSQLite() error {
 		return errors.New("DSN required")
 	}
 
+	// replace the database with a backup before opening it
+	if err := db.restoreSQLite(); err != nil {
+		db.log.Fatal().Err(err).Msg("could not restore database")
+		return err
+	}
+
 	var err error
 
 	// open database connection
@@ -19,6 +19,7 @@ CREATE TABLE indexer
     enabled        BOOLEAN,
     name           TEXT NOT NULL,
     settings       TEXT,
+    proxy          TEXT DEFAULT '',
     created_at     TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
     updated_at     TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
     UNIQUE (identifier)
@@ -39,6 +40,12 @@ CREATE TABLE irc_network
     invite_command      TEXT,
     nickserv_account    TEXT,
     nickserv_password   TEXT,
+    nickserv_regain     TEXT,
+    sasl_mechanism      TEXT,
+    tls_client_cert     TEXT,
+    tls_client_key      TEXT,
+    bouncer             BOOLEAN DEFAULT FALSE,
+    proxy               TEXT DEFAULT '',
     connected           BOOLEAN,
     connected_since     TIMESTAMP,
     created_at          TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
@@ -78,6 +85,8 @@ CREATE TABLE filter
     freeleech                      BOOLEAN,
     freeleech_percent              TEXT,
     shows                          TEXT,
+    match_list_id                  INTEGER,
+    except_list_id                 INTEGER,
     seasons                        TEXT,
     episodes                       TEXT,
     resolutions                    TEXT []   DEFAULT '{}' NOT NULL,
@@ -106,6 +115,22 @@ CREATE TABLE filter
     except_uploaders               TEXT,
     tags                           TEXT,
     except_tags                    TEXT,
+    capture_patterns               TEXT,
+    smart_delay                    INTEGER DEFAULT 0,
+    smart_delay_indexers           TEXT,
+    smart_delay_prefer_size        TEXT,
+    dedup_key                      TEXT,
+    dedup_window                   INTEGER DEFAULT 0,
+    arr_skip_duplicates            BOOLEAN   DEFAULT FALSE,
+    arr_only_monitored             BOOLEAN   DEFAULT FALSE,
+    arr_season_pack_threshold      INTEGER   DEFAULT 0,
+    schedule                       TEXT,
+    torrent_file_check             BOOLEAN   DEFAULT FALSE,
+    require_approval               BOOLEAN   DEFAULT FALSE,
+    min_files                      INTEGER   DEFAULT 0,
+    max_files                      INTEGER   DEFAULT 0,
+    match_file_extensions          TEXT,
+    except_file_extensions         TEXT,
     origins                        TEXT []   DEFAULT '{}',
     except_origins                 TEXT []   DEFAULT '{}',
     external_script_enabled        BOOLEAN   DEFAULT FALSE,
@@ -116,6 +141,8 @@ CREATE TABLE filter
     external_webhook_host          TEXT,
     external_webhook_data          TEXT,
     external_webhook_expect_status INTEGER,
+    external_webhook_type          TEXT      DEFAULT 'HTTP',
+    external_webhook_parse_body    BOOLEAN   DEFAULT FALSE,
     created_at                     TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
     updated_at                     TIMESTAMP DEFAULT CURRENT_TIMESTAMP
 );
@@ -141,7 +168,10 @@ CREATE TABLE client
     tls_skip_verify BOOLEAN,
     username 		TEXT,
     password 		TEXT,
-    settings 		JSON
+    settings 		JSON,
+    health_status   TEXT DEFAULT 'UNKNOWN',
+    health_error    TEXT,
+    health_checked_at TIMESTAMP
 );
 
 CREATE TABLE action
@@ -152,11 +182,16 @@ CREATE TABLE action
     enabled                 BOOLEAN,
     exec_cmd                TEXT,
     exec_args               TEXT,
+    exec_timeout            INTEGER DEFAULT 0,
+    bandwidth_priority      INTEGER DEFAULT 0,
+    bandwidth_group         TEXT,
+    sequential_download     BOOLEAN DEFAULT FALSE,
     watch_folder            TEXT,
     category                TEXT,
     tags                    TEXT,
     label                   TEXT,
     save_path               TEXT,
+    move_completed_path     TEXT,
     paused                  BOOLEAN,
     ignore_rules            BOOLEAN,
     skip_hash_check         BOOLEAN DEFAULT false,
@@ -176,8 +211,15 @@ CREATE TABLE action
     webhook_headers         TEXT[] DEFAULT '{}',
     client_id               INTEGER,
     filter_id               INTEGER,
+    depends_on_filter_id    INTEGER,
+    fallback_client_id      INTEGER,
+    external_download_client_id INTEGER,
+    external_download_client    TEXT,
+    run_condition               TEXT,
     FOREIGN KEY (filter_id) REFERENCES filter (id),
-    FOREIGN KEY (client_id) REFERENCES client (id) ON DELETE SET NULL
+    FOREIGN KEY (client_id) REFERENCES client (id) ON DELETE SET NULL,
+    FOREIGN KEY (depends_on_filter_id) REFERENCES filter (id) ON DELETE SET NULL,
+    FOREIGN KEY (fallback_client_id) REFERENCES client (id) ON DELETE SET NULL
 );
 
 CREATE TABLE "release"
@@ -192,6 +234,7 @@ CREATE TABLE "release"
     timestamp         TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
     group_id          TEXT,
     torrent_id        TEXT,
+    torrent_url       TEXT,
     torrent_name      TEXT,
     size              INTEGER,
     title             TEXT,
@@ -230,6 +273,29 @@ CREATE INDEX release_timestamp_index
 CREATE INDEX release_torrent_name_index
     ON "release" (torrent_name);
 
+CREATE VIRTUAL TABLE release_fts USING fts5
+(
+    torrent_name,
+    content = 'release',
+    content_rowid = 'id'
+);
+
+CREATE TRIGGER release_fts_insert AFTER INSERT ON "release"
+BEGIN
+    INSERT INTO release_fts (rowid, torrent_name) VALUES (new.id, new.torrent_name);
+END;
+
+CREATE TRIGGER release_fts_delete AFTER DELETE ON "release"
+BEGIN
+    INSERT INTO release_fts (release_fts, rowid, torrent_name) VALUES ('delete', old.id, old.torrent_name);
+END;
+
+CREATE TRIGGER release_fts_update AFTER UPDATE OF torrent_name ON "release"
+BEGIN
+    INSERT INTO release_fts (release_fts, rowid, torrent_name) VALUES ('delete', old.id, old.torrent_name);
+    INSERT INTO release_fts (rowid, torrent_name) VALUES (new.id, new.torrent_name);
+END;
+
 CREATE TABLE release_action_status
 (
 	id            INTEGER PRIMARY KEY,
@@ -268,6 +334,10 @@ CREATE TABLE notification
 	rooms      TEXT,
 	targets    TEXT,
 	devices    TEXT,
+	topic      TEXT,
+	priority   INTEGER,
+	click_url  TEXT,
+	templates  TEXT,
 	created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
 	updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
 );
@@ -281,10 +351,18 @@ CREATE TABLE feed
 	enabled      BOOLEAN,
 	url          TEXT,
 	interval     INTEGER,
+	backfill     INTEGER   DEFAULT 0,
 	categories   TEXT []   DEFAULT '{}' NOT NULL,
 	capabilities TEXT []   DEFAULT '{}' NOT NULL,
 	api_key      TEXT,
 	settings     TEXT,
+	cookie       TEXT,
+	headers      TEXT,
+	username     TEXT,
+	password     TEXT,
+	last_success_at TIMESTAMP,
+	last_error   TEXT,
+	last_error_at TIMESTAMP,
     indexer_id   INTEGER,
     created_at   TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
     updated_at   TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
@@ -304,8 +382,176 @@ CREATE TABLE api_key
     name       TEXT,
     key        TEXT PRIMARY KEY,
     scopes     TEXT []   DEFAULT '{}' NOT NULL,
+    expires_at TIMESTAMP,
     created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
 );
+
+CREATE TABLE quota
+(
+    id            INTEGER PRIMARY KEY,
+    name          TEXT NOT NULL,
+    enabled       BOOLEAN DEFAULT TRUE,
+    scope         TEXT NOT NULL,
+    filter_id     INTEGER,
+    indexer       TEXT,
+    max_downloads INTEGER DEFAULT 0,
+    max_size      TEXT,
+    period        TEXT,
+    created_at    TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at    TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (filter_id) REFERENCES filter(id) ON DELETE CASCADE
+);
+
+CREATE TABLE instance_lease
+(
+    name       TEXT PRIMARY KEY,
+    holder     TEXT NOT NULL,
+    expires_at TIMESTAMP NOT NULL
+);
+
+CREATE TABLE release_claim
+(
+    key        TEXT PRIMARY KEY,
+    holder     TEXT NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE release_dedup
+(
+    key          TEXT PRIMARY KEY,
+    indexer      TEXT NOT NULL,
+    torrent_name TEXT NOT NULL,
+    created_at   TIMESTAMP NOT NULL
+);
+
+CREATE TABLE action_retry
+(
+    id              INTEGER PRIMARY KEY,
+    release_id      INTEGER NOT NULL,
+    filter_id       INTEGER,
+    action_id       INTEGER NOT NULL,
+    torrent_name    TEXT,
+    indexer         TEXT,
+    filter          TEXT,
+    action          TEXT,
+    action_type     TEXT,
+    status          TEXT NOT NULL,
+    attempts        INTEGER DEFAULT 0,
+    last_error      TEXT,
+    next_attempt_at TIMESTAMP NOT NULL,
+    expires_at      TIMESTAMP NOT NULL,
+    created_at      TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (release_id) REFERENCES "release"(id) ON DELETE CASCADE,
+    FOREIGN KEY (action_id) REFERENCES action(id) ON DELETE CASCADE
+);
+
+CREATE INDEX action_retry_status_next_attempt_at_index
+    ON action_retry (status, next_attempt_at);
+
+CREATE TABLE release_approval
+(
+    id           INTEGER PRIMARY KEY,
+    release_id   INTEGER NOT NULL,
+    filter_id    INTEGER,
+    torrent_name TEXT,
+    indexer      TEXT,
+    filter       TEXT,
+    size         BIGINT,
+    status       TEXT NOT NULL,
+    decided_by   TEXT,
+    decided_at   TIMESTAMP,
+    expires_at   TIMESTAMP NOT NULL,
+    created_at   TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (release_id) REFERENCES "release"(id) ON DELETE CASCADE
+);
+
+CREATE INDEX release_approval_status_index
+    ON release_approval (status);
+
+CREATE TABLE filter_rejection
+(
+    filter_id INTEGER NOT NULL,
+    reason    TEXT NOT NULL,
+    bucket    TIMESTAMP NOT NULL,
+    count     INTEGER DEFAULT 0 NOT NULL,
+    PRIMARY KEY (filter_id, reason, bucket),
+    FOREIGN KEY (filter_id) REFERENCES filter(id) ON DELETE CASCADE
+);
+
+CREATE INDEX filter_rejection_bucket_index
+    ON filter_rejection (bucket);
+
+CREATE TABLE torrent_blocklist
+(
+    id         INTEGER PRIMARY KEY,
+    type       TEXT NOT NULL,
+    value      TEXT NOT NULL,
+    reason     TEXT,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (type, value)
+);
+
+CREATE TABLE audit_log
+(
+    id          INTEGER PRIMARY KEY,
+    actor       TEXT NOT NULL,
+    action      TEXT NOT NULL,
+    entity_type TEXT NOT NULL,
+    entity_id   TEXT,
+    entity_name TEXT,
+    changes     TEXT,
+    created_at  TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX audit_log_entity_index
+    ON audit_log (entity_type, entity_id);
+
+CREATE INDEX audit_log_created_at_index
+    ON audit_log (created_at);
+
+CREATE TABLE list
+(
+    id                  INTEGER PRIMARY KEY,
+    name                TEXT NOT NULL,
+    type                TEXT NOT NULL,
+    enabled             BOOLEAN DEFAULT TRUE,
+    client_id           INTEGER,
+    url                 TEXT,
+    api_key             TEXT,
+    include_unmonitored BOOLEAN DEFAULT FALSE,
+    item_count          INTEGER DEFAULT 0,
+    last_refresh_time   TIMESTAMP,
+    last_refresh_status TEXT,
+    last_refresh_error  TEXT,
+    created_at          TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at          TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (client_id) REFERENCES client(id) ON DELETE SET NULL
+);
+
+CREATE TABLE list_item
+(
+    id      INTEGER PRIMARY KEY,
+    list_id INTEGER NOT NULL,
+    title   TEXT NOT NULL,
+    FOREIGN KEY (list_id) REFERENCES list(id) ON DELETE CASCADE
+);
+
+CREATE INDEX list_item_list_id_index
+    ON list_item (list_id);
+
+CREATE TABLE announce_blocklist
+(
+    id          INTEGER PRIMARY KEY,
+    name        TEXT NOT NULL,
+    enabled     BOOLEAN DEFAULT TRUE,
+    type        TEXT NOT NULL,
+    value       TEXT NOT NULL,
+    indexer     TEXT,
+    hits        INTEGER DEFAULT 0,
+    last_hit_at TIMESTAMP,
+    created_at  TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at  TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
 `
 
 var sqliteMigrations = []string{
@@ -877,4 +1123,409 @@ CREATE INDEX indexer_identifier_index
 		created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
 	);
 	`,
+	`
+	ALTER TABLE irc_network
+		ADD COLUMN nickserv_regain TEXT;
+	`,
+	`
+	ALTER TABLE action
+		ADD COLUMN depends_on_filter_id INTEGER
+			CONSTRAINT action_depends_on_filter_id_fkey
+				REFERENCES filter (id)
+				ON DELETE SET NULL;
+	`,
+	`
+	ALTER TABLE action
+		ADD COLUMN watch_folder_mapping TEXT;
+	`,
+	`
+	ALTER TABLE filter
+		ADD COLUMN capture_patterns TEXT;
+	`,
+	`
+	ALTER TABLE feed
+		ADD COLUMN backfill INTEGER DEFAULT 0;
+	`,
+	`
+	CREATE TABLE quota
+	(
+		id            INTEGER PRIMARY KEY,
+		name          TEXT NOT NULL,
+		enabled       BOOLEAN DEFAULT TRUE,
+		scope         TEXT NOT NULL,
+		filter_id     INTEGER,
+		indexer       TEXT,
+		max_downloads INTEGER DEFAULT 0,
+		max_size      TEXT,
+		period        TEXT,
+		created_at    TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+		updated_at    TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+		FOREIGN KEY (filter_id) REFERENCES filter(id) ON DELETE CASCADE
+	);
+	`,
+	`
+	ALTER TABLE filter
+		ADD COLUMN smart_delay INTEGER DEFAULT 0;
+
+	ALTER TABLE filter
+		ADD COLUMN smart_delay_indexers TEXT;
+
+	ALTER TABLE filter
+		ADD COLUMN smart_delay_prefer_size TEXT;
+	`,
+	`
+	CREATE TABLE instance_lease
+	(
+		name       TEXT PRIMARY KEY,
+		holder     TEXT NOT NULL,
+		expires_at TIMESTAMP NOT NULL
+	);
+
+	CREATE TABLE release_claim
+	(
+		key        TEXT PRIMARY KEY,
+		holder     TEXT NOT NULL,
+		created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+	);
+	`,
+	`
+	ALTER TABLE "release"
+		ADD COLUMN torrent_url TEXT;
+	`,
+	`
+	ALTER TABLE irc_network
+		ADD COLUMN sasl_mechanism TEXT;
+
+	ALTER TABLE irc_network
+		ADD COLUMN tls_client_cert TEXT;
+
+	ALTER TABLE irc_network
+		ADD COLUMN tls_client_key TEXT;
+	`,
+	`
+	ALTER TABLE action
+		ADD COLUMN move_completed_path TEXT;
+	`,
+	`
+	ALTER TABLE filter
+		ADD COLUMN arr_skip_duplicates BOOLEAN DEFAULT FALSE;
+	`,
+	`
+	ALTER TABLE filter
+		ADD COLUMN external_webhook_type TEXT DEFAULT 'HTTP';
+
+	ALTER TABLE filter
+		ADD COLUMN external_webhook_parse_body BOOLEAN DEFAULT FALSE;
+	`,
+	`
+	ALTER TABLE notification
+		ADD COLUMN templates TEXT;
+	`,
+	`
+	ALTER TABLE notification
+		ADD COLUMN topic TEXT;
+
+	ALTER TABLE notification
+		ADD COLUMN priority INTEGER;
+
+	ALTER TABLE notification
+		ADD COLUMN click_url TEXT;
+	`,
+	`
+	ALTER TABLE filter
+		ADD COLUMN torrent_file_check BOOLEAN DEFAULT FALSE;
+
+	ALTER TABLE filter
+		ADD COLUMN min_files INTEGER DEFAULT 0;
+
+	ALTER TABLE filter
+		ADD COLUMN max_files INTEGER DEFAULT 0;
+
+	ALTER TABLE filter
+		ADD COLUMN match_file_extensions TEXT;
+
+	ALTER TABLE filter
+		ADD COLUMN except_file_extensions TEXT;
+	`,
+	`
+	ALTER TABLE feed
+		ADD COLUMN cookie TEXT;
+
+	ALTER TABLE feed
+		ADD COLUMN headers TEXT;
+
+	ALTER TABLE feed
+		ADD COLUMN username TEXT;
+
+	ALTER TABLE feed
+		ADD COLUMN password TEXT;
+
+	ALTER TABLE feed
+		ADD COLUMN last_success_at TIMESTAMP;
+
+	ALTER TABLE feed
+		ADD COLUMN last_error TEXT;
+
+	ALTER TABLE feed
+		ADD COLUMN last_error_at TIMESTAMP;
+	`,
+	`
+	ALTER TABLE action
+		ADD COLUMN fallback_client_id INTEGER
+			CONSTRAINT action_fallback_client_id_fkey
+				REFERENCES client (id)
+				ON DELETE SET NULL;
+
+	ALTER TABLE client
+		ADD COLUMN health_status TEXT DEFAULT 'UNKNOWN';
+
+	ALTER TABLE client
+		ADD COLUMN health_error TEXT;
+
+	ALTER TABLE client
+		ADD COLUMN health_checked_at TIMESTAMP;
+	`,
+	`
+	CREATE TABLE action_retry
+	(
+		id              INTEGER PRIMARY KEY,
+		release_id      INTEGER NOT NULL,
+		filter_id       INTEGER,
+		action_id       INTEGER NOT NULL,
+		torrent_name    TEXT,
+		indexer         TEXT,
+		filter          TEXT,
+		action          TEXT,
+		action_type     TEXT,
+		status          TEXT NOT NULL,
+		attempts        INTEGER DEFAULT 0,
+		last_error      TEXT,
+		next_attempt_at TIMESTAMP NOT NULL,
+		expires_at      TIMESTAMP NOT NULL,
+		created_at      TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+		FOREIGN KEY (release_id) REFERENCES "release"(id) ON DELETE CASCADE,
+		FOREIGN KEY (action_id) REFERENCES action(id) ON DELETE CASCADE
+	);
+
+	CREATE INDEX action_retry_status_next_attempt_at_index
+		ON action_retry (status, next_attempt_at);
+	`,
+	`
+	ALTER TABLE filter
+		ADD COLUMN arr_only_monitored BOOLEAN DEFAULT FALSE;
+	`,
+	`
+	ALTER TABLE api_key
+		ADD COLUMN expires_at TIMESTAMP;
+	`,
+	`
+	ALTER TABLE action
+		ADD COLUMN exec_timeout INTEGER DEFAULT 0;
+	`,
+	`
+	ALTER TABLE action
+		ADD COLUMN bandwidth_priority INTEGER DEFAULT 0;
+
+	ALTER TABLE action
+		ADD COLUMN bandwidth_group TEXT;
+
+	ALTER TABLE action
+		ADD COLUMN sequential_download BOOLEAN DEFAULT FALSE;
+	`,
+	`
+	ALTER TABLE filter
+		ADD COLUMN schedule TEXT;
+	`,
+	`
+	ALTER TABLE indexer
+		ADD COLUMN proxy TEXT DEFAULT '';
+	`,
+	`
+	CREATE TABLE filter_rejection
+	(
+		filter_id INTEGER NOT NULL,
+		reason    TEXT NOT NULL,
+		bucket    TIMESTAMP NOT NULL,
+		count     INTEGER DEFAULT 0 NOT NULL,
+		PRIMARY KEY (filter_id, reason, bucket),
+		FOREIGN KEY (filter_id) REFERENCES filter(id) ON DELETE CASCADE
+	);
+
+	CREATE INDEX filter_rejection_bucket_index
+		ON filter_rejection (bucket);
+	`,
+	`
+	ALTER TABLE action
+		ADD COLUMN external_download_client_id INTEGER;
+
+	ALTER TABLE action
+		ADD COLUMN external_download_client TEXT;
+	`,
+	`
+	CREATE TABLE release_dedup
+	(
+		key          TEXT PRIMARY KEY,
+		indexer      TEXT NOT NULL,
+		torrent_name TEXT NOT NULL,
+		created_at   TIMESTAMP NOT NULL
+	);
+
+	ALTER TABLE filter
+		ADD COLUMN dedup_key TEXT;
+
+	ALTER TABLE filter
+		ADD COLUMN dedup_window INTEGER DEFAULT 0;
+	`,
+	`
+	ALTER TABLE filter
+		ADD COLUMN arr_season_pack_threshold INTEGER DEFAULT 0;
+	`,
+	`
+	ALTER TABLE action
+		ADD COLUMN run_condition TEXT;
+	`,
+	`
+	CREATE TABLE torrent_blocklist
+	(
+		id         INTEGER PRIMARY KEY,
+		type       TEXT NOT NULL,
+		value      TEXT NOT NULL,
+		reason     TEXT,
+		created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+		UNIQUE (type, value)
+	);
+	`,
+	`
+	ALTER TABLE irc_network
+		ADD COLUMN bouncer BOOLEAN DEFAULT FALSE;
+	`,
+	`
+	CREATE TABLE audit_log
+	(
+		id          INTEGER PRIMARY KEY,
+		actor       TEXT NOT NULL,
+		action      TEXT NOT NULL,
+		entity_type TEXT NOT NULL,
+		entity_id   TEXT,
+		entity_name TEXT,
+		changes     TEXT,
+		created_at  TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+	);
+
+	CREATE INDEX audit_log_entity_index
+	    ON audit_log (entity_type, entity_id);
+
+	CREATE INDEX audit_log_created_at_index
+	    ON audit_log (created_at);
+	`,
+	`
+	CREATE TABLE list
+	(
+		id                  INTEGER PRIMARY KEY,
+		name                TEXT NOT NULL,
+		type                TEXT NOT NULL,
+		enabled             BOOLEAN DEFAULT TRUE,
+		client_id           INTEGER,
+		url                 TEXT,
+		api_key             TEXT,
+		include_unmonitored BOOLEAN DEFAULT FALSE,
+		item_count          INTEGER DEFAULT 0,
+		last_refresh_time   TIMESTAMP,
+		last_refresh_status TEXT,
+		last_refresh_error  TEXT,
+		created_at          TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+		updated_at          TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+		FOREIGN KEY (client_id) REFERENCES client(id) ON DELETE SET NULL
+	);
+
+	CREATE TABLE list_item
+	(
+		id      INTEGER PRIMARY KEY,
+		list_id INTEGER NOT NULL,
+		title   TEXT NOT NULL,
+		FOREIGN KEY (list_id) REFERENCES list(id) ON DELETE CASCADE
+	);
+
+	CREATE INDEX list_item_list_id_index
+	    ON list_item (list_id);
+
+	ALTER TABLE filter
+		ADD COLUMN match_list_id INTEGER;
+
+	ALTER TABLE filter
+		ADD COLUMN except_list_id INTEGER;
+	`,
+	`
+	CREATE TABLE release_approval
+	(
+		id           INTEGER PRIMARY KEY,
+		release_id   INTEGER NOT NULL,
+		filter_id    INTEGER,
+		torrent_name TEXT,
+		indexer      TEXT,
+		filter       TEXT,
+		size         BIGINT,
+		status       TEXT NOT NULL,
+		decided_by   TEXT,
+		decided_at   TIMESTAMP,
+		expires_at   TIMESTAMP NOT NULL,
+		created_at   TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+		FOREIGN KEY (release_id) REFERENCES "release"(id) ON DELETE CASCADE
+	);
+
+	CREATE INDEX release_approval_status_index
+	    ON release_approval (status);
+
+	ALTER TABLE filter
+		ADD COLUMN require_approval BOOLEAN DEFAULT FALSE;
+	`,
+	`
+	CREATE VIRTUAL TABLE release_fts USING fts5
+	(
+		torrent_name,
+		content = 'release',
+		content_rowid = 'id'
+	);
+
+	CREATE TRIGGER release_fts_insert AFTER INSERT ON "release"
+	BEGIN
+		INSERT INTO release_fts (rowid, torrent_name) VALUES (new.id, new.torrent_name);
+	END;
+
+	CREATE TRIGGER release_fts_delete AFTER DELETE ON "release"
+	BEGIN
+		INSERT INTO release_fts (release_fts, rowid, torrent_name) VALUES ('delete', old.id, old.torrent_name);
+	END;
+
+	CREATE TRIGGER release_fts_update AFTER UPDATE OF torrent_name ON "release"
+	BEGIN
+		INSERT INTO release_fts (release_fts, rowid, torrent_name) VALUES ('delete', old.id, old.torrent_name);
+		INSERT INTO release_fts (rowid, torrent_name) VALUES (new.id, new.torrent_name);
+	END;
+
+	INSERT INTO release_fts (release_fts) VALUES ('rebuild');
+	`,
+	`
+	CREATE TABLE announce_blocklist
+	(
+		id          INTEGER PRIMARY KEY,
+		name        TEXT NOT NULL,
+		enabled     BOOLEAN DEFAULT TRUE,
+		type        TEXT NOT NULL,
+		value       TEXT NOT NULL,
+		indexer     TEXT,
+		hits        INTEGER DEFAULT 0,
+		last_hit_at TIMESTAMP,
+		created_at  TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+		updated_at  TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+	);
+	`,
+	`
+	ALTER TABLE irc_network
+		ADD COLUMN proxy TEXT DEFAULT '';
+	`,
+	`
+	ALTER TABLE action
+		DROP COLUMN watch_folder_mapping;
+	`,
 }
@@ -0,0 +1,97 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/internal/logger"
+	"github.com/autobrr/autobrr/pkg/errors"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/rs/zerolog"
+)
+
+type TorrentBlocklistRepo struct {
+	log zerolog.Logger
+	db  *DB
+}
+
+func NewTorrentBlocklistRepo(log logger.Logger, db *DB) domain.TorrentBlocklistRepo {
+	return &TorrentBlocklistRepo{
+		log: log.With().Str("repo", "torrent_blocklist").Logger(),
+		db:  db,
+	}
+}
+
+func (r *TorrentBlocklistRepo) List(ctx context.Context) ([]domain.TorrentBlocklistEntry, error) {
+	query, args, err := r.db.squirrel.
+		Select("id", "type", "value", "reason", "created_at").
+		From("torrent_blocklist").
+		OrderBy("created_at DESC").
+		ToSql()
+	if err != nil {
+		return nil, errors.Wrap(err, "error building query")
+	}
+
+	rows, err := r.db.handler.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, errors.Wrap(err, "error executing query")
+	}
+
+	defer rows.Close()
+
+	entries := make([]domain.TorrentBlocklistEntry, 0)
+	for rows.Next() {
+		var e domain.TorrentBlocklistEntry
+		var reason sql.NullString
+
+		if err := rows.Scan(&e.ID, &e.Type, &e.Value, &reason, &e.CreatedAt); err != nil {
+			return nil, errors.Wrap(err, "error scanning row")
+		}
+
+		e.Reason = reason.String
+
+		entries = append(entries, e)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, errors.Wrap(err, "row error")
+	}
+
+	return entries, nil
+}
+
+func (r *TorrentBlocklistRepo) Store(ctx context.Context, entry *domain.TorrentBlocklistEntry) error {
+	queryBuilder := r.db.squirrel.
+		Insert("torrent_blocklist").
+		Columns("type", "value", "reason").
+		Values(entry.Type, entry.Value, toNullString(entry.Reason)).
+		Suffix("RETURNING id, created_at").RunWith(r.db.handler)
+
+	if err := queryBuilder.QueryRowContext(ctx).Scan(&entry.ID, &entry.CreatedAt); err != nil {
+		return errors.Wrap(err, "error executing query")
+	}
+
+	r.log.Debug().Msgf("torrent_blocklist.store: %v %v", entry.Type, entry.Value)
+
+	return nil
+}
+
+func (r *TorrentBlocklistRepo) Delete(ctx context.Context, id int) error {
+	query, args, err := r.db.squirrel.
+		Delete("torrent_blocklist").
+		Where(sq.Eq{"id": id}).
+		ToSql()
+	if err != nil {
+		return errors.Wrap(err, "error building query")
+	}
+
+	if _, err = r.db.handler.ExecContext(ctx, query, args...); err != nil {
+		return errors.Wrap(err, "error executing query")
+	}
+
+	r.log.Debug().Msgf("torrent_blocklist.delete: %v", id)
+
+	return nil
+}
@@ -1,6 +1,10 @@
 package domain
 
-import "context"
+import (
+	"context"
+	"sort"
+	"time"
+)
 
 type ActionRepo interface {
 	Store(ctx context.Context, action Action) (*Action, error)
@@ -8,6 +12,7 @@ type ActionRepo interface {
 	DeleteByFilterID(ctx context.Context, filterID int) error
 	FindByFilterID(ctx context.Context, filterID int) ([]*Action, error)
 	List(ctx context.Context) ([]Action, error)
+	FindFilterDependencies(ctx context.Context) (FilterDependencies, error)
 	Delete(actionID int) error
 	ToggleEnabled(actionID int) error
 }
@@ -19,12 +24,17 @@ type Action struct {
 	Enabled               bool                `json:"enabled"`
 	ExecCmd               string              `json:"exec_cmd,omitempty"`
 	ExecArgs              string              `json:"exec_args,omitempty"`
+	ExecTimeout           int                 `json:"exec_timeout,omitempty"`
 	WatchFolder           string              `json:"watch_folder,omitempty"`
 	Category              string              `json:"category,omitempty"`
 	Tags                  string              `json:"tags,omitempty"`
 	Label                 string              `json:"label,omitempty"`
 	SavePath              string              `json:"save_path,omitempty"`
+	MoveCompletedPath     string              `json:"move_completed_path,omitempty"`
 	Paused                bool                `json:"paused,omitempty"`
+	BandwidthPriority     int                 `json:"bandwidth_priority,omitempty"`
+	BandwidthGroup        string              `json:"bandwidth_group,omitempty"`
+	SequentialDownload    bool                `json:"sequential_download,omitempty"`
 	IgnoreRules           bool                `json:"ignore_rules,omitempty"`
 	SkipHashCheck         bool                `json:"skip_hash_check,omitempty"`
 	ContentLayout         ActionContentLayout `json:"content_layout,omitempty"`
@@ -43,9 +53,21 @@ type Action struct {
 	WebhookHeaders        []string            `json:"webhook_headers,omitempty"`
 	FilterID              int                 `json:"filter_id,omitempty"`
 	ClientID              int32               `json:"client_id,omitempty"`
+	DependsOnFilterID     int                 `json:"depends_on_filter_id,omitempty"`
+	FallbackClientID      int32               `json:"fallback_client_id,omitempty"`
 	Client                DownloadClient      `json:"client,omitempty"`
+
+	// arr push actions, the download client the arr sends the release to by id or name
+	ExternalDownloadClientID int32  `json:"external_download_client_id,omitempty"`
+	ExternalDownloadClient   string `json:"external_download_client,omitempty"`
+
+	// optional expression on the release, the action only runs if it is true, eg. release.Size > 10GB
+	RunCondition string `json:"run_condition,omitempty"`
 }
 
+// ActionExecDefaultTimeout is used for exec actions without a timeout set
+const ActionExecDefaultTimeout = 5 * time.Minute
+
 type ActionType string
 
 const (
@@ -56,12 +78,14 @@ const (
 	ActionTypeDelugeV2     ActionType = "DELUGE_V2"
 	ActionTypeRTorrent     ActionType = "RTORRENT"
 	ActionTypeTransmission ActionType = "TRANSMISSION"
+	ActionTypePorla        ActionType = "PORLA"
 	ActionTypeWatchFolder  ActionType = "WATCH_FOLDER"
 	ActionTypeWebhook      ActionType = "WEBHOOK"
 	ActionTypeRadarr       ActionType = "RADARR"
 	ActionTypeSonarr       ActionType = "SONARR"
 	ActionTypeLidarr       ActionType = "LIDARR"
 	ActionTypeWhisparr     ActionType = "WHISPARR"
+	ActionTypeCrossSeed    ActionType = "CROSS_SEED"
 )
 
 type ActionContentLayout string
@@ -71,3 +95,147 @@ const (
 	ActionContentLayoutSubfolderNone   ActionContentLayout = "SUBFOLDER_NONE"
 	ActionContentLayoutSubfolderCreate ActionContentLayout = "SUBFOLDER_CREATE"
 )
+
+// FilterDependencies maps a filter id to the filters its actions depend on.
+// An action with DependsOnFilterID set only runs if that filter matched the same release.
+type FilterDependencies map[int][]int
+
+// WithFilterActions returns a copy where the dependencies of filterID are replaced by those of the given actions
+func (d FilterDependencies) WithFilterActions(filterID int, actions []*Action) FilterDependencies {
+	deps := make(FilterDependencies, len(d)+1)
+	for id, dependsOn := range d {
+		if id != filterID {
+			deps[id] = dependsOn
+		}
+	}
+
+	for _, a := range actions {
+		if a == nil || !a.Enabled || a.DependsOnFilterID == 0 {
+			continue
+		}
+		deps[filterID] = append(deps[filterID], a.DependsOnFilterID)
+	}
+
+	return deps
+}
+
+// Targets returns the filters that actions of other filters depend on
+func (d FilterDependencies) Targets() map[int]struct{} {
+	targets := make(map[int]struct{}, len(d))
+	for _, dependsOn := range d {
+		for _, id := range dependsOn {
+			targets[id] = struct{}{}
+		}
+	}
+
+	return targets
+}
+
+// FindCycle returns the filter ids forming a dependency cycle or nil if there is none
+func (d FilterDependencies) FindCycle() []int {
+	const (
+		unvisited = iota
+		visiting
+		done
+	)
+
+	ids := make([]int, 0, len(d))
+	for id := range d {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+
+	state := map[int]int{}
+	var path []int
+
+	var visit func(id int) []int
+	visit = func(id int) []int {
+		switch state[id] {
+		case visiting:
+			for i, p := range path {
+				if p == id {
+					return append([]int{}, path[i:]...)
+				}
+			}
+		case done:
+			return nil
+		}
+
+		state[id] = visiting
+		path = append(path, id)
+
+		for _, dep := range d[id] {
+			if cycle := visit(dep); cycle != nil {
+				return cycle
+			}
+		}
+
+		path = path[:len(path)-1]
+		state[id] = done
+
+		return nil
+	}
+
+	for _, id := range ids {
+		if cycle := visit(id); cycle != nil {
+			return cycle
+		}
+	}
+
+	return nil
+}
+
+// SortFilters orders filters so that every filter is evaluated after the filters it depends on.
+// Otherwise, the given (priority) order is kept. Filters that can not be ordered because they
+// are part of, or depend on, a cycle are appended last and returned as unresolved.
+func (d FilterDependencies) SortFilters(filters []Filter) ([]Filter, map[int]struct{}) {
+	present := make(map[int]struct{}, len(filters))
+	for _, f := range filters {
+		present[f.ID] = struct{}{}
+	}
+
+	sorted := make([]Filter, 0, len(filters))
+	placed := make(map[int]struct{}, len(filters))
+	remaining := filters
+
+	for len(remaining) > 0 {
+		next := -1
+		for i, f := range remaining {
+			if d.resolved(f.ID, present, placed) {
+				next = i
+				break
+			}
+		}
+
+		if next == -1 {
+			break
+		}
+
+		sorted = append(sorted, remaining[next])
+		placed[remaining[next].ID] = struct{}{}
+
+		remaining = append(append([]Filter{}, remaining[:next]...), remaining[next+1:]...)
+	}
+
+	unresolved := make(map[int]struct{}, len(remaining))
+	for _, f := range remaining {
+		unresolved[f.ID] = struct{}{}
+		sorted = append(sorted, f)
+	}
+
+	return sorted, unresolved
+}
+
+// resolved reports whether all dependencies of a filter, that are part of the current evaluation, are placed
+func (d FilterDependencies) resolved(filterID int, present map[int]struct{}, placed map[int]struct{}) bool {
+	for _, dep := range d[filterID] {
+		if _, ok := present[dep]; !ok {
+			continue
+		}
+		if _, ok := placed[dep]; !ok {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,70 @@
+package domain
+
+import (
+	"strings"
+
+	"github.com/autobrr/autobrr/pkg/errors"
+	"github.com/autobrr/autobrr/pkg/expr"
+)
+
+// RunConditionMatches evaluates the run condition of the action against the release, actions without one always run
+func (a *Action) RunConditionMatches(release *Release) (bool, error) {
+	if strings.TrimSpace(a.RunCondition) == "" {
+		return true, nil
+	}
+
+	return expr.Eval(a.RunCondition, release.ConditionEnv())
+}
+
+// ValidateRunCondition checks that the run condition parses and evaluates against an empty release
+func (a *Action) ValidateRunCondition() error {
+	if _, err := a.RunConditionMatches(&Release{}); err != nil {
+		return errors.Wrap(err, "invalid run condition for action %v", a.Name)
+	}
+
+	return nil
+}
+
+// ConditionEnv returns the release fields that can be used in action run conditions, eg. release.Size
+func (r *Release) ConditionEnv() expr.Env {
+	return expr.Env{
+		"release.Indexer":          r.Indexer,
+		"release.TorrentName":      r.TorrentName,
+		"release.Title":            r.Title,
+		"release.Category":         r.Category,
+		"release.Season":           r.Season,
+		"release.Episode":          r.Episode,
+		"release.Year":             r.Year,
+		"release.Resolution":       r.Resolution,
+		"release.Source":           r.Source,
+		"release.Codec":            nonNilStrings(r.Codec),
+		"release.Container":        r.Container,
+		"release.HDR":              nonNilStrings(r.HDR),
+		"release.Audio":            nonNilStrings(r.Audio),
+		"release.Group":            r.Group,
+		"release.Region":           r.Region,
+		"release.Language":         r.Language,
+		"release.Proper":           r.Proper,
+		"release.Repack":           r.Repack,
+		"release.Website":          r.Website,
+		"release.Artists":          r.Artists,
+		"release.Type":             r.Type,
+		"release.LogScore":         r.LogScore,
+		"release.IsScene":          r.IsScene,
+		"release.Origin":           r.Origin,
+		"release.Tags":             nonNilStrings(r.Tags),
+		"release.Freeleech":        r.Freeleech,
+		"release.FreeleechPercent": r.FreeleechPercent,
+		"release.Bonus":            nonNilStrings(r.Bonus),
+		"release.Uploader":         r.Uploader,
+		"release.Other":            nonNilStrings(r.Other),
+		"release.Size":             r.Size,
+	}
+}
+
+func nonNilStrings(s []string) []string {
+	if s == nil {
+		return []string{}
+	}
+	return s
+}
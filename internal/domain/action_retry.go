@@ -0,0 +1,98 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+type ActionRetryRepo interface {
+	Store(ctx context.Context, retry *ActionRetry) error
+	Update(ctx context.Context, retry *ActionRetry) error
+	FindByID(ctx context.Context, id int) (*ActionRetry, error)
+	FindDue(ctx context.Context) ([]*ActionRetry, error)
+	List(ctx context.Context) ([]*ActionRetry, error)
+	Delete(ctx context.Context, id int) error
+}
+
+const (
+	// ActionRetryBaseDelay is the wait before the first retry, it doubles with every attempt
+	ActionRetryBaseDelay = 1 * time.Minute
+	// ActionRetryMaxDelay caps the wait between two attempts
+	ActionRetryMaxDelay = 1 * time.Hour
+)
+
+type ActionRetryStatus string
+
+const (
+	// ActionRetryStatusPending is retried once NextAttemptAt has passed
+	ActionRetryStatusPending ActionRetryStatus = "PENDING"
+	// ActionRetryStatusFailed ran out of its retry window and is only retried manually
+	ActionRetryStatusFailed ActionRetryStatus = "FAILED"
+)
+
+// ActionRetry is a failed action for a release waiting to be run again
+type ActionRetry struct {
+	ID            int               `json:"id"`
+	ReleaseID     int64             `json:"release_id"`
+	FilterID      int               `json:"filter_id"`
+	ActionID      int               `json:"action_id"`
+	TorrentName   string            `json:"torrent_name"`
+	Indexer       string            `json:"indexer"`
+	Filter        string            `json:"filter"`
+	Action        string            `json:"action"`
+	ActionType    ActionType        `json:"action_type"`
+	Status        ActionRetryStatus `json:"status"`
+	Attempts      int               `json:"attempts"`
+	LastError     string            `json:"last_error"`
+	NextAttemptAt time.Time         `json:"next_attempt_at"`
+	ExpiresAt     time.Time         `json:"expires_at"`
+	CreatedAt     time.Time         `json:"created_at"`
+}
+
+// NewActionRetry queues the failed action of the release to be retried within the window
+func NewActionRetry(release *Release, action *Action, actionErr error, window time.Duration, now time.Time) *ActionRetry {
+	r := &ActionRetry{
+		ReleaseID:   release.ID,
+		FilterID:    release.FilterID,
+		ActionID:    action.ID,
+		TorrentName: release.TorrentName,
+		Indexer:     release.Indexer,
+		Filter:      release.FilterName,
+		Action:      action.Name,
+		ActionType:  action.Type,
+		Status:      ActionRetryStatusPending,
+		ExpiresAt:   now.Add(window),
+		CreatedAt:   now,
+	}
+
+	r.Failed(actionErr, now)
+
+	return r
+}
+
+// Failed records a failed attempt and schedules the next one with exponential backoff.
+// It returns false and marks the retry as failed once the next attempt would be outside the window.
+func (r *ActionRetry) Failed(err error, now time.Time) bool {
+	r.Attempts++
+	r.LastError = err.Error()
+
+	delay := ActionRetryBaseDelay
+	for i := 1; i < r.Attempts && delay < ActionRetryMaxDelay; i++ {
+		delay *= 2
+	}
+
+	if delay > ActionRetryMaxDelay {
+		delay = ActionRetryMaxDelay
+	}
+
+	r.NextAttemptAt = now.Add(delay)
+
+	if r.NextAttemptAt.After(r.ExpiresAt) {
+		r.Status = ActionRetryStatusFailed
+		return false
+	}
+
+	r.Status = ActionRetryStatusPending
+
+	return true
+}
@@ -0,0 +1,47 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/autobrr/autobrr/pkg/errors"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestActionRetry_Failed(t *testing.T) {
+	now := time.Date(2022, 10, 1, 12, 0, 0, 0, time.UTC)
+
+	release := &Release{ID: 1, FilterID: 2, TorrentName: "That.Movie.2022.1080p.BluRay.x264-GROUP", Indexer: "mock"}
+	action := &Action{ID: 3, Name: "qbit", Type: ActionTypeQbittorrent}
+
+	retry := NewActionRetry(release, action, errors.New("connection refused"), 10*time.Minute, now)
+
+	assert.Equal(t, ActionRetryStatusPending, retry.Status)
+	assert.Equal(t, 1, retry.Attempts)
+	assert.Equal(t, "connection refused", retry.LastError)
+	assert.Equal(t, now.Add(1*time.Minute), retry.NextAttemptAt)
+
+	assert.True(t, retry.Failed(errors.New("connection refused"), now))
+	assert.Equal(t, now.Add(2*time.Minute), retry.NextAttemptAt)
+
+	assert.True(t, retry.Failed(errors.New("connection refused"), now))
+	assert.Equal(t, now.Add(4*time.Minute), retry.NextAttemptAt)
+
+	assert.True(t, retry.Failed(errors.New("connection refused"), now))
+	assert.Equal(t, now.Add(8*time.Minute), retry.NextAttemptAt)
+
+	// next attempt would be after the window
+	assert.False(t, retry.Failed(errors.New("connection refused"), now))
+	assert.Equal(t, ActionRetryStatusFailed, retry.Status)
+	assert.Equal(t, 5, retry.Attempts)
+}
+
+func TestActionRetry_Failed_maxDelay(t *testing.T) {
+	now := time.Date(2022, 10, 1, 12, 0, 0, 0, time.UTC)
+
+	retry := &ActionRetry{Attempts: 20, ExpiresAt: now.Add(24 * time.Hour)}
+
+	assert.True(t, retry.Failed(errors.New("error"), now))
+	assert.Equal(t, now.Add(ActionRetryMaxDelay), retry.NextAttemptAt)
+}
@@ -0,0 +1,102 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFilterDependencies_FindCycle(t *testing.T) {
+	tests := []struct {
+		name string
+		deps FilterDependencies
+		want []int
+	}{
+		{name: "empty", deps: FilterDependencies{}, want: nil},
+		{name: "chain", deps: FilterDependencies{3: {2}, 2: {1}}, want: nil},
+		{name: "shared_dependency", deps: FilterDependencies{2: {1}, 3: {1, 2}}, want: nil},
+		{name: "self", deps: FilterDependencies{1: {1}}, want: []int{1}},
+		{name: "two_filters", deps: FilterDependencies{1: {2}, 2: {1}}, want: []int{1, 2}},
+		{name: "three_filters", deps: FilterDependencies{1: {2}, 2: {3}, 3: {1}, 4: {1}}, want: []int{1, 2, 3}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.deps.FindCycle())
+		})
+	}
+}
+
+func TestFilterDependencies_SortFilters(t *testing.T) {
+	ids := func(filters []Filter) []int {
+		var ret []int
+		for _, f := range filters {
+			ret = append(ret, f.ID)
+		}
+		return ret
+	}
+
+	tests := []struct {
+		name           string
+		deps           FilterDependencies
+		filters        []Filter
+		wantOrder      []int
+		wantUnresolved map[int]struct{}
+	}{
+		{
+			name:           "no_dependencies_keeps_priority_order",
+			deps:           FilterDependencies{},
+			filters:        []Filter{{ID: 3}, {ID: 1}, {ID: 2}},
+			wantOrder:      []int{3, 1, 2},
+			wantUnresolved: map[int]struct{}{},
+		},
+		{
+			name:           "dependent_moved_after_dependency",
+			deps:           FilterDependencies{1: {2}},
+			filters:        []Filter{{ID: 1}, {ID: 2}, {ID: 3}},
+			wantOrder:      []int{2, 1, 3},
+			wantUnresolved: map[int]struct{}{},
+		},
+		{
+			name:           "dependency_not_evaluated_is_ignored",
+			deps:           FilterDependencies{1: {9}},
+			filters:        []Filter{{ID: 1}, {ID: 2}},
+			wantOrder:      []int{1, 2},
+			wantUnresolved: map[int]struct{}{},
+		},
+		{
+			name:           "cycle_appended_last",
+			deps:           FilterDependencies{1: {2}, 2: {1}, 4: {1}},
+			filters:        []Filter{{ID: 1}, {ID: 2}, {ID: 3}, {ID: 4}},
+			wantOrder:      []int{3, 1, 2, 4},
+			wantUnresolved: map[int]struct{}{1: {}, 2: {}, 4: {}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, unresolved := tt.deps.SortFilters(tt.filters)
+			assert.Equal(t, tt.wantOrder, ids(got))
+			assert.Equal(t, tt.wantUnresolved, unresolved)
+		})
+	}
+}
+
+func TestFilterDependencies_WithFilterActions(t *testing.T) {
+	deps := FilterDependencies{1: {2}, 3: {1}}
+
+	got := deps.WithFilterActions(1, []*Action{
+		{Enabled: true, DependsOnFilterID: 3},
+		{Enabled: false, DependsOnFilterID: 4},
+		{Enabled: true},
+	})
+
+	assert.Equal(t, FilterDependencies{1: {3}, 3: {1}}, got)
+	assert.Equal(t, FilterDependencies{1: {2}, 3: {1}}, deps)
+	assert.Equal(t, []int{1, 3}, got.FindCycle())
+}
+
+func TestFilterDependencies_Targets(t *testing.T) {
+	deps := FilterDependencies{2: {1}, 3: {1, 4}}
+
+	assert.Equal(t, map[int]struct{}{1: {}, 4: {}}, deps.Targets())
+	assert.Empty(t, FilterDependencies{}.Targets())
+}
@@ -0,0 +1,119 @@
+package domain
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/autobrr/autobrr/pkg/errors"
+)
+
+type AnnounceBlocklistRepo interface {
+	List(ctx context.Context) ([]AnnounceBlocklistRule, error)
+	FindByID(ctx context.Context, id int) (*AnnounceBlocklistRule, error)
+	Store(ctx context.Context, rule *AnnounceBlocklistRule) error
+	Update(ctx context.Context, rule *AnnounceBlocklistRule) error
+	Delete(ctx context.Context, id int) error
+	// AddHits adds the counted hits to the rules and sets their last hit
+	AddHits(ctx context.Context, hits []AnnounceBlocklistHits) error
+}
+
+type AnnounceBlocklistType string
+
+const (
+	// AnnounceBlocklistTypeGroup blocks releases of the release groups, a comma separated list with wildcards
+	AnnounceBlocklistTypeGroup AnnounceBlocklistType = "GROUP"
+	// AnnounceBlocklistTypeUploader blocks releases of the uploaders, a comma separated list with wildcards
+	AnnounceBlocklistTypeUploader AnnounceBlocklistType = "UPLOADER"
+	// AnnounceBlocklistTypeCategory blocks releases in the categories, a comma separated list with wildcards
+	AnnounceBlocklistTypeCategory AnnounceBlocklistType = "CATEGORY"
+	// AnnounceBlocklistTypeTitleRegex blocks releases whose name matches the case-insensitive regex
+	AnnounceBlocklistTypeTitleRegex AnnounceBlocklistType = "TITLE_REGEX"
+)
+
+// AnnounceBlocklistRule drops matching announces before any filter is checked,
+// blocked releases are not stored in the release history.
+type AnnounceBlocklistRule struct {
+	ID        int                   `json:"id"`
+	Name      string                `json:"name"`
+	Enabled   bool                  `json:"enabled"`
+	Type      AnnounceBlocklistType `json:"type"`
+	Value     string                `json:"value"`
+	Indexer   string                `json:"indexer,omitempty"` // empty applies to all indexers
+	Hits      int64                 `json:"hits"`
+	LastHitAt *time.Time            `json:"last_hit_at"`
+	CreatedAt time.Time             `json:"created_at"`
+	UpdatedAt time.Time             `json:"updated_at"`
+
+	re *regexp.Regexp
+}
+
+// AnnounceBlocklistHits is the number of announces a rule blocked since the last write
+type AnnounceBlocklistHits struct {
+	RuleID    int
+	Count     int64
+	LastHitAt time.Time
+}
+
+// Validate checks the rule and compiles the regex of title rules
+func (r *AnnounceBlocklistRule) Validate() error {
+	r.Name = strings.TrimSpace(r.Name)
+	if r.Name == "" {
+		return errors.New("validation: name is required")
+	}
+
+	r.Value = strings.TrimSpace(r.Value)
+	if r.Value == "" {
+		return errors.New("validation: value is required")
+	}
+
+	r.Indexer = strings.TrimSpace(r.Indexer)
+
+	switch r.Type {
+	case AnnounceBlocklistTypeGroup, AnnounceBlocklistTypeUploader, AnnounceBlocklistTypeCategory:
+
+	case AnnounceBlocklistTypeTitleRegex:
+		re, err := regexp.Compile(`(?i)(?:` + r.Value + `)`)
+		if err != nil {
+			return errors.Wrap(err, "validation: invalid regex")
+		}
+		r.re = re
+
+	default:
+		return errors.New("validation: unsupported blocklist type: %q", r.Type)
+	}
+
+	return nil
+}
+
+// Matches reports whether the enabled rule blocks the release of the indexer.
+// Title rules only match after Validate compiled their regex.
+func (r *AnnounceBlocklistRule) Matches(release *Release) bool {
+	if !r.Enabled {
+		return false
+	}
+
+	if r.Indexer != "" && r.Indexer != release.Indexer {
+		return false
+	}
+
+	switch r.Type {
+	case AnnounceBlocklistTypeGroup:
+		return contains(release.Group, r.Value)
+	case AnnounceBlocklistTypeUploader:
+		return contains(release.Uploader, r.Value)
+	case AnnounceBlocklistTypeCategory:
+		return contains(release.Category, r.Value)
+	case AnnounceBlocklistTypeTitleRegex:
+		return r.re != nil && r.re.MatchString(release.TorrentName)
+	}
+
+	return false
+}
+
+// String describes the rule in logs and release events
+func (r *AnnounceBlocklistRule) String() string {
+	return fmt.Sprintf("%s (%s: %s)", r.Name, strings.ToLower(string(r.Type)), r.Value)
+}
@@ -0,0 +1,59 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAnnounceBlocklistRule_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		rule    AnnounceBlocklistRule
+		wantErr bool
+	}{
+		{name: "group", rule: AnnounceBlocklistRule{Name: "bad groups", Type: AnnounceBlocklistTypeGroup, Value: "GRP,OTHER*"}},
+		{name: "regex", rule: AnnounceBlocklistRule{Name: "spam", Type: AnnounceBlocklistTypeTitleRegex, Value: `\bcam\b`}},
+		{name: "regex_invalid", rule: AnnounceBlocklistRule{Name: "spam", Type: AnnounceBlocklistTypeTitleRegex, Value: "(cam"}, wantErr: true},
+		{name: "no_name", rule: AnnounceBlocklistRule{Name: " ", Type: AnnounceBlocklistTypeGroup, Value: "GRP"}, wantErr: true},
+		{name: "no_value", rule: AnnounceBlocklistRule{Name: "empty", Type: AnnounceBlocklistTypeUploader, Value: " "}, wantErr: true},
+		{name: "unsupported_type", rule: AnnounceBlocklistRule{Name: "hash", Type: "INFOHASH", Value: "x"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.rule.Validate()
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestAnnounceBlocklistRule_Matches(t *testing.T) {
+	release := &Release{Indexer: "mock", TorrentName: "That.Movie.2022.CAM.x264-GRP", Group: "GRP", Uploader: "Spammer", Category: "Movies :: CAM"}
+
+	tests := []struct {
+		name string
+		rule AnnounceBlocklistRule
+		want bool
+	}{
+		{name: "group", rule: AnnounceBlocklistRule{Type: AnnounceBlocklistTypeGroup, Value: "other,grp"}, want: true},
+		{name: "group_other", rule: AnnounceBlocklistRule{Type: AnnounceBlocklistTypeGroup, Value: "GRP2"}, want: false},
+		{name: "uploader_wildcard", rule: AnnounceBlocklistRule{Type: AnnounceBlocklistTypeUploader, Value: "spam*"}, want: true},
+		{name: "category", rule: AnnounceBlocklistRule{Type: AnnounceBlocklistTypeCategory, Value: "*cam"}, want: true},
+		{name: "regex", rule: AnnounceBlocklistRule{Type: AnnounceBlocklistTypeTitleRegex, Value: `\.cam\.`}, want: true},
+		{name: "regex_other", rule: AnnounceBlocklistRule{Type: AnnounceBlocklistTypeTitleRegex, Value: `\.telesync\.`}, want: false},
+		{name: "indexer", rule: AnnounceBlocklistRule{Type: AnnounceBlocklistTypeGroup, Value: "GRP", Indexer: "mock"}, want: true},
+		{name: "indexer_other", rule: AnnounceBlocklistRule{Type: AnnounceBlocklistTypeGroup, Value: "GRP", Indexer: "other"}, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.rule.Name = tt.name
+			tt.rule.Enabled = true
+			assert.NoError(t, tt.rule.Validate())
+			assert.Equal(t, tt.want, tt.rule.Matches(release))
+		})
+	}
+}
@@ -2,7 +2,13 @@ package domain
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strings"
 	"time"
+
+	"github.com/autobrr/autobrr/pkg/errors"
 )
 
 type APIRepo interface {
@@ -12,8 +18,116 @@ type APIRepo interface {
 }
 
 type APIKey struct {
-	Name      string    `json:"name"`
-	Key       string    `json:"key"`
-	Scopes    []string  `json:"scopes"`
-	CreatedAt time.Time `json:"created_at"`
+	ID        string     `json:"id"`
+	Name      string     `json:"name"`
+	Key       string     `json:"key,omitempty"`
+	KeyHint   string     `json:"key_hint,omitempty"`
+	Scopes    []string   `json:"scopes"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+// API key scopes. A key without scopes has full access, like keys created before scopes existed.
+const (
+	APIKeyScopeAdmin    = "admin"
+	APIKeyScopeReadOnly = "read"
+	APIKeyScopeReleases = "releases"
+	APIKeyScopeFilters  = "filters"
+)
+
+// apiKeyScopeRoutes are the api routes a scope gives full access to
+var apiKeyScopeRoutes = map[string][]string{
+	APIKeyScopeReleases: {"/api/release"},
+	APIKeyScopeFilters:  {"/api/filters", "/api/actions"},
+}
+
+// apiKeyAdminRoutes return or change secrets, like other keys, credentials and database backups, and are limited to the admin scope.
+// The event streams are included, the log stream carries torrent urls with passkeys and the arguments of exec actions.
+var apiKeyAdminRoutes = []string{
+	"/api/audit",
+	"/api/backup",
+	"/api/config",
+	"/api/download_clients",
+	"/api/events",
+	"/api/feeds",
+	"/api/indexer",
+	"/api/irc",
+	"/api/keys",
+	"/api/lists",
+	"/api/notification",
+}
+
+// APIKeyID is the identifier of a key that can be shown and used to delete it without exposing the key itself
+func APIKeyID(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:8])
+}
+
+// Redacted returns the key without its value, the value is only returned when the key is created
+func (k APIKey) Redacted() APIKey {
+	k.ID = APIKeyID(k.Key)
+	if len(k.Key) > 4 {
+		k.KeyHint = k.Key[:4] + "…"
+	}
+	k.Key = ""
+
+	return k
+}
+
+func (k APIKey) Validate() error {
+	for _, scope := range k.Scopes {
+		switch scope {
+		case APIKeyScopeAdmin, APIKeyScopeReadOnly, APIKeyScopeReleases, APIKeyScopeFilters:
+		default:
+			return errors.New("invalid scope: %v", scope)
+		}
+	}
+
+	return nil
+}
+
+func (k APIKey) Expired(now time.Time) bool {
+	return k.ExpiresAt != nil && !now.Before(*k.ExpiresAt)
+}
+
+// Allows reports whether the key may call the api route. The path can carry the base url in front of /api/.
+func (k APIKey) Allows(method string, path string) bool {
+	if len(k.Scopes) == 0 {
+		return true
+	}
+
+	if idx := strings.Index(path, "/api/"); idx > 0 {
+		path = path[idx:]
+	}
+
+	adminOnly := matchesAPIRoute(path, apiKeyAdminRoutes)
+
+	for _, scope := range k.Scopes {
+		switch scope {
+		case APIKeyScopeAdmin:
+			return true
+
+		case APIKeyScopeReadOnly:
+			if !adminOnly && (method == http.MethodGet || method == http.MethodHead) {
+				return true
+			}
+
+		default:
+			if !adminOnly && matchesAPIRoute(path, apiKeyScopeRoutes[scope]) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func matchesAPIRoute(path string, routes []string) bool {
+	for _, route := range routes {
+		if path == route || strings.HasPrefix(path, route+"/") {
+			return true
+		}
+	}
+
+	return false
 }
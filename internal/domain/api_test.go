@@ -0,0 +1,73 @@
+package domain
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIKey_Allows(t *testing.T) {
+	tests := []struct {
+		name   string
+		scopes []string
+		method string
+		path   string
+		want   bool
+	}{
+		{name: "no_scopes", scopes: nil, method: http.MethodDelete, path: "/api/filters/1", want: true},
+		{name: "admin", scopes: []string{APIKeyScopeAdmin}, method: http.MethodPost, path: "/api/keys", want: true},
+		{name: "read_get", scopes: []string{APIKeyScopeReadOnly}, method: http.MethodGet, path: "/api/filters", want: true},
+		{name: "read_keys", scopes: []string{APIKeyScopeReadOnly}, method: http.MethodGet, path: "/api/keys", want: false},
+		{name: "read_backup", scopes: []string{APIKeyScopeReadOnly}, method: http.MethodGet, path: "/autobrr/api/backup/autobrr.db", want: false},
+		{name: "read_download_clients", scopes: []string{APIKeyScopeReadOnly}, method: http.MethodGet, path: "/api/download_clients", want: false},
+		{name: "read_events", scopes: []string{APIKeyScopeReadOnly}, method: http.MethodGet, path: "/api/events", want: false},
+		{name: "releases_events", scopes: []string{APIKeyScopeReleases}, method: http.MethodGet, path: "/api/events", want: false},
+		{name: "filters_events", scopes: []string{APIKeyScopeFilters}, method: http.MethodGet, path: "/autobrr/api/events", want: false},
+		{name: "admin_events", scopes: []string{APIKeyScopeAdmin}, method: http.MethodGet, path: "/api/events", want: true},
+		{name: "admin_backup", scopes: []string{APIKeyScopeAdmin}, method: http.MethodGet, path: "/api/backup/autobrr.db", want: true},
+		{name: "read_post", scopes: []string{APIKeyScopeReadOnly}, method: http.MethodPost, path: "/api/filters", want: false},
+		{name: "releases", scopes: []string{APIKeyScopeReleases}, method: http.MethodPost, path: "/api/release/replay", want: true},
+		{name: "releases_root", scopes: []string{APIKeyScopeReleases}, method: http.MethodGet, path: "/api/release", want: true},
+		{name: "releases_other_route", scopes: []string{APIKeyScopeReleases}, method: http.MethodGet, path: "/api/filters", want: false},
+		{name: "releases_prefix", scopes: []string{APIKeyScopeReleases}, method: http.MethodGet, path: "/api/releases-other", want: false},
+		{name: "filters", scopes: []string{APIKeyScopeFilters}, method: http.MethodPut, path: "/api/filters/1", want: true},
+		{name: "filters_actions", scopes: []string{APIKeyScopeFilters}, method: http.MethodPatch, path: "/api/actions/1/toggleEnabled", want: true},
+		{name: "filters_keys", scopes: []string{APIKeyScopeFilters}, method: http.MethodPost, path: "/api/keys", want: false},
+		{name: "base_url", scopes: []string{APIKeyScopeFilters}, method: http.MethodPost, path: "/autobrr/api/filters", want: true},
+		{name: "combined", scopes: []string{APIKeyScopeReadOnly, APIKeyScopeReleases}, method: http.MethodDelete, path: "/api/release/retries/1", want: true},
+		{name: "combined_denied", scopes: []string{APIKeyScopeReadOnly, APIKeyScopeReleases}, method: http.MethodDelete, path: "/api/filters/1", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			k := APIKey{Scopes: tt.scopes}
+			assert.Equal(t, tt.want, k.Allows(tt.method, tt.path))
+		})
+	}
+}
+
+func TestAPIKey_Expired(t *testing.T) {
+	now := time.Now()
+	past := now.Add(-time.Minute)
+	future := now.Add(time.Minute)
+
+	assert.False(t, APIKey{}.Expired(now))
+	assert.True(t, APIKey{ExpiresAt: &past}.Expired(now))
+	assert.True(t, APIKey{ExpiresAt: &now}.Expired(now))
+	assert.False(t, APIKey{ExpiresAt: &future}.Expired(now))
+}
+
+func TestAPIKey_Redacted(t *testing.T) {
+	k := APIKey{Name: "test", Key: "0123456789abcdef"}.Redacted()
+
+	assert.Empty(t, k.Key)
+	assert.Equal(t, "0123…", k.KeyHint)
+	assert.Equal(t, APIKeyID("0123456789abcdef"), k.ID)
+	assert.Len(t, k.ID, 16)
+}
+
+func TestAPIKey_Validate(t *testing.T) {
+	assert.NoError(t, APIKey{Scopes: []string{APIKeyScopeReadOnly, APIKeyScopeFilters}}.Validate())
+	assert.EqualError(t, APIKey{Scopes: []string{"write"}}.Validate(), "invalid scope: write")
+}
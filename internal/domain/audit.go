@@ -0,0 +1,299 @@
+package domain
+
+import (
+	"context"
+	"encoding/json"
+	"net/url"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type AuditRepo interface {
+	Store(ctx context.Context, entry *AuditEntry) error
+	Find(ctx context.Context, params AuditQueryParams) ([]AuditEntry, error)
+}
+
+type AuditAction string
+
+const (
+	AuditActionCreate AuditAction = "CREATE"
+	AuditActionUpdate AuditAction = "UPDATE"
+	AuditActionDelete AuditAction = "DELETE"
+)
+
+type AuditEntityType string
+
+const (
+	AuditEntityFilter         AuditEntityType = "FILTER"
+	AuditEntityIndexer        AuditEntityType = "INDEXER"
+	AuditEntityIrcNetwork     AuditEntityType = "IRC_NETWORK"
+	AuditEntityDownloadClient AuditEntityType = "DOWNLOAD_CLIENT"
+	AuditEntityNotification   AuditEntityType = "NOTIFICATION"
+	AuditEntityAPIKey         AuditEntityType = "API_KEY"
+	AuditEntityList           AuditEntityType = "LIST"
+	AuditEntityFeed           AuditEntityType = "FEED"
+	AuditEntityBlocklistRule  AuditEntityType = "ANNOUNCE_BLOCKLIST"
+	AuditEntityQuota          AuditEntityType = "QUOTA"
+)
+
+// AuditActorSystem is the actor of changes not made through the api, eg. on startup
+const AuditActorSystem = "system"
+
+// AuditRedacted replaces the values of secret fields
+const AuditRedacted = "[redacted]"
+
+// AuditEntry is a configuration change, who made it and which fields changed
+type AuditEntry struct {
+	ID         int64           `json:"id"`
+	Actor      string          `json:"actor"`
+	Action     AuditAction     `json:"action"`
+	EntityType AuditEntityType `json:"entity_type"`
+	EntityID   string          `json:"entity_id"`
+	EntityName string          `json:"entity_name"`
+	Changes    []AuditChange   `json:"changes"`
+	CreatedAt  time.Time       `json:"created_at"`
+}
+
+// AuditChange is a changed field, nested fields are joined with dots like "settings.apikey" or "actions.0.save_path"
+type AuditChange struct {
+	Field string      `json:"field"`
+	Old   interface{} `json:"old,omitempty"`
+	New   interface{} `json:"new,omitempty"`
+}
+
+type AuditQueryParams struct {
+	EntityType AuditEntityType
+	EntityID   string
+	Actor      string
+	Limit      uint64
+	Offset     uint64
+}
+
+type auditActorKey struct{}
+
+// WithAuditActor returns a context that attributes changes to the actor
+func WithAuditActor(ctx context.Context, actor string) context.Context {
+	return context.WithValue(ctx, auditActorKey{}, actor)
+}
+
+// AuditActor returns the actor changes in ctx are attributed to
+func AuditActor(ctx context.Context) string {
+	if actor, ok := ctx.Value(auditActorKey{}).(string); ok && actor != "" {
+		return actor
+	}
+
+	return AuditActorSystem
+}
+
+// auditIgnoredFields change without anyone changing the configuration
+var auditIgnoredFields = map[string]struct{}{
+	"created_at":      {},
+	"updated_at":      {},
+	"connected":       {},
+	"connected_since": {},
+	"health":          {},
+	"hits":            {},
+	"last_hit_at":     {},
+	"last_success_at": {},
+	"last_error":      {},
+	"last_error_at":   {},
+}
+
+// auditSecretFields are redacted if the field name contains any of them
+var auditSecretFields = []string{"pass", "secret", "token", "cookie", "apikey", "api_key", "authkey", "auth_key", "userkey", "user_key", "rsskey", "invite_command", "header"}
+
+// isAuditSecret reports whether the value of the field must not end up in the audit log
+func isAuditSecret(field string) bool {
+	name := strings.ToLower(field)
+	if name == "key" || name == "uid" || name == "webhook" {
+		return true
+	}
+
+	for _, secret := range auditSecretFields {
+		if strings.Contains(name, secret) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// AuditDiff returns the changed fields between two versions of an entity, secrets are redacted.
+// Pass nil as before for created and nil as after for deleted entities, then all set fields are returned.
+func AuditDiff(before, after interface{}) ([]AuditChange, error) {
+	oldFields, err := flattenAuditValue(before)
+	if err != nil {
+		return nil, err
+	}
+
+	newFields, err := flattenAuditValue(after)
+	if err != nil {
+		return nil, err
+	}
+
+	fields := make([]string, 0, len(oldFields)+len(newFields))
+	for field := range oldFields {
+		fields = append(fields, field)
+	}
+	for field := range newFields {
+		if _, ok := oldFields[field]; !ok {
+			fields = append(fields, field)
+		}
+	}
+
+	sort.Strings(fields)
+
+	changes := make([]AuditChange, 0)
+	for _, field := range fields {
+		oldValue, newValue := oldFields[field], newFields[field]
+		if reflect.DeepEqual(oldValue, newValue) {
+			continue
+		}
+
+		change := AuditChange{Field: field, Old: oldValue, New: newValue}
+
+		if isAuditSecret(lastAuditSegment(field)) {
+			if oldValue != nil {
+				change.Old = AuditRedacted
+			}
+			if newValue != nil {
+				change.New = AuditRedacted
+			}
+		} else {
+			change.Old = redactAuditURL(change.Old)
+			change.New = redactAuditURL(change.New)
+		}
+
+		changes = append(changes, change)
+	}
+
+	return changes, nil
+}
+
+// auditRedactedURLValue stands in for AuditRedacted while the url is encoded
+const auditRedactedURLValue = "__redacted__"
+
+// redactAuditURL hides the password and query values of a url, eg. webhook urls often carry an api key in the query
+func redactAuditURL(v interface{}) interface{} {
+	value, ok := v.(string)
+	if !ok {
+		return v
+	}
+
+	u, err := url.Parse(value)
+	if err != nil || u.Scheme == "" || u.Host == "" {
+		return v
+	}
+
+	redacted := false
+
+	if u.User != nil {
+		if _, ok := u.User.Password(); ok {
+			u.User = url.UserPassword(u.User.Username(), auditRedactedURLValue)
+			redacted = true
+		}
+	}
+
+	if u.RawQuery != "" {
+		query := u.Query()
+		keys := make([]string, 0, len(query))
+		for key := range query {
+			keys = append(keys, url.QueryEscape(key)+"="+auditRedactedURLValue)
+		}
+		sort.Strings(keys)
+
+		u.RawQuery = strings.Join(keys, "&")
+		redacted = true
+	}
+
+	if !redacted {
+		return v
+	}
+
+	// brackets would be escaped in the url, put them in after
+	return strings.ReplaceAll(u.String(), auditRedactedURLValue, AuditRedacted)
+}
+
+// flattenAuditValue encodes v as json and returns its set leaf values by dotted path
+func flattenAuditValue(v interface{}) (map[string]interface{}, error) {
+	fields := map[string]interface{}{}
+
+	if v == nil || (reflect.ValueOf(v).Kind() == reflect.Ptr && reflect.ValueOf(v).IsNil()) {
+		return fields, nil
+	}
+
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	var decoded interface{}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return nil, err
+	}
+
+	flattenAuditInto(fields, "", decoded)
+
+	return fields, nil
+}
+
+func flattenAuditInto(fields map[string]interface{}, prefix string, v interface{}) {
+	join := func(key string) string {
+		if prefix == "" {
+			return key
+		}
+		return prefix + "." + key
+	}
+
+	switch value := v.(type) {
+	case map[string]interface{}:
+		for key, nested := range value {
+			if _, ok := auditIgnoredFields[key]; ok {
+				continue
+			}
+			flattenAuditInto(fields, join(key), nested)
+		}
+
+	case []interface{}:
+		for i, nested := range value {
+			flattenAuditInto(fields, join(strconv.Itoa(i)), nested)
+		}
+
+	default:
+		// unset values are left out so they don't show as changed from null to empty
+		if isZeroAuditValue(value) {
+			return
+		}
+		fields[prefix] = value
+	}
+}
+
+func isZeroAuditValue(v interface{}) bool {
+	switch value := v.(type) {
+	case nil:
+		return true
+	case string:
+		return value == ""
+	case bool:
+		return !value
+	case float64:
+		return value == 0
+	}
+
+	return false
+}
+
+// lastAuditSegment returns the last field name of a path, skipping list indexes
+func lastAuditSegment(field string) string {
+	parts := strings.Split(field, ".")
+	for i := len(parts) - 1; i >= 0; i-- {
+		if _, err := strconv.Atoi(parts[i]); err != nil {
+			return parts[i]
+		}
+	}
+
+	return field
+}
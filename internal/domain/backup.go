@@ -0,0 +1,15 @@
+package domain
+
+import "time"
+
+type DatabaseBackup struct {
+	Name      string    `json:"name"`
+	Size      int64     `json:"size"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type DatabaseBackupSettings struct {
+	Dir      string `json:"dir"`
+	Interval int    `json:"interval"`
+	Retain   int    `json:"retain"`
+}
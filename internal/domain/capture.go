@@ -0,0 +1,120 @@
+package domain
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/autobrr/autobrr/pkg/errors"
+)
+
+type capturePattern struct {
+	tags bool
+	re   *regexp.Regexp
+}
+
+// compiledCapturePatterns caches the compiled form of the capture patterns of a filter
+type compiledCapturePatterns struct {
+	source   string
+	patterns []capturePattern
+}
+
+// compileCapturePatterns compiles the patterns, one per line.
+// Lines prefixed with "tags:" match against the release tags, otherwise the torrent name is used.
+func compileCapturePatterns(patterns string) ([]capturePattern, error) {
+	var compiled []capturePattern
+
+	for i, line := range strings.Split(patterns, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+
+		p := capturePattern{}
+		if strings.HasPrefix(line, "tags:") {
+			p.tags = true
+			line = strings.TrimPrefix(line, "tags:")
+		} else {
+			line = strings.TrimPrefix(line, "name:")
+		}
+
+		re, err := regexp.Compile(`(?i)` + strings.TrimSpace(line))
+		if err != nil {
+			return nil, errors.Wrap(err, "invalid capture pattern on line %d", i+1)
+		}
+		p.re = re
+
+		compiled = append(compiled, p)
+	}
+
+	return compiled, nil
+}
+
+// ValidateCapturePatterns reports whether every capture pattern compiles
+func ValidateCapturePatterns(patterns string) error {
+	if _, err := compileCapturePatterns(patterns); err != nil {
+		return errors.Wrap(err, "validation")
+	}
+
+	return nil
+}
+
+// CompileCapturePatterns validates the capture patterns of the filter and caches the compiled form for CheckFilter
+func (f *Filter) CompileCapturePatterns() error {
+	patterns, err := compileCapturePatterns(f.CapturePatterns)
+	if err != nil {
+		return errors.Wrap(err, "validation")
+	}
+
+	f.capture = &compiledCapturePatterns{source: f.CapturePatterns, patterns: patterns}
+
+	return nil
+}
+
+// capturePatterns matches every capture pattern of the filter against the release and collects the capture groups.
+// Named groups are stored by name and every group is also stored as group1, group2 and so on,
+// which makes them available as {{ .Match.name }} in action macros.
+func (f Filter) capturePatterns(r *Release) (map[string]string, bool) {
+	var patterns []capturePattern
+	if f.capture != nil && f.capture.source == f.CapturePatterns {
+		patterns = f.capture.patterns
+	} else {
+		// patterns that were not compiled by CompileCapturePatterns, e.g. filters stored before validation existed
+		compiled, err := compileCapturePatterns(f.CapturePatterns)
+		if err != nil {
+			return nil, false
+		}
+		patterns = compiled
+	}
+
+	match := map[string]string{}
+
+	group := 0
+
+	for _, p := range patterns {
+		value := r.TorrentName
+		if p.tags {
+			value = strings.Join(r.Tags, ", ")
+		}
+
+		submatches := p.re.FindStringSubmatch(value)
+		if submatches == nil {
+			return nil, false
+		}
+
+		for i, name := range p.re.SubexpNames() {
+			if i == 0 {
+				continue
+			}
+
+			group++
+			match[fmt.Sprintf("group%d", group)] = submatches[i]
+
+			if name != "" {
+				match[name] = submatches[i]
+			}
+		}
+	}
+
+	return match, true
+}
@@ -0,0 +1,103 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_capturePatterns(t *testing.T) {
+	tests := []struct {
+		name     string
+		release  Release
+		patterns string
+		want     map[string]string
+		wantOk   bool
+	}{
+		{
+			name:     "named_groups",
+			release:  Release{TorrentName: "That.Show.S01E02.1080p.WEB-DL-GROUP"},
+			patterns: `^(?P<show>.+?)\.S(?P<season>\d+)E\d+`,
+			want:     map[string]string{"group1": "That.Show", "group2": "01", "show": "That.Show", "season": "01"},
+			wantOk:   true,
+		},
+		{
+			name:     "name_and_tags",
+			release:  Release{TorrentName: "Artist - Album [2022] [FLAC]", Tags: []string{"rock", "alternative"}},
+			patterns: "name:^(.+?) - \ntags:(rock|pop)",
+			want:     map[string]string{"group1": "Artist", "group2": "rock"},
+			wantOk:   true,
+		},
+		{
+			name:     "case_insensitive",
+			release:  Release{TorrentName: "that.show.s01.1080p"},
+			patterns: `S(\d+)`,
+			want:     map[string]string{"group1": "01"},
+			wantOk:   true,
+		},
+		{
+			name:     "no_match",
+			release:  Release{TorrentName: "That.Movie.2022.1080p"},
+			patterns: `S(\d+)E(\d+)`,
+			wantOk:   false,
+		},
+		{
+			name:     "one_of_many_not_matching",
+			release:  Release{TorrentName: "That.Show.S01.1080p", Tags: []string{"drama"}},
+			patterns: "S(\\d+)\ntags:(comedy)",
+			wantOk:   false,
+		},
+		{
+			name:     "invalid_regex",
+			release:  Release{TorrentName: "That.Show.S01.1080p"},
+			patterns: `S(\d+`,
+			wantOk:   false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := Filter{CapturePatterns: tt.patterns}
+			got, ok := f.capturePatterns(&tt.release)
+			assert.Equal(t, tt.wantOk, ok)
+			if tt.wantOk {
+				assert.Equal(t, tt.want, got)
+			}
+		})
+	}
+}
+
+func TestFilter_CompileCapturePatterns(t *testing.T) {
+	f := Filter{CapturePatterns: "S(\\d+)\ntags:(drama"}
+	assert.EqualError(t, f.CompileCapturePatterns(), "validation: invalid capture pattern on line 2: error parsing regexp: missing closing ): `(?i)(drama`")
+	assert.Nil(t, f.capture)
+
+	f.CapturePatterns = "S(\\d+)\ntags:(drama)"
+	assert.NoError(t, f.CompileCapturePatterns())
+	assert.Len(t, f.capture.patterns, 2)
+
+	// the compiled patterns are shared by copies of the filter
+	c := f
+	match, ok := c.capturePatterns(&Release{TorrentName: "That.Show.S02.1080p", Tags: []string{"drama"}})
+	assert.True(t, ok)
+	assert.Equal(t, map[string]string{"group1": "02", "group2": "drama"}, match)
+
+	// patterns changed after compiling are compiled again
+	c.CapturePatterns = "E(\\d+)"
+	_, ok = c.capturePatterns(&Release{TorrentName: "That.Show.S02.1080p"})
+	assert.False(t, ok)
+}
+
+func TestFilter_CheckFilter_CapturePatterns(t *testing.T) {
+	f := Filter{CapturePatterns: `^(?P<show>.+?)\.S\d+`}
+
+	r := &Release{TorrentName: "That.Show.S01.1080p.WEB-DL-GROUP"}
+	_, match := f.CheckFilter(r)
+	assert.True(t, match)
+	assert.Equal(t, "That.Show", r.Match["show"])
+
+	r = &Release{TorrentName: "That.Movie.2022.1080p.BluRay-GROUP"}
+	rejections, match := f.CheckFilter(r)
+	assert.False(t, match)
+	assert.Len(t, rejections, 1)
+	assert.Nil(t, r.Match)
+}
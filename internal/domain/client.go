@@ -1,6 +1,10 @@
 package domain
 
-import "context"
+import (
+	"context"
+	"strings"
+	"time"
+)
 
 type DownloadClientRepo interface {
 	List(ctx context.Context) ([]DownloadClient, error)
@@ -8,6 +12,7 @@ type DownloadClientRepo interface {
 	Store(ctx context.Context, client DownloadClient) (*DownloadClient, error)
 	Update(ctx context.Context, client DownloadClient) (*DownloadClient, error)
 	Delete(ctx context.Context, clientID int) error
+	UpdateHealth(ctx context.Context, clientID int, health DownloadClientHealth) error
 }
 
 type DownloadClient struct {
@@ -22,12 +27,35 @@ type DownloadClient struct {
 	Username      string                 `json:"username"`
 	Password      string                 `json:"password"`
 	Settings      DownloadClientSettings `json:"settings,omitempty"`
+	Health        DownloadClientHealth   `json:"health"`
+}
+
+type DownloadClientHealthStatus string
+
+const (
+	DownloadClientHealthUnknown DownloadClientHealthStatus = "UNKNOWN"
+	DownloadClientHealthOK      DownloadClientHealthStatus = "OK"
+	DownloadClientHealthDown    DownloadClientHealthStatus = "DOWN"
+)
+
+// DownloadClientHealth is the result of the last periodic connection check of a client
+type DownloadClientHealth struct {
+	Status    DownloadClientHealthStatus `json:"status"`
+	Error     string                     `json:"error,omitempty"`
+	CheckedAt *time.Time                 `json:"checked_at,omitempty"`
+}
+
+func (h DownloadClientHealth) Down() bool {
+	return h.Status == DownloadClientHealthDown
 }
 
 type DownloadClientSettings struct {
 	APIKey string              `json:"apikey,omitempty"`
 	Basic  BasicAuth           `json:"basic,omitempty"`
 	Rules  DownloadClientRules `json:"rules,omitempty"`
+
+	// WatchFolder is the directory the client watches, watch folder actions for the client save torrent files there
+	WatchFolder string `json:"watch_folder,omitempty"`
 }
 
 type DownloadClientRules struct {
@@ -35,6 +63,31 @@ type DownloadClientRules struct {
 	MaxActiveDownloads     int   `json:"max_active_downloads"`
 	IgnoreSlowTorrents     bool  `json:"ignore_slow_torrents"`
 	DownloadSpeedThreshold int64 `json:"download_speed_threshold"`
+
+	Trackers []DownloadClientTrackerRule `json:"trackers,omitempty"`
+}
+
+// DownloadClientTrackerRule sets defaults for torrents added from an indexer.
+// Limits set on the action take precedence, tags are added to the action tags.
+type DownloadClientTrackerRule struct {
+	Indexer            string  `json:"indexer"`
+	Tags               string  `json:"tags,omitempty"`
+	Paused             bool    `json:"paused,omitempty"`
+	LimitUploadSpeed   int64   `json:"limit_upload_speed,omitempty"`
+	LimitDownloadSpeed int64   `json:"limit_download_speed,omitempty"`
+	LimitRatio         float64 `json:"limit_ratio,omitempty"`
+	LimitSeedTime      int64   `json:"limit_seed_time,omitempty"`
+}
+
+// TrackerRule returns the rule for the indexer or nil if there is none
+func (r DownloadClientRules) TrackerRule(indexer string) *DownloadClientTrackerRule {
+	for i := range r.Trackers {
+		if strings.EqualFold(r.Trackers[i].Indexer, indexer) {
+			return &r.Trackers[i]
+		}
+	}
+
+	return nil
 }
 
 type BasicAuth struct {
@@ -51,6 +104,7 @@ const (
 	DownloadClientTypeDelugeV2     DownloadClientType = "DELUGE_V2"
 	DownloadClientTypeRTorrent     DownloadClientType = "RTORRENT"
 	DownloadClientTypeTransmission DownloadClientType = "TRANSMISSION"
+	DownloadClientTypePorla        DownloadClientType = "PORLA"
 	DownloadClientTypeRadarr       DownloadClientType = "RADARR"
 	DownloadClientTypeSonarr       DownloadClientType = "SONARR"
 	DownloadClientTypeLidarr       DownloadClientType = "LIDARR"
@@ -16,4 +16,52 @@ type Config struct {
 	PostgresDatabase  string `toml:"postgresDatabase"`
 	PostgresUser      string `toml:"postgresUser"`
 	PostgresPass      string `toml:"postgresPass"`
+
+	FeedBackoff           bool  `toml:"feedBackoff"`
+	FeedBackoffThreshold  int64 `toml:"feedBackoffThreshold"`
+	FeedBackoffMultiplier int   `toml:"feedBackoffMultiplier"`
+
+	IRCReadTimeout  int `toml:"ircReadTimeout"`
+	IRCPingTimeout  int `toml:"ircPingTimeout"`
+	IRCWriteTimeout int `toml:"ircWriteTimeout"`
+
+	IRCAnnounceSilenceThreshold int `toml:"ircAnnounceSilenceThreshold"`
+
+	InstanceCoordinate   bool   `toml:"instanceCoordinate"`
+	InstanceName         string `toml:"instanceName"`
+	InstanceReadOnly     bool   `toml:"instanceReadOnly"`
+	InstanceLeaseTimeout int    `toml:"instanceLeaseTimeout"`
+
+	ActionRetryWindow int `toml:"actionRetryWindow"`
+
+	ReleaseRetentionDays         int  `toml:"releaseRetentionDays"`
+	ReleaseRetentionMaxRows      int  `toml:"releaseRetentionMaxRows"`
+	ReleaseRetentionKeepApproved bool `toml:"releaseRetentionKeepApproved"`
+
+	ReleaseDedupKey    string `toml:"releaseDedupKey"`
+	ReleaseDedupWindow int    `toml:"releaseDedupWindow"`
+
+	ReleaseIndexerWorkers   int `toml:"releaseIndexerWorkers"`
+	ReleaseIndexerQueueSize int `toml:"releaseIndexerQueueSize"`
+
+	ReleaseApprovalWindow int    `toml:"releaseApprovalWindow"`
+	PublicURL             string `toml:"publicUrl"`
+
+	Proxy string `toml:"proxy"`
+
+	BackupDir      string `toml:"backupDir"`
+	BackupInterval int    `toml:"backupInterval"`
+	BackupRetain   int    `toml:"backupRetain"`
+
+	Timezone string `toml:"timezone"`
+
+	OIDCEnabled       bool   `toml:"oidcEnabled"`
+	OIDCIssuer        string `toml:"oidcIssuer"`
+	OIDCClientID      string `toml:"oidcClientId"`
+	OIDCClientSecret  string `toml:"oidcClientSecret"`
+	OIDCRedirectURL   string `toml:"oidcRedirectUrl"`
+	OIDCUsernameClaim string `toml:"oidcUsernameClaim"`
+
+	// Errors lists the invalid settings of the last load, they were replaced by their defaults except the database type
+	Errors []string
 }
@@ -2,7 +2,12 @@ package domain
 
 import (
 	"context"
+	"net/http"
+	"net/textproto"
+	"strings"
 	"time"
+
+	"github.com/autobrr/autobrr/pkg/errors"
 )
 
 type FeedCacheRepo interface {
@@ -21,6 +26,7 @@ type FeedRepo interface {
 	Update(ctx context.Context, feed *Feed) error
 	ToggleEnabled(ctx context.Context, id int, enabled bool) error
 	Delete(ctx context.Context, id int) error
+	UpdateLastRun(ctx context.Context, id int, lastError string) error
 }
 
 type Feed struct {
@@ -31,13 +37,44 @@ type Feed struct {
 	Enabled      bool              `json:"enabled"`
 	URL          string            `json:"url"`
 	Interval     int               `json:"interval"`
+	Backfill     int               `json:"backfill"`
 	Capabilities []string          `json:"capabilities"`
 	ApiKey       string            `json:"api_key"`
 	Settings     map[string]string `json:"settings"`
+	Cookie       string            `json:"cookie"`
+	Headers      string            `json:"headers"` // one "Name: value" per line
+	Username     string            `json:"username"`
+	Password     string            `json:"password"`
+	LastSuccess  *time.Time        `json:"last_success_at"`
+	LastError    string            `json:"last_error"`
+	LastErrorAt  *time.Time        `json:"last_error_at"`
 	CreatedAt    time.Time         `json:"created_at"`
 	UpdatedAt    time.Time         `json:"updated_at"`
 	IndexerID    int               `json:"indexer_id,omitempty"`
 	Indexerr     FeedIndexer       `json:"-"`
+	Proxy        string            `json:"-"` // proxy of the indexer
+}
+
+// HTTPHeaders parses the custom headers sent with each feed request
+func (f Feed) HTTPHeaders() (http.Header, error) {
+	headers := http.Header{}
+
+	for _, line := range strings.Split(f.Headers, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+
+		name, value, ok := strings.Cut(line, ":")
+		name = strings.TrimSpace(name)
+		if !ok || name == "" || strings.ContainsAny(name, " \t") {
+			return nil, errors.New("invalid header %q, expected \"Name: value\"", line)
+		}
+
+		headers.Add(textproto.CanonicalMIMEHeaderKey(name), strings.TrimSpace(value))
+	}
+
+	return headers, nil
 }
 
 type FeedIndexer struct {
@@ -51,4 +88,75 @@ type FeedType string
 const (
 	FeedTypeTorznab FeedType = "TORZNAB"
 	FeedTypeRSS     FeedType = "RSS"
+	FeedTypeAPI     FeedType = "API"
 )
+
+// FeedAPIMapping holds the json paths used to read releases from an api feed, they are stored in the feed settings.
+// Paths are keys separated by dots, array elements are selected by index, eg. "data.torrents" or "files.0.name".
+// The download url can also be a template over the item instead of a path, eg. "https://tracker/download.php?id={{ .id }}".
+type FeedAPIMapping struct {
+	Items       string // path to the list of items, empty when the response is the list
+	GUID        string // unique id of an item, the download url is used when empty
+	Title       string
+	DownloadURL string
+	Size        string
+	Category    string
+}
+
+func (f Feed) APIMapping() (FeedAPIMapping, error) {
+	m := FeedAPIMapping{
+		Items:       f.Settings["items"],
+		GUID:        f.Settings["guid"],
+		Title:       f.Settings["title"],
+		DownloadURL: f.Settings["download_url"],
+		Size:        f.Settings["size"],
+		Category:    f.Settings["category"],
+	}
+
+	if m.Title == "" {
+		return m, errors.New("api feed requires a title path")
+	}
+
+	if m.DownloadURL == "" {
+		return m, errors.New("api feed requires a download url path")
+	}
+
+	return m, nil
+}
+
+// FeedDryRunItem is a single feed item checked against the filters of the feed indexer.
+// Error is set when the item could not be turned into a release.
+type FeedDryRunItem struct {
+	Title   string             `json:"title"`
+	Release *Release           `json:"release,omitempty"`
+	Matched []string           `json:"matched"`
+	Filters []FilterTestResult `json:"filters"`
+	Error   string             `json:"error,omitempty"`
+}
+
+// NewFeedDryRunItem collects the names of the matching filters next to the full result per filter
+func NewFeedDryRunItem(release *Release, results []FilterTestResult) FeedDryRunItem {
+	item := FeedDryRunItem{
+		Title:   release.TorrentName,
+		Release: release,
+		Matched: []string{},
+		Filters: results,
+	}
+
+	for _, r := range results {
+		if r.Match {
+			item.Matched = append(item.Matched, r.FilterName)
+		}
+	}
+
+	return item
+}
+
+// FeedDryRunResponse is the report of a feed fetched once and run through the filters
+// without storing releases, running actions or touching the feed cache.
+type FeedDryRunResponse struct {
+	FeedID  int              `json:"feed_id"`
+	Feed    string           `json:"feed"`
+	Indexer string           `json:"indexer"`
+	Items   []FeedDryRunItem `json:"items"`
+}
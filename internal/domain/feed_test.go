@@ -0,0 +1,55 @@
+package domain
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFeed_HTTPHeaders(t *testing.T) {
+	tests := []struct {
+		name    string
+		headers string
+		want    http.Header
+		wantErr bool
+	}{
+		{
+			name:    "empty",
+			headers: "",
+			want:    http.Header{},
+		},
+		{
+			name:    "multiple",
+			headers: "x-api-key: abc\r\n\nAccept: application/rss+xml\nX-Api-Key: def",
+			want:    http.Header{"X-Api-Key": {"abc", "def"}, "Accept": {"application/rss+xml"}},
+		},
+		{
+			name:    "value_with_colon",
+			headers: "Authorization: Bearer a:b",
+			want:    http.Header{"Authorization": {"Bearer a:b"}},
+		},
+		{
+			name:    "missing_colon",
+			headers: "X-Api-Key abc",
+			wantErr: true,
+		},
+		{
+			name:    "empty_name",
+			headers: ": abc",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Feed{Headers: tt.headers}.HTTPHeaders()
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -2,6 +2,7 @@ package domain
 
 import (
 	"context"
+	"path"
 	"regexp"
 	"strconv"
 	"strings"
@@ -26,10 +27,14 @@ type FilterRepo interface {
 	Update(ctx context.Context, filter Filter) (*Filter, error)
 	UpdatePartial(ctx context.Context, filter FilterUpdate) error
 	ToggleEnabled(ctx context.Context, filterID int, enabled bool) error
+	UpdateBulk(ctx context.Context, update FilterBulkUpdate) error
 	Delete(ctx context.Context, filterID int) error
 	StoreIndexerConnection(ctx context.Context, filterID int, indexerID int) error
 	StoreIndexerConnections(ctx context.Context, filterID int, indexers []Indexer) error
 	DeleteIndexerConnections(ctx context.Context, filterID int) error
+	StoreRejectionCounts(ctx context.Context, counts []FilterRejectionCount) error
+	GetRejectionReasons(ctx context.Context, filterID int, since time.Time) ([]FilterRejectionReason, error)
+	DeleteRejectionCounts(ctx context.Context, before time.Time) (int64, error)
 }
 
 type FilterDownloads struct {
@@ -50,6 +55,42 @@ const (
 	FilterMaxDownloadsEver  FilterMaxDownloadsUnit = "EVER"
 )
 
+// FilterSizePreference breaks ties between equal quality releases in the smart delay window
+type FilterSizePreference string
+
+const (
+	FilterSizePreferenceNone    FilterSizePreference = ""
+	FilterSizePreferenceSmaller FilterSizePreference = "SMALLER"
+	FilterSizePreferenceLarger  FilterSizePreference = "LARGER"
+)
+
+// FilterDedupKey decides which releases from different indexers are the same release for duplicate suppression
+type FilterDedupKey string
+
+const (
+	// FilterDedupKeyDefault uses the key from the config
+	FilterDedupKeyDefault               FilterDedupKey = ""
+	FilterDedupKeyDisabled              FilterDedupKey = "DISABLED"
+	FilterDedupKeyTitle                 FilterDedupKey = "TITLE"
+	FilterDedupKeyTitleGroup            FilterDedupKey = "TITLE_GROUP"
+	FilterDedupKeyTitleResolutionSource FilterDedupKey = "TITLE_RESOLUTION_SOURCE"
+)
+
+type FilterWebhookType string
+
+const (
+	// FilterWebhookTypeHTTP posts the data to the host and checks the status code
+	FilterWebhookTypeHTTP FilterWebhookType = "HTTP"
+	// FilterWebhookTypeGRPC calls a unary gRPC method with a JSON codec and reads an ExternalFilterResponse
+	FilterWebhookTypeGRPC FilterWebhookType = "GRPC"
+)
+
+// ExternalFilterResponse is the structured decision an external check can return
+type ExternalFilterResponse struct {
+	Approved bool   `json:"approved"`
+	Reason   string `json:"reason,omitempty"`
+}
+
 type FilterQueryParams struct {
 	Sort    map[string]string
 	Filters struct {
@@ -82,6 +123,8 @@ type Filter struct {
 	Freeleech                   bool                   `json:"freeleech,omitempty"`
 	FreeleechPercent            string                 `json:"freeleech_percent,omitempty"`
 	Shows                       string                 `json:"shows,omitempty"`
+	MatchListID                 int64                  `json:"match_list_id,omitempty"`
+	ExceptListID                int64                  `json:"except_list_id,omitempty"`
 	Seasons                     string                 `json:"seasons,omitempty"`
 	Episodes                    string                 `json:"episodes,omitempty"`
 	Resolutions                 []string               `json:"resolutions,omitempty"` // SD, 480i, 480p, 576p, 720p, 810p, 1080i, 1080p.
@@ -96,7 +139,7 @@ type Filter struct {
 	Artists                     string                 `json:"artists,omitempty"`
 	Albums                      string                 `json:"albums,omitempty"`
 	MatchReleaseTypes           []string               `json:"match_release_types,omitempty"` // Album,Single,EP
-	ExceptReleaseTypes          string                 `json:"except_release_types,omitempty"`
+	ExceptReleaseTypes          []string               `json:"except_release_types,omitempty"`
 	Formats                     []string               `json:"formats,omitempty"` // MP3, FLAC, Ogg, AAC, AC3, DTS
 	Quality                     []string               `json:"quality,omitempty"` // 192, 320, APS (VBR), V2 (VBR), V1 (VBR), APX (VBR), V0 (VBR), q8.x (VBR), Lossless, 24bit Lossless, Other
 	Media                       []string               `json:"media,omitempty"`   // CD, DVD, Vinyl, Soundboard, SACD, DAT, Cassette, WEB, Other
@@ -112,6 +155,22 @@ type Filter struct {
 	ExceptTags                  string                 `json:"except_tags,omitempty"`
 	TagsAny                     string                 `json:"tags_any,omitempty"`
 	ExceptTagsAny               string                 `json:"except_tags_any,omitempty"`
+	CapturePatterns             string                 `json:"capture_patterns,omitempty"`
+	SmartDelay                  int                    `json:"smart_delay,omitempty"`
+	SmartDelayIndexers          string                 `json:"smart_delay_indexers,omitempty"`
+	SmartDelayPreferSize        FilterSizePreference   `json:"smart_delay_prefer_size,omitempty"`
+	DedupKey                    FilterDedupKey         `json:"dedup_key,omitempty"`
+	DedupWindow                 int                    `json:"dedup_window,omitempty"`
+	ArrSkipDuplicates           bool                   `json:"arr_skip_duplicates,omitempty"`
+	ArrOnlyMonitored            bool                   `json:"arr_only_monitored,omitempty"`
+	ArrSeasonPackThreshold      int                    `json:"arr_season_pack_threshold,omitempty"`
+	Schedule                    string                 `json:"schedule,omitempty"`
+	TorrentFileCheck            bool                   `json:"torrent_file_check,omitempty"`
+	RequireApproval             bool                   `json:"require_approval,omitempty"`
+	MinFiles                    int                    `json:"min_files,omitempty"`
+	MaxFiles                    int                    `json:"max_files,omitempty"`
+	MatchFileExtensions         string                 `json:"match_file_extensions,omitempty"`
+	ExceptFileExtensions        string                 `json:"except_file_extensions,omitempty"`
 	ExternalScriptEnabled       bool                   `json:"external_script_enabled,omitempty"`
 	ExternalScriptCmd           string                 `json:"external_script_cmd,omitempty"`
 	ExternalScriptArgs          string                 `json:"external_script_args,omitempty"`
@@ -120,10 +179,16 @@ type Filter struct {
 	ExternalWebhookHost         string                 `json:"external_webhook_host,omitempty"`
 	ExternalWebhookData         string                 `json:"external_webhook_data,omitempty"`
 	ExternalWebhookExpectStatus int                    `json:"external_webhook_expect_status,omitempty"`
+	ExternalWebhookType         FilterWebhookType      `json:"external_webhook_type,omitempty"`
+	ExternalWebhookParseBody    bool                   `json:"external_webhook_parse_body,omitempty"`
 	ActionsCount                int                    `json:"actions_count"`
 	Actions                     []*Action              `json:"actions,omitempty"`
 	Indexers                    []Indexer              `json:"indexers"`
 	Downloads                   *FilterDownloads       `json:"-"`
+	MatchListTitles             ListTitles             `json:"-"` // titles of MatchListID set by the filter service
+	ExceptListTitles            ListTitles             `json:"-"` // titles of ExceptListID set by the filter service
+
+	capture *compiledCapturePatterns
 }
 
 type FilterUpdate struct {
@@ -148,6 +213,8 @@ type FilterUpdate struct {
 	Freeleech                   *bool                   `json:"freeleech,omitempty"`
 	FreeleechPercent            *string                 `json:"freeleech_percent,omitempty"`
 	Shows                       *string                 `json:"shows,omitempty"`
+	MatchListID                 *int64                  `json:"match_list_id,omitempty"`
+	ExceptListID                *int64                  `json:"except_list_id,omitempty"`
 	Seasons                     *string                 `json:"seasons,omitempty"`
 	Episodes                    *string                 `json:"episodes,omitempty"`
 	Resolutions                 *[]string               `json:"resolutions,omitempty"` // SD, 480i, 480p, 576p, 720p, 810p, 1080i, 1080p.
@@ -162,7 +229,7 @@ type FilterUpdate struct {
 	Artists                     *string                 `json:"artists,omitempty"`
 	Albums                      *string                 `json:"albums,omitempty"`
 	MatchReleaseTypes           *[]string               `json:"match_release_types,omitempty"` // Album,Single,EP
-	ExceptReleaseTypes          *string                 `json:"except_release_types,omitempty"`
+	ExceptReleaseTypes          *[]string               `json:"except_release_types,omitempty"`
 	Formats                     *[]string               `json:"formats,omitempty"` // MP3, FLAC, Ogg, AAC, AC3, DTS
 	Quality                     *[]string               `json:"quality,omitempty"` // 192, 320, APS (VBR), V2 (VBR), V1 (VBR), APX (VBR), V0 (VBR), q8.x (VBR), Lossless, 24bit Lossless, Other
 	Media                       *[]string               `json:"media,omitempty"`   // CD, DVD, Vinyl, Soundboard, SACD, DAT, Cassette, WEB, Other
@@ -178,6 +245,22 @@ type FilterUpdate struct {
 	ExceptTags                  *string                 `json:"except_tags,omitempty"`
 	TagsAny                     *string                 `json:"tags_any,omitempty"`
 	ExceptTagsAny               *string                 `json:"except_tags_any,omitempty"`
+	CapturePatterns             *string                 `json:"capture_patterns,omitempty"`
+	SmartDelay                  *int                    `json:"smart_delay,omitempty"`
+	SmartDelayIndexers          *string                 `json:"smart_delay_indexers,omitempty"`
+	SmartDelayPreferSize        *FilterSizePreference   `json:"smart_delay_prefer_size,omitempty"`
+	DedupKey                    *FilterDedupKey         `json:"dedup_key,omitempty"`
+	DedupWindow                 *int                    `json:"dedup_window,omitempty"`
+	ArrSkipDuplicates           *bool                   `json:"arr_skip_duplicates,omitempty"`
+	ArrOnlyMonitored            *bool                   `json:"arr_only_monitored,omitempty"`
+	ArrSeasonPackThreshold      *int                    `json:"arr_season_pack_threshold,omitempty"`
+	Schedule                    *string                 `json:"schedule,omitempty"`
+	TorrentFileCheck            *bool                   `json:"torrent_file_check,omitempty"`
+	RequireApproval             *bool                   `json:"require_approval,omitempty"`
+	MinFiles                    *int                    `json:"min_files,omitempty"`
+	MaxFiles                    *int                    `json:"max_files,omitempty"`
+	MatchFileExtensions         *string                 `json:"match_file_extensions,omitempty"`
+	ExceptFileExtensions        *string                 `json:"except_file_extensions,omitempty"`
 	ExternalScriptEnabled       *bool                   `json:"external_script_enabled,omitempty"`
 	ExternalScriptCmd           *string                 `json:"external_script_cmd,omitempty"`
 	ExternalScriptArgs          *string                 `json:"external_script_args,omitempty"`
@@ -186,13 +269,16 @@ type FilterUpdate struct {
 	ExternalWebhookHost         *string                 `json:"external_webhook_host,omitempty"`
 	ExternalWebhookData         *string                 `json:"external_webhook_data,omitempty"`
 	ExternalWebhookExpectStatus *int                    `json:"external_webhook_expect_status,omitempty"`
+	ExternalWebhookType         *FilterWebhookType      `json:"external_webhook_type,omitempty"`
+	ExternalWebhookParseBody    *bool                   `json:"external_webhook_parse_body,omitempty"`
 	Actions                     []*Action               `json:"actions,omitempty"`
 	Indexers                    []Indexer               `json:"indexers,omitempty"`
 }
 
 func (f Filter) CheckFilter(r *Release) ([]string, bool) {
-	// reset rejections first to clean previous checks
+	// reset rejections and captures first to clean previous checks
 	r.resetRejections()
+	r.Match = nil
 
 	// max downloads check. If reached return early
 	if f.MaxDownloads > 0 && !f.checkMaxDownloads(f.MaxDownloads, f.MaxDownloadsUnit) {
@@ -224,6 +310,14 @@ func (f Filter) CheckFilter(r *Release) ([]string, bool) {
 		r.addRejectionF("shows not matching. got: %v want: %v", r.Title, f.Shows)
 	}
 
+	if f.MatchListID > 0 && !f.MatchListTitles.Contains(r.Title) {
+		r.addRejectionF("title not in list. got: %v", r.Title)
+	}
+
+	if f.ExceptListID > 0 && f.ExceptListTitles.Contains(r.Title) {
+		r.addRejectionF("title in except list. got: %v", r.Title)
+	}
+
 	if f.Seasons != "" && !containsIntStrings(r.Season, f.Seasons) {
 		r.addRejectionF("season not matching. got: %d want: %v", r.Season, f.Seasons)
 	}
@@ -317,8 +411,12 @@ func (f Filter) CheckFilter(r *Release) ([]string, bool) {
 		r.addRejectionF("category unwanted. got: %v want: %v", r.Category, f.ExceptCategories)
 	}
 
-	if len(f.MatchReleaseTypes) > 0 && !containsSlice(r.Category, f.MatchReleaseTypes) {
-		r.addRejectionF("release type not matching. got: %v want: %v", r.Category, f.MatchReleaseTypes)
+	if len(f.MatchReleaseTypes) > 0 && !containsReleaseType(r.ReleaseType(), f.MatchReleaseTypes) {
+		r.addRejectionF("release type not matching. got: %v want: %v", r.ReleaseType(), f.MatchReleaseTypes)
+	}
+
+	if len(f.ExceptReleaseTypes) > 0 && containsReleaseType(r.ReleaseType(), f.ExceptReleaseTypes) {
+		r.addRejectionF("except release type not matching. got: %v unwanted: %v", r.ReleaseType(), f.ExceptReleaseTypes)
 	}
 
 	if (f.MinSize != "" || f.MaxSize != "") && !f.checkSizeFilter(r, f.MinSize, f.MaxSize) {
@@ -333,6 +431,15 @@ func (f Filter) CheckFilter(r *Release) ([]string, bool) {
 		r.addRejectionF("tags unwanted. got: %v want: %v", r.Tags, f.ExceptTags)
 	}
 
+	if f.CapturePatterns != "" {
+		match, ok := f.capturePatterns(r)
+		if !ok {
+			r.addRejectionF("capture patterns not matching. got: %v want: %v", r.TorrentName, f.CapturePatterns)
+		}
+
+		r.Match = match
+	}
+
 	if len(f.Artists) > 0 && !containsFuzzy(r.TorrentName, f.Artists) {
 		r.addRejectionF("artists not matching. got: %v want: %v", r.TorrentName, f.Artists)
 	}
@@ -481,6 +588,58 @@ func (f Filter) checkSizeFilter(r *Release, minSize string, maxSize string) bool
 	return true
 }
 
+// CheckTorrentFiles checks the size and files parsed from the downloaded torrent file against the filter
+func (f Filter) CheckTorrentFiles(r *Release) bool {
+	if len(r.Files) == 0 {
+		r.addRejection("files: no files found in torrent")
+		return false
+	}
+
+	if !f.checkSizeFilter(r, f.MinSize, f.MaxSize) {
+		return false
+	}
+
+	if f.MinFiles > 0 && len(r.Files) < f.MinFiles {
+		r.addRejectionF("files: %d files is less than min files %d", len(r.Files), f.MinFiles)
+		return false
+	}
+
+	if f.MaxFiles > 0 && len(r.Files) > f.MaxFiles {
+		r.addRejectionF("files: %d files is more than max files %d", len(r.Files), f.MaxFiles)
+		return false
+	}
+
+	for _, file := range r.Files {
+		if f.MatchFileExtensions != "" && !containsFileExtension(f.MatchFileExtensions, file.Path) {
+			r.addRejectionF("files: extension not in match file extensions: %v", file.Path)
+			return false
+		}
+
+		if f.ExceptFileExtensions != "" && containsFileExtension(f.ExceptFileExtensions, file.Path) {
+			r.addRejectionF("files: extension in except file extensions: %v", file.Path)
+			return false
+		}
+	}
+
+	return true
+}
+
+// containsFileExtension checks the extension of the file against a comma separated list like "mkv,.srt"
+func containsFileExtension(extensions string, file string) bool {
+	ext := strings.TrimPrefix(strings.ToLower(path.Ext(file)), ".")
+	if ext == "" {
+		return false
+	}
+
+	for _, e := range strings.Split(extensions, ",") {
+		if strings.TrimPrefix(strings.ToLower(strings.TrimSpace(e)), ".") == ext {
+			return true
+		}
+	}
+
+	return false
+}
+
 func matchRegex(tag string, filterList string) bool {
 	if tag == "" {
 		return false
@@ -562,6 +721,21 @@ func containsSlice(tag string, filters []string) bool {
 	return containsMatch([]string{tag}, filters)
 }
 
+// containsReleaseType checks the normalized release type against the normalized filter values
+func containsReleaseType(releaseType string, filters []string) bool {
+	if releaseType == "" {
+		return false
+	}
+
+	for _, filter := range filters {
+		if strings.EqualFold(releaseType, NormalizeReleaseType(filter)) {
+			return true
+		}
+	}
+
+	return false
+}
+
 func containsAny(tags []string, filter string) bool {
 	return containsMatch(tags, strings.Split(filter, ","))
 }
@@ -0,0 +1,71 @@
+package domain
+
+import (
+	"fmt"
+
+	"github.com/autobrr/autobrr/pkg/errors"
+)
+
+// FilterBulkMaxDuplicates limits how many copies of a filter can be made in one request
+const FilterBulkMaxDuplicates = 100
+
+// FilterBulkUpdate changes the enabled state and/or priority of several filters at once
+type FilterBulkUpdate struct {
+	IDs      []int  `json:"ids"`
+	Enabled  *bool  `json:"enabled,omitempty"`
+	Priority *int32 `json:"priority,omitempty"`
+}
+
+func (u FilterBulkUpdate) Validate() error {
+	if len(u.IDs) == 0 {
+		return errors.New("validation: no filter ids")
+	}
+
+	if u.Enabled == nil && u.Priority == nil {
+		return errors.New("validation: nothing to update, set enabled or priority")
+	}
+
+	return nil
+}
+
+// FilterBulkDelete deletes several filters with their actions at once
+type FilterBulkDelete struct {
+	IDs []int `json:"ids"`
+}
+
+func (d FilterBulkDelete) Validate() error {
+	if len(d.IDs) == 0 {
+		return errors.New("validation: no filter ids")
+	}
+
+	return nil
+}
+
+// FilterBulkDuplicate makes Count copies of a filter named "<name> <suffix> <n>"
+type FilterBulkDuplicate struct {
+	Count  int    `json:"count"`
+	Suffix string `json:"suffix"`
+}
+
+func (d FilterBulkDuplicate) Validate() error {
+	if d.Count < 1 || d.Count > FilterBulkMaxDuplicates {
+		return errors.New("validation: count must be between 1 and %d", FilterBulkMaxDuplicates)
+	}
+
+	return nil
+}
+
+// Names returns the names of the copies of the filter
+func (d FilterBulkDuplicate) Names(name string) []string {
+	suffix := d.Suffix
+	if suffix == "" {
+		suffix = "Copy"
+	}
+
+	names := make([]string, 0, d.Count)
+	for i := 1; i <= d.Count; i++ {
+		names = append(names, fmt.Sprintf("%v %v %d", name, suffix, i))
+	}
+
+	return names
+}
@@ -0,0 +1,32 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFilterBulkDuplicate_Names(t *testing.T) {
+	tests := []struct {
+		name      string
+		duplicate FilterBulkDuplicate
+		want      []string
+	}{
+		{name: "default_suffix", duplicate: FilterBulkDuplicate{Count: 2}, want: []string{"Movies Copy 1", "Movies Copy 2"}},
+		{name: "custom_suffix", duplicate: FilterBulkDuplicate{Count: 3, Suffix: "Tracker"}, want: []string{"Movies Tracker 1", "Movies Tracker 2", "Movies Tracker 3"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.duplicate.Names("Movies"))
+		})
+	}
+}
+
+func TestFilterBulkUpdate_Validate(t *testing.T) {
+	enabled := true
+
+	assert.Error(t, FilterBulkUpdate{Enabled: &enabled}.Validate())
+	assert.Error(t, FilterBulkUpdate{IDs: []int{1, 2}}.Validate())
+	assert.NoError(t, FilterBulkUpdate{IDs: []int{1, 2}, Enabled: &enabled}.Validate())
+	assert.Error(t, FilterBulkDuplicate{Count: FilterBulkMaxDuplicates + 1}.Validate())
+}
@@ -0,0 +1,76 @@
+package domain
+
+import (
+	"strings"
+
+	"github.com/autobrr/autobrr/pkg/errors"
+)
+
+// FilterTestRequest is a raw announce or a release name to test against the filters of an indexer
+type FilterTestRequest struct {
+	Indexer     string `json:"indexer"`
+	Announce    string `json:"announce,omitempty"`
+	ReleaseName string `json:"release_name,omitempty"`
+}
+
+func (r FilterTestRequest) Validate() error {
+	if r.Indexer == "" {
+		return errors.New("validation: indexer is required")
+	}
+
+	if strings.TrimSpace(r.Announce) == "" && strings.TrimSpace(r.ReleaseName) == "" {
+		return errors.New("validation: announce or release name is required")
+	}
+
+	return nil
+}
+
+// AnnounceLines splits the pasted announce into lines, skipping empty ones
+func (r FilterTestRequest) AnnounceLines() []string {
+	var lines []string
+	for _, line := range strings.Split(r.Announce, "\n") {
+		line = strings.TrimRight(line, "\r")
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		lines = append(lines, line)
+	}
+
+	return lines
+}
+
+// FilterTestResult is the outcome of a single filter, Skipped lists the checks
+// that are not run in a dry run because they download files or call out to other services.
+type FilterTestResult struct {
+	FilterID   int      `json:"filter_id"`
+	FilterName string   `json:"filter_name"`
+	Priority   int32    `json:"priority"`
+	Match      bool     `json:"match"`
+	Rejections []string `json:"rejections"`
+	Skipped    []string `json:"skipped"`
+}
+
+type FilterTestResponse struct {
+	Release *Release           `json:"release"`
+	Filters []FilterTestResult `json:"filters"`
+}
+
+// DryRunSkippedChecks returns the filter checks a dry run does not perform
+func (f Filter) DryRunSkippedChecks(r *Release) []string {
+	skipped := []string{}
+
+	if r.Size == 0 && (f.MinSize != "" || f.MaxSize != "") {
+		skipped = append(skipped, "additional size check")
+	}
+	if f.TorrentFileCheck {
+		skipped = append(skipped, "torrent file check")
+	}
+	if f.ExternalScriptEnabled && f.ExternalScriptCmd != "" {
+		skipped = append(skipped, "external script")
+	}
+	if f.ExternalWebhookEnabled && f.ExternalWebhookHost != "" {
+		skipped = append(skipped, "external webhook")
+	}
+
+	return skipped
+}
@@ -0,0 +1,238 @@
+package domain
+
+import (
+	"encoding/json"
+
+	"github.com/autobrr/autobrr/pkg/errors"
+
+	"gopkg.in/yaml.v3"
+)
+
+// FilterExportVersion is bumped whenever the export document changes in a way older versions can't read
+const FilterExportVersion = 1
+
+type FilterImportConflict string
+
+const (
+	// FilterImportConflictRename stores the import as a new filter with a free name
+	FilterImportConflictRename FilterImportConflict = "rename"
+	// FilterImportConflictOverwrite replaces the existing filter, its indexers and actions
+	FilterImportConflictOverwrite FilterImportConflict = "overwrite"
+	// FilterImportConflictMerge updates the fields in the document and adds indexers and actions missing on the existing filter
+	FilterImportConflictMerge FilterImportConflict = "merge"
+)
+
+func (c FilterImportConflict) Valid() bool {
+	switch c {
+	case FilterImportConflictRename, FilterImportConflictOverwrite, FilterImportConflictMerge:
+		return true
+	}
+
+	return false
+}
+
+// FilterExport is a portable filter document that can be shared and imported into another instance.
+// Ids, download clients, secrets like webhook headers and the external script and webhook checks are left out.
+type FilterExport struct {
+	Version  int                      `json:"version"`
+	Name     string                   `json:"name"`
+	Filter   map[string]interface{}   `json:"filter"`
+	Actions  []map[string]interface{} `json:"actions,omitempty"`
+	Indexers []string                 `json:"indexers,omitempty"`
+}
+
+type FilterImportResult struct {
+	Filter          *Filter              `json:"filter"`
+	Conflict        FilterImportConflict `json:"conflict,omitempty"`
+	MissingIndexers []string             `json:"missing_indexers,omitempty"`
+}
+
+var (
+	// filter fields that only make sense in the instance they were exported from
+	filterExportOmit = append([]string{"id", "name", "enabled", "created_at", "updated_at", "actions_count", "actions", "indexers", "match_list_id", "except_list_id"}, filterExternalFields...)
+
+	// external checks run a command or call a url as soon as the filter is enabled, they are never shared
+	filterExternalFields = []string{"external_script_enabled", "external_script_cmd", "external_script_args", "external_webhook_enabled", "external_webhook_host", "external_webhook_data"}
+
+	// action fields tied to the instance or holding secrets
+	actionExportOmit = []string{"id", "enabled", "filter_id", "client_id", "fallback_client_id", "external_download_client_id", "client", "depends_on_filter_id", "webhook_headers"}
+)
+
+func NewFilterExport(filter Filter, actions []*Action, indexers []Indexer) (*FilterExport, error) {
+	export := &FilterExport{
+		Version: FilterExportVersion,
+		Name:    filter.Name,
+	}
+
+	f, err := toExportMap(filter, filterExportOmit)
+	if err != nil {
+		return nil, err
+	}
+	export.Filter = f
+
+	for _, action := range actions {
+		a, err := toExportMap(action, actionExportOmit)
+		if err != nil {
+			return nil, err
+		}
+		export.Actions = append(export.Actions, a)
+	}
+
+	for _, indexer := range indexers {
+		export.Indexers = append(export.Indexers, indexer.Identifier)
+	}
+
+	return export, nil
+}
+
+// ParseFilterExport reads an export document in either JSON or YAML
+func ParseFilterExport(data []byte) (*FilterExport, error) {
+	// JSON is valid YAML, so one decoder covers both
+	var raw map[string]interface{}
+	if err := yaml.Unmarshal(data, &raw); err != nil {
+		return nil, errors.Wrap(err, "could not parse filter export")
+	}
+
+	b, err := json.Marshal(raw)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not parse filter export")
+	}
+
+	var export FilterExport
+	if err := json.Unmarshal(b, &export); err != nil {
+		return nil, errors.Wrap(err, "could not parse filter export")
+	}
+
+	if err := export.Validate(); err != nil {
+		return nil, err
+	}
+
+	return &export, nil
+}
+
+func (e FilterExport) Validate() error {
+	if e.Version == 0 {
+		return errors.New("validation: missing export version")
+	}
+	if e.Version > FilterExportVersion {
+		return errors.New("validation: export version %d is newer than supported version %d", e.Version, FilterExportVersion)
+	}
+	if e.Name == "" {
+		return errors.New("validation: name can't be empty")
+	}
+
+	return nil
+}
+
+// YAML returns the document as YAML with the same field names as the JSON document
+func (e FilterExport) YAML() ([]byte, error) {
+	b, err := json.Marshal(e)
+	if err != nil {
+		return nil, err
+	}
+
+	var raw map[string]interface{}
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return nil, err
+	}
+
+	return yaml.Marshal(raw)
+}
+
+// ToFilter returns the imported filter, always disabled so it can be reviewed first
+func (e FilterExport) ToFilter() (*Filter, error) {
+	var filter Filter
+	if err := fromExportMap(e.Filter, &filter); err != nil {
+		return nil, errors.Wrap(err, "could not read filter")
+	}
+
+	filter.ID = 0
+	filter.Name = e.Name
+	filter.Enabled = false
+
+	// documents from older versions or edited by hand can still carry external checks
+	filter.ExternalScriptEnabled = false
+	filter.ExternalScriptCmd = ""
+	filter.ExternalScriptArgs = ""
+	filter.ExternalWebhookEnabled = false
+	filter.ExternalWebhookHost = ""
+	filter.ExternalWebhookData = ""
+
+	return &filter, nil
+}
+
+// ToFilterUpdate returns only the fields set in the document
+func (e FilterExport) ToFilterUpdate(filterID int) (*FilterUpdate, error) {
+	var update FilterUpdate
+	if err := fromExportMap(e.Filter, &update); err != nil {
+		return nil, errors.Wrap(err, "could not read filter")
+	}
+
+	update.ID = filterID
+	update.Name = nil
+	update.Enabled = nil
+	update.Indexers = nil
+	update.Actions = nil
+
+	// keep the external checks of the existing filter
+	update.ExternalScriptEnabled = nil
+	update.ExternalScriptCmd = nil
+	update.ExternalScriptArgs = nil
+	update.ExternalWebhookEnabled = nil
+	update.ExternalWebhookHost = nil
+	update.ExternalWebhookData = nil
+
+	return &update, nil
+}
+
+// ToActions returns the imported actions. They are disabled since they can run commands
+// and need a download client picked before they are useful.
+func (e FilterExport) ToActions() ([]*Action, error) {
+	actions := make([]*Action, 0, len(e.Actions))
+
+	for _, a := range e.Actions {
+		var action Action
+		if err := fromExportMap(a, &action); err != nil {
+			return nil, errors.Wrap(err, "could not read action")
+		}
+
+		action.ID = 0
+		action.Enabled = false
+		action.ClientID = 0
+		action.FallbackClientID = 0
+		action.ExternalDownloadClientID = 0
+		action.DependsOnFilterID = 0
+		action.WebhookHeaders = nil
+
+		actions = append(actions, &action)
+	}
+
+	return actions, nil
+}
+
+func toExportMap(v interface{}, omit []string) (map[string]interface{}, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	var m map[string]interface{}
+	if err := json.Unmarshal(b, &m); err != nil {
+		return nil, err
+	}
+
+	for _, key := range omit {
+		delete(m, key)
+	}
+
+	return m, nil
+}
+
+func fromExportMap(m map[string]interface{}, v interface{}) error {
+	b, err := json.Marshal(m)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(b, v)
+}
@@ -0,0 +1,134 @@
+package domain
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewFilterExport(t *testing.T) {
+	filter := Filter{
+		ID:                  5,
+		Name:                "Movies",
+		Enabled:             true,
+		MinSize:             "1 GB",
+		Resolutions:         []string{"1080p"},
+		ExternalScriptCmd:   "/bin/check",
+		ExternalWebhookHost: "http://localhost/check",
+	}
+	actions := []*Action{{
+		ID:             3,
+		Name:           "webhook",
+		Type:           ActionTypeWebhook,
+		Enabled:        true,
+		WebhookHost:    "http://localhost/hook",
+		WebhookHeaders: []string{"Authorization=Bearer secret"},
+		ClientID:       2,
+		FilterID:       5,
+	}}
+	indexers := []Indexer{{ID: 1, Identifier: "mock"}}
+
+	export, err := NewFilterExport(filter, actions, indexers)
+	assert.NoError(t, err)
+
+	assert.Equal(t, FilterExportVersion, export.Version)
+	assert.Equal(t, "Movies", export.Name)
+	assert.Equal(t, []string{"mock"}, export.Indexers)
+
+	assert.NotContains(t, export.Filter, "id")
+	assert.NotContains(t, export.Filter, "enabled")
+	assert.NotContains(t, export.Filter, "external_script_cmd")
+	assert.NotContains(t, export.Filter, "external_webhook_host")
+
+	assert.Len(t, export.Actions, 1)
+	assert.NotContains(t, export.Actions[0], "webhook_headers")
+	assert.NotContains(t, export.Actions[0], "client_id")
+	assert.NotContains(t, export.Actions[0], "client")
+	assert.Equal(t, "http://localhost/hook", export.Actions[0]["webhook_host"])
+}
+
+func TestParseFilterExport(t *testing.T) {
+	filter := Filter{Name: "Movies", MinSize: "1 GB", Resolutions: []string{"1080p"}, Delay: 10}
+	actions := []*Action{{Name: "test", Type: ActionTypeTest, Enabled: true}}
+
+	export, err := NewFilterExport(filter, actions, nil)
+	assert.NoError(t, err)
+
+	asJSON, err := json.Marshal(export)
+	assert.NoError(t, err)
+
+	asYAML, err := export.YAML()
+	assert.NoError(t, err)
+
+	for name, data := range map[string][]byte{"json": asJSON, "yaml": asYAML} {
+		t.Run(name, func(t *testing.T) {
+			parsed, err := ParseFilterExport(data)
+			assert.NoError(t, err)
+
+			f, err := parsed.ToFilter()
+			assert.NoError(t, err)
+			assert.Equal(t, "Movies", f.Name)
+			assert.Equal(t, "1 GB", f.MinSize)
+			assert.Equal(t, []string{"1080p"}, f.Resolutions)
+			assert.Equal(t, 10, f.Delay)
+			assert.False(t, f.Enabled)
+
+			a, err := parsed.ToActions()
+			assert.NoError(t, err)
+			assert.Len(t, a, 1)
+			assert.Equal(t, ActionTypeTest, a[0].Type)
+			assert.False(t, a[0].Enabled)
+
+			update, err := parsed.ToFilterUpdate(7)
+			assert.NoError(t, err)
+			assert.Equal(t, 7, update.ID)
+			assert.Nil(t, update.Name)
+			assert.Equal(t, "1 GB", *update.MinSize)
+			assert.Nil(t, update.MaxSize)
+		})
+	}
+}
+
+func TestFilterExport_externalChecks(t *testing.T) {
+	data := `{"version": 1, "name": "Movies", "filter": {"min_size": "1 GB", "external_script_enabled": true, "external_script_cmd": "/bin/sh", "external_script_args": "-c id", "external_webhook_enabled": true, "external_webhook_host": "http://example.com", "external_webhook_data": "{}"}}`
+
+	parsed, err := ParseFilterExport([]byte(data))
+	assert.NoError(t, err)
+
+	f, err := parsed.ToFilter()
+	assert.NoError(t, err)
+	assert.Equal(t, "1 GB", f.MinSize)
+	assert.False(t, f.ExternalScriptEnabled)
+	assert.Empty(t, f.ExternalScriptCmd)
+	assert.Empty(t, f.ExternalScriptArgs)
+	assert.False(t, f.ExternalWebhookEnabled)
+	assert.Empty(t, f.ExternalWebhookHost)
+	assert.Empty(t, f.ExternalWebhookData)
+
+	update, err := parsed.ToFilterUpdate(7)
+	assert.NoError(t, err)
+	assert.Equal(t, "1 GB", *update.MinSize)
+	assert.Nil(t, update.ExternalScriptEnabled)
+	assert.Nil(t, update.ExternalScriptCmd)
+	assert.Nil(t, update.ExternalWebhookEnabled)
+	assert.Nil(t, update.ExternalWebhookHost)
+}
+
+func TestParseFilterExport_invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+	}{
+		{name: "not_a_document", data: "- a\n- b"},
+		{name: "missing_version", data: `{"name": "Movies", "filter": {}}`},
+		{name: "newer_version", data: `{"version": 99, "name": "Movies", "filter": {}}`},
+		{name: "missing_name", data: "version: 1\nfilter: {}"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseFilterExport([]byte(tt.data))
+			assert.Error(t, err)
+		})
+	}
+}
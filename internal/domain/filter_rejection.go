@@ -0,0 +1,53 @@
+package domain
+
+import (
+	"strings"
+	"time"
+)
+
+// FilterRejectionCount is the number of rejections with the same reason for a filter in an hour
+type FilterRejectionCount struct {
+	FilterID int
+	Reason   string
+	Bucket   time.Time
+	Count    int
+}
+
+type FilterRejectionReason struct {
+	Reason string `json:"reason"`
+	Count  int    `json:"count"`
+}
+
+type FilterRejectionStats struct {
+	FilterID int                     `json:"filter_id"`
+	From     time.Time               `json:"from"`
+	To       time.Time               `json:"to"`
+	Total    int                     `json:"total"`
+	Reasons  []FilterRejectionReason `json:"reasons"`
+}
+
+// RejectionReason reduces a rejection message to the check that rejected it, eg.
+// "size not matching. got: 10 GB want min: 1 GB max: 5 GB" and "size: larger than max size" are both "size".
+func RejectionReason(rejection string) string {
+	reason := rejection
+
+	if i := strings.Index(reason, ". got"); i > 0 {
+		reason = reason[:i]
+	}
+
+	if before, after, ok := strings.Cut(reason, ":"); ok {
+		reason = before
+
+		// wanted: freeleech
+		if reason == "wanted" {
+			reason = strings.TrimSpace(after)
+		}
+	}
+
+	// max downloads (5) this (DAY) reached
+	if strings.HasPrefix(reason, "max downloads") {
+		return "max downloads"
+	}
+
+	return strings.TrimSuffix(strings.TrimSpace(reason), " not matching")
+}
@@ -0,0 +1,30 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRejectionReason(t *testing.T) {
+	tests := []struct {
+		rejection string
+		want      string
+	}{
+		{rejection: "size not matching. got: 10 GB want min: 1 GB max: 5 GB", want: "size"},
+		{rejection: "size: larger than max size", want: "size"},
+		{rejection: "resolution not matching. got: 720p want: [1080p]", want: "resolution"},
+		{rejection: "wanted: freeleech", want: "freeleech"},
+		{rejection: "wanted: perfect flac. got: [FLAC]", want: "perfect flac"},
+		{rejection: "max downloads (5) this (DAY) reached", want: "max downloads"},
+		{rejection: "unwanted release group. got: GRP unwanted: GRP", want: "unwanted release group"},
+		{rejection: "except releases regex: unwanted release. got: Some.Release want: .*", want: "except releases regex"},
+		{rejection: "files: 3 files is less than min files 4", want: "files"},
+		{rejection: "log score. got: 90 want: 100", want: "log score"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			assert.Equal(t, tt.want, RejectionReason(tt.rejection))
+		})
+	}
+}
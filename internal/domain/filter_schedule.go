@@ -0,0 +1,163 @@
+package domain
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/autobrr/autobrr/pkg/errors"
+)
+
+var scheduleWeekdays = map[string]time.Weekday{
+	"sun": time.Sunday,
+	"mon": time.Monday,
+	"tue": time.Tuesday,
+	"wed": time.Wednesday,
+	"thu": time.Thursday,
+	"fri": time.Friday,
+	"sat": time.Saturday,
+}
+
+// FilterScheduleWindow is a daily time window on a set of weekdays. Windows that end before they start
+// run past midnight into the next day, eg. "fri 22:00-06:00" is active until saturday morning.
+type FilterScheduleWindow struct {
+	Days  [7]bool
+	Start int // minutes after midnight
+	End   int // minutes after midnight
+}
+
+// FilterSchedule is a list of weekly windows in which a filter is active.
+// The format is a comma separated list of "[days] HH:MM-HH:MM" where days is a single day,
+// a range like "mon-fri" or "*", eg. "sat-sun 00:00-24:00, mon-fri 22:00-06:00".
+// Without days the window applies to every day.
+type FilterSchedule []FilterScheduleWindow
+
+func ParseFilterSchedule(schedule string) (FilterSchedule, error) {
+	var res FilterSchedule
+
+	for _, part := range strings.Split(schedule, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		window, err := parseFilterScheduleWindow(part)
+		if err != nil {
+			return nil, err
+		}
+
+		res = append(res, window)
+	}
+
+	return res, nil
+}
+
+func parseFilterScheduleWindow(s string) (FilterScheduleWindow, error) {
+	var window FilterScheduleWindow
+
+	fields := strings.Fields(strings.ToLower(s))
+
+	var days, hours string
+	switch len(fields) {
+	case 1:
+		days, hours = "*", fields[0]
+	case 2:
+		days, hours = fields[0], fields[1]
+	default:
+		return window, errors.New("invalid schedule window: %q", s)
+	}
+
+	if days == "*" {
+		for i := range window.Days {
+			window.Days[i] = true
+		}
+	} else {
+		from, to, isRange := strings.Cut(days, "-")
+
+		start, ok := scheduleWeekdays[from]
+		if !ok {
+			return window, errors.New("invalid schedule day: %q", from)
+		}
+
+		end := start
+		if isRange {
+			if end, ok = scheduleWeekdays[to]; !ok {
+				return window, errors.New("invalid schedule day: %q", to)
+			}
+		}
+
+		// ranges wrap around the week, eg. fri-mon
+		for d := start; ; d = (d + 1) % 7 {
+			window.Days[d] = true
+			if d == end {
+				break
+			}
+		}
+	}
+
+	startTime, endTime, ok := strings.Cut(hours, "-")
+	if !ok {
+		return window, errors.New("invalid schedule time range: %q", hours)
+	}
+
+	var err error
+	if window.Start, err = parseScheduleTime(startTime); err != nil {
+		return window, err
+	}
+	if window.End, err = parseScheduleTime(endTime); err != nil {
+		return window, err
+	}
+
+	return window, nil
+}
+
+// parseScheduleTime parses HH:MM into minutes after midnight, 24:00 is allowed as end of day
+func parseScheduleTime(s string) (int, error) {
+	hour, minute, ok := strings.Cut(s, ":")
+	if !ok {
+		return 0, errors.New("invalid schedule time: %q", s)
+	}
+
+	h, err := strconv.Atoi(hour)
+	if err != nil {
+		return 0, errors.New("invalid schedule time: %q", s)
+	}
+
+	m, err := strconv.Atoi(minute)
+	if err != nil {
+		return 0, errors.New("invalid schedule time: %q", s)
+	}
+
+	if h < 0 || m < 0 || m > 59 || h > 24 || (h == 24 && m != 0) {
+		return 0, errors.New("invalid schedule time: %q", s)
+	}
+
+	return h*60 + m, nil
+}
+
+// Active reports whether t falls in one of the windows, an empty schedule is always active
+func (s FilterSchedule) Active(t time.Time) bool {
+	if len(s) == 0 {
+		return true
+	}
+
+	minute := t.Hour()*60 + t.Minute()
+	day := t.Weekday()
+	previousDay := (day + 6) % 7
+
+	for _, w := range s {
+		if w.Start < w.End {
+			if w.Days[day] && minute >= w.Start && minute < w.End {
+				return true
+			}
+			continue
+		}
+
+		// window runs past midnight, equal start and end is a full 24 hours
+		if (w.Days[day] && minute >= w.Start) || (w.Days[previousDay] && minute < w.End) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,72 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseFilterSchedule(t *testing.T) {
+	tests := []struct {
+		name     string
+		schedule string
+		wantErr  string
+	}{
+		{name: "empty", schedule: ""},
+		{name: "every_day", schedule: "22:00-06:00"},
+		{name: "days", schedule: "sat-sun 00:00-24:00, mon-fri 22:00-06:00"},
+		{name: "all_days", schedule: "* 01:00-02:30"},
+		{name: "single_day", schedule: "Fri 20:00-23:00"},
+		{name: "invalid_day", schedule: "someday 20:00-23:00", wantErr: `invalid schedule day: "someday"`},
+		{name: "invalid_range", schedule: "fri 20:00", wantErr: `invalid schedule time range: "20:00"`},
+		{name: "invalid_time", schedule: "fri 25:00-26:00", wantErr: `invalid schedule time: "25:00"`},
+		{name: "invalid_window", schedule: "fri sat 20:00-23:00", wantErr: `invalid schedule window: "fri sat 20:00-23:00"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseFilterSchedule(tt.schedule)
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestFilterSchedule_Active(t *testing.T) {
+	// 2022-10-14 is a friday
+	at := func(day int, hour int, minute int) time.Time {
+		return time.Date(2022, 10, day, hour, minute, 0, 0, time.UTC)
+	}
+
+	tests := []struct {
+		name     string
+		schedule string
+		time     time.Time
+		want     bool
+	}{
+		{name: "empty", schedule: "", time: at(14, 12, 0), want: true},
+		{name: "inside", schedule: "mon-fri 09:00-17:00", time: at(14, 12, 0), want: true},
+		{name: "before", schedule: "mon-fri 09:00-17:00", time: at(14, 8, 59), want: false},
+		{name: "end_exclusive", schedule: "mon-fri 09:00-17:00", time: at(14, 17, 0), want: false},
+		{name: "wrong_day", schedule: "mon-fri 09:00-17:00", time: at(15, 12, 0), want: false},
+		{name: "overnight_start_day", schedule: "fri 22:00-06:00", time: at(14, 23, 30), want: true},
+		{name: "overnight_next_day", schedule: "fri 22:00-06:00", time: at(15, 5, 59), want: true},
+		{name: "overnight_ended", schedule: "fri 22:00-06:00", time: at(15, 6, 0), want: false},
+		{name: "overnight_day_before", schedule: "fri 22:00-06:00", time: at(14, 5, 0), want: false},
+		{name: "weekend_full_day", schedule: "sat-sun 00:00-24:00", time: at(16, 23, 59), want: true},
+		{name: "range_wraps_week", schedule: "fri-mon 10:00-11:00", time: at(16, 10, 30), want: true},
+		{name: "range_wraps_week_excluded", schedule: "fri-mon 10:00-11:00", time: at(12, 10, 30), want: false},
+		{name: "multiple_windows", schedule: "mon 01:00-02:00, fri 12:00-13:00", time: at(14, 12, 15), want: true},
+		{name: "full_day_equal_times", schedule: "fri 08:00-08:00", time: at(15, 7, 0), want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := ParseFilterSchedule(tt.schedule)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, s.Active(tt.time))
+		})
+	}
+}
@@ -953,6 +953,51 @@ func TestFilter_CheckFilter(t *testing.T) {
 			},
 			want: true,
 		},
+		{
+			name: "match_music_9",
+			fields: &Release{
+				TorrentName: "Artist - Albumname",
+				Category:    "Music",
+				Type:        "EP",
+			},
+			args: args{
+				filter: Filter{
+					Enabled:           true,
+					MatchReleaseTypes: []string{"Album", "E.P."},
+				},
+			},
+			want: true,
+		},
+		{
+			name: "match_music_10",
+			fields: &Release{
+				TorrentName: "Artist - Albumname",
+				Category:    "Compilation",
+			},
+			args: args{
+				filter: Filter{
+					Enabled:            true,
+					ExceptReleaseTypes: []string{"Compilation", "DJ Mix"},
+				},
+				rejections: []string{"except release type not matching. got: Compilation unwanted: [Compilation DJ Mix]"},
+			},
+			want: false,
+		},
+		{
+			name: "match_music_11",
+			fields: &Release{
+				TorrentName: "Artist - Albumname",
+				Type:        "Single",
+			},
+			args: args{
+				filter: Filter{
+					Enabled:            true,
+					MatchReleaseTypes:  []string{"Single"},
+					ExceptReleaseTypes: []string{"Compilation"},
+				},
+			},
+			want: true,
+		},
 		{
 			name: "match_anime_1",
 			fields: &Release{
@@ -1686,3 +1731,75 @@ func Test_matchRegex(t *testing.T) {
 		})
 	}
 }
+
+func TestFilter_CheckTorrentFiles(t *testing.T) {
+	files := []ReleaseFile{
+		{Path: "That.Show.S01/That.Show.S01E01.mkv", Size: 1000},
+		{Path: "That.Show.S01/That.Show.S01E01.srt", Size: 10},
+		{Path: "That.Show.S01/That.Show.S01E02.mkv", Size: 1000},
+	}
+
+	tests := []struct {
+		name       string
+		filter     Filter
+		release    Release
+		want       bool
+		rejections []string
+	}{
+		{
+			name:    "match",
+			filter:  Filter{MinSize: "1KB", MaxSize: "10KB", MinFiles: 2, MaxFiles: 5, MatchFileExtensions: "mkv, .SRT", ExceptFileExtensions: "rar"},
+			release: Release{Size: 2010, Files: files},
+			want:    true,
+		},
+		{
+			name:       "no_files",
+			filter:     Filter{},
+			release:    Release{Size: 2010},
+			want:       false,
+			rejections: []string{"files: no files found in torrent"},
+		},
+		{
+			name:       "actual_size_too_small",
+			filter:     Filter{MinSize: "10KB"},
+			release:    Release{Size: 2010, Files: files},
+			want:       false,
+			rejections: []string{"size: smaller than min size"},
+		},
+		{
+			name:       "too_many_files",
+			filter:     Filter{MaxFiles: 2},
+			release:    Release{Size: 2010, Files: files},
+			want:       false,
+			rejections: []string{"files: 3 files is more than max files 2"},
+		},
+		{
+			name:       "too_few_files",
+			filter:     Filter{MinFiles: 4},
+			release:    Release{Size: 2010, Files: files},
+			want:       false,
+			rejections: []string{"files: 3 files is less than min files 4"},
+		},
+		{
+			name:       "extension_not_matched",
+			filter:     Filter{MatchFileExtensions: "mkv"},
+			release:    Release{Size: 2010, Files: files},
+			want:       false,
+			rejections: []string{"files: extension not in match file extensions: That.Show.S01/That.Show.S01E01.srt"},
+		},
+		{
+			name:       "extension_excepted",
+			filter:     Filter{ExceptFileExtensions: "exe,srt"},
+			release:    Release{Size: 2010, Files: files},
+			want:       false,
+			rejections: []string{"files: extension in except file extensions: That.Show.S01/That.Show.S01E01.srt"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := tt.release
+			assert.Equal(t, tt.want, tt.filter.CheckTorrentFiles(&r))
+			assert.Equal(t, tt.rejections, r.Rejections)
+		})
+	}
+}
@@ -0,0 +1,197 @@
+package domain
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/tls"
+	"net/http"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/autobrr/autobrr/pkg/errors"
+	"github.com/autobrr/autobrr/pkg/sharedhttp"
+
+	"github.com/Masterminds/sprig/v3"
+	"github.com/dustin/go-humanize"
+)
+
+// Indexer settings added to definitions that support freeleech tokens, tokens are only spent if one is set
+const (
+	FreeleechTokenSettingMinSize = "freeleech_token_min_size"
+	FreeleechTokenSettingMaxSize = "freeleech_token_max_size"
+)
+
+// FreeleechToken is how an indexer definition spends a freeleech token on a torrent,
+// by downloading it with extra url parameters or by an api call.
+type FreeleechToken struct {
+	URLParams string             `json:"urlparams,omitempty"` // eg. usetoken=1, added to the torrent url
+	API       *FreeleechTokenAPI `json:"api,omitempty"`
+}
+
+type FreeleechTokenAPI struct {
+	Method string `json:"method,omitempty"`
+	URL    string `json:"url"` // template with the announce vars and indexer settings
+}
+
+// FreeleechTokenSettings are the indexer settings for definitions with a freeleech token
+func FreeleechTokenSettings() []IndexerSetting {
+	return []IndexerSetting{
+		{
+			Name:  FreeleechTokenSettingMinSize,
+			Type:  "text",
+			Label: "Freeleech token min size",
+			Help:  "Spend a freeleech token on actioned releases that are not freeleech and at least this size. Eg. 1GB",
+		},
+		{
+			Name:  FreeleechTokenSettingMaxSize,
+			Type:  "text",
+			Label: "Freeleech token max size",
+			Help:  "Spend a freeleech token on actioned releases that are not freeleech and at most this size. Eg. 20GB",
+		},
+	}
+}
+
+// ReleaseFreeleechToken is the request that spends a freeleech token on the release and the sizes it is spent on
+type ReleaseFreeleechToken struct {
+	Method  string
+	URL     string
+	MinSize uint64
+	MaxSize uint64
+
+	// the url downloads the torrent, anything else means the token was not spent
+	Torrent bool
+}
+
+// Apply sets the freeleech token request on the release if the indexer settings enable it
+func (t *FreeleechToken) Apply(vars map[string]string, settings map[string]string, release *Release) error {
+	minSize, maxSize := settings[FreeleechTokenSettingMinSize], settings[FreeleechTokenSettingMaxSize]
+	if minSize == "" && maxSize == "" {
+		return nil
+	}
+
+	token := &ReleaseFreeleechToken{Method: http.MethodGet}
+
+	var err error
+	if minSize != "" {
+		if token.MinSize, err = humanize.ParseBytes(minSize); err != nil {
+			return errors.Wrap(err, "could not parse freeleech token min size: %v", minSize)
+		}
+	}
+	if maxSize != "" {
+		if token.MaxSize, err = humanize.ParseBytes(maxSize); err != nil {
+			return errors.Wrap(err, "could not parse freeleech token max size: %v", maxSize)
+		}
+	}
+
+	switch {
+	case t.URLParams != "":
+		if release.TorrentURL == "" {
+			return errors.New("freeleech token needs a torrent url")
+		}
+
+		sep := "?"
+		if strings.Contains(release.TorrentURL, "?") {
+			sep = "&"
+		}
+
+		token.URL = release.TorrentURL + sep + t.URLParams
+		token.Torrent = true
+
+	case t.API != nil && t.API.URL != "":
+		tmpVars := map[string]string{}
+		for k, v := range vars {
+			tmpVars[k] = v
+		}
+		for k, v := range settings {
+			tmpVars[k] = v
+		}
+
+		tmpl, err := template.New("freeleechtoken").Funcs(sprig.TxtFuncMap()).Parse(t.API.URL)
+		if err != nil {
+			return errors.Wrap(err, "could not create freeleech token url template")
+		}
+
+		var urlBytes bytes.Buffer
+		if err := tmpl.Execute(&urlBytes, &tmpVars); err != nil {
+			return errors.Wrap(err, "could not write freeleech token url template output")
+		}
+
+		token.URL = urlBytes.String()
+		if t.API.Method != "" {
+			token.Method = strings.ToUpper(t.API.Method)
+		}
+
+	default:
+		return errors.New("freeleech token needs urlparams or an api url")
+	}
+
+	release.FreeleechToken = token
+
+	return nil
+}
+
+// UseFreeleechToken is true if a freeleech token should be spent on the release,
+// it must not be freeleech already and its size must be known and within the range.
+func (r *Release) UseFreeleechToken() bool {
+	t := r.FreeleechToken
+	if t == nil || r.Freeleech || r.FreeleechPercent >= 100 || r.Size == 0 {
+		return false
+	}
+
+	if t.MinSize > 0 && r.Size < t.MinSize {
+		return false
+	}
+
+	if t.MaxSize > 0 && r.Size > t.MaxSize {
+		return false
+	}
+
+	return true
+}
+
+// SpendFreeleechToken sends the request that spends a freeleech token on the release
+func (r *Release) SpendFreeleechToken(ctx context.Context) error {
+	if r.FreeleechToken == nil {
+		return errors.New("no freeleech token for indexer: %v", r.Indexer)
+	}
+
+	transport, err := sharedhttp.Transport(r.Proxy)
+	if err != nil {
+		return errors.Wrap(err, "could not create transport")
+	}
+
+	customTransport := transport.Clone()
+	customTransport.TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
+	client := &http.Client{Transport: customTransport, Timeout: 30 * time.Second}
+
+	req, err := http.NewRequestWithContext(ctx, r.FreeleechToken.Method, r.FreeleechToken.URL, nil)
+	if err != nil {
+		return errors.Wrap(err, "could not build freeleech token request")
+	}
+
+	if r.RawCookie != "" {
+		req.Header.Set("Cookie", r.RawCookie)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return errors.Wrap(err, "freeleech token request failed")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return errors.New("freeleech token request for %v returned status code: %d", r.TorrentName, resp.StatusCode)
+	}
+
+	// indexers answer with an error page when no tokens are left, a torrent file is a bencoded dictionary
+	if r.FreeleechToken.Torrent {
+		first, err := bufio.NewReader(resp.Body).Peek(1)
+		if err != nil || first[0] != 'd' {
+			return errors.New("freeleech token request for %v did not return a torrent, no tokens left?", r.TorrentName)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,128 @@
+package domain
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFreeleechToken_Apply(t *testing.T) {
+	vars := map[string]string{"torrentId": "1234"}
+
+	tests := []struct {
+		name     string
+		token    FreeleechToken
+		settings map[string]string
+		want     *ReleaseFreeleechToken
+		wantErr  bool
+	}{
+		{
+			name:     "url_params",
+			token:    FreeleechToken{URLParams: "usetoken=1"},
+			settings: map[string]string{FreeleechTokenSettingMaxSize: "20GB"},
+			want:     &ReleaseFreeleechToken{Method: http.MethodGet, URL: "https://example.com/torrents.php?action=download&id=1234&usetoken=1", MaxSize: 20000000000, Torrent: true},
+		},
+		{
+			name:     "api",
+			token:    FreeleechToken{API: &FreeleechTokenAPI{Method: "post", URL: "https://example.com/api/token/{{ .torrentId }}?apikey={{ .api_key }}"}},
+			settings: map[string]string{FreeleechTokenSettingMinSize: "1GB", "api_key": "secret"},
+			want:     &ReleaseFreeleechToken{Method: http.MethodPost, URL: "https://example.com/api/token/1234?apikey=secret", MinSize: 1000000000},
+		},
+		{
+			name:     "disabled",
+			token:    FreeleechToken{URLParams: "usetoken=1"},
+			settings: map[string]string{},
+			want:     nil,
+		},
+		{
+			name:     "invalid_size",
+			token:    FreeleechToken{URLParams: "usetoken=1"},
+			settings: map[string]string{FreeleechTokenSettingMaxSize: "a lot"},
+			wantErr:  true,
+		},
+		{
+			name:     "no_method",
+			token:    FreeleechToken{},
+			settings: map[string]string{FreeleechTokenSettingMaxSize: "20GB"},
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			release := &Release{TorrentURL: "https://example.com/torrents.php?action=download&id=1234"}
+
+			err := tt.token.Apply(vars, tt.settings, release)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, release.FreeleechToken)
+		})
+	}
+}
+
+func TestRelease_UseFreeleechToken(t *testing.T) {
+	token := &ReleaseFreeleechToken{MinSize: 1000, MaxSize: 5000}
+
+	tests := []struct {
+		name    string
+		release Release
+		want    bool
+	}{
+		{name: "in_range", release: Release{Size: 2000, FreeleechToken: token}, want: true},
+		{name: "partial_freeleech", release: Release{Size: 2000, FreeleechPercent: 50, FreeleechToken: token}, want: true},
+		{name: "too_small", release: Release{Size: 500, FreeleechToken: token}, want: false},
+		{name: "too_big", release: Release{Size: 6000, FreeleechToken: token}, want: false},
+		{name: "unknown_size", release: Release{FreeleechToken: token}, want: false},
+		{name: "freeleech", release: Release{Size: 2000, Freeleech: true, FreeleechToken: token}, want: false},
+		{name: "freeleech_percent", release: Release{Size: 2000, FreeleechPercent: 100, FreeleechToken: token}, want: false},
+		{name: "no_token", release: Release{Size: 2000}, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.release.UseFreeleechToken())
+		})
+	}
+}
+
+func TestRelease_SpendFreeleechToken(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Query().Get("id") {
+		case "1":
+			w.Write([]byte("d8:announce"))
+		case "2":
+			w.Write([]byte("<html>You do not have any freeleech tokens left.</html>"))
+		default:
+			w.WriteHeader(http.StatusForbidden)
+		}
+	}))
+	defer srv.Close()
+
+	tests := []struct {
+		name    string
+		token   ReleaseFreeleechToken
+		wantErr bool
+	}{
+		{name: "torrent", token: ReleaseFreeleechToken{Method: http.MethodGet, URL: srv.URL + "?id=1", Torrent: true}},
+		{name: "no_tokens_left", token: ReleaseFreeleechToken{Method: http.MethodGet, URL: srv.URL + "?id=2", Torrent: true}, wantErr: true},
+		{name: "api", token: ReleaseFreeleechToken{Method: http.MethodPost, URL: srv.URL + "?id=2"}},
+		{name: "api_error", token: ReleaseFreeleechToken{Method: http.MethodPost, URL: srv.URL + "?id=3"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			release := &Release{TorrentName: "That.Movie.2017.1080p.BluRay.x264-GROUP", FreeleechToken: &tt.token}
+
+			err := release.SpendFreeleechToken(context.Background())
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
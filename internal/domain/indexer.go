@@ -3,9 +3,12 @@ package domain
 import (
 	"bytes"
 	"context"
-	"errors"
 	"net/url"
+	"regexp"
 	"text/template"
+	"time"
+
+	"github.com/autobrr/autobrr/pkg/errors"
 
 	"github.com/Masterminds/sprig/v3"
 	"github.com/dustin/go-humanize"
@@ -27,6 +30,13 @@ type Indexer struct {
 	Enabled        bool              `json:"enabled"`
 	Implementation string            `json:"implementation"`
 	Settings       map[string]string `json:"settings,omitempty"`
+	// Proxy is used for the http traffic of the indexer, its irc network has a proxy of its own
+	Proxy string `json:"proxy"`
+}
+
+type IndexerProxyTestRequest struct {
+	Identifier string `json:"identifier"`
+	Proxy      string `json:"proxy"`
 }
 
 type IndexerDefinition struct {
@@ -46,7 +56,10 @@ type IndexerDefinition struct {
 	IRC            *IndexerIRC       `json:"irc,omitempty"`
 	Torznab        *Torznab          `json:"torznab,omitempty"`
 	RSS            *FeedSettings     `json:"rss,omitempty"`
+	API            *FeedSettings     `json:"api,omitempty"`
 	Parse          *IndexerParse     `json:"parse,omitempty"`
+	FreeleechToken *FreeleechToken   `json:"freeleechtoken,omitempty"`
+	Proxy          string            `json:"proxy,omitempty"`
 }
 
 func (i IndexerDefinition) HasApi() bool {
@@ -112,10 +125,22 @@ func (i IndexerIRC) ValidChannel(channel string) bool {
 type IndexerParse struct {
 	Type          string                `json:"type"`
 	ForceSizeUnit string                `json:"forcesizeunit"`
+	LineTimeout   int                   `json:"linetimeout,omitempty"` // seconds to wait for the next line of a multi line announce
 	Lines         []IndexerParseExtract `json:"lines"`
 	Match         IndexerParseMatch     `json:"match"`
 }
 
+// DefaultAnnounceLineTimeout is how long a partial multi line announce is kept waiting for its next line
+const DefaultAnnounceLineTimeout = 10 * time.Second
+
+func (p *IndexerParse) LineTimeoutDuration() time.Duration {
+	if p.LineTimeout <= 0 {
+		return DefaultAnnounceLineTimeout
+	}
+
+	return time.Duration(p.LineTimeout) * time.Second
+}
+
 type IndexerParseExtract struct {
 	Test    []string `json:"test"`
 	Pattern string   `json:"pattern"`
@@ -194,6 +219,54 @@ func (p *IndexerParse) ParseMatch(vars map[string]string, extraVars map[string]s
 	return nil
 }
 
+// ParseAnnounce parses the lines of a single announce into a release without processing it,
+// used to test announces against filters.
+func (i *IndexerDefinition) ParseAnnounce(lines []string) (*Release, error) {
+	if i.Parse == nil || len(i.Parse.Lines) == 0 {
+		return nil, errors.New("indexer %v does not support announce parsing", i.Identifier)
+	}
+
+	if len(lines) != len(i.Parse.Lines) {
+		return nil, errors.New("indexer %v expects %d announce lines, got %d", i.Identifier, len(i.Parse.Lines), len(lines))
+	}
+
+	vars := map[string]string{}
+
+	for idx, extract := range i.Parse.Lines {
+		rxp, err := regexp.Compile(extract.Pattern)
+		if err != nil {
+			return nil, errors.Wrap(err, "invalid pattern for line %d", idx+1)
+		}
+
+		line := StripIRCFormatting(lines[idx])
+
+		matches := rxp.FindStringSubmatch(line)
+		if matches == nil {
+			return nil, errors.New("line %d not matching expected pattern: %v", idx+1, line)
+		}
+
+		for v, name := range extract.Vars {
+			if v+1 < len(matches) {
+				vars[name] = matches[v+1]
+			}
+		}
+	}
+
+	rls := NewRelease(i.Identifier)
+
+	if err := rls.MapVars(i, vars); err != nil {
+		return nil, errors.Wrap(err, "could not map vars for release")
+	}
+
+	rls.ParseString(rls.TorrentName)
+
+	if err := i.Parse.ParseMatch(vars, i.SettingsMap, rls); err != nil {
+		return nil, errors.Wrap(err, "could not parse torrent url")
+	}
+
+	return rls, nil
+}
+
 type TorrentBasic struct {
 	Id        string `json:"Id"`
 	TorrentId string `json:"TorrentId,omitempty"`
@@ -161,3 +161,71 @@ func TestIndexerParse_ParseMatch(t *testing.T) {
 		})
 	}
 }
+
+func TestIndexerDefinition_ParseAnnounce(t *testing.T) {
+	def := &IndexerDefinition{
+		Identifier:  "mock",
+		SettingsMap: map[string]string{"rsskey": "00000000000000000000"},
+		Parse: &IndexerParse{
+			Type: "single",
+			Lines: []IndexerParseExtract{
+				{
+					Pattern: "New Torrent Announcement:\\s*<([^>]*)>\\s*Name:'(.*)' uploaded by '([^']*)'\\s*(freeleech)*\\s*-\\s*(https?\\:\\/\\/[^\\/]+\\/)torrent\\/(\\d+)",
+					Vars:    []string{"category", "torrentName", "uploader", "freeleech", "baseUrl", "torrentId"},
+				},
+			},
+			Match: IndexerParseMatch{
+				TorrentURL: "{{ .baseUrl }}rss/download/{{ .torrentId }}/{{ .rsskey }}/{{ .torrentName }}.torrent",
+				Encode:     []string{"torrentName"},
+			},
+		},
+	}
+
+	tests := []struct {
+		name    string
+		lines   []string
+		want    *Release
+		wantErr string
+	}{
+		{
+			name:  "match",
+			lines: []string{"New Torrent Announcement: <TV :: Episodes HD>  Name:'The Show 2019 S03E08 2160p DV WEBRip 6CH x265 HEVC-GROUP' uploaded by 'Anonymous' - https://mock.org/torrent/240860011"},
+			want: &Release{
+				TorrentName: "The Show 2019 S03E08 2160p DV WEBRip 6CH x265 HEVC-GROUP",
+				TorrentURL:  "https://mock.org/rss/download/240860011/00000000000000000000/The+Show+2019+S03E08+2160p+DV+WEBRip+6CH+x265+HEVC-GROUP.torrent",
+				Title:       "The Show",
+				Season:      3,
+				Episode:     8,
+				Group:       "GROUP",
+			},
+		},
+		{
+			name:    "not_matching",
+			lines:   []string{"Some other line"},
+			wantErr: "line 1 not matching expected pattern: Some other line",
+		},
+		{
+			name:    "wrong_line_count",
+			lines:   []string{"line one", "line two"},
+			wantErr: "indexer mock expects 1 announce lines, got 2",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := def.ParseAnnounce(tt.lines)
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, "mock", got.Indexer)
+			assert.Equal(t, tt.want.TorrentName, got.TorrentName)
+			assert.Equal(t, tt.want.TorrentURL, got.TorrentURL)
+			assert.Equal(t, tt.want.Title, got.Title)
+			assert.Equal(t, tt.want.Season, got.Season)
+			assert.Equal(t, tt.want.Episode, got.Episode)
+			assert.Equal(t, tt.want.Group, got.Group)
+		})
+	}
+}
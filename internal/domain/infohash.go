@@ -0,0 +1,109 @@
+package domain
+
+import (
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/base32"
+	"encoding/hex"
+	"strings"
+
+	"github.com/autobrr/autobrr/pkg/errors"
+
+	"github.com/anacrolix/torrent/bencode"
+	"github.com/anacrolix/torrent/metainfo"
+)
+
+// InfoHashes holds the info hashes of a torrent.
+// V1 torrents only have V1, v2 torrents only V2 and hybrid torrents have both.
+type InfoHashes struct {
+	V1 string // sha1 of the info dict, hex encoded
+	V2 string // sha256 of the info dict, hex encoded
+}
+
+// NewInfoHashes computes the v1 and v2 info hashes from the info dict of a torrent
+func NewInfoHashes(meta *metainfo.MetaInfo) (InfoHashes, error) {
+	var hashes InfoHashes
+
+	if meta == nil || len(meta.InfoBytes) == 0 {
+		return hashes, errors.New("torrent has no info dict")
+	}
+
+	var info struct {
+		MetaVersion int64  `bencode:"meta version,omitempty"`
+		Pieces      []byte `bencode:"pieces,omitempty"`
+	}
+
+	if err := bencode.Unmarshal(meta.InfoBytes, &info); err != nil {
+		return hashes, errors.Wrap(err, "could not decode info dict")
+	}
+
+	isV2 := info.MetaVersion == 2
+
+	// hybrid torrents keep the v1 pieces next to the v2 file tree
+	if !isV2 || len(info.Pieces) > 0 {
+		v1 := sha1.Sum(meta.InfoBytes)
+		hashes.V1 = hex.EncodeToString(v1[:])
+	}
+
+	if isV2 {
+		v2 := sha256.Sum256(meta.InfoBytes)
+		hashes.V2 = hex.EncodeToString(v2[:])
+	}
+
+	return hashes, nil
+}
+
+// Primary returns the hash used to identify the torrent in clients and trackers.
+// That is the v1 hash, or the truncated v2 hash for v2 only torrents.
+func (h InfoHashes) Primary() string {
+	if h.V1 != "" {
+		return h.V1
+	}
+	if len(h.V2) == 64 {
+		return h.V2[:40]
+	}
+	return ""
+}
+
+// Matches reports whether hash identifies the same torrent, as either v1, v2 or truncated v2 hash
+func (h InfoHashes) Matches(hash string) bool {
+	normalized, err := NormalizeInfoHash(hash)
+	if err != nil {
+		return false
+	}
+
+	switch {
+	case h.V1 != "" && normalized == h.V1:
+		return true
+	case h.V2 != "" && normalized == h.V2:
+		return true
+	case len(h.V2) == 64 && normalized == h.V2[:40]:
+		return true
+	}
+
+	return false
+}
+
+// NormalizeInfoHash validates an info hash and returns it as lowercase hex.
+// Accepts 40 char hex (v1 or truncated v2), 32 char base32 (v1 as in magnet links) and 64 char hex (v2).
+func NormalizeInfoHash(hash string) (string, error) {
+	hash = strings.TrimSpace(hash)
+
+	switch len(hash) {
+	case 40, 64:
+		b, err := hex.DecodeString(hash)
+		if err != nil {
+			return "", errors.Wrap(err, "invalid info hash: %v", hash)
+		}
+		return hex.EncodeToString(b), nil
+
+	case 32:
+		b, err := base32.StdEncoding.DecodeString(strings.ToUpper(hash))
+		if err != nil {
+			return "", errors.Wrap(err, "invalid info hash: %v", hash)
+		}
+		return hex.EncodeToString(b), nil
+	}
+
+	return "", errors.New("invalid info hash length %d: %v", len(hash), hash)
+}
@@ -0,0 +1,181 @@
+package domain
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/base32"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/anacrolix/torrent/bencode"
+	"github.com/anacrolix/torrent/metainfo"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	testV1Info = map[string]interface{}{
+		"name":         "That.Movie.2022.mkv",
+		"piece length": 16384,
+		"length":       1024,
+		"pieces":       string(bytes.Repeat([]byte{0xaa}, 20)),
+	}
+	testV2Info = map[string]interface{}{
+		"name":         "That.Movie.2022.mkv",
+		"piece length": 16384,
+		"meta version": 2,
+		"file tree": map[string]interface{}{
+			"That.Movie.2022.mkv": map[string]interface{}{
+				"": map[string]interface{}{
+					"length":      1024,
+					"pieces root": string(bytes.Repeat([]byte{0xbb}, 32)),
+				},
+			},
+		},
+	}
+	testHybridInfo = map[string]interface{}{
+		"name":         "That.Movie.2022.mkv",
+		"piece length": 16384,
+		"length":       1024,
+		"pieces":       string(bytes.Repeat([]byte{0xaa}, 20)),
+		"meta version": 2,
+		"file tree":    testV2Info["file tree"],
+	}
+)
+
+func testTorrent(t *testing.T, info map[string]interface{}) (torrent []byte, v1 string, v2 string) {
+	t.Helper()
+
+	infoBytes, err := bencode.Marshal(info)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	torrent, err = bencode.Marshal(map[string]interface{}{
+		"announce": "http://tracker.test/announce",
+		"info":     bencode.Bytes(infoBytes),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sum1 := sha1.Sum(infoBytes)
+	sum2 := sha256.Sum256(infoBytes)
+
+	return torrent, hex.EncodeToString(sum1[:]), hex.EncodeToString(sum2[:])
+}
+
+func TestNewInfoHashes(t *testing.T) {
+	tests := []struct {
+		name        string
+		info        map[string]interface{}
+		wantV1      bool
+		wantV2      bool
+		wantPrimary func(v1, v2 string) string
+	}{
+		{name: "v1", info: testV1Info, wantV1: true, wantPrimary: func(v1, v2 string) string { return v1 }},
+		{name: "v2", info: testV2Info, wantV2: true, wantPrimary: func(v1, v2 string) string { return v2[:40] }},
+		{name: "hybrid", info: testHybridInfo, wantV1: true, wantV2: true, wantPrimary: func(v1, v2 string) string { return v1 }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			torrent, v1, v2 := testTorrent(t, tt.info)
+
+			meta, err := metainfo.Load(bytes.NewReader(torrent))
+			assert.NoError(t, err)
+
+			got, err := NewInfoHashes(meta)
+			assert.NoError(t, err)
+
+			want := InfoHashes{}
+			if tt.wantV1 {
+				want.V1 = v1
+			}
+			if tt.wantV2 {
+				want.V2 = v2
+			}
+
+			assert.Equal(t, want, got)
+			assert.Equal(t, tt.wantPrimary(v1, v2), got.Primary())
+		})
+	}
+}
+
+func TestInfoHashes_Matches(t *testing.T) {
+	_, v1, v2 := testTorrent(t, testHybridInfo)
+	hashes := InfoHashes{V1: v1, V2: v2}
+
+	v1Bytes, _ := hex.DecodeString(v1)
+
+	tests := []struct {
+		name string
+		hash string
+		want bool
+	}{
+		{name: "v1", hash: v1, want: true},
+		{name: "v1_uppercase", hash: string(bytes.ToUpper([]byte(v1))), want: true},
+		{name: "v1_base32", hash: base32.StdEncoding.EncodeToString(v1Bytes), want: true},
+		{name: "v2", hash: v2, want: true},
+		{name: "v2_truncated", hash: v2[:40], want: true},
+		{name: "other", hash: "0123456789abcdef0123456789abcdef01234567", want: false},
+		{name: "invalid", hash: "not a hash", want: false},
+		{name: "empty", hash: "", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, hashes.Matches(tt.hash))
+		})
+	}
+}
+
+func TestNormalizeInfoHash(t *testing.T) {
+	tests := []struct {
+		name    string
+		hash    string
+		want    string
+		wantErr bool
+	}{
+		{name: "hex_v1", hash: " 0123456789ABCDEF0123456789ABCDEF01234567 ", want: "0123456789abcdef0123456789abcdef01234567"},
+		{name: "base32_v1", hash: "aerukz4jvpg66ajdivtytk6n54asgrlh", want: "0123456789abcdef0123456789abcdef01234567"},
+		{name: "hex_v2", hash: "0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef", want: "0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef"},
+		{name: "bad_hex", hash: "zz23456789abcdef0123456789abcdef01234567", wantErr: true},
+		{name: "bad_length", hash: "0123456789abcdef", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NormalizeInfoHash(tt.hash)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestRelease_DownloadTorrentFile_hybrid(t *testing.T) {
+	torrent, v1, v2 := testTorrent(t, testHybridInfo)
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(torrent)
+	}))
+	defer ts.Close()
+
+	r := &Release{TorrentName: "That.Movie.2022", TorrentURL: ts.URL + "/file.torrent"}
+
+	assert.NoError(t, r.DownloadTorrentFile())
+	defer os.Remove(r.TorrentTmpFile)
+
+	assert.Equal(t, v1, r.InfoHashV1)
+	assert.Equal(t, v2, r.InfoHashV2)
+	assert.Equal(t, v1, r.TorrentHash)
+	assert.Equal(t, uint64(1024), r.Size)
+
+	assert.True(t, r.InfoHashes().Matches(v1))
+	assert.True(t, r.InfoHashes().Matches(v2))
+	assert.True(t, r.InfoHashes().Matches(v2[:40]))
+}
@@ -0,0 +1,19 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// InstanceRepo coordinates instances sharing the same database
+type InstanceRepo interface {
+	// AcquireLease takes or renews the lease if it is free, expired or already held by holder
+	AcquireLease(ctx context.Context, name string, holder string, ttl time.Duration) (bool, error)
+	ReleaseLease(ctx context.Context, name string, holder string) error
+
+	// ClaimRelease returns true for the first instance to claim the key
+	ClaimRelease(ctx context.Context, key string, holder string) (bool, error)
+	// DeleteReleaseClaim gives up the claim on key if holder holds it
+	DeleteReleaseClaim(ctx context.Context, key string, holder string) error
+	DeleteReleaseClaims(ctx context.Context, before time.Time) error
+}
@@ -2,7 +2,11 @@ package domain
 
 import (
 	"context"
+	"strings"
 	"time"
+
+	"github.com/autobrr/autobrr/pkg/errors"
+	"github.com/autobrr/autobrr/pkg/sharedhttp"
 )
 
 type IrcChannel struct {
@@ -17,21 +21,113 @@ type IrcChannel struct {
 type NickServ struct {
 	Account  string `json:"account,omitempty"`
 	Password string `json:"password,omitempty"`
+	Regain   string `json:"regain,omitempty"`
 }
 
+type IrcSASLMechanism string
+
+const (
+	// IrcSASLMechanismPlain authenticates with the NickServ account and password
+	IrcSASLMechanismPlain IrcSASLMechanism = "PLAIN"
+	// IrcSASLMechanismExternal authenticates with the fingerprint of the client certificate (CertFP)
+	IrcSASLMechanismExternal IrcSASLMechanism = "EXTERNAL"
+)
+
 type IrcNetwork struct {
-	ID             int64        `json:"id"`
-	Name           string       `json:"name"`
-	Enabled        bool         `json:"enabled"`
-	Server         string       `json:"server"`
-	Port           int          `json:"port"`
-	TLS            bool         `json:"tls"`
-	Pass           string       `json:"pass"`
-	InviteCommand  string       `json:"invite_command"`
-	NickServ       NickServ     `json:"nickserv,omitempty"`
-	Channels       []IrcChannel `json:"channels"`
-	Connected      bool         `json:"connected"`
-	ConnectedSince *time.Time   `json:"connected_since"`
+	ID             int64            `json:"id"`
+	Name           string           `json:"name"`
+	Enabled        bool             `json:"enabled"`
+	Server         string           `json:"server"`
+	Port           int              `json:"port"`
+	TLS            bool             `json:"tls"`
+	Pass           string           `json:"pass"`
+	InviteCommand  string           `json:"invite_command"`
+	NickServ       NickServ         `json:"nickserv,omitempty"`
+	SASLMechanism  IrcSASLMechanism `json:"sasl_mechanism,omitempty"`
+	TLSClientCert  string           `json:"tls_client_cert,omitempty"`
+	TLSClientKey   string           `json:"tls_client_key,omitempty"`
+	Bouncer        bool             `json:"bouncer"`
+	Proxy          string           `json:"proxy"`
+	Channels       []IrcChannel     `json:"channels"`
+	Connected      bool             `json:"connected"`
+	ConnectedSince *time.Time       `json:"connected_since"`
+}
+
+func (n IrcNetwork) Validate() error {
+	switch n.SASLMechanism {
+	case "", IrcSASLMechanismPlain:
+	case IrcSASLMechanismExternal:
+		if !n.TLS {
+			return errors.New("validation: SASL EXTERNAL requires TLS")
+		}
+		if n.TLSClientCert == "" || n.TLSClientKey == "" {
+			return errors.New("validation: SASL EXTERNAL requires a client certificate and key")
+		}
+	default:
+		return errors.New("validation: unsupported SASL mechanism: %v", n.SASLMechanism)
+	}
+
+	if (n.TLSClientCert == "") != (n.TLSClientKey == "") {
+		return errors.New("validation: client certificate and key must be set together")
+	}
+
+	if n.Proxy != "" {
+		if _, err := sharedhttp.ParseProxy(n.Proxy); err != nil {
+			return errors.Wrap(err, "validation")
+		}
+	}
+
+	// bouncers identify us by the server password, or by the client certificate
+	if n.Bouncer && n.SASLMechanism != IrcSASLMechanismExternal {
+		if _, err := ParseBouncerLogin(n.Pass); err != nil {
+			return errors.Wrap(err, "validation")
+		}
+	}
+
+	return nil
+}
+
+// BouncerLogin is the login to a ZNC or soju bouncer
+type BouncerLogin struct {
+	User     string
+	Client   string
+	Network  string
+	Password string
+}
+
+// ParseBouncerLogin parses a bouncer server password in the form user[@client][/network]:password
+func ParseBouncerLogin(pass string) (BouncerLogin, error) {
+	var login BouncerLogin
+
+	username, password, ok := strings.Cut(pass, ":")
+	if !ok || username == "" || password == "" {
+		return login, errors.New("bouncer password must be in the form user[@client][/network]:password")
+	}
+
+	login.Password = password
+
+	username, login.Network, _ = strings.Cut(username, "/")
+	login.User, login.Client, _ = strings.Cut(username, "@")
+
+	if login.User == "" {
+		return login, errors.New("bouncer password is missing the user")
+	}
+
+	return login, nil
+}
+
+// Username is sent with USER and selects the user, client and network on the bouncer.
+// ZNC and soju both accept it together with the password alone as server password.
+func (l BouncerLogin) Username() string {
+	username := l.User
+	if l.Client != "" {
+		username += "@" + l.Client
+	}
+	if l.Network != "" {
+		username += "/" + l.Network
+	}
+
+	return username
 }
 
 type IrcNetworkWithHealth struct {
@@ -44,6 +140,11 @@ type IrcNetworkWithHealth struct {
 	Pass             string              `json:"pass"`
 	InviteCommand    string              `json:"invite_command"`
 	NickServ         NickServ            `json:"nickserv,omitempty"`
+	SASLMechanism    IrcSASLMechanism    `json:"sasl_mechanism,omitempty"`
+	TLSClientCert    string              `json:"tls_client_cert,omitempty"`
+	TLSClientKey     string              `json:"tls_client_key,omitempty"`
+	Bouncer          bool                `json:"bouncer"`
+	Proxy            string              `json:"proxy"`
 	CurrentNick      string              `json:"current_nick"`
 	PreferredNick    string              `json:"preferred_nick"`
 	Channels         []ChannelWithHealth `json:"channels"`
@@ -62,6 +163,7 @@ type ChannelWithHealth struct {
 	Monitoring      bool      `json:"monitoring"`
 	MonitoringSince time.Time `json:"monitoring_since"`
 	LastAnnounce    time.Time `json:"last_announce"`
+	Silent          bool      `json:"silent"`
 }
 
 type ChannelHealth struct {
@@ -69,6 +171,41 @@ type ChannelHealth struct {
 	Monitoring      bool      `json:"monitoring"`
 	MonitoringSince time.Time `json:"monitoring_since"`
 	LastAnnounce    time.Time `json:"last_announce"`
+	Silent          bool      `json:"silent"`
+}
+
+// IrcNetworkStatus is a read-only snapshot of a running irc handler
+type IrcNetworkStatus struct {
+	ID             int64           `json:"id"`
+	Name           string          `json:"name"`
+	Server         string          `json:"server"`
+	Enabled        bool            `json:"enabled"`
+	Connected      bool            `json:"connected"`
+	ConnectedSince time.Time       `json:"connected_since"`
+	CurrentNick    string          `json:"current_nick"`
+	PreferredNick  string          `json:"preferred_nick"`
+	Channels       []ChannelHealth `json:"channels"`
+	LastActivity   time.Time       `json:"last_activity"`
+}
+
+type IrcStatusEventType string
+
+const (
+	IrcStatusEventConnected       IrcStatusEventType = "CONNECTED"
+	IrcStatusEventDisconnected    IrcStatusEventType = "DISCONNECTED"
+	IrcStatusEventJoined          IrcStatusEventType = "JOINED"
+	IrcStatusEventParted          IrcStatusEventType = "PARTED"
+	IrcStatusEventAnnounceSilent  IrcStatusEventType = "ANNOUNCE_SILENT"
+	IrcStatusEventAnnounceResumed IrcStatusEventType = "ANNOUNCE_RESUMED"
+)
+
+// IrcStatusEvent is a change of the connection or a channel of a network, streamed to clients as it happens
+type IrcStatusEvent struct {
+	Timestamp time.Time          `json:"timestamp"`
+	Type      IrcStatusEventType `json:"type"`
+	NetworkID int64              `json:"network_id"`
+	Network   string             `json:"network"`
+	Channel   string             `json:"channel,omitempty"`
 }
 
 type IrcRepo interface {
@@ -85,3 +222,68 @@ type IrcRepo interface {
 	GetNetworkByID(ctx context.Context, id int64) (*IrcNetwork, error)
 	DeleteNetwork(ctx context.Context, id int64) error
 }
+
+// StripIRCFormatting removes mIRC formatting codes like bold, italic, reset and both numeric
+// (\x03FG,BG) and hex (\x04RRGGBB,RRGGBB) colors so announces can be matched as plain text.
+func StripIRCFormatting(s string) string {
+	if strings.IndexFunc(s, isIRCFormattingCode) == -1 {
+		return s
+	}
+
+	var b strings.Builder
+	b.Grow(len(s))
+
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; c {
+		case '\x02', '\x0f', '\x11', '\x16', '\x1d', '\x1e', '\x1f':
+		case '\x03':
+			i += ircColorLength(s[i+1:], 2, isDigit)
+		case '\x04':
+			i += ircColorLength(s[i+1:], 6, isHexDigit)
+		default:
+			b.WriteByte(c)
+		}
+	}
+
+	return b.String()
+}
+
+// ircColorLength returns the length of the foreground and optional background color after a color code
+func ircColorLength(s string, max int, valid func(byte) bool) int {
+	fg := 0
+	for fg < max && fg < len(s) && valid(s[fg]) {
+		fg++
+	}
+
+	if fg == 0 || fg >= len(s) || s[fg] != ',' {
+		return fg
+	}
+
+	bg := 0
+	for bg < max && fg+1+bg < len(s) && valid(s[fg+1+bg]) {
+		bg++
+	}
+
+	// a comma without a background color is part of the text
+	if bg == 0 {
+		return fg
+	}
+
+	return fg + 1 + bg
+}
+
+func isIRCFormattingCode(r rune) bool {
+	switch r {
+	case '\x02', '\x03', '\x04', '\x0f', '\x11', '\x16', '\x1d', '\x1e', '\x1f':
+		return true
+	}
+	return false
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func isHexDigit(c byte) bool {
+	return isDigit(c) || (c >= 'a' && c <= 'f') || (c >= 'A' && c <= 'F')
+}
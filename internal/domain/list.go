@@ -0,0 +1,129 @@
+package domain
+
+import (
+	"context"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/autobrr/autobrr/pkg/errors"
+)
+
+type ListRepo interface {
+	List(ctx context.Context) ([]List, error)
+	FindByID(ctx context.Context, id int64) (*List, error)
+	Store(ctx context.Context, list *List) error
+	Update(ctx context.Context, list *List) error
+	Delete(ctx context.Context, id int64) error
+	UpdateLastRefresh(ctx context.Context, list *List) error
+	StoreItems(ctx context.Context, listID int64, titles []string) error
+	GetItems(ctx context.Context, listID int64) ([]string, error)
+}
+
+type ListType string
+
+const (
+	// ListTypeSonarr syncs the monitored series of a sonarr client
+	ListTypeSonarr ListType = "SONARR"
+	// ListTypeRadarr syncs the monitored movies of a radarr client
+	ListTypeRadarr ListType = "RADARR"
+	// ListTypeTrakt syncs the items of a trakt api list url
+	ListTypeTrakt ListType = "TRAKT"
+	// ListTypeMdblist syncs the json export of a mdblist list
+	ListTypeMdblist ListType = "MDBLIST"
+	// ListTypePlainText syncs a url with one title per line
+	ListTypePlainText ListType = "PLAINTEXT"
+)
+
+type ListRefreshStatus string
+
+const (
+	ListRefreshStatusSuccess ListRefreshStatus = "SUCCESS"
+	ListRefreshStatusError   ListRefreshStatus = "ERROR"
+)
+
+// List is a source of titles filters can match or exclude releases by
+type List struct {
+	ID                 int64             `json:"id"`
+	Name               string            `json:"name"`
+	Type               ListType          `json:"type"`
+	Enabled            bool              `json:"enabled"`
+	ClientID           int               `json:"client_id"` // sonarr or radarr download client
+	URL                string            `json:"url"`
+	APIKey             string            `json:"api_key"` // trakt client id
+	IncludeUnmonitored bool              `json:"include_unmonitored"`
+	ItemCount          int               `json:"item_count"`
+	LastRefreshTime    *time.Time        `json:"last_refresh_time"`
+	LastRefreshStatus  ListRefreshStatus `json:"last_refresh_status"`
+	LastRefreshError   string            `json:"last_refresh_error"`
+	CreatedAt          time.Time         `json:"created_at"`
+	UpdatedAt          time.Time         `json:"updated_at"`
+}
+
+func (l List) Validate() error {
+	if strings.TrimSpace(l.Name) == "" {
+		return errors.New("validation: name is required")
+	}
+
+	switch l.Type {
+	case ListTypeSonarr, ListTypeRadarr:
+		if l.ClientID == 0 {
+			return errors.New("validation: client is required for %v lists", l.Type)
+		}
+
+	case ListTypeTrakt, ListTypeMdblist, ListTypePlainText:
+		u, err := url.Parse(l.URL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return errors.New("validation: invalid url: %q", l.URL)
+		}
+
+		if l.Type == ListTypeTrakt && l.APIKey == "" {
+			return errors.New("validation: trakt lists need the client id of a trakt api app as api key")
+		}
+
+	default:
+		return errors.New("validation: unsupported list type: %q", l.Type)
+	}
+
+	return nil
+}
+
+var listTitleYear = regexp.MustCompile(`\s*\(\d{4}\)$`)
+
+// NormalizeListTitle returns the title lowercased with only letters and digits so list titles
+// like "Marvel's Agents of S.H.I.E.L.D. (2013)" match the parsed release title "Marvels Agents of SHIELD"
+func NormalizeListTitle(title string) string {
+	title = listTitleYear.ReplaceAllString(strings.TrimSpace(title), "")
+	title = strings.ReplaceAll(strings.ToLower(title), "&", "and")
+
+	var b strings.Builder
+	for _, r := range title {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			b.WriteRune(r)
+		}
+	}
+
+	return b.String()
+}
+
+// ListTitles is a set of normalized list titles
+type ListTitles map[string]struct{}
+
+func NewListTitles(titles []string) ListTitles {
+	set := make(ListTitles, len(titles))
+	for _, title := range titles {
+		if normalized := NormalizeListTitle(title); normalized != "" {
+			set[normalized] = struct{}{}
+		}
+	}
+
+	return set
+}
+
+// Contains reports whether the release title is in the list
+func (t ListTitles) Contains(title string) bool {
+	_, ok := t[NormalizeListTitle(title)]
+	return ok
+}
@@ -0,0 +1,79 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizeListTitle(t *testing.T) {
+	tests := []struct {
+		title string
+		want  string
+	}{
+		{title: "The Show", want: "theshow"},
+		{title: "Marvel's Agents of S.H.I.E.L.D. (2013)", want: "marvelsagentsofshield"},
+		{title: "Law & Order", want: "lawandorder"},
+		{title: "Law and Order", want: "lawandorder"},
+		{title: "Amélie", want: "amélie"},
+		{title: "1923", want: "1923"},
+		{title: " - ", want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.title, func(t *testing.T) {
+			assert.Equal(t, tt.want, NormalizeListTitle(tt.title))
+		})
+	}
+}
+
+func TestList_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		list    List
+		wantErr bool
+	}{
+		{name: "sonarr", list: List{Name: "tv", Type: ListTypeSonarr, ClientID: 1}},
+		{name: "sonarr_no_client", list: List{Name: "tv", Type: ListTypeSonarr}, wantErr: true},
+		{name: "trakt", list: List{Name: "trakt", Type: ListTypeTrakt, URL: "https://api.trakt.tv/users/me/lists/watch/items", APIKey: "client-id"}},
+		{name: "trakt_no_key", list: List{Name: "trakt", Type: ListTypeTrakt, URL: "https://api.trakt.tv/users/me/lists/watch/items"}, wantErr: true},
+		{name: "plaintext_bad_url", list: List{Name: "txt", Type: ListTypePlainText, URL: "ftp://example.com/list.txt"}, wantErr: true},
+		{name: "no_name", list: List{Type: ListTypeMdblist, URL: "https://mdblist.com/lists/me/movies"}, wantErr: true},
+		{name: "unknown_type", list: List{Name: "x", Type: "IMDB"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.list.Validate()
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestFilter_CheckFilter_Lists(t *testing.T) {
+	titles := NewListTitles([]string{"That Show (2020)", "Other Show"})
+
+	tests := []struct {
+		name   string
+		filter Filter
+		title  string
+		want   bool
+	}{
+		{name: "in_match_list", filter: Filter{MatchListID: 1, MatchListTitles: titles}, title: "That.Show.S01E01.1080p.WEB-DL.DDP5.1.H.264-GROUP", want: true},
+		{name: "not_in_match_list", filter: Filter{MatchListID: 1, MatchListTitles: titles}, title: "Third.Show.S01E01.1080p.WEB-DL.DDP5.1.H.264-GROUP", want: false},
+		{name: "empty_match_list", filter: Filter{MatchListID: 1}, title: "That.Show.S01E01.1080p.WEB-DL.DDP5.1.H.264-GROUP", want: false},
+		{name: "in_except_list", filter: Filter{ExceptListID: 2, ExceptListTitles: titles}, title: "Other.Show.S02E03.720p.HDTV.x264-GROUP", want: false},
+		{name: "not_in_except_list", filter: Filter{ExceptListID: 2, ExceptListTitles: titles}, title: "Third.Show.S01E01.1080p.WEB-DL.DDP5.1.H.264-GROUP", want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := NewRelease("mock")
+			r.ParseString(tt.title)
+
+			rejections, _ := tt.filter.CheckFilter(r)
+			assert.Equal(t, tt.want, len(rejections) == 0, rejections)
+		})
+	}
+}
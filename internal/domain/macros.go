@@ -31,6 +31,7 @@ type Macro struct {
 	CurrentHour     int
 	CurrentMinute   int
 	CurrentSecond   int
+	Match           map[string]string
 }
 
 func NewMacro(release Release) Macro {
@@ -58,6 +59,7 @@ func NewMacro(release Release) Macro {
 		CurrentHour:     currentTime.Hour(),
 		CurrentMinute:   currentTime.Minute(),
 		CurrentSecond:   currentTime.Second(),
+		Match:           release.Match,
 	}
 
 	return ma
@@ -166,6 +166,16 @@ func TestMacros_Parse(t *testing.T) {
 			want:    "movies-2021",
 			wantErr: false,
 		},
+		{
+			name: "test_capture_groups",
+			release: Release{
+				TorrentName: "That Show S01 1080p WEB-DL-GROUP",
+				Match:       map[string]string{"group1": "That Show", "show": "That Show"},
+			},
+			args:    args{text: "/tv/{{.Match.show}}/{{.Match.group1}}"},
+			want:    "/tv/That Show/That Show",
+			wantErr: false,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -1,8 +1,15 @@
 package domain
 
 import (
+	"bytes"
 	"context"
+	"strings"
+	"text/template"
 	"time"
+
+	"github.com/autobrr/autobrr/pkg/errors"
+
+	"github.com/dustin/go-humanize"
 )
 
 type NotificationRepo interface {
@@ -20,25 +27,67 @@ type NotificationSender interface {
 }
 
 type Notification struct {
-	ID        int              `json:"id"`
-	Name      string           `json:"name"`
-	Type      NotificationType `json:"type"`
-	Enabled   bool             `json:"enabled"`
-	Events    []string         `json:"events"`
-	Token     string           `json:"token"`
-	APIKey    string           `json:"api_key"`
-	Webhook   string           `json:"webhook"`
-	Title     string           `json:"title"`
-	Icon      string           `json:"icon"`
-	Username  string           `json:"username"`
-	Host      string           `json:"host"`
-	Password  string           `json:"password"`
-	Channel   string           `json:"channel"`
-	Rooms     string           `json:"rooms"`
-	Targets   string           `json:"targets"`
-	Devices   string           `json:"devices"`
-	CreatedAt time.Time        `json:"created_at"`
-	UpdatedAt time.Time        `json:"updated_at"`
+	ID        int                          `json:"id"`
+	Name      string                       `json:"name"`
+	Type      NotificationType             `json:"type"`
+	Enabled   bool                         `json:"enabled"`
+	Events    []string                     `json:"events"`
+	Token     string                       `json:"token"`
+	APIKey    string                       `json:"api_key"`
+	Webhook   string                       `json:"webhook"`
+	Title     string                       `json:"title"`
+	Icon      string                       `json:"icon"`
+	Username  string                       `json:"username"`
+	Host      string                       `json:"host"`
+	Password  string                       `json:"password"`
+	Channel   string                       `json:"channel"`
+	Rooms     string                       `json:"rooms"`
+	Targets   string                       `json:"targets"`
+	Devices   string                       `json:"devices"`
+	Topic     string                       `json:"topic"`
+	Priority  int32                        `json:"priority"`
+	ClickURL  string                       `json:"click_url"`
+	Templates map[NotificationEvent]string `json:"templates,omitempty"`
+	CreatedAt time.Time                    `json:"created_at"`
+	UpdatedAt time.Time                    `json:"updated_at"`
+}
+
+// notificationTemplateFuncs are available in notification templates on top of the text/template builtins
+var notificationTemplateFuncs = template.FuncMap{
+	"join":  strings.Join,
+	"bytes": humanize.Bytes,
+}
+
+// ValidateTemplates parses all templates so mistakes are caught when saving instead of when sending
+func (n Notification) ValidateTemplates() error {
+	for event, tmpl := range n.Templates {
+		if _, err := template.New(string(event)).Funcs(notificationTemplateFuncs).Parse(tmpl); err != nil {
+			return errors.Wrap(err, "invalid template for event: %v", event)
+		}
+	}
+
+	return nil
+}
+
+// RenderTemplate renders the custom message template for the event with the payload as data.
+// It returns false if there is no template for the event and the sender should build its default message.
+func (n Notification) RenderTemplate(event NotificationEvent, payload NotificationPayload) (string, bool, error) {
+	tmpl, ok := n.Templates[event]
+	if !ok || strings.TrimSpace(tmpl) == "" {
+		return "", false, nil
+	}
+
+	t, err := template.New(string(event)).Funcs(notificationTemplateFuncs).Option("missingkey=zero").Parse(tmpl)
+	if err != nil {
+		return "", false, errors.Wrap(err, "could not parse template for event: %v", event)
+	}
+
+	var buf bytes.Buffer
+	if err := t.Execute(&buf, payload); err != nil {
+		return "", false, errors.Wrap(err, "could not render template for event: %v", event)
+	}
+
+	return buf.String(), true, nil
 }
 
 type NotificationPayload struct {
@@ -58,6 +107,9 @@ type NotificationPayload struct {
 	Protocol       ReleaseProtocol       // torrent
 	Implementation ReleaseImplementation // irc, rss, api
 	Timestamp      time.Time
+	Release        *Release // set for push events, nil otherwise
+	ApproveURL     string   // set for pending approval events
+	RejectURL      string   // set for pending approval events
 }
 
 type NotificationType string
@@ -74,18 +126,25 @@ const (
 	NotificationTypeRocketChat NotificationType = "ROCKETCHAT"
 	NotificationTypeSlack      NotificationType = "SLACK"
 	NotificationTypeTelegram   NotificationType = "TELEGRAM"
+	NotificationTypeNtfy       NotificationType = "NTFY"
+	NotificationTypeApprise    NotificationType = "APPRISE"
 )
 
 type NotificationEvent string
 
 const (
-	NotificationEventAppUpdateAvailable NotificationEvent = "APP_UPDATE_AVAILABLE"
-	NotificationEventPushApproved       NotificationEvent = "PUSH_APPROVED"
-	NotificationEventPushRejected       NotificationEvent = "PUSH_REJECTED"
-	NotificationEventPushError          NotificationEvent = "PUSH_ERROR"
-	NotificationEventIRCDisconnected    NotificationEvent = "IRC_DISCONNECTED"
-	NotificationEventIRCReconnected     NotificationEvent = "IRC_RECONNECTED"
-	NotificationEventTest               NotificationEvent = "TEST"
+	NotificationEventAppUpdateAvailable      NotificationEvent = "APP_UPDATE_AVAILABLE"
+	NotificationEventPushApproved            NotificationEvent = "PUSH_APPROVED"
+	NotificationEventPushRejected            NotificationEvent = "PUSH_REJECTED"
+	NotificationEventPushError               NotificationEvent = "PUSH_ERROR"
+	NotificationEventIRCDisconnected         NotificationEvent = "IRC_DISCONNECTED"
+	NotificationEventIRCReconnected          NotificationEvent = "IRC_RECONNECTED"
+	NotificationEventIRCAnnounceSilent       NotificationEvent = "IRC_ANNOUNCE_SILENT"
+	NotificationEventIRCAnnounceResumed      NotificationEvent = "IRC_ANNOUNCE_RESUMED"
+	NotificationEventDownloadClientDown      NotificationEvent = "DOWNLOAD_CLIENT_DOWN"
+	NotificationEventDownloadClientRecovered NotificationEvent = "DOWNLOAD_CLIENT_RECOVERED"
+	NotificationEventReleasePendingApproval  NotificationEvent = "RELEASE_PENDING_APPROVAL"
+	NotificationEventTest                    NotificationEvent = "TEST"
 )
 
 type NotificationEventArr []NotificationEvent
@@ -0,0 +1,80 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNotification_RenderTemplate(t *testing.T) {
+	release := NewRelease("mock")
+	release.ParseString("That.Show.S01E01.1080p.WEB-DL-GROUP")
+
+	payload := NotificationPayload{
+		Event:       NotificationEventPushRejected,
+		ReleaseName: "That.Show.S01E01.1080p.WEB-DL-GROUP",
+		Filter:      "TV",
+		Indexer:     "mock",
+		Size:        1500000000,
+		Rejections:  []string{"Unknown Series", "Not wanted"},
+		Release:     release,
+	}
+
+	tests := []struct {
+		name      string
+		templates map[NotificationEvent]string
+		event     NotificationEvent
+		want      string
+		wantOk    bool
+		wantErr   bool
+	}{
+		{
+			name:   "no_template",
+			event:  NotificationEventPushRejected,
+			wantOk: false,
+		},
+		{
+			name:      "other_event",
+			templates: map[NotificationEvent]string{NotificationEventPushApproved: "grabbed {{ .ReleaseName }}"},
+			event:     NotificationEventPushRejected,
+			wantOk:    false,
+		},
+		{
+			name:      "payload_fields",
+			templates: map[NotificationEvent]string{NotificationEventPushRejected: "{{ .ReleaseName }} rejected by {{ .Filter }}: {{ join .Rejections \", \" }} ({{ bytes .Size }})"},
+			event:     NotificationEventPushRejected,
+			want:      "That.Show.S01E01.1080p.WEB-DL-GROUP rejected by TV: Unknown Series, Not wanted (1.5 GB)",
+			wantOk:    true,
+		},
+		{
+			name:      "release_fields",
+			templates: map[NotificationEvent]string{NotificationEventPushRejected: "{{ with .Release }}{{ .Title }} S{{ .Season }}E{{ .Episode }} {{ .Resolution }}{{ end }}"},
+			event:     NotificationEventPushRejected,
+			want:      "That Show S1E1 1080p",
+			wantOk:    true,
+		},
+		{
+			name:      "invalid_template",
+			templates: map[NotificationEvent]string{NotificationEventPushRejected: "{{ .ReleaseName "},
+			event:     NotificationEventPushRejected,
+			wantErr:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			n := Notification{Templates: tt.templates}
+
+			got, ok, err := n.RenderTemplate(tt.event, payload)
+			if tt.wantErr {
+				assert.Error(t, err)
+				assert.Error(t, n.ValidateTemplates())
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.NoError(t, n.ValidateTemplates())
+			assert.Equal(t, tt.wantOk, ok)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
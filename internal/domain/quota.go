@@ -0,0 +1,106 @@
+package domain
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/autobrr/autobrr/pkg/errors"
+
+	"github.com/dustin/go-humanize"
+)
+
+type QuotaRepo interface {
+	List(ctx context.Context) ([]Quota, error)
+	FindByID(ctx context.Context, id int) (*Quota, error)
+	FindActive(ctx context.Context, filterID int, indexer string) ([]Quota, error)
+	Usage(ctx context.Context, quota Quota) (*QuotaUsage, error)
+	Store(ctx context.Context, quota *Quota) error
+	Update(ctx context.Context, quota *Quota) error
+	Delete(ctx context.Context, id int) error
+}
+
+type QuotaScope string
+
+const (
+	QuotaScopeFilter  QuotaScope = "FILTER"
+	QuotaScopeIndexer QuotaScope = "INDEXER"
+)
+
+// Quota limits the number of downloads and/or the amount of data grabbed by a filter or from an indexer per period
+type Quota struct {
+	ID           int                    `json:"id"`
+	Name         string                 `json:"name"`
+	Enabled      bool                   `json:"enabled"`
+	Scope        QuotaScope             `json:"scope"`
+	FilterID     int                    `json:"filter_id,omitempty"`
+	Indexer      string                 `json:"indexer,omitempty"`
+	MaxDownloads int                    `json:"max_downloads"`
+	MaxSize      string                 `json:"max_size"`
+	Period       FilterMaxDownloadsUnit `json:"period"`
+	CreatedAt    time.Time              `json:"created_at"`
+	UpdatedAt    time.Time              `json:"updated_at"`
+}
+
+// QuotaUsage is what has been grabbed in the current quota period
+type QuotaUsage struct {
+	Downloads int    `json:"downloads"`
+	Size      uint64 `json:"size"`
+}
+
+func (q Quota) Validate() error {
+	if q.Name == "" {
+		return errors.New("name is required")
+	}
+
+	switch q.Scope {
+	case QuotaScopeFilter:
+		if q.FilterID == 0 {
+			return errors.New("filter is required for filter quota")
+		}
+	case QuotaScopeIndexer:
+		if q.Indexer == "" {
+			return errors.New("indexer is required for indexer quota")
+		}
+	default:
+		return errors.New("invalid scope: %v", q.Scope)
+	}
+
+	switch q.Period {
+	case FilterMaxDownloadsHour, FilterMaxDownloadsDay, FilterMaxDownloadsWeek, FilterMaxDownloadsMonth, FilterMaxDownloadsEver:
+	default:
+		return errors.New("invalid period: %v", q.Period)
+	}
+
+	if q.MaxDownloads <= 0 && q.MaxSize == "" {
+		return errors.New("max downloads or max size is required")
+	}
+
+	if q.MaxSize != "" {
+		if _, err := humanize.ParseBytes(q.MaxSize); err != nil {
+			return errors.New("invalid max size: %v", q.MaxSize)
+		}
+	}
+
+	return nil
+}
+
+// Check returns a rejection if grabbing a release of size would exceed the quota
+func (q Quota) Check(usage QuotaUsage, size uint64) (string, bool) {
+	if q.MaxDownloads > 0 && usage.Downloads >= q.MaxDownloads {
+		return fmt.Sprintf("quota %v reached: max downloads (%d) this (%v)", q.Name, q.MaxDownloads, q.Period), false
+	}
+
+	if q.MaxSize != "" {
+		maxSize, err := humanize.ParseBytes(q.MaxSize)
+		if err != nil {
+			return fmt.Sprintf("quota %v has invalid max size: %v", q.Name, q.MaxSize), false
+		}
+
+		if usage.Size+size > maxSize {
+			return fmt.Sprintf("quota %v reached: max size (%v) this (%v), used: %v release: %v", q.Name, q.MaxSize, q.Period, humanize.Bytes(usage.Size), humanize.Bytes(size)), false
+		}
+	}
+
+	return "", true
+}
@@ -0,0 +1,115 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQuota_Check(t *testing.T) {
+	tests := []struct {
+		name  string
+		quota Quota
+		usage QuotaUsage
+		size  uint64
+		want  bool
+	}{
+		{
+			name:  "downloads_below_limit",
+			quota: Quota{Name: "daily", MaxDownloads: 5, Period: FilterMaxDownloadsDay},
+			usage: QuotaUsage{Downloads: 4},
+			want:  true,
+		},
+		{
+			name:  "downloads_limit_reached",
+			quota: Quota{Name: "daily", MaxDownloads: 5, Period: FilterMaxDownloadsDay},
+			usage: QuotaUsage{Downloads: 5},
+			want:  false,
+		},
+		{
+			name:  "size_below_limit",
+			quota: Quota{Name: "weekly", MaxSize: "100 GB", Period: FilterMaxDownloadsWeek},
+			usage: QuotaUsage{Downloads: 10, Size: 90_000_000_000},
+			size:  10_000_000_000,
+			want:  true,
+		},
+		{
+			name:  "size_limit_exceeded_by_release",
+			quota: Quota{Name: "weekly", MaxSize: "100 GB", Period: FilterMaxDownloadsWeek},
+			usage: QuotaUsage{Downloads: 10, Size: 90_000_000_000},
+			size:  10_000_000_001,
+			want:  false,
+		},
+		{
+			name:  "both_limits_downloads_reached",
+			quota: Quota{Name: "monthly", MaxDownloads: 2, MaxSize: "1 TB", Period: FilterMaxDownloadsMonth},
+			usage: QuotaUsage{Downloads: 2, Size: 1000},
+			size:  1000,
+			want:  false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rejection, ok := tt.quota.Check(tt.usage, tt.size)
+			assert.Equal(t, tt.want, ok)
+			if tt.want {
+				assert.Empty(t, rejection)
+			} else {
+				assert.Contains(t, rejection, tt.quota.Name)
+			}
+		})
+	}
+}
+
+func TestQuota_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		quota   Quota
+		wantErr string
+	}{
+		{
+			name:  "valid_filter_quota",
+			quota: Quota{Name: "q", Scope: QuotaScopeFilter, FilterID: 1, MaxDownloads: 1, Period: FilterMaxDownloadsDay},
+		},
+		{
+			name:  "valid_indexer_quota",
+			quota: Quota{Name: "q", Scope: QuotaScopeIndexer, Indexer: "mock", MaxSize: "50GB", Period: FilterMaxDownloadsEver},
+		},
+		{
+			name:    "missing_name",
+			quota:   Quota{Scope: QuotaScopeFilter, FilterID: 1, MaxDownloads: 1, Period: FilterMaxDownloadsDay},
+			wantErr: "name is required",
+		},
+		{
+			name:    "missing_indexer",
+			quota:   Quota{Name: "q", Scope: QuotaScopeIndexer, MaxDownloads: 1, Period: FilterMaxDownloadsDay},
+			wantErr: "indexer is required for indexer quota",
+		},
+		{
+			name:    "invalid_period",
+			quota:   Quota{Name: "q", Scope: QuotaScopeFilter, FilterID: 1, MaxDownloads: 1, Period: "YEAR"},
+			wantErr: "invalid period: YEAR",
+		},
+		{
+			name:    "no_limits",
+			quota:   Quota{Name: "q", Scope: QuotaScopeFilter, FilterID: 1, Period: FilterMaxDownloadsDay},
+			wantErr: "max downloads or max size is required",
+		},
+		{
+			name:    "invalid_size",
+			quota:   Quota{Name: "q", Scope: QuotaScopeFilter, FilterID: 1, MaxSize: "lots", Period: FilterMaxDownloadsDay},
+			wantErr: "invalid max size: lots",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.quota.Validate()
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+
+			assert.EqualError(t, err, tt.wantErr)
+		})
+	}
+}
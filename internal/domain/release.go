@@ -8,13 +8,16 @@ import (
 	"io"
 	"net/http"
 	"net/http/cookiejar"
+	"net/url"
 	"os"
 	"regexp"
 	"strconv"
 	"strings"
 	"time"
+	"unicode"
 
 	"github.com/autobrr/autobrr/pkg/errors"
+	"github.com/autobrr/autobrr/pkg/sharedhttp"
 
 	"github.com/anacrolix/torrent/metainfo"
 	"github.com/dustin/go-humanize"
@@ -25,67 +28,83 @@ import (
 type ReleaseRepo interface {
 	Store(ctx context.Context, release *Release) (*Release, error)
 	Find(ctx context.Context, params ReleaseQueryParams) (res []*Release, nextCursor int64, count int64, err error)
+	FindByID(ctx context.Context, id int64) (*Release, error)
 	FindRecent(ctx context.Context) ([]*Release, error)
 	GetIndexerOptions(ctx context.Context) ([]string, error)
 	GetActionStatusByReleaseID(ctx context.Context, releaseID int64) ([]ReleaseActionStatus, error)
 	Stats(ctx context.Context) (*ReleaseStats, error)
 	StoreReleaseActionStatus(ctx context.Context, actionStatus *ReleaseActionStatus) error
+	UpdateFilterStatus(ctx context.Context, id int64, status ReleaseFilterStatus) error
 	Delete(ctx context.Context) error
+	Prune(ctx context.Context, retention ReleaseRetention) (int64, error)
+
+	// ClaimDedup records the release for the key unless another release was recorded since the given time,
+	// that release is returned instead.
+	ClaimDedup(ctx context.Context, key string, since time.Time, release *Release) (*ReleaseDedup, error)
+	// DeleteDedupKey deletes the key if it was recorded for the release
+	DeleteDedupKey(ctx context.Context, key string, release *Release) error
+	DeleteDedup(ctx context.Context, before time.Time) (int64, error)
 }
 
 type Release struct {
-	ID                          int64                 `json:"id"`
-	FilterStatus                ReleaseFilterStatus   `json:"filter_status"`
-	Rejections                  []string              `json:"rejections"`
-	Indexer                     string                `json:"indexer"`
-	FilterName                  string                `json:"filter"`
-	Protocol                    ReleaseProtocol       `json:"protocol"`
-	Implementation              ReleaseImplementation `json:"implementation"` // irc, rss, api
-	Timestamp                   time.Time             `json:"timestamp"`
-	GroupID                     string                `json:"group_id"`
-	TorrentID                   string                `json:"torrent_id"`
-	TorrentURL                  string                `json:"-"`
-	TorrentTmpFile              string                `json:"-"`
-	TorrentDataRawBytes         []byte                `json:"-"`
-	TorrentHash                 string                `json:"-"`
-	TorrentName                 string                `json:"torrent_name"` // full release name
-	Size                        uint64                `json:"size"`
-	Title                       string                `json:"title"` // Parsed title
-	Category                    string                `json:"category"`
-	Season                      int                   `json:"season"`
-	Episode                     int                   `json:"episode"`
-	Year                        int                   `json:"year"`
-	Resolution                  string                `json:"resolution"`
-	Source                      string                `json:"source"`
-	Codec                       []string              `json:"codec"`
-	Container                   string                `json:"container"`
-	HDR                         []string              `json:"hdr"`
-	Audio                       []string              `json:"-"`
-	AudioChannels               string                `json:"-"`
-	Group                       string                `json:"group"`
-	Region                      string                `json:"-"`
-	Language                    string                `json:"-"`
-	Proper                      bool                  `json:"proper"`
-	Repack                      bool                  `json:"repack"`
-	Website                     string                `json:"website"`
-	Artists                     string                `json:"-"`
-	Type                        string                `json:"type"` // Album,Single,EP
-	LogScore                    int                   `json:"-"`
-	IsScene                     bool                  `json:"-"`
-	Origin                      string                `json:"origin"` // P2P, Internal
-	Tags                        []string              `json:"-"`
-	ReleaseTags                 string                `json:"-"`
-	Freeleech                   bool                  `json:"-"`
-	FreeleechPercent            int                   `json:"-"`
-	Bonus                       []string              `json:"-"`
-	Uploader                    string                `json:"uploader"`
-	PreTime                     string                `json:"pre_time"`
-	Other                       []string              `json:"-"`
-	RawCookie                   string                `json:"-"`
-	AdditionalSizeCheckRequired bool                  `json:"-"`
-	FilterID                    int                   `json:"-"`
-	Filter                      *Filter               `json:"-"`
-	ActionStatus                []ReleaseActionStatus `json:"action_status"`
+	ID                          int64                  `json:"id"`
+	FilterStatus                ReleaseFilterStatus    `json:"filter_status"`
+	Rejections                  []string               `json:"rejections"`
+	Indexer                     string                 `json:"indexer"`
+	FilterName                  string                 `json:"filter"`
+	Protocol                    ReleaseProtocol        `json:"protocol"`
+	Implementation              ReleaseImplementation  `json:"implementation"` // irc, rss, api
+	Timestamp                   time.Time              `json:"timestamp"`
+	GroupID                     string                 `json:"group_id"`
+	TorrentID                   string                 `json:"torrent_id"`
+	TorrentURL                  string                 `json:"-"`
+	TorrentTmpFile              string                 `json:"-"`
+	TorrentDataRawBytes         []byte                 `json:"-"`
+	TorrentHash                 string                 `json:"-"`
+	InfoHashV1                  string                 `json:"-"`
+	InfoHashV2                  string                 `json:"-"`
+	TorrentName                 string                 `json:"torrent_name"` // full release name
+	Size                        uint64                 `json:"size"`
+	Files                       []ReleaseFile          `json:"-"`
+	Title                       string                 `json:"title"` // Parsed title
+	Category                    string                 `json:"category"`
+	Season                      int                    `json:"season"`
+	Episode                     int                    `json:"episode"`
+	Year                        int                    `json:"year"`
+	Resolution                  string                 `json:"resolution"`
+	Source                      string                 `json:"source"`
+	Codec                       []string               `json:"codec"`
+	Container                   string                 `json:"container"`
+	HDR                         []string               `json:"hdr"`
+	Audio                       []string               `json:"-"`
+	AudioChannels               string                 `json:"-"`
+	Group                       string                 `json:"group"`
+	Region                      string                 `json:"-"`
+	Language                    string                 `json:"-"`
+	Proper                      bool                   `json:"proper"`
+	Repack                      bool                   `json:"repack"`
+	Website                     string                 `json:"website"`
+	Artists                     string                 `json:"-"`
+	Type                        string                 `json:"type"` // Album,Single,EP
+	LogScore                    int                    `json:"-"`
+	IsScene                     bool                   `json:"-"`
+	Origin                      string                 `json:"origin"` // P2P, Internal
+	Tags                        []string               `json:"-"`
+	ReleaseTags                 string                 `json:"-"`
+	Freeleech                   bool                   `json:"-"`
+	FreeleechPercent            int                    `json:"-"`
+	Bonus                       []string               `json:"-"`
+	Uploader                    string                 `json:"uploader"`
+	PreTime                     string                 `json:"pre_time"`
+	Other                       []string               `json:"-"`
+	RawCookie                   string                 `json:"-"`
+	Proxy                       string                 `json:"-"`
+	FreeleechToken              *ReleaseFreeleechToken `json:"-"`
+	AdditionalSizeCheckRequired bool                   `json:"-"`
+	FilterID                    int                    `json:"-"`
+	Filter                      *Filter                `json:"-"`
+	Match                       map[string]string      `json:"-"`
+	ActionStatus                []ReleaseActionStatus  `json:"action_status"`
 }
 
 type ReleaseActionStatus struct {
@@ -96,21 +115,53 @@ type ReleaseActionStatus struct {
 	Client     string            `json:"client"`
 	Filter     string            `json:"filter"`
 	Rejections []string          `json:"rejections"`
+	Log        string            `json:"log,omitempty"`
 	Timestamp  time.Time         `json:"timestamp"`
 	ReleaseID  int64             `json:"-"`
 }
 
+// ReleaseFile is a single file of the release torrent
+type ReleaseFile struct {
+	Path string `json:"path"`
+	Size int64  `json:"size"`
+}
+
 type DownloadTorrentFileResponse struct {
 	MetaInfo    *metainfo.MetaInfo
 	TmpFileName string
 }
 
+// ReleaseRetention limits the release history, zero MaxAgeDays or MaxRows disables that limit
+type ReleaseRetention struct {
+	MaxAgeDays int `json:"max_age_days"`
+	MaxRows    int `json:"max_rows"`
+	// KeepApproved never prunes releases that passed a filter
+	KeepApproved bool `json:"keep_approved"`
+}
+
+func NewReleaseRetention(config *Config) ReleaseRetention {
+	return ReleaseRetention{
+		MaxAgeDays:   config.ReleaseRetentionDays,
+		MaxRows:      config.ReleaseRetentionMaxRows,
+		KeepApproved: config.ReleaseRetentionKeepApproved,
+	}
+}
+
+func (r ReleaseRetention) Enabled() bool {
+	return r.MaxAgeDays > 0 || r.MaxRows > 0
+}
+
+func (r ReleaseRetention) MaxAge() time.Duration {
+	return time.Duration(r.MaxAgeDays) * 24 * time.Hour
+}
+
 type ReleaseStats struct {
 	TotalCount          int64 `json:"total_count"`
 	FilteredCount       int64 `json:"filtered_count"`
 	FilterRejectedCount int64 `json:"filter_rejected_count"`
 	PushApprovedCount   int64 `json:"push_approved_count"`
 	PushRejectedCount   int64 `json:"push_rejected_count"`
+	BlockedCount        int64 `json:"blocked_count"`
 }
 
 type ReleasePushStatus string
@@ -141,8 +192,10 @@ type ReleaseFilterStatus string
 const (
 	ReleaseStatusFilterApproved ReleaseFilterStatus = "FILTER_APPROVED"
 	ReleaseStatusFilterPending  ReleaseFilterStatus = "PENDING"
+	ReleaseStatusFilterRejected ReleaseFilterStatus = "FILTER_REJECTED"
 
-	//ReleaseStatusFilterRejected ReleaseFilterStatus = "FILTER_REJECTED"
+	// ReleaseStatusFilterPendingApproval matched a filter that requires approval before its actions run
+	ReleaseStatusFilterPendingApproval ReleaseFilterStatus = "PENDING_APPROVAL"
 )
 
 type ReleaseProtocol string
@@ -157,18 +210,181 @@ const (
 	ReleaseImplementationIRC     ReleaseImplementation = "IRC"
 	ReleaseImplementationTorznab ReleaseImplementation = "TORZNAB"
 	ReleaseImplementationRSS     ReleaseImplementation = "RSS"
+	ReleaseImplementationWebhook ReleaseImplementation = "WEBHOOK"
+	ReleaseImplementationAPI     ReleaseImplementation = "API"
 )
 
+// ReleaseWebhookPayload is a release pushed to autobrr by an external source
+type ReleaseWebhookPayload struct {
+	Name        string   `json:"name"`
+	DownloadURL string   `json:"download_url"`
+	Indexer     string   `json:"indexer"`
+	Size        string   `json:"size,omitempty"` // bytes or human readable, eg. 1.5 GB
+	Category    string   `json:"category,omitempty"`
+	Tags        []string `json:"tags,omitempty"`
+	Uploader    string   `json:"uploader,omitempty"`
+	Freeleech   bool     `json:"freeleech,omitempty"`
+}
+
+func (p ReleaseWebhookPayload) Validate() error {
+	if p.Name == "" {
+		return errors.New("name is required")
+	}
+	if p.Indexer == "" {
+		return errors.New("indexer is required")
+	}
+	if p.DownloadURL == "" {
+		return errors.New("download_url is required")
+	}
+
+	u, err := url.Parse(p.DownloadURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return errors.New("download_url must be a valid http(s) url")
+	}
+
+	if p.Size != "" {
+		if _, err := humanize.ParseBytes(p.Size); err != nil {
+			return errors.New("size is invalid: %v", p.Size)
+		}
+	}
+
+	return nil
+}
+
+// Release creates a release from the payload ready to be processed
+func (p ReleaseWebhookPayload) Release() *Release {
+	r := NewRelease(p.Indexer)
+
+	r.Implementation = ReleaseImplementationWebhook
+	r.TorrentURL = p.DownloadURL
+	r.Category = p.Category
+	r.Uploader = p.Uploader
+	r.Freeleech = p.Freeleech
+
+	if p.Freeleech {
+		r.Bonus = append(r.Bonus, "Freeleech")
+	}
+
+	if len(p.Tags) > 0 {
+		r.Tags = p.Tags
+	}
+
+	if p.Size != "" {
+		r.ParseSizeBytesString(p.Size)
+	}
+
+	r.ParseString(p.Name)
+
+	return r
+}
+
 type ReleaseQueryParams struct {
 	Limit   uint64
 	Offset  uint64
 	Cursor  uint64
 	Sort    map[string]string
-	Filters struct {
-		Indexers   []string
-		PushStatus string
+	Filters ReleaseQueryFilters
+	Search  string
+}
+
+// ReleaseQueryFilters narrow down the release history, empty fields don't filter
+type ReleaseQueryFilters struct {
+	Indexers   []string
+	Filters    []string // filter names
+	PushStatus string
+	From       time.Time
+	To         time.Time
+	MinSize    uint64
+	MaxSize    uint64
+}
+
+// SearchTerms splits a search into the words of release names, "That.Movie 2022" searches for that, movie and 2022
+func SearchTerms(search string) []string {
+	return strings.FieldsFunc(strings.ToLower(search), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+type ReleaseEventType string
+
+const (
+	ReleaseEventAnnounce ReleaseEventType = "ANNOUNCE"
+	ReleaseEventMatch    ReleaseEventType = "MATCH"
+	ReleaseEventAction   ReleaseEventType = "ACTION"
+	ReleaseEventError    ReleaseEventType = "ERROR"
+	ReleaseEventBlocked  ReleaseEventType = "BLOCKED"
+)
+
+// ReleaseEvent is a step of a release through the pipeline, kept in memory for debugging
+type ReleaseEvent struct {
+	Timestamp   time.Time        `json:"timestamp"`
+	Type        ReleaseEventType `json:"type"`
+	Indexer     string           `json:"indexer"`
+	TorrentName string           `json:"torrent_name"`
+	Filter      string           `json:"filter,omitempty"`
+	Action      string           `json:"action,omitempty"`
+	Message     string           `json:"message,omitempty"`
+}
+
+type ReleaseEventQueryParams struct {
+	Types    []string
+	Indexers []string
+	Limit    int
+	// Since only returns events newer than the time, used to follow the events
+	Since time.Time
+}
+
+// ErrReplayNotActive is returned when actions are replayed on a read-only or standby instance
+var ErrReplayNotActive = errors.New("actions are not run on this instance, it is read-only or standby")
+
+type ReleaseReplayRequest struct {
+	FilterID   int     `json:"filter_id"`
+	ReleaseIDs []int64 `json:"release_ids"`
+	RunActions bool    `json:"run_actions"`
+}
+
+func (r ReleaseReplayRequest) Validate() error {
+	if r.FilterID == 0 {
+		return errors.New("validation: filter_id is required")
 	}
-	Search string
+	if len(r.ReleaseIDs) == 0 {
+		return errors.New("validation: release_ids can't be empty")
+	}
+
+	return nil
+}
+
+type ReleaseReplayResult struct {
+	ReleaseID     int64    `json:"release_id"`
+	TorrentName   string   `json:"torrent_name"`
+	Indexer       string   `json:"indexer"`
+	Match         bool     `json:"match"`
+	Rejections    []string `json:"rejections"`
+	Skipped       []string `json:"skipped"`
+	ActionsQueued bool     `json:"actions_queued"`
+	Error         string   `json:"error,omitempty"`
+}
+
+// Replay returns a fresh copy of a stored release to run through the filters again.
+// Only what is stored is known, so values like freeleech from the announce are lost.
+func (r *Release) Replay() *Release {
+	rls := NewRelease(r.Indexer)
+	rls.ParseString(r.TorrentName)
+
+	rls.Protocol = r.Protocol
+	rls.Implementation = r.Implementation
+	rls.GroupID = r.GroupID
+	rls.TorrentID = r.TorrentID
+	rls.TorrentURL = r.TorrentURL
+	rls.Size = r.Size
+	rls.Category = r.Category
+	rls.Uploader = r.Uploader
+	rls.PreTime = r.PreTime
+	if len(r.Tags) > 0 {
+		rls.Tags = r.Tags
+	}
+
+	return rls
 }
 
 func NewRelease(indexer string) *Release {
@@ -281,7 +497,12 @@ func (r *Release) DownloadTorrentFile() error {
 		return errors.Wrap(err, "could not create cookiejar")
 	}
 
-	customTransport := http.DefaultTransport.(*http.Transport).Clone()
+	transport, err := sharedhttp.Transport(r.Proxy)
+	if err != nil {
+		return errors.Wrap(err, "could not create transport")
+	}
+
+	customTransport := transport.Clone()
 	customTransport.TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
 	client := &http.Client{
 		Transport: customTransport,
@@ -325,25 +546,74 @@ func (r *Release) DownloadTorrentFile() error {
 		return errors.Wrap(err, "error writing downloaded file: %v", tmpFile.Name())
 	}
 
-	meta, err := metainfo.LoadFromFile(tmpFile.Name())
+	if err := r.parseTorrentFile(tmpFile.Name()); err != nil {
+		return err
+	}
+
+	r.TorrentTmpFile = tmpFile.Name()
+
+	// remove file if fail
+
+	return nil
+}
+
+// ParseTorrentFile sets the info hashes, size and files of the release from the downloaded TorrentTmpFile
+func (r *Release) ParseTorrentFile() error {
+	if r.TorrentTmpFile == "" {
+		return errors.New("torrent file not downloaded")
+	}
+
+	return r.parseTorrentFile(r.TorrentTmpFile)
+}
+
+func (r *Release) parseTorrentFile(path string) error {
+	meta, err := metainfo.LoadFromFile(path)
 	if err != nil {
-		return errors.Wrap(err, "metainfo could not load file contents: %v", tmpFile.Name())
+		return errors.Wrap(err, "metainfo could not load file contents: %v", path)
 	}
 
 	torrentMetaInfo, err := meta.UnmarshalInfo()
 	if err != nil {
-		return errors.Wrap(err, "metainfo could not unmarshal info from torrent: %v", tmpFile.Name())
+		return errors.Wrap(err, "metainfo could not unmarshal info from torrent: %v", path)
 	}
 
-	r.TorrentTmpFile = tmpFile.Name()
-	r.TorrentHash = meta.HashInfoBytes().String()
+	hashes, err := NewInfoHashes(meta)
+	if err != nil {
+		return errors.Wrap(err, "could not compute info hashes from torrent: %v", path)
+	}
+
+	r.TorrentHash = hashes.Primary()
+	r.InfoHashV1 = hashes.V1
+	r.InfoHashV2 = hashes.V2
 	r.Size = uint64(torrentMetaInfo.TotalLength())
 
-	// remove file if fail
+	files := torrentMetaInfo.UpvertedFiles()
+	r.Files = make([]ReleaseFile, 0, len(files))
+	for _, f := range files {
+		r.Files = append(r.Files, ReleaseFile{Path: f.DisplayPath(&torrentMetaInfo), Size: f.Length})
+	}
 
 	return nil
 }
 
+// InfoHashes returns the known info hashes of the release torrent
+func (r *Release) InfoHashes() InfoHashes {
+	hashes := InfoHashes{V1: r.InfoHashV1, V2: r.InfoHashV2}
+
+	// hash might only be known from the announce or api
+	if hashes.V1 == "" && hashes.V2 == "" && r.TorrentHash != "" {
+		if hash, err := NormalizeInfoHash(r.TorrentHash); err == nil {
+			if len(hash) == 64 {
+				hashes.V2 = hash
+			} else {
+				hashes.V1 = hash
+			}
+		}
+	}
+
+	return hashes
+}
+
 func (r *Release) addRejection(reason string) {
 	r.Rejections = append(r.Rejections, reason)
 }
@@ -368,6 +638,59 @@ func (r *Release) RejectionsString() string {
 	return ""
 }
 
+// music release types and the variant spellings announced by trackers
+var releaseTypeVariants = map[string][]string{
+	"Album":             {"album", "lp", "full length", "fulllength"},
+	"Single":            {"single"},
+	"EP":                {"ep", "e.p.", "e.p"},
+	"Soundtrack":        {"soundtrack", "ost", "score"},
+	"Anthology":         {"anthology", "box set", "boxset"},
+	"Compilation":       {"compilation", "comp", "various artists", "va"},
+	"Live album":        {"live album", "livealbum", "live"},
+	"Remix":             {"remix", "remixes"},
+	"Bootleg":           {"bootleg"},
+	"Interview":         {"interview"},
+	"Mixtape":           {"mixtape", "mix tape", "mixtape/street"},
+	"Demo":              {"demo"},
+	"Concert Recording": {"concert recording", "concert"},
+	"DJ Mix":            {"dj mix", "djmix", "dj-mix"},
+	"Unknown":           {"unknown"},
+}
+
+// NormalizeReleaseType maps variant spellings like "E.P.", "[Single]" or "dj-mix"
+// to the release type names used in filters. Unknown types are returned as is.
+func NormalizeReleaseType(releaseType string) string {
+	releaseType = strings.TrimSpace(releaseType)
+	if releaseType == "" {
+		return ""
+	}
+
+	s := strings.ToLower(strings.Trim(releaseType, "[]() "))
+	s = strings.Join(strings.FieldsFunc(s, func(r rune) bool {
+		return r == ' ' || r == '_'
+	}), " ")
+
+	for name, variants := range releaseTypeVariants {
+		for _, v := range variants {
+			if s == v {
+				return name
+			}
+		}
+	}
+
+	return releaseType
+}
+
+// ReleaseType returns the music release type. Falls back to category
+// for indexers that announce the release type as the category.
+func (r *Release) ReleaseType() string {
+	if r.Type != "" {
+		return r.Type
+	}
+
+	return NormalizeReleaseType(r.Category)
+}
+
 // MapVars better name
 func (r *Release) MapVars(def *IndexerDefinition, varMap map[string]string) error {
 
@@ -385,6 +708,10 @@ func (r *Release) MapVars(def *IndexerDefinition, varMap map[string]string) erro
 		r.Category = category
 	}
 
+	if releaseType, err := getStringMapValue(varMap, "releaseType"); err == nil {
+		r.Type = NormalizeReleaseType(releaseType)
+	}
+
 	if freeleech, err := getStringMapValue(varMap, "freeleech"); err == nil {
 		fl := StringEqualFoldMulti(freeleech, "freeleech", "yes", "1", "VIP")
 		if fl {
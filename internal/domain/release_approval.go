@@ -0,0 +1,128 @@
+package domain
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"github.com/autobrr/autobrr/pkg/errors"
+)
+
+type ReleaseApprovalRepo interface {
+	Store(ctx context.Context, approval *ReleaseApproval) error
+	// Update stores the decision of an approval that is still pending, it reports false when it was decided or expired meanwhile
+	Update(ctx context.Context, approval *ReleaseApproval) (bool, error)
+	FindByID(ctx context.Context, id int) (*ReleaseApproval, error)
+	List(ctx context.Context, status ReleaseApprovalStatus) ([]*ReleaseApproval, error)
+	// Expire marks the pending approvals past their expiry as expired
+	Expire(ctx context.Context, now time.Time) (int64, error)
+}
+
+type ReleaseApprovalStatus string
+
+const (
+	// ReleaseApprovalStatusPending waits for a decision, the actions have not run
+	ReleaseApprovalStatusPending ReleaseApprovalStatus = "PENDING"
+	// ReleaseApprovalStatusApproved ran the actions of the filter
+	ReleaseApprovalStatusApproved ReleaseApprovalStatus = "APPROVED"
+	// ReleaseApprovalStatusRejected was declined, the actions never run
+	ReleaseApprovalStatusRejected ReleaseApprovalStatus = "REJECTED"
+	// ReleaseApprovalStatusExpired was not decided on before it expired
+	ReleaseApprovalStatusExpired ReleaseApprovalStatus = "EXPIRED"
+)
+
+type ReleaseApprovalDecision string
+
+const (
+	ReleaseApprovalDecisionApprove ReleaseApprovalDecision = "approve"
+	ReleaseApprovalDecisionReject  ReleaseApprovalDecision = "reject"
+)
+
+func (d ReleaseApprovalDecision) Valid() bool {
+	return d == ReleaseApprovalDecisionApprove || d == ReleaseApprovalDecisionReject
+}
+
+// ReleaseApproval is a release matched by a filter that requires approval before its actions run
+type ReleaseApproval struct {
+	ID          int                   `json:"id"`
+	ReleaseID   int64                 `json:"release_id"`
+	FilterID    int                   `json:"filter_id"`
+	TorrentName string                `json:"torrent_name"`
+	Indexer     string                `json:"indexer"`
+	Filter      string                `json:"filter"`
+	Size        uint64                `json:"size"`
+	Status      ReleaseApprovalStatus `json:"status"`
+	DecidedBy   string                `json:"decided_by"`
+	DecidedAt   *time.Time            `json:"decided_at"`
+	ExpiresAt   time.Time             `json:"expires_at"`
+	CreatedAt   time.Time             `json:"created_at"`
+}
+
+// NewReleaseApproval holds the stored release for approval until the window has passed
+func NewReleaseApproval(release *Release, window time.Duration, now time.Time) *ReleaseApproval {
+	return &ReleaseApproval{
+		ReleaseID:   release.ID,
+		FilterID:    release.FilterID,
+		TorrentName: release.TorrentName,
+		Indexer:     release.Indexer,
+		Filter:      release.FilterName,
+		Size:        release.Size,
+		Status:      ReleaseApprovalStatusPending,
+		ExpiresAt:   now.Add(window),
+		CreatedAt:   now,
+	}
+}
+
+// Decide approves or rejects the release, only pending approvals that have not expired can be decided
+func (a *ReleaseApproval) Decide(decision ReleaseApprovalDecision, actor string, now time.Time) error {
+	if !decision.Valid() {
+		return errors.New("invalid decision: %q", decision)
+	}
+
+	if a.Status != ReleaseApprovalStatusPending {
+		return errors.New("release approval %v is already %v", a.ID, a.Status)
+	}
+
+	if now.After(a.ExpiresAt) {
+		a.Status = ReleaseApprovalStatusExpired
+		return errors.New("release approval %v expired at %v", a.ID, a.ExpiresAt.Format(time.RFC3339))
+	}
+
+	a.Status = ReleaseApprovalStatusApproved
+	if decision == ReleaseApprovalDecisionReject {
+		a.Status = ReleaseApprovalStatusRejected
+	}
+
+	a.DecidedBy = actor
+	a.DecidedAt = &now
+
+	return nil
+}
+
+// approvalTokenKey derives the key for approval tokens from the session secret,
+// so the tokens and the session cookies are never signed with the same key
+func approvalTokenKey(secret string) []byte {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte("approval"))
+
+	return mac.Sum(nil)
+}
+
+// Token signs the decision for this approval so it can be made from a link without logging in.
+// The token is only valid for this approval, decision and expiry.
+func (a *ReleaseApproval) Token(secret string, decision ReleaseApprovalDecision) string {
+	mac := hmac.New(sha256.New, approvalTokenKey(secret))
+	mac.Write([]byte(fmt.Sprintf("release-approval:%d:%s:%d", a.ID, decision, a.ExpiresAt.Unix())))
+
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// VerifyToken reports whether the token was signed for this approval and decision
+func (a *ReleaseApproval) VerifyToken(secret string, decision ReleaseApprovalDecision, token string) bool {
+	want := a.Token(secret, decision)
+
+	return hmac.Equal([]byte(want), []byte(token))
+}
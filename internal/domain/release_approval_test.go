@@ -0,0 +1,75 @@
+package domain
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReleaseApproval_Decide(t *testing.T) {
+	now := time.Date(2022, 10, 1, 12, 0, 0, 0, time.UTC)
+
+	release := &Release{ID: 1, FilterID: 2, FilterName: "movies", TorrentName: "That.Movie.2022.1080p.BluRay.x264-GROUP", Indexer: "mock", Size: 1024}
+
+	tests := []struct {
+		name       string
+		decision   ReleaseApprovalDecision
+		at         time.Time
+		wantStatus ReleaseApprovalStatus
+		wantErr    bool
+	}{
+		{name: "approve", decision: ReleaseApprovalDecisionApprove, at: now.Add(time.Hour), wantStatus: ReleaseApprovalStatusApproved},
+		{name: "reject", decision: ReleaseApprovalDecisionReject, at: now.Add(time.Hour), wantStatus: ReleaseApprovalStatusRejected},
+		{name: "expired", decision: ReleaseApprovalDecisionApprove, at: now.Add(25 * time.Hour), wantStatus: ReleaseApprovalStatusExpired, wantErr: true},
+		{name: "invalid_decision", decision: "maybe", at: now.Add(time.Hour), wantStatus: ReleaseApprovalStatusPending, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			approval := NewReleaseApproval(release, 24*time.Hour, now)
+
+			err := approval.Decide(tt.decision, "user", tt.at)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, "user", approval.DecidedBy)
+				assert.Equal(t, tt.at, *approval.DecidedAt)
+			}
+
+			assert.Equal(t, tt.wantStatus, approval.Status)
+		})
+	}
+
+	t.Run("decided_once", func(t *testing.T) {
+		approval := NewReleaseApproval(release, 24*time.Hour, now)
+
+		assert.NoError(t, approval.Decide(ReleaseApprovalDecisionReject, "user", now))
+		assert.Error(t, approval.Decide(ReleaseApprovalDecisionApprove, "user", now))
+		assert.Equal(t, ReleaseApprovalStatusRejected, approval.Status)
+	})
+}
+
+func TestReleaseApproval_VerifyToken(t *testing.T) {
+	now := time.Date(2022, 10, 1, 12, 0, 0, 0, time.UTC)
+
+	approval := &ReleaseApproval{ID: 7, ExpiresAt: now.Add(24 * time.Hour)}
+	token := approval.Token("secret", ReleaseApprovalDecisionApprove)
+
+	assert.True(t, approval.VerifyToken("secret", ReleaseApprovalDecisionApprove, token))
+	assert.False(t, approval.VerifyToken("secret", ReleaseApprovalDecisionReject, token))
+	assert.False(t, approval.VerifyToken("other-secret", ReleaseApprovalDecisionApprove, token))
+	assert.False(t, approval.VerifyToken("secret", ReleaseApprovalDecisionApprove, ""))
+
+	other := &ReleaseApproval{ID: 8, ExpiresAt: approval.ExpiresAt}
+	assert.False(t, other.VerifyToken("secret", ReleaseApprovalDecisionApprove, token))
+
+	// the token is not signed with the session secret itself
+	mac := hmac.New(sha256.New, []byte("secret"))
+	mac.Write([]byte(fmt.Sprintf("release-approval:%d:%s:%d", approval.ID, ReleaseApprovalDecisionApprove, approval.ExpiresAt.Unix())))
+	assert.False(t, approval.VerifyToken("secret", ReleaseApprovalDecisionApprove, hex.EncodeToString(mac.Sum(nil))))
+}
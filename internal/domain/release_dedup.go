@@ -0,0 +1,67 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// ReleaseDedup is the first release approved for a dedup key within the window
+type ReleaseDedup struct {
+	Key         string
+	Indexer     string
+	TorrentName string
+	CreatedAt   time.Time
+}
+
+// SameRelease is true for the same announce, eg. matched by another filter
+func (d ReleaseDedup) SameRelease(release *Release) bool {
+	return d.Indexer == release.Indexer && d.TorrentName == release.TorrentName
+}
+
+// DedupKey returns the key to find the same release announced on other indexers, empty if it can't be built.
+// The title key is the normalized title with year, season and episode so different episodes never collide.
+func (r *Release) DedupKey(key FilterDedupKey) string {
+	title := r.Title
+	if title == "" {
+		title = r.TorrentName
+	}
+
+	parts := []string{normalizeDedupValue(title)}
+	if parts[0] == "" {
+		return ""
+	}
+
+	if r.Year > 0 {
+		parts = append(parts, fmt.Sprintf("%d", r.Year))
+	}
+
+	if r.Season > 0 && r.Episode > 0 {
+		parts = append(parts, fmt.Sprintf("s%02de%02d", r.Season, r.Episode))
+	} else if r.Season > 0 {
+		parts = append(parts, fmt.Sprintf("s%02d", r.Season))
+	} else if r.Episode > 0 {
+		parts = append(parts, fmt.Sprintf("e%02d", r.Episode))
+	}
+
+	switch key {
+	case FilterDedupKeyTitle:
+	case FilterDedupKeyTitleGroup:
+		parts = append(parts, normalizeDedupValue(r.Group))
+	case FilterDedupKeyTitleResolutionSource:
+		parts = append(parts, normalizeDedupValue(r.Resolution), normalizeDedupValue(r.Source))
+	default:
+		return ""
+	}
+
+	return strings.Join(parts, "|")
+}
+
+// normalizeDedupValue lowercases and keeps only letters and digits separated by single spaces,
+// "The.Show" and "The Show" are the same.
+func normalizeDedupValue(value string) string {
+	return strings.Join(strings.FieldsFunc(strings.ToLower(value), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}), " ")
+}
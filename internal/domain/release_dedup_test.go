@@ -0,0 +1,33 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRelease_DedupKey(t *testing.T) {
+	episode := &Release{TorrentName: "The.Show.S01E02.1080p.WEB-DL.H.264-GRP", Title: "The Show", Season: 1, Episode: 2, Resolution: "1080p", Source: "WEB-DL", Group: "GRP"}
+
+	tests := []struct {
+		name    string
+		release *Release
+		key     FilterDedupKey
+		want    string
+	}{
+		{name: "title", release: episode, key: FilterDedupKeyTitle, want: "the show|s01e02"},
+		{name: "title_group", release: episode, key: FilterDedupKeyTitleGroup, want: "the show|s01e02|grp"},
+		{name: "title_resolution_source", release: episode, key: FilterDedupKeyTitleResolutionSource, want: "the show|s01e02|1080p|web dl"},
+		{name: "movie", release: &Release{Title: "That Movie", Year: 2022}, key: FilterDedupKeyTitle, want: "that movie|2022"},
+		{name: "season_pack", release: &Release{Title: "The Show", Season: 3}, key: FilterDedupKeyTitle, want: "the show|s03"},
+		{name: "no_parsed_title", release: &Release{TorrentName: "Artist - Album (2022) [FLAC]"}, key: FilterDedupKeyTitle, want: "artist album 2022 flac"},
+		{name: "no_title", release: &Release{TorrentName: "..."}, key: FilterDedupKeyTitle, want: ""},
+		{name: "disabled", release: episode, key: FilterDedupKeyDisabled, want: ""},
+		{name: "default", release: episode, key: FilterDedupKeyDefault, want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.release.DedupKey(tt.key))
+		})
+	}
+}
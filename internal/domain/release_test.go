@@ -488,6 +488,38 @@ func TestRelease_MapVars(t *testing.T) {
 				},
 			},
 		},
+		{
+			name:   "12",
+			fields: &Release{},
+			want: &Release{
+				TorrentName: "Artist - Albumname",
+				Category:    "Music",
+				Type:        "EP",
+				Year:        2022,
+			},
+			args: args{
+				varMap: map[string]string{
+					"torrentName": "Artist - Albumname",
+					"category":    "Music",
+					"releaseType": "E.P.",
+					"year":        "2022",
+				},
+			},
+		},
+		{
+			name:   "13",
+			fields: &Release{},
+			want: &Release{
+				TorrentName: "Artist - Albumname",
+				Type:        "Single",
+			},
+			args: args{
+				varMap: map[string]string{
+					"torrentName": "Artist - Albumname",
+					"releaseType": "[Single]",
+				},
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -499,6 +531,46 @@ func TestRelease_MapVars(t *testing.T) {
 	}
 }
 
+func TestNormalizeReleaseType(t *testing.T) {
+	tests := []struct {
+		releaseType string
+		want        string
+	}{
+		{releaseType: "Album", want: "Album"},
+		{releaseType: "album", want: "Album"},
+		{releaseType: "[Album]", want: "Album"},
+		{releaseType: "LP", want: "Album"},
+		{releaseType: "Single", want: "Single"},
+		{releaseType: "(single)", want: "Single"},
+		{releaseType: "EP", want: "EP"},
+		{releaseType: "E.P.", want: "EP"},
+		{releaseType: "ep", want: "EP"},
+		{releaseType: "Compilation", want: "Compilation"},
+		{releaseType: "VA", want: "Compilation"},
+		{releaseType: "OST", want: "Soundtrack"},
+		{releaseType: "Live Album", want: "Live album"},
+		{releaseType: "live_album", want: "Live album"},
+		{releaseType: "DJ-Mix", want: "DJ Mix"},
+		{releaseType: "dj  mix", want: "DJ Mix"},
+		{releaseType: "Mix Tape", want: "Mixtape"},
+		{releaseType: "Concert Recording", want: "Concert Recording"},
+		{releaseType: " Remix ", want: "Remix"},
+		{releaseType: "Something else", want: "Something else"},
+		{releaseType: "", want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.releaseType, func(t *testing.T) {
+			assert.Equal(t, tt.want, NormalizeReleaseType(tt.releaseType))
+		})
+	}
+}
+
+func TestRelease_ReleaseType(t *testing.T) {
+	assert.Equal(t, "EP", (&Release{Type: "EP", Category: "Album"}).ReleaseType())
+	assert.Equal(t, "Album", (&Release{Category: "[Album]"}).ReleaseType())
+	assert.Equal(t, "", (&Release{}).ReleaseType())
+}
+
 func TestSplitAny(t *testing.T) {
 	type args struct {
 		s    string
@@ -630,3 +702,89 @@ func TestRelease_ParseString(t *testing.T) {
 		})
 	}
 }
+
+func TestReleaseWebhookPayload_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		payload ReleaseWebhookPayload
+		wantErr string
+	}{
+		{
+			name:    "ok",
+			payload: ReleaseWebhookPayload{Name: "That.Movie.2022.1080p.BluRay.x264-GROUP", DownloadURL: "https://example.com/dl/1", Indexer: "mock", Size: "1.5 GB"},
+		},
+		{
+			name:    "missing_name",
+			payload: ReleaseWebhookPayload{DownloadURL: "https://example.com/dl/1", Indexer: "mock"},
+			wantErr: "name is required",
+		},
+		{
+			name:    "missing_indexer",
+			payload: ReleaseWebhookPayload{Name: "That.Movie.2022.1080p.BluRay.x264-GROUP", DownloadURL: "https://example.com/dl/1"},
+			wantErr: "indexer is required",
+		},
+		{
+			name:    "bad_url",
+			payload: ReleaseWebhookPayload{Name: "That.Movie.2022.1080p.BluRay.x264-GROUP", DownloadURL: "ftp://example.com/dl/1", Indexer: "mock"},
+			wantErr: "download_url must be a valid http(s) url",
+		},
+		{
+			name:    "bad_size",
+			payload: ReleaseWebhookPayload{Name: "That.Movie.2022.1080p.BluRay.x264-GROUP", DownloadURL: "https://example.com/dl/1", Indexer: "mock", Size: "large"},
+			wantErr: "size is invalid: large",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.payload.Validate()
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.EqualError(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestReleaseWebhookPayload_Release(t *testing.T) {
+	payload := ReleaseWebhookPayload{
+		Name:        "That.Movie.2022.1080p.BluRay.x264-GROUP",
+		DownloadURL: "https://example.com/dl/1",
+		Indexer:     "mock",
+		Size:        "1073741824",
+		Tags:        []string{"action"},
+		Freeleech:   true,
+	}
+
+	r := payload.Release()
+
+	assert.Equal(t, "mock", r.Indexer)
+	assert.Equal(t, ReleaseImplementationWebhook, r.Implementation)
+	assert.Equal(t, "https://example.com/dl/1", r.TorrentURL)
+	assert.Equal(t, "That.Movie.2022.1080p.BluRay.x264-GROUP", r.TorrentName)
+	assert.Equal(t, "That Movie", r.Title)
+	assert.Equal(t, 2022, r.Year)
+	assert.Equal(t, "GROUP", r.Group)
+	assert.Equal(t, uint64(1073741824), r.Size)
+	assert.Equal(t, []string{"action"}, r.Tags)
+	assert.True(t, r.Freeleech)
+	assert.Equal(t, []string{"Freeleech"}, r.Bonus)
+}
+
+func TestSearchTerms(t *testing.T) {
+	tests := []struct {
+		name   string
+		search string
+		want   []string
+	}{
+		{name: "empty", search: "", want: []string{}},
+		{name: "words", search: "That Movie", want: []string{"that", "movie"}},
+		{name: "release_name", search: "That.Movie.2022.1080p-GROUP", want: []string{"that", "movie", "2022", "1080p", "group"}},
+		{name: "operators", search: "\"movie\" OR *group:", want: []string{"movie", "or", "group"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, SearchTerms(tt.search))
+		})
+	}
+}
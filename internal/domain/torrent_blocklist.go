@@ -0,0 +1,87 @@
+package domain
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/autobrr/autobrr/pkg/errors"
+)
+
+type TorrentBlocklistRepo interface {
+	List(ctx context.Context) ([]TorrentBlocklistEntry, error)
+	Store(ctx context.Context, entry *TorrentBlocklistEntry) error
+	Delete(ctx context.Context, id int) error
+}
+
+type TorrentBlocklistType string
+
+const (
+	// TorrentBlocklistTypeInfoHash blocks a single torrent by its v1, v2 or truncated v2 info hash
+	TorrentBlocklistTypeInfoHash TorrentBlocklistType = "INFOHASH"
+	// TorrentBlocklistTypeGroup blocks every release of a release group
+	TorrentBlocklistTypeGroup TorrentBlocklistType = "GROUP"
+)
+
+// TorrentBlocklistEntry rejects matching torrents after they are downloaded and before they are sent to a client
+type TorrentBlocklistEntry struct {
+	ID        int                  `json:"id"`
+	Type      TorrentBlocklistType `json:"type"`
+	Value     string               `json:"value"`
+	Reason    string               `json:"reason"`
+	CreatedAt time.Time            `json:"created_at"`
+}
+
+// Validate checks the entry and normalizes info hashes to lowercase hex
+func (e *TorrentBlocklistEntry) Validate() error {
+	e.Value = strings.TrimSpace(e.Value)
+	if e.Value == "" {
+		return errors.New("validation: value is required")
+	}
+
+	switch e.Type {
+	case TorrentBlocklistTypeInfoHash:
+		hash, err := NormalizeInfoHash(e.Value)
+		if err != nil {
+			return errors.Wrap(err, "validation: invalid info hash")
+		}
+		e.Value = hash
+
+	case TorrentBlocklistTypeGroup:
+
+	default:
+		return errors.New("validation: unsupported blocklist type: %q", e.Type)
+	}
+
+	return nil
+}
+
+// Matches reports whether the release is blocked by the entry, groups are compared case-insensitive
+func (e TorrentBlocklistEntry) Matches(release *Release) bool {
+	switch e.Type {
+	case TorrentBlocklistTypeInfoHash:
+		return release.InfoHashes().Matches(e.Value)
+	case TorrentBlocklistTypeGroup:
+		return release.Group != "" && strings.EqualFold(release.Group, e.Value)
+	}
+
+	return false
+}
+
+// Rejection returns the reason shown when the entry rejects a release
+func (e TorrentBlocklistEntry) Rejection() string {
+	var reason string
+	switch e.Type {
+	case TorrentBlocklistTypeInfoHash:
+		reason = fmt.Sprintf("info hash blocklisted: %s", e.Value)
+	default:
+		reason = fmt.Sprintf("group blocklisted: %s", e.Value)
+	}
+
+	if e.Reason != "" {
+		reason += " (" + e.Reason + ")"
+	}
+
+	return reason
+}
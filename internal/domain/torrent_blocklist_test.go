@@ -0,0 +1,53 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTorrentBlocklistEntry_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		entry   TorrentBlocklistEntry
+		want    string
+		wantErr bool
+	}{
+		{name: "infohash", entry: TorrentBlocklistEntry{Type: TorrentBlocklistTypeInfoHash, Value: " 0123456789ABCDEF0123456789ABCDEF01234567 "}, want: "0123456789abcdef0123456789abcdef01234567"},
+		{name: "infohash_invalid", entry: TorrentBlocklistEntry{Type: TorrentBlocklistTypeInfoHash, Value: "nothex"}, wantErr: true},
+		{name: "group", entry: TorrentBlocklistEntry{Type: TorrentBlocklistTypeGroup, Value: " GRP "}, want: "GRP"},
+		{name: "empty", entry: TorrentBlocklistEntry{Type: TorrentBlocklistTypeGroup, Value: " "}, wantErr: true},
+		{name: "unsupported_type", entry: TorrentBlocklistEntry{Type: "TITLE", Value: "x"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.entry.Validate()
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, tt.entry.Value)
+		})
+	}
+}
+
+func TestTorrentBlocklistEntry_Matches(t *testing.T) {
+	release := &Release{Group: "GRP", InfoHashV1: "0123456789abcdef0123456789abcdef01234567"}
+
+	tests := []struct {
+		name  string
+		entry TorrentBlocklistEntry
+		want  bool
+	}{
+		{name: "infohash", entry: TorrentBlocklistEntry{Type: TorrentBlocklistTypeInfoHash, Value: "0123456789abcdef0123456789abcdef01234567"}, want: true},
+		{name: "infohash_other", entry: TorrentBlocklistEntry{Type: TorrentBlocklistTypeInfoHash, Value: "fedcba9876543210fedcba9876543210fedcba98"}, want: false},
+		{name: "group", entry: TorrentBlocklistEntry{Type: TorrentBlocklistTypeGroup, Value: "grp"}, want: true},
+		{name: "group_other", entry: TorrentBlocklistEntry{Type: TorrentBlocklistTypeGroup, Value: "OTHER"}, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.entry.Matches(release))
+		})
+	}
+}
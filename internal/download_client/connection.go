@@ -7,6 +7,7 @@ import (
 	"github.com/autobrr/autobrr/internal/domain"
 	"github.com/autobrr/autobrr/pkg/errors"
 	"github.com/autobrr/autobrr/pkg/lidarr"
+	"github.com/autobrr/autobrr/pkg/porla"
 	"github.com/autobrr/autobrr/pkg/qbittorrent"
 	"github.com/autobrr/autobrr/pkg/radarr"
 	"github.com/autobrr/autobrr/pkg/sonarr"
@@ -31,6 +32,9 @@ func (s *service) testConnection(client domain.DownloadClient) error {
 	case domain.DownloadClientTypeTransmission:
 		return s.testTransmissionConnection(client)
 
+	case domain.DownloadClientTypePorla:
+		return s.testPorlaConnection(client)
+
 	case domain.DownloadClientTypeRadarr:
 		return s.testRadarrConnection(client)
 
@@ -163,6 +167,25 @@ func (s *service) testTransmissionConnection(client domain.DownloadClient) error
 	return nil
 }
 
+func (s *service) testPorlaConnection(client domain.DownloadClient) error {
+	p := porla.New(porla.Config{
+		Hostname:      client.Host,
+		AuthToken:     client.Settings.APIKey,
+		TLSSkipVerify: client.TLSSkipVerify,
+	})
+
+	versions, err := p.SysVersions()
+	if err != nil {
+		return errors.Wrap(err, "porla: failed to get version: %v", client.Host)
+	}
+
+	s.log.Debug().Msgf("test client connection for Porla: got version: %v (%v)", versions.Porla.Version, versions.Porla.Commitish)
+
+	s.log.Debug().Msgf("test client connection for Porla: success")
+
+	return nil
+}
+
 func (s *service) testRadarrConnection(client domain.DownloadClient) error {
 	r := radarr.New(radarr.Config{
 		Hostname:  client.Host,
@@ -242,3 +265,61 @@ func (s *service) testWhisparrConnection(client domain.DownloadClient) error {
 
 	return nil
 }
+
+// LargestActiveDownload returns the bytes left of the largest active download across enabled clients.
+// Only qBittorrent reports this for now.
+func (s *service) LargestActiveDownload(ctx context.Context) (int64, error) {
+	clients, err := s.repo.List(ctx)
+	if err != nil {
+		return 0, errors.Wrap(err, "could not list download clients")
+	}
+
+	var largest int64
+
+	for _, client := range clients {
+		if !client.Enabled || client.Type != domain.DownloadClientTypeQbittorrent {
+			continue
+		}
+
+		qbtSettings := qbittorrent.Settings{
+			Hostname:      client.Host,
+			Port:          uint(client.Port),
+			Username:      client.Username,
+			Password:      client.Password,
+			TLS:           client.TLS,
+			TLSSkipVerify: client.TLSSkipVerify,
+			Log:           s.subLogger,
+		}
+
+		// only set basic auth if enabled
+		if client.Settings.Basic.Auth {
+			qbtSettings.BasicAuth = client.Settings.Basic.Auth
+			qbtSettings.Basic.Username = client.Settings.Basic.Username
+			qbtSettings.Basic.Password = client.Settings.Basic.Password
+		}
+
+		qbt := qbittorrent.NewClient(qbtSettings)
+
+		if err := qbt.Login(); err != nil {
+			return 0, errors.Wrap(err, "error logging into client: %v", client.Host)
+		}
+
+		torrents, err := qbt.GetTorrentsActiveDownloads()
+		if err != nil {
+			return 0, errors.Wrap(err, "could not get active downloads: %v", client.Host)
+		}
+
+		for _, torrent := range torrents {
+			// stalled downloads are not using any bandwidth
+			if torrent.State != qbittorrent.TorrentStateDownloading {
+				continue
+			}
+
+			if int64(torrent.AmountLeft) > largest {
+				largest = int64(torrent.AmountLeft)
+			}
+		}
+	}
+
+	return largest, nil
+}
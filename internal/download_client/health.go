@@ -0,0 +1,92 @@
+package download_client
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/autobrr/autobrr/internal/domain"
+
+	"github.com/robfig/cron/v3"
+)
+
+const healthCheckInterval = 5 * time.Minute
+
+// Start schedules the periodic health check of the download clients
+func (s *service) Start() error {
+	job := cron.FuncJob(func() {
+		s.checkClientsHealth(context.Background())
+	})
+
+	if _, err := s.scheduler.AddJob(job, healthCheckInterval, "download-client-health"); err != nil {
+		s.log.Error().Err(err).Msg("could not add download client health check job")
+		return err
+	}
+
+	return nil
+}
+
+func (s *service) checkClientsHealth(ctx context.Context) {
+	clients, err := s.repo.List(ctx)
+	if err != nil {
+		s.log.Error().Err(err).Msg("could not list download clients for health check")
+		return
+	}
+
+	for _, client := range clients {
+		if !client.Enabled {
+			continue
+		}
+
+		s.CheckHealth(ctx, client)
+	}
+}
+
+// CheckHealth tests the connection to the client, stores the result and notifies when the client goes down or recovers
+func (s *service) CheckHealth(ctx context.Context, client domain.DownloadClient) domain.DownloadClientHealth {
+	now := time.Now()
+
+	health := domain.DownloadClientHealth{
+		Status:    domain.DownloadClientHealthOK,
+		CheckedAt: &now,
+	}
+
+	if err := s.testConnection(client); err != nil {
+		health.Status = domain.DownloadClientHealthDown
+		health.Error = err.Error()
+	}
+
+	if err := s.repo.UpdateHealth(ctx, client.ID, health); err != nil {
+		s.log.Error().Err(err).Msgf("could not store health for download client: %v", client.Name)
+	}
+
+	if health.Status == client.Health.Status {
+		return health
+	}
+
+	switch {
+	case health.Down():
+		s.log.Warn().Msgf("download client %v is down: %v", client.Name, health.Error)
+
+		s.notificationSvc.Send(domain.NotificationEventDownloadClientDown, domain.NotificationPayload{
+			Subject:      "Download client down",
+			Message:      fmt.Sprintf("Client: %v\nError: %v", client.Name, health.Error),
+			Event:        domain.NotificationEventDownloadClientDown,
+			ActionClient: client.Name,
+			Timestamp:    now,
+		})
+
+	case client.Health.Down():
+		s.log.Info().Msgf("download client %v recovered", client.Name)
+
+		s.notificationSvc.Send(domain.NotificationEventDownloadClientRecovered, domain.NotificationPayload{
+			Subject:      "Download client recovered",
+			Message:      fmt.Sprintf("Client: %v", client.Name),
+			Event:        domain.NotificationEventDownloadClientRecovered,
+			ActionClient: client.Name,
+			Timestamp:    now,
+		})
+	}
+
+	return health
+}
@@ -5,8 +5,11 @@ import (
 	"errors"
 	"log"
 
+	"github.com/autobrr/autobrr/internal/audit"
 	"github.com/autobrr/autobrr/internal/domain"
 	"github.com/autobrr/autobrr/internal/logger"
+	"github.com/autobrr/autobrr/internal/notification"
+	"github.com/autobrr/autobrr/internal/scheduler"
 
 	"github.com/dcarbone/zadapters/zstdlog"
 	"github.com/rs/zerolog"
@@ -19,18 +22,27 @@ type Service interface {
 	Update(ctx context.Context, client domain.DownloadClient) (*domain.DownloadClient, error)
 	Delete(ctx context.Context, clientID int) error
 	Test(client domain.DownloadClient) error
+	LargestActiveDownload(ctx context.Context) (int64, error)
+	CheckHealth(ctx context.Context, client domain.DownloadClient) domain.DownloadClientHealth
+	Start() error
 }
 
 type service struct {
-	log       zerolog.Logger
-	repo      domain.DownloadClientRepo
-	subLogger *log.Logger
+	log             zerolog.Logger
+	repo            domain.DownloadClientRepo
+	scheduler       scheduler.Service
+	notificationSvc notification.Service
+	auditSvc        audit.Service
+	subLogger       *log.Logger
 }
 
-func NewService(log logger.Logger, repo domain.DownloadClientRepo) Service {
+func NewService(log logger.Logger, repo domain.DownloadClientRepo, scheduler scheduler.Service, notificationSvc notification.Service, auditSvc audit.Service) Service {
 	s := &service{
-		log:  log.With().Str("module", "download_client").Logger(),
-		repo: repo,
+		log:             log.With().Str("module", "download_client").Logger(),
+		repo:            repo,
+		scheduler:       scheduler,
+		notificationSvc: notificationSvc,
+		auditSvc:        auditSvc,
 	}
 
 	s.subLogger = zstdlog.NewStdLoggerWithLevel(s.log.With().Logger(), zerolog.TraceLevel)
@@ -73,6 +85,8 @@ func (s *service) Store(ctx context.Context, client domain.DownloadClient) (*dom
 		return nil, err
 	}
 
+	s.auditSvc.Record(ctx, domain.AuditEntityDownloadClient, c.ID, c.Name, nil, c)
+
 	return c, err
 }
 
@@ -84,6 +98,12 @@ func (s *service) Update(ctx context.Context, client domain.DownloadClient) (*do
 		return nil, errors.New("validation error: no type")
 	}
 
+	before, err := s.repo.FindByID(ctx, int32(client.ID))
+	if err != nil {
+		s.log.Error().Err(err).Msgf("could not find download client: %v", client.ID)
+		return nil, err
+	}
+
 	// update
 	c, err := s.repo.Update(ctx, client)
 	if err != nil {
@@ -91,15 +111,25 @@ func (s *service) Update(ctx context.Context, client domain.DownloadClient) (*do
 		return nil, err
 	}
 
+	s.auditSvc.Record(ctx, domain.AuditEntityDownloadClient, c.ID, c.Name, before, c)
+
 	return c, err
 }
 
 func (s *service) Delete(ctx context.Context, clientID int) error {
-	err := s.repo.Delete(ctx, clientID)
+	before, err := s.repo.FindByID(ctx, int32(clientID))
 	if err != nil {
+		s.log.Error().Err(err).Msgf("could not find download client: %v", clientID)
+		return err
+	}
+
+	if err := s.repo.Delete(ctx, clientID); err != nil {
 		s.log.Error().Err(err).Msgf("could not delete download client: %v", clientID)
 		return err
 	}
+
+	s.auditSvc.Record(ctx, domain.AuditEntityDownloadClient, before.ID, before.Name, before, nil)
+
 	return nil
 }
 
@@ -0,0 +1,78 @@
+package enrichment
+
+import (
+	"context"
+	"sync"
+
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/internal/logger"
+	"github.com/autobrr/autobrr/pkg/errors"
+
+	"github.com/rs/zerolog"
+)
+
+// Enricher adds data to a release after it matched a filter and before the actions of that filter run
+type Enricher interface {
+	// Name identifies the enricher in logs and release events
+	Name() string
+	// Enabled reports whether the enricher should run for the release and the filter it matched, release.Filter is always set
+	Enabled(release *domain.Release) bool
+	Enrich(ctx context.Context, release *domain.Release) error
+}
+
+type Service interface {
+	Register(enricher Enricher)
+	Enrich(ctx context.Context, release *domain.Release) []error
+}
+
+type service struct {
+	log zerolog.Logger
+
+	m         sync.RWMutex
+	enrichers []Enricher
+}
+
+func NewService(log logger.Logger, enrichers ...Enricher) Service {
+	return &service{
+		log:       log.With().Str("module", "enrichment").Logger(),
+		enrichers: enrichers,
+	}
+}
+
+// Register adds an enricher, enrichers run in the order they are registered
+func (s *service) Register(enricher Enricher) {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	s.enrichers = append(s.enrichers, enricher)
+}
+
+// Enrich runs every enabled enricher on the release. A failing enricher does not stop the others
+// or the release, the errors are returned so the caller can report them.
+func (s *service) Enrich(ctx context.Context, release *domain.Release) []error {
+	if release == nil || release.Filter == nil {
+		return nil
+	}
+
+	s.m.RLock()
+	enrichers := make([]Enricher, len(s.enrichers))
+	copy(enrichers, s.enrichers)
+	s.m.RUnlock()
+
+	var errs []error
+	for _, e := range enrichers {
+		if !e.Enabled(release) {
+			continue
+		}
+
+		s.log.Trace().Msgf("enrichment: running %v for release: %v", e.Name(), release.TorrentName)
+
+		if err := e.Enrich(ctx, release); err != nil {
+			s.log.Warn().Err(err).Msgf("enrichment: %v failed for release: %v", e.Name(), release.TorrentName)
+			errs = append(errs, errors.Wrap(err, "enricher %v", e.Name()))
+			continue
+		}
+	}
+
+	return errs
+}
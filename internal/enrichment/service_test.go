@@ -0,0 +1,96 @@
+package enrichment
+
+import (
+	"context"
+	"testing"
+
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/internal/logger"
+	"github.com/autobrr/autobrr/pkg/errors"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type mockEnricher struct {
+	name    string
+	enabled bool
+	err     error
+	ran     *[]string
+}
+
+func (e *mockEnricher) Name() string {
+	return e.name
+}
+
+func (e *mockEnricher) Enabled(release *domain.Release) bool {
+	return e.enabled
+}
+
+func (e *mockEnricher) Enrich(ctx context.Context, release *domain.Release) error {
+	*e.ran = append(*e.ran, e.name)
+	return e.err
+}
+
+func Test_service_Enrich(t *testing.T) {
+	var ran []string
+
+	s := NewService(logger.Mock(), &mockEnricher{name: "first", enabled: true, err: errors.New("no size"), ran: &ran})
+	s.Register(&mockEnricher{name: "disabled", ran: &ran})
+	s.Register(&mockEnricher{name: "last", enabled: true, ran: &ran})
+
+	assert.Empty(t, s.Enrich(context.Background(), &domain.Release{TorrentName: "no filter"}))
+	assert.Empty(t, ran)
+
+	errs := s.Enrich(context.Background(), &domain.Release{TorrentName: "matched", Filter: &domain.Filter{}})
+	assert.Equal(t, []string{"first", "last"}, ran)
+	assert.Len(t, errs, 1)
+	assert.EqualError(t, errs[0], "enricher first: no size")
+}
+
+func Test_torrentFileEnricher_Enabled(t *testing.T) {
+	tests := []struct {
+		name    string
+		release domain.Release
+		actions []*domain.Action
+		want    bool
+	}{
+		{
+			name:    "torrent_client_action",
+			release: domain.Release{Protocol: domain.ReleaseProtocolTorrent, TorrentURL: "https://tracker.test/dl/1"},
+			actions: []*domain.Action{{Type: domain.ActionTypeQbittorrent, Enabled: true}},
+			want:    true,
+		},
+		{
+			name:    "disabled_action",
+			release: domain.Release{Protocol: domain.ReleaseProtocolTorrent, TorrentURL: "https://tracker.test/dl/1"},
+			actions: []*domain.Action{{Type: domain.ActionTypeQbittorrent}},
+			want:    false,
+		},
+		{
+			name:    "arr_action",
+			release: domain.Release{Protocol: domain.ReleaseProtocolTorrent, TorrentURL: "https://tracker.test/dl/1"},
+			actions: []*domain.Action{{Type: domain.ActionTypeRadarr, Enabled: true}},
+			want:    false,
+		},
+		{
+			name:    "already_downloaded",
+			release: domain.Release{Protocol: domain.ReleaseProtocolTorrent, TorrentURL: "https://tracker.test/dl/1", TorrentTmpFile: "/tmp/autobrr-1"},
+			actions: []*domain.Action{{Type: domain.ActionTypeQbittorrent, Enabled: true}},
+			want:    false,
+		},
+		{
+			name:    "no_url",
+			release: domain.Release{Protocol: domain.ReleaseProtocolTorrent},
+			actions: []*domain.Action{{Type: domain.ActionTypeWatchFolder, Enabled: true}},
+			want:    false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			release := tt.release
+			release.Filter = &domain.Filter{Actions: tt.actions}
+
+			assert.Equal(t, tt.want, NewTorrentFileEnricher().Enabled(&release))
+		})
+	}
+}
@@ -0,0 +1,57 @@
+package enrichment
+
+import (
+	"context"
+
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/pkg/errors"
+)
+
+// torrentFileActions download the torrent file anyway, fetching it up front is free and shared by all actions
+var torrentFileActions = map[domain.ActionType]struct{}{
+	domain.ActionTypeQbittorrent:  {},
+	domain.ActionTypeDelugeV1:     {},
+	domain.ActionTypeDelugeV2:     {},
+	domain.ActionTypeRTorrent:     {},
+	domain.ActionTypeTransmission: {},
+	domain.ActionTypePorla:        {},
+	domain.ActionTypeWatchFolder:  {},
+	domain.ActionTypeCrossSeed:    {},
+}
+
+type torrentFileEnricher struct{}
+
+// NewTorrentFileEnricher downloads the torrent file of a release to set its exact size, info hashes and file list
+func NewTorrentFileEnricher() Enricher {
+	return &torrentFileEnricher{}
+}
+
+func (e *torrentFileEnricher) Name() string {
+	return "torrent_file"
+}
+
+func (e *torrentFileEnricher) Enabled(release *domain.Release) bool {
+	if release.Protocol != domain.ReleaseProtocolTorrent || release.TorrentURL == "" || release.TorrentTmpFile != "" {
+		return false
+	}
+
+	for _, a := range release.Filter.Actions {
+		if a == nil || !a.Enabled {
+			continue
+		}
+
+		if _, ok := torrentFileActions[a.Type]; ok {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (e *torrentFileEnricher) Enrich(ctx context.Context, release *domain.Release) error {
+	if err := release.DownloadTorrentFile(); err != nil {
+		return errors.Wrap(err, "could not download torrent file for release: %v", release.TorrentName)
+	}
+
+	return nil
+}
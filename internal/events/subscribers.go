@@ -2,29 +2,36 @@ package events
 
 import (
 	"context"
+	"encoding/json"
 
 	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/internal/irc"
 	"github.com/autobrr/autobrr/internal/logger"
 	"github.com/autobrr/autobrr/internal/notification"
 	"github.com/autobrr/autobrr/internal/release"
 
 	"github.com/asaskevich/EventBus"
+	"github.com/r3labs/sse/v2"
 	"github.com/rs/zerolog"
 )
 
 type Subscriber struct {
 	log             zerolog.Logger
 	eventbus        EventBus.Bus
+	sse             *sse.Server
 	notificationSvc notification.Service
 	releaseSvc      release.Service
+	ircSvc          irc.Service
 }
 
-func NewSubscribers(log logger.Logger, eventbus EventBus.Bus, notificationSvc notification.Service, releaseSvc release.Service) Subscriber {
+func NewSubscribers(log logger.Logger, eventbus EventBus.Bus, sse *sse.Server, notificationSvc notification.Service, releaseSvc release.Service, ircSvc irc.Service) Subscriber {
 	s := Subscriber{
 		log:             log.With().Str("module", "events").Logger(),
 		eventbus:        eventbus,
+		sse:             sse,
 		notificationSvc: notificationSvc,
 		releaseSvc:      releaseSvc,
+		ircSvc:          ircSvc,
 	}
 
 	s.Register()
@@ -36,6 +43,9 @@ func (s Subscriber) Register() {
 	s.eventbus.Subscribe("release:store-action-status", s.releaseActionStatus)
 	s.eventbus.Subscribe("release:push", s.releasePushStatus)
 	s.eventbus.Subscribe("events:notification", s.sendNotification)
+	s.eventbus.Subscribe("indexer:definitions-reloaded", s.indexerDefinitionsReloaded)
+	s.eventbus.Subscribe("release:event", s.releaseEvent)
+	s.eventbus.Subscribe("irc:status", s.ircStatus)
 }
 
 func (s Subscriber) releaseActionStatus(actionStatus *domain.ReleaseActionStatus) {
@@ -60,3 +70,36 @@ func (s Subscriber) sendNotification(event *domain.NotificationEvent, payload *d
 
 	s.notificationSvc.Send(*event, *payload)
 }
+
+func (s Subscriber) indexerDefinitionsReloaded() {
+	s.log.Trace().Msg("events: 'indexer:definitions-reloaded'")
+
+	s.ircSvc.ReloadIndexerDefinitions()
+}
+
+func (s Subscriber) releaseEvent(event *domain.ReleaseEvent) {
+	s.publishStream("releases", event)
+}
+
+func (s Subscriber) ircStatus(event *domain.IrcStatusEvent) {
+	s.log.Trace().Msgf("events: 'irc:status' '%+v'", event)
+
+	s.publishStream("irc", event)
+}
+
+// publishStream sends the event as json to the clients of the server-sent events stream
+func (s Subscriber) publishStream(stream string, event interface{}) {
+	if s.sse == nil {
+		return
+	}
+
+	data, err := json.Marshal(event)
+	if err != nil {
+		s.log.Error().Err(err).Msgf("events: could not marshal event for stream: %v", stream)
+		return
+	}
+
+	s.sse.Publish(stream, &sse.Event{
+		Data: data,
+	})
+}
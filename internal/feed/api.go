@@ -0,0 +1,310 @@
+package feed
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/internal/metrics"
+	"github.com/autobrr/autobrr/internal/release"
+	"github.com/autobrr/autobrr/pkg/errors"
+	"github.com/autobrr/autobrr/pkg/sharedhttp"
+
+	"github.com/Masterminds/sprig/v3"
+	"github.com/rs/zerolog"
+)
+
+// APIJob polls a tracker json api for trackers without irc announces and torznab,
+// the fields of each item are read with the json paths of the feed mapping.
+type APIJob struct {
+	Name              string
+	IndexerIdentifier string
+	Log               zerolog.Logger
+	URL               string
+	Mapping           domain.FeedAPIMapping
+	Repo              domain.FeedCacheRepo
+	ReleaseSvc        release.Service
+
+	Cookie  string
+	Headers http.Header
+	Proxy   string
+
+	attempts int
+	errors   []error
+	backoff  *pollBackoff
+	status   *feedStatus
+
+	JobID int
+}
+
+func NewAPIJob(name string, indexerIdentifier string, log zerolog.Logger, url string, mapping domain.FeedAPIMapping, repo domain.FeedCacheRepo, releaseSvc release.Service) *APIJob {
+	return &APIJob{
+		Name:              name,
+		IndexerIdentifier: indexerIdentifier,
+		Log:               log,
+		URL:               url,
+		Mapping:           mapping,
+		Repo:              repo,
+		ReleaseSvc:        releaseSvc,
+	}
+}
+
+func (j *APIJob) Run() {
+	if j.backoff.Skip(j.Name) {
+		return
+	}
+
+	err := j.process()
+	j.status.update(err)
+	if err != nil {
+		j.Log.Err(err).Int("attempts", j.attempts).Msg("api feed process error")
+
+		j.errors = append(j.errors, err)
+		return
+	}
+
+	j.attempts = 0
+	j.errors = []error{}
+}
+
+func (j *APIJob) process() error {
+	start := time.Now()
+
+	releases, err := j.getReleases()
+	metrics.FeedFetchDuration.Observe(time.Since(start).Seconds(), j.Name)
+	if err != nil {
+		metrics.FeedFetchErrors.Inc(j.Name)
+		return errors.Wrap(err, "error getting api feed items")
+	}
+
+	j.Log.Debug().Msgf("found (%d) new items to process", len(releases))
+
+	if len(releases) == 0 {
+		return nil
+	}
+
+	// process all new releases, the job is skipped while this waits on a full queue
+	queueReleases(j.Log, j.Name, j.Repo, j.ReleaseSvc, releases)
+
+	return nil
+}
+
+// getReleases fetches the api and returns the items not seen before as releases, they are cached once queued
+func (j *APIJob) getReleases() ([]feedRelease, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	items, err := j.fetchItems(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	j.Log.Debug().Msgf("refreshing api feed: %v, found (%d) items", j.Name, len(items))
+
+	releases := make([]feedRelease, 0)
+
+	for _, item := range items {
+		rls, guid, err := j.itemRelease(item)
+		if err != nil {
+			j.Log.Warn().Err(err).Msg("skipping api feed item")
+			continue
+		}
+
+		exists, err := j.Repo.Exists(j.Name, guid)
+		if err != nil {
+			j.Log.Error().Err(err).Msg("could not check if item exists")
+			continue
+		}
+		if exists {
+			j.Log.Trace().Msgf("cache item exists, skipping release: %v", rls.TorrentName)
+			continue
+		}
+
+		releases = append(releases, feedRelease{release: rls, key: guid})
+	}
+
+	return releases, nil
+}
+
+func (j *APIJob) fetchItems(ctx context.Context) ([]interface{}, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, j.URL, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not build request")
+	}
+
+	req.Header.Set("User-Agent", "autobrr")
+	req.Header.Set("Accept", "application/json")
+
+	for name, values := range j.Headers {
+		for _, v := range values {
+			req.Header.Add(name, v)
+		}
+	}
+
+	if j.Cookie != "" {
+		req.Header.Set("Cookie", j.Cookie)
+	}
+
+	client, err := sharedhttp.Client(j.Proxy, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not make request")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.New("unexpected status code: %d", resp.StatusCode)
+	}
+
+	var body interface{}
+
+	dec := json.NewDecoder(resp.Body)
+	dec.UseNumber()
+	if err := dec.Decode(&body); err != nil {
+		return nil, errors.Wrap(err, "could not decode api response")
+	}
+
+	list := body
+	if j.Mapping.Items != "" {
+		var ok bool
+		if list, ok = jsonPath(body, j.Mapping.Items); !ok {
+			return nil, errors.New("items path %q not found in api response", j.Mapping.Items)
+		}
+	}
+
+	items, ok := list.([]interface{})
+	if !ok {
+		return nil, errors.New("items path %q is not a list", j.Mapping.Items)
+	}
+
+	return items, nil
+}
+
+// itemRelease maps an api item to a release and returns the key used to deduplicate it
+func (j *APIJob) itemRelease(item interface{}) (*domain.Release, string, error) {
+	title := jsonString(item, j.Mapping.Title)
+	if title == "" {
+		return nil, "", errors.New("item has no title at %q", j.Mapping.Title)
+	}
+
+	downloadURL, err := j.downloadURL(item)
+	if err != nil {
+		return nil, "", err
+	}
+
+	rls := domain.NewRelease(j.IndexerIdentifier)
+	rls.Implementation = domain.ReleaseImplementationAPI
+	rls.RawCookie = j.Cookie
+	rls.Proxy = j.Proxy
+	rls.TorrentURL = downloadURL
+
+	rls.ParseString(title)
+
+	if j.Mapping.Size != "" {
+		if size := jsonString(item, j.Mapping.Size); size != "" {
+			rls.ParseSizeBytesString(size)
+		}
+	}
+
+	if j.Mapping.Category != "" {
+		rls.Category = jsonString(item, j.Mapping.Category)
+	}
+
+	guid := downloadURL
+	if j.Mapping.GUID != "" {
+		if v := jsonString(item, j.Mapping.GUID); v != "" {
+			guid = v
+		}
+	}
+
+	return rls, guid, nil
+}
+
+func (j *APIJob) downloadURL(item interface{}) (string, error) {
+	if !strings.Contains(j.Mapping.DownloadURL, "{{") {
+		u := jsonString(item, j.Mapping.DownloadURL)
+		if u == "" {
+			return "", errors.New("item has no download url at %q", j.Mapping.DownloadURL)
+		}
+		return u, nil
+	}
+
+	tmpl, err := template.New("download_url").Funcs(sprig.TxtFuncMap()).Parse(j.Mapping.DownloadURL)
+	if err != nil {
+		return "", errors.Wrap(err, "invalid download url template")
+	}
+
+	var b bytes.Buffer
+	if err := tmpl.Execute(&b, item); err != nil {
+		return "", errors.Wrap(err, "could not execute download url template")
+	}
+
+	return b.String(), nil
+}
+
+// jsonPath walks a decoded json value by keys separated with dots, numbers index into lists
+func jsonPath(v interface{}, path string) (interface{}, bool) {
+	if path == "" {
+		return v, true
+	}
+
+	for _, key := range strings.Split(path, ".") {
+		switch node := v.(type) {
+		case map[string]interface{}:
+			next, ok := node[key]
+			if !ok {
+				return nil, false
+			}
+			v = next
+
+		case []interface{}:
+			idx, err := strconv.Atoi(key)
+			if err != nil || idx < 0 || idx >= len(node) {
+				return nil, false
+			}
+			v = node[idx]
+
+		default:
+			return nil, false
+		}
+	}
+
+	return v, true
+}
+
+// jsonString returns the value at path as a string, lists are joined with a comma
+func jsonString(v interface{}, path string) string {
+	value, ok := jsonPath(v, path)
+	if !ok {
+		return ""
+	}
+
+	switch val := value.(type) {
+	case string:
+		return strings.TrimSpace(val)
+	case json.Number:
+		return val.String()
+	case bool:
+		return strconv.FormatBool(val)
+	case []interface{}:
+		var parts []string
+		for _, p := range val {
+			if s := jsonString(p, ""); s != "" {
+				parts = append(parts, s)
+			}
+		}
+		return strings.Join(parts, ", ")
+	}
+
+	return ""
+}
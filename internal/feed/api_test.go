@@ -0,0 +1,96 @@
+package feed
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/autobrr/autobrr/internal/domain"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+)
+
+const apiFeed = `{
+  "data": {
+    "torrents": [
+      {"id": 2, "name": "That.Show.S01E02.1080p.WEB-DL-GROUP", "size": 2147483648, "tags": ["tv", "hd"]},
+      {"id": 1, "name": "That.Show.S01E01.1080p.WEB-DL-GROUP", "size": "1.5 GB", "tags": ["tv"]},
+      {"id": 3, "size": 100}
+    ]
+  }
+}`
+
+func TestAPIJob_getReleases(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer abc" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(apiFeed))
+	}))
+	defer ts.Close()
+
+	mapping := domain.FeedAPIMapping{
+		Items:       "data.torrents",
+		GUID:        "id",
+		Title:       "name",
+		DownloadURL: "https://tracker.test/download.php?id={{ .id }}",
+		Size:        "size",
+		Category:    "tags",
+	}
+
+	cache := &mockFeedCache{keys: map[string]struct{}{"1": {}}}
+
+	j := NewAPIJob("feed", "mock", zerolog.Nop(), ts.URL, mapping, cache, nil)
+	j.Headers = http.Header{"Authorization": {"Bearer abc"}}
+
+	releases, err := j.getReleases()
+	assert.NoError(t, err)
+
+	// id 1 is cached and id 3 has no title
+	if assert.Len(t, releases, 1) {
+		assert.Equal(t, "2", releases[0].key)
+
+		rls := releases[0].release
+		assert.Equal(t, "That.Show.S01E02.1080p.WEB-DL-GROUP", rls.TorrentName)
+		assert.Equal(t, "https://tracker.test/download.php?id=2", rls.TorrentURL)
+		assert.Equal(t, uint64(2147483648), rls.Size)
+		assert.Equal(t, "tv, hd", rls.Category)
+		assert.Equal(t, domain.ReleaseImplementationAPI, rls.Implementation)
+	}
+
+	// releases are cached once queued, not when fetched
+	_, seen := cache.keys["2"]
+	assert.False(t, seen)
+
+	releases, err = j.getReleases()
+	assert.NoError(t, err)
+	assert.Len(t, releases, 1)
+}
+
+func Test_jsonString(t *testing.T) {
+	item := map[string]interface{}{
+		"name":  "release",
+		"files": []interface{}{map[string]interface{}{"name": "file.mkv"}},
+		"tags":  []interface{}{"a", "b"},
+	}
+
+	tests := []struct {
+		path string
+		want string
+	}{
+		{path: "name", want: "release"},
+		{path: "files.0.name", want: "file.mkv"},
+		{path: "files.1.name", want: ""},
+		{path: "tags", want: "a, b"},
+		{path: "missing", want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			assert.Equal(t, tt.want, jsonString(item, tt.path))
+		})
+	}
+}
@@ -0,0 +1,98 @@
+package feed
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// activeDownloadChecker reports the bytes left of the largest active download
+type activeDownloadChecker interface {
+	LargestActiveDownload(ctx context.Context) (int64, error)
+}
+
+// pollBackoff defers feed polls while a large download is active,
+// so feeds are only polled every nth interval.
+type pollBackoff struct {
+	log     zerolog.Logger
+	checker activeDownloadChecker
+
+	threshold     int64
+	multiplier    int
+	checkInterval time.Duration
+
+	m         sync.Mutex
+	busy      bool
+	lastCheck time.Time
+	skipped   map[string]int
+}
+
+func newPollBackoff(log zerolog.Logger, checker activeDownloadChecker, thresholdMB int64, multiplier int) *pollBackoff {
+	return &pollBackoff{
+		log:           log,
+		checker:       checker,
+		threshold:     thresholdMB * 1024 * 1024,
+		multiplier:    multiplier,
+		checkInterval: 1 * time.Minute,
+		skipped:       map[string]int{},
+	}
+}
+
+// Skip reports whether the poll for the feed should be deferred
+func (b *pollBackoff) Skip(feed string) bool {
+	if b == nil || b.multiplier <= 1 {
+		return false
+	}
+
+	// multiple feeds run around the same time so only ask the clients once in a while.
+	// The check is claimed under the lock but done without it, feeds polled meanwhile use the last result.
+	b.m.Lock()
+	check := time.Since(b.lastCheck) >= b.checkInterval
+	if check {
+		b.lastCheck = time.Now()
+	}
+	b.m.Unlock()
+
+	var busy bool
+	if check {
+		busy = b.isBusy()
+	}
+
+	b.m.Lock()
+	defer b.m.Unlock()
+
+	if check {
+		b.busy = busy
+	}
+
+	if !b.busy {
+		b.skipped[feed] = 0
+		return false
+	}
+
+	if b.skipped[feed] < b.multiplier-1 {
+		b.skipped[feed]++
+		b.log.Debug().Msgf("large download active, deferring poll for feed: %v (%d/%d)", feed, b.skipped[feed], b.multiplier-1)
+		return true
+	}
+
+	b.skipped[feed] = 0
+
+	return false
+}
+
+func (b *pollBackoff) isBusy() bool {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	size, err := b.checker.LargestActiveDownload(ctx)
+	if err != nil {
+		// don't hold back feeds if we can't reach the clients
+		b.log.Error().Err(err).Msg("could not check active downloads")
+		return false
+	}
+
+	return size >= b.threshold
+}
@@ -0,0 +1,117 @@
+package feed
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/autobrr/autobrr/pkg/errors"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockDownloadChecker struct {
+	size int64
+	err  error
+}
+
+func (m *mockDownloadChecker) LargestActiveDownload(ctx context.Context) (int64, error) {
+	return m.size, m.err
+}
+
+func Test_pollBackoff_Skip(t *testing.T) {
+	checker := &mockDownloadChecker{}
+
+	b := newPollBackoff(zerolog.Nop(), checker, 1024, 3)
+	b.checkInterval = 0
+
+	// idle, poll every interval
+	assert.False(t, b.Skip("feed"))
+	assert.False(t, b.Skip("feed"))
+
+	// small download below threshold
+	checker.size = 512 * 1024 * 1024
+	assert.False(t, b.Skip("feed"))
+
+	// large download active, poll every third interval
+	checker.size = 2048 * 1024 * 1024
+	assert.True(t, b.Skip("feed"))
+	assert.True(t, b.Skip("feed"))
+	assert.False(t, b.Skip("feed"))
+	assert.True(t, b.Skip("feed"))
+
+	// feeds are counted separately
+	assert.True(t, b.Skip("other"))
+
+	// download done, back to normal
+	checker.size = 0
+	assert.False(t, b.Skip("feed"))
+	assert.False(t, b.Skip("other"))
+
+	// errors never defer polls
+	checker.size = 2048 * 1024 * 1024
+	checker.err = errors.New("connection refused")
+	assert.False(t, b.Skip("feed"))
+}
+
+func Test_pollBackoff_Skip_cached(t *testing.T) {
+	checker := &mockDownloadChecker{size: 2048 * 1024 * 1024}
+
+	b := newPollBackoff(zerolog.Nop(), checker, 1024, 2)
+
+	assert.True(t, b.Skip("feed"))
+
+	// state is cached until the next check
+	checker.size = 0
+	assert.False(t, b.Skip("feed"))
+	assert.True(t, b.Skip("feed"))
+}
+
+type blockingDownloadChecker struct {
+	called  chan struct{}
+	release chan struct{}
+}
+
+func (m *blockingDownloadChecker) LargestActiveDownload(ctx context.Context) (int64, error) {
+	close(m.called)
+	<-m.release
+	return 2048 * 1024 * 1024, nil
+}
+
+func Test_pollBackoff_Skip_checkWithoutLock(t *testing.T) {
+	checker := &blockingDownloadChecker{called: make(chan struct{}), release: make(chan struct{})}
+
+	b := newPollBackoff(zerolog.Nop(), checker, 1024, 2)
+
+	done := make(chan bool)
+	go func() {
+		done <- b.Skip("feed")
+	}()
+	<-checker.called
+
+	// other feeds don't wait for the check and use the last result
+	skipped := make(chan bool)
+	go func() {
+		skipped <- b.Skip("other")
+	}()
+
+	select {
+	case skip := <-skipped:
+		assert.False(t, skip)
+	case <-time.After(time.Second):
+		t.Fatal("feed waited for the active download check")
+	}
+
+	close(checker.release)
+	assert.True(t, <-done)
+}
+
+func Test_pollBackoff_Skip_disabled(t *testing.T) {
+	var b *pollBackoff
+	assert.False(t, b.Skip("feed"))
+
+	b = newPollBackoff(zerolog.Nop(), &mockDownloadChecker{size: 2048 * 1024 * 1024}, 1024, 1)
+	b.checkInterval = 0
+	assert.False(t, b.Skip("feed"))
+}
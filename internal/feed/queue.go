@@ -0,0 +1,46 @@
+package feed
+
+import (
+	"context"
+	"time"
+
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/internal/release"
+
+	"github.com/rs/zerolog"
+)
+
+// feedQueueTimeout is how long a feed waits for room in the release queue of its indexer
+const feedQueueTimeout = 5 * time.Minute
+
+// feedRelease is a new feed item and the key it is cached with once its release is queued
+type feedRelease struct {
+	release *domain.Release
+	key     string
+}
+
+// queueReleases hands the new releases to the release service and caches the ones that were queued.
+// Releases that were dropped because the queue stayed full are not cached and show up again on the next poll.
+func queueReleases(log zerolog.Logger, name string, repo domain.FeedCacheRepo, releaseSvc release.Service, items []feedRelease) {
+	releases := make([]*domain.Release, 0, len(items))
+	for _, item := range items {
+		releases = append(releases, item.release)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), feedQueueTimeout)
+	defer cancel()
+
+	queued, err := releaseSvc.ProcessMultiple(ctx, releases)
+	if err != nil {
+		log.Warn().Err(err).Msgf("queued %d of %d new releases, the others are retried on the next poll", queued, len(releases))
+	}
+
+	// set ttl to 1 month
+	ttl := time.Now().AddDate(0, 1, 0)
+
+	for _, item := range items[:queued] {
+		if err := repo.Put(name, item.key, []byte(item.release.TorrentName), ttl); err != nil {
+			log.Error().Stack().Err(err).Str("entry", item.key).Msg("cache.Put: error storing item in cache")
+		}
+	}
+}
@@ -0,0 +1,58 @@
+package feed
+
+import (
+	"context"
+	"testing"
+
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/internal/release"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockReleaseService struct {
+	release.Service
+	accept int
+}
+
+func (m *mockReleaseService) ProcessMultiple(ctx context.Context, releases []*domain.Release) (int, error) {
+	if len(releases) > m.accept {
+		return m.accept, release.ErrQueueFull
+	}
+
+	return len(releases), nil
+}
+
+func Test_queueReleases(t *testing.T) {
+	tests := []struct {
+		name   string
+		accept int
+		want   []string
+	}{
+		{name: "all_queued", accept: 3, want: []string{"a", "b", "c"}},
+		{name: "queue_full", accept: 1, want: []string{"a"}},
+		{name: "none_queued", accept: 0, want: []string{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cache := &mockFeedCache{keys: map[string]struct{}{}}
+
+			items := []feedRelease{
+				{release: domain.NewRelease("mock"), key: "a"},
+				{release: domain.NewRelease("mock"), key: "b"},
+				{release: domain.NewRelease("mock"), key: "c"},
+			}
+
+			queueReleases(zerolog.Nop(), "feed", cache, &mockReleaseService{accept: tt.accept}, items)
+
+			keys := make([]string, 0)
+			for _, item := range items {
+				if _, ok := cache.keys[item.key]; ok {
+					keys = append(keys, item.key)
+				}
+			}
+			assert.Equal(t, tt.want, keys)
+		})
+	}
+}
@@ -2,12 +2,15 @@ package feed
 
 import (
 	"context"
+	"net/http"
 	"sort"
 	"time"
 
 	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/internal/metrics"
 	"github.com/autobrr/autobrr/internal/release"
 	"github.com/autobrr/autobrr/pkg/errors"
+	"github.com/autobrr/autobrr/pkg/sharedhttp"
 
 	"github.com/mmcdole/gofeed"
 	"github.com/rs/zerolog"
@@ -21,8 +24,19 @@ type RSSJob struct {
 	Repo              domain.FeedCacheRepo
 	ReleaseSvc        release.Service
 
+	// Cookie and Headers are sent with every request, private trackers often use session based feeds
+	Cookie   string
+	Headers  http.Header
+	Username string
+	Password string
+
+	// Proxy of the indexer, used for the feed and torrent downloads
+	Proxy string
+
 	attempts int
 	errors   []error
+	backoff  *pollBackoff
+	status   *feedStatus
 
 	JobID int
 }
@@ -39,7 +53,13 @@ func NewRSSJob(name string, indexerIdentifier string, log zerolog.Logger, url st
 }
 
 func (j *RSSJob) Run() {
-	if err := j.process(); err != nil {
+	if j.backoff.Skip(j.Name) {
+		return
+	}
+
+	err := j.process()
+	j.status.update(err)
+	if err != nil {
 		j.Log.Err(err).Int("attempts", j.attempts).Msg("rss feed process error")
 
 		j.errors = append(j.errors, err)
@@ -53,8 +73,12 @@ func (j *RSSJob) Run() {
 }
 
 func (j *RSSJob) process() error {
+	start := time.Now()
+
 	items, err := j.getFeed()
+	metrics.FeedFetchDuration.Observe(time.Since(start).Seconds(), j.Name)
 	if err != nil {
+		metrics.FeedFetchErrors.Inc(j.Name)
 		j.Log.Error().Err(err).Msgf("error fetching rss feed items")
 		return errors.Wrap(err, "error getting rss feed items")
 	}
@@ -65,65 +89,71 @@ func (j *RSSJob) process() error {
 		return nil
 	}
 
-	releases := make([]*domain.Release, 0)
+	releases := make([]feedRelease, 0, len(items))
 
 	for _, item := range items {
-		rls := domain.NewRelease(j.IndexerIdentifier)
-		rls.Implementation = domain.ReleaseImplementationRSS
-
-		rls.ParseString(item.Title)
-
-		if len(item.Enclosures) > 0 {
-			e := item.Enclosures[0]
-			if e.Type == "application/x-bittorrent" && e.URL != "" {
-				rls.TorrentURL = e.URL
-			}
-			if e.Length != "" {
-				rls.ParseSizeBytesString(e.Length)
-			}
-		}
+		releases = append(releases, feedRelease{release: j.itemRelease(item), key: rssCacheKey(item)})
+	}
 
-		if rls.TorrentURL == "" && item.Link != "" {
-			rls.TorrentURL = item.Link
-		}
+	// process all new releases, the job is skipped while this waits on a full queue
+	queueReleases(j.Log, j.Name, j.Repo, j.ReleaseSvc, releases)
+
+	return nil
+}
+
+func (j *RSSJob) itemRelease(item *gofeed.Item) *domain.Release {
+	rls := domain.NewRelease(j.IndexerIdentifier)
+	rls.Implementation = domain.ReleaseImplementationRSS
+	rls.RawCookie = j.Cookie
+	rls.Proxy = j.Proxy
 
-		for _, v := range item.Categories {
-			if len(rls.Category) != 0 {
-				rls.Category += ", "
-			}
+	rls.ParseString(item.Title)
 
-			rls.Category += v
+	if len(item.Enclosures) > 0 {
+		e := item.Enclosures[0]
+		if e.Type == "application/x-bittorrent" && e.URL != "" {
+			rls.TorrentURL = e.URL
 		}
+		if e.Length != "" {
+			rls.ParseSizeBytesString(e.Length)
+		}
+	}
 
-		for _, v := range item.Authors {
-			if len(rls.Uploader) != 0 {
-				rls.Uploader += ", "
-			}
+	if rls.TorrentURL == "" && item.Link != "" {
+		rls.TorrentURL = item.Link
+	}
 
-			rls.Uploader += v.Name
+	for _, v := range item.Categories {
+		if len(rls.Category) != 0 {
+			rls.Category += ", "
 		}
 
-		if rls.Size == 0 {
-			// parse size bytes string
-			if sz, ok := item.Custom["size"]; ok {
-				rls.ParseSizeBytesString(sz)
-			}
+		rls.Category += v
+	}
+
+	for _, v := range item.Authors {
+		if len(rls.Uploader) != 0 {
+			rls.Uploader += ", "
 		}
 
-		releases = append(releases, rls)
+		rls.Uploader += v.Name
 	}
 
-	// process all new releases
-	go j.ReleaseSvc.ProcessMultiple(releases)
+	if rls.Size == 0 {
+		// parse size bytes string
+		if sz, ok := item.Custom["size"]; ok {
+			rls.ParseSizeBytesString(sz)
+		}
+	}
 
-	return nil
+	return rls
 }
 
 func (j *RSSJob) getFeed() (items []*gofeed.Item, err error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
 	defer cancel()
 
-	feed, err := gofeed.NewParser().ParseURLWithContext(j.URL, ctx) // there's an RSS specific parser as well.
+	feed, err := j.fetchFeed(ctx)
 	if err != nil {
 		j.Log.Error().Err(err).Msgf("error fetching rss feed items")
 		return nil, errors.Wrap(err, "error fetching rss feed items")
@@ -137,13 +167,11 @@ func (j *RSSJob) getFeed() (items []*gofeed.Item, err error) {
 
 	sort.Sort(feed)
 
+	// items are cached once their release is queued
 	for _, i := range feed.Items {
-		s := i.GUID
+		s := rssCacheKey(i)
 		if len(s) == 0 {
-			s = i.Title
-			if len(s) == 0 {
-				continue
-			}
+			continue
 		}
 
 		exists, err := j.Repo.Exists(j.Name, s)
@@ -156,18 +184,66 @@ func (j *RSSJob) getFeed() (items []*gofeed.Item, err error) {
 			continue
 		}
 
-		// set ttl to 1 month
-		ttl := time.Now().AddDate(0, 1, 0)
-
-		if err := j.Repo.Put(j.Name, s, []byte(i.Title), ttl); err != nil {
-			j.Log.Error().Stack().Err(err).Str("entry", s).Msg("cache.Put: error storing item in cache")
-			continue
-		}
-
-		// only append if we successfully added to cache
 		items = append(items, i)
 	}
 
 	// send to filters
 	return
 }
+
+// rssCacheKey is the guid of the item, or its title for feeds without guids
+func rssCacheKey(item *gofeed.Item) string {
+	if item.GUID != "" {
+		return item.GUID
+	}
+
+	return item.Title
+}
+
+// fetchFeed requests the feed with the configured auth and parses it
+func (j *RSSJob) fetchFeed(ctx context.Context) (*gofeed.Feed, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, j.URL, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not build request")
+	}
+
+	req.Header.Set("User-Agent", "autobrr")
+
+	for name, values := range j.Headers {
+		for _, v := range values {
+			req.Header.Add(name, v)
+		}
+	}
+
+	if j.Cookie != "" {
+		// set the cookie on the header instead of req.AddCookie
+		// since we have a raw cookie like "uid=10; pass=000"
+		req.Header.Set("Cookie", j.Cookie)
+	}
+
+	if j.Username != "" || j.Password != "" {
+		req.SetBasicAuth(j.Username, j.Password)
+	}
+
+	client, err := sharedhttp.Client(j.Proxy, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not make request")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.New("unexpected status code: %d", resp.StatusCode)
+	}
+
+	feed, err := gofeed.NewParser().Parse(resp.Body) // there's an RSS specific parser as well.
+	if err != nil {
+		return nil, errors.Wrap(err, "could not parse feed")
+	}
+
+	return feed, nil
+}
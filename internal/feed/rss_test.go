@@ -0,0 +1,74 @@
+package feed
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+)
+
+const rssFeed = `<?xml version="1.0" encoding="UTF-8"?>
+<rss version="2.0">
+  <channel>
+    <title>mock</title>
+    <item>
+      <title>That.Show.S01E01.1080p.WEB-DL-GROUP</title>
+      <guid>1</guid>
+      <enclosure url="https://tracker.test/dl/1" length="1000" type="application/x-bittorrent" />
+    </item>
+  </channel>
+</rss>`
+
+func TestRSSJob_fetchFeed(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, pass, ok := r.BasicAuth()
+		if !ok || user != "user" || pass != "pass" || r.Header.Get("Cookie") != "uid=10; pass=000" || r.Header.Get("X-Api-Key") != "abc" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/rss+xml")
+		w.Write([]byte(rssFeed))
+	}))
+	defer ts.Close()
+
+	tests := []struct {
+		name     string
+		cookie   string
+		username string
+		wantErr  string
+	}{
+		{
+			name:     "authenticated",
+			cookie:   "uid=10; pass=000",
+			username: "user",
+		},
+		{
+			name:     "missing_cookie",
+			username: "user",
+			wantErr:  "unexpected status code: 401",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			j := NewRSSJob("feed", "mock", zerolog.Nop(), ts.URL, nil, nil)
+			j.Cookie = tt.cookie
+			j.Headers = http.Header{"X-Api-Key": {"abc"}}
+			j.Username = tt.username
+			j.Password = "pass"
+
+			feed, err := j.fetchFeed(context.Background())
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Len(t, feed.Items, 1)
+			assert.Equal(t, "That.Show.S01E01.1080p.WEB-DL-GROUP", feed.Items[0].Title)
+		})
+	}
+}
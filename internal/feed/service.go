@@ -2,13 +2,18 @@ package feed
 
 import (
 	"context"
+	"net/http"
 	"time"
 
+	"github.com/autobrr/autobrr/internal/audit"
 	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/internal/download_client"
+	"github.com/autobrr/autobrr/internal/filter"
 	"github.com/autobrr/autobrr/internal/logger"
 	"github.com/autobrr/autobrr/internal/release"
 	"github.com/autobrr/autobrr/internal/scheduler"
 	"github.com/autobrr/autobrr/pkg/errors"
+	"github.com/autobrr/autobrr/pkg/sharedhttp"
 	"github.com/autobrr/autobrr/pkg/torznab"
 
 	"github.com/dcarbone/zadapters/zstdlog"
@@ -22,6 +27,8 @@ type Service interface {
 	Store(ctx context.Context, feed *domain.Feed) error
 	Update(ctx context.Context, feed *domain.Feed) error
 	Test(ctx context.Context, feed *domain.Feed) error
+	DryRun(ctx context.Context, id int) (*domain.FeedDryRunResponse, error)
+	Fetch(ctx context.Context, id int) error
 	ToggleEnabled(ctx context.Context, id int, enabled bool) error
 	Delete(ctx context.Context, id int) error
 
@@ -29,12 +36,20 @@ type Service interface {
 }
 
 type feedInstance struct {
+	ID                int
 	Name              string
 	IndexerIdentifier string
 	URL               string
 	ApiKey            string
 	Implementation    string
 	CronSchedule      time.Duration
+	Backfill          int
+	Cookie            string
+	Proxy             string
+	Headers           http.Header
+	Username          string
+	Password          string
+	APIMapping        domain.FeedAPIMapping
 }
 
 type service struct {
@@ -44,18 +59,29 @@ type service struct {
 	repo       domain.FeedRepo
 	cacheRepo  domain.FeedCacheRepo
 	releaseSvc release.Service
+	filterSvc  filter.Service
 	scheduler  scheduler.Service
+	auditSvc   audit.Service
+	backoff    *pollBackoff
 }
 
-func NewService(log logger.Logger, repo domain.FeedRepo, cacheRepo domain.FeedCacheRepo, releaseSvc release.Service, scheduler scheduler.Service) Service {
-	return &service{
+func NewService(log logger.Logger, config *domain.Config, repo domain.FeedRepo, cacheRepo domain.FeedCacheRepo, releaseSvc release.Service, filterSvc filter.Service, downloadClientSvc download_client.Service, scheduler scheduler.Service, auditSvc audit.Service) Service {
+	s := &service{
 		log:        log.With().Str("module", "feed").Logger(),
 		jobs:       map[string]int{},
 		repo:       repo,
 		cacheRepo:  cacheRepo,
 		releaseSvc: releaseSvc,
+		filterSvc:  filterSvc,
 		scheduler:  scheduler,
+		auditSvc:   auditSvc,
 	}
+
+	if config.FeedBackoff {
+		s.backoff = newPollBackoff(s.log, downloadClientSvc, config.FeedBackoffThreshold, config.FeedBackoffMultiplier)
+	}
+
+	return s
 }
 
 func (s *service) FindByID(ctx context.Context, id int) (*domain.Feed, error) {
@@ -89,11 +115,18 @@ func (s *service) Find(ctx context.Context) ([]domain.Feed, error) {
 }
 
 func (s *service) Store(ctx context.Context, feed *domain.Feed) error {
+	if _, err := feed.HTTPHeaders(); err != nil {
+		s.log.Error().Err(err).Msgf("invalid feed headers: %v", feed.Name)
+		return err
+	}
+
 	if err := s.repo.Store(ctx, feed); err != nil {
 		s.log.Error().Err(err).Msgf("could not store feed: %+v", feed)
 		return err
 	}
 
+	s.auditSvc.Record(ctx, domain.AuditEntityFeed, feed.ID, feed.Name, nil, feed)
+
 	s.log.Debug().Msgf("successfully added feed: %+v", feed)
 
 	return nil
@@ -128,11 +161,26 @@ func (s *service) ToggleEnabled(ctx context.Context, id int, enabled bool) error
 }
 
 func (s *service) update(ctx context.Context, feed *domain.Feed) error {
+	if _, err := feed.HTTPHeaders(); err != nil {
+		s.log.Error().Err(err).Msg("feed.Update: invalid feed headers")
+		return err
+	}
+
+	before, err := s.repo.FindByID(ctx, feed.ID)
+	if err != nil {
+		s.log.Error().Err(err).Msg("feed.Update: error finding feed")
+		return err
+	}
+
 	if err := s.repo.Update(ctx, feed); err != nil {
 		s.log.Error().Err(err).Msg("feed.Update: error updating feed")
 		return err
 	}
 
+	if after, err := s.repo.FindByID(ctx, feed.ID); err == nil {
+		s.auditSvc.Record(ctx, domain.AuditEntityFeed, after.ID, after.Name, before, after)
+	}
+
 	if err := s.restartJob(feed); err != nil {
 		s.log.Error().Err(err).Msg("feed.Update: error restarting feed")
 		return err
@@ -159,6 +207,11 @@ func (s *service) delete(ctx context.Context, id int) error {
 			s.log.Error().Err(err).Msg("error stopping rss job")
 			return err
 		}
+	case string(domain.FeedTypeAPI):
+		if err := s.stopAPIJob(f.Indexer); err != nil {
+			s.log.Error().Err(err).Msg("error stopping api job")
+			return err
+		}
 	}
 
 	if err := s.repo.Delete(ctx, id); err != nil {
@@ -166,6 +219,8 @@ func (s *service) delete(ctx context.Context, id int) error {
 		return err
 	}
 
+	s.auditSvc.Record(ctx, domain.AuditEntityFeed, f.ID, f.Name, f, nil)
+
 	if err := s.cacheRepo.DeleteBucket(ctx, f.Name); err != nil {
 		s.log.Error().Err(err).Msgf("could not delete feedCache bucket by id: %v", id)
 		return err
@@ -188,6 +243,10 @@ func (s *service) toggleEnabled(ctx context.Context, id int, enabled bool) error
 		return err
 	}
 
+	after := *f
+	after.Enabled = enabled
+	s.auditSvc.Record(ctx, domain.AuditEntityFeed, f.ID, f.Name, f, &after)
+
 	if f.Enabled && !enabled {
 		switch f.Type {
 		case string(domain.FeedTypeTorznab):
@@ -200,6 +259,11 @@ func (s *service) toggleEnabled(ctx context.Context, id int, enabled bool) error
 				s.log.Error().Err(err).Msg("feed.ToggleEnabled: error stopping rss job")
 				return err
 			}
+		case string(domain.FeedTypeAPI):
+			if err := s.stopAPIJob(f.Indexer); err != nil {
+				s.log.Error().Err(err).Msg("feed.ToggleEnabled: error stopping api job")
+				return err
+			}
 		}
 
 		s.log.Debug().Msgf("feed.ToggleEnabled: stopping feed: %v", f.Name)
@@ -237,11 +301,215 @@ func (s *service) Test(ctx context.Context, feed *domain.Feed) error {
 		}
 	}
 
+	// implementation == RSS
+	if feed.Type == string(domain.FeedTypeRSS) {
+		headers, err := feed.HTTPHeaders()
+		if err != nil {
+			s.log.Error().Err(err).Msg("invalid feed headers")
+			return err
+		}
+
+		job := NewRSSJob(feed.Name, feed.Indexer, s.log, feed.URL, s.cacheRepo, s.releaseSvc)
+		job.Cookie = feed.Cookie
+		job.Headers = headers
+		job.Username = feed.Username
+		job.Password = feed.Password
+
+		ctx, cancel := context.WithTimeout(ctx, 60*time.Second)
+		defer cancel()
+
+		if _, err := job.fetchFeed(ctx); err != nil {
+			s.log.Error().Err(err).Msg("error testing feed")
+			return err
+		}
+	}
+
+	// implementation == API
+	if feed.Type == string(domain.FeedTypeAPI) {
+		headers, err := feed.HTTPHeaders()
+		if err != nil {
+			s.log.Error().Err(err).Msg("invalid feed headers")
+			return err
+		}
+
+		mapping, err := feed.APIMapping()
+		if err != nil {
+			return err
+		}
+
+		job := NewAPIJob(feed.Name, feed.Indexer, s.log, feed.URL, mapping, s.cacheRepo, s.releaseSvc)
+		job.Cookie = feed.Cookie
+		job.Headers = headers
+		job.Proxy = feed.Proxy
+
+		ctx, cancel := context.WithTimeout(ctx, 60*time.Second)
+		defer cancel()
+
+		items, err := job.fetchItems(ctx)
+		if err != nil {
+			s.log.Error().Err(err).Msg("error testing feed")
+			return err
+		}
+
+		// check the mapping against the newest item so a wrong path shows up in the test
+		if len(items) > 0 {
+			if _, _, err := job.itemRelease(items[0]); err != nil {
+				return err
+			}
+		}
+	}
+
 	s.log.Debug().Msgf("test successful - connected to feed: %+v", feed.URL)
 
 	return nil
 }
 
+// DryRun fetches the feed once and checks every item against the filters of the feed indexer.
+// Nothing is stored, no actions are run and the feed cache is left alone so the items are still new for the next poll.
+func (s *service) DryRun(ctx context.Context, id int) (*domain.FeedDryRunResponse, error) {
+	feed, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		s.log.Error().Err(err).Msgf("could not find feed by id: %v", id)
+		return nil, err
+	}
+
+	items, err := s.fetchDryRunItems(ctx, feed)
+	if err != nil {
+		s.log.Error().Err(err).Msgf("could not fetch feed: %v", feed.Name)
+		return nil, err
+	}
+
+	res := &domain.FeedDryRunResponse{
+		FeedID:  feed.ID,
+		Feed:    feed.Name,
+		Indexer: feed.Indexer,
+		Items:   make([]domain.FeedDryRunItem, 0, len(items)),
+	}
+
+	for _, item := range items {
+		if item.Release == nil {
+			res.Items = append(res.Items, item)
+			continue
+		}
+
+		results, err := s.filterSvc.DryRun(ctx, item.Release)
+		if err != nil {
+			return nil, err
+		}
+
+		res.Items = append(res.Items, domain.NewFeedDryRunItem(item.Release, results))
+	}
+
+	s.log.Debug().Msgf("dry run of feed %v checked (%d) items", feed.Name, len(res.Items))
+
+	return res, nil
+}
+
+// Fetch runs the scheduled job of the feed now instead of waiting for the next poll
+func (s *service) Fetch(ctx context.Context, id int) error {
+	feed, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		s.log.Error().Err(err).Msgf("could not find feed by id: %v", id)
+		return err
+	}
+
+	if !feed.Enabled {
+		return errors.New("feed %v is disabled", feed.Name)
+	}
+
+	if err := s.scheduler.RunJobByIdentifier(feed.Indexer); err != nil {
+		return errors.Wrap(err, "could not run feed: %v", feed.Name)
+	}
+
+	s.log.Debug().Msgf("fetching feed %v", feed.Name)
+
+	return nil
+}
+
+// fetchDryRunItems fetches the first page of the feed and builds the releases the way the feed jobs do
+func (s *service) fetchDryRunItems(ctx context.Context, feed *domain.Feed) ([]domain.FeedDryRunItem, error) {
+	ctx, cancel := context.WithTimeout(ctx, 60*time.Second)
+	defer cancel()
+
+	headers, err := feed.HTTPHeaders()
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]domain.FeedDryRunItem, 0)
+
+	switch feed.Type {
+	case string(domain.FeedTypeTorznab):
+		transport, err := sharedhttp.Transport(feed.Proxy)
+		if err != nil {
+			return nil, errors.Wrap(err, "invalid proxy for feed: %v", feed.Name)
+		}
+
+		c := torznab.NewClient(torznab.Config{Host: feed.URL, ApiKey: feed.ApiKey, Transport: transport})
+
+		feedItems, err := c.GetFeedPage(0, 0)
+		if err != nil {
+			return nil, errors.Wrap(err, "error fetching feed items")
+		}
+
+		job := NewTorznabJob(feed.Name, feed.Indexer, s.log, feed.URL, c, s.cacheRepo, s.releaseSvc)
+		job.Proxy = feed.Proxy
+
+		for _, item := range feedItems {
+			items = append(items, domain.FeedDryRunItem{Title: item.Title, Release: job.itemRelease(item)})
+		}
+
+	case string(domain.FeedTypeRSS):
+		job := NewRSSJob(feed.Name, feed.Indexer, s.log, feed.URL, s.cacheRepo, s.releaseSvc)
+		job.Cookie = feed.Cookie
+		job.Headers = headers
+		job.Username = feed.Username
+		job.Password = feed.Password
+		job.Proxy = feed.Proxy
+
+		parsed, err := job.fetchFeed(ctx)
+		if err != nil {
+			return nil, errors.Wrap(err, "error fetching rss feed items")
+		}
+
+		for _, item := range parsed.Items {
+			items = append(items, domain.FeedDryRunItem{Title: item.Title, Release: job.itemRelease(item)})
+		}
+
+	case string(domain.FeedTypeAPI):
+		mapping, err := feed.APIMapping()
+		if err != nil {
+			return nil, err
+		}
+
+		job := NewAPIJob(feed.Name, feed.Indexer, s.log, feed.URL, mapping, s.cacheRepo, s.releaseSvc)
+		job.Cookie = feed.Cookie
+		job.Headers = headers
+		job.Proxy = feed.Proxy
+
+		apiItems, err := job.fetchItems(ctx)
+		if err != nil {
+			return nil, errors.Wrap(err, "error fetching api feed items")
+		}
+
+		for _, item := range apiItems {
+			rls, _, err := job.itemRelease(item)
+			if err != nil {
+				// report the unmapped item instead of failing the whole dry run
+				items = append(items, domain.FeedDryRunItem{Matched: []string{}, Filters: []domain.FilterTestResult{}, Error: err.Error()})
+				continue
+			}
+
+			items = append(items, domain.FeedDryRunItem{Title: rls.TorrentName, Release: rls})
+		}
+
+	default:
+		return nil, errors.New("unsupported feed type: %v", feed.Type)
+	}
+
+	return items, nil
+}
+
 func (s *service) Start() error {
 	// get all torznab indexer definitions
 	feeds, err := s.repo.Find(context.TODO())
@@ -292,14 +560,34 @@ func (s *service) startJob(f domain.Feed) error {
 		return nil
 	}
 
+	headers, err := f.HTTPHeaders()
+	if err != nil {
+		return errors.Wrap(err, "invalid headers for feed: %v", f.Name)
+	}
+
+	var mapping domain.FeedAPIMapping
+	if f.Type == string(domain.FeedTypeAPI) {
+		if mapping, err = f.APIMapping(); err != nil {
+			return errors.Wrap(err, "invalid api mapping for feed: %v", f.Name)
+		}
+	}
+
 	// cron schedule to run every X minutes
 	fi := feedInstance{
+		ID:                f.ID,
 		Name:              f.Name,
 		IndexerIdentifier: f.Indexer,
 		Implementation:    f.Type,
 		URL:               f.URL,
 		ApiKey:            f.ApiKey,
 		CronSchedule:      time.Duration(f.Interval) * time.Minute,
+		Backfill:          f.Backfill,
+		Cookie:            f.Cookie,
+		Proxy:             f.Proxy,
+		Headers:           headers,
+		Username:          f.Username,
+		Password:          f.Password,
+		APIMapping:        mapping,
 	}
 
 	switch fi.Implementation {
@@ -313,6 +601,11 @@ func (s *service) startJob(f domain.Feed) error {
 			s.log.Error().Err(err).Msg("feed.startJob: failed to initialize rss feed")
 			return err
 		}
+	case string(domain.FeedTypeAPI):
+		if err := s.addAPIJob(fi); err != nil {
+			s.log.Error().Err(err).Msg("feed.startJob: failed to initialize api feed")
+			return err
+		}
 	}
 
 	return nil
@@ -329,11 +622,20 @@ func (s *service) addTorznabJob(f feedInstance) error {
 	// setup logger
 	l := s.log.With().Str("feed", f.Name).Logger()
 
+	transport, err := sharedhttp.Transport(f.Proxy)
+	if err != nil {
+		return errors.Wrap(err, "invalid proxy for feed: %v", f.Name)
+	}
+
 	// setup torznab Client
-	c := torznab.NewClient(torznab.Config{Host: f.URL, ApiKey: f.ApiKey})
+	c := torznab.NewClient(torznab.Config{Host: f.URL, ApiKey: f.ApiKey, Transport: transport})
 
 	// create job
 	job := NewTorznabJob(f.Name, f.IndexerIdentifier, l, f.URL, c, s.cacheRepo, s.releaseSvc)
+	job.backoff = s.backoff
+	job.status = newFeedStatus(l, s.repo, f.ID)
+	job.Backfill = f.Backfill
+	job.Proxy = f.Proxy
 
 	// schedule job
 	id, err := s.scheduler.AddJob(job, f.CronSchedule, f.IndexerIdentifier)
@@ -374,6 +676,13 @@ func (s *service) addRSSJob(f feedInstance) error {
 
 	// create job
 	job := NewRSSJob(f.Name, f.IndexerIdentifier, l, f.URL, s.cacheRepo, s.releaseSvc)
+	job.Cookie = f.Cookie
+	job.Headers = f.Headers
+	job.Username = f.Username
+	job.Password = f.Password
+	job.Proxy = f.Proxy
+	job.backoff = s.backoff
+	job.status = newFeedStatus(l, s.repo, f.ID)
 
 	// schedule job
 	id, err := s.scheduler.AddJob(job, f.CronSchedule, f.IndexerIdentifier)
@@ -400,3 +709,48 @@ func (s *service) stopRSSJob(indexer string) error {
 
 	return nil
 }
+
+func (s *service) addAPIJob(f feedInstance) error {
+	if f.URL == "" {
+		return errors.New("api feed requires URL")
+	}
+	if f.CronSchedule < time.Duration(5*time.Minute) {
+		f.CronSchedule = time.Duration(15 * time.Minute)
+	}
+
+	// setup logger
+	l := s.log.With().Str("feed", f.Name).Logger()
+
+	// create job
+	job := NewAPIJob(f.Name, f.IndexerIdentifier, l, f.URL, f.APIMapping, s.cacheRepo, s.releaseSvc)
+	job.Cookie = f.Cookie
+	job.Headers = f.Headers
+	job.Proxy = f.Proxy
+	job.backoff = s.backoff
+	job.status = newFeedStatus(l, s.repo, f.ID)
+
+	// schedule job
+	id, err := s.scheduler.AddJob(job, f.CronSchedule, f.IndexerIdentifier)
+	if err != nil {
+		return errors.Wrap(err, "feed.AddAPIJob: add job failed")
+	}
+	job.JobID = id
+
+	// add to job map
+	s.jobs[f.IndexerIdentifier] = id
+
+	s.log.Debug().Msgf("feed.AddAPIJob: %v", f.Name)
+
+	return nil
+}
+
+func (s *service) stopAPIJob(indexer string) error {
+	// remove job from scheduler
+	if err := s.scheduler.RemoveJobByIdentifier(indexer); err != nil {
+		return errors.Wrap(err, "feed.stopAPIJob: stop job failed")
+	}
+
+	s.log.Debug().Msgf("feed.stopAPIJob: %v", indexer)
+
+	return nil
+}
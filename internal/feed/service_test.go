@@ -0,0 +1,91 @@
+package feed
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/internal/filter"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockFeedRepo struct {
+	domain.FeedRepo
+	feed *domain.Feed
+}
+
+func (m *mockFeedRepo) FindByID(ctx context.Context, id int) (*domain.Feed, error) {
+	return m.feed, nil
+}
+
+type mockFilterService struct {
+	filter.Service
+}
+
+// DryRun matches episode 2 and rejects everything else
+func (m *mockFilterService) DryRun(ctx context.Context, release *domain.Release) ([]domain.FilterTestResult, error) {
+	res := domain.FilterTestResult{FilterID: 1, FilterName: "episodes", Rejections: []string{}, Skipped: []string{}}
+
+	if strings.Contains(release.TorrentName, "S01E02") {
+		res.Match = true
+	} else {
+		res.Rejections = append(res.Rejections, "episodes not matching. got: 1 want: 2")
+	}
+
+	return []domain.FilterTestResult{res}, nil
+}
+
+func TestService_DryRun(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(apiFeed))
+	}))
+	defer ts.Close()
+
+	feed := &domain.Feed{
+		ID:      1,
+		Name:    "feed",
+		Indexer: "mock",
+		Type:    string(domain.FeedTypeAPI),
+		URL:     ts.URL,
+		Settings: map[string]string{
+			"items":        "data.torrents",
+			"guid":         "id",
+			"title":        "name",
+			"download_url": "https://tracker.test/download.php?id={{ .id }}",
+			"size":         "size",
+		},
+	}
+
+	cache := &mockFeedCache{keys: map[string]struct{}{}}
+
+	s := &service{
+		log:       zerolog.Nop(),
+		repo:      &mockFeedRepo{feed: feed},
+		cacheRepo: cache,
+		filterSvc: &mockFilterService{},
+	}
+
+	res, err := s.DryRun(context.Background(), feed.ID)
+	assert.NoError(t, err)
+
+	if assert.Len(t, res.Items, 3) {
+		assert.Equal(t, "That.Show.S01E02.1080p.WEB-DL-GROUP", res.Items[0].Title)
+		assert.Equal(t, []string{"episodes"}, res.Items[0].Matched)
+
+		assert.Equal(t, "That.Show.S01E01.1080p.WEB-DL-GROUP", res.Items[1].Title)
+		assert.Empty(t, res.Items[1].Matched)
+		assert.Equal(t, []string{"episodes not matching. got: 1 want: 2"}, res.Items[1].Filters[0].Rejections)
+
+		// the item without a title is reported instead of failing the dry run
+		assert.NotEmpty(t, res.Items[2].Error)
+	}
+
+	// the items are still new for the next poll
+	assert.Empty(t, cache.keys)
+}
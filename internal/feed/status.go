@@ -0,0 +1,43 @@
+package feed
+
+import (
+	"context"
+	"time"
+
+	"github.com/autobrr/autobrr/internal/domain"
+
+	"github.com/rs/zerolog"
+)
+
+// feedStatus records the result of each poll so fetch errors and the last success show up in the UI
+type feedStatus struct {
+	log  zerolog.Logger
+	repo domain.FeedRepo
+	id   int
+}
+
+func newFeedStatus(log zerolog.Logger, repo domain.FeedRepo, id int) *feedStatus {
+	return &feedStatus{
+		log:  log,
+		repo: repo,
+		id:   id,
+	}
+}
+
+func (s *feedStatus) update(runErr error) {
+	if s == nil {
+		return
+	}
+
+	var lastError string
+	if runErr != nil {
+		lastError = runErr.Error()
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if err := s.repo.UpdateLastRun(ctx, s.id, lastError); err != nil {
+		s.log.Error().Err(err).Msg("could not update feed status")
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/internal/metrics"
 	"github.com/autobrr/autobrr/internal/release"
 	"github.com/autobrr/autobrr/pkg/errors"
 	"github.com/autobrr/autobrr/pkg/torznab"
@@ -21,8 +22,16 @@ type TorznabJob struct {
 	Repo              domain.FeedCacheRepo
 	ReleaseSvc        release.Service
 
+	// Backfill is the number of extra pages to fetch when a page only has unseen items
+	Backfill int
+
+	// Proxy of the indexer, torrent downloads go through it as well
+	Proxy string
+
 	attempts int
 	errors   []error
+	backoff  *pollBackoff
+	status   *feedStatus
 
 	JobID int
 }
@@ -40,7 +49,12 @@ func NewTorznabJob(name string, indexerIdentifier string, log zerolog.Logger, ur
 }
 
 func (j *TorznabJob) Run() {
+	if j.backoff.Skip(j.Name) {
+		return
+	}
+
 	err := j.process()
+	j.status.update(err)
 	if err != nil {
 		j.Log.Err(err).Int("attempts", j.attempts).Msg("torznab process error")
 
@@ -53,8 +67,12 @@ func (j *TorznabJob) Run() {
 
 func (j *TorznabJob) process() error {
 	// get feed
+	start := time.Now()
+
 	items, err := j.getFeed()
+	metrics.FeedFetchDuration.Observe(time.Since(start).Seconds(), j.Name)
 	if err != nil {
+		metrics.FeedFetchErrors.Inc(j.Name)
 		j.Log.Error().Err(err).Msgf("error fetching feed items")
 		return errors.Wrap(err, "error getting feed items")
 	}
@@ -65,48 +83,77 @@ func (j *TorznabJob) process() error {
 		return nil
 	}
 
-	releases := make([]*domain.Release, 0)
+	releases := make([]feedRelease, 0, len(items))
 
 	for _, item := range items {
-		rls := domain.NewRelease(j.IndexerIdentifier)
+		releases = append(releases, feedRelease{release: j.itemRelease(item), key: item.GUID})
+	}
 
-		rls.TorrentName = item.Title
-		rls.TorrentURL = item.Link
-		rls.Implementation = domain.ReleaseImplementationTorznab
+	// process all new releases, the job is skipped while this waits on a full queue
+	queueReleases(j.Log, j.Name, j.Repo, j.ReleaseSvc, releases)
 
-		// parse size bytes string
-		rls.ParseSizeBytesString(item.Size)
+	return nil
+}
 
-		rls.ParseString(item.Title)
+func (j *TorznabJob) itemRelease(item torznab.FeedItem) *domain.Release {
+	rls := domain.NewRelease(j.IndexerIdentifier)
+	rls.Proxy = j.Proxy
 
-		releases = append(releases, rls)
-	}
+	rls.TorrentName = item.Title
+	rls.TorrentURL = item.Link
+	rls.Implementation = domain.ReleaseImplementationTorznab
 
-	// process all new releases
-	go j.ReleaseSvc.ProcessMultiple(releases)
+	// parse size bytes string
+	rls.ParseSizeBytesString(item.Size)
 
-	return nil
+	rls.ParseString(item.Title)
+
+	return rls
 }
 
 func (j *TorznabJob) getFeed() ([]torznab.FeedItem, error) {
-	// get feed
-	feedItems, err := j.Client.GetFeed()
-	if err != nil {
-		j.Log.Error().Err(err).Msgf("error fetching feed items")
-		return nil, errors.Wrap(err, "error fetching feed items")
-	}
+	items := make([]torznab.FeedItem, 0)
 
-	j.Log.Debug().Msgf("refreshing feed: %v, found (%d) items", j.Name, len(feedItems))
+	offset := 0
 
-	items := make([]torznab.FeedItem, 0)
-	if len(feedItems) == 0 {
-		return items, nil
+	// keep paging back until we reach items from the previous poll or run out of backfill pages
+	for page := 0; page <= j.Backfill; page++ {
+		feedItems, err := j.Client.GetFeedPage(offset, 0)
+		if err != nil {
+			j.Log.Error().Err(err).Msgf("error fetching feed items")
+			return nil, errors.Wrap(err, "error fetching feed items")
+		}
+
+		j.Log.Debug().Msgf("refreshing feed: %v page: %d offset: %d, found (%d) items", j.Name, page, offset, len(feedItems))
+
+		if len(feedItems) == 0 {
+			break
+		}
+
+		newItems, seen := j.newItems(feedItems)
+		items = append(items, newItems...)
+
+		if seen {
+			break
+		}
+
+		offset += len(feedItems)
 	}
 
-	sort.SliceStable(feedItems, func(i, j int) bool {
-		return feedItems[i].PubDate.After(feedItems[j].PubDate.Time)
+	sort.SliceStable(items, func(i, j int) bool {
+		return items[i].PubDate.After(items[j].PubDate.Time)
 	})
 
+	// send to filters
+	return items, nil
+}
+
+// newItems returns the items not in the feed cache, they are cached once their release is queued.
+// seen is true if any of the items was already in the cache.
+func (j *TorznabJob) newItems(feedItems []torznab.FeedItem) ([]torznab.FeedItem, bool) {
+	items := make([]torznab.FeedItem, 0)
+	seen := false
+
 	for _, i := range feedItems {
 		if i.GUID == "" {
 			continue
@@ -119,21 +166,12 @@ func (j *TorznabJob) getFeed() ([]torznab.FeedItem, error) {
 		}
 		if exists {
 			j.Log.Trace().Msgf("cache item exists, skipping release: %v", i.Title)
+			seen = true
 			continue
 		}
 
-		// set ttl to 1 month
-		ttl := time.Now().AddDate(0, 1, 0)
-
-		if err := j.Repo.Put(j.Name, i.GUID, []byte(i.Title), ttl); err != nil {
-			j.Log.Error().Stack().Err(err).Str("guid", i.GUID).Msg("cache.Put: error storing item in cache")
-			continue
-		}
-
-		// only append if we successfully added to cache
 		items = append(items, i)
 	}
 
-	// send to filters
-	return items, nil
+	return items, seen
 }
@@ -0,0 +1,140 @@
+package feed
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/pkg/torznab"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockTorznabClient struct {
+	torznab.Client
+	items   []torznab.FeedItem
+	pages   int
+	offsets []int
+}
+
+func (m *mockTorznabClient) GetFeedPage(offset int, limit int) ([]torznab.FeedItem, error) {
+	m.offsets = append(m.offsets, offset)
+
+	if offset >= len(m.items) {
+		return nil, nil
+	}
+
+	end := offset + m.pages
+	if end > len(m.items) {
+		end = len(m.items)
+	}
+
+	return m.items[offset:end], nil
+}
+
+type mockFeedCache struct {
+	domain.FeedCacheRepo
+	keys map[string]struct{}
+}
+
+func (m *mockFeedCache) Exists(bucket string, key string) (bool, error) {
+	_, ok := m.keys[key]
+	return ok, nil
+}
+
+func (m *mockFeedCache) Put(bucket string, key string, val []byte, ttl time.Time) error {
+	m.keys[key] = struct{}{}
+	return nil
+}
+
+func (m *mockFeedCache) Delete(ctx context.Context, bucket string, key string) error {
+	delete(m.keys, key)
+	return nil
+}
+
+func newFeedItems(n int) []torznab.FeedItem {
+	now := time.Now()
+
+	items := make([]torznab.FeedItem, 0, n)
+	for i := 0; i < n; i++ {
+		items = append(items, torznab.FeedItem{
+			Title:   fmt.Sprintf("item %d", i),
+			GUID:    fmt.Sprintf("guid-%d", i),
+			PubDate: torznab.Time{Time: now.Add(-time.Duration(i) * time.Minute)},
+		})
+	}
+
+	return items
+}
+
+func TestTorznabJob_getFeed(t *testing.T) {
+	tests := []struct {
+		name        string
+		backfill    int
+		cached      []string
+		wantItems   int
+		wantOffsets []int
+	}{
+		{
+			name:        "no_backfill",
+			backfill:    0,
+			wantItems:   10,
+			wantOffsets: []int{0},
+		},
+		{
+			name:        "backfill_first_run",
+			backfill:    2,
+			wantItems:   30,
+			wantOffsets: []int{0, 10, 20},
+		},
+		{
+			name:        "backfill_until_seen",
+			backfill:    5,
+			cached:      []string{"guid-14"},
+			wantItems:   19,
+			wantOffsets: []int{0, 10},
+		},
+		{
+			name:        "backfill_end_of_feed",
+			backfill:    10,
+			wantItems:   45,
+			wantOffsets: []int{0, 10, 20, 30, 40, 45},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &mockTorznabClient{items: newFeedItems(45), pages: 10}
+			cache := &mockFeedCache{keys: map[string]struct{}{}}
+			for _, key := range tt.cached {
+				cache.keys[key] = struct{}{}
+			}
+
+			j := NewTorznabJob("feed", "mock", zerolog.Nop(), "", client, cache, nil)
+			j.Backfill = tt.backfill
+
+			items, err := j.getFeed()
+			assert.NoError(t, err)
+			assert.Len(t, items, tt.wantItems)
+			assert.Equal(t, tt.wantOffsets, client.offsets)
+
+			// items are sorted newest first
+			for i := 1; i < len(items); i++ {
+				assert.False(t, items[i].PubDate.After(items[i-1].PubDate.Time))
+			}
+
+			// items are cached once queued, a second run only sees cached items
+			for _, item := range items {
+				cache.keys[item.GUID] = struct{}{}
+			}
+
+			client.offsets = nil
+			items, err = j.getFeed()
+			assert.NoError(t, err)
+			assert.Len(t, items, 0)
+			assert.Equal(t, []int{0}, client.offsets)
+		})
+	}
+}
@@ -0,0 +1,49 @@
+package filter
+
+import (
+	"context"
+
+	"github.com/autobrr/autobrr/internal/domain"
+)
+
+// auditSnapshot returns the filter with its actions and indexers to diff for the audit log, nil if it can't be found
+func (s *service) auditSnapshot(ctx context.Context, filterID int) *domain.Filter {
+	if s.auditSvc == nil {
+		return nil
+	}
+
+	f, err := s.FindByID(ctx, filterID)
+	if err != nil {
+		s.log.Warn().Err(err).Msgf("could not find filter %v for audit log", filterID)
+		return nil
+	}
+
+	return f
+}
+
+// auditCreated records a new filter
+func (s *service) auditCreated(ctx context.Context, filterID int) {
+	if after := s.auditSnapshot(ctx, filterID); after != nil {
+		s.auditSvc.Record(ctx, domain.AuditEntityFilter, after.ID, after.Name, nil, after)
+	}
+}
+
+// auditUpdated records the changes since the before snapshot
+func (s *service) auditUpdated(ctx context.Context, before *domain.Filter) {
+	if before == nil {
+		return
+	}
+
+	if after := s.auditSnapshot(ctx, before.ID); after != nil {
+		s.auditSvc.Record(ctx, domain.AuditEntityFilter, after.ID, after.Name, before, after)
+	}
+}
+
+// auditDeleted records a deleted filter with the values it had
+func (s *service) auditDeleted(ctx context.Context, before *domain.Filter) {
+	if before == nil {
+		return
+	}
+
+	s.auditSvc.Record(ctx, domain.AuditEntityFilter, before.ID, before.Name, before, nil)
+}
@@ -0,0 +1,134 @@
+package filter
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/binary"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/pkg/errors"
+
+	"golang.org/x/net/http2"
+)
+
+// defaultGRPCMethod is called when the host has no method path
+const defaultGRPCMethod = "/autobrr.ExternalFilter/Check"
+
+// grpcCheck does a unary gRPC call using the json codec (application/grpc+json).
+// The host is grpc://host:port/package.Service/Method for plaintext or grpcs:// for TLS,
+// the request message is the parsed webhook data and the reply an ExternalFilterResponse.
+func grpcCheck(ctx context.Context, host string, data []byte) (*domain.ExternalFilterResponse, error) {
+	u, err := url.Parse(host)
+	if err != nil {
+		return nil, errors.Wrap(err, "grpc: could not parse host: %v", host)
+	}
+
+	t := &http2.Transport{}
+
+	switch u.Scheme {
+	case "grpc":
+		// plaintext http2 with prior knowledge
+		t.AllowHTTP = true
+		t.DialTLSContext = func(ctx context.Context, network, addr string, _ *tls.Config) (net.Conn, error) {
+			var d net.Dialer
+			return d.DialContext(ctx, network, addr)
+		}
+		u.Scheme = "http"
+
+	case "grpcs":
+		t.TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
+		u.Scheme = "https"
+
+	default:
+		return nil, errors.New("grpc: unsupported scheme %q, use grpc:// or grpcs://", u.Scheme)
+	}
+
+	if u.Path == "" || u.Path == "/" {
+		u.Path = defaultGRPCMethod
+	}
+
+	// length prefixed message: compressed flag, big endian length, payload
+	frame := make([]byte, 5+len(data))
+	binary.BigEndian.PutUint32(frame[1:5], uint32(len(data)))
+	copy(frame[5:], data)
+
+	ctx, cancel := context.WithTimeout(ctx, 15*time.Second)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, u.String(), bytes.NewReader(frame))
+	if err != nil {
+		return nil, errors.Wrap(err, "grpc: could not build request")
+	}
+
+	req.Header.Set("Content-Type", "application/grpc+json")
+	req.Header.Set("TE", "trailers")
+	req.Header.Set("User-Agent", "autobrr")
+
+	res, err := (&http.Client{Transport: t}).Do(req)
+	if err != nil {
+		return nil, errors.Wrap(err, "grpc: could not make request to: %v", u.Host)
+	}
+
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return nil, errors.New("grpc: unexpected http status: %d", res.StatusCode)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(res.Body, maxWebhookResponseSize))
+	if err != nil {
+		return nil, errors.Wrap(err, "grpc: could not read response")
+	}
+
+	// trailers are only populated after the body is read, trailers-only responses use the headers
+	status := res.Trailer.Get("Grpc-Status")
+	message := res.Trailer.Get("Grpc-Message")
+	if status == "" {
+		status = res.Header.Get("Grpc-Status")
+		message = res.Header.Get("Grpc-Message")
+	}
+
+	if status != "0" {
+		if decoded, err := url.PathUnescape(message); err == nil {
+			message = decoded
+		}
+		return nil, errors.New("grpc: call failed with status %v: %v", status, message)
+	}
+
+	msg, err := grpcMessage(body)
+	if err != nil {
+		return nil, err
+	}
+
+	var response domain.ExternalFilterResponse
+	if err := json.Unmarshal(msg, &response); err != nil {
+		return nil, errors.Wrap(err, "grpc: could not unmarshal response")
+	}
+
+	return &response, nil
+}
+
+// grpcMessage reads the first length prefixed message from a response body
+func grpcMessage(body []byte) ([]byte, error) {
+	if len(body) < 5 {
+		return nil, errors.New("grpc: response too short")
+	}
+
+	if body[0] != 0 {
+		return nil, errors.New("grpc: compressed responses are not supported")
+	}
+
+	length := binary.BigEndian.Uint32(body[1:5])
+	if uint32(len(body)-5) < length {
+		return nil, errors.New("grpc: truncated response")
+	}
+
+	return body[5 : 5+length], nil
+}
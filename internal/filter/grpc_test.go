@@ -0,0 +1,133 @@
+package filter
+
+import (
+	"context"
+	"encoding/binary"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/autobrr/autobrr/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/h2c"
+)
+
+func grpcFrame(msg string) []byte {
+	frame := make([]byte, 5+len(msg))
+	binary.BigEndian.PutUint32(frame[1:5], uint32(len(msg)))
+	copy(frame[5:], msg)
+	return frame
+}
+
+func Test_grpcCheck(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/autobrr.ExternalFilter/Check", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "application/grpc+json", r.Header.Get("Content-Type"))
+
+		body, _ := io.ReadAll(r.Body)
+		msg, err := grpcMessage(body)
+		assert.NoError(t, err)
+
+		w.Header().Set("Content-Type", "application/grpc+json")
+		w.Header().Set("Trailer", "Grpc-Status, Grpc-Message")
+
+		if strings.Contains(string(msg), "rejected") {
+			w.Write(grpcFrame(`{"approved":false,"reason":"already have it"}`))
+		} else {
+			w.Write(grpcFrame(`{"approved":true}`))
+		}
+
+		w.Header().Set("Grpc-Status", "0")
+	})
+	mux.HandleFunc("/custom.Service/Fail", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/grpc+json")
+		w.Header().Set("Grpc-Status", "12")
+		w.Header().Set("Grpc-Message", "method%20not%20implemented")
+	})
+
+	ts := httptest.NewServer(h2c.NewHandler(mux, &http2.Server{}))
+	defer ts.Close()
+
+	host := strings.Replace(ts.URL, "http://", "grpc://", 1)
+
+	tests := []struct {
+		name    string
+		host    string
+		data    string
+		want    *domain.ExternalFilterResponse
+		wantErr bool
+	}{
+		{
+			name: "approved",
+			host: host,
+			data: `{"release":"approved"}`,
+			want: &domain.ExternalFilterResponse{Approved: true},
+		},
+		{
+			name: "rejected",
+			host: host + "/autobrr.ExternalFilter/Check",
+			data: `{"release":"rejected"}`,
+			want: &domain.ExternalFilterResponse{Approved: false, Reason: "already have it"},
+		},
+		{
+			name:    "status_error",
+			host:    host + "/custom.Service/Fail",
+			data:    `{}`,
+			wantErr: true,
+		},
+		{
+			name:    "bad_scheme",
+			host:    ts.URL,
+			data:    `{}`,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := grpcCheck(context.Background(), tt.host, []byte(tt.data))
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_checkExternalResponse(t *testing.T) {
+	tests := []struct {
+		name           string
+		res            domain.ExternalFilterResponse
+		want           bool
+		wantRejections []string
+	}{
+		{
+			name: "approved",
+			res:  domain.ExternalFilterResponse{Approved: true},
+			want: true,
+		},
+		{
+			name:           "rejected_with_reason",
+			res:            domain.ExternalFilterResponse{Reason: "bad group"},
+			wantRejections: []string{"external webhook rejected: bad group"},
+		},
+		{
+			name:           "rejected",
+			res:            domain.ExternalFilterResponse{},
+			wantRejections: []string{"external webhook rejected"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			release := &domain.Release{}
+			assert.Equal(t, tt.want, checkExternalResponse(release, "external webhook", &tt.res))
+			assert.Equal(t, tt.wantRejections, release.Rejections)
+		})
+	}
+}
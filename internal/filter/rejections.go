@@ -0,0 +1,124 @@
+package filter
+
+import (
+	"context"
+	"time"
+
+	"github.com/autobrr/autobrr/internal/domain"
+)
+
+const (
+	rejectionFlushInterval = time.Minute
+
+	// rejectionRetention is how long rejection counts are kept and the longest window that can be queried
+	rejectionRetention = 30 * 24 * time.Hour
+)
+
+type rejectionKey struct {
+	filterID int
+	reason   string
+	bucket   time.Time
+}
+
+// Start periodically writes the counted rejections to the database and deletes counts past the retention
+func (s *service) Start() {
+	go func() {
+		ticker := time.NewTicker(rejectionFlushInterval)
+		defer ticker.Stop()
+
+		var lastPrune time.Time
+
+		for range ticker.C {
+			ctx := context.Background()
+
+			s.flushRejections(ctx)
+
+			if time.Since(lastPrune) >= time.Hour {
+				if _, err := s.repo.DeleteRejectionCounts(ctx, s.now().Add(-rejectionRetention)); err != nil {
+					s.log.Error().Err(err).Msg("could not delete old filter rejection counts")
+				}
+				lastPrune = time.Now()
+			}
+		}
+	}()
+}
+
+// RecordRejections counts the reasons a release was rejected by a filter, each reason is counted once per release
+func (s *service) RecordRejections(filterID int, rejections []string) {
+	if len(rejections) == 0 {
+		return
+	}
+
+	bucket := s.now().UTC().Truncate(time.Hour)
+
+	s.rejectionsMu.Lock()
+	defer s.rejectionsMu.Unlock()
+
+	seen := make(map[string]struct{}, len(rejections))
+	for _, rejection := range rejections {
+		reason := domain.RejectionReason(rejection)
+		if _, ok := seen[reason]; ok {
+			continue
+		}
+		seen[reason] = struct{}{}
+
+		s.rejections[rejectionKey{filterID: filterID, reason: reason, bucket: bucket}]++
+	}
+}
+
+func (s *service) flushRejections(ctx context.Context) {
+	s.rejectionsMu.Lock()
+	pending := s.rejections
+	s.rejections = map[rejectionKey]int{}
+	s.rejectionsMu.Unlock()
+
+	if len(pending) == 0 {
+		return
+	}
+
+	counts := make([]domain.FilterRejectionCount, 0, len(pending))
+	for key, count := range pending {
+		counts = append(counts, domain.FilterRejectionCount{
+			FilterID: key.filterID,
+			Reason:   key.reason,
+			Bucket:   key.bucket,
+			Count:    count,
+		})
+	}
+
+	if err := s.repo.StoreRejectionCounts(ctx, counts); err != nil {
+		s.log.Error().Err(err).Msgf("could not store %d filter rejection counts", len(counts))
+	}
+}
+
+// RejectionStats returns the rejections of a filter per reason within the window, longer windows are cut to the retention
+func (s *service) RejectionStats(ctx context.Context, filterID int, window time.Duration) (*domain.FilterRejectionStats, error) {
+	if window > rejectionRetention {
+		window = rejectionRetention
+	}
+
+	// include the rejections counted since the last flush
+	s.flushRejections(ctx)
+
+	now := s.now()
+	from := now.Add(-window).UTC().Truncate(time.Hour)
+
+	reasons, err := s.repo.GetRejectionReasons(ctx, filterID, from)
+	if err != nil {
+		s.log.Error().Err(err).Msgf("could not get rejection reasons for filter: %v", filterID)
+		return nil, err
+	}
+
+	stats := &domain.FilterRejectionStats{
+		FilterID: filterID,
+		From:     from,
+		To:       now,
+		Reasons:  reasons,
+	}
+
+	for _, reason := range reasons {
+		stats.Total += reason.Count
+	}
+
+	return stats, nil
+}
@@ -0,0 +1,62 @@
+package filter
+
+import (
+	"context"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/autobrr/autobrr/internal/domain"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockRejectionRepo struct {
+	domain.FilterRepo
+
+	stored []domain.FilterRejectionCount
+}
+
+func (r *mockRejectionRepo) StoreRejectionCounts(ctx context.Context, counts []domain.FilterRejectionCount) error {
+	r.stored = append(r.stored, counts...)
+	return nil
+}
+
+func Test_service_RecordRejections(t *testing.T) {
+	now := time.Date(2022, 10, 14, 12, 30, 0, 0, time.UTC)
+	repo := &mockRejectionRepo{}
+
+	s := &service{
+		log:        zerolog.Nop(),
+		repo:       repo,
+		now:        func() time.Time { return now },
+		rejections: map[rejectionKey]int{},
+	}
+
+	s.RecordRejections(1, []string{"size not matching. got: 10 GB want min: 1 GB max: 5 GB", "size: larger than max size", "wanted: freeleech"})
+	s.RecordRejections(1, []string{"size: larger than max size"})
+	s.RecordRejections(2, []string{"resolution not matching. got: 720p want: [1080p]"})
+	s.RecordRejections(2, nil)
+
+	s.flushRejections(context.Background())
+
+	sort.Slice(repo.stored, func(i, j int) bool {
+		if repo.stored[i].FilterID != repo.stored[j].FilterID {
+			return repo.stored[i].FilterID < repo.stored[j].FilterID
+		}
+		return repo.stored[i].Reason < repo.stored[j].Reason
+	})
+
+	bucket := time.Date(2022, 10, 14, 12, 0, 0, 0, time.UTC)
+	assert.Equal(t, []domain.FilterRejectionCount{
+		{FilterID: 1, Reason: "freeleech", Bucket: bucket, Count: 1},
+		{FilterID: 1, Reason: "size", Bucket: bucket, Count: 2},
+		{FilterID: 2, Reason: "resolution", Bucket: bucket, Count: 1},
+	}, repo.stored)
+
+	// nothing left to flush
+	repo.stored = nil
+	s.flushRejections(context.Background())
+	assert.Empty(t, repo.stored)
+}
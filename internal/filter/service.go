@@ -4,16 +4,22 @@ import (
 	"bytes"
 	"context"
 	"crypto/tls"
+	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
 	"os"
 	"os/exec"
 	"strings"
+	"sync"
 	"time"
 
+	"github.com/autobrr/autobrr/internal/audit"
 	"github.com/autobrr/autobrr/internal/domain"
 	"github.com/autobrr/autobrr/internal/indexer"
+	"github.com/autobrr/autobrr/internal/list"
 	"github.com/autobrr/autobrr/internal/logger"
+	"github.com/autobrr/autobrr/internal/quota"
 	"github.com/autobrr/autobrr/pkg/errors"
 
 	"github.com/dustin/go-humanize"
@@ -26,13 +32,24 @@ type Service interface {
 	FindByIndexerIdentifier(indexer string) ([]domain.Filter, error)
 	Find(ctx context.Context, params domain.FilterQueryParams) ([]domain.Filter, error)
 	CheckFilter(f domain.Filter, release *domain.Release) (bool, error)
+	Test(ctx context.Context, req domain.FilterTestRequest) (*domain.FilterTestResponse, error)
+	DryRun(ctx context.Context, release *domain.Release) ([]domain.FilterTestResult, error)
+	DryRunFilter(ctx context.Context, f domain.Filter, release *domain.Release) (domain.FilterTestResult, error)
 	ListFilters(ctx context.Context) ([]domain.Filter, error)
 	Store(ctx context.Context, filter domain.Filter) (*domain.Filter, error)
 	Update(ctx context.Context, filter domain.Filter) (*domain.Filter, error)
 	UpdatePartial(ctx context.Context, filter domain.FilterUpdate) error
 	Duplicate(ctx context.Context, filterID int) (*domain.Filter, error)
+	DuplicateBulk(ctx context.Context, filterID int, duplicate domain.FilterBulkDuplicate) ([]*domain.Filter, error)
+	Export(ctx context.Context, filterID int) (*domain.FilterExport, error)
+	Import(ctx context.Context, export domain.FilterExport, conflict domain.FilterImportConflict) (*domain.FilterImportResult, error)
 	ToggleEnabled(ctx context.Context, filterID int, enabled bool) error
+	UpdateBulk(ctx context.Context, update domain.FilterBulkUpdate) error
 	Delete(ctx context.Context, filterID int) error
+	DeleteBulk(ctx context.Context, del domain.FilterBulkDelete) error
+	Start()
+	RecordRejections(filterID int, rejections []string)
+	RejectionStats(ctx context.Context, filterID int, window time.Duration) (*domain.FilterRejectionStats, error)
 }
 
 type service struct {
@@ -41,16 +58,44 @@ type service struct {
 	actionRepo domain.ActionRepo
 	indexerSvc indexer.Service
 	apiService indexer.APIService
+	quotaSvc   quota.Service
+	listSvc    list.Service
+	auditSvc   audit.Service
+
+	// location filter schedules are evaluated in
+	location *time.Location
+	now      func() time.Time
+
+	// rejections counted per hour, written to the database periodically
+	rejectionsMu sync.Mutex
+	rejections   map[rejectionKey]int
 }
 
-func NewService(log logger.Logger, repo domain.FilterRepo, actionRepo domain.ActionRepo, apiService indexer.APIService, indexerSvc indexer.Service) Service {
-	return &service{
+func NewService(log logger.Logger, config *domain.Config, repo domain.FilterRepo, actionRepo domain.ActionRepo, apiService indexer.APIService, indexerSvc indexer.Service, quotaSvc quota.Service, listSvc list.Service, auditSvc audit.Service) Service {
+	s := &service{
 		log:        log.With().Str("module", "filter").Logger(),
 		repo:       repo,
 		actionRepo: actionRepo,
 		apiService: apiService,
 		indexerSvc: indexerSvc,
+		quotaSvc:   quotaSvc,
+		listSvc:    listSvc,
+		auditSvc:   auditSvc,
+		location:   time.Local,
+		now:        time.Now,
+		rejections: map[rejectionKey]int{},
+	}
+
+	if config.Timezone != "" {
+		location, err := time.LoadLocation(config.Timezone)
+		if err != nil {
+			s.log.Warn().Err(err).Msgf("invalid timezone %q, filter schedules use the system timezone", config.Timezone)
+		} else {
+			s.location = location
+		}
 	}
+
+	return s
 }
 
 func (s *service) Find(ctx context.Context, params domain.FilterQueryParams) ([]domain.Filter, error) {
@@ -132,11 +177,29 @@ func (s *service) FindByIndexerIdentifier(indexer string) ([]domain.Filter, erro
 		return nil, err
 	}
 
+	for i := range filters {
+		if filters[i].CapturePatterns == "" {
+			continue
+		}
+
+		// compile once per lookup so every check of the release reuses the patterns
+		if err := filters[i].CompileCapturePatterns(); err != nil {
+			s.log.Warn().Err(err).Msgf("filter %v has invalid capture patterns", filters[i].Name)
+		}
+	}
+
 	return filters, nil
 }
 
 func (s *service) Store(ctx context.Context, filter domain.Filter) (*domain.Filter, error) {
 	// validate data
+	if _, err := domain.ParseFilterSchedule(filter.Schedule); err != nil {
+		return nil, errors.Wrap(err, "validation: invalid schedule")
+	}
+
+	if err := domain.ValidateCapturePatterns(filter.CapturePatterns); err != nil {
+		return nil, err
+	}
 
 	// store
 	f, err := s.repo.Store(ctx, filter)
@@ -145,6 +208,8 @@ func (s *service) Store(ctx context.Context, filter domain.Filter) (*domain.Filt
 		return nil, err
 	}
 
+	s.auditCreated(ctx, f.ID)
+
 	return f, nil
 }
 
@@ -154,6 +219,24 @@ func (s *service) Update(ctx context.Context, filter domain.Filter) (*domain.Fil
 		return nil, errors.New("validation: name can't be empty")
 	}
 
+	if _, err := domain.ParseFilterSchedule(filter.Schedule); err != nil {
+		return nil, errors.Wrap(err, "validation: invalid schedule")
+	}
+
+	if err := domain.ValidateCapturePatterns(filter.CapturePatterns); err != nil {
+		return nil, err
+	}
+
+	if err := validateActionConditions(filter.Actions); err != nil {
+		return nil, err
+	}
+
+	if err := s.validateActionDependencies(ctx, filter.ID, filter.Actions); err != nil {
+		return nil, err
+	}
+
+	before := s.auditSnapshot(ctx, filter.ID)
+
 	// update
 	f, err := s.repo.Update(ctx, filter)
 	if err != nil {
@@ -176,10 +259,35 @@ func (s *service) Update(ctx context.Context, filter domain.Filter) (*domain.Fil
 
 	f.Actions = actions
 
+	s.auditUpdated(ctx, before)
+
 	return f, nil
 }
 
 func (s *service) UpdatePartial(ctx context.Context, filter domain.FilterUpdate) error {
+	if filter.Schedule != nil {
+		if _, err := domain.ParseFilterSchedule(*filter.Schedule); err != nil {
+			return errors.Wrap(err, "validation: invalid schedule")
+		}
+	}
+
+	if filter.CapturePatterns != nil {
+		if err := domain.ValidateCapturePatterns(*filter.CapturePatterns); err != nil {
+			return err
+		}
+	}
+
+	if filter.Actions != nil {
+		if err := validateActionConditions(filter.Actions); err != nil {
+			return err
+		}
+
+		if err := s.validateActionDependencies(ctx, filter.ID, filter.Actions); err != nil {
+			return err
+		}
+	}
+
+	before := s.auditSnapshot(ctx, filter.ID)
 
 	// update
 	if err := s.repo.UpdatePartial(ctx, filter); err != nil {
@@ -203,6 +311,38 @@ func (s *service) UpdatePartial(ctx context.Context, filter domain.FilterUpdate)
 		}
 	}
 
+	s.auditUpdated(ctx, before)
+
+	return nil
+}
+
+// validateActionDependencies makes sure the actions of a filter do not introduce a dependency cycle between filters
+func (s *service) validateActionDependencies(ctx context.Context, filterID int, actions []*domain.Action) error {
+	deps, err := s.actionRepo.FindFilterDependencies(ctx)
+	if err != nil {
+		s.log.Error().Err(err).Msgf("could not find filter dependencies: %v", filterID)
+		return err
+	}
+
+	if cycle := deps.WithFilterActions(filterID, actions).FindCycle(); cycle != nil {
+		return errors.New("validation: action dependencies form a cycle between filters: %v", cycle)
+	}
+
+	return nil
+}
+
+// validateActionConditions makes sure the run conditions of the actions can be evaluated
+func validateActionConditions(actions []*domain.Action) error {
+	for _, a := range actions {
+		if a == nil {
+			continue
+		}
+
+		if err := a.ValidateRunCondition(); err != nil {
+			return errors.Wrap(err, "validation")
+		}
+	}
+
 	return nil
 }
 
@@ -212,8 +352,40 @@ func (s *service) Duplicate(ctx context.Context, filterID int) (*domain.Filter,
 	if err != nil {
 		return nil, err
 	}
+
+	return s.duplicate(ctx, *baseFilter, fmt.Sprintf("%v Copy", baseFilter.Name))
+}
+
+// DuplicateBulk makes several disabled copies of a filter with its indexers and actions
+func (s *service) DuplicateBulk(ctx context.Context, filterID int, duplicate domain.FilterBulkDuplicate) ([]*domain.Filter, error) {
+	if err := duplicate.Validate(); err != nil {
+		return nil, err
+	}
+
+	baseFilter, err := s.repo.FindByID(ctx, filterID)
+	if err != nil {
+		return nil, err
+	}
+
+	filters := make([]*domain.Filter, 0, duplicate.Count)
+
+	for _, name := range duplicate.Names(baseFilter.Name) {
+		filter, err := s.duplicate(ctx, *baseFilter, name)
+		if err != nil {
+			return filters, err
+		}
+
+		filters = append(filters, filter)
+	}
+
+	return filters, nil
+}
+
+func (s *service) duplicate(ctx context.Context, baseFilter domain.Filter, name string) (*domain.Filter, error) {
+	filterID := baseFilter.ID
+
 	baseFilter.ID = 0
-	baseFilter.Name = fmt.Sprintf("%v Copy", baseFilter.Name)
+	baseFilter.Name = name
 	baseFilter.Enabled = false
 
 	// find actions and attach
@@ -231,7 +403,7 @@ func (s *service) Duplicate(ctx context.Context, filterID int) (*domain.Filter,
 	}
 
 	// update
-	filter, err := s.repo.Store(ctx, *baseFilter)
+	filter, err := s.repo.Store(ctx, baseFilter)
 	if err != nil {
 		s.log.Error().Err(err).Msgf("could not update filter: %v", baseFilter.Name)
 		return nil, err
@@ -253,25 +425,316 @@ func (s *service) Duplicate(ctx context.Context, filterID int) (*domain.Filter,
 
 	filter.Actions = actions
 
+	s.auditCreated(ctx, filter.ID)
+
 	return filter, nil
 }
 
+// Export returns a portable copy of the filter with its actions and indexers
+func (s *service) Export(ctx context.Context, filterID int) (*domain.FilterExport, error) {
+	filter, err := s.repo.FindByID(ctx, filterID)
+	if err != nil {
+		return nil, err
+	}
+
+	actions, err := s.actionRepo.FindByFilterID(ctx, filterID)
+	if err != nil {
+		s.log.Error().Err(err).Msgf("could not find filter actions: %v", filterID)
+		return nil, err
+	}
+
+	indexers, err := s.indexerSvc.FindByFilterID(ctx, filterID)
+	if err != nil {
+		s.log.Error().Err(err).Msgf("could not find indexers for filter: %v", filter.Name)
+		return nil, err
+	}
+
+	return domain.NewFilterExport(*filter, actions, indexers)
+}
+
+// Import stores an exported filter. If a filter with the same name exists the conflict mode decides what happens.
+func (s *service) Import(ctx context.Context, export domain.FilterExport, conflict domain.FilterImportConflict) (*domain.FilterImportResult, error) {
+	if err := export.Validate(); err != nil {
+		return nil, err
+	}
+
+	if !conflict.Valid() {
+		return nil, errors.New("validation: invalid conflict mode: %v", conflict)
+	}
+
+	filter, err := export.ToFilter()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := domain.ValidateCapturePatterns(filter.CapturePatterns); err != nil {
+		return nil, err
+	}
+
+	actions, err := export.ToActions()
+	if err != nil {
+		return nil, err
+	}
+
+	indexers, missing, err := s.importIndexers(ctx, export.Indexers)
+	if err != nil {
+		return nil, err
+	}
+
+	filters, err := s.repo.ListFilters(ctx)
+	if err != nil {
+		s.log.Error().Err(err).Msg("could not list filters")
+		return nil, err
+	}
+
+	var existing *domain.Filter
+	for i := range filters {
+		if strings.EqualFold(filters[i].Name, filter.Name) {
+			existing = &filters[i]
+			break
+		}
+	}
+
+	result := &domain.FilterImportResult{MissingIndexers: missing}
+
+	if existing == nil {
+		if result.Filter, err = s.storeImported(ctx, *filter, indexers, actions); err != nil {
+			return nil, err
+		}
+
+		return result, nil
+	}
+
+	result.Conflict = conflict
+
+	switch conflict {
+	case domain.FilterImportConflictRename:
+		filter.Name = importName(filter.Name, filters)
+
+		if result.Filter, err = s.storeImported(ctx, *filter, indexers, actions); err != nil {
+			return nil, err
+		}
+
+	case domain.FilterImportConflictOverwrite:
+		filter.ID = existing.ID
+		filter.Name = existing.Name
+		filter.Indexers = indexers
+		filter.Actions = actions
+
+		result.Filter, err = s.Update(ctx, *filter)
+		if err != nil {
+			return nil, err
+		}
+		result.Filter.Indexers = indexers
+
+	case domain.FilterImportConflictMerge:
+		update, err := export.ToFilterUpdate(existing.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		currentIndexers, err := s.indexerSvc.FindByFilterID(ctx, existing.ID)
+		if err != nil {
+			s.log.Error().Err(err).Msgf("could not find indexers for filter: %v", existing.Name)
+			return nil, err
+		}
+		update.Indexers = mergeIndexers(currentIndexers, indexers)
+
+		currentActions, err := s.actionRepo.FindByFilterID(ctx, existing.ID)
+		if err != nil {
+			s.log.Error().Err(err).Msgf("could not find filter actions: %v", existing.ID)
+			return nil, err
+		}
+		update.Actions = mergeActions(currentActions, actions)
+
+		if err := s.UpdatePartial(ctx, *update); err != nil {
+			return nil, err
+		}
+
+		result.Filter, err = s.FindByID(ctx, existing.ID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	s.log.Info().Msgf("filter.import: imported filter '%v' (%v)", result.Filter.Name, conflict)
+
+	return result, nil
+}
+
+func (s *service) storeImported(ctx context.Context, filter domain.Filter, indexers []domain.Indexer, actions []*domain.Action) (*domain.Filter, error) {
+	f, err := s.Store(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	// take care of connected indexers
+	if err := s.repo.StoreIndexerConnections(ctx, f.ID, indexers); err != nil {
+		s.log.Error().Err(err).Msgf("could not store filter indexer connections: %v", f.Name)
+		return nil, err
+	}
+	f.Indexers = indexers
+
+	// take care of filter actions
+	if f.Actions, err = s.actionRepo.StoreFilterActions(ctx, actions, int64(f.ID)); err != nil {
+		s.log.Error().Err(err).Msgf("could not store filter actions: %v", f.Name)
+		return nil, err
+	}
+
+	return f, nil
+}
+
+// importIndexers looks up the exported indexer identifiers and returns the ones not set up here
+func (s *service) importIndexers(ctx context.Context, identifiers []string) ([]domain.Indexer, []string, error) {
+	if len(identifiers) == 0 {
+		return []domain.Indexer{}, nil, nil
+	}
+
+	all, err := s.indexerSvc.List(ctx)
+	if err != nil {
+		s.log.Error().Err(err).Msg("could not list indexers")
+		return nil, nil, err
+	}
+
+	indexers := make([]domain.Indexer, 0, len(identifiers))
+	var missing []string
+
+	for _, identifier := range identifiers {
+		found := false
+		for _, indexer := range all {
+			if indexer.Identifier == identifier {
+				indexers = append(indexers, indexer)
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			missing = append(missing, identifier)
+		}
+	}
+
+	return indexers, missing, nil
+}
+
+// importName returns the first free name of the form "name (n)"
+func importName(name string, filters []domain.Filter) string {
+	taken := make(map[string]struct{}, len(filters))
+	for _, f := range filters {
+		taken[strings.ToLower(f.Name)] = struct{}{}
+	}
+
+	for i := 2; ; i++ {
+		candidate := fmt.Sprintf("%v (%d)", name, i)
+		if _, ok := taken[strings.ToLower(candidate)]; !ok {
+			return candidate
+		}
+	}
+}
+
+func mergeIndexers(current []domain.Indexer, imported []domain.Indexer) []domain.Indexer {
+	merged := append([]domain.Indexer{}, current...)
+
+	for _, indexer := range imported {
+		found := false
+		for _, c := range current {
+			if c.ID == indexer.ID {
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			merged = append(merged, indexer)
+		}
+	}
+
+	return merged
+}
+
+// mergeActions keeps the current actions and adds imported ones with a name not in use
+func mergeActions(current []*domain.Action, imported []*domain.Action) []*domain.Action {
+	merged := append([]*domain.Action{}, current...)
+
+	for _, action := range imported {
+		found := false
+		for _, c := range current {
+			if strings.EqualFold(c.Name, action.Name) {
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			merged = append(merged, action)
+		}
+	}
+
+	return merged
+}
+
 func (s *service) ToggleEnabled(ctx context.Context, filterID int, enabled bool) error {
+	before := s.auditSnapshot(ctx, filterID)
+
 	if err := s.repo.ToggleEnabled(ctx, filterID, enabled); err != nil {
 		s.log.Error().Err(err).Msg("could not update filter enabled")
 		return err
 	}
 
+	s.auditUpdated(ctx, before)
+
 	s.log.Debug().Msgf("filter.toggle_enabled: update filter '%v' to '%v'", filterID, enabled)
 
 	return nil
 }
 
+// UpdateBulk sets the enabled state and/or priority of several filters
+func (s *service) UpdateBulk(ctx context.Context, update domain.FilterBulkUpdate) error {
+	if err := update.Validate(); err != nil {
+		return err
+	}
+
+	before := make([]*domain.Filter, 0, len(update.IDs))
+	for _, id := range update.IDs {
+		before = append(before, s.auditSnapshot(ctx, id))
+	}
+
+	if err := s.repo.UpdateBulk(ctx, update); err != nil {
+		s.log.Error().Err(err).Msgf("could not update filters: %v", update.IDs)
+		return err
+	}
+
+	for _, f := range before {
+		s.auditUpdated(ctx, f)
+	}
+
+	s.log.Debug().Msgf("filter.update_bulk: updated filters %v", update.IDs)
+
+	return nil
+}
+
+// DeleteBulk deletes several filters with their actions and indexer connections
+func (s *service) DeleteBulk(ctx context.Context, del domain.FilterBulkDelete) error {
+	if err := del.Validate(); err != nil {
+		return err
+	}
+
+	for _, id := range del.IDs {
+		if err := s.Delete(ctx, id); err != nil {
+			return errors.Wrap(err, "could not delete filter: %v", id)
+		}
+	}
+
+	return nil
+}
+
 func (s *service) Delete(ctx context.Context, filterID int) error {
 	if filterID == 0 {
 		return nil
 	}
 
+	before := s.auditSnapshot(ctx, filterID)
+
 	// take care of filter actions
 	if err := s.actionRepo.DeleteByFilterID(ctx, filterID); err != nil {
 		s.log.Error().Err(err).Msg("could not delete filter actions")
@@ -290,6 +753,8 @@ func (s *service) Delete(ctx context.Context, filterID int) error {
 		return err
 	}
 
+	s.auditDeleted(ctx, before)
+
 	return nil
 }
 
@@ -298,6 +763,8 @@ func (s *service) CheckFilter(f domain.Filter, release *domain.Release) (bool, e
 	s.log.Trace().Msgf("filter.Service.CheckFilter: checking filter: %v %+v", f.Name, f)
 	s.log.Trace().Msgf("filter.Service.CheckFilter: checking filter: %v for release: %+v", f.Name, release)
 
+	s.setListTitles(&f)
+
 	rejections, matchedFilter := f.CheckFilter(release)
 	if len(rejections) > 0 {
 		s.log.Debug().Msgf("filter.Service.CheckFilter: (%v) for release: %v rejections: (%v)", f.Name, release.TorrentName, release.RejectionsString())
@@ -305,6 +772,11 @@ func (s *service) CheckFilter(f domain.Filter, release *domain.Release) (bool, e
 	}
 
 	if matchedFilter {
+		if !s.scheduleActive(f, release) {
+			s.log.Debug().Msgf("filter.Service.CheckFilter: (%v) outside of schedule: %v", f.Name, f.Schedule)
+			return false, nil
+		}
+
 		// if matched, do additional size check if needed, attach actions and return the filter
 
 		s.log.Debug().Msgf("filter.Service.CheckFilter: found and matched filter: %+v", f.Name)
@@ -329,6 +801,33 @@ func (s *service) CheckFilter(f domain.Filter, release *domain.Release) (bool, e
 			}
 		}
 
+		// download the torrent file to check the actual size and files, announced sizes are often missing or wrong
+		if f.TorrentFileCheck {
+			ok, err := s.torrentFileCheck(f, release)
+			if err != nil {
+				s.log.Error().Err(err).Msgf("filter.Service.CheckFilter: (%v) torrent file check error", f.Name)
+				return false, err
+			}
+
+			if !ok {
+				s.log.Debug().Msgf("filter.Service.CheckFilter: (%v) torrent file check rejected: %v", f.Name, release.RejectionsString())
+				return false, nil
+			}
+		}
+
+		// check download quotas for filter and indexer
+		rejection, ok, err := s.quotaSvc.Check(context.TODO(), f.ID, release.Indexer, release.Size)
+		if err != nil {
+			s.log.Error().Err(err).Msgf("filter.Service.CheckFilter: (%v) quota check error", f.Name)
+			return false, err
+		}
+
+		if !ok {
+			s.log.Trace().Msgf("filter.Service.CheckFilter: (%v) %v", f.Name, rejection)
+			release.AddRejectionF("%v", rejection)
+			return false, nil
+		}
+
 		// run external script
 		if f.ExternalScriptEnabled && f.ExternalScriptCmd != "" {
 			exitCode, err := s.execCmd(release, f.ExternalScriptCmd, f.ExternalScriptArgs)
@@ -346,16 +845,13 @@ func (s *service) CheckFilter(f domain.Filter, release *domain.Release) (bool, e
 
 		// run external webhook
 		if f.ExternalWebhookEnabled && f.ExternalWebhookHost != "" && f.ExternalWebhookData != "" {
-			// run external scripts
-			statusCode, err := s.webhook(release, f.ExternalWebhookHost, f.ExternalWebhookData)
+			ok, err := s.externalWebhookCheck(f, release)
 			if err != nil {
 				s.log.Error().Err(err).Msgf("filter.Service.CheckFilter: error executing external webhook for filter: %v", f.Name)
 				return false, err
 			}
 
-			if statusCode != f.ExternalWebhookExpectStatus {
-				s.log.Trace().Msgf("filter.Service.CheckFilter: external webhook unexpected status code. got: %v want: %v", statusCode, f.ExternalWebhookExpectStatus)
-				release.AddRejectionF("external webhook unexpected status code. got: %v want: %v", statusCode, f.ExternalWebhookExpectStatus)
+			if !ok {
 				return false, nil
 			}
 		}
@@ -381,6 +877,138 @@ func (s *service) CheckFilter(f domain.Filter, release *domain.Release) (bool, e
 	return false, nil
 }
 
+// Test parses an announce or release name and checks it against the filters of the indexer without running
+// actions or any check that downloads files or calls external services.
+func (s *service) Test(ctx context.Context, req domain.FilterTestRequest) (*domain.FilterTestResponse, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	var release *domain.Release
+
+	if lines := req.AnnounceLines(); len(lines) > 0 {
+		def, err := s.indexerSvc.GetMappedDefinitionByName(req.Indexer)
+		if err != nil {
+			return nil, err
+		}
+
+		release, err = def.ParseAnnounce(lines)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not parse announce")
+		}
+	} else {
+		release = domain.NewRelease(req.Indexer)
+		release.TorrentName = strings.TrimSpace(req.ReleaseName)
+		release.ParseString(release.TorrentName)
+	}
+
+	results, err := s.DryRun(ctx, release)
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.FilterTestResponse{
+		Release: release,
+		Filters: results,
+	}, nil
+}
+
+// DryRun checks the release against the filters of its indexer and reports the outcome per filter.
+// Actions are not run and checks that download files or call external services are skipped.
+func (s *service) DryRun(ctx context.Context, release *domain.Release) ([]domain.FilterTestResult, error) {
+	filters, err := s.repo.FindByIndexerIdentifier(release.Indexer)
+	if err != nil {
+		s.log.Error().Err(err).Msgf("could not find filters for indexer: %v", release.Indexer)
+		return nil, err
+	}
+
+	results := make([]domain.FilterTestResult, 0, len(filters))
+
+	for _, f := range filters {
+		result, err := s.DryRunFilter(ctx, f, release)
+		if err != nil {
+			return nil, err
+		}
+
+		results = append(results, result)
+	}
+
+	// rejections belong to the last checked filter, they are reported per filter instead
+	release.Rejections = []string{}
+
+	return results, nil
+}
+
+// DryRunFilter checks the release against a single filter like DryRun does
+func (s *service) DryRunFilter(ctx context.Context, f domain.Filter, release *domain.Release) (domain.FilterTestResult, error) {
+	s.setListTitles(&f)
+
+	rejections, match := f.CheckFilter(release)
+
+	result := domain.FilterTestResult{
+		FilterID:   f.ID,
+		FilterName: f.Name,
+		Priority:   f.Priority,
+		Match:      match && len(rejections) == 0,
+		Rejections: append([]string{}, rejections...),
+		Skipped:    []string{},
+	}
+
+	if result.Match && !s.scheduleActive(f, release) {
+		result.Match = false
+		result.Rejections = append([]string{}, release.Rejections...)
+	}
+
+	if result.Match {
+		result.Skipped = f.DryRunSkippedChecks(release)
+
+		rejection, ok, err := s.quotaSvc.Check(ctx, f.ID, release.Indexer, release.Size)
+		if err != nil {
+			return result, err
+		}
+
+		if !ok {
+			result.Match = false
+			result.Rejections = append(result.Rejections, rejection)
+		}
+	}
+
+	return result, nil
+}
+
+// setListTitles loads the titles of the lists the filter matches or excludes
+func (s *service) setListTitles(f *domain.Filter) {
+	if f.MatchListID > 0 {
+		f.MatchListTitles = s.listSvc.Titles(f.MatchListID)
+	}
+
+	if f.ExceptListID > 0 {
+		f.ExceptListTitles = s.listSvc.Titles(f.ExceptListID)
+	}
+}
+
+// scheduleActive checks the filter schedule against the current time in the configured timezone
+// and adds a rejection to the release when outside of it.
+func (s *service) scheduleActive(f domain.Filter, release *domain.Release) bool {
+	if f.Schedule == "" {
+		return true
+	}
+
+	schedule, err := domain.ParseFilterSchedule(f.Schedule)
+	if err != nil {
+		// schedules are validated on save, an invalid one is treated as always active
+		s.log.Warn().Err(err).Msgf("filter.Service.CheckFilter: (%v) invalid schedule: %v", f.Name, f.Schedule)
+		return true
+	}
+
+	if !schedule.Active(s.now().In(s.location)) {
+		release.AddRejectionF("outside filter schedule: %v", f.Schedule)
+		return false
+	}
+
+	return true
+}
+
 // AdditionalSizeCheck
 // Some indexers do not announce the size and if size (min,max) is set in a filter then it will need
 // additional size check. Some indexers have api implemented to fetch this data and for the others
@@ -431,6 +1059,37 @@ func (s *service) AdditionalSizeCheck(f domain.Filter, release *domain.Release)
 	return true, nil
 }
 
+// torrentFileCheck checks the size and files of the torrent against the filter.
+// A torrent file that was already downloaded for the release is reused.
+func (s *service) torrentFileCheck(f domain.Filter, release *domain.Release) (bool, error) {
+	if release.TorrentTmpFile != "" {
+		if len(release.Files) == 0 {
+			if err := release.ParseTorrentFile(); err != nil {
+				return false, errors.Wrap(err, "could not read torrent file: %v", release.TorrentTmpFile)
+			}
+		}
+
+		return f.CheckTorrentFiles(release), nil
+	}
+
+	if release.TorrentURL == "" {
+		release.AddRejectionF("files: no torrent url to check")
+		return false, nil
+	}
+
+	// magnet metadata can only be fetched from peers, leave those to the download client
+	if strings.HasPrefix(release.TorrentURL, "magnet:") {
+		release.AddRejectionF("files: can't check magnet links")
+		return false, nil
+	}
+
+	if err := release.DownloadTorrentFile(); err != nil {
+		return false, errors.Wrap(err, "could not download torrent file with id: '%v' from: %v", release.TorrentID, release.Indexer)
+	}
+
+	return f.CheckTorrentFiles(release), nil
+}
+
 func checkSizeFilter(minSize string, maxSize string, releaseSize uint64) (bool, error) {
 	// handle both min and max
 	if minSize != "" {
@@ -524,11 +1183,69 @@ func (s *service) execCmd(release *domain.Release, cmd string, args string) (int
 	return 0, nil
 }
 
-func (s *service) webhook(release *domain.Release, url string, data string) (int, error) {
+// maxWebhookResponseSize caps how much of an external check response is read
+const maxWebhookResponseSize = 1 << 20
+
+// externalWebhookCheck runs the external webhook or gRPC check and adds a rejection if it does not approve the release
+func (s *service) externalWebhookCheck(f domain.Filter, release *domain.Release) (bool, error) {
+	data, err := s.webhookData(release, f.ExternalWebhookData)
+	if err != nil {
+		return false, err
+	}
+
+	if f.ExternalWebhookType == domain.FilterWebhookTypeGRPC {
+		res, err := grpcCheck(context.TODO(), f.ExternalWebhookHost, []byte(data))
+		if err != nil {
+			return false, err
+		}
+
+		return checkExternalResponse(release, "external grpc", res), nil
+	}
+
+	statusCode, body, err := s.webhook(f.ExternalWebhookHost, data)
+	if err != nil {
+		return false, err
+	}
+
+	if statusCode != f.ExternalWebhookExpectStatus {
+		s.log.Trace().Msgf("filter.Service.CheckFilter: external webhook unexpected status code. got: %v want: %v", statusCode, f.ExternalWebhookExpectStatus)
+		release.AddRejectionF("external webhook unexpected status code. got: %v want: %v", statusCode, f.ExternalWebhookExpectStatus)
+		return false, nil
+	}
+
+	if f.ExternalWebhookParseBody {
+		var res domain.ExternalFilterResponse
+		if err := json.Unmarshal(body, &res); err != nil {
+			return false, errors.Wrap(err, "could not parse external webhook response: %s", string(body))
+		}
+
+		return checkExternalResponse(release, "external webhook", &res), nil
+	}
+
+	return true, nil
+}
+
+// checkExternalResponse adds the reason from a typed response as rejection
+func checkExternalResponse(release *domain.Release, source string, res *domain.ExternalFilterResponse) bool {
+	if res.Approved {
+		return true
+	}
+
+	if res.Reason != "" {
+		release.AddRejectionF("%v rejected: %v", source, res.Reason)
+	} else {
+		release.AddRejectionF("%v rejected", source)
+	}
+
+	return false
+}
+
+// webhookData downloads the torrent if needed and parses the data macro
+func (s *service) webhookData(release *domain.Release, data string) (string, error) {
 	// if webhook data contains TorrentPathName or TorrentDataRawBytes, lets download the torrent file
 	if release.TorrentTmpFile == "" && (strings.Contains(data, "TorrentPathName") || strings.Contains(data, "TorrentDataRawBytes")) {
 		if err := release.DownloadTorrentFile(); err != nil {
-			return 0, errors.Wrap(err, "webhook: could not download torrent file for release: %v", release.TorrentName)
+			return "", errors.Wrap(err, "webhook: could not download torrent file for release: %v", release.TorrentName)
 		}
 	}
 
@@ -536,7 +1253,7 @@ func (s *service) webhook(release *domain.Release, url string, data string) (int
 	if len(release.TorrentDataRawBytes) == 0 && strings.Contains(data, "TorrentDataRawBytes") {
 		t, err := os.ReadFile(release.TorrentTmpFile)
 		if err != nil {
-			return 0, errors.Wrap(err, "could not read torrent file: %v", release.TorrentTmpFile)
+			return "", errors.Wrap(err, "could not read torrent file: %v", release.TorrentTmpFile)
 		}
 
 		release.TorrentDataRawBytes = t
@@ -547,9 +1264,13 @@ func (s *service) webhook(release *domain.Release, url string, data string) (int
 	// parse and replace values in argument string before continuing
 	dataArgs, err := m.Parse(data)
 	if err != nil {
-		return 0, errors.Wrap(err, "could not parse webhook data macro: %v", data)
+		return "", errors.Wrap(err, "could not parse webhook data macro: %v", data)
 	}
 
+	return dataArgs, nil
+}
+
+func (s *service) webhook(url string, data string) (int, []byte, error) {
 	t := &http.Transport{
 		TLSClientConfig: &tls.Config{
 			InsecureSkipVerify: true,
@@ -558,9 +1279,9 @@ func (s *service) webhook(release *domain.Release, url string, data string) (int
 
 	client := http.Client{Transport: t, Timeout: 15 * time.Second}
 
-	req, err := http.NewRequest(http.MethodPost, url, bytes.NewBufferString(dataArgs))
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewBufferString(data))
 	if err != nil {
-		return 0, errors.Wrap(err, "could not build request for webhook")
+		return 0, nil, errors.Wrap(err, "could not build request for webhook")
 	}
 
 	req.Header.Set("Content-Type", "application/json")
@@ -568,16 +1289,21 @@ func (s *service) webhook(release *domain.Release, url string, data string) (int
 
 	res, err := client.Do(req)
 	if err != nil {
-		return 0, errors.Wrap(err, "could not make request for webhook")
+		return 0, nil, errors.Wrap(err, "could not make request for webhook")
 	}
 
 	defer res.Body.Close()
 
+	body, err := io.ReadAll(io.LimitReader(res.Body, maxWebhookResponseSize))
+	if err != nil {
+		return 0, nil, errors.Wrap(err, "could not read webhook response")
+	}
+
 	if res.StatusCode > 299 {
-		return res.StatusCode, nil
+		return res.StatusCode, body, nil
 	}
 
-	s.log.Debug().Msgf("successfully ran external webhook filter to: (%v) payload: (%v)", url, dataArgs)
+	s.log.Debug().Msgf("successfully ran external webhook filter to: (%v) payload: (%v)", url, data)
 
-	return res.StatusCode, nil
+	return res.StatusCode, body, nil
 }
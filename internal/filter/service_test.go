@@ -1,6 +1,21 @@
 package filter
 
-import "testing"
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/internal/quota"
+
+	"github.com/anacrolix/torrent/bencode"
+	"github.com/stretchr/testify/assert"
+)
 
 func Test_checkSizeFilter(t *testing.T) {
 	type args struct {
@@ -36,3 +51,138 @@ func Test_checkSizeFilter(t *testing.T) {
 		})
 	}
 }
+
+func Test_importName(t *testing.T) {
+	filters := []domain.Filter{{Name: "Movies"}, {Name: "movies (2)"}}
+
+	assert.Equal(t, "Movies (3)", importName("Movies", filters))
+	assert.Equal(t, "TV (2)", importName("TV", filters))
+}
+
+func Test_mergeActions(t *testing.T) {
+	current := []*domain.Action{{ID: 1, Name: "qbit", Enabled: true}}
+	imported := []*domain.Action{{Name: "QBIT"}, {Name: "webhook"}}
+
+	merged := mergeActions(current, imported)
+
+	assert.Len(t, merged, 2)
+	assert.Equal(t, 1, merged[0].ID)
+	assert.Equal(t, "webhook", merged[1].Name)
+}
+
+func Test_service_scheduleActive(t *testing.T) {
+	stockholm, err := time.LoadLocation("Europe/Stockholm")
+	if err != nil {
+		t.Skip("timezone data not available")
+	}
+
+	// friday 21:30 UTC is 23:30 in stockholm
+	now := time.Date(2022, 10, 14, 21, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		location *time.Location
+		schedule string
+		want     bool
+	}{
+		{name: "no_schedule", location: time.UTC, schedule: "", want: true},
+		{name: "utc_outside", location: time.UTC, schedule: "fri 22:00-06:00", want: false},
+		{name: "timezone_inside", location: stockholm, schedule: "fri 22:00-06:00", want: true},
+		{name: "timezone_outside", location: stockholm, schedule: "fri 18:00-22:00", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &service{
+				location: tt.location,
+				now:      func() time.Time { return now },
+			}
+
+			release := domain.NewRelease("mock")
+
+			assert.Equal(t, tt.want, s.scheduleActive(domain.Filter{Name: "test", Schedule: tt.schedule}, release))
+			if !tt.want {
+				assert.Equal(t, []string{"outside filter schedule: " + tt.schedule}, release.Rejections)
+			}
+		})
+	}
+}
+
+func Test_service_torrentFileCheck_reusesTmpFile(t *testing.T) {
+	info, err := bencode.Marshal(map[string]interface{}{
+		"name":         "That.Movie.2022.mkv",
+		"piece length": 16384,
+		"length":       1024,
+		"pieces":       string(bytes.Repeat([]byte{0xaa}, 20)),
+	})
+	assert.NoError(t, err)
+
+	torrent, err := bencode.Marshal(map[string]interface{}{"announce": "http://tracker.test/announce", "info": bencode.Bytes(info)})
+	assert.NoError(t, err)
+
+	path := filepath.Join(t.TempDir(), "release.torrent")
+	assert.NoError(t, os.WriteFile(path, torrent, 0644))
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("torrent file downloaded again")
+	}))
+	defer srv.Close()
+
+	s := &service{}
+
+	release := &domain.Release{TorrentURL: srv.URL, TorrentTmpFile: path}
+	ok, err := s.torrentFileCheck(domain.Filter{MatchFileExtensions: "mkv"}, release)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, []domain.ReleaseFile{{Path: "That.Movie.2022.mkv", Size: 1024}}, release.Files)
+
+	// magnet links are fine once the torrent file is known
+	release = &domain.Release{TorrentURL: "magnet:?xt=urn:btih:aaaa", TorrentTmpFile: path}
+	ok, err = s.torrentFileCheck(domain.Filter{ExceptFileExtensions: "mkv"}, release)
+	assert.NoError(t, err)
+	assert.False(t, ok)
+	assert.Equal(t, []string{"files: extension in except file extensions: That.Movie.2022.mkv"}, release.Rejections)
+}
+
+type mockQuotaService struct {
+	quota.Service
+}
+
+func (s mockQuotaService) Check(ctx context.Context, filterID int, indexer string, size uint64) (string, bool, error) {
+	return "", true, nil
+}
+
+func Test_service_DryRunFilter_noExternalChecks(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("external webhook called in a dry run")
+	}))
+	defer srv.Close()
+
+	ran := filepath.Join(t.TempDir(), "ran")
+
+	s := &service{quotaSvc: mockQuotaService{}, location: time.UTC, now: time.Now}
+
+	f := domain.Filter{
+		ID:                     1,
+		Name:                   "movies",
+		Enabled:                true,
+		TorrentFileCheck:       true,
+		ExternalScriptEnabled:  true,
+		ExternalScriptCmd:      "touch",
+		ExternalScriptArgs:     ran,
+		ExternalWebhookEnabled: true,
+		ExternalWebhookHost:    srv.URL,
+	}
+
+	release := domain.NewRelease("mock")
+	release.TorrentName = "That.Movie.2022.1080p.BluRay.x264-GROUP"
+	release.TorrentURL = srv.URL
+	release.ParseString(release.TorrentName)
+
+	result, err := s.DryRunFilter(context.Background(), f, release)
+	assert.NoError(t, err)
+	assert.True(t, result.Match)
+	assert.Equal(t, []string{"torrent file check", "external script", "external webhook"}, result.Skipped)
+
+	_, err = os.Stat(ran)
+	assert.True(t, os.IsNotExist(err), "external script ran in a dry run")
+}
@@ -16,6 +16,10 @@ type actionService interface {
 	Store(ctx context.Context, action domain.Action) (*domain.Action, error)
 	Delete(actionID int) error
 	ToggleEnabled(actionID int) error
+
+	ListBlocklist(ctx context.Context) ([]domain.TorrentBlocklistEntry, error)
+	StoreBlocklistEntry(ctx context.Context, entry *domain.TorrentBlocklistEntry) error
+	DeleteBlocklistEntry(ctx context.Context, id int) error
 }
 
 type actionHandler struct {
@@ -36,6 +40,10 @@ func (h actionHandler) Routes(r chi.Router) {
 	r.Delete("/{id}", h.deleteAction)
 	r.Put("/{id}", h.updateAction)
 	r.Patch("/{id}/toggleEnabled", h.toggleActionEnabled)
+
+	r.Get("/blocklist", h.listBlocklist)
+	r.Post("/blocklist", h.storeBlocklistEntry)
+	r.Delete("/blocklist/{id}", h.deleteBlocklistEntry)
 }
 
 func (h actionHandler) getActions(w http.ResponseWriter, r *http.Request) {
@@ -115,6 +123,60 @@ func (h actionHandler) toggleActionEnabled(w http.ResponseWriter, r *http.Reques
 	h.encoder.StatusResponse(ctx, w, nil, http.StatusCreated)
 }
 
+func (h actionHandler) listBlocklist(w http.ResponseWriter, r *http.Request) {
+	entries, err := h.service.ListBlocklist(r.Context())
+	if err != nil {
+		h.encoder.Error(w, err)
+		return
+	}
+
+	h.encoder.StatusResponse(r.Context(), w, entries, http.StatusOK)
+}
+
+func (h actionHandler) storeBlocklistEntry(w http.ResponseWriter, r *http.Request) {
+	var (
+		data domain.TorrentBlocklistEntry
+		ctx  = r.Context()
+	)
+
+	if err := json.NewDecoder(r.Body).Decode(&data); err != nil {
+		h.encoder.StatusInternalError(w)
+		return
+	}
+
+	if err := data.Validate(); err != nil {
+		h.encoder.StatusResponse(ctx, w, map[string]interface{}{
+			"code":    "BAD_REQUEST_PARAMS",
+			"message": err.Error(),
+		}, http.StatusBadRequest)
+		return
+	}
+
+	if err := h.service.StoreBlocklistEntry(ctx, &data); err != nil {
+		h.encoder.Error(w, err)
+		return
+	}
+
+	h.encoder.StatusResponse(ctx, w, data, http.StatusCreated)
+}
+
+func (h actionHandler) deleteBlocklistEntry(w http.ResponseWriter, r *http.Request) {
+	var ctx = r.Context()
+
+	id, err := parseInt(chi.URLParam(r, "id"))
+	if err != nil {
+		h.encoder.StatusResponse(ctx, w, errors.New("bad param id"), http.StatusBadRequest)
+		return
+	}
+
+	if err := h.service.DeleteBlocklistEntry(ctx, id); err != nil {
+		h.encoder.Error(w, err)
+		return
+	}
+
+	h.encoder.StatusResponse(ctx, w, nil, http.StatusNoContent)
+}
+
 func parseInt(s string) (int, error) {
 	u, err := strconv.ParseInt(s, 10, 64)
 	if err != nil {
@@ -17,6 +17,7 @@ type apikeyService interface {
 	Update(ctx context.Context, key *domain.APIKey) error
 	Delete(ctx context.Context, key string) error
 	ValidateAPIKey(ctx context.Context, token string) bool
+	FindByKey(ctx context.Context, token string) (*domain.APIKey, error)
 }
 
 type apikeyHandler struct {
@@ -34,7 +35,7 @@ func newAPIKeyHandler(encoder encoder, service apikeyService) *apikeyHandler {
 func (h apikeyHandler) Routes(r chi.Router) {
 	r.Get("/", h.list)
 	r.Post("/", h.store)
-	r.Delete("/{apikey}", h.delete)
+	r.Delete("/{apikeyID}", h.delete)
 }
 
 func (h apikeyHandler) list(w http.ResponseWriter, r *http.Request) {
@@ -60,6 +61,14 @@ func (h apikeyHandler) store(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if err := data.Validate(); err != nil {
+		h.encoder.StatusResponse(ctx, w, map[string]interface{}{
+			"code":    "BAD_REQUEST_PARAMS",
+			"message": err.Error(),
+		}, http.StatusBadRequest)
+		return
+	}
+
 	if err := h.service.Store(ctx, &data); err != nil {
 		// encode error
 		h.encoder.StatusInternalError(w)
@@ -70,7 +79,7 @@ func (h apikeyHandler) store(w http.ResponseWriter, r *http.Request) {
 }
 
 func (h apikeyHandler) delete(w http.ResponseWriter, r *http.Request) {
-	if err := h.service.Delete(r.Context(), chi.URLParam(r, "apikey")); err != nil {
+	if err := h.service.Delete(r.Context(), chi.URLParam(r, "apikeyID")); err != nil {
 		h.encoder.StatusInternalError(w)
 		return
 	}
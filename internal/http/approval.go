@@ -0,0 +1,112 @@
+package http
+
+import (
+	"context"
+	"html/template"
+	"net/http"
+	"strconv"
+
+	"github.com/autobrr/autobrr/internal/domain"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type approvalLinkService interface {
+	DecideApprovalWithToken(ctx context.Context, id int, decision domain.ReleaseApprovalDecision, token string) error
+}
+
+// approvalLinkHandler serves the signed approve and reject links of the approval notifications.
+// The links are opened without a session, the token authorizes the single decision.
+type approvalLinkHandler struct {
+	service approvalLinkService
+}
+
+func newApprovalLinkHandler(service approvalLinkService) *approvalLinkHandler {
+	return &approvalLinkHandler{
+		service: service,
+	}
+}
+
+func (h approvalLinkHandler) Routes(r chi.Router) {
+	r.Get("/{approvalID}/{decision}", h.confirm)
+	r.Post("/{approvalID}/{decision}", h.decide)
+}
+
+var approvalPage = template.Must(template.New("approval").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>autobrr</title>
+</head>
+<body style="font-family: sans-serif; max-width: 32rem; margin: 4rem auto; padding: 0 1rem;">
+<p>{{ .Message }}</p>
+{{ if .Confirm }}
+<form method="post">
+<input type="hidden" name="token" value="{{ .Token }}">
+<button type="submit">{{ .Confirm }}</button>
+</form>
+{{ end }}
+</body>
+</html>
+`))
+
+type approvalPageData struct {
+	Message string
+	Confirm string
+	Token   string
+}
+
+// confirm asks before deciding so link previews of chat apps don't approve the release
+func (h approvalLinkHandler) confirm(w http.ResponseWriter, r *http.Request) {
+	decision := domain.ReleaseApprovalDecision(chi.URLParam(r, "decision"))
+	if !decision.Valid() {
+		h.render(w, http.StatusBadRequest, approvalPageData{Message: "Invalid decision."})
+		return
+	}
+
+	confirm := "Approve release"
+	if decision == domain.ReleaseApprovalDecisionReject {
+		confirm = "Reject release"
+	}
+
+	h.render(w, http.StatusOK, approvalPageData{
+		Message: confirm + "?",
+		Confirm: confirm,
+		Token:   r.URL.Query().Get("token"),
+	})
+}
+
+func (h approvalLinkHandler) decide(w http.ResponseWriter, r *http.Request) {
+	decision := domain.ReleaseApprovalDecision(chi.URLParam(r, "decision"))
+	if !decision.Valid() {
+		h.render(w, http.StatusBadRequest, approvalPageData{Message: "Invalid decision."})
+		return
+	}
+
+	id, err := strconv.Atoi(chi.URLParam(r, "approvalID"))
+	if err != nil {
+		h.render(w, http.StatusBadRequest, approvalPageData{Message: "Invalid approval."})
+		return
+	}
+
+	ctx := domain.WithAuditActor(r.Context(), "link")
+
+	if err := h.service.DecideApprovalWithToken(ctx, id, decision, r.FormValue("token")); err != nil {
+		h.render(w, http.StatusBadRequest, approvalPageData{Message: "Could not " + string(decision) + " release: " + err.Error()})
+		return
+	}
+
+	message := "Release approved, the actions are running."
+	if decision == domain.ReleaseApprovalDecisionReject {
+		message = "Release rejected."
+	}
+
+	h.render(w, http.StatusOK, approvalPageData{Message: message})
+}
+
+func (h approvalLinkHandler) render(w http.ResponseWriter, status int, data approvalPageData) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(status)
+	approvalPage.Execute(w, data)
+}
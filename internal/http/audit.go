@@ -0,0 +1,74 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	"github.com/autobrr/autobrr/internal/domain"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type auditService interface {
+	Find(ctx context.Context, params domain.AuditQueryParams) ([]domain.AuditEntry, error)
+}
+
+type auditHandler struct {
+	encoder encoder
+	service auditService
+}
+
+func newAuditHandler(encoder encoder, service auditService) *auditHandler {
+	return &auditHandler{
+		encoder: encoder,
+		service: service,
+	}
+}
+
+func (h auditHandler) Routes(r chi.Router) {
+	r.Get("/", h.find)
+}
+
+func (h auditHandler) find(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	query := r.URL.Query()
+
+	params := domain.AuditQueryParams{
+		EntityType: domain.AuditEntityType(query.Get("entity_type")),
+		EntityID:   query.Get("entity_id"),
+		Actor:      query.Get("actor"),
+	}
+
+	if limitP := query.Get("limit"); limitP != "" {
+		limit, err := strconv.ParseUint(limitP, 10, 64)
+		if err != nil {
+			h.encoder.StatusResponse(ctx, w, map[string]interface{}{
+				"code":    "BAD_REQUEST_PARAMS",
+				"message": "limit parameter is invalid",
+			}, http.StatusBadRequest)
+			return
+		}
+		params.Limit = limit
+	}
+
+	if offsetP := query.Get("offset"); offsetP != "" {
+		offset, err := strconv.ParseUint(offsetP, 10, 64)
+		if err != nil {
+			h.encoder.StatusResponse(ctx, w, map[string]interface{}{
+				"code":    "BAD_REQUEST_PARAMS",
+				"message": "offset parameter is invalid",
+			}, http.StatusBadRequest)
+			return
+		}
+		params.Offset = offset
+	}
+
+	entries, err := h.service.Find(ctx, params)
+	if err != nil {
+		h.encoder.Error(w, err)
+		return
+	}
+
+	h.encoder.StatusResponse(ctx, w, entries, http.StatusOK)
+}
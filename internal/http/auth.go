@@ -2,8 +2,12 @@ package http
 
 import (
 	"context"
+	"crypto/rand"
+	"encoding/base64"
 	"encoding/json"
 	"net/http"
+	"path"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/gorilla/sessions"
@@ -11,10 +15,17 @@ import (
 	"github.com/autobrr/autobrr/internal/domain"
 )
 
+// oidcSessionTTL is how long a started oidc login can take before the state expires
+const oidcSessionTTL = 10 * time.Minute
+
 type authService interface {
 	GetUserCount(ctx context.Context) (int, error)
 	Login(ctx context.Context, username, password string) (*domain.User, error)
 	CreateUser(ctx context.Context, username, password string) error
+
+	OIDCEnabled() bool
+	OIDCAuthURL(ctx context.Context, state, nonce, verifier string) (string, error)
+	OIDCLogin(ctx context.Context, code, verifier, nonce string) (*domain.User, error)
 }
 
 type authHandler struct {
@@ -40,6 +51,10 @@ func (h authHandler) Routes(r chi.Router) {
 	r.Post("/onboard", h.onboard)
 	r.Get("/onboard", h.canOnboard)
 	r.Get("/validate", h.validate)
+
+	r.Get("/oidc/config", h.oidcConfig)
+	r.Get("/oidc/login", h.oidcLogin)
+	r.Get("/oidc/callback", h.oidcCallback)
 }
 
 func (h authHandler) login(w http.ResponseWriter, r *http.Request) {
@@ -54,23 +69,11 @@ func (h authHandler) login(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	h.cookieStore.Options.HttpOnly = true
-	h.cookieStore.Options.SameSite = http.SameSiteLaxMode
-	h.cookieStore.Options.Path = h.config.BaseURL
-
-	// autobrr does not support serving on TLS / https, so this is only available behind reverse proxy
-	// if forwarded protocol is https then set cookie secure
-	// SameSite Strict can only be set with a secure cookie. So we overwrite it here if possible.
-	// https://developer.mozilla.org/en-US/docs/Web/HTTP/Headers/Set-Cookie/SameSite
-	fwdProto := r.Header.Get("X-Forwarded-Proto")
-	if fwdProto == "https" {
-		h.cookieStore.Options.Secure = true
-		h.cookieStore.Options.SameSite = http.SameSiteStrictMode
-	}
+	h.setCookieOptions(r)
 
 	session, _ := h.cookieStore.Get(r, "user_session")
 
-	_, err := h.service.Login(ctx, data.Username, data.Password)
+	user, err := h.service.Login(ctx, data.Username, data.Password)
 	if err != nil {
 		h.encoder.StatusResponse(ctx, w, nil, http.StatusUnauthorized)
 		return
@@ -78,11 +81,28 @@ func (h authHandler) login(w http.ResponseWriter, r *http.Request) {
 
 	// Set user as authenticated
 	session.Values["authenticated"] = true
+	session.Values["username"] = user.Username
 	session.Save(r, w)
 
 	h.encoder.StatusResponse(ctx, w, nil, http.StatusNoContent)
 }
 
+func (h authHandler) setCookieOptions(r *http.Request) {
+	h.cookieStore.Options.HttpOnly = true
+	h.cookieStore.Options.SameSite = http.SameSiteLaxMode
+	h.cookieStore.Options.Path = h.config.BaseURL
+
+	// autobrr does not support serving on TLS / https, so this is only available behind reverse proxy
+	// if forwarded protocol is https then set cookie secure
+	// SameSite Strict can only be set with a secure cookie. So we overwrite it here if possible.
+	// https://developer.mozilla.org/en-US/docs/Web/HTTP/Headers/Set-Cookie/SameSite
+	fwdProto := r.Header.Get("X-Forwarded-Proto")
+	if fwdProto == "https" {
+		h.cookieStore.Options.Secure = true
+		h.cookieStore.Options.SameSite = http.SameSiteStrictMode
+	}
+}
+
 func (h authHandler) logout(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
@@ -155,3 +175,100 @@ func (h authHandler) validate(w http.ResponseWriter, r *http.Request) {
 	// send empty response as ok
 	h.encoder.StatusResponse(ctx, w, nil, http.StatusNoContent)
 }
+
+func (h authHandler) oidcConfig(w http.ResponseWriter, r *http.Request) {
+	h.encoder.StatusResponse(r.Context(), w, map[string]bool{"enabled": h.service.OIDCEnabled()}, http.StatusOK)
+}
+
+// oidcLogin redirects to the identity provider. State, nonce and pkce verifier are kept in a short-lived
+// session of their own, it has to be SameSite Lax to survive the cross-site redirect back to the callback.
+func (h authHandler) oidcLogin(w http.ResponseWriter, r *http.Request) {
+	if !h.service.OIDCEnabled() {
+		http.Error(w, "Not found", http.StatusNotFound)
+		return
+	}
+
+	values := map[string]string{}
+	for _, key := range []string{"state", "nonce", "verifier"} {
+		token, err := randomToken()
+		if err != nil {
+			http.Error(w, "Internal server error", http.StatusInternalServerError)
+			return
+		}
+		values[key] = token
+	}
+
+	authURL, err := h.service.OIDCAuthURL(r.Context(), values["state"], values["nonce"], values["verifier"])
+	if err != nil {
+		http.Error(w, "Bad gateway", http.StatusBadGateway)
+		return
+	}
+
+	session, _ := h.cookieStore.Get(r, "oidc_session")
+	session.Options = &sessions.Options{
+		Path:     h.config.BaseURL,
+		MaxAge:   int(oidcSessionTTL.Seconds()),
+		HttpOnly: true,
+		Secure:   r.Header.Get("X-Forwarded-Proto") == "https",
+		SameSite: http.SameSiteLaxMode,
+	}
+	for key, value := range values {
+		session.Values[key] = value
+	}
+	session.Save(r, w)
+
+	http.Redirect(w, r, authURL, http.StatusFound)
+}
+
+func (h authHandler) oidcCallback(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	if !h.service.OIDCEnabled() {
+		http.Error(w, "Not found", http.StatusNotFound)
+		return
+	}
+
+	loginURL := path.Join(h.config.BaseURL, "login")
+
+	oidcSession, _ := h.cookieStore.Get(r, "oidc_session")
+	state, _ := oidcSession.Values["state"].(string)
+	nonce, _ := oidcSession.Values["nonce"].(string)
+	verifier, _ := oidcSession.Values["verifier"].(string)
+
+	// the state is single use
+	oidcSession.Options = &sessions.Options{Path: h.config.BaseURL, MaxAge: -1}
+	oidcSession.Save(r, w)
+
+	query := r.URL.Query()
+	if state == "" || query.Get("state") != state || query.Get("code") == "" {
+		http.Redirect(w, r, loginURL+"?error=oidc", http.StatusFound)
+		return
+	}
+
+	user, err := h.service.OIDCLogin(ctx, query.Get("code"), verifier, nonce)
+	if err != nil {
+		http.Redirect(w, r, loginURL+"?error=oidc", http.StatusFound)
+		return
+	}
+
+	h.setCookieOptions(r)
+
+	session, _ := h.cookieStore.Get(r, "user_session")
+
+	// Set user as authenticated
+	session.Values["authenticated"] = true
+	session.Values["username"] = user.Username
+	session.Save(r, w)
+
+	http.Redirect(w, r, loginURL, http.StatusFound)
+}
+
+// randomToken returns a url safe random string used for the oidc state, nonce and pkce verifier
+func randomToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
@@ -0,0 +1,84 @@
+package http
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/autobrr/autobrr/internal/domain"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type backupService interface {
+	Settings() domain.DatabaseBackupSettings
+	Create(ctx context.Context) (*domain.DatabaseBackup, error)
+	List() ([]domain.DatabaseBackup, error)
+	Path(name string) (string, error)
+	Delete(name string) error
+}
+
+type backupHandler struct {
+	encoder encoder
+	service backupService
+}
+
+func newBackupHandler(encoder encoder, service backupService) *backupHandler {
+	return &backupHandler{
+		encoder: encoder,
+		service: service,
+	}
+}
+
+func (h backupHandler) Routes(r chi.Router) {
+	r.Get("/", h.list)
+	r.Post("/", h.create)
+	r.Get("/settings", h.settings)
+	r.Get("/{name}", h.download)
+	r.Delete("/{name}", h.delete)
+}
+
+func (h backupHandler) list(w http.ResponseWriter, r *http.Request) {
+	backups, err := h.service.List()
+	if err != nil {
+		h.encoder.Error(w, err)
+		return
+	}
+
+	h.encoder.StatusResponse(r.Context(), w, backups, http.StatusOK)
+}
+
+func (h backupHandler) create(w http.ResponseWriter, r *http.Request) {
+	backup, err := h.service.Create(r.Context())
+	if err != nil {
+		h.encoder.Error(w, err)
+		return
+	}
+
+	h.encoder.StatusCreatedData(w, backup)
+}
+
+func (h backupHandler) settings(w http.ResponseWriter, r *http.Request) {
+	h.encoder.StatusResponse(r.Context(), w, h.service.Settings(), http.StatusOK)
+}
+
+func (h backupHandler) download(w http.ResponseWriter, r *http.Request) {
+	name := chi.URLParam(r, "name")
+
+	path, err := h.service.Path(name)
+	if err != nil {
+		h.encoder.StatusNotFound(r.Context(), w)
+		return
+	}
+
+	w.Header().Set("Content-Disposition", "attachment; filename=\""+name+"\"")
+	http.ServeFile(w, r, path)
+}
+
+func (h backupHandler) delete(w http.ResponseWriter, r *http.Request) {
+	if err := h.service.Delete(chi.URLParam(r, "name")); err != nil {
+		h.encoder.StatusNotFound(r.Context(), w)
+		return
+	}
+
+	h.encoder.NoContent(w)
+}
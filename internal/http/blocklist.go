@@ -0,0 +1,138 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/autobrr/autobrr/internal/domain"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type blocklistService interface {
+	List(ctx context.Context) ([]domain.AnnounceBlocklistRule, error)
+	FindByID(ctx context.Context, id int) (*domain.AnnounceBlocklistRule, error)
+	Store(ctx context.Context, rule *domain.AnnounceBlocklistRule) error
+	Update(ctx context.Context, rule *domain.AnnounceBlocklistRule) error
+	Delete(ctx context.Context, id int) error
+}
+
+type blocklistHandler struct {
+	encoder encoder
+	service blocklistService
+}
+
+func newBlocklistHandler(encoder encoder, service blocklistService) *blocklistHandler {
+	return &blocklistHandler{
+		encoder: encoder,
+		service: service,
+	}
+}
+
+func (h blocklistHandler) Routes(r chi.Router) {
+	r.Get("/", h.list)
+	r.Post("/", h.store)
+	r.Get("/{ruleID}", h.findByID)
+	r.Put("/{ruleID}", h.update)
+	r.Delete("/{ruleID}", h.delete)
+}
+
+func (h blocklistHandler) list(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	rules, err := h.service.List(ctx)
+	if err != nil {
+		h.encoder.Error(w, err)
+		return
+	}
+
+	h.encoder.StatusResponse(ctx, w, rules, http.StatusOK)
+}
+
+func (h blocklistHandler) findByID(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	id, _ := strconv.Atoi(chi.URLParam(r, "ruleID"))
+
+	rule, err := h.service.FindByID(ctx, id)
+	if err != nil {
+		h.encoder.StatusNotFound(ctx, w)
+		return
+	}
+
+	h.encoder.StatusResponse(ctx, w, rule, http.StatusOK)
+}
+
+func (h blocklistHandler) store(w http.ResponseWriter, r *http.Request) {
+	var (
+		ctx  = r.Context()
+		data domain.AnnounceBlocklistRule
+	)
+
+	if !h.decode(w, r, &data) {
+		return
+	}
+
+	if err := h.service.Store(ctx, &data); err != nil {
+		h.encoder.StatusInternalError(w)
+		return
+	}
+
+	h.encoder.StatusResponse(ctx, w, data, http.StatusCreated)
+}
+
+func (h blocklistHandler) update(w http.ResponseWriter, r *http.Request) {
+	var (
+		ctx  = r.Context()
+		data domain.AnnounceBlocklistRule
+	)
+
+	if !h.decode(w, r, &data) {
+		return
+	}
+
+	data.ID, _ = strconv.Atoi(chi.URLParam(r, "ruleID"))
+
+	if err := h.service.Update(ctx, &data); err != nil {
+		h.encoder.StatusInternalError(w)
+		return
+	}
+
+	h.encoder.StatusResponse(ctx, w, data, http.StatusOK)
+}
+
+func (h blocklistHandler) delete(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	id, _ := strconv.Atoi(chi.URLParam(r, "ruleID"))
+
+	if err := h.service.Delete(ctx, id); err != nil {
+		h.encoder.StatusInternalError(w)
+		return
+	}
+
+	h.encoder.StatusResponse(ctx, w, nil, http.StatusNoContent)
+}
+
+// decode decodes and validates the rule and writes a bad request response if it is invalid
+func (h blocklistHandler) decode(w http.ResponseWriter, r *http.Request, data *domain.AnnounceBlocklistRule) bool {
+	if err := json.NewDecoder(r.Body).Decode(data); err != nil {
+		h.encoder.StatusResponse(r.Context(), w, map[string]interface{}{
+			"code":    "BAD_REQUEST_PARAMS",
+			"message": "could not decode announce blocklist rule",
+		}, http.StatusBadRequest)
+		return false
+	}
+
+	if err := data.Validate(); err != nil {
+		h.encoder.StatusResponse(r.Context(), w, map[string]interface{}{
+			"code":    "BAD_REQUEST_PARAMS",
+			"message": err.Error(),
+		}, http.StatusBadRequest)
+		return false
+	}
+
+	return true
+}
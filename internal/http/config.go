@@ -15,6 +15,8 @@ type configJson struct {
 	Version  string `json:"version"`
 	Commit   string `json:"commit"`
 	Date     string `json:"date"`
+	// Errors are the invalid settings that fell back to their defaults
+	Errors []string `json:"errors"`
 }
 
 type configHandler struct {
@@ -37,15 +39,18 @@ func (h configHandler) Routes(r chi.Router) {
 func (h configHandler) getConfig(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
+	config := h.server.configService.Current()
+
 	conf := configJson{
-		Host:     h.server.config.Host,
-		Port:     h.server.config.Port,
-		LogLevel: h.server.config.LogLevel,
-		LogPath:  h.server.config.LogPath,
-		BaseURL:  h.server.config.BaseURL,
+		Host:     config.Host,
+		Port:     config.Port,
+		LogLevel: config.LogLevel,
+		LogPath:  config.LogPath,
+		BaseURL:  config.BaseURL,
 		Version:  h.server.version,
 		Commit:   h.server.commit,
 		Date:     h.server.date,
+		Errors:   config.Errors,
 	}
 
 	h.encoder.StatusResponse(ctx, w, conf, http.StatusOK)
@@ -18,6 +18,8 @@ type feedService interface {
 	Delete(ctx context.Context, id int) error
 	ToggleEnabled(ctx context.Context, id int, enabled bool) error
 	Test(ctx context.Context, feed *domain.Feed) error
+	DryRun(ctx context.Context, id int) (*domain.FeedDryRunResponse, error)
+	Fetch(ctx context.Context, id int) error
 }
 
 type feedHandler struct {
@@ -38,6 +40,8 @@ func (h feedHandler) Routes(r chi.Router) {
 	r.Post("/test", h.test)
 	r.Put("/{feedID}", h.update)
 	r.Patch("/{feedID}/enabled", h.toggleEnabled)
+	r.Post("/{feedID}/dry-run", h.dryRun)
+	r.Post("/{feedID}/fetch", h.fetch)
 	r.Delete("/{feedID}", h.delete)
 }
 
@@ -160,3 +164,50 @@ func (h feedHandler) delete(w http.ResponseWriter, r *http.Request) {
 
 	h.encoder.StatusResponse(ctx, w, nil, http.StatusNoContent)
 }
+
+func (h feedHandler) fetch(w http.ResponseWriter, r *http.Request) {
+	var (
+		ctx    = r.Context()
+		feedID = chi.URLParam(r, "feedID")
+	)
+
+	id, err := strconv.Atoi(feedID)
+	if err != nil {
+		h.encoder.StatusResponse(ctx, w, map[string]interface{}{
+			"code":    "BAD_REQUEST_PARAMS",
+			"message": "feedID parameter is invalid",
+		}, http.StatusBadRequest)
+		return
+	}
+
+	if err := h.service.Fetch(ctx, id); err != nil {
+		h.encoder.Error(w, err)
+		return
+	}
+
+	h.encoder.NoContent(w)
+}
+
+func (h feedHandler) dryRun(w http.ResponseWriter, r *http.Request) {
+	var (
+		ctx    = r.Context()
+		feedID = chi.URLParam(r, "feedID")
+	)
+
+	id, err := strconv.Atoi(feedID)
+	if err != nil {
+		h.encoder.StatusResponse(ctx, w, map[string]interface{}{
+			"code":    "BAD_REQUEST_PARAMS",
+			"message": "feedID parameter is invalid",
+		}, http.StatusBadRequest)
+		return
+	}
+
+	res, err := h.service.DryRun(ctx, id)
+	if err != nil {
+		h.encoder.Error(w, err)
+		return
+	}
+
+	h.encoder.StatusResponse(ctx, w, res, http.StatusOK)
+}
@@ -3,10 +3,14 @@ package http
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
 	"net/http"
 	"net/url"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 
@@ -22,7 +26,14 @@ type filterService interface {
 	Update(ctx context.Context, filter domain.Filter) (*domain.Filter, error)
 	UpdatePartial(ctx context.Context, filter domain.FilterUpdate) error
 	Duplicate(ctx context.Context, filterID int) (*domain.Filter, error)
+	DuplicateBulk(ctx context.Context, filterID int, duplicate domain.FilterBulkDuplicate) ([]*domain.Filter, error)
+	Export(ctx context.Context, filterID int) (*domain.FilterExport, error)
+	Import(ctx context.Context, export domain.FilterExport, conflict domain.FilterImportConflict) (*domain.FilterImportResult, error)
 	ToggleEnabled(ctx context.Context, filterID int, enabled bool) error
+	UpdateBulk(ctx context.Context, update domain.FilterBulkUpdate) error
+	DeleteBulk(ctx context.Context, del domain.FilterBulkDelete) error
+	Test(ctx context.Context, req domain.FilterTestRequest) (*domain.FilterTestResponse, error)
+	RejectionStats(ctx context.Context, filterID int, window time.Duration) (*domain.FilterRejectionStats, error)
 }
 
 type filterHandler struct {
@@ -41,7 +52,14 @@ func (h filterHandler) Routes(r chi.Router) {
 	r.Get("/", h.getFilters)
 	r.Get("/{filterID}", h.getByID)
 	r.Get("/{filterID}/duplicate", h.duplicate)
+	r.Get("/{filterID}/export", h.export)
+	r.Get("/{filterID}/rejections", h.rejections)
 	r.Post("/", h.store)
+	r.Post("/import", h.importFilter)
+	r.Post("/test", h.test)
+	r.Post("/{filterID}/duplicate", h.duplicateBulk)
+	r.Patch("/bulk", h.updateBulk)
+	r.Post("/bulk/delete", h.deleteBulk)
 	r.Put("/{filterID}", h.update)
 	r.Patch("/{filterID}", h.updatePartial)
 	r.Put("/{filterID}/enabled", h.toggleEnabled)
@@ -139,6 +157,238 @@ func (h filterHandler) duplicate(w http.ResponseWriter, r *http.Request) {
 	h.encoder.StatusResponse(ctx, w, filter, http.StatusOK)
 }
 
+func (h filterHandler) duplicateBulk(w http.ResponseWriter, r *http.Request) {
+	var (
+		ctx      = r.Context()
+		filterID = chi.URLParam(r, "filterID")
+		data     domain.FilterBulkDuplicate
+	)
+
+	id, err := strconv.Atoi(filterID)
+	if err != nil {
+		h.encoder.Error(w, err)
+		return
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&data); err != nil {
+		h.encoder.Error(w, err)
+		return
+	}
+
+	if err := data.Validate(); err != nil {
+		h.badRequest(ctx, w, err)
+		return
+	}
+
+	filters, err := h.service.DuplicateBulk(ctx, id, data)
+	if err != nil {
+		h.encoder.Error(w, err)
+		return
+	}
+
+	h.encoder.StatusCreatedData(w, filters)
+}
+
+func (h filterHandler) updateBulk(w http.ResponseWriter, r *http.Request) {
+	var (
+		ctx  = r.Context()
+		data domain.FilterBulkUpdate
+	)
+
+	if err := json.NewDecoder(r.Body).Decode(&data); err != nil {
+		h.encoder.Error(w, err)
+		return
+	}
+
+	if err := data.Validate(); err != nil {
+		h.badRequest(ctx, w, err)
+		return
+	}
+
+	if err := h.service.UpdateBulk(ctx, data); err != nil {
+		h.encoder.Error(w, err)
+		return
+	}
+
+	h.encoder.NoContent(w)
+}
+
+func (h filterHandler) deleteBulk(w http.ResponseWriter, r *http.Request) {
+	var (
+		ctx  = r.Context()
+		data domain.FilterBulkDelete
+	)
+
+	if err := json.NewDecoder(r.Body).Decode(&data); err != nil {
+		h.encoder.Error(w, err)
+		return
+	}
+
+	if err := data.Validate(); err != nil {
+		h.badRequest(ctx, w, err)
+		return
+	}
+
+	if err := h.service.DeleteBulk(ctx, data); err != nil {
+		h.encoder.Error(w, err)
+		return
+	}
+
+	h.encoder.NoContent(w)
+}
+
+func (h filterHandler) test(w http.ResponseWriter, r *http.Request) {
+	var (
+		ctx  = r.Context()
+		data domain.FilterTestRequest
+	)
+
+	if err := json.NewDecoder(r.Body).Decode(&data); err != nil {
+		h.encoder.Error(w, err)
+		return
+	}
+
+	if err := data.Validate(); err != nil {
+		h.badRequest(ctx, w, err)
+		return
+	}
+
+	// errors here are unknown indexers or announces that do not parse
+	res, err := h.service.Test(ctx, data)
+	if err != nil {
+		h.badRequest(ctx, w, err)
+		return
+	}
+
+	h.encoder.StatusResponse(ctx, w, res, http.StatusOK)
+}
+
+// rejections returns the rejection reasons of the filter over the last ?hours=24
+func (h filterHandler) rejections(w http.ResponseWriter, r *http.Request) {
+	var (
+		ctx      = r.Context()
+		filterID = chi.URLParam(r, "filterID")
+		hours    = r.URL.Query().Get("hours")
+	)
+
+	id, err := strconv.Atoi(filterID)
+	if err != nil {
+		h.encoder.Error(w, err)
+		return
+	}
+
+	window := 24
+	if hours != "" {
+		window, err = strconv.Atoi(hours)
+		if err != nil || window < 1 {
+			h.badRequest(ctx, w, errors.New("hours must be a positive number"))
+			return
+		}
+	}
+
+	stats, err := h.service.RejectionStats(ctx, id, time.Duration(window)*time.Hour)
+	if err != nil {
+		h.encoder.Error(w, err)
+		return
+	}
+
+	h.encoder.StatusResponse(ctx, w, stats, http.StatusOK)
+}
+
+func (h filterHandler) badRequest(ctx context.Context, w http.ResponseWriter, err error) {
+	h.encoder.StatusResponse(ctx, w, map[string]interface{}{
+		"code":    "BAD_REQUEST_PARAMS",
+		"message": err.Error(),
+	}, http.StatusBadRequest)
+}
+
+func (h filterHandler) export(w http.ResponseWriter, r *http.Request) {
+	var (
+		ctx      = r.Context()
+		filterID = chi.URLParam(r, "filterID")
+		format   = r.URL.Query().Get("format")
+	)
+
+	id, err := strconv.Atoi(filterID)
+	if err != nil {
+		h.encoder.Error(w, err)
+		return
+	}
+
+	if format != "" && format != "json" && format != "yaml" {
+		h.encoder.StatusResponse(ctx, w, map[string]interface{}{
+			"code":    "BAD_REQUEST_PARAMS",
+			"message": "format must be json or yaml",
+		}, http.StatusBadRequest)
+		return
+	}
+
+	export, err := h.service.Export(ctx, id)
+	if err != nil {
+		h.encoder.StatusNotFound(ctx, w)
+		return
+	}
+
+	if format != "yaml" {
+		w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", export.Name+".json"))
+		h.encoder.StatusResponse(ctx, w, export, http.StatusOK)
+		return
+	}
+
+	data, err := export.YAML()
+	if err != nil {
+		h.encoder.Error(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/yaml; charset=utf-8")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", export.Name+".yaml"))
+	w.WriteHeader(http.StatusOK)
+	w.Write(data)
+}
+
+func (h filterHandler) importFilter(w http.ResponseWriter, r *http.Request) {
+	var (
+		ctx      = r.Context()
+		conflict = domain.FilterImportConflict(r.URL.Query().Get("conflict"))
+	)
+
+	if conflict == "" {
+		conflict = domain.FilterImportConflictRename
+	}
+
+	if !conflict.Valid() {
+		h.encoder.StatusResponse(ctx, w, map[string]interface{}{
+			"code":    "BAD_REQUEST_PARAMS",
+			"message": "conflict must be rename, overwrite or merge",
+		}, http.StatusBadRequest)
+		return
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		h.encoder.Error(w, err)
+		return
+	}
+
+	export, err := domain.ParseFilterExport(body)
+	if err != nil {
+		h.encoder.StatusResponse(ctx, w, map[string]interface{}{
+			"code":    "BAD_REQUEST_PARAMS",
+			"message": err.Error(),
+		}, http.StatusBadRequest)
+		return
+	}
+
+	result, err := h.service.Import(ctx, *export, conflict)
+	if err != nil {
+		h.encoder.Error(w, err)
+		return
+	}
+
+	h.encoder.StatusResponse(ctx, w, result, http.StatusCreated)
+}
+
 func (h filterHandler) store(w http.ResponseWriter, r *http.Request) {
 	var (
 		ctx  = r.Context()
@@ -151,6 +401,11 @@ func (h filterHandler) store(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if err := domain.ValidateCapturePatterns(data.CapturePatterns); err != nil {
+		h.badRequest(ctx, w, err)
+		return
+	}
+
 	filter, err := h.service.Store(ctx, data)
 	if err != nil {
 		// encode error
@@ -173,6 +428,11 @@ func (h filterHandler) update(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if err := domain.ValidateCapturePatterns(data.CapturePatterns); err != nil {
+		h.badRequest(ctx, w, err)
+		return
+	}
+
 	filter, err := h.service.Update(ctx, data)
 	if err != nil {
 		// encode error
@@ -204,6 +464,13 @@ func (h filterHandler) updatePartial(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if data.CapturePatterns != nil {
+		if err := domain.ValidateCapturePatterns(*data.CapturePatterns); err != nil {
+			h.badRequest(ctx, w, err)
+			return
+		}
+	}
+
 	if err := h.service.UpdatePartial(ctx, data); err != nil {
 		// encode error
 		h.encoder.Error(w, err)
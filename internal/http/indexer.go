@@ -18,6 +18,8 @@ type indexerService interface {
 	GetAll() ([]*domain.IndexerDefinition, error)
 	GetTemplates() ([]domain.IndexerDefinition, error)
 	Delete(ctx context.Context, id int) error
+	TestProxy(ctx context.Context, req domain.IndexerProxyTestRequest) error
+	TestAPI(ctx context.Context, id int) error
 }
 
 type indexerHandler struct {
@@ -40,6 +42,8 @@ func (h indexerHandler) Routes(r chi.Router) {
 	r.Put("/", h.update)
 	r.Get("/", h.getAll)
 	r.Get("/options", h.list)
+	r.Post("/proxy/test", h.testProxy)
+	r.Post("/{indexerID}/api/test", h.testAPI)
 	r.Delete("/{indexerID}", h.delete)
 }
 
@@ -132,3 +136,48 @@ func (h indexerHandler) list(w http.ResponseWriter, r *http.Request) {
 
 	h.encoder.StatusResponse(ctx, w, indexers, http.StatusOK)
 }
+
+func (h indexerHandler) testAPI(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	id, err := strconv.Atoi(chi.URLParam(r, "indexerID"))
+	if err != nil {
+		h.encoder.StatusResponse(ctx, w, map[string]interface{}{
+			"code":    "BAD_REQUEST_PARAMS",
+			"message": "indexerID parameter is invalid",
+		}, http.StatusBadRequest)
+		return
+	}
+
+	if err := h.service.TestAPI(ctx, id); err != nil {
+		h.encoder.StatusResponse(ctx, w, map[string]interface{}{
+			"code":    "BAD_REQUEST_PARAMS",
+			"message": err.Error(),
+		}, http.StatusBadRequest)
+		return
+	}
+
+	h.encoder.NoContent(w)
+}
+
+func (h indexerHandler) testProxy(w http.ResponseWriter, r *http.Request) {
+	var (
+		ctx  = r.Context()
+		data domain.IndexerProxyTestRequest
+	)
+
+	if err := json.NewDecoder(r.Body).Decode(&data); err != nil {
+		h.encoder.Error(w, err)
+		return
+	}
+
+	if err := h.service.TestProxy(ctx, data); err != nil {
+		h.encoder.StatusResponse(ctx, w, map[string]interface{}{
+			"code":    "BAD_REQUEST_PARAMS",
+			"message": err.Error(),
+		}, http.StatusBadRequest)
+		return
+	}
+
+	h.encoder.NoContent(w)
+}
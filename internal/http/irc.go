@@ -14,6 +14,7 @@ import (
 type ircService interface {
 	ListNetworks(ctx context.Context) ([]domain.IrcNetwork, error)
 	GetNetworksWithHealth(ctx context.Context) ([]domain.IrcNetworkWithHealth, error)
+	IRCStatus() []domain.IrcNetworkStatus
 	DeleteNetwork(ctx context.Context, id int64) error
 	GetNetworkByID(ctx context.Context, id int64) (*domain.IrcNetwork, error)
 	StoreNetwork(ctx context.Context, network *domain.IrcNetwork) error
@@ -37,6 +38,7 @@ func newIrcHandler(encoder encoder, service ircService) *ircHandler {
 func (h ircHandler) Routes(r chi.Router) {
 	r.Get("/", h.listNetworks)
 	r.Post("/", h.storeNetwork)
+	r.Get("/status", h.status)
 	r.Put("/network/{networkID}", h.updateNetwork)
 	r.Post("/network/{networkID}/channel", h.storeChannel)
 	r.Get("/network/{networkID}/restart", h.restartNetwork)
@@ -55,6 +57,10 @@ func (h ircHandler) listNetworks(w http.ResponseWriter, r *http.Request) {
 	h.encoder.StatusResponse(ctx, w, networks, http.StatusOK)
 }
 
+func (h ircHandler) status(w http.ResponseWriter, r *http.Request) {
+	h.encoder.StatusResponse(r.Context(), w, h.service.IRCStatus(), http.StatusOK)
+}
+
 func (h ircHandler) getNetworkByID(w http.ResponseWriter, r *http.Request) {
 	var (
 		ctx       = r.Context()
@@ -0,0 +1,176 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/autobrr/autobrr/internal/domain"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type listService interface {
+	List(ctx context.Context) ([]domain.List, error)
+	FindByID(ctx context.Context, id int64) (*domain.List, error)
+	Store(ctx context.Context, list *domain.List) error
+	Update(ctx context.Context, list *domain.List) error
+	Delete(ctx context.Context, id int64) error
+	Refresh(ctx context.Context, id int64) error
+}
+
+type listHandler struct {
+	encoder encoder
+	service listService
+}
+
+func newListHandler(encoder encoder, service listService) *listHandler {
+	return &listHandler{
+		encoder: encoder,
+		service: service,
+	}
+}
+
+func (h listHandler) Routes(r chi.Router) {
+	r.Get("/", h.list)
+	r.Post("/", h.store)
+	r.Get("/{listID}", h.findByID)
+	r.Put("/{listID}", h.update)
+	r.Delete("/{listID}", h.delete)
+	r.Post("/{listID}/refresh", h.refresh)
+}
+
+func (h listHandler) list(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	lists, err := h.service.List(ctx)
+	if err != nil {
+		h.encoder.Error(w, err)
+		return
+	}
+
+	h.encoder.StatusResponse(ctx, w, lists, http.StatusOK)
+}
+
+func (h listHandler) findByID(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	id, ok := h.listID(w, r)
+	if !ok {
+		return
+	}
+
+	list, err := h.service.FindByID(ctx, id)
+	if err != nil {
+		h.encoder.StatusNotFound(ctx, w)
+		return
+	}
+
+	h.encoder.StatusResponse(ctx, w, list, http.StatusOK)
+}
+
+func (h listHandler) store(w http.ResponseWriter, r *http.Request) {
+	var (
+		ctx  = r.Context()
+		data domain.List
+	)
+
+	if err := json.NewDecoder(r.Body).Decode(&data); err != nil {
+		h.encoder.StatusInternalError(w)
+		return
+	}
+
+	if err := data.Validate(); err != nil {
+		h.badRequest(w, r, err.Error())
+		return
+	}
+
+	if err := h.service.Store(ctx, &data); err != nil {
+		h.encoder.Error(w, err)
+		return
+	}
+
+	h.encoder.StatusResponse(ctx, w, data, http.StatusCreated)
+}
+
+func (h listHandler) update(w http.ResponseWriter, r *http.Request) {
+	var (
+		ctx  = r.Context()
+		data domain.List
+	)
+
+	id, ok := h.listID(w, r)
+	if !ok {
+		return
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&data); err != nil {
+		h.encoder.StatusInternalError(w)
+		return
+	}
+
+	data.ID = id
+
+	if err := data.Validate(); err != nil {
+		h.badRequest(w, r, err.Error())
+		return
+	}
+
+	if err := h.service.Update(ctx, &data); err != nil {
+		h.encoder.Error(w, err)
+		return
+	}
+
+	h.encoder.StatusResponse(ctx, w, data, http.StatusOK)
+}
+
+func (h listHandler) delete(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	id, ok := h.listID(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.service.Delete(ctx, id); err != nil {
+		h.encoder.Error(w, err)
+		return
+	}
+
+	h.encoder.NoContent(w)
+}
+
+// refresh fetches the list now and returns the error of the source if it fails
+func (h listHandler) refresh(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	id, ok := h.listID(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.service.Refresh(ctx, id); err != nil {
+		h.encoder.Error(w, err)
+		return
+	}
+
+	h.encoder.NoContent(w)
+}
+
+func (h listHandler) listID(w http.ResponseWriter, r *http.Request) (int64, bool) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "listID"), 10, 64)
+	if err != nil {
+		h.badRequest(w, r, "list id parameter is invalid")
+		return 0, false
+	}
+
+	return id, true
+}
+
+func (h listHandler) badRequest(w http.ResponseWriter, r *http.Request, message string) {
+	h.encoder.StatusResponse(r.Context(), w, map[string]interface{}{
+		"code":    "BAD_REQUEST_PARAMS",
+		"message": message,
+	}, http.StatusBadRequest)
+}
@@ -1,22 +1,33 @@
 package http
 
-import "net/http"
+import (
+	"net/http"
+	"time"
+
+	"github.com/autobrr/autobrr/internal/domain"
+)
 
 func (s Server) IsAuthenticated(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		if token := r.Header.Get("X-API-Token"); token != "" {
 			// check header
-			if !s.apiService.ValidateAPIKey(r.Context(), token) {
-				http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+			key, status := s.authorizeAPIKey(r, token)
+			if status != http.StatusOK {
+				http.Error(w, http.StatusText(status), status)
 				return
 			}
 
-		} else if key := r.URL.Query().Get("apikey"); key != "" {
+			r = r.WithContext(domain.WithAuditActor(r.Context(), "apikey:"+key.Name))
+
+		} else if token := r.URL.Query().Get("apikey"); token != "" {
 			// check query param lke ?apikey=TOKEN
-			if !s.apiService.ValidateAPIKey(r.Context(), key) {
-				http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+			key, status := s.authorizeAPIKey(r, token)
+			if status != http.StatusOK {
+				http.Error(w, http.StatusText(status), status)
 				return
 			}
+
+			r = r.WithContext(domain.WithAuditActor(r.Context(), "apikey:"+key.Name))
 		} else {
 			// check session
 			session, _ := s.cookieStore.Get(r, "user_session")
@@ -26,8 +37,30 @@ func (s Server) IsAuthenticated(next http.Handler) http.Handler {
 				http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
 				return
 			}
+
+			// sessions from before usernames were stored are still attributed to a user
+			username, ok := session.Values["username"].(string)
+			if !ok || username == "" {
+				username = "user"
+			}
+
+			r = r.WithContext(domain.WithAuditActor(r.Context(), username))
 		}
 
 		next.ServeHTTP(w, r)
 	})
 }
+
+// authorizeAPIKey returns the key with 200, 401 for unknown or expired keys and 403 when the key scopes do not cover the route
+func (s Server) authorizeAPIKey(r *http.Request, token string) (*domain.APIKey, int) {
+	key, err := s.apiService.FindByKey(r.Context(), token)
+	if err != nil || key == nil || key.Expired(time.Now()) {
+		return nil, http.StatusUnauthorized
+	}
+
+	if !key.Allows(r.Method, r.URL.Path) {
+		return nil, http.StatusForbidden
+	}
+
+	return key, http.StatusOK
+}
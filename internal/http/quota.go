@@ -0,0 +1,138 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/autobrr/autobrr/internal/domain"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type quotaService interface {
+	List(ctx context.Context) ([]domain.Quota, error)
+	FindByID(ctx context.Context, id int) (*domain.Quota, error)
+	Store(ctx context.Context, quota *domain.Quota) error
+	Update(ctx context.Context, quota *domain.Quota) error
+	Delete(ctx context.Context, id int) error
+}
+
+type quotaHandler struct {
+	encoder encoder
+	service quotaService
+}
+
+func newQuotaHandler(encoder encoder, service quotaService) *quotaHandler {
+	return &quotaHandler{
+		encoder: encoder,
+		service: service,
+	}
+}
+
+func (h quotaHandler) Routes(r chi.Router) {
+	r.Get("/", h.list)
+	r.Post("/", h.store)
+	r.Get("/{quotaID}", h.findByID)
+	r.Put("/{quotaID}", h.update)
+	r.Delete("/{quotaID}", h.delete)
+}
+
+func (h quotaHandler) list(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	quotas, err := h.service.List(ctx)
+	if err != nil {
+		h.encoder.StatusNotFound(ctx, w)
+		return
+	}
+
+	h.encoder.StatusResponse(ctx, w, quotas, http.StatusOK)
+}
+
+func (h quotaHandler) findByID(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	id, _ := strconv.Atoi(chi.URLParam(r, "quotaID"))
+
+	quota, err := h.service.FindByID(ctx, id)
+	if err != nil {
+		h.encoder.StatusNotFound(ctx, w)
+		return
+	}
+
+	h.encoder.StatusResponse(ctx, w, quota, http.StatusOK)
+}
+
+func (h quotaHandler) store(w http.ResponseWriter, r *http.Request) {
+	var (
+		ctx  = r.Context()
+		data domain.Quota
+	)
+
+	if !h.decode(w, r, &data) {
+		return
+	}
+
+	if err := h.service.Store(ctx, &data); err != nil {
+		h.encoder.StatusInternalError(w)
+		return
+	}
+
+	h.encoder.StatusResponse(ctx, w, data, http.StatusCreated)
+}
+
+func (h quotaHandler) update(w http.ResponseWriter, r *http.Request) {
+	var (
+		ctx  = r.Context()
+		data domain.Quota
+	)
+
+	if !h.decode(w, r, &data) {
+		return
+	}
+
+	data.ID, _ = strconv.Atoi(chi.URLParam(r, "quotaID"))
+
+	if err := h.service.Update(ctx, &data); err != nil {
+		h.encoder.StatusInternalError(w)
+		return
+	}
+
+	h.encoder.StatusResponse(ctx, w, data, http.StatusOK)
+}
+
+func (h quotaHandler) delete(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	id, _ := strconv.Atoi(chi.URLParam(r, "quotaID"))
+
+	if err := h.service.Delete(ctx, id); err != nil {
+		h.encoder.StatusInternalError(w)
+		return
+	}
+
+	h.encoder.StatusResponse(ctx, w, nil, http.StatusNoContent)
+}
+
+// decode decodes and validates the quota and writes a bad request response if it is invalid
+func (h quotaHandler) decode(w http.ResponseWriter, r *http.Request, data *domain.Quota) bool {
+	if err := json.NewDecoder(r.Body).Decode(data); err != nil {
+		h.encoder.StatusResponse(r.Context(), w, map[string]interface{}{
+			"code":    "BAD_REQUEST_PARAMS",
+			"message": "could not decode quota",
+		}, http.StatusBadRequest)
+		return false
+	}
+
+	if err := data.Validate(); err != nil {
+		h.encoder.StatusResponse(r.Context(), w, map[string]interface{}{
+			"code":    "BAD_REQUEST_PARAMS",
+			"message": err.Error(),
+		}, http.StatusBadRequest)
+		return false
+	}
+
+	return true
+}
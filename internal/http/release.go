@@ -2,20 +2,37 @@ package http
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
 	"net/http"
-	"net/url"
 	"strconv"
+	"time"
 
 	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/pkg/errors"
+
+	"github.com/dustin/go-humanize"
 	"github.com/go-chi/chi/v5"
 )
 
 type releaseService interface {
 	Find(ctx context.Context, query domain.ReleaseQueryParams) (res []*domain.Release, nextCursor int64, count int64, err error)
+	Export(ctx context.Context, query domain.ReleaseQueryParams, fn func(releases []*domain.Release) error) error
 	FindRecent(ctx context.Context) (res []*domain.Release, err error)
 	GetIndexerOptions(ctx context.Context) ([]string, error)
 	Stats(ctx context.Context) (*domain.ReleaseStats, error)
 	Delete(ctx context.Context) error
+	FindEvents(params domain.ReleaseEventQueryParams) []domain.ReleaseEvent
+	EnqueueWait(ctx context.Context, release *domain.Release) error
+	Replay(ctx context.Context, req domain.ReleaseReplayRequest) ([]domain.ReleaseReplayResult, error)
+	ListRetries(ctx context.Context) ([]*domain.ActionRetry, error)
+	Retry(ctx context.Context, id int) error
+	DiscardRetry(ctx context.Context, id int) error
+	Prune(ctx context.Context) (int64, error)
+	Retention() domain.ReleaseRetention
+	ListApprovals(ctx context.Context, status domain.ReleaseApprovalStatus) ([]*domain.ReleaseApproval, error)
+	DecideApproval(ctx context.Context, id int, decision domain.ReleaseApprovalDecision) error
+	DecideApprovalWithToken(ctx context.Context, id int, decision domain.ReleaseApprovalDecision, token string) error
 }
 
 type releaseHandler struct {
@@ -32,93 +49,246 @@ func newReleaseHandler(encoder encoder, service releaseService) *releaseHandler
 
 func (h releaseHandler) Routes(r chi.Router) {
 	r.Get("/", h.findReleases)
+	r.Get("/export", h.exportReleases)
 	r.Get("/recent", h.findRecentReleases)
 	r.Get("/stats", h.getStats)
 	r.Get("/indexers", h.getIndexerOptions)
+	r.Get("/events", h.findEvents)
+	r.Post("/webhook", h.webhook)
+	r.Post("/replay", h.replay)
+	r.Get("/retries", h.listRetries)
+	r.Post("/retries/{retryID}/retry", h.retry)
+	r.Delete("/retries/{retryID}", h.discardRetry)
+	r.Get("/approvals", h.listApprovals)
+	r.Post("/approvals/{approvalID}/{decision}", h.decideApproval)
 	r.Delete("/all", h.deleteReleases)
+	r.Get("/retention", h.getRetention)
+	r.Post("/retention/prune", h.prune)
 }
 
 func (h releaseHandler) findReleases(w http.ResponseWriter, r *http.Request) {
+	query, err := releaseQueryParams(r)
+	if err != nil {
+		h.encoder.StatusResponse(r.Context(), w, map[string]interface{}{
+			"code":    "BAD_REQUEST_PARAMS",
+			"message": err.Error(),
+		}, http.StatusBadRequest)
+		return
+	}
 
-	limitP := r.URL.Query().Get("limit")
-	limit, err := strconv.Atoi(limitP)
-	if err != nil && limitP != "" {
+	releases, nextCursor, count, err := h.service.Find(r.Context(), query)
+	if err != nil {
+		h.encoder.StatusNotFound(r.Context(), w)
+		return
+	}
+
+	ret := struct {
+		Data       []*domain.Release `json:"data"`
+		NextCursor int64             `json:"next_cursor"`
+		Count      int64             `json:"count"`
+	}{
+		Data:       releases,
+		NextCursor: nextCursor,
+		Count:      count,
+	}
+
+	h.encoder.StatusResponse(r.Context(), w, ret, http.StatusOK)
+}
+
+// releaseQueryParams reads the search, filters and pagination of the release history from the query string.
+// Dates are RFC3339 or YYYY-MM-DD, a date as to includes the whole day. Sizes are bytes or eg. 10GB.
+func releaseQueryParams(r *http.Request) (domain.ReleaseQueryParams, error) {
+	vals := r.URL.Query()
+
+	query := domain.ReleaseQueryParams{
+		Search: vals.Get("q"),
+		Filters: domain.ReleaseQueryFilters{
+			Indexers:   vals["indexer"],
+			Filters:    vals["filter"],
+			PushStatus: vals.Get("push_status"),
+		},
+	}
+
+	for _, p := range []struct {
+		name  string
+		value *uint64
+	}{
+		{"limit", &query.Limit},
+		{"offset", &query.Offset},
+		{"cursor", &query.Cursor},
+	} {
+		if v := vals.Get(p.name); v != "" {
+			n, err := strconv.ParseUint(v, 10, 64)
+			if err != nil {
+				return query, errors.New("%v parameter is invalid", p.name)
+			}
+			*p.value = n
+		}
+	}
+
+	if query.Limit == 0 {
+		query.Limit = 20
+	}
+
+	for _, p := range []struct {
+		name  string
+		value *uint64
+	}{
+		{"min_size", &query.Filters.MinSize},
+		{"max_size", &query.Filters.MaxSize},
+	} {
+		if v := vals.Get(p.name); v != "" {
+			n, err := humanize.ParseBytes(v)
+			if err != nil {
+				return query, errors.New("%v parameter is invalid", p.name)
+			}
+			*p.value = n
+		}
+	}
+
+	if v := vals.Get("from"); v != "" {
+		from, _, err := parseQueryTime(v)
+		if err != nil {
+			return query, errors.New("from parameter is invalid")
+		}
+		query.Filters.From = from
+	}
+
+	if v := vals.Get("to"); v != "" {
+		to, dateOnly, err := parseQueryTime(v)
+		if err != nil {
+			return query, errors.New("to parameter is invalid")
+		}
+		if dateOnly {
+			to = to.AddDate(0, 0, 1)
+		}
+		query.Filters.To = to
+	}
+
+	return query, nil
+}
+
+func parseQueryTime(v string) (time.Time, bool, error) {
+	if t, err := time.ParseInLocation("2006-01-02", v, time.Local); err == nil {
+		return t, true, nil
+	}
+
+	t, err := time.Parse(time.RFC3339, v)
+	return t, false, err
+}
+
+func (h releaseHandler) exportReleases(w http.ResponseWriter, r *http.Request) {
+	query, err := releaseQueryParams(r)
+	if err != nil {
 		h.encoder.StatusResponse(r.Context(), w, map[string]interface{}{
 			"code":    "BAD_REQUEST_PARAMS",
-			"message": "limit parameter is invalid",
+			"message": err.Error(),
 		}, http.StatusBadRequest)
 		return
 	}
-	if limit == 0 {
-		limit = 20
+
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		format = "csv"
 	}
 
-	offsetP := r.URL.Query().Get("offset")
-	offset, err := strconv.Atoi(offsetP)
-	if err != nil && offsetP != "" {
+	var export releaseExporter
+	switch format {
+	case "csv":
+		export = newReleaseCSVExporter(w)
+	case "json":
+		export = newReleaseJSONExporter(w)
+	default:
 		h.encoder.StatusResponse(r.Context(), w, map[string]interface{}{
 			"code":    "BAD_REQUEST_PARAMS",
-			"message": "offset parameter is invalid",
+			"message": "format parameter is invalid, use csv or json",
 		}, http.StatusBadRequest)
 		return
 	}
 
-	cursorP := r.URL.Query().Get("cursor")
-	cursor := 0
-	if cursorP != "" {
-		cursor, err = strconv.Atoi(cursorP)
-		if err != nil && cursorP != "" {
-			h.encoder.StatusResponse(r.Context(), w, map[string]interface{}{
-				"code":    "BAD_REQUEST_PARAMS",
-				"message": "cursor parameter is invalid",
-			}, http.StatusBadRequest)
-		}
+	w.Header().Set("Content-Type", export.contentType())
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", "autobrr-releases-"+time.Now().Format("2006-01-02")+"."+format))
+
+	// the response has started once the first page is written, errors after that end up as a truncated file
+	if err := h.service.Export(r.Context(), query, export.write); err != nil {
+		h.encoder.Error(w, err)
 		return
 	}
 
-	u, err := url.Parse(r.URL.String())
-	if err != nil {
+	if err := export.close(); err != nil {
+		h.encoder.Error(w, err)
+		return
+	}
+}
+
+func (h releaseHandler) findEvents(w http.ResponseWriter, r *http.Request) {
+	limitP := r.URL.Query().Get("limit")
+	limit, err := strconv.Atoi(limitP)
+	if (err != nil && limitP != "") || limit < 0 {
 		h.encoder.StatusResponse(r.Context(), w, map[string]interface{}{
 			"code":    "BAD_REQUEST_PARAMS",
-			"message": "indexer parameter is invalid",
+			"message": "limit parameter is invalid",
 		}, http.StatusBadRequest)
 		return
 	}
-	vals := u.Query()
-	indexer := vals["indexer"]
 
-	pushStatus := r.URL.Query().Get("push_status")
-	search := r.URL.Query().Get("q")
+	var since time.Time
+	if sinceP := r.URL.Query().Get("since"); sinceP != "" {
+		if since, err = time.Parse(time.RFC3339Nano, sinceP); err != nil {
+			h.encoder.StatusResponse(r.Context(), w, map[string]interface{}{
+				"code":    "BAD_REQUEST_PARAMS",
+				"message": "since parameter is invalid",
+			}, http.StatusBadRequest)
+			return
+		}
+	}
+
+	events := h.service.FindEvents(domain.ReleaseEventQueryParams{
+		Types:    r.URL.Query()["type"],
+		Indexers: r.URL.Query()["indexer"],
+		Limit:    limit,
+		Since:    since,
+	})
 
-	query := domain.ReleaseQueryParams{
-		Limit:  uint64(limit),
-		Offset: uint64(offset),
-		Cursor: uint64(cursor),
-		Sort:   nil,
-		Filters: struct {
-			Indexers   []string
-			PushStatus string
-		}{Indexers: indexer, PushStatus: pushStatus},
-		Search: search,
+	h.encoder.StatusResponse(r.Context(), w, events, http.StatusOK)
+}
+
+// webhookQueueTimeout is how long a webhook release waits for room in the queue of its indexer
+const webhookQueueTimeout = 10 * time.Second
+
+func (h releaseHandler) webhook(w http.ResponseWriter, r *http.Request) {
+	var data domain.ReleaseWebhookPayload
+
+	if err := json.NewDecoder(r.Body).Decode(&data); err != nil {
+		h.encoder.StatusResponse(r.Context(), w, map[string]interface{}{
+			"code":    "BAD_REQUEST_PARAMS",
+			"message": "could not decode release payload",
+		}, http.StatusBadRequest)
+		return
 	}
 
-	releases, nextCursor, count, err := h.service.Find(r.Context(), query)
-	if err != nil {
-		h.encoder.StatusNotFound(r.Context(), w)
+	if err := data.Validate(); err != nil {
+		h.encoder.StatusResponse(r.Context(), w, map[string]interface{}{
+			"code":    "BAD_REQUEST_PARAMS",
+			"message": err.Error(),
+		}, http.StatusBadRequest)
 		return
 	}
 
-	ret := struct {
-		Data       []*domain.Release `json:"data"`
-		NextCursor int64             `json:"next_cursor"`
-		Count      int64             `json:"count"`
-	}{
-		Data:       releases,
-		NextCursor: nextCursor,
-		Count:      count,
+	// process in the background like announces, the result shows up in releases and events.
+	// Wait a little for room in the indexer queue, the sender can retry when it is still full.
+	ctx, cancel := context.WithTimeout(r.Context(), webhookQueueTimeout)
+	defer cancel()
+
+	if err := h.service.EnqueueWait(ctx, data.Release()); err != nil {
+		h.encoder.StatusResponse(r.Context(), w, map[string]interface{}{
+			"code":    "QUEUE_FULL",
+			"message": err.Error(),
+		}, http.StatusServiceUnavailable)
+		return
 	}
 
-	h.encoder.StatusResponse(r.Context(), w, ret, http.StatusOK)
+	h.encoder.StatusResponse(r.Context(), w, nil, http.StatusAccepted)
 }
 
 func (h releaseHandler) findRecentReleases(w http.ResponseWriter, r *http.Request) {
@@ -168,3 +338,153 @@ func (h releaseHandler) deleteReleases(w http.ResponseWriter, r *http.Request) {
 
 	h.encoder.NoContent(w)
 }
+
+func (h releaseHandler) getRetention(w http.ResponseWriter, r *http.Request) {
+	h.encoder.StatusResponse(r.Context(), w, h.service.Retention(), http.StatusOK)
+}
+
+func (h releaseHandler) prune(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	deleted, err := h.service.Prune(ctx)
+	if err != nil {
+		h.encoder.Error(w, err)
+		return
+	}
+
+	h.encoder.StatusResponse(ctx, w, map[string]interface{}{"deleted": deleted}, http.StatusOK)
+}
+
+func (h releaseHandler) replay(w http.ResponseWriter, r *http.Request) {
+	var (
+		ctx = r.Context()
+		req domain.ReleaseReplayRequest
+	)
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.encoder.StatusResponse(ctx, w, map[string]interface{}{
+			"code":    "BAD_REQUEST_PARAMS",
+			"message": "could not decode replay request",
+		}, http.StatusBadRequest)
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		h.encoder.StatusResponse(ctx, w, map[string]interface{}{
+			"code":    "BAD_REQUEST_PARAMS",
+			"message": err.Error(),
+		}, http.StatusBadRequest)
+		return
+	}
+
+	results, err := h.service.Replay(ctx, req)
+	if err != nil {
+		if errors.Is(err, domain.ErrReplayNotActive) {
+			h.encoder.StatusResponse(ctx, w, map[string]interface{}{
+				"code":    "INSTANCE_NOT_ACTIVE",
+				"message": err.Error(),
+			}, http.StatusConflict)
+			return
+		}
+
+		h.encoder.Error(w, err)
+		return
+	}
+
+	h.encoder.StatusResponse(ctx, w, results, http.StatusOK)
+}
+
+func (h releaseHandler) listRetries(w http.ResponseWriter, r *http.Request) {
+	retries, err := h.service.ListRetries(r.Context())
+	if err != nil {
+		h.encoder.Error(w, err)
+		return
+	}
+
+	h.encoder.StatusResponse(r.Context(), w, retries, http.StatusOK)
+}
+
+func (h releaseHandler) retry(w http.ResponseWriter, r *http.Request) {
+	var (
+		ctx     = r.Context()
+		retryID = chi.URLParam(r, "retryID")
+	)
+
+	id, err := strconv.Atoi(retryID)
+	if err != nil {
+		h.encoder.Error(w, err)
+		return
+	}
+
+	if err := h.service.Retry(ctx, id); err != nil {
+		h.encoder.Error(w, err)
+		return
+	}
+
+	h.encoder.NoContent(w)
+}
+
+func (h releaseHandler) discardRetry(w http.ResponseWriter, r *http.Request) {
+	var (
+		ctx     = r.Context()
+		retryID = chi.URLParam(r, "retryID")
+	)
+
+	id, err := strconv.Atoi(retryID)
+	if err != nil {
+		h.encoder.Error(w, err)
+		return
+	}
+
+	if err := h.service.DiscardRetry(ctx, id); err != nil {
+		h.encoder.Error(w, err)
+		return
+	}
+
+	h.encoder.NoContent(w)
+}
+
+// listApprovals returns the pending approvals, or the approvals with the status query parameter
+func (h releaseHandler) listApprovals(w http.ResponseWriter, r *http.Request) {
+	status := domain.ReleaseApprovalStatusPending
+	if r.URL.Query().Has("status") {
+		status = domain.ReleaseApprovalStatus(r.URL.Query().Get("status"))
+	}
+
+	approvals, err := h.service.ListApprovals(r.Context(), status)
+	if err != nil {
+		h.encoder.Error(w, err)
+		return
+	}
+
+	h.encoder.StatusResponse(r.Context(), w, approvals, http.StatusOK)
+}
+
+func (h releaseHandler) decideApproval(w http.ResponseWriter, r *http.Request) {
+	var (
+		ctx        = r.Context()
+		approvalID = chi.URLParam(r, "approvalID")
+		decision   = domain.ReleaseApprovalDecision(chi.URLParam(r, "decision"))
+	)
+
+	id, err := strconv.Atoi(approvalID)
+	if err != nil {
+		h.encoder.Error(w, err)
+		return
+	}
+
+	if !decision.Valid() {
+		h.encoder.StatusResponse(ctx, w, map[string]interface{}{
+			"code":    "BAD_REQUEST_PARAMS",
+			"message": "decision must be approve or reject",
+		}, http.StatusBadRequest)
+		return
+	}
+
+	if err := h.service.DecideApproval(ctx, id, decision); err != nil {
+		h.encoder.Error(w, err)
+		return
+	}
+
+	h.encoder.NoContent(w)
+}
@@ -0,0 +1,136 @@
+package http
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/autobrr/autobrr/internal/domain"
+)
+
+// releaseExporter writes the pages of a release export as they are read
+type releaseExporter interface {
+	contentType() string
+	write(releases []*domain.Release) error
+	close() error
+}
+
+var releaseCSVHeader = []string{"id", "timestamp", "indexer", "filter", "filter_status", "protocol", "torrent_name", "title", "size", "rejections", "actions"}
+
+type releaseCSVExporter struct {
+	w           *csv.Writer
+	wroteHeader bool
+}
+
+func newReleaseCSVExporter(w io.Writer) *releaseCSVExporter {
+	return &releaseCSVExporter{w: csv.NewWriter(w)}
+}
+
+func (e *releaseCSVExporter) contentType() string {
+	return "text/csv; charset=utf-8"
+}
+
+func (e *releaseCSVExporter) write(releases []*domain.Release) error {
+	if !e.wroteHeader {
+		if err := e.w.Write(releaseCSVHeader); err != nil {
+			return err
+		}
+		e.wroteHeader = true
+	}
+
+	for _, r := range releases {
+		// actions as "action:status" of every action that ran
+		actions := make([]string, 0, len(r.ActionStatus))
+		for _, a := range r.ActionStatus {
+			actions = append(actions, a.Action+":"+string(a.Status))
+		}
+
+		if err := e.w.Write([]string{
+			strconv.FormatInt(r.ID, 10),
+			r.Timestamp.Format(time.RFC3339),
+			csvText(r.Indexer),
+			csvText(r.FilterName),
+			csvText(string(r.FilterStatus)),
+			csvText(string(r.Protocol)),
+			csvText(r.TorrentName),
+			csvText(r.Title),
+			strconv.FormatUint(r.Size, 10),
+			csvText(strings.Join(r.Rejections, "; ")),
+			csvText(strings.Join(actions, "; ")),
+		}); err != nil {
+			return err
+		}
+	}
+
+	e.w.Flush()
+
+	return e.w.Error()
+}
+
+// csvText keeps spreadsheets from running a cell as a formula. Release names come from announces,
+// so a cell starting with = + - @ or a tab or carriage return is prefixed with a quote.
+func csvText(value string) string {
+	if value != "" && strings.ContainsRune("=+-@\t\r", rune(value[0])) {
+		return "'" + value
+	}
+
+	return value
+}
+
+func (e *releaseCSVExporter) close() error {
+	// an empty export still has the header
+	if !e.wroteHeader {
+		return e.write(nil)
+	}
+
+	return nil
+}
+
+// releaseJSONExporter writes a single array without holding all releases in memory
+type releaseJSONExporter struct {
+	w       io.Writer
+	enc     *json.Encoder
+	started bool
+}
+
+func newReleaseJSONExporter(w io.Writer) *releaseJSONExporter {
+	return &releaseJSONExporter{w: w, enc: json.NewEncoder(w)}
+}
+
+func (e *releaseJSONExporter) contentType() string {
+	return "application/json"
+}
+
+func (e *releaseJSONExporter) write(releases []*domain.Release) error {
+	for _, r := range releases {
+		sep := ","
+		if !e.started {
+			sep = "["
+			e.started = true
+		}
+
+		if _, err := io.WriteString(e.w, sep); err != nil {
+			return err
+		}
+
+		if err := e.enc.Encode(r); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (e *releaseJSONExporter) close() error {
+	end := "]\n"
+	if !e.started {
+		end = "[]\n"
+	}
+
+	_, err := io.WriteString(e.w, end)
+
+	return err
+}
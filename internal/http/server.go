@@ -8,6 +8,7 @@ import (
 
 	"github.com/autobrr/autobrr/internal/database"
 	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/internal/metrics"
 	"github.com/autobrr/autobrr/web"
 
 	"github.com/go-chi/chi/v5"
@@ -17,12 +18,19 @@ import (
 	"github.com/rs/cors"
 )
 
+// configService publishes the running config, it changes when the config is reloaded
+type configService interface {
+	Current() *domain.Config
+}
+
 type Server struct {
 	sse *sse.Server
 	db  *database.DB
 
-	config      *domain.Config
-	cookieStore *sessions.CookieStore
+	// config is the config at startup, settings that reload are read from configService
+	config        *domain.Config
+	configService configService
+	cookieStore   *sessions.CookieStore
 
 	version string
 	commit  string
@@ -30,36 +38,49 @@ type Server struct {
 
 	actionService         actionService
 	apiService            apikeyService
+	auditService          auditService
 	authService           authService
+	backupService         backupService
+	blocklistService      blocklistService
 	downloadClientService downloadClientService
 	filterService         filterService
 	feedService           feedService
 	indexerService        indexerService
 	ircService            ircService
+	listService           listService
 	notificationService   notificationService
+	quotaService          quotaService
 	releaseService        releaseService
 }
 
-func NewServer(config *domain.Config, sse *sse.Server, db *database.DB, version string, commit string, date string, actionService actionService, apiService apikeyService, authService authService, downloadClientSvc downloadClientService, filterSvc filterService, feedSvc feedService, indexerSvc indexerService, ircSvc ircService, notificationSvc notificationService, releaseSvc releaseService) Server {
+func NewServer(configSvc configService, sse *sse.Server, db *database.DB, version string, commit string, date string, actionService actionService, apiService apikeyService, auditSvc auditService, authService authService, backupSvc backupService, blocklistSvc blocklistService, downloadClientSvc downloadClientService, filterSvc filterService, feedSvc feedService, indexerSvc indexerService, ircSvc ircService, listSvc listService, notificationSvc notificationService, quotaSvc quotaService, releaseSvc releaseService) Server {
+	config := configSvc.Current()
+
 	return Server{
-		config:  config,
-		sse:     sse,
-		db:      db,
-		version: version,
-		commit:  commit,
-		date:    date,
+		config:        config,
+		configService: configSvc,
+		sse:           sse,
+		db:            db,
+		version:       version,
+		commit:        commit,
+		date:          date,
 
 		cookieStore: sessions.NewCookieStore([]byte(config.SessionSecret)),
 
 		actionService:         actionService,
 		apiService:            apiService,
+		auditService:          auditSvc,
 		authService:           authService,
+		backupService:         backupSvc,
+		blocklistService:      blocklistSvc,
 		downloadClientService: downloadClientSvc,
 		filterService:         filterSvc,
 		feedService:           feedSvc,
 		indexerService:        indexerSvc,
 		ircService:            ircSvc,
+		listService:           listSvc,
 		notificationService:   notificationSvc,
+		quotaService:          quotaSvc,
 		releaseService:        releaseSvc,
 	}
 }
@@ -111,12 +132,16 @@ func (s Server) Handler() http.Handler {
 
 	r.Route("/api/auth", newAuthHandler(encoder, s.config, s.cookieStore, s.authService).Routes)
 	r.Route("/api/healthz", newHealthHandler(encoder, s.db).Routes)
+	r.Route("/api/approval", newApprovalLinkHandler(s.releaseService).Routes)
 
 	r.Group(func(r chi.Router) {
 		r.Use(s.IsAuthenticated)
 
 		r.Route("/api", func(r chi.Router) {
 			r.Route("/actions", newActionHandler(encoder, s.actionService).Routes)
+			r.Route("/audit", newAuditHandler(encoder, s.auditService).Routes)
+			r.Route("/backup", newBackupHandler(encoder, s.backupService).Routes)
+			r.Route("/blocklist", newBlocklistHandler(encoder, s.blocklistService).Routes)
 			r.Route("/config", newConfigHandler(encoder, s).Routes)
 			r.Route("/download_clients", newDownloadClientHandler(encoder, s.downloadClientService).Routes)
 			r.Route("/filters", newFilterHandler(encoder, s.filterService).Routes)
@@ -124,9 +149,13 @@ func (s Server) Handler() http.Handler {
 			r.Route("/irc", newIrcHandler(encoder, s.ircService).Routes)
 			r.Route("/indexer", newIndexerHandler(encoder, s.indexerService, s.ircService).Routes)
 			r.Route("/keys", newAPIKeyHandler(encoder, s.apiService).Routes)
+			r.Route("/lists", newListHandler(encoder, s.listService).Routes)
 			r.Route("/notification", newNotificationHandler(encoder, s.notificationService).Routes)
+			r.Route("/quotas", newQuotaHandler(encoder, s.quotaService).Routes)
 			r.Route("/release", newReleaseHandler(encoder, s.releaseService).Routes)
 
+			r.Handle("/metrics", metrics.Handler())
+
 			r.HandleFunc("/events", func(w http.ResponseWriter, r *http.Request) {
 
 				// inject CORS headers to bypass checks
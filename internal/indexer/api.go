@@ -1,6 +1,8 @@
 package indexer
 
 import (
+	"net/http"
+
 	"github.com/rs/zerolog"
 
 	"github.com/autobrr/autobrr/internal/domain"
@@ -11,12 +13,13 @@ import (
 	"github.com/autobrr/autobrr/pkg/ggn"
 	"github.com/autobrr/autobrr/pkg/ptp"
 	"github.com/autobrr/autobrr/pkg/red"
+	"github.com/autobrr/autobrr/pkg/sharedhttp"
 )
 
 type APIService interface {
 	TestConnection(indexer string) (bool, error)
 	GetTorrentByID(indexer string, torrentID string) (*domain.TorrentBasic, error)
-	AddClient(indexer string, settings map[string]string) error
+	AddClient(indexer string, proxy string, settings map[string]string) error
 	RemoveClient(indexer string) error
 }
 
@@ -25,6 +28,11 @@ type apiClient interface {
 	TestAPI() (bool, error)
 }
 
+// httpClientSetter is implemented by api clients that can send requests through a proxy
+type httpClientSetter interface {
+	WithHTTPClient(client *http.Client)
+}
+
 type apiService struct {
 	log        zerolog.Logger
 	apiClients map[string]apiClient
@@ -59,7 +67,7 @@ func (s *apiService) GetTorrentByID(indexer string, torrentID string) (*domain.T
 func (s *apiService) TestConnection(indexer string) (bool, error) {
 	v, ok := s.apiClients[indexer]
 	if !ok {
-		return false, nil
+		return false, errors.New("no api configured for indexer: %v", indexer)
 	}
 
 	t, err := v.TestAPI()
@@ -71,7 +79,7 @@ func (s *apiService) TestConnection(indexer string) (bool, error) {
 	return t, nil
 }
 
-func (s *apiService) AddClient(indexer string, settings map[string]string) error {
+func (s *apiService) AddClient(indexer string, proxy string, settings map[string]string) error {
 	// basic validation
 	if indexer == "" {
 		return errors.New("api.Service.AddClient: validation falied: indexer can't be empty")
@@ -123,6 +131,18 @@ func (s *apiService) AddClient(indexer string, settings map[string]string) error
 		return errors.New("api.Service.AddClient: could not initialize client: unsupported indexer '%v'", indexer)
 
 	}
+
+	if proxy != "" {
+		client, err := sharedhttp.Client(proxy, 0)
+		if err != nil {
+			return errors.Wrap(err, "api.Service.AddClient: could not initialize proxy for '%v'", indexer)
+		}
+
+		if c, ok := s.apiClients[indexer].(httpClientSetter); ok {
+			c.WithHTTPClient(client)
+		}
+	}
+
 	return nil
 }
 
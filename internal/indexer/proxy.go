@@ -0,0 +1,57 @@
+package indexer
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/pkg/errors"
+	"github.com/autobrr/autobrr/pkg/sharedhttp"
+)
+
+func validateProxy(proxy string) error {
+	if proxy == "" {
+		return nil
+	}
+
+	_, err := sharedhttp.ParseProxy(proxy)
+	return err
+}
+
+// TestProxy requests the indexer site through the proxy. Any response counts as success,
+// the test only checks that the proxy accepts the connection and reaches the indexer.
+func (s *service) TestProxy(ctx context.Context, req domain.IndexerProxyTestRequest) error {
+	if req.Proxy == "" {
+		return errors.New("proxy is required")
+	}
+
+	definition := s.getDefinitionByName(req.Identifier)
+	if definition == nil {
+		return errors.New("unknown indexer: %v", req.Identifier)
+	}
+
+	if len(definition.URLS) == 0 {
+		return errors.New("indexer %v has no url to test against", req.Identifier)
+	}
+
+	client, err := sharedhttp.Client(req.Proxy, 15*time.Second)
+	if err != nil {
+		return err
+	}
+
+	r, err := http.NewRequestWithContext(ctx, http.MethodHead, definition.URLS[0], nil)
+	if err != nil {
+		return errors.Wrap(err, "could not build request")
+	}
+
+	res, err := client.Do(r)
+	if err != nil {
+		return errors.Wrap(err, "could not reach %v through proxy", definition.URLS[0])
+	}
+	defer res.Body.Close()
+
+	s.log.Debug().Msgf("proxy test for %v got status %d", req.Identifier, res.StatusCode)
+
+	return nil
+}
@@ -8,12 +8,15 @@ import (
 	"path/filepath"
 	"sort"
 	"strings"
+	"sync"
 
+	"github.com/autobrr/autobrr/internal/audit"
 	"github.com/autobrr/autobrr/internal/domain"
 	"github.com/autobrr/autobrr/internal/logger"
 	"github.com/autobrr/autobrr/internal/scheduler"
 	"github.com/autobrr/autobrr/pkg/errors"
 
+	"github.com/asaskevich/EventBus"
 	"github.com/gosimple/slug"
 	"github.com/rs/zerolog"
 	"gopkg.in/yaml.v3"
@@ -26,9 +29,13 @@ type Service interface {
 	FindByFilterID(ctx context.Context, id int) ([]domain.Indexer, error)
 	FindByID(ctx context.Context, id int) (*domain.Indexer, error)
 	List(ctx context.Context) ([]domain.Indexer, error)
+	TestProxy(ctx context.Context, req domain.IndexerProxyTestRequest) error
+	TestAPI(ctx context.Context, id int) error
 	GetAll() ([]*domain.IndexerDefinition, error)
+	GetMappedDefinitionByName(name string) (*domain.IndexerDefinition, error)
 	GetTemplates() ([]domain.IndexerDefinition, error)
 	LoadIndexerDefinitions() error
+	ReloadDefinitions() error
 	GetIndexersByIRCNetwork(server string) []*domain.IndexerDefinition
 	GetTorznabIndexers() []domain.IndexerDefinition
 	Start() error
@@ -40,6 +47,11 @@ type service struct {
 	repo       domain.IndexerRepo
 	apiService APIService
 	scheduler  scheduler.Service
+	bus        EventBus.Bus
+	auditSvc   audit.Service
+
+	// guards the definition maps, custom definitions are reloaded while running
+	m sync.RWMutex
 
 	// contains all raw indexer definitions
 	definitions map[string]domain.IndexerDefinition
@@ -53,13 +65,15 @@ type service struct {
 	rssIndexers map[string]*domain.IndexerDefinition
 }
 
-func NewService(log logger.Logger, config *domain.Config, repo domain.IndexerRepo, apiService APIService, scheduler scheduler.Service) Service {
+func NewService(log logger.Logger, config *domain.Config, repo domain.IndexerRepo, apiService APIService, scheduler scheduler.Service, bus EventBus.Bus, auditSvc audit.Service) Service {
 	return &service{
 		log:                       log.With().Str("module", "indexer").Logger(),
 		config:                    config,
 		repo:                      repo,
 		apiService:                apiService,
 		scheduler:                 scheduler,
+		bus:                       bus,
+		auditSvc:                  auditSvc,
 		lookupIRCServerDefinition: make(map[string]map[string]*domain.IndexerDefinition),
 		torznabIndexers:           make(map[string]*domain.IndexerDefinition),
 		rssIndexers:               make(map[string]*domain.IndexerDefinition),
@@ -81,10 +95,19 @@ func (s *service) Store(ctx context.Context, indexer domain.Indexer) (*domain.In
 		// if the name already contains rss remove it
 		cleanName := strings.ReplaceAll(strings.ToLower(indexer.Name), "rss", "")
 		identifier = slug.Make(fmt.Sprintf("%v-%v", indexer.Implementation, cleanName)) // rss-name
+
+	case "api":
+		// if the name already contains api remove it
+		cleanName := strings.ReplaceAll(strings.ToLower(indexer.Name), "api", "")
+		identifier = slug.Make(fmt.Sprintf("%v-%v", indexer.Implementation, cleanName)) // api-name
 	}
 
 	indexer.Identifier = identifier
 
+	if err := validateProxy(indexer.Proxy); err != nil {
+		return nil, err
+	}
+
 	i, err := s.repo.Store(ctx, indexer)
 	if err != nil {
 		s.log.Error().Stack().Err(err).Msgf("failed to store indexer: %v", indexer.Name)
@@ -98,10 +121,21 @@ func (s *service) Store(ctx context.Context, indexer domain.Indexer) (*domain.In
 		return nil, err
 	}
 
+	s.auditSvc.Record(ctx, domain.AuditEntityIndexer, i.ID, i.Name, nil, i)
+
 	return i, nil
 }
 
 func (s *service) Update(ctx context.Context, indexer domain.Indexer) (*domain.Indexer, error) {
+	if err := validateProxy(indexer.Proxy); err != nil {
+		return nil, err
+	}
+
+	before, err := s.repo.FindByID(ctx, int(indexer.ID))
+	if err != nil {
+		s.log.Warn().Err(err).Msgf("could not find indexer %v for audit log", indexer.ID)
+	}
+
 	i, err := s.repo.Update(ctx, indexer)
 	if err != nil {
 		s.log.Error().Err(err).Msgf("could not update indexer: %+v", indexer)
@@ -115,12 +149,16 @@ func (s *service) Update(ctx context.Context, indexer domain.Indexer) (*domain.I
 		return nil, err
 	}
 
-	if indexer.Implementation == "torznab" || indexer.Implementation == "rss" {
+	if indexer.Implementation == "torznab" || indexer.Implementation == "rss" || indexer.Implementation == "api" {
 		if !indexer.Enabled {
 			s.stopFeed(indexer.Identifier)
 		}
 	}
 
+	if before != nil {
+		s.auditSvc.Record(ctx, domain.AuditEntityIndexer, i.ID, i.Name, before, i)
+	}
+
 	s.log.Debug().Msgf("successfully updated indexer: %v", indexer.Name)
 
 	return i, nil
@@ -140,6 +178,8 @@ func (s *service) Delete(ctx context.Context, id int) error {
 	// remove from lookup tables
 	s.removeIndexer(*indexer)
 
+	s.auditSvc.Record(ctx, domain.AuditEntityIndexer, indexer.ID, indexer.Name, indexer, nil)
+
 	return nil
 }
 
@@ -163,6 +203,25 @@ func (s *service) FindByID(ctx context.Context, id int) (*domain.Indexer, error)
 	return indexers, err
 }
 
+// TestAPI checks the api connection of the indexer with its configured api key
+func (s *service) TestAPI(ctx context.Context, id int) error {
+	indexer, err := s.FindByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	ok, err := s.apiService.TestConnection(indexer.Identifier)
+	if err != nil {
+		return errors.Wrap(err, "api connection failed for indexer: %v", indexer.Name)
+	}
+
+	if !ok {
+		return errors.New("api connection failed for indexer: %v", indexer.Name)
+	}
+
+	return nil
+}
+
 func (s *service) List(ctx context.Context) ([]domain.Indexer, error) {
 	indexers, err := s.repo.List(ctx)
 	if err != nil {
@@ -174,6 +233,9 @@ func (s *service) List(ctx context.Context) ([]domain.Indexer, error) {
 }
 
 func (s *service) GetAll() ([]*domain.IndexerDefinition, error) {
+	s.m.RLock()
+	defer s.m.RUnlock()
+
 	var res = make([]*domain.IndexerDefinition, 0)
 
 	for _, indexer := range s.mappedDefinitions {
@@ -192,6 +254,19 @@ func (s *service) GetAll() ([]*domain.IndexerDefinition, error) {
 	return res, nil
 }
 
+// GetMappedDefinitionByName returns the definition with settings of a configured indexer
+func (s *service) GetMappedDefinitionByName(name string) (*domain.IndexerDefinition, error) {
+	s.m.RLock()
+	defer s.m.RUnlock()
+
+	v, ok := s.mappedDefinitions[name]
+	if !ok || v == nil {
+		return nil, errors.New("could not find indexer: %v", name)
+	}
+
+	return v, nil
+}
+
 func (s *service) mapIndexers() (map[string]*domain.IndexerDefinition, error) {
 	indexers, err := s.repo.List(context.Background())
 	if err != nil {
@@ -221,6 +296,8 @@ func (s *service) mapIndexer(indexer domain.Indexer) (*domain.IndexerDefinition,
 		definitionName = "torznab"
 	} else if indexer.Implementation == "rss" {
 		definitionName = "rss"
+	} else if indexer.Implementation == "api" {
+		definitionName = "api"
 	}
 
 	d := s.getDefinitionByName(definitionName)
@@ -234,6 +311,7 @@ func (s *service) mapIndexer(indexer domain.Indexer) (*domain.IndexerDefinition,
 	d.Identifier = indexer.Identifier
 	d.Implementation = indexer.Implementation
 	d.Enabled = indexer.Enabled
+	d.Proxy = indexer.Proxy
 
 	if d.SettingsMap == nil {
 		d.SettingsMap = make(map[string]string)
@@ -269,6 +347,7 @@ func (s *service) updateMapIndexer(indexer domain.Indexer) (*domain.IndexerDefin
 	d.Identifier = indexer.Identifier
 	d.Implementation = indexer.Implementation
 	d.Enabled = indexer.Enabled
+	d.Proxy = indexer.Proxy
 
 	if d.SettingsMap == nil {
 		d.SettingsMap = make(map[string]string)
@@ -294,6 +373,9 @@ func (s *service) updateMapIndexer(indexer domain.Indexer) (*domain.IndexerDefin
 }
 
 func (s *service) GetTemplates() ([]domain.IndexerDefinition, error) {
+	s.m.RLock()
+	defer s.m.RUnlock()
+
 	definitions := s.definitions
 
 	ret := make([]domain.IndexerDefinition, 0)
@@ -305,6 +387,9 @@ func (s *service) GetTemplates() ([]domain.IndexerDefinition, error) {
 }
 
 func (s *service) Start() error {
+	s.m.Lock()
+	defer s.m.Unlock()
+
 	// load all indexer definitions
 	err := s.LoadIndexerDefinitions()
 	if err != nil {
@@ -321,6 +406,61 @@ func (s *service) Start() error {
 	}
 
 	// load the indexers' setup by the user
+	if err := s.loadIndexers(); err != nil {
+		return err
+	}
+
+	s.log.Info().Msgf("Loaded %d indexers", len(s.mappedDefinitions))
+
+	if s.config.CustomDefinitions != "" {
+		go s.watchCustomDefinitions()
+	}
+
+	return nil
+}
+
+// ReloadDefinitions loads the built-in and custom indexer definitions again and maps the indexers setup by the user on them.
+// The current definitions are kept if a custom definition can't be loaded.
+func (s *service) ReloadDefinitions() error {
+	s.m.Lock()
+
+	previous := s.definitions
+	s.definitions = make(map[string]domain.IndexerDefinition)
+
+	if err := s.LoadIndexerDefinitions(); err != nil {
+		s.definitions = previous
+		s.m.Unlock()
+		return err
+	}
+
+	if err := s.LoadCustomIndexerDefinitions(); err != nil {
+		s.definitions = previous
+		s.m.Unlock()
+		return errors.Wrap(err, "could not load custom indexer definitions")
+	}
+
+	s.mappedDefinitions = make(map[string]*domain.IndexerDefinition)
+	s.lookupIRCServerDefinition = make(map[string]map[string]*domain.IndexerDefinition)
+	s.torznabIndexers = make(map[string]*domain.IndexerDefinition)
+	s.rssIndexers = make(map[string]*domain.IndexerDefinition)
+
+	err := s.loadIndexers()
+	s.m.Unlock()
+
+	if err != nil {
+		return err
+	}
+
+	s.log.Info().Msgf("Reloaded %d indexer definitions", len(s.definitions))
+
+	// let running irc handlers pick up the new definitions
+	s.bus.Publish("indexer:definitions-reloaded")
+
+	return nil
+}
+
+// loadIndexers maps the indexers setup by the user on the loaded definitions
+func (s *service) loadIndexers() error {
 	indexerDefinitions, err := s.mapIndexers()
 	if err != nil {
 		return err
@@ -333,7 +473,7 @@ func (s *service) Start() error {
 
 			// check if it has api and add to api service
 			if indexer.Enabled && indexer.HasApi() {
-				if err := s.apiService.AddClient(indexer.Identifier, indexer.SettingsMap); err != nil {
+				if err := s.apiService.AddClient(indexer.Identifier, indexer.Proxy, indexer.SettingsMap); err != nil {
 					s.log.Error().Stack().Err(err).Msgf("indexer.start: could not init api client for: '%v'", indexer.Identifier)
 				}
 			}
@@ -347,12 +487,13 @@ func (s *service) Start() error {
 		}
 	}
 
-	s.log.Info().Msgf("Loaded %d indexers", len(indexerDefinitions))
-
 	return nil
 }
 
 func (s *service) removeIndexer(indexer domain.Indexer) {
+	s.m.Lock()
+	defer s.m.Unlock()
+
 	// remove Torznab
 	if indexer.Implementation == "torznab" {
 		delete(s.torznabIndexers, indexer.Identifier)
@@ -367,6 +508,9 @@ func (s *service) removeIndexer(indexer domain.Indexer) {
 }
 
 func (s *service) addIndexer(indexer domain.Indexer) error {
+	s.m.Lock()
+	defer s.m.Unlock()
+
 	indexerDefinition, err := s.mapIndexer(indexer)
 	if err != nil {
 		return err
@@ -382,7 +526,7 @@ func (s *service) addIndexer(indexer domain.Indexer) error {
 
 		// check if it has api and add to api service
 		if indexerDefinition.Enabled && indexerDefinition.HasApi() {
-			if err := s.apiService.AddClient(indexerDefinition.Identifier, indexerDefinition.SettingsMap); err != nil {
+			if err := s.apiService.AddClient(indexerDefinition.Identifier, indexerDefinition.Proxy, indexerDefinition.SettingsMap); err != nil {
 				s.log.Error().Stack().Err(err).Msgf("indexer.start: could not init api client for: '%v'", indexer.Identifier)
 			}
 		}
@@ -401,6 +545,9 @@ func (s *service) addIndexer(indexer domain.Indexer) error {
 }
 
 func (s *service) updateIndexer(indexer domain.Indexer) error {
+	s.m.Lock()
+	defer s.m.Unlock()
+
 	indexerDefinition, err := s.updateMapIndexer(indexer)
 	if err != nil {
 		return err
@@ -416,7 +563,7 @@ func (s *service) updateIndexer(indexer domain.Indexer) error {
 
 		// check if it has api and add to api service
 		if indexerDefinition.Enabled && indexerDefinition.HasApi() {
-			if err := s.apiService.AddClient(indexerDefinition.Identifier, indexerDefinition.SettingsMap); err != nil {
+			if err := s.apiService.AddClient(indexerDefinition.Identifier, indexerDefinition.Proxy, indexerDefinition.SettingsMap); err != nil {
 				s.log.Error().Stack().Err(err).Msgf("indexer.start: could not init api client for: '%v'", indexer.Identifier)
 			}
 		}
@@ -489,6 +636,10 @@ func (s *service) LoadIndexerDefinitions() error {
 			d.Implementation = "irc"
 		}
 
+		if d.FreeleechToken != nil {
+			d.Settings = append(d.Settings, domain.FreeleechTokenSettings()...)
+		}
+
 		s.definitions[d.Identifier] = *d
 	}
 
@@ -513,7 +664,7 @@ func (s *service) LoadCustomIndexerDefinitions() error {
 
 	entries, err := outputDirRead.ReadDir(0)
 	if err != nil {
-		s.log.Fatal().Err(err).Stack().Msg("failed reading directory")
+		s.log.Error().Err(err).Stack().Msg("failed reading directory")
 		return errors.Wrap(err, "could not read directory")
 	}
 
@@ -552,6 +703,15 @@ func (s *service) LoadCustomIndexerDefinitions() error {
 			d.Implementation = "irc"
 		}
 
+		if d.FreeleechToken != nil {
+			d.Settings = append(d.Settings, domain.FreeleechTokenSettings()...)
+		}
+
+		// custom definitions take precedence over built-in ones
+		if _, ok := s.definitions[d.Identifier]; ok {
+			s.log.Debug().Msgf("custom definition %v overrides definition: %v", file, d.Identifier)
+		}
+
 		s.definitions[d.Identifier] = *d
 
 		customCount++
@@ -563,6 +723,9 @@ func (s *service) LoadCustomIndexerDefinitions() error {
 }
 
 func (s *service) GetIndexersByIRCNetwork(server string) []*domain.IndexerDefinition {
+	s.m.RLock()
+	defer s.m.RUnlock()
+
 	server = strings.ToLower(server)
 
 	var indexerDefinitions []*domain.IndexerDefinition
@@ -578,6 +741,9 @@ func (s *service) GetIndexersByIRCNetwork(server string) []*domain.IndexerDefini
 }
 
 func (s *service) GetTorznabIndexers() []domain.IndexerDefinition {
+	s.m.RLock()
+	defer s.m.RUnlock()
+
 	indexerDefinitions := make([]domain.IndexerDefinition, 0)
 
 	for _, definition := range s.torznabIndexers {
@@ -590,6 +756,9 @@ func (s *service) GetTorznabIndexers() []domain.IndexerDefinition {
 }
 
 func (s *service) GetRSSIndexers() []domain.IndexerDefinition {
+	s.m.RLock()
+	defer s.m.RUnlock()
+
 	indexerDefinitions := make([]domain.IndexerDefinition, 0)
 
 	for _, definition := range s.rssIndexers {
@@ -0,0 +1,72 @@
+package indexer
+
+import (
+	"path/filepath"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// editors often write a file in several steps, wait for changes to settle before reloading
+const definitionsReloadDelay = 500 * time.Millisecond
+
+// watchCustomDefinitions reloads the indexer definitions when files in the custom definitions directory change
+func (s *service) watchCustomDefinitions() {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		s.log.Error().Err(err).Msg("could not create custom definitions watcher")
+		return
+	}
+	defer watcher.Close()
+
+	if err := watcher.Add(s.config.CustomDefinitions); err != nil {
+		s.log.Warn().Err(err).Msgf("could not watch custom definitions directory %q", s.config.CustomDefinitions)
+		return
+	}
+
+	s.log.Debug().Msgf("watching custom definitions directory %q for changes", s.config.CustomDefinitions)
+
+	var reload *time.Timer
+
+	for {
+		select {
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return
+			}
+
+			if !isDefinitionChange(event) {
+				continue
+			}
+
+			s.log.Trace().Msgf("custom definition changed: %v", event)
+
+			if reload != nil {
+				reload.Stop()
+			}
+
+			reload = time.AfterFunc(definitionsReloadDelay, func() {
+				if err := s.ReloadDefinitions(); err != nil {
+					s.log.Error().Err(err).Msg("could not reload indexer definitions, keeping the current ones")
+				}
+			})
+
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return
+			}
+
+			s.log.Error().Err(err).Msg("custom definitions watcher error")
+		}
+	}
+}
+
+func isDefinitionChange(event fsnotify.Event) bool {
+	if event.Op == fsnotify.Chmod {
+		return false
+	}
+
+	ext := filepath.Ext(event.Name)
+
+	return ext == ".yaml" || ext == ".yml"
+}
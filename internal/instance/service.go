@@ -0,0 +1,212 @@
+package instance
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/internal/logger"
+
+	"github.com/rs/zerolog"
+)
+
+const (
+	leaseName = "leader"
+
+	// claims only need to outlive the time it takes for all instances to see the same announce
+	claimRetention = 24 * time.Hour
+)
+
+// Service decides whether this instance runs actions when several instances share a postgres database
+// and instanceCoordinate is set. Only the instance holding the lease runs actions, the others are on standby
+// and take over when the lease expires.
+type Service interface {
+	Start()
+	Stop()
+	Name() string
+	IsLeader() bool
+	ClaimRelease(ctx context.Context, release *domain.Release) bool
+	UnclaimRelease(ctx context.Context, release *domain.Release)
+}
+
+type service struct {
+	log  zerolog.Logger
+	repo domain.InstanceRepo
+
+	name       string
+	readOnly   bool
+	coordinate bool
+	ttl        time.Duration
+
+	leader      int32
+	lastCleanup time.Time
+
+	stop chan struct{}
+	wg   sync.WaitGroup
+}
+
+func NewService(log logger.Logger, config *domain.Config, repo domain.InstanceRepo) Service {
+	name := config.InstanceName
+	if name == "" {
+		hostname, _ := os.Hostname()
+		name = fmt.Sprintf("%v-%d", hostname, os.Getpid())
+	}
+
+	ttl := time.Duration(config.InstanceLeaseTimeout) * time.Second
+	if ttl <= 0 {
+		ttl = 30 * time.Second
+	}
+
+	s := &service{
+		log:      log.With().Str("module", "instance").Str("instance", name).Logger(),
+		repo:     repo,
+		name:     name,
+		readOnly: config.InstanceReadOnly,
+		// sqlite can't be shared, so there is nothing to coordinate
+		coordinate: config.InstanceCoordinate && config.DatabaseType == "postgres",
+		ttl:        ttl,
+		stop:       make(chan struct{}),
+	}
+
+	if config.InstanceCoordinate && !s.coordinate {
+		s.log.Warn().Msg("instanceCoordinate needs a postgres database, running as the only instance")
+	}
+
+	if !s.readOnly && !s.coordinate {
+		s.setLeader(true)
+	}
+
+	return s
+}
+
+func (s *service) Name() string {
+	return s.name
+}
+
+func (s *service) IsLeader() bool {
+	return atomic.LoadInt32(&s.leader) == 1
+}
+
+// setLeader returns the previous state
+func (s *service) setLeader(leader bool) bool {
+	var v int32
+	if leader {
+		v = 1
+	}
+
+	return atomic.SwapInt32(&s.leader, v) == 1
+}
+
+func (s *service) Start() {
+	if s.readOnly {
+		s.log.Info().Msg("instance is read-only, actions will not be run")
+		return
+	}
+
+	if !s.coordinate {
+		return
+	}
+
+	s.renew()
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+
+		ticker := time.NewTicker(s.ttl / 3)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-s.stop:
+				return
+			case <-ticker.C:
+				s.renew()
+			}
+		}
+	}()
+}
+
+// Stop gives up the lease so a standby instance can take over right away
+func (s *service) Stop() {
+	if s.readOnly || !s.coordinate {
+		return
+	}
+
+	close(s.stop)
+	s.wg.Wait()
+
+	if !s.setLeader(false) {
+		return
+	}
+
+	if err := s.repo.ReleaseLease(context.Background(), leaseName, s.name); err != nil {
+		s.log.Error().Err(err).Msg("could not release lease")
+	}
+}
+
+func (s *service) renew() {
+	ok, err := s.repo.AcquireLease(context.Background(), leaseName, s.name, s.ttl)
+	if err != nil {
+		s.log.Error().Err(err).Msg("could not acquire lease")
+
+		// the lease can't be trusted once it might have expired
+		ok = false
+	}
+
+	if was := s.setLeader(ok); was != ok {
+		if ok {
+			s.log.Info().Msg("instance is now active")
+		} else {
+			s.log.Warn().Msg("instance is now on standby, another instance holds the lease")
+		}
+	}
+
+	if ok && time.Since(s.lastCleanup) > time.Hour {
+		s.lastCleanup = time.Now()
+
+		if err := s.repo.DeleteReleaseClaims(context.Background(), time.Now().Add(-claimRetention)); err != nil {
+			s.log.Error().Err(err).Msg("could not delete old release claims")
+		}
+	}
+}
+
+// ClaimRelease returns true if this instance should run the actions for the release
+func (s *service) ClaimRelease(ctx context.Context, release *domain.Release) bool {
+	if !s.IsLeader() {
+		return false
+	}
+
+	if !s.coordinate {
+		return true
+	}
+
+	ok, err := s.repo.ClaimRelease(ctx, claimKey(release), s.name)
+	if err != nil {
+		// rather grab twice than miss the release
+		s.log.Error().Err(err).Msgf("could not claim release: %v", release.TorrentName)
+		return true
+	}
+
+	return ok
+}
+
+// UnclaimRelease gives up the claim on a release whose actions were all rejected or failed,
+// so another instance seeing the same announce can still run its actions
+func (s *service) UnclaimRelease(ctx context.Context, release *domain.Release) {
+	if !s.coordinate {
+		return
+	}
+
+	if err := s.repo.DeleteReleaseClaim(ctx, claimKey(release), s.name); err != nil {
+		s.log.Error().Err(err).Msgf("could not unclaim release: %v", release.TorrentName)
+	}
+}
+
+func claimKey(release *domain.Release) string {
+	return fmt.Sprintf("%v|%v|%d", release.Indexer, release.TorrentName, release.FilterID)
+}
@@ -0,0 +1,142 @@
+package instance
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/internal/logger"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type mockInstanceRepo struct {
+	mu      sync.Mutex
+	holder  string
+	expires time.Time
+	claims  map[string]string
+}
+
+func (r *mockInstanceRepo) AcquireLease(ctx context.Context, name string, holder string, ttl time.Duration) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.holder != "" && r.holder != holder && time.Now().Before(r.expires) {
+		return false, nil
+	}
+
+	r.holder = holder
+	r.expires = time.Now().Add(ttl)
+
+	return true, nil
+}
+
+func (r *mockInstanceRepo) ReleaseLease(ctx context.Context, name string, holder string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.holder == holder {
+		r.holder = ""
+	}
+
+	return nil
+}
+
+func (r *mockInstanceRepo) ClaimRelease(ctx context.Context, key string, holder string) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.claims[key]; ok {
+		return false, nil
+	}
+
+	r.claims[key] = holder
+
+	return true, nil
+}
+
+func (r *mockInstanceRepo) DeleteReleaseClaim(ctx context.Context, key string, holder string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.claims[key] == holder {
+		delete(r.claims, key)
+	}
+
+	return nil
+}
+
+func (r *mockInstanceRepo) DeleteReleaseClaims(ctx context.Context, before time.Time) error {
+	return nil
+}
+
+func TestService_leaseHandover(t *testing.T) {
+	repo := &mockInstanceRepo{claims: map[string]string{}}
+
+	config := func(name string) *domain.Config {
+		return &domain.Config{DatabaseType: "postgres", InstanceCoordinate: true, InstanceName: name, InstanceLeaseTimeout: 60}
+	}
+
+	a := NewService(logger.Mock(), config("a"), repo)
+	b := NewService(logger.Mock(), config("b"), repo)
+
+	a.Start()
+	b.Start()
+
+	assert.True(t, a.IsLeader())
+	assert.False(t, b.IsLeader())
+
+	release := &domain.Release{Indexer: "mock", TorrentName: "That.Movie.2022.1080p.BluRay.x264-GROUP", FilterID: 1}
+
+	assert.False(t, b.ClaimRelease(context.Background(), release))
+	assert.True(t, a.ClaimRelease(context.Background(), release))
+
+	// the same release is only handled once
+	assert.False(t, a.ClaimRelease(context.Background(), release))
+
+	// unless its actions failed, then it can be claimed again
+	b.UnclaimRelease(context.Background(), release)
+	assert.False(t, a.ClaimRelease(context.Background(), release))
+
+	a.UnclaimRelease(context.Background(), release)
+	assert.True(t, a.ClaimRelease(context.Background(), release))
+
+	a.Stop()
+	assert.False(t, a.IsLeader())
+
+	// standby takes over on the next renewal
+	b.(*service).renew()
+	assert.True(t, b.IsLeader())
+
+	b.Stop()
+}
+
+func TestService_withoutCoordination(t *testing.T) {
+	repo := &mockInstanceRepo{claims: map[string]string{}}
+	release := &domain.Release{Indexer: "mock", TorrentName: "That.Movie.2022.1080p.BluRay.x264-GROUP", FilterID: 1}
+
+	sqlite := NewService(logger.Mock(), &domain.Config{DatabaseType: "sqlite"}, repo)
+	sqlite.Start()
+
+	assert.True(t, sqlite.IsLeader())
+	assert.True(t, sqlite.ClaimRelease(context.Background(), release))
+	assert.True(t, sqlite.ClaimRelease(context.Background(), release))
+	assert.Empty(t, repo.claims)
+
+	// postgres alone doesn't mean several instances share it
+	postgres := NewService(logger.Mock(), &domain.Config{DatabaseType: "postgres"}, repo)
+	postgres.Start()
+
+	assert.True(t, postgres.IsLeader())
+	assert.True(t, postgres.ClaimRelease(context.Background(), release))
+	assert.Empty(t, repo.claims)
+
+	readOnly := NewService(logger.Mock(), &domain.Config{DatabaseType: "postgres", InstanceCoordinate: true, InstanceReadOnly: true}, repo)
+	readOnly.Start()
+
+	assert.False(t, readOnly.IsLeader())
+	assert.False(t, readOnly.ClaimRelease(context.Background(), release))
+	assert.Equal(t, "", repo.holder)
+}
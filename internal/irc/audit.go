@@ -0,0 +1,49 @@
+package irc
+
+import (
+	"context"
+
+	"github.com/autobrr/autobrr/internal/domain"
+)
+
+// auditSnapshot returns the network with its channels to diff for the audit log, nil if it can't be found
+func (s *service) auditSnapshot(ctx context.Context, id int64) *domain.IrcNetwork {
+	if s.auditSvc == nil {
+		return nil
+	}
+
+	network, err := s.GetNetworkByID(ctx, id)
+	if err != nil {
+		s.log.Warn().Err(err).Msgf("could not find network %v for audit log", id)
+		return nil
+	}
+
+	return network
+}
+
+// auditUpdated records the changes since the before snapshot
+func (s *service) auditUpdated(ctx context.Context, before *domain.IrcNetwork) {
+	if before == nil {
+		return
+	}
+
+	if after := s.auditSnapshot(ctx, before.ID); after != nil {
+		s.auditSvc.Record(ctx, domain.AuditEntityIrcNetwork, after.ID, after.Name, before, after)
+	}
+}
+
+// auditCreated records a new network
+func (s *service) auditCreated(ctx context.Context, id int64) {
+	if after := s.auditSnapshot(ctx, id); after != nil {
+		s.auditSvc.Record(ctx, domain.AuditEntityIrcNetwork, after.ID, after.Name, nil, after)
+	}
+}
+
+// auditDeleted records a deleted network with the values it had
+func (s *service) auditDeleted(ctx context.Context, before *domain.IrcNetwork) {
+	if s.auditSvc == nil || before == nil {
+		return
+	}
+
+	s.auditSvc.Record(ctx, domain.AuditEntityIrcNetwork, before.ID, before.Name, before, nil)
+}
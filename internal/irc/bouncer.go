@@ -0,0 +1,62 @@
+package irc
+
+import (
+	"time"
+
+	"github.com/autobrr/autobrr/internal/domain"
+
+	"github.com/ergochat/irc-go/ircevent"
+	"github.com/ergochat/irc-go/ircmsg"
+)
+
+// setupBouncer makes the client log in to a ZNC or soju bouncer.
+//
+// The user, client and network from the server password are sent as username and only the password as PASS,
+// both bouncers accept that. NickServ is left to the bouncer, so SASL PLAIN with the NickServ account is not used.
+// server-time is requested to tell buffer playback from new messages.
+func setupBouncer(client *ircevent.Connection, network *domain.IrcNetwork) error {
+	client.SASLLogin = ""
+	client.SASLPassword = ""
+	client.RequestCaps = append(client.RequestCaps, "server-time")
+
+	// without password the bouncer identifies us by the client certificate
+	if network.Pass == "" {
+		return nil
+	}
+
+	login, err := domain.ParseBouncerLogin(network.Pass)
+	if err != nil {
+		return err
+	}
+
+	client.User = login.Username()
+	client.Password = login.Password
+
+	return nil
+}
+
+// isPlayback reports whether the message is replayed from the bouncer buffer.
+// Replayed messages keep the server-time of when the bouncer received them, which is before we connected.
+func (h *Handler) isPlayback(msg ircmsg.Message) bool {
+	h.m.RLock()
+	bouncer := h.network.Bouncer
+	connectedSince := h.connectedSince
+	h.m.RUnlock()
+
+	if !bouncer || connectedSince.IsZero() {
+		return false
+	}
+
+	ok, value := msg.GetTag("time")
+	if !ok {
+		return false
+	}
+
+	sent, err := time.Parse(time.RFC3339Nano, value)
+	if err != nil {
+		h.log.Trace().Err(err).Msgf("could not parse server-time: %v", value)
+		return false
+	}
+
+	return sent.Before(connectedSince)
+}
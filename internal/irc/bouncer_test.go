@@ -0,0 +1,136 @@
+package irc
+
+import (
+	"bufio"
+	"fmt"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/autobrr/autobrr/internal/domain"
+
+	"github.com/ergochat/irc-go/ircevent"
+	"github.com/ergochat/irc-go/ircmsg"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_setupBouncer(t *testing.T) {
+	client := &ircevent.Connection{User: "nick", Password: "user/libera:secret", SASLLogin: "nick", SASLPassword: "nickserv"}
+
+	err := setupBouncer(client, &domain.IrcNetwork{Bouncer: true, Pass: "user/libera:secret"})
+	assert.NoError(t, err)
+
+	assert.Equal(t, "user/libera", client.User)
+	assert.Equal(t, "secret", client.Password)
+	assert.Empty(t, client.SASLLogin)
+	assert.Empty(t, client.SASLPassword)
+	assert.Equal(t, []string{"server-time"}, client.RequestCaps)
+}
+
+func TestHandler_Run_bouncer(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	defer ln.Close()
+
+	lines := make(chan string, 100)
+	registered := make(chan struct{})
+
+	// the bouncer offers sasl, like soju does
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		scanner := bufio.NewScanner(conn)
+		for scanner.Scan() {
+			line := scanner.Text()
+			lines <- line
+
+			switch {
+			case strings.HasPrefix(line, "CAP LS"):
+				fmt.Fprintf(conn, ":bnc.test CAP * LS :sasl server-time\r\n")
+			case strings.HasPrefix(line, "CAP REQ"):
+				fmt.Fprintf(conn, ":bnc.test CAP * ACK :%v\r\n", strings.TrimPrefix(line, "CAP REQ "))
+			case strings.HasPrefix(line, "USER"):
+				fmt.Fprintf(conn, ":bnc.test 001 autobrr :Welcome\r\n")
+				fmt.Fprintf(conn, ":bnc.test 376 autobrr :End of MOTD\r\n")
+				close(registered)
+			case strings.HasPrefix(line, "QUIT"):
+				return
+			}
+		}
+	}()
+
+	addr := ln.Addr().(*net.TCPAddr)
+	network := domain.IrcNetwork{
+		Server:   addr.IP.String(),
+		Port:     addr.Port,
+		Bouncer:  true,
+		Pass:     "user/libera:secret",
+		NickServ: domain.NickServ{Account: "autobrr", Password: "nickserv"},
+	}
+
+	h := NewHandler(zerolog.Nop(), network, nil, nil, nil, nil, ConnectionTimeouts{})
+
+	done := make(chan error)
+	go func() {
+		done <- h.Run()
+	}()
+
+	select {
+	case <-registered:
+	case <-time.After(5 * time.Second):
+		t.Fatal("not registered with the bouncer")
+	}
+
+	h.Stop()
+	<-done
+
+	var received []string
+	for len(lines) > 0 {
+		received = append(received, <-lines)
+	}
+
+	assert.Contains(t, received, "PASS secret")
+	assert.Contains(t, received, "CAP REQ server-time")
+	for _, line := range received {
+		assert.NotContains(t, line, "sasl")
+		assert.False(t, strings.HasPrefix(line, "AUTHENTICATE"), line)
+	}
+
+	assert.False(t, h.client.UseSASL)
+	assert.Empty(t, h.client.SASLLogin)
+	assert.Empty(t, h.client.SASLPassword)
+}
+
+func TestHandler_isPlayback(t *testing.T) {
+	connectedSince := time.Date(2022, 10, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		bouncer bool
+		line    string
+		want    bool
+	}{
+		{name: "playback", bouncer: true, line: "@time=2022-10-01T11:59:58.123Z :bot!bot@host PRIVMSG #announce :old", want: true},
+		{name: "new", bouncer: true, line: "@time=2022-10-01T12:00:01.000Z :bot!bot@host PRIVMSG #announce :new", want: false},
+		{name: "no_time", bouncer: true, line: ":bot!bot@host PRIVMSG #announce :new", want: false},
+		{name: "invalid_time", bouncer: true, line: "@time=yesterday :bot!bot@host PRIVMSG #announce :new", want: false},
+		{name: "not_bouncer", bouncer: false, line: "@time=2022-10-01T11:59:58.123Z :bot!bot@host PRIVMSG #announce :old", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := NewHandler(zerolog.Nop(), domain.IrcNetwork{Bouncer: tt.bouncer}, nil, nil, nil, nil, ConnectionTimeouts{})
+			h.connectedSince = connectedSince
+
+			msg, err := ircmsg.ParseLine(tt.line)
+			assert.NoError(t, err)
+
+			assert.Equal(t, tt.want, h.isPlayback(msg))
+		})
+	}
+}
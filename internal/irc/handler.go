@@ -1,21 +1,22 @@
 package irc
 
 import (
-	"crypto/tls"
 	"fmt"
+	"sort"
 	"strings"
 	"time"
 
 	"github.com/autobrr/autobrr/internal/announce"
 	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/internal/metrics"
 	"github.com/autobrr/autobrr/internal/notification"
 	"github.com/autobrr/autobrr/internal/release"
 	"github.com/autobrr/autobrr/pkg/errors"
 
+	"github.com/asaskevich/EventBus"
 	"github.com/avast/retry-go"
 	"github.com/dcarbone/zadapters/zstdlog"
 	"github.com/ergochat/irc-go/ircevent"
-	"github.com/ergochat/irc-go/ircfmt"
 	"github.com/ergochat/irc-go/ircmsg"
 	"github.com/rs/zerolog"
 	"github.com/sasha-s/go-deadlock"
@@ -28,13 +29,19 @@ type channelHealth struct {
 	monitoring      bool
 	monitoringSince time.Time
 	lastAnnounce    time.Time
+
+	// announce rate is kept across reconnects so slow channels are not flagged too early
+	announceInterval time.Duration
+	announceSamples  int
+	silent           bool
 }
 
-// SetLastAnnounce set last announce to now
-func (ch *channelHealth) SetLastAnnounce() {
+// SetLastAnnounce set last announce to now, returns true if the channel was flagged as silent
+func (ch *channelHealth) SetLastAnnounce() bool {
 	ch.m.Lock()
-	ch.lastAnnounce = time.Now()
-	ch.m.Unlock()
+	defer ch.m.Unlock()
+
+	return ch.recordAnnounce(time.Now())
 }
 
 // SetMonitoring set monitoring and time
@@ -51,6 +58,7 @@ func (ch *channelHealth) resetMonitoring() {
 	ch.monitoring = false
 	ch.monitoringSince = time.Time{}
 	ch.lastAnnounce = time.Time{}
+	ch.silent = false
 	ch.m.Unlock()
 }
 
@@ -59,13 +67,18 @@ type Handler struct {
 	network             *domain.IrcNetwork
 	releaseSvc          release.Service
 	notificationService notification.Service
+	bus                 EventBus.Bus
 	announceProcessors  map[string]announce.Processor
 	definitions         map[string]*domain.IndexerDefinition
 
 	client *ircevent.Connection
 	m      deadlock.RWMutex
 
+	timeouts  ConnectionTimeouts
+	probeStop chan struct{}
+
 	connectedSince       time.Time
+	lastActivity         time.Time
 	haveDisconnected     bool
 	manuallyDisconnected bool
 
@@ -80,13 +93,15 @@ type Handler struct {
 	saslauthed    bool
 }
 
-func NewHandler(log zerolog.Logger, network domain.IrcNetwork, definitions []*domain.IndexerDefinition, releaseSvc release.Service, notificationSvc notification.Service) *Handler {
+func NewHandler(log zerolog.Logger, network domain.IrcNetwork, definitions []*domain.IndexerDefinition, releaseSvc release.Service, notificationSvc notification.Service, bus EventBus.Bus, timeouts ConnectionTimeouts) *Handler {
 	h := &Handler{
+		timeouts:            timeouts,
 		log:                 log.With().Str("network", network.Server).Logger(),
 		client:              nil,
 		network:             &network,
 		releaseSvc:          releaseSvc,
 		notificationService: notificationSvc,
+		bus:                 bus,
 		definitions:         map[string]*domain.IndexerDefinition{},
 		announceProcessors:  map[string]announce.Processor{},
 		validAnnouncers:     map[string]struct{}{},
@@ -107,10 +122,6 @@ func (h *Handler) InitIndexers(definitions []*domain.IndexerDefinition) {
 	// Networks can be shared by multiple indexers but channels are unique
 	// so let's add a new AnnounceProcessor per channel
 	for _, definition := range definitions {
-		if _, ok := h.definitions[definition.Identifier]; ok {
-			continue
-		}
-
 		h.definitions[definition.Identifier] = definition
 
 		// indexers can use multiple channels, but it's not common, but let's handle that anyway.
@@ -118,6 +129,12 @@ func (h *Handler) InitIndexers(definitions []*domain.IndexerDefinition) {
 			// some channels are defined in mixed case
 			channel = strings.ToLower(channel)
 
+			// definitions can be reloaded, keep the running processor and let it use the new definition
+			if processor, ok := h.announceProcessors[channel]; ok {
+				processor.UpdateIndexer(definition)
+				continue
+			}
+
 			h.announceProcessors[channel] = announce.NewAnnounceProcessor(h.log, h.releaseSvc, definition)
 
 			h.channelHealth[channel] = &channelHealth{
@@ -150,29 +167,57 @@ func (h *Handler) Run() error {
 
 	addr := fmt.Sprintf("%v:%d", h.network.Server, h.network.Port)
 
+	// the client connects through the relay, it dials the server through the proxy
+	relay, err := newProxyRelay(h.log, h.network.Proxy, addr, h.timeouts.writeTimeout())
+	if err != nil {
+		return err
+	}
+	defer relay.Close()
+
 	subLogger := zstdlog.NewStdLoggerWithLevel(h.log.With().Logger(), zerolog.TraceLevel)
 
+	h.m.Lock()
+
 	h.client = &ircevent.Connection{
 		Nick:          h.network.NickServ.Account,
 		User:          h.network.NickServ.Account,
 		RealName:      h.network.NickServ.Account,
 		Password:      h.network.Pass,
-		SASLLogin:     h.network.NickServ.Account,
-		SASLPassword:  h.network.NickServ.Password,
 		SASLOptional:  true,
-		Server:        addr,
-		KeepAlive:     4 * time.Minute,
-		Timeout:       2 * time.Minute,
+		Server:        relay.Addr(),
+		KeepAlive:     h.timeouts.keepAlive(),
+		Timeout:       h.timeouts.writeTimeout(),
 		ReconnectFreq: 15 * time.Second,
 		Version:       "autobrr",
 		QuitMessage:   "bye from autobrr",
 		Debug:         true,
 		Log:           subLogger,
 	}
+	h.m.Unlock()
 
 	if h.network.TLS {
+		tlsConfig, err := tlsClientConfig(h.network)
+		if err != nil {
+			return err
+		}
+
+		// the client may connect to the proxy relay, the server name is sent for SNI
+		tlsConfig.ServerName = h.network.Server
+
 		h.client.UseTLS = true
-		h.client.TLSConfig = &tls.Config{InsecureSkipVerify: true}
+		h.client.TLSConfig = tlsConfig
+	}
+
+	if h.network.Bouncer {
+		if err := setupBouncer(h.client, h.network); err != nil {
+			return err
+		}
+	}
+
+	setupSASL(h.client, h.network)
+
+	if h.network.SASLMechanism == domain.IrcSASLMechanismExternal {
+		setupSASLExternal(h.client, h.network)
 	}
 
 	h.client.AddConnectCallback(h.onConnect)
@@ -186,6 +231,11 @@ func (h *Handler) Run() error {
 	h.client.AddCallback("NICK", h.onNick)
 	h.client.AddCallback("903", h.handleSASLSuccess)
 
+	// track last activity from the server
+	for _, cmd := range []string{"PRIVMSG", "NOTICE", "PING", "PONG", "JOIN", "PART", "QUIT", "NICK", "KICK", "TOPIC", "MODE"} {
+		h.client.AddCallback(cmd, h.onActivity)
+	}
+
 	//h.setConnectionStatus()
 	h.saslauthed = false
 
@@ -199,6 +249,8 @@ func (h *Handler) Run() error {
 			func() error {
 				h.log.Debug().Msgf("connect attempt %d", connectAttempts)
 
+				setupSASL(h.client, h.network)
+
 				if err := h.client.Connect(); err != nil {
 					connectAttempts++
 					return err
@@ -320,9 +372,18 @@ func (h *Handler) onConnect(m ircmsg.Message) {
 
 	h.setConnectionStatus()
 
+	// detect half-open connections
+	h.startProbe()
+
 	func() {
 		h.m.Lock()
+		metrics.IRCConnected.Set(1, h.network.Name)
+
+		h.publishStatus(domain.IrcStatusEventConnected, "")
+
 		if h.haveDisconnected {
+			metrics.IRCReconnects.Inc(h.network.Name)
+
 			h.notificationService.Send(domain.NotificationEventIRCReconnected, domain.NotificationPayload{
 				Subject: "IRC Reconnected",
 				Message: fmt.Sprintf("Network: %v", h.network.Name),
@@ -338,9 +399,71 @@ func (h *Handler) onConnect(m ircmsg.Message) {
 
 	time.Sleep(1 * time.Second)
 
+	// reclaim our nick before we authenticate and join channels
+	if h.regainNick() {
+		return
+	}
+
 	h.authenticate()
 }
 
+// regainNick sends the NickServ regain command if our preferred nick is in use.
+// Returns true if we should wait for the NICK event before authenticating.
+func (h *Handler) regainNick() bool {
+	h.m.RLock()
+	regain := h.network.NickServ.Regain
+	password := h.network.NickServ.Password
+	bouncer := h.network.Bouncer
+	h.m.RUnlock()
+
+	// the bouncer keeps the nick on the network
+	if bouncer || regain == "" || password == "" {
+		return false
+	}
+
+	currentNick := h.CurrentNick()
+	preferredNick := h.PreferredNick()
+
+	if currentNick == preferredNick {
+		return false
+	}
+
+	cmd, err := buildNickServRegainCommand(regain, preferredNick, password)
+	if err != nil {
+		h.log.Error().Err(err).Msg("could not build NickServ regain command")
+		return false
+	}
+
+	h.log.Debug().Msgf("nick %v in use, connected as %v: sending NickServ regain", preferredNick, currentNick)
+
+	if err := h.client.Send("PRIVMSG", "NickServ", cmd); err != nil {
+		h.log.Error().Err(err).Msg("error sending NickServ regain")
+		return false
+	}
+
+	// give NickServ some time to free the nick and then ask for it again
+	time.Sleep(1 * time.Second)
+
+	if h.CurrentNick() != preferredNick {
+		if err := h.client.Send("NICK", preferredNick); err != nil {
+			h.log.Error().Err(err).Msgf("error sending NICK %v", preferredNick)
+			return false
+		}
+	}
+
+	// if the regain never goes through continue on the current nick
+	time.AfterFunc(nickServRegainTimeout, func() {
+		if !h.client.Connected() || h.CurrentNick() == h.PreferredNick() {
+			return
+		}
+
+		h.log.Warn().Msgf("could not regain nick %v, continuing as %v", preferredNick, h.CurrentNick())
+		h.authenticate()
+	})
+
+	return true
+}
+
 // onDisconnect is the disconnect callback
 func (h *Handler) onDisconnect(m ircmsg.Message) {
 	h.log.Debug().Msgf("DISCONNECT")
@@ -350,6 +473,8 @@ func (h *Handler) onDisconnect(m ircmsg.Message) {
 	// reset connectedSince
 	h.connectedSince = time.Time{}
 
+	h.stopProbe()
+
 	// reset channelHealth
 	for _, ch := range h.channelHealth {
 		ch.resetMonitoring()
@@ -360,6 +485,10 @@ func (h *Handler) onDisconnect(m ircmsg.Message) {
 
 	h.haveDisconnected = true
 
+	metrics.IRCConnected.Set(0, h.network.Name)
+
+	h.publishStatus(domain.IrcStatusEventDisconnected, "")
+
 	// check if we are responsible for disconnect
 	if !h.manuallyDisconnected {
 		// only send notification if we did not initiate disconnect/restart/stop
@@ -455,7 +584,8 @@ func (h *Handler) authenticate() bool {
 		return true
 	}
 
-	if !h.saslauthed && h.network.NickServ.Password != "" {
+	// the bouncer is already identified with NickServ
+	if !h.saslauthed && !h.network.Bouncer && h.network.NickServ.Password != "" {
 		h.log.Trace().Msg("on connect not authenticated and password not empty: send nickserv identify")
 		if err := h.NickServIdentify(h.network.NickServ.Password); err != nil {
 			h.log.Error().Stack().Err(err).Msg("error nickserv")
@@ -549,6 +679,12 @@ func (h *Handler) onMessage(msg ircmsg.Message) {
 		return
 	}
 
+	// announces from the bouncer buffer are old and have been seen before
+	if h.isPlayback(msg) {
+		h.log.Debug().Str("channel", channel).Str("user", announcer).Msgf("skipping playback: %v", h.cleanMessage(message))
+		return
+	}
+
 	// clean message
 	cleanedMsg := h.cleanMessage(message)
 	h.log.Debug().Str("channel", channel).Str("user", announcer).Msgf("%v", cleanedMsg)
@@ -578,7 +714,9 @@ func (h *Handler) sendToAnnounceProcessor(channel string, msg string) error {
 	}
 
 	if v, ok := h.channelHealth[channel]; ok {
-		v.SetLastAnnounce()
+		if wasSilent := v.SetLastAnnounce(); wasSilent {
+			h.notifyAnnounceResumed(channel)
+		}
 	}
 
 	return nil
@@ -633,6 +771,10 @@ func (h *Handler) handlePart(msg ircmsg.Message) {
 
 	// TODO remove announceProcessor
 
+	h.m.RLock()
+	h.publishStatus(domain.IrcStatusEventParted, channel)
+	h.m.RUnlock()
+
 	h.log.Debug().Msgf("Left channel %v", channel)
 }
 
@@ -690,6 +832,8 @@ func (h *Handler) handleJoined(msg ircmsg.Message) {
 
 		h.log.Trace().Msgf("add channel health monitoring: %v", channel)
 	}
+
+	h.publishStatus(domain.IrcStatusEventJoined, channel)
 	h.m.Unlock()
 
 	// if not valid it's considered an extra channel
@@ -701,6 +845,21 @@ func (h *Handler) handleJoined(msg ircmsg.Message) {
 	h.log.Info().Msgf("Monitoring channel %v", channel)
 }
 
+// publishStatus publishes a change of the connection or a channel for the live event stream, callers hold the lock
+func (h *Handler) publishStatus(eventType domain.IrcStatusEventType, channel string) {
+	if h.bus == nil {
+		return
+	}
+
+	h.bus.Publish("irc:status", &domain.IrcStatusEvent{
+		Timestamp: time.Now(),
+		Type:      eventType,
+		NetworkID: h.network.ID,
+		Network:   h.network.Name,
+		Channel:   channel,
+	})
+}
+
 // sendConnectCommands sends invite commands
 func (h *Handler) sendConnectCommands(msg string) error {
 	connectCommand := strings.ReplaceAll(msg, "/msg", "")
@@ -850,7 +1009,7 @@ func (h *Handler) isValidHandlerChannel(channel string) bool {
 
 // irc line can contain lots of extra stuff like color so lets clean that
 func (h *Handler) cleanMessage(message string) string {
-	return ircfmt.Strip(message)
+	return domain.StripIRCFormatting(message)
 }
 
 func (h *Handler) addConnectError(message string) {
@@ -860,6 +1019,57 @@ func (h *Handler) addConnectError(message string) {
 	h.connectionErrors = append(h.connectionErrors, message)
 }
 
+// onActivity records the last time we received something from the server
+func (h *Handler) onActivity(msg ircmsg.Message) {
+	h.m.Lock()
+	h.lastActivity = time.Now()
+	h.m.Unlock()
+}
+
+// Status returns a snapshot of the current connection state and joined channels.
+// It is safe to call while the connection is running.
+func (h *Handler) Status() domain.IrcNetworkStatus {
+	h.m.RLock()
+	defer h.m.RUnlock()
+
+	status := domain.IrcNetworkStatus{
+		ID:           h.network.ID,
+		Name:         h.network.Name,
+		Server:       h.network.Server,
+		Enabled:      h.network.Enabled,
+		Channels:     []domain.ChannelHealth{},
+		LastActivity: h.lastActivity,
+	}
+
+	// nick and connected state is only available with an active connection
+	if h.client != nil && h.client.Connected() {
+		status.Connected = h.connectedSince != time.Time{}
+		status.ConnectedSince = h.connectedSince
+		status.CurrentNick = h.client.CurrentNick()
+		status.PreferredNick = h.client.PreferredNick()
+	}
+
+	for _, ch := range h.channelHealth {
+		ch.m.RLock()
+		if ch.monitoring {
+			status.Channels = append(status.Channels, domain.ChannelHealth{
+				Name:            ch.name,
+				Monitoring:      ch.monitoring,
+				MonitoringSince: ch.monitoringSince,
+				LastAnnounce:    ch.lastAnnounce,
+				Silent:          ch.silent,
+			})
+		}
+		ch.m.RUnlock()
+	}
+
+	sort.SliceStable(status.Channels, func(i, j int) bool {
+		return status.Channels[i].Name < status.Channels[j].Name
+	})
+
+	return status
+}
+
 // Healthy if enabled but not monitoring return false,
 //
 // if any channel is enabled but not monitoring return false,
@@ -0,0 +1,73 @@
+package irc
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/autobrr/autobrr/internal/domain"
+
+	"github.com/ergochat/irc-go/ircmsg"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHandler_Status(t *testing.T) {
+	network := domain.IrcNetwork{
+		ID:      1,
+		Name:    "Test",
+		Enabled: true,
+		Server:  "irc.example.com",
+	}
+
+	definitions := []*domain.IndexerDefinition{
+		{
+			Identifier: "mock",
+			IRC: &domain.IndexerIRC{
+				Channels:   []string{"#Announce", "#other"},
+				Announcers: []string{"bot"},
+			},
+			Parse: &domain.IndexerParse{
+				Lines: []domain.IndexerParseExtract{{Pattern: `(.*)`, Vars: []string{"torrentName"}}},
+			},
+		},
+	}
+
+	h := NewHandler(zerolog.Nop(), network, definitions, nil, nil, nil, ConnectionTimeouts{})
+
+	status := h.Status()
+	assert.Equal(t, int64(1), status.ID)
+	assert.False(t, status.Connected)
+	assert.Empty(t, status.Channels)
+	assert.True(t, status.LastActivity.IsZero())
+
+	h.channelHealth["#announce"].SetMonitoring()
+	h.onActivity(ircmsg.Message{Command: "PING"})
+
+	status = h.Status()
+	assert.Len(t, status.Channels, 1)
+	assert.Equal(t, "#announce", status.Channels[0].Name)
+	assert.WithinDuration(t, time.Now(), status.LastActivity, time.Second)
+}
+
+func TestHandler_Status_concurrent(t *testing.T) {
+	h := NewHandler(zerolog.Nop(), domain.IrcNetwork{Name: "Test"}, nil, nil, nil, nil, ConnectionTimeouts{})
+	h.AddChannelHealth("#announce")
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			h.onActivity(ircmsg.Message{Command: "PRIVMSG"})
+			h.channelHealth["#announce"].SetLastAnnounce()
+		}()
+		go func() {
+			defer wg.Done()
+			_ = h.Status()
+		}()
+	}
+	wg.Wait()
+
+	assert.Len(t, h.Status().Channels, 1)
+}
@@ -0,0 +1,54 @@
+package irc
+
+import (
+	"bytes"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/autobrr/autobrr/pkg/errors"
+)
+
+// nickServRegainTimeout is how long we wait for NickServ to hand back our nick
+// before we continue to authenticate and join channels on the current nick.
+const nickServRegainTimeout = 15 * time.Second
+
+// nickServRegainCommands are the common NickServ variants to reclaim a nick in use.
+// GHOST and RELEASE only free the nick, REGAIN and RECOVER also change it for us.
+var nickServRegainCommands = map[string]string{
+	"GHOST":   "GHOST {{ .Nick }} {{ .Password }}",
+	"REGAIN":  "REGAIN {{ .Nick }} {{ .Password }}",
+	"RECOVER": "RECOVER {{ .Nick }} {{ .Password }}",
+	"RELEASE": "RELEASE {{ .Nick }} {{ .Password }}",
+}
+
+type nickServRegainVars struct {
+	Nick     string
+	Password string
+}
+
+// buildNickServRegainCommand renders the regain command.
+// The regain setting is either one of the common variants or a custom template.
+func buildNickServRegainCommand(regain, nick, password string) (string, error) {
+	regain = strings.TrimSpace(regain)
+	if regain == "" {
+		return "", errors.New("empty regain command")
+	}
+
+	tmpl := regain
+	if cmd, ok := nickServRegainCommands[strings.ToUpper(regain)]; ok {
+		tmpl = cmd
+	}
+
+	t, err := template.New("regain").Parse(tmpl)
+	if err != nil {
+		return "", errors.Wrap(err, "could not parse regain command: %v", regain)
+	}
+
+	var buf bytes.Buffer
+	if err := t.Execute(&buf, nickServRegainVars{Nick: nick, Password: password}); err != nil {
+		return "", errors.Wrap(err, "could not execute regain command: %v", regain)
+	}
+
+	return strings.TrimSpace(buf.String()), nil
+}
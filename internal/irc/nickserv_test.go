@@ -0,0 +1,192 @@
+package irc
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/autobrr/autobrr/internal/domain"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_buildNickServRegainCommand(t *testing.T) {
+	tests := []struct {
+		name    string
+		regain  string
+		want    string
+		wantErr bool
+	}{
+		{name: "ghost", regain: "GHOST", want: "GHOST autobrr secret"},
+		{name: "regain_lowercase", regain: "regain", want: "REGAIN autobrr secret"},
+		{name: "recover", regain: "RECOVER", want: "RECOVER autobrr secret"},
+		{name: "release", regain: "RELEASE", want: "RELEASE autobrr secret"},
+		{name: "custom", regain: "GHOST {{ .Nick }}", want: "GHOST autobrr"},
+		{name: "empty", regain: "", wantErr: true},
+		{name: "bad_template", regain: "GHOST {{ .Nick", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := buildNickServRegainCommand(tt.regain, "autobrr", "secret")
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+type mockNotificationService struct{}
+
+func (m *mockNotificationService) Find(ctx context.Context, params domain.NotificationQueryParams) ([]domain.Notification, int, error) {
+	return nil, 0, nil
+}
+func (m *mockNotificationService) FindByID(ctx context.Context, id int) (*domain.Notification, error) {
+	return nil, nil
+}
+func (m *mockNotificationService) Store(ctx context.Context, n domain.Notification) (*domain.Notification, error) {
+	return nil, nil
+}
+func (m *mockNotificationService) Update(ctx context.Context, n domain.Notification) (*domain.Notification, error) {
+	return nil, nil
+}
+func (m *mockNotificationService) Delete(ctx context.Context, id int) error { return nil }
+func (m *mockNotificationService) Send(event domain.NotificationEvent, payload domain.NotificationPayload) {
+}
+func (m *mockNotificationService) Test(ctx context.Context, notification domain.Notification) error {
+	return nil
+}
+
+// fakeNickServer answers the nick with 433 until it is regained through NickServ
+func fakeNickServer(ln net.Listener, lines chan<- string) {
+	conn, err := ln.Accept()
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+
+	send := func(format string, args ...interface{}) {
+		fmt.Fprintf(conn, format+"\r\n", args...)
+	}
+
+	registered := false
+	current := ""
+
+	scanner := bufio.NewScanner(conn)
+	for scanner.Scan() {
+		line := scanner.Text()
+		lines <- line
+
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+
+		switch fields[0] {
+		case "CAP":
+			if fields[1] == "LS" {
+				send(":irc.test CAP * LS :")
+			}
+
+		case "NICK":
+			if registered {
+				continue
+			}
+			if fields[1] == "autobrr" {
+				send(":irc.test 433 * autobrr :Nickname is already in use")
+				continue
+			}
+			current = fields[1]
+			registered = true
+			send(":irc.test 001 %s :Welcome", current)
+			send(":irc.test 376 %s :End of MOTD", current)
+
+		case "PRIVMSG":
+			msg := strings.TrimPrefix(strings.Join(fields[2:], " "), ":")
+			switch {
+			case strings.HasPrefix(msg, "REGAIN autobrr secret"):
+				send(":%s!user@host NICK :autobrr", current)
+				current = "autobrr"
+			case strings.HasPrefix(msg, "IDENTIFY"):
+				send(":irc.test MODE %s :+r", current)
+			}
+
+		case "JOIN":
+			send(":%s!user@host JOIN %s", current, fields[1])
+			send(":irc.test 366 %s %s :End of /NAMES list", current, fields[1])
+
+		case "QUIT":
+			return
+		}
+	}
+}
+
+func TestHandler_regainNick(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+
+	lines := make(chan string, 64)
+	go fakeNickServer(ln, lines)
+
+	network := domain.IrcNetwork{
+		Name:    "Test",
+		Enabled: true,
+		Server:  "127.0.0.1",
+		Port:    ln.Addr().(*net.TCPAddr).Port,
+		NickServ: domain.NickServ{
+			Account:  "autobrr",
+			Password: "secret",
+			Regain:   "REGAIN",
+		},
+		Channels: []domain.IrcChannel{{Name: "#announce"}},
+	}
+
+	h := NewHandler(zerolog.Nop(), network, nil, nil, &mockNotificationService{}, nil, ConnectionTimeouts{})
+
+	go func() {
+		_ = h.Run()
+	}()
+
+	var received []string
+	timeout := time.After(10 * time.Second)
+
+wait:
+	for {
+		select {
+		case line := <-lines:
+			received = append(received, line)
+			if strings.HasPrefix(line, "JOIN #announce") {
+				break wait
+			}
+		case <-timeout:
+			t.Fatalf("timed out waiting for JOIN, received: %v", received)
+		}
+	}
+
+	h.Stop()
+
+	regainIdx, joinIdx := -1, -1
+	for i, line := range received {
+		if strings.HasPrefix(line, "PRIVMSG NickServ :REGAIN autobrr secret") {
+			regainIdx = i
+		}
+		if strings.HasPrefix(line, "JOIN #announce") {
+			joinIdx = i
+		}
+	}
+
+	assert.Contains(t, received, "NICK autobrr_0")
+	assert.NotEqual(t, -1, regainIdx, "regain not sent")
+	assert.Less(t, regainIdx, joinIdx, "regain must be sent before joining channels")
+	assert.Equal(t, "autobrr", h.CurrentNick())
+}
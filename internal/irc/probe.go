@@ -0,0 +1,127 @@
+package irc
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/ergochat/irc-go/ircevent"
+)
+
+const (
+	defaultIrcWriteTimeout = 2 * time.Minute
+	defaultIrcKeepAlive    = 4 * time.Minute
+
+	probePingPrefix = "autobrr-probe-"
+)
+
+// ConnectionTimeouts control how half-open connections are detected
+type ConnectionTimeouts struct {
+	// Read is the time without any data from the server before a PING probe is sent. Zero disables probing.
+	Read time.Duration
+	// Ping is the time to wait for a reply to the probe before forcing a reconnect.
+	Ping time.Duration
+	// Write is the deadline for writes to the server.
+	Write time.Duration
+}
+
+func (t ConnectionTimeouts) writeTimeout() time.Duration {
+	if t.Write <= 0 {
+		return defaultIrcWriteTimeout
+	}
+	return t.Write
+}
+
+func (t ConnectionTimeouts) keepAlive() time.Duration {
+	// ircevent requires KeepAlive to be at least Timeout
+	if t.writeTimeout() > defaultIrcKeepAlive {
+		return t.writeTimeout()
+	}
+	return defaultIrcKeepAlive
+}
+
+func (t ConnectionTimeouts) probeEnabled() bool {
+	return t.Read > 0 && t.Ping > 0
+}
+
+// startProbe starts probing the current connection, any previous probe is stopped
+func (h *Handler) startProbe() {
+	if !h.timeouts.probeEnabled() {
+		return
+	}
+
+	h.m.Lock()
+	defer h.m.Unlock()
+
+	if h.probeStop != nil {
+		close(h.probeStop)
+	}
+
+	h.lastActivity = time.Now()
+	h.probeStop = make(chan struct{})
+
+	go h.probeConnection(h.client, h.probeStop)
+}
+
+// stopProbe stops probing the connection. Callers must hold h.m
+func (h *Handler) stopProbe() {
+	if h.probeStop != nil {
+		close(h.probeStop)
+		h.probeStop = nil
+	}
+}
+
+// probeConnection sends a PING when nothing was read from the server within the read timeout
+// and forces a reconnect if nothing arrives within the ping timeout after that.
+// This catches half-open connections where the server is gone without closing the socket.
+func (h *Handler) probeConnection(client *ircevent.Connection, stop chan struct{}) {
+	interval := h.timeouts.Read
+	if h.timeouts.Ping < interval {
+		interval = h.timeouts.Ping
+	}
+
+	ticker := time.NewTicker(interval / 4)
+	defer ticker.Stop()
+
+	var probeSentAt time.Time
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+		}
+
+		h.m.RLock()
+		lastActivity := h.lastActivity
+		h.m.RUnlock()
+
+		if !probeSentAt.IsZero() {
+			if lastActivity.After(probeSentAt) {
+				h.log.Trace().Msg("connection probe answered")
+				probeSentAt = time.Time{}
+				continue
+			}
+
+			if time.Since(probeSentAt) < h.timeouts.Ping {
+				continue
+			}
+
+			h.log.Warn().Msgf("no reply from server %v within %v after PING, reconnecting", h.network.Server, h.timeouts.Ping)
+			h.addConnectError(fmt.Sprintf("no reply to PING within %v, reconnecting", h.timeouts.Ping))
+
+			client.Reconnect()
+			return
+		}
+
+		if time.Since(lastActivity) < h.timeouts.Read {
+			continue
+		}
+
+		h.log.Debug().Msgf("nothing read from server %v in %v, sending PING", h.network.Server, h.timeouts.Read)
+
+		probeSentAt = time.Now()
+		if err := client.Send("PING", fmt.Sprintf("%s%d", probePingPrefix, probeSentAt.Unix())); err != nil {
+			h.log.Error().Err(err).Msg("could not send PING probe")
+		}
+	}
+}
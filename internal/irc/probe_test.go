@@ -0,0 +1,126 @@
+package irc
+
+import (
+	"bufio"
+	"fmt"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/autobrr/autobrr/internal/domain"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeSilentServer registers clients and then goes quiet, only answering PING if answerPing is set
+func fakeSilentServer(ln net.Listener, answerPing bool, connections chan<- int, lines chan<- string) {
+	for n := 1; ; n++ {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		connections <- n
+
+		go func(conn net.Conn) {
+			defer conn.Close()
+
+			send := func(format string, args ...interface{}) {
+				fmt.Fprintf(conn, format+"\r\n", args...)
+			}
+
+			scanner := bufio.NewScanner(conn)
+			for scanner.Scan() {
+				line := scanner.Text()
+				lines <- line
+
+				fields := strings.Fields(line)
+				if len(fields) < 2 {
+					continue
+				}
+
+				switch fields[0] {
+				case "CAP":
+					if fields[1] == "LS" {
+						send(":irc.test CAP * LS :")
+					}
+				case "NICK":
+					send(":irc.test 001 %s :Welcome", fields[1])
+					send(":irc.test 376 %s :End of MOTD", fields[1])
+				case "PING":
+					if answerPing {
+						send(":irc.test PONG irc.test :%s", fields[1])
+					}
+				}
+			}
+		}(conn)
+	}
+}
+
+func TestHandler_probeConnection(t *testing.T) {
+	tests := []struct {
+		name          string
+		answerPing    bool
+		wantReconnect bool
+	}{
+		{name: "silent_connection_reconnects", answerPing: false, wantReconnect: true},
+		{name: "answered_probe_keeps_connection", answerPing: true, wantReconnect: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ln, err := net.Listen("tcp", "127.0.0.1:0")
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer ln.Close()
+
+			connections := make(chan int, 8)
+			lines := make(chan string, 256)
+			go fakeSilentServer(ln, tt.answerPing, connections, lines)
+
+			network := domain.IrcNetwork{
+				Name:     "Test",
+				Enabled:  true,
+				Server:   "127.0.0.1",
+				Port:     ln.Addr().(*net.TCPAddr).Port,
+				NickServ: domain.NickServ{Account: "autobrr"},
+			}
+
+			// onConnect blocks the read loop for a second, keep the timeouts above that
+			h := NewHandler(zerolog.Nop(), network, nil, nil, &mockNotificationService{}, nil, ConnectionTimeouts{
+				Read: 1200 * time.Millisecond,
+				Ping: 500 * time.Millisecond,
+			})
+
+			go func() {
+				_ = h.Run()
+			}()
+			defer h.Stop()
+
+			assert.Equal(t, 1, <-connections)
+
+			sawProbe := false
+			reconnected := false
+			timeout := time.After(4 * time.Second)
+
+		wait:
+			for {
+				select {
+				case line := <-lines:
+					if strings.HasPrefix(line, "PING "+probePingPrefix) {
+						sawProbe = true
+					}
+				case <-connections:
+					reconnected = true
+					break wait
+				case <-timeout:
+					break wait
+				}
+			}
+
+			assert.True(t, sawProbe, "expected a PING probe on the idle connection")
+			assert.Equal(t, tt.wantReconnect, reconnected)
+		})
+	}
+}
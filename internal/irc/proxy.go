@@ -0,0 +1,126 @@
+package irc
+
+import (
+	"context"
+	"io"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/autobrr/autobrr/pkg/errors"
+	"github.com/autobrr/autobrr/pkg/sharedhttp"
+
+	"github.com/rs/zerolog"
+)
+
+// proxyRelay connects the client to the server through the network proxy, or the global proxy when the network has none.
+//
+// ircevent dials the server itself and has no dialer hook, so the client connects to the relay on localhost instead.
+// Every connection the relay accepts is dialed to the server and copied both ways. The proxy is looked up per connection,
+// so reconnects pick up a reloaded global proxy. Without any proxy the server is dialed directly.
+// TLS is left to the client, it runs through the relay like any other data.
+type proxyRelay struct {
+	log     zerolog.Logger
+	ln      net.Listener
+	proxy   string
+	server  string
+	timeout time.Duration
+
+	wg sync.WaitGroup
+}
+
+func newProxyRelay(log zerolog.Logger, proxy string, server string, timeout time.Duration) (*proxyRelay, error) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, errors.Wrap(err, "could not listen for proxy relay")
+	}
+
+	r := &proxyRelay{
+		log:     log,
+		ln:      ln,
+		proxy:   proxy,
+		server:  server,
+		timeout: timeout,
+	}
+
+	r.wg.Add(1)
+	go r.accept()
+
+	return r, nil
+}
+
+// Addr is the address the client connects to
+func (r *proxyRelay) Addr() string {
+	return r.ln.Addr().String()
+}
+
+// Close stops accepting connections and waits for the relayed connections to end
+func (r *proxyRelay) Close() error {
+	err := r.ln.Close()
+	r.wg.Wait()
+
+	return err
+}
+
+func (r *proxyRelay) accept() {
+	defer r.wg.Done()
+
+	for {
+		conn, err := r.ln.Accept()
+		if err != nil {
+			return
+		}
+
+		r.wg.Add(1)
+		go func() {
+			defer r.wg.Done()
+			r.relay(conn)
+		}()
+	}
+}
+
+func (r *proxyRelay) relay(client net.Conn) {
+	defer client.Close()
+
+	ctx := context.Background()
+	if r.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, r.timeout)
+		defer cancel()
+	}
+
+	// the client sees the connection closed and retries like any failed connect
+	proxyURL, err := sharedhttp.ResolveProxy(r.proxy)
+	if err != nil {
+		r.log.Error().Err(err).Msg("invalid proxy")
+		return
+	}
+
+	server, err := sharedhttp.DialContext(ctx, proxyURL, r.server)
+	if err != nil {
+		r.log.Error().Err(err).Msgf("could not connect to %v", r.server)
+		return
+	}
+	defer server.Close()
+
+	if proxyURL != nil {
+		r.log.Debug().Msgf("connected to %v through proxy %v", r.server, proxyURL.Host)
+	}
+
+	done := make(chan struct{}, 2)
+
+	go func() {
+		io.Copy(server, client)
+		done <- struct{}{}
+	}()
+	go func() {
+		io.Copy(client, server)
+		done <- struct{}{}
+	}()
+
+	// closing both ends when either side is done stops the other copy
+	<-done
+	client.Close()
+	server.Close()
+	<-done
+}
@@ -0,0 +1,103 @@
+package irc
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/autobrr/autobrr/internal/domain"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHandler_Run_proxy(t *testing.T) {
+	server, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	defer server.Close()
+
+	registered := make(chan struct{})
+
+	go func() {
+		conn, err := server.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		scanner := bufio.NewScanner(conn)
+		for scanner.Scan() {
+			switch line := scanner.Text(); {
+			case strings.HasPrefix(line, "USER"):
+				fmt.Fprintf(conn, ":irc.test 001 autobrr :Welcome\r\n")
+				fmt.Fprintf(conn, ":irc.test 376 autobrr :End of MOTD\r\n")
+				close(registered)
+			case strings.HasPrefix(line, "QUIT"):
+				return
+			}
+		}
+	}()
+
+	proxy, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	defer proxy.Close()
+
+	connects := make(chan string, 1)
+
+	// http proxy that tunnels CONNECT requests
+	go func() {
+		conn, err := proxy.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		req, err := http.ReadRequest(bufio.NewReader(conn))
+		if err != nil {
+			return
+		}
+		connects <- req.Method + " " + req.Host
+
+		target, err := net.Dial("tcp", req.Host)
+		if err != nil {
+			return
+		}
+		defer target.Close()
+
+		io.WriteString(conn, "HTTP/1.1 200 Connection established\r\n\r\n")
+
+		go io.Copy(target, conn)
+		io.Copy(conn, target)
+	}()
+
+	addr := server.Addr().(*net.TCPAddr)
+	network := domain.IrcNetwork{
+		Server:   addr.IP.String(),
+		Port:     addr.Port,
+		Proxy:    "http://" + proxy.Addr().String(),
+		NickServ: domain.NickServ{Account: "autobrr"},
+	}
+
+	h := NewHandler(zerolog.Nop(), network, nil, nil, nil, nil, ConnectionTimeouts{})
+
+	done := make(chan error)
+	go func() {
+		done <- h.Run()
+	}()
+
+	select {
+	case <-registered:
+	case <-time.After(5 * time.Second):
+		t.Fatal("not registered through the proxy")
+	}
+
+	h.Stop()
+	<-done
+
+	assert.Equal(t, "CONNECT "+server.Addr().String(), <-connects)
+}
@@ -0,0 +1,104 @@
+package irc
+
+import (
+	"crypto/tls"
+	"strings"
+
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/pkg/errors"
+
+	"github.com/ergochat/irc-go/ircevent"
+	"github.com/ergochat/irc-go/ircmsg"
+)
+
+// tlsClientConfig returns the tls config for the network with its client certificate if one is set
+func tlsClientConfig(network *domain.IrcNetwork) (*tls.Config, error) {
+	config := &tls.Config{InsecureSkipVerify: true}
+
+	if network.TLSClientCert == "" && network.TLSClientKey == "" {
+		return config, nil
+	}
+
+	cert, err := tls.LoadX509KeyPair(network.TLSClientCert, network.TLSClientKey)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not load client certificate for network: %v", network.Name)
+	}
+
+	config.Certificates = []tls.Certificate{cert}
+
+	return config, nil
+}
+
+// setupSASL sets the sasl settings of the client from the network. It runs before every connect attempt,
+// so a mechanism switched by setupSASLExternal during an earlier attempt never carries over to the next one.
+// A bouncer is never sent the NickServ account with PLAIN, it identifies with NickServ by itself.
+func setupSASL(client *ircevent.Connection, network *domain.IrcNetwork) {
+	client.SASLMech = string(domain.IrcSASLMechanismPlain)
+
+	if network.SASLMechanism == domain.IrcSASLMechanismExternal {
+		client.UseSASL = true
+		client.SASLLogin = ""
+		client.SASLPassword = ""
+		return
+	}
+
+	if network.Bouncer {
+		client.UseSASL = false
+		client.SASLLogin = ""
+		client.SASLPassword = ""
+		return
+	}
+
+	client.SASLLogin = network.NickServ.Account
+	client.SASLPassword = network.NickServ.Password
+}
+
+// setupSASLExternal makes the client authenticate with SASL EXTERNAL.
+//
+// ircevent only implements PLAIN and rejects any other mechanism when connecting, so the mechanism is switched
+// once the server acknowledges the sasl cap, and put back as soon as the server answers.
+// The PLAIN response is replaced by the empty EXTERNAL response, the server identifies us by the client certificate.
+// When the connection is lost before the server answers, setupSASL puts it back before the next attempt.
+func setupSASLExternal(client *ircevent.Connection, network *domain.IrcNetwork) {
+	setupSASL(client, network)
+
+	resetMech := func(msg ircmsg.Message) {
+		client.SASLMech = string(domain.IrcSASLMechanismPlain)
+	}
+
+	// added before ircevent adds its own callbacks on connect, so this runs before the acknowledged cap
+	// is handed over to the negotiation that sends AUTHENTICATE with the mechanism
+	client.AddCallback("CAP", func(msg ircmsg.Message) {
+		if len(msg.Params) < 3 || msg.Params[1] != "ACK" || !hasCap(msg.Params[2], "sasl") {
+			return
+		}
+
+		client.SASLMech = string(domain.IrcSASLMechanismExternal)
+
+		client.ClearCallback("AUTHENTICATE")
+		client.AddCallback("AUTHENTICATE", func(msg ircmsg.Message) {
+			resetMech(msg)
+			client.Send("AUTHENTICATE", "+")
+		})
+	})
+
+	// ircevent reconnects by itself after a disconnect, make sure those attempts start from plain too
+	client.AddCallback("ERROR", resetMech)
+	client.AddDisconnectCallback(func(msg ircmsg.Message) {
+		setupSASL(client, network)
+	})
+
+	for _, code := range []string{ircevent.RPL_LOGGEDOUT, ircevent.ERR_NICKLOCKED, ircevent.RPL_SASLSUCCESS, ircevent.ERR_SASLFAIL, ircevent.ERR_SASLTOOLONG, ircevent.ERR_SASLABORTED, ircevent.RPL_SASLMECHS} {
+		client.AddCallback(code, resetMech)
+	}
+}
+
+func hasCap(caps string, name string) bool {
+	for _, token := range strings.Fields(caps) {
+		if capName, _, _ := strings.Cut(token, "="); capName == name {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,159 @@
+package irc
+
+import (
+	"bufio"
+	"fmt"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/autobrr/autobrr/internal/domain"
+
+	"github.com/ergochat/irc-go/ircevent"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeSASLServer accepts SASL EXTERNAL for any client and records the lines it receives
+func fakeSASLServer(ln net.Listener, lines chan<- string) {
+	conn, err := ln.Accept()
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+
+	send := func(format string, args ...interface{}) {
+		fmt.Fprintf(conn, format+"\r\n", args...)
+	}
+
+	scanner := bufio.NewScanner(conn)
+	for scanner.Scan() {
+		line := scanner.Text()
+		lines <- line
+
+		switch {
+		case strings.HasPrefix(line, "CAP LS"):
+			send(":irc.test CAP * LS :sasl")
+		case strings.HasPrefix(line, "CAP REQ"):
+			send(":irc.test CAP * ACK :sasl")
+		case line == "AUTHENTICATE EXTERNAL":
+			send("AUTHENTICATE +")
+		case line == "AUTHENTICATE +":
+			send(":irc.test 903 autobrr :SASL authentication successful")
+		case strings.HasPrefix(line, "USER"):
+			send(":irc.test 001 autobrr :Welcome")
+			send(":irc.test 376 autobrr :End of MOTD")
+		}
+	}
+}
+
+func Test_setupSASLExternal(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	defer ln.Close()
+
+	lines := make(chan string, 100)
+	go fakeSASLServer(ln, lines)
+
+	client := &ircevent.Connection{
+		Server:       ln.Addr().String(),
+		Nick:         "autobrr",
+		SASLLogin:    "autobrr",
+		SASLPassword: "password",
+		Timeout:      5 * time.Second,
+	}
+	setupSASLExternal(client, &domain.IrcNetwork{SASLMechanism: domain.IrcSASLMechanismExternal})
+
+	assert.NoError(t, client.Connect())
+	defer client.Quit()
+
+	var received []string
+	for len(lines) > 0 {
+		received = append(received, <-lines)
+	}
+
+	assert.Contains(t, received, "AUTHENTICATE EXTERNAL")
+	assert.Contains(t, received, "AUTHENTICATE +")
+	assert.Equal(t, string(domain.IrcSASLMechanismPlain), client.SASLMech)
+
+	// authentication is finished before registration
+	authenticated, end := -1, -1
+	for i, line := range received {
+		switch line {
+		case "AUTHENTICATE +":
+			authenticated = i
+		case "CAP END":
+			end = i
+		}
+	}
+	assert.Less(t, authenticated, end)
+}
+
+func Test_setupSASL_reconnect(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	defer ln.Close()
+
+	// the first connection is lost after the sasl cap is acknowledged, before the server answers
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+
+		scanner := bufio.NewScanner(conn)
+		for scanner.Scan() {
+			switch line := scanner.Text(); {
+			case strings.HasPrefix(line, "CAP LS"):
+				fmt.Fprintf(conn, ":irc.test CAP * LS :sasl\r\n")
+			case strings.HasPrefix(line, "CAP REQ"):
+				fmt.Fprintf(conn, ":irc.test CAP * ACK :sasl\r\n")
+			case line == "AUTHENTICATE EXTERNAL":
+				conn.Close()
+				return
+			}
+		}
+	}()
+
+	network := &domain.IrcNetwork{SASLMechanism: domain.IrcSASLMechanismExternal}
+
+	client := &ircevent.Connection{
+		Server:  ln.Addr().String(),
+		Nick:    "autobrr",
+		Timeout: 5 * time.Second,
+	}
+	setupSASLExternal(client, network)
+
+	assert.Error(t, client.Connect())
+	assert.Equal(t, string(domain.IrcSASLMechanismExternal), client.SASLMech)
+
+	lines := make(chan string, 100)
+	go fakeSASLServer(ln, lines)
+
+	// every attempt starts from the network settings
+	setupSASL(client, network)
+	assert.NoError(t, client.Connect())
+	defer client.Quit()
+
+	var received []string
+	for len(lines) > 0 {
+		received = append(received, <-lines)
+	}
+
+	assert.Contains(t, received, "AUTHENTICATE EXTERNAL")
+	assert.Contains(t, received, "AUTHENTICATE +")
+}
+
+func Test_setupSASL(t *testing.T) {
+	client := &ircevent.Connection{SASLMech: string(domain.IrcSASLMechanismExternal)}
+
+	setupSASL(client, &domain.IrcNetwork{NickServ: domain.NickServ{Account: "autobrr", Password: "secret"}})
+	assert.Equal(t, string(domain.IrcSASLMechanismPlain), client.SASLMech)
+	assert.Equal(t, "autobrr", client.SASLLogin)
+	assert.Equal(t, "secret", client.SASLPassword)
+
+	setupSASL(client, &domain.IrcNetwork{SASLMechanism: domain.IrcSASLMechanismExternal, NickServ: domain.NickServ{Account: "autobrr", Password: "secret"}})
+	assert.True(t, client.UseSASL)
+	assert.Empty(t, client.SASLLogin)
+	assert.Empty(t, client.SASLPassword)
+}
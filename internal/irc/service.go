@@ -2,17 +2,21 @@ package irc
 
 import (
 	"context"
+	"sort"
 	"strings"
 	"sync"
 	"time"
 
+	"github.com/autobrr/autobrr/internal/audit"
 	"github.com/autobrr/autobrr/internal/domain"
 	"github.com/autobrr/autobrr/internal/indexer"
 	"github.com/autobrr/autobrr/internal/logger"
 	"github.com/autobrr/autobrr/internal/notification"
 	"github.com/autobrr/autobrr/internal/release"
+	"github.com/autobrr/autobrr/internal/scheduler"
 	"github.com/autobrr/autobrr/pkg/errors"
 
+	"github.com/asaskevich/EventBus"
 	"github.com/rs/zerolog"
 )
 
@@ -23,11 +27,13 @@ type Service interface {
 	RestartNetwork(ctx context.Context, id int64) error
 	ListNetworks(ctx context.Context) ([]domain.IrcNetwork, error)
 	GetNetworksWithHealth(ctx context.Context) ([]domain.IrcNetworkWithHealth, error)
+	IRCStatus() []domain.IrcNetworkStatus
 	GetNetworkByID(ctx context.Context, id int64) (*domain.IrcNetwork, error)
 	DeleteNetwork(ctx context.Context, id int64) error
 	StoreNetwork(ctx context.Context, network *domain.IrcNetwork) error
 	UpdateNetwork(ctx context.Context, network *domain.IrcNetwork) error
 	StoreChannel(networkID int64, channel *domain.IrcChannel) error
+	ReloadIndexerDefinitions()
 }
 
 type service struct {
@@ -39,18 +45,34 @@ type service struct {
 	releaseService      release.Service
 	indexerService      indexer.Service
 	notificationService notification.Service
+	scheduler           scheduler.Service
+	bus                 EventBus.Bus
+	auditSvc            audit.Service
 	indexerMap          map[string]string
 	handlers            map[handlerKey]*Handler
+	timeouts            ConnectionTimeouts
+
+	announceSilenceThreshold time.Duration
 }
 
-func NewService(log logger.Logger, repo domain.IrcRepo, releaseSvc release.Service, indexerSvc indexer.Service, notificationSvc notification.Service) Service {
+func NewService(log logger.Logger, config *domain.Config, repo domain.IrcRepo, releaseSvc release.Service, indexerSvc indexer.Service, notificationSvc notification.Service, scheduler scheduler.Service, bus EventBus.Bus, auditSvc audit.Service) Service {
 	return &service{
+		timeouts: ConnectionTimeouts{
+			Read:  time.Duration(config.IRCReadTimeout) * time.Second,
+			Ping:  time.Duration(config.IRCPingTimeout) * time.Second,
+			Write: time.Duration(config.IRCWriteTimeout) * time.Second,
+		},
 		log:                 log.With().Str("module", "irc").Logger(),
 		repo:                repo,
 		releaseService:      releaseSvc,
 		indexerService:      indexerSvc,
 		notificationService: notificationSvc,
+		scheduler:           scheduler,
+		bus:                 bus,
+		auditSvc:            auditSvc,
 		handlers:            make(map[handlerKey]*Handler),
+
+		announceSilenceThreshold: time.Duration(config.IRCAnnounceSilenceThreshold) * time.Hour,
 	}
 }
 
@@ -84,7 +106,7 @@ func (s *service) StartHandlers() {
 		definitions := s.indexerService.GetIndexersByIRCNetwork(network.Server)
 
 		// init new irc handler
-		handler := NewHandler(s.log, network, definitions, s.releaseService, s.notificationService)
+		handler := NewHandler(s.log, network, definitions, s.releaseService, s.notificationService, s.bus, s.timeouts)
 
 		// use network.Server + nick to use multiple indexers with different nick per network
 		// this allows for multiple handlers to one network
@@ -99,6 +121,22 @@ func (s *service) StartHandlers() {
 			}
 		}(network)
 	}
+
+	s.startAnnounceSilenceCheck()
+}
+
+// ReloadIndexerDefinitions hands the reloaded indexer definitions to the running handlers
+func (s *service) ReloadIndexerDefinitions() {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	for _, handler := range s.handlers {
+		definitions := s.indexerService.GetIndexersByIRCNetwork(handler.network.Server)
+
+		handler.InitIndexers(definitions)
+
+		s.log.Debug().Msgf("reloaded %d indexer definitions for network: %v", len(definitions), handler.network.Name)
+	}
 }
 
 func (s *service) StopHandlers() {
@@ -136,7 +174,7 @@ func (s *service) startNetwork(network domain.IrcNetwork) error {
 		definitions := s.indexerService.GetIndexersByIRCNetwork(network.Server)
 
 		// init new irc handler
-		handler := NewHandler(s.log, network, definitions, s.releaseService, s.notificationService)
+		handler := NewHandler(s.log, network, definitions, s.releaseService, s.notificationService, s.bus, s.timeouts)
 
 		s.handlers[handlerKey{network.Server, network.NickServ.Account}] = handler
 		s.lock.Unlock()
@@ -173,6 +211,14 @@ func (s *service) checkIfNetworkRestartNeeded(network *domain.IrcNetwork) error
 				restartNeeded = true
 			} else if handler.InviteCommand != network.InviteCommand {
 				restartNeeded = true
+			} else if handler.SASLMechanism != network.SASLMechanism {
+				restartNeeded = true
+			} else if handler.TLSClientCert != network.TLSClientCert || handler.TLSClientKey != network.TLSClientKey {
+				restartNeeded = true
+			} else if handler.Bouncer != network.Bouncer {
+				restartNeeded = true
+			} else if handler.Proxy != network.Proxy {
+				restartNeeded = true
 			}
 			if restartNeeded {
 				s.log.Info().Msgf("irc: restarting network: %+v", network.Server)
@@ -322,7 +368,9 @@ func (s *service) StopAndRemoveNetwork(key handlerKey) error {
 		handler.Stop()
 
 		// remove from handlers
+		s.lock.Lock()
 		delete(s.handlers, key)
+		s.lock.Unlock()
 		s.log.Debug().Msgf("stopped network: %+v", key)
 	}
 
@@ -398,6 +446,11 @@ func (s *service) GetNetworksWithHealth(ctx context.Context) ([]domain.IrcNetwor
 			Pass:             n.Pass,
 			InviteCommand:    n.InviteCommand,
 			NickServ:         n.NickServ,
+			SASLMechanism:    n.SASLMechanism,
+			TLSClientCert:    n.TLSClientCert,
+			TLSClientKey:     n.TLSClientKey,
+			Bouncer:          n.Bouncer,
+			Proxy:            n.Proxy,
 			Connected:        false,
 			Channels:         []domain.ChannelWithHealth{},
 			ConnectionErrors: []string{},
@@ -457,6 +510,7 @@ func (s *service) GetNetworksWithHealth(ctx context.Context) ([]domain.IrcNetwor
 					ch.Monitoring = chan1.monitoring
 					ch.MonitoringSince = chan1.monitoringSince
 					ch.LastAnnounce = chan1.lastAnnounce
+					ch.Silent = chan1.silent
 
 					chan1.m.RUnlock()
 				}
@@ -473,6 +527,24 @@ func (s *service) GetNetworksWithHealth(ctx context.Context) ([]domain.IrcNetwor
 	return ret, nil
 }
 
+// IRCStatus returns the connection state of all running handlers
+func (s *service) IRCStatus() []domain.IrcNetworkStatus {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	ret := make([]domain.IrcNetworkStatus, 0, len(s.handlers))
+
+	for _, handler := range s.handlers {
+		ret = append(ret, handler.Status())
+	}
+
+	sort.SliceStable(ret, func(i, j int) bool {
+		return ret[i].Name < ret[j].Name
+	})
+
+	return ret
+}
+
 func (s *service) DeleteNetwork(ctx context.Context, id int64) error {
 	network, err := s.GetNetworkByID(ctx, id)
 	if err != nil {
@@ -494,10 +566,17 @@ func (s *service) DeleteNetwork(ctx context.Context, id int64) error {
 		return err
 	}
 
+	s.auditDeleted(ctx, network)
+
 	return nil
 }
 
 func (s *service) UpdateNetwork(ctx context.Context, network *domain.IrcNetwork) error {
+	if err := network.Validate(); err != nil {
+		return err
+	}
+
+	before := s.auditSnapshot(ctx, network.ID)
 
 	if network.Channels != nil {
 		if err := s.repo.StoreNetworkChannels(ctx, network.ID, network.Channels); err != nil {
@@ -510,6 +589,8 @@ func (s *service) UpdateNetwork(ctx context.Context, network *domain.IrcNetwork)
 	}
 	s.log.Debug().Msgf("irc.service: update network: %+v", network)
 
+	s.auditUpdated(ctx, before)
+
 	// stop or start network
 	// TODO get current state to see if enabled or not?
 	if network.Enabled {
@@ -535,6 +616,10 @@ func (s *service) UpdateNetwork(ctx context.Context, network *domain.IrcNetwork)
 }
 
 func (s *service) StoreNetwork(ctx context.Context, network *domain.IrcNetwork) error {
+	if err := network.Validate(); err != nil {
+		return err
+	}
+
 	existingNetwork, err := s.repo.CheckExistingNetwork(ctx, network)
 	if err != nil {
 		s.log.Error().Err(err).Msg("could not check for existing network")
@@ -557,6 +642,8 @@ func (s *service) StoreNetwork(ctx context.Context, network *domain.IrcNetwork)
 			}
 		}
 
+		s.auditCreated(ctx, network.ID)
+
 		return nil
 	}
 
@@ -567,6 +654,8 @@ func (s *service) StoreNetwork(ctx context.Context, network *domain.IrcNetwork)
 	}
 	existingNetwork.Channels = existingChannels
 
+	before := s.auditSnapshot(ctx, existingNetwork.ID)
+
 	if network.Channels != nil {
 		for _, channel := range network.Channels {
 			// add channels. Make sure it doesn't delete before
@@ -587,6 +676,8 @@ func (s *service) StoreNetwork(ctx context.Context, network *domain.IrcNetwork)
 		}
 	}
 
+	s.auditUpdated(ctx, before)
+
 	if existingNetwork.Enabled {
 		// if server, tls, invite command, port : changed - restart
 		// if nickserv account, nickserv password : changed - stay connected, and change those
@@ -0,0 +1,170 @@
+package irc
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/autobrr/autobrr/internal/domain"
+
+	"github.com/dustin/go-humanize"
+	"github.com/robfig/cron/v3"
+)
+
+const (
+	announceSilenceCheckInterval = 15 * time.Minute
+
+	// announceRateFactor is how many usual announce intervals may pass before a channel is considered silent
+	announceRateFactor = 4
+
+	// announceRateMinSamples is the number of announces needed before the learned rate is trusted
+	announceRateMinSamples = 10
+
+	// announceRateMinInterval ignores the lines of multi line announces when learning the rate
+	announceRateMinInterval = 5 * time.Second
+)
+
+// recordAnnounce updates the last announce and the learned announce interval.
+// Must be called with the lock held.
+func (ch *channelHealth) recordAnnounce(now time.Time) bool {
+	if !ch.lastAnnounce.IsZero() {
+		if interval := now.Sub(ch.lastAnnounce); interval >= announceRateMinInterval {
+			if ch.announceInterval == 0 {
+				ch.announceInterval = interval
+			} else {
+				// moving average, recent intervals weigh 1/8
+				ch.announceInterval += (interval - ch.announceInterval) / 8
+			}
+			ch.announceSamples++
+		}
+	}
+
+	ch.lastAnnounce = now
+
+	wasSilent := ch.silent
+	ch.silent = false
+
+	return wasSilent
+}
+
+// silenceThreshold returns the configured threshold, raised for channels that usually announce less often
+func (ch *channelHealth) silenceThreshold(threshold time.Duration) time.Duration {
+	if ch.announceSamples < announceRateMinSamples {
+		return threshold
+	}
+
+	if expected := ch.announceInterval * announceRateFactor; expected > threshold {
+		return expected
+	}
+
+	return threshold
+}
+
+// checkSilence flags the channel as silent when nothing was announced for longer than the threshold.
+// Channels that were never joined, eg. after a missed invite, count from when the connection was made.
+// It returns true only when the channel goes silent, together with the time silence is counted from.
+func (ch *channelHealth) checkSilence(now time.Time, threshold time.Duration, connectedSince time.Time) (bool, time.Time) {
+	ch.m.Lock()
+	defer ch.m.Unlock()
+
+	if ch.silent {
+		return false, time.Time{}
+	}
+
+	since := ch.lastAnnounce
+	if since.IsZero() {
+		since = ch.monitoringSince
+	}
+	if since.IsZero() {
+		since = connectedSince
+	}
+
+	if since.IsZero() || now.Sub(since) < ch.silenceThreshold(threshold) {
+		return false, time.Time{}
+	}
+
+	ch.silent = true
+
+	return true, since
+}
+
+// checkAnnounceSilence sends a notification for channels that stopped announcing while the connection is up
+func (h *Handler) checkAnnounceSilence(now time.Time, threshold time.Duration) {
+	h.m.RLock()
+	defer h.m.RUnlock()
+
+	if h.client == nil || !h.client.Connected() || h.connectedSince.IsZero() {
+		return
+	}
+
+	for name, ch := range h.channelHealth {
+		silent, since := ch.checkSilence(now, threshold, h.connectedSince)
+		if !silent {
+			continue
+		}
+
+		h.log.Warn().Msgf("no announces in channel %v since %v", name, since.Format(time.RFC3339))
+
+		h.publishStatus(domain.IrcStatusEventAnnounceSilent, name)
+
+		h.notificationService.Send(domain.NotificationEventIRCAnnounceSilent, domain.NotificationPayload{
+			Subject:   "IRC announces silent",
+			Message:   fmt.Sprintf("Network: %v\nChannel: %v\nSilent since: %v", h.network.Name, name, humanize.RelTime(since, now, "ago", "from now")),
+			Event:     domain.NotificationEventIRCAnnounceSilent,
+			Indexer:   h.channelIndexer(name),
+			Timestamp: now,
+		})
+	}
+}
+
+func (h *Handler) notifyAnnounceResumed(channel string) {
+	h.m.RLock()
+	defer h.m.RUnlock()
+
+	h.log.Info().Msgf("announces resumed in channel %v", channel)
+
+	h.publishStatus(domain.IrcStatusEventAnnounceResumed, channel)
+
+	h.notificationService.Send(domain.NotificationEventIRCAnnounceResumed, domain.NotificationPayload{
+		Subject:   "IRC announces resumed",
+		Message:   fmt.Sprintf("Network: %v\nChannel: %v", h.network.Name, channel),
+		Event:     domain.NotificationEventIRCAnnounceResumed,
+		Indexer:   h.channelIndexer(channel),
+		Timestamp: time.Now(),
+	})
+}
+
+// channelIndexer returns the name of the indexer announcing in the channel
+func (h *Handler) channelIndexer(channel string) string {
+	for _, definition := range h.definitions {
+		for _, c := range definition.IRC.Channels {
+			if strings.EqualFold(c, channel) {
+				return definition.Name
+			}
+		}
+	}
+
+	return ""
+}
+
+// startAnnounceSilenceCheck schedules the check for channels that went silent, a threshold of 0 disables it
+func (s *service) startAnnounceSilenceCheck() {
+	if s.announceSilenceThreshold <= 0 {
+		return
+	}
+
+	job := cron.FuncJob(func() {
+		now := time.Now()
+
+		s.lock.RLock()
+		defer s.lock.RUnlock()
+
+		for _, handler := range s.handlers {
+			handler.checkAnnounceSilence(now, s.announceSilenceThreshold)
+		}
+	})
+
+	if _, err := s.scheduler.AddJob(job, announceSilenceCheckInterval, "irc-announce-silence"); err != nil {
+		s.log.Error().Err(err).Msg("could not add irc announce silence check job")
+	}
+}
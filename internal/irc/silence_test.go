@@ -0,0 +1,97 @@
+package irc
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_channelHealth_checkSilence(t *testing.T) {
+	now := time.Date(2022, 10, 14, 12, 0, 0, 0, time.UTC)
+	threshold := 12 * time.Hour
+
+	tests := []struct {
+		name           string
+		health         *channelHealth
+		connectedSince time.Time
+		wantSilent     bool
+		wantSince      time.Time
+	}{
+		{
+			name:       "recent_announce",
+			health:     &channelHealth{monitoring: true, monitoringSince: now.Add(-48 * time.Hour), lastAnnounce: now.Add(-time.Hour)},
+			wantSilent: false,
+		},
+		{
+			name:       "silent",
+			health:     &channelHealth{monitoring: true, monitoringSince: now.Add(-48 * time.Hour), lastAnnounce: now.Add(-13 * time.Hour)},
+			wantSilent: true,
+			wantSince:  now.Add(-13 * time.Hour),
+		},
+		{
+			name:       "no_announce_since_join",
+			health:     &channelHealth{monitoring: true, monitoringSince: now.Add(-13 * time.Hour)},
+			wantSilent: true,
+			wantSince:  now.Add(-13 * time.Hour),
+		},
+		{
+			name:           "never_joined",
+			health:         &channelHealth{},
+			connectedSince: now.Add(-13 * time.Hour),
+			wantSilent:     true,
+			wantSince:      now.Add(-13 * time.Hour),
+		},
+		{
+			name:       "already_silent",
+			health:     &channelHealth{monitoring: true, lastAnnounce: now.Add(-13 * time.Hour), silent: true},
+			wantSilent: false,
+		},
+		{
+			name:       "slow_channel",
+			health:     &channelHealth{monitoring: true, lastAnnounce: now.Add(-13 * time.Hour), announceInterval: 6 * time.Hour, announceSamples: 20},
+			wantSilent: false,
+		},
+		{
+			name:       "slow_channel_silent",
+			health:     &channelHealth{monitoring: true, lastAnnounce: now.Add(-25 * time.Hour), announceInterval: 6 * time.Hour, announceSamples: 20},
+			wantSilent: true,
+			wantSince:  now.Add(-25 * time.Hour),
+		},
+		{
+			name:       "slow_channel_few_samples",
+			health:     &channelHealth{monitoring: true, lastAnnounce: now.Add(-13 * time.Hour), announceInterval: 6 * time.Hour, announceSamples: 2},
+			wantSilent: true,
+			wantSince:  now.Add(-13 * time.Hour),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			silent, since := tt.health.checkSilence(now, threshold, tt.connectedSince)
+			assert.Equal(t, tt.wantSilent, silent)
+			assert.Equal(t, tt.wantSince, since)
+		})
+	}
+}
+
+func Test_channelHealth_recordAnnounce(t *testing.T) {
+	now := time.Date(2022, 10, 14, 12, 0, 0, 0, time.UTC)
+
+	ch := &channelHealth{monitoring: true, silent: true}
+
+	assert.True(t, ch.recordAnnounce(now))
+	assert.False(t, ch.silent)
+	assert.Equal(t, 0, ch.announceSamples)
+
+	// lines of a multi line announce do not count towards the rate
+	assert.False(t, ch.recordAnnounce(now.Add(time.Second)))
+	assert.Equal(t, 0, ch.announceSamples)
+
+	assert.False(t, ch.recordAnnounce(now.Add(time.Second+time.Hour)))
+	assert.Equal(t, 1, ch.announceSamples)
+	assert.Equal(t, time.Hour, ch.announceInterval)
+
+	assert.False(t, ch.recordAnnounce(now.Add(time.Second+3*time.Hour)))
+	assert.Equal(t, 2, ch.announceSamples)
+	assert.Equal(t, time.Hour+7*time.Minute+30*time.Second, ch.announceInterval)
+}
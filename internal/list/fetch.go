@@ -0,0 +1,243 @@
+package list
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/pkg/errors"
+	"github.com/autobrr/autobrr/pkg/radarr"
+	"github.com/autobrr/autobrr/pkg/sonarr"
+)
+
+// maxListSize caps the response body of url lists
+const maxListSize = 10 << 20
+
+func (s *service) fetch(ctx context.Context, list domain.List) ([]string, error) {
+	switch list.Type {
+	case domain.ListTypeSonarr, domain.ListTypeRadarr:
+		return s.fetchArr(ctx, list)
+
+	case domain.ListTypeTrakt:
+		body, err := s.get(ctx, list.URL, map[string]string{
+			"Content-Type":      "application/json",
+			"trakt-api-version": "2",
+			"trakt-api-key":     list.APIKey,
+		})
+		if err != nil {
+			return nil, err
+		}
+		return parseTrakt(body)
+
+	case domain.ListTypeMdblist:
+		body, err := s.get(ctx, mdblistJSONURL(list.URL), nil)
+		if err != nil {
+			return nil, err
+		}
+		return parseMdblist(body)
+
+	case domain.ListTypePlainText:
+		body, err := s.get(ctx, list.URL, nil)
+		if err != nil {
+			return nil, err
+		}
+		return parsePlainText(body), nil
+	}
+
+	return nil, errors.New("unsupported list type: %v", list.Type)
+}
+
+// fetchArr returns the titles and alternate titles of the monitored series or movies of the arr client
+func (s *service) fetchArr(ctx context.Context, list domain.List) ([]string, error) {
+	client, err := s.downloadClientSvc.FindByID(ctx, int32(list.ClientID))
+	if err != nil {
+		return nil, errors.Wrap(err, "could not find client: %v", list.ClientID)
+	}
+
+	if client == nil {
+		return nil, errors.New("could not find client: %v", list.ClientID)
+	}
+
+	wantType := domain.DownloadClientTypeSonarr
+	if list.Type == domain.ListTypeRadarr {
+		wantType = domain.DownloadClientTypeRadarr
+	}
+
+	if client.Type != wantType {
+		return nil, errors.New("client %v is %v, %v lists need a %v client", client.Name, client.Type, list.Type, wantType)
+	}
+
+	titles := make([]string, 0)
+
+	if list.Type == domain.ListTypeSonarr {
+		cfg := sonarr.Config{
+			Hostname: client.Host,
+			APIKey:   client.Settings.APIKey,
+			Log:      s.subLogger,
+		}
+
+		if client.Settings.Basic.Auth {
+			cfg.BasicAuth = client.Settings.Basic.Auth
+			cfg.Username = client.Settings.Basic.Username
+			cfg.Password = client.Settings.Basic.Password
+		}
+
+		series, err := sonarr.New(cfg).GetSeries(0)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, show := range series {
+			if !show.Monitored && !list.IncludeUnmonitored {
+				continue
+			}
+
+			titles = append(titles, show.Title)
+			for _, alt := range show.AlternateTitles {
+				titles = append(titles, alt.Title)
+			}
+		}
+
+		return titles, nil
+	}
+
+	cfg := radarr.Config{
+		Hostname: client.Host,
+		APIKey:   client.Settings.APIKey,
+		Log:      s.subLogger,
+	}
+
+	if client.Settings.Basic.Auth {
+		cfg.BasicAuth = client.Settings.Basic.Auth
+		cfg.Username = client.Settings.Basic.Username
+		cfg.Password = client.Settings.Basic.Password
+	}
+
+	movies, err := radarr.New(cfg).GetMovies()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, movie := range movies {
+		if !movie.Monitored && !list.IncludeUnmonitored {
+			continue
+		}
+
+		titles = append(titles, movie.Title)
+		for _, alt := range movie.AlternateTitles {
+			titles = append(titles, alt.Title)
+		}
+	}
+
+	return titles, nil
+}
+
+func (s *service) get(ctx context.Context, rawURL string, headers map[string]string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not build request: %v", rawURL)
+	}
+
+	req.Header.Set("User-Agent", "autobrr")
+	for name, value := range headers {
+		req.Header.Set(name, value)
+	}
+
+	resp, err := s.http.Do(req)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not fetch list: %v", rawURL)
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.New("unexpected status fetching list: %v (status: %d)", rawURL, resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxListSize))
+	if err != nil {
+		return nil, errors.Wrap(err, "could not read list: %v", rawURL)
+	}
+
+	return body, nil
+}
+
+type traktItem struct {
+	Movie *struct {
+		Title string `json:"title"`
+	} `json:"movie"`
+	Show *struct {
+		Title string `json:"title"`
+	} `json:"show"`
+}
+
+// parseTrakt reads the movies and shows of a trakt list items or watchlist response
+func parseTrakt(body []byte) ([]string, error) {
+	var items []traktItem
+	if err := json.Unmarshal(body, &items); err != nil {
+		return nil, errors.Wrap(err, "could not decode trakt list")
+	}
+
+	titles := make([]string, 0, len(items))
+	for _, item := range items {
+		switch {
+		case item.Movie != nil:
+			titles = append(titles, item.Movie.Title)
+		case item.Show != nil:
+			titles = append(titles, item.Show.Title)
+		}
+	}
+
+	return titles, nil
+}
+
+// mdblistJSONURL returns the json export of a mdblist list url
+func mdblistJSONURL(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil || strings.HasSuffix(u.Path, "/json") {
+		return rawURL
+	}
+
+	u.Path = strings.TrimSuffix(u.Path, "/") + "/json"
+
+	return u.String()
+}
+
+func parseMdblist(body []byte) ([]string, error) {
+	var items []struct {
+		Title string `json:"title"`
+	}
+	if err := json.Unmarshal(body, &items); err != nil {
+		return nil, errors.Wrap(err, "could not decode mdblist list")
+	}
+
+	titles := make([]string, 0, len(items))
+	for _, item := range items {
+		titles = append(titles, item.Title)
+	}
+
+	return titles, nil
+}
+
+// parsePlainText returns one title per line, empty lines and lines starting with # are skipped
+func parsePlainText(body []byte) []string {
+	titles := make([]string, 0)
+
+	scanner := bufio.NewScanner(bytes.NewReader(body))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		titles = append(titles, line)
+	}
+
+	return titles
+}
@@ -0,0 +1,39 @@
+package list
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseTrakt(t *testing.T) {
+	body := []byte(`[{"rank":1,"type":"movie","movie":{"title":"That Movie","year":2020}},{"rank":2,"type":"show","show":{"title":"That Show","year":2019}},{"rank":3,"type":"person","person":{"name":"Someone"}}]`)
+
+	titles, err := parseTrakt(body)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"That Movie", "That Show"}, titles)
+
+	_, err = parseTrakt([]byte(`{"error":"unauthorized"}`))
+	assert.Error(t, err)
+}
+
+func TestParseMdblist(t *testing.T) {
+	titles, err := parseMdblist([]byte(`[{"id":1,"title":"That Movie","release_year":2020,"mediatype":"movie"}]`))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"That Movie"}, titles)
+}
+
+func TestParsePlainText(t *testing.T) {
+	body := []byte("# my shows\nThat Show\n\n  Other Show  \r\n")
+	assert.Equal(t, []string{"That Show", "Other Show"}, parsePlainText(body))
+}
+
+func TestMdblistJSONURL(t *testing.T) {
+	assert.Equal(t, "https://mdblist.com/lists/me/movies/json", mdblistJSONURL("https://mdblist.com/lists/me/movies"))
+	assert.Equal(t, "https://mdblist.com/lists/me/movies/json", mdblistJSONURL("https://mdblist.com/lists/me/movies/"))
+	assert.Equal(t, "https://mdblist.com/lists/me/movies/json", mdblistJSONURL("https://mdblist.com/lists/me/movies/json"))
+}
+
+func TestUniqueTitles(t *testing.T) {
+	assert.Equal(t, []string{"That Show", "Other Show"}, uniqueTitles([]string{"That Show", "", "that show (2020)", "Other Show"}))
+}
@@ -0,0 +1,288 @@
+package list
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/autobrr/autobrr/internal/audit"
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/internal/download_client"
+	"github.com/autobrr/autobrr/internal/logger"
+	"github.com/autobrr/autobrr/internal/scheduler"
+
+	"github.com/dcarbone/zadapters/zstdlog"
+	"github.com/robfig/cron/v3"
+	"github.com/rs/zerolog"
+)
+
+const (
+	refreshInterval = 6 * time.Hour
+	fetchTimeout    = 60 * time.Second
+)
+
+type Service interface {
+	List(ctx context.Context) ([]domain.List, error)
+	FindByID(ctx context.Context, id int64) (*domain.List, error)
+	Store(ctx context.Context, list *domain.List) error
+	Update(ctx context.Context, list *domain.List) error
+	Delete(ctx context.Context, id int64) error
+	Refresh(ctx context.Context, id int64) error
+	Titles(listID int64) domain.ListTitles
+	Start() error
+}
+
+type service struct {
+	log       zerolog.Logger
+	subLogger *log.Logger
+
+	repo              domain.ListRepo
+	downloadClientSvc download_client.Service
+	scheduler         scheduler.Service
+	auditSvc          audit.Service
+	http              *http.Client
+
+	// titles are cached per list as filters check them for every release
+	titles map[int64]domain.ListTitles
+	m      sync.RWMutex
+}
+
+func NewService(log logger.Logger, repo domain.ListRepo, downloadClientSvc download_client.Service, scheduler scheduler.Service, auditSvc audit.Service) Service {
+	s := &service{
+		log:               log.With().Str("module", "list").Logger(),
+		repo:              repo,
+		downloadClientSvc: downloadClientSvc,
+		scheduler:         scheduler,
+		auditSvc:          auditSvc,
+		http:              &http.Client{Timeout: fetchTimeout},
+		titles:            map[int64]domain.ListTitles{},
+	}
+
+	s.subLogger = zstdlog.NewStdLoggerWithLevel(s.log.With().Logger(), zerolog.TraceLevel)
+
+	return s
+}
+
+func (s *service) List(ctx context.Context) ([]domain.List, error) {
+	lists, err := s.repo.List(ctx)
+	if err != nil {
+		s.log.Error().Err(err).Msg("could not list lists")
+		return nil, err
+	}
+
+	return lists, nil
+}
+
+func (s *service) FindByID(ctx context.Context, id int64) (*domain.List, error) {
+	list, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		s.log.Error().Err(err).Msgf("could not find list by id: %v", id)
+		return nil, err
+	}
+
+	return list, nil
+}
+
+func (s *service) Store(ctx context.Context, list *domain.List) error {
+	if err := list.Validate(); err != nil {
+		return err
+	}
+
+	if err := s.repo.Store(ctx, list); err != nil {
+		s.log.Error().Err(err).Msgf("could not store list: %v", list.Name)
+		return err
+	}
+
+	s.auditSvc.Record(ctx, domain.AuditEntityList, list.ID, list.Name, nil, list)
+
+	s.refreshInBackground(*list)
+
+	return nil
+}
+
+func (s *service) Update(ctx context.Context, list *domain.List) error {
+	if err := list.Validate(); err != nil {
+		return err
+	}
+
+	before, err := s.repo.FindByID(ctx, list.ID)
+	if err != nil {
+		s.log.Error().Err(err).Msgf("could not find list: %v", list.ID)
+		return err
+	}
+
+	if err := s.repo.Update(ctx, list); err != nil {
+		s.log.Error().Err(err).Msgf("could not update list: %v", list.Name)
+		return err
+	}
+
+	s.auditSvc.Record(ctx, domain.AuditEntityList, list.ID, list.Name, before, list)
+
+	s.refreshInBackground(*list)
+
+	return nil
+}
+
+func (s *service) Delete(ctx context.Context, id int64) error {
+	before, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		s.log.Error().Err(err).Msgf("could not find list: %v", id)
+		return err
+	}
+
+	if err := s.repo.Delete(ctx, id); err != nil {
+		s.log.Error().Err(err).Msgf("could not delete list: %v", id)
+		return err
+	}
+
+	s.m.Lock()
+	delete(s.titles, id)
+	s.m.Unlock()
+
+	s.auditSvc.Record(ctx, domain.AuditEntityList, before.ID, before.Name, before, nil)
+
+	return nil
+}
+
+// Titles returns the titles of the list, an unknown or failed list has no titles
+func (s *service) Titles(listID int64) domain.ListTitles {
+	s.m.RLock()
+	titles, ok := s.titles[listID]
+	s.m.RUnlock()
+
+	if ok {
+		return titles
+	}
+
+	items, err := s.repo.GetItems(context.Background(), listID)
+	if err != nil {
+		s.log.Error().Err(err).Msgf("could not get items of list: %v", listID)
+		return domain.ListTitles{}
+	}
+
+	titles = domain.NewListTitles(items)
+
+	s.m.Lock()
+	s.titles[listID] = titles
+	s.m.Unlock()
+
+	return titles
+}
+
+// Start schedules the refresh of all enabled lists
+func (s *service) Start() error {
+	job := cron.FuncJob(func() {
+		s.refreshAll(context.Background())
+	})
+
+	if _, err := s.scheduler.AddJob(job, refreshInterval, "lists-refresh"); err != nil {
+		s.log.Error().Err(err).Msg("could not add list refresh job")
+		return err
+	}
+
+	return nil
+}
+
+func (s *service) refreshAll(ctx context.Context) {
+	lists, err := s.repo.List(ctx)
+	if err != nil {
+		s.log.Error().Err(err).Msg("could not list lists for refresh")
+		return
+	}
+
+	for _, list := range lists {
+		if !list.Enabled {
+			continue
+		}
+
+		if err := s.refresh(ctx, list); err != nil {
+			s.log.Error().Err(err).Msgf("could not refresh list: %v", list.Name)
+		}
+	}
+}
+
+func (s *service) refreshInBackground(list domain.List) {
+	if !list.Enabled {
+		return
+	}
+
+	go func() {
+		if err := s.refresh(context.Background(), list); err != nil {
+			s.log.Error().Err(err).Msgf("could not refresh list: %v", list.Name)
+		}
+	}()
+}
+
+// Refresh fetches the titles of the list now, the result is stored on the list
+func (s *service) Refresh(ctx context.Context, id int64) error {
+	list, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	return s.refresh(ctx, *list)
+}
+
+func (s *service) refresh(ctx context.Context, list domain.List) error {
+	now := time.Now()
+	list.LastRefreshTime = &now
+
+	titles, err := s.fetch(ctx, list)
+	if err != nil {
+		// keep the titles of the last successful refresh so a source being down does not empty the list
+		list.LastRefreshStatus = domain.ListRefreshStatusError
+		list.LastRefreshError = err.Error()
+
+		if updateErr := s.repo.UpdateLastRefresh(ctx, &list); updateErr != nil {
+			s.log.Error().Err(updateErr).Msgf("could not store refresh status of list: %v", list.Name)
+		}
+
+		return err
+	}
+
+	titles = uniqueTitles(titles)
+
+	if err := s.repo.StoreItems(ctx, list.ID, titles); err != nil {
+		return err
+	}
+
+	list.ItemCount = len(titles)
+	list.LastRefreshStatus = domain.ListRefreshStatusSuccess
+	list.LastRefreshError = ""
+
+	if err := s.repo.UpdateLastRefresh(ctx, &list); err != nil {
+		return err
+	}
+
+	s.m.Lock()
+	s.titles[list.ID] = domain.NewListTitles(titles)
+	s.m.Unlock()
+
+	s.log.Debug().Msgf("refreshed list %v: %d titles", list.Name, len(titles))
+
+	return nil
+}
+
+// uniqueTitles drops empty titles and titles that normalize to one already in the list
+func uniqueTitles(titles []string) []string {
+	seen := map[string]struct{}{}
+	unique := make([]string, 0, len(titles))
+
+	for _, title := range titles {
+		normalized := domain.NormalizeListTitle(title)
+		if normalized == "" {
+			continue
+		}
+
+		if _, ok := seen[normalized]; ok {
+			continue
+		}
+
+		seen[normalized] = struct{}{}
+		unique = append(unique, title)
+	}
+
+	return unique
+}
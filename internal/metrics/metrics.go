@@ -0,0 +1,246 @@
+// Package metrics keeps counters, gauges and histograms in memory and exposes them in the Prometheus text format.
+package metrics
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+var (
+	Announces        = NewCounterVec("autobrr_announces_total", "Releases announced per indexer.", "indexer")
+	AnnouncesBlocked = NewCounterVec("autobrr_announces_blocked_total", "Announces dropped by the announce blocklist per indexer and rule type.", "indexer", "type")
+
+	ReleaseQueueWait    = NewHistogramVec("autobrr_release_queue_wait_seconds", "Time releases waited in the indexer queue before processing.", []float64{0.001, 0.01, 0.1, 0.5, 1, 5, 30}, "indexer")
+	ReleaseQueueDepth   = NewGaugeVec("autobrr_release_queue_depth", "Releases waiting in the indexer queue.", "indexer")
+	ReleaseQueueDropped = NewCounterVec("autobrr_release_queue_dropped_total", "Releases dropped because the indexer queue was full.", "indexer")
+
+	FilterMatches    = NewCounterVec("autobrr_filter_matches_total", "Releases matched per filter.", "indexer", "filter")
+	FilterRejections = NewCounterVec("autobrr_filter_rejections_total", "Filter rejections by reason.", "filter", "reason")
+
+	ActionPushes = NewCounterVec("autobrr_action_pushes_total", "Action runs per client by status (approved, rejected, error).", "type", "client", "status")
+
+	IRCConnected  = NewGaugeVec("autobrr_irc_connected", "Whether the IRC network is connected.", "network")
+	IRCReconnects = NewCounterVec("autobrr_irc_reconnects_total", "IRC reconnects after an unexpected disconnect.", "network")
+
+	FeedFetchDuration = NewHistogramVec("autobrr_feed_fetch_duration_seconds", "Time to fetch a feed.", []float64{0.25, 0.5, 1, 2.5, 5, 10, 30}, "feed")
+	FeedFetchErrors   = NewCounterVec("autobrr_feed_fetch_errors_total", "Failed feed fetches.", "feed")
+)
+
+var registry = struct {
+	sync.Mutex
+	metrics []*metric
+}{}
+
+type metricType string
+
+const (
+	typeCounter   metricType = "counter"
+	typeGauge     metricType = "gauge"
+	typeHistogram metricType = "histogram"
+)
+
+type series struct {
+	labelValues []string
+	value       float64
+
+	// histogram only
+	buckets []uint64
+	count   uint64
+}
+
+type metric struct {
+	name    string
+	help    string
+	typ     metricType
+	labels  []string
+	buckets []float64
+
+	mu     sync.Mutex
+	series map[string]*series
+}
+
+func newMetric(name string, help string, typ metricType, buckets []float64, labels []string) *metric {
+	m := &metric{
+		name:    name,
+		help:    help,
+		typ:     typ,
+		labels:  labels,
+		buckets: buckets,
+		series:  map[string]*series{},
+	}
+
+	registry.Lock()
+	registry.metrics = append(registry.metrics, m)
+	registry.Unlock()
+
+	return m
+}
+
+// with returns the series for the label values, the caller must hold the lock
+func (m *metric) with(labelValues []string) *series {
+	if len(labelValues) != len(m.labels) {
+		panic(fmt.Sprintf("metrics: %v expects %d label values, got %d", m.name, len(m.labels), len(labelValues)))
+	}
+
+	key := strings.Join(labelValues, "\xff")
+
+	s, ok := m.series[key]
+	if !ok {
+		s = &series{labelValues: append([]string(nil), labelValues...)}
+		if m.typ == typeHistogram {
+			s.buckets = make([]uint64, len(m.buckets))
+		}
+		m.series[key] = s
+	}
+
+	return s
+}
+
+type CounterVec struct{ m *metric }
+
+func NewCounterVec(name string, help string, labels ...string) *CounterVec {
+	return &CounterVec{m: newMetric(name, help, typeCounter, nil, labels)}
+}
+
+func (c *CounterVec) Inc(labelValues ...string) {
+	c.Add(1, labelValues...)
+}
+
+func (c *CounterVec) Add(v float64, labelValues ...string) {
+	c.m.mu.Lock()
+	c.m.with(labelValues).value += v
+	c.m.mu.Unlock()
+}
+
+type GaugeVec struct{ m *metric }
+
+func NewGaugeVec(name string, help string, labels ...string) *GaugeVec {
+	return &GaugeVec{m: newMetric(name, help, typeGauge, nil, labels)}
+}
+
+func (g *GaugeVec) Set(v float64, labelValues ...string) {
+	g.m.mu.Lock()
+	g.m.with(labelValues).value = v
+	g.m.mu.Unlock()
+}
+
+type HistogramVec struct{ m *metric }
+
+func NewHistogramVec(name string, help string, buckets []float64, labels ...string) *HistogramVec {
+	return &HistogramVec{m: newMetric(name, help, typeHistogram, buckets, labels)}
+}
+
+func (h *HistogramVec) Observe(v float64, labelValues ...string) {
+	h.m.mu.Lock()
+	defer h.m.mu.Unlock()
+
+	s := h.m.with(labelValues)
+	for i, upper := range h.m.buckets {
+		if v <= upper {
+			s.buckets[i]++
+		}
+	}
+	s.count++
+	s.value += v
+}
+
+// Write writes all metrics in the Prometheus text exposition format
+func Write(w io.Writer) error {
+	registry.Lock()
+	metrics := append([]*metric(nil), registry.metrics...)
+	registry.Unlock()
+
+	bw := bufio.NewWriter(w)
+
+	for _, m := range metrics {
+		m.write(bw)
+	}
+
+	return bw.Flush()
+}
+
+func (m *metric) write(w *bufio.Writer) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	fmt.Fprintf(w, "# HELP %s %s\n", m.name, m.help)
+	fmt.Fprintf(w, "# TYPE %s %s\n", m.name, m.typ)
+
+	keys := make([]string, 0, len(m.series))
+	for k := range m.series {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		s := m.series[k]
+
+		if m.typ != typeHistogram {
+			fmt.Fprintf(w, "%s%s %s\n", m.name, formatLabels(m.labels, s.labelValues, "", ""), formatValue(s.value))
+			continue
+		}
+
+		for i, upper := range m.buckets {
+			fmt.Fprintf(w, "%s_bucket%s %d\n", m.name, formatLabels(m.labels, s.labelValues, "le", formatValue(upper)), s.buckets[i])
+		}
+		fmt.Fprintf(w, "%s_bucket%s %d\n", m.name, formatLabels(m.labels, s.labelValues, "le", "+Inf"), s.count)
+		fmt.Fprintf(w, "%s_sum%s %s\n", m.name, formatLabels(m.labels, s.labelValues, "", ""), formatValue(s.value))
+		fmt.Fprintf(w, "%s_count%s %d\n", m.name, formatLabels(m.labels, s.labelValues, "", ""), s.count)
+	}
+}
+
+var labelValueReplacer = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func formatLabels(names []string, values []string, extraName string, extraValue string) string {
+	if len(names) == 0 && extraName == "" {
+		return ""
+	}
+
+	pairs := make([]string, 0, len(names)+1)
+	for i, name := range names {
+		pairs = append(pairs, fmt.Sprintf(`%s="%s"`, name, labelValueReplacer.Replace(values[i])))
+	}
+	if extraName != "" {
+		pairs = append(pairs, fmt.Sprintf(`%s="%s"`, extraName, extraValue))
+	}
+
+	return "{" + strings.Join(pairs, ",") + "}"
+}
+
+func formatValue(v float64) string {
+	if math.IsInf(v, 1) {
+		return "+Inf"
+	}
+
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+// Handler serves the metrics for scraping
+func Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		w.WriteHeader(http.StatusOK)
+
+		_ = Write(w)
+	})
+}
+
+// RejectionReason strips the values from a filter rejection to keep the number of series low,
+// eg. "size not matching. got: 1 GB want: 2 GB" becomes "size not matching"
+func RejectionReason(rejection string) string {
+	if i := strings.Index(rejection, ". got"); i > 0 {
+		return rejection[:i]
+	}
+	if i := strings.Index(rejection, ":"); i > 0 {
+		return rejection[:i]
+	}
+
+	return rejection
+}
@@ -0,0 +1,56 @@
+package metrics
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWrite(t *testing.T) {
+	counter := NewCounterVec("test_counter_total", "A test counter.", "indexer")
+	counter.Inc("mock")
+	counter.Add(2, "mock")
+	counter.Inc(`quote"d`)
+
+	gauge := NewGaugeVec("test_gauge", "A test gauge.", "network")
+	gauge.Set(1, "net")
+	gauge.Set(0, "net")
+
+	histogram := NewHistogramVec("test_duration_seconds", "A test histogram.", []float64{0.5, 1}, "feed")
+	histogram.Observe(0.25, "rss")
+	histogram.Observe(0.75, "rss")
+	histogram.Observe(3, "rss")
+
+	var buf bytes.Buffer
+	assert.NoError(t, Write(&buf))
+
+	out := buf.String()
+
+	assert.Contains(t, out, "# HELP test_counter_total A test counter.\n# TYPE test_counter_total counter\n")
+	assert.Contains(t, out, "test_counter_total{indexer=\"mock\"} 3\n")
+	assert.Contains(t, out, "test_counter_total{indexer=\"quote\\\"d\"} 1\n")
+	assert.Contains(t, out, "# TYPE test_gauge gauge\ntest_gauge{network=\"net\"} 0\n")
+	assert.Contains(t, out, "# TYPE test_duration_seconds histogram\n")
+	assert.Contains(t, out, "test_duration_seconds_bucket{feed=\"rss\",le=\"0.5\"} 1\n")
+	assert.Contains(t, out, "test_duration_seconds_bucket{feed=\"rss\",le=\"1\"} 2\n")
+	assert.Contains(t, out, "test_duration_seconds_bucket{feed=\"rss\",le=\"+Inf\"} 3\n")
+	assert.Contains(t, out, "test_duration_seconds_sum{feed=\"rss\"} 4\n")
+	assert.Contains(t, out, "test_duration_seconds_count{feed=\"rss\"} 3\n")
+}
+
+func TestRejectionReason(t *testing.T) {
+	tests := []struct {
+		rejection string
+		want      string
+	}{
+		{rejection: "size not matching. got: 1 GB want: 2 GB", want: "size not matching"},
+		{rejection: "quota daily reached: max downloads (5) this (DAY)", want: "quota daily reached"},
+		{rejection: "max downloads reached", want: "max downloads reached"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			assert.Equal(t, tt.want, RejectionReason(tt.rejection))
+		})
+	}
+}
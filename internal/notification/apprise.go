@@ -0,0 +1,141 @@
+package notification
+
+import (
+	"bytes"
+	"crypto/tls"
+	"encoding/json"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/pkg/errors"
+
+	"github.com/rs/zerolog"
+)
+
+type appriseMessage struct {
+	URLs  string `json:"urls,omitempty"`
+	Title string `json:"title,omitempty"`
+	Body  string `json:"body"`
+	Type  string `json:"type,omitempty"`
+}
+
+type appriseSender struct {
+	log      zerolog.Logger
+	Settings domain.Notification
+}
+
+// NewAppriseSender sends to an Apprise API endpoint. The host is the full notify url,
+// eg. http://apprise:8000/notify/autobrr for a stored config or http://apprise:8000/notify
+// together with targets for stateless notifications.
+func NewAppriseSender(log zerolog.Logger, settings domain.Notification) domain.NotificationSender {
+	return &appriseSender{
+		log:      log.With().Str("sender", "apprise").Logger(),
+		Settings: settings,
+	}
+}
+
+func (s *appriseSender) Send(event domain.NotificationEvent, payload domain.NotificationPayload) error {
+	m := appriseMessage{
+		URLs:  s.Settings.Targets,
+		Title: buildTitle(payload),
+		Body:  buildTextMessage(payload),
+		Type:  appriseType(event),
+	}
+
+	// custom templates replace the body
+	if custom, ok, err := s.Settings.RenderTemplate(event, payload); err != nil {
+		s.log.Error().Err(err).Msgf("apprise could not render template for event: %v, sending default message", event)
+	} else if ok {
+		m.Body = custom
+	}
+
+	jsonData, err := json.Marshal(m)
+	if err != nil {
+		s.log.Error().Err(err).Msgf("apprise client could not marshal data: %v", m)
+		return errors.Wrap(err, "could not marshal data: %+v", m)
+	}
+
+	req, err := http.NewRequest(http.MethodPost, s.Settings.Host, bytes.NewBuffer(jsonData))
+	if err != nil {
+		s.log.Error().Err(err).Msgf("apprise client request error: %v", event)
+		return errors.Wrap(err, "could not create request")
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "autobrr")
+
+	if s.Settings.Username != "" && s.Settings.Password != "" {
+		req.SetBasicAuth(s.Settings.Username, s.Settings.Password)
+	}
+
+	t := &http.Transport{
+		TLSClientConfig: &tls.Config{
+			InsecureSkipVerify: true,
+		},
+	}
+
+	client := http.Client{Transport: t, Timeout: 30 * time.Second}
+	res, err := client.Do(req)
+	if err != nil {
+		s.log.Error().Err(err).Msgf("apprise client request error: %v", event)
+		return errors.Wrap(err, "could not make request: %+v", req)
+	}
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		s.log.Error().Err(err).Msgf("apprise client request error: %v", event)
+		return errors.Wrap(err, "could not read data")
+	}
+
+	defer res.Body.Close()
+
+	s.log.Trace().Msgf("apprise status: %v response: %v", res.StatusCode, string(body))
+
+	if res.StatusCode >= 300 {
+		s.log.Error().Err(err).Msgf("apprise client request error: %v", string(body))
+		return errors.New("bad status: %v body: %v", res.StatusCode, string(body))
+	}
+
+	s.log.Debug().Msg("notification successfully sent to apprise")
+
+	return nil
+}
+
+func (s *appriseSender) CanSend(event domain.NotificationEvent) bool {
+	if s.isEnabled() && s.isEnabledEvent(event) {
+		return true
+	}
+	return false
+}
+
+func (s *appriseSender) isEnabled() bool {
+	if s.Settings.Enabled && s.Settings.Host != "" {
+		return true
+	}
+	return false
+}
+
+func (s *appriseSender) isEnabledEvent(event domain.NotificationEvent) bool {
+	for _, e := range s.Settings.Events {
+		if e == string(event) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func appriseType(event domain.NotificationEvent) string {
+	switch event {
+	case domain.NotificationEventPushApproved, domain.NotificationEventIRCReconnected, domain.NotificationEventIRCAnnounceResumed, domain.NotificationEventDownloadClientRecovered:
+		return "success"
+	case domain.NotificationEventPushRejected, domain.NotificationEventIRCAnnounceSilent, domain.NotificationEventReleasePendingApproval:
+		return "warning"
+	case domain.NotificationEventPushError, domain.NotificationEventIRCDisconnected, domain.NotificationEventDownloadClientDown:
+		return "failure"
+	}
+
+	return "info"
+}
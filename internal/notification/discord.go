@@ -41,6 +41,7 @@ const (
 	RED        EmbedColors = 15548997 // ed4245
 	GREEN      EmbedColors = 5763719  // 57f287
 	GRAY       EmbedColors = 10070709 // 99aab5
+	ORANGE     EmbedColors = 15105570 // e67e22
 )
 
 type discordSender struct {
@@ -56,9 +57,19 @@ func NewDiscordSender(log zerolog.Logger, settings domain.Notification) domain.N
 }
 
 func (a *discordSender) Send(event domain.NotificationEvent, payload domain.NotificationPayload) error {
+	embed := a.buildEmbed(event, payload)
+
+	// custom templates replace the description and fields of the default embed
+	if custom, ok, err := a.Settings.RenderTemplate(event, payload); err != nil {
+		a.log.Error().Err(err).Msgf("discord could not render template for event: %v, sending default message", event)
+	} else if ok {
+		embed.Description = custom
+		embed.Fields = nil
+	}
+
 	m := DiscordMessage{
 		Content: nil,
-		Embeds:  []DiscordEmbeds{a.buildEmbed(event, payload)},
+		Embeds:  []DiscordEmbeds{embed},
 	}
 
 	jsonData, err := json.Marshal(m)
@@ -148,6 +159,16 @@ func (a *discordSender) buildEmbed(event domain.NotificationEvent, payload domai
 		color = RED
 	case domain.NotificationEventIRCReconnected:
 		color = GREEN
+	case domain.NotificationEventIRCAnnounceSilent:
+		color = ORANGE
+	case domain.NotificationEventIRCAnnounceResumed:
+		color = GREEN
+	case domain.NotificationEventDownloadClientDown:
+		color = RED
+	case domain.NotificationEventDownloadClientRecovered:
+		color = GREEN
+	case domain.NotificationEventReleasePendingApproval:
+		color = ORANGE
 	case domain.NotificationEventTest:
 		color = LIGHT_BLUE
 	}
@@ -52,9 +52,18 @@ func NewNotifiarrSender(log zerolog.Logger, settings domain.Notification) domain
 }
 
 func (s *notifiarrSender) Send(event domain.NotificationEvent, payload domain.NotificationPayload) error {
+	data := s.buildMessage(payload)
+
+	// custom templates replace the message, the structured fields are still sent
+	if custom, ok, err := s.Settings.RenderTemplate(event, payload); err != nil {
+		s.log.Error().Err(err).Msgf("notifiarr could not render template for event: %v, sending default message", event)
+	} else if ok {
+		data.Message = custom
+	}
+
 	m := notifiarrMessage{
 		Event: string(event),
-		Data:  s.buildMessage(payload),
+		Data:  data,
 	}
 
 	jsonData, err := json.Marshal(m)
@@ -0,0 +1,240 @@
+package notification
+
+import (
+	"bytes"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/pkg/errors"
+
+	"github.com/rs/zerolog"
+)
+
+const defaultNtfyHost = "https://ntfy.sh"
+
+type ntfyMessage struct {
+	Topic    string   `json:"topic"`
+	Title    string   `json:"title,omitempty"`
+	Message  string   `json:"message"`
+	Priority int32    `json:"priority,omitempty"`
+	Tags     []string `json:"tags,omitempty"`
+	Click    string   `json:"click,omitempty"`
+}
+
+type ntfySender struct {
+	log      zerolog.Logger
+	Settings domain.Notification
+}
+
+func NewNtfySender(log zerolog.Logger, settings domain.Notification) domain.NotificationSender {
+	return &ntfySender{
+		log:      log.With().Str("sender", "ntfy").Logger(),
+		Settings: settings,
+	}
+}
+
+func (s *ntfySender) Send(event domain.NotificationEvent, payload domain.NotificationPayload) error {
+	m := ntfyMessage{
+		Topic:    s.Settings.Topic,
+		Title:    buildTitle(payload),
+		Message:  buildTextMessage(payload),
+		Priority: s.Settings.Priority,
+		Tags:     ntfyTags(event),
+	}
+
+	// custom templates replace the message
+	if custom, ok, err := s.Settings.RenderTemplate(event, payload); err != nil {
+		s.log.Error().Err(err).Msgf("ntfy could not render template for event: %v, sending default message", event)
+	} else if ok {
+		m.Message = custom
+	}
+
+	if s.Settings.ClickURL != "" {
+		click, err := renderClickURL(s.Settings.ClickURL, payload)
+		if err != nil {
+			s.log.Error().Err(err).Msgf("ntfy could not render click url for event: %v", event)
+		} else {
+			m.Click = click
+		}
+	}
+
+	jsonData, err := json.Marshal(m)
+	if err != nil {
+		s.log.Error().Err(err).Msgf("ntfy client could not marshal data: %v", m)
+		return errors.Wrap(err, "could not marshal data: %+v", m)
+	}
+
+	host := s.Settings.Host
+	if host == "" {
+		host = defaultNtfyHost
+	}
+
+	// publishing as json goes to the root url with the topic in the body
+	req, err := http.NewRequest(http.MethodPost, strings.TrimSuffix(host, "/"), bytes.NewBuffer(jsonData))
+	if err != nil {
+		s.log.Error().Err(err).Msgf("ntfy client request error: %v", event)
+		return errors.Wrap(err, "could not create request")
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "autobrr")
+
+	if s.Settings.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+s.Settings.Token)
+	} else if s.Settings.Username != "" && s.Settings.Password != "" {
+		req.SetBasicAuth(s.Settings.Username, s.Settings.Password)
+	}
+
+	t := &http.Transport{
+		TLSClientConfig: &tls.Config{
+			InsecureSkipVerify: true,
+		},
+	}
+
+	client := http.Client{Transport: t, Timeout: 30 * time.Second}
+	res, err := client.Do(req)
+	if err != nil {
+		s.log.Error().Err(err).Msgf("ntfy client request error: %v", event)
+		return errors.Wrap(err, "could not make request: %+v", req)
+	}
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		s.log.Error().Err(err).Msgf("ntfy client request error: %v", event)
+		return errors.Wrap(err, "could not read data")
+	}
+
+	defer res.Body.Close()
+
+	s.log.Trace().Msgf("ntfy status: %v response: %v", res.StatusCode, string(body))
+
+	if res.StatusCode != http.StatusOK {
+		s.log.Error().Err(err).Msgf("ntfy client request error: %v", string(body))
+		return errors.New("bad status: %v body: %v", res.StatusCode, string(body))
+	}
+
+	s.log.Debug().Msg("notification successfully sent to ntfy")
+
+	return nil
+}
+
+func (s *ntfySender) CanSend(event domain.NotificationEvent) bool {
+	if s.isEnabled() && s.isEnabledEvent(event) {
+		return true
+	}
+	return false
+}
+
+func (s *ntfySender) isEnabled() bool {
+	if s.Settings.Enabled && s.Settings.Topic != "" {
+		return true
+	}
+	return false
+}
+
+func (s *ntfySender) isEnabledEvent(event domain.NotificationEvent) bool {
+	for _, e := range s.Settings.Events {
+		if e == string(event) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ntfyTags are shown as emojis in front of the title
+func ntfyTags(event domain.NotificationEvent) []string {
+	switch event {
+	case domain.NotificationEventPushApproved:
+		return []string{"white_check_mark"}
+	case domain.NotificationEventPushRejected:
+		return []string{"no_entry_sign"}
+	case domain.NotificationEventPushError, domain.NotificationEventIRCDisconnected, domain.NotificationEventDownloadClientDown:
+		return []string{"rotating_light"}
+	case domain.NotificationEventIRCReconnected, domain.NotificationEventDownloadClientRecovered:
+		return []string{"electric_plug"}
+	case domain.NotificationEventIRCAnnounceSilent:
+		return []string{"mute"}
+	case domain.NotificationEventIRCAnnounceResumed:
+		return []string{"loud_sound"}
+	case domain.NotificationEventAppUpdateAvailable:
+		return []string{"arrow_up"}
+	case domain.NotificationEventReleasePendingApproval:
+		return []string{"hourglass"}
+	}
+
+	return nil
+}
+
+// renderClickURL renders the click url template, eg. https://tracker/torrents.php?id={{ .Release.TorrentID }}
+func renderClickURL(tmpl string, payload domain.NotificationPayload) (string, error) {
+	t, err := template.New("click").Parse(tmpl)
+	if err != nil {
+		return "", errors.Wrap(err, "could not parse click url")
+	}
+
+	var buf bytes.Buffer
+	if err := t.Execute(&buf, payload); err != nil {
+		return "", errors.Wrap(err, "could not render click url")
+	}
+
+	return buf.String(), nil
+}
+
+// buildTitle returns the subject or a title for push events
+func buildTitle(payload domain.NotificationPayload) string {
+	if payload.Subject != "" {
+		return payload.Subject
+	}
+
+	switch payload.Event {
+	case domain.NotificationEventPushApproved:
+		return "New release!"
+	case domain.NotificationEventPushRejected:
+		return "Push rejected"
+	case domain.NotificationEventPushError:
+		return "Push error"
+	}
+
+	return fmt.Sprintf("autobrr: %v", payload.Event)
+}
+
+// buildTextMessage returns a plain text message for senders without markup
+func buildTextMessage(payload domain.NotificationPayload) string {
+	var lines []string
+
+	if payload.Subject != "" && payload.Message != "" {
+		lines = append(lines, payload.Message)
+	}
+	if payload.ReleaseName != "" {
+		lines = append(lines, fmt.Sprintf("New release: %v", payload.ReleaseName))
+	}
+	if payload.Status != "" {
+		lines = append(lines, fmt.Sprintf("Status: %v", payload.Status.String()))
+	}
+	if payload.Indexer != "" {
+		lines = append(lines, fmt.Sprintf("Indexer: %v", payload.Indexer))
+	}
+	if payload.Filter != "" {
+		lines = append(lines, fmt.Sprintf("Filter: %v", payload.Filter))
+	}
+	if payload.Action != "" {
+		action := fmt.Sprintf("Action: %v Type: %v", payload.Action, payload.ActionType)
+		if payload.ActionClient != "" {
+			action += fmt.Sprintf(" Client: %v", payload.ActionClient)
+		}
+		lines = append(lines, action)
+	}
+	if len(payload.Rejections) > 0 {
+		lines = append(lines, fmt.Sprintf("Rejections: %v", strings.Join(payload.Rejections, ", ")))
+	}
+
+	return strings.Join(lines, "\n")
+}
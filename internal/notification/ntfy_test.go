@@ -0,0 +1,92 @@
+package notification
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/autobrr/autobrr/internal/domain"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_ntfySender_Send(t *testing.T) {
+	var got ntfyMessage
+	var auth string
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth = r.Header.Get("Authorization")
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&got))
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer ts.Close()
+
+	sender := NewNtfySender(zerolog.Nop(), domain.Notification{
+		Enabled:  true,
+		Events:   []string{string(domain.NotificationEventPushApproved)},
+		Host:     ts.URL,
+		Topic:    "autobrr",
+		Token:    "tk_mock",
+		Priority: 4,
+		ClickURL: "https://tracker.test/torrents.php?id={{ .Release.TorrentID }}",
+	})
+
+	release := domain.NewRelease("mock")
+	release.TorrentID = "123"
+
+	payload := domain.NotificationPayload{
+		Event:       domain.NotificationEventPushApproved,
+		ReleaseName: "That.Show.S01E01.1080p.WEB-DL-GROUP",
+		Filter:      "TV",
+		Indexer:     "mock",
+		Status:      domain.ReleasePushStatusApproved,
+		Release:     release,
+	}
+
+	assert.True(t, sender.CanSend(domain.NotificationEventPushApproved))
+	assert.False(t, sender.CanSend(domain.NotificationEventPushError))
+
+	assert.NoError(t, sender.Send(payload.Event, payload))
+	assert.Equal(t, "Bearer tk_mock", auth)
+	assert.Equal(t, ntfyMessage{
+		Topic:    "autobrr",
+		Title:    "New release!",
+		Message:  "New release: That.Show.S01E01.1080p.WEB-DL-GROUP\nStatus: Approved\nIndexer: mock\nFilter: TV",
+		Priority: 4,
+		Tags:     []string{"white_check_mark"},
+		Click:    "https://tracker.test/torrents.php?id=123",
+	}, got)
+}
+
+func Test_appriseSender_Send(t *testing.T) {
+	var got appriseMessage
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/notify/autobrr", r.URL.Path)
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&got))
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer ts.Close()
+
+	sender := NewAppriseSender(zerolog.Nop(), domain.Notification{
+		Enabled:   true,
+		Events:    []string{string(domain.NotificationEventIRCDisconnected)},
+		Host:      ts.URL + "/notify/autobrr",
+		Templates: map[domain.NotificationEvent]string{domain.NotificationEventIRCDisconnected: "irc down: {{ .Message }}"},
+	})
+
+	payload := domain.NotificationPayload{
+		Subject: "IRC Disconnected unexpectedly",
+		Message: "Network: P2P-Network",
+		Event:   domain.NotificationEventIRCDisconnected,
+	}
+
+	assert.NoError(t, sender.Send(payload.Event, payload))
+	assert.Equal(t, appriseMessage{
+		Title: "IRC Disconnected unexpectedly",
+		Body:  "irc down: Network: P2P-Network",
+		Type:  "failure",
+	}, got)
+}
@@ -6,6 +6,7 @@ import (
 
 	"golang.org/x/sync/errgroup"
 
+	"github.com/autobrr/autobrr/internal/audit"
 	"github.com/autobrr/autobrr/internal/domain"
 	"github.com/autobrr/autobrr/internal/logger"
 
@@ -23,16 +24,18 @@ type Service interface {
 }
 
 type service struct {
-	log     zerolog.Logger
-	repo    domain.NotificationRepo
-	senders []domain.NotificationSender
+	log      zerolog.Logger
+	repo     domain.NotificationRepo
+	auditSvc audit.Service
+	senders  []domain.NotificationSender
 }
 
-func NewService(log logger.Logger, repo domain.NotificationRepo) Service {
+func NewService(log logger.Logger, repo domain.NotificationRepo, auditSvc audit.Service) Service {
 	s := &service{
-		log:     log.With().Str("module", "notification").Logger(),
-		repo:    repo,
-		senders: []domain.NotificationSender{},
+		log:      log.With().Str("module", "notification").Logger(),
+		repo:     repo,
+		auditSvc: auditSvc,
+		senders:  []domain.NotificationSender{},
 	}
 
 	s.registerSenders()
@@ -61,12 +64,18 @@ func (s *service) FindByID(ctx context.Context, id int) (*domain.Notification, e
 }
 
 func (s *service) Store(ctx context.Context, n domain.Notification) (*domain.Notification, error) {
-	_, err := s.repo.Store(ctx, n)
+	if err := n.ValidateTemplates(); err != nil {
+		return nil, err
+	}
+
+	stored, err := s.repo.Store(ctx, n)
 	if err != nil {
-		s.log.Error().Err(err).Msgf("could not store notification: %+v", n)
+		s.log.Error().Err(err).Msgf("could not store notification: %v (%v)", n.Name, n.Type)
 		return nil, err
 	}
 
+	s.auditSvc.Record(ctx, domain.AuditEntityNotification, stored.ID, stored.Name, nil, stored)
+
 	// reset senders
 	s.senders = []domain.NotificationSender{}
 
@@ -77,12 +86,24 @@ func (s *service) Store(ctx context.Context, n domain.Notification) (*domain.Not
 }
 
 func (s *service) Update(ctx context.Context, n domain.Notification) (*domain.Notification, error) {
-	_, err := s.repo.Update(ctx, n)
+	if err := n.ValidateTemplates(); err != nil {
+		return nil, err
+	}
+
+	before, err := s.repo.FindByID(ctx, n.ID)
 	if err != nil {
-		s.log.Error().Err(err).Msgf("could not update notification: %+v", n)
+		s.log.Error().Err(err).Msgf("could not find notification: %v", n.ID)
 		return nil, err
 	}
 
+	updated, err := s.repo.Update(ctx, n)
+	if err != nil {
+		s.log.Error().Err(err).Msgf("could not update notification: %v (%v)", n.Name, n.Type)
+		return nil, err
+	}
+
+	s.auditSvc.Record(ctx, domain.AuditEntityNotification, updated.ID, updated.Name, before, updated)
+
 	// reset senders
 	s.senders = []domain.NotificationSender{}
 
@@ -93,12 +114,19 @@ func (s *service) Update(ctx context.Context, n domain.Notification) (*domain.No
 }
 
 func (s *service) Delete(ctx context.Context, id int) error {
-	err := s.repo.Delete(ctx, id)
+	before, err := s.repo.FindByID(ctx, id)
 	if err != nil {
+		s.log.Error().Err(err).Msgf("could not find notification: %v", id)
+		return err
+	}
+
+	if err := s.repo.Delete(ctx, id); err != nil {
 		s.log.Error().Err(err).Msgf("could not delete notification: %v", id)
 		return err
 	}
 
+	s.auditSvc.Record(ctx, domain.AuditEntityNotification, before.ID, before.Name, before, nil)
+
 	// reset senders
 	s.senders = []domain.NotificationSender{}
 
@@ -124,6 +152,10 @@ func (s *service) registerSenders() {
 				s.senders = append(s.senders, NewNotifiarrSender(s.log, n))
 			case domain.NotificationTypeTelegram:
 				s.senders = append(s.senders, NewTelegramSender(s.log, n))
+			case domain.NotificationTypeNtfy:
+				s.senders = append(s.senders, NewNtfySender(s.log, n))
+			case domain.NotificationTypeApprise:
+				s.senders = append(s.senders, NewAppriseSender(s.log, n))
 			}
 		}
 	}
@@ -152,6 +184,11 @@ func (s *service) Send(event domain.NotificationEvent, payload domain.Notificati
 func (s *service) Test(ctx context.Context, notification domain.Notification) error {
 	var agent domain.NotificationSender
 
+	// sample release so templates using release fields can be tested
+	release := domain.NewRelease("MockIndexer")
+	release.TorrentName = "Best.Show.Ever.S18E21.1080p.AMZN.WEB-DL.DDP2.0.H.264-GROUP"
+	release.ParseString(release.TorrentName)
+
 	// send test events
 	events := []domain.NotificationPayload{
 		{
@@ -175,6 +212,7 @@ func (s *service) Test(ctx context.Context, notification domain.Notification) er
 			Protocol:       domain.ReleaseProtocolTorrent,
 			Implementation: domain.ReleaseImplementationIRC,
 			Timestamp:      time.Now(),
+			Release:        release,
 		},
 		{
 			Subject:        "New release!",
@@ -191,6 +229,7 @@ func (s *service) Test(ctx context.Context, notification domain.Notification) er
 			Protocol:       domain.ReleaseProtocolTorrent,
 			Implementation: domain.ReleaseImplementationIRC,
 			Timestamp:      time.Now(),
+			Release:        release,
 		},
 		{
 			Subject:        "New release!",
@@ -207,6 +246,7 @@ func (s *service) Test(ctx context.Context, notification domain.Notification) er
 			Protocol:       domain.ReleaseProtocolTorrent,
 			Implementation: domain.ReleaseImplementationIRC,
 			Timestamp:      time.Now(),
+			Release:        release,
 		},
 		{
 			Subject:   "IRC Disconnected unexpectedly",
@@ -220,6 +260,30 @@ func (s *service) Test(ctx context.Context, notification domain.Notification) er
 			Event:     domain.NotificationEventIRCReconnected,
 			Timestamp: time.Now(),
 		},
+		{
+			Subject:   "IRC announces silent",
+			Message:   "Network: P2P-Network\nChannel: #announce\nLast announce: 14h ago",
+			Event:     domain.NotificationEventIRCAnnounceSilent,
+			Timestamp: time.Now(),
+		},
+		{
+			Subject:   "Download client down",
+			Message:   "Client: qBittorrent\nError: could not log into client",
+			Event:     domain.NotificationEventDownloadClientDown,
+			Timestamp: time.Now(),
+		},
+		{
+			Subject:     "Release pending approval",
+			Message:     "Best.Show.Ever.S18E21.1080p.AMZN.WEB-DL.DDP2.0.H.264-GROUP\nApprove: https://autobrr.example.com/api/approval/1/approve\nReject: https://autobrr.example.com/api/approval/1/reject",
+			Event:       domain.NotificationEventReleasePendingApproval,
+			ReleaseName: "Best.Show.Ever.S18E21.1080p.AMZN.WEB-DL.DDP2.0.H.264-GROUP",
+			Filter:      "TV",
+			Indexer:     "MockIndexer",
+			ApproveURL:  "https://autobrr.example.com/api/approval/1/approve",
+			RejectURL:   "https://autobrr.example.com/api/approval/1/reject",
+			Timestamp:   time.Now(),
+			Release:     release,
+		},
 		{
 			Subject:   "New update available!",
 			Message:   "v1.6.0",
@@ -235,6 +299,10 @@ func (s *service) Test(ctx context.Context, notification domain.Notification) er
 		agent = NewNotifiarrSender(s.log, notification)
 	case domain.NotificationTypeTelegram:
 		agent = NewTelegramSender(s.log, notification)
+	case domain.NotificationTypeNtfy:
+		agent = NewNtfySender(s.log, notification)
+	case domain.NotificationTypeApprise:
+		agent = NewAppriseSender(s.log, notification)
 	}
 
 	g, ctx := errgroup.WithContext(ctx)
@@ -36,9 +36,18 @@ func NewTelegramSender(log zerolog.Logger, settings domain.Notification) domain.
 }
 
 func (s *telegramSender) Send(event domain.NotificationEvent, payload domain.NotificationPayload) error {
+	text := s.buildMessage(event, payload)
+
+	// custom templates replace the default message
+	if custom, ok, err := s.Settings.RenderTemplate(event, payload); err != nil {
+		s.log.Error().Err(err).Msgf("telegram could not render template for event: %v, sending default message", event)
+	} else if ok {
+		text = custom
+	}
+
 	m := TelegramMessage{
 		ChatID:    s.Settings.Channel,
-		Text:      s.buildMessage(event, payload),
+		Text:      text,
 		ParseMode: "HTML",
 		//ParseMode: "MarkdownV2",
 	}
@@ -0,0 +1,113 @@
+package quota
+
+import (
+	"context"
+
+	"github.com/autobrr/autobrr/internal/audit"
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/internal/logger"
+	"github.com/autobrr/autobrr/pkg/errors"
+
+	"github.com/rs/zerolog"
+)
+
+type Service interface {
+	List(ctx context.Context) ([]domain.Quota, error)
+	FindByID(ctx context.Context, id int) (*domain.Quota, error)
+	Store(ctx context.Context, quota *domain.Quota) error
+	Update(ctx context.Context, quota *domain.Quota) error
+	Delete(ctx context.Context, id int) error
+	Check(ctx context.Context, filterID int, indexer string, size uint64) (string, bool, error)
+}
+
+type service struct {
+	log      zerolog.Logger
+	repo     domain.QuotaRepo
+	auditSvc audit.Service
+}
+
+func NewService(log logger.Logger, repo domain.QuotaRepo, auditSvc audit.Service) Service {
+	return &service{
+		log:      log.With().Str("module", "quota").Logger(),
+		repo:     repo,
+		auditSvc: auditSvc,
+	}
+}
+
+func (s *service) List(ctx context.Context) ([]domain.Quota, error) {
+	return s.repo.List(ctx)
+}
+
+func (s *service) FindByID(ctx context.Context, id int) (*domain.Quota, error) {
+	return s.repo.FindByID(ctx, id)
+}
+
+func (s *service) Store(ctx context.Context, quota *domain.Quota) error {
+	if err := quota.Validate(); err != nil {
+		return errors.Wrap(err, "invalid quota")
+	}
+
+	if err := s.repo.Store(ctx, quota); err != nil {
+		return err
+	}
+
+	s.auditSvc.Record(ctx, domain.AuditEntityQuota, quota.ID, quota.Name, nil, quota)
+
+	return nil
+}
+
+func (s *service) Update(ctx context.Context, quota *domain.Quota) error {
+	if err := quota.Validate(); err != nil {
+		return errors.Wrap(err, "invalid quota")
+	}
+
+	before, err := s.repo.FindByID(ctx, quota.ID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.repo.Update(ctx, quota); err != nil {
+		return err
+	}
+
+	s.auditSvc.Record(ctx, domain.AuditEntityQuota, quota.ID, quota.Name, before, quota)
+
+	return nil
+}
+
+func (s *service) Delete(ctx context.Context, id int) error {
+	before, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if err := s.repo.Delete(ctx, id); err != nil {
+		return err
+	}
+
+	s.auditSvc.Record(ctx, domain.AuditEntityQuota, before.ID, before.Name, before, nil)
+
+	return nil
+}
+
+// Check checks every enabled quota for the filter and indexer and returns the rejection of the first one exceeded
+func (s *service) Check(ctx context.Context, filterID int, indexer string, size uint64) (string, bool, error) {
+	quotas, err := s.repo.FindActive(ctx, filterID, indexer)
+	if err != nil {
+		return "", false, errors.Wrap(err, "could not find quotas for filter: %v indexer: %v", filterID, indexer)
+	}
+
+	for _, q := range quotas {
+		usage, err := s.repo.Usage(ctx, q)
+		if err != nil {
+			return "", false, errors.Wrap(err, "could not get usage for quota: %v", q.Name)
+		}
+
+		if rejection, ok := q.Check(*usage, size); !ok {
+			s.log.Debug().Msgf("quota %v exceeded: downloads %d size %d", q.Name, usage.Downloads, usage.Size)
+			return rejection, false, nil
+		}
+	}
+
+	return "", true, nil
+}
@@ -0,0 +1,220 @@
+package release
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/pkg/errors"
+
+	"github.com/dustin/go-humanize"
+	"github.com/rs/zerolog"
+)
+
+const approvalExpireInterval = 10 * time.Minute
+
+// approvalBaseURL is where the approve and reject links of notifications point to
+func approvalBaseURL(config *domain.Config) string {
+	if config.PublicURL != "" {
+		return strings.TrimSuffix(config.PublicURL, "/") + "/"
+	}
+
+	host := config.Host
+	if host == "" || host == "0.0.0.0" || host == "::" {
+		host = "localhost"
+	}
+
+	base := "/" + strings.Trim(config.BaseURL, "/")
+	if base != "/" {
+		base += "/"
+	}
+
+	return fmt.Sprintf("http://%s:%d%s", host, config.Port, base)
+}
+
+// requestApproval stores the release as pending approval and sends the approve and reject links,
+// the actions of the filter run once the release is approved
+func (s *service) requestApproval(l zerolog.Logger, release *domain.Release) {
+	release.FilterStatus = domain.ReleaseStatusFilterPendingApproval
+	if err := s.Store(context.Background(), release); err != nil {
+		l.Error().Err(err).Msgf("release.Process: error writing release to database: %+v", release)
+		s.addEvent(domain.ReleaseEventError, release, "", err.Error())
+		return
+	}
+
+	approval := domain.NewReleaseApproval(release, s.approvalWindow, time.Now())
+	if err := s.approvalRepo.Store(context.Background(), approval); err != nil {
+		l.Error().Err(err).Msgf("release.Process: could not store approval for release: %v", release.TorrentName)
+		s.addEvent(domain.ReleaseEventError, release, "", err.Error())
+		return
+	}
+
+	l.Info().Msgf("Holding '%v' (%v) for %v until it is approved", release.TorrentName, release.Filter.Name, release.Indexer)
+	s.addEvent(domain.ReleaseEventAction, release, "", "pending approval")
+
+	approveURL := s.approvalURL(approval, domain.ReleaseApprovalDecisionApprove)
+	rejectURL := s.approvalURL(approval, domain.ReleaseApprovalDecisionReject)
+
+	message := fmt.Sprintf("%v\nFilter: %v\nIndexer: %v", release.TorrentName, release.FilterName, release.Indexer)
+	if release.Size > 0 {
+		message += fmt.Sprintf("\nSize: %v", humanize.Bytes(release.Size))
+	}
+	message += fmt.Sprintf("\nExpires: %v\nApprove: %v\nReject: %v", approval.ExpiresAt.Format(time.RFC1123), approveURL, rejectURL)
+
+	s.notificationSvc.Send(domain.NotificationEventReleasePendingApproval, domain.NotificationPayload{
+		Subject:        "Release pending approval",
+		Message:        message,
+		Event:          domain.NotificationEventReleasePendingApproval,
+		ReleaseName:    release.TorrentName,
+		Filter:         release.FilterName,
+		Indexer:        release.Indexer,
+		Size:           release.Size,
+		Protocol:       release.Protocol,
+		Implementation: release.Implementation,
+		Timestamp:      time.Now(),
+		Release:        release,
+		ApproveURL:     approveURL,
+		RejectURL:      rejectURL,
+	})
+}
+
+func (s *service) approvalURL(approval *domain.ReleaseApproval, decision domain.ReleaseApprovalDecision) string {
+	return fmt.Sprintf("%sapi/approval/%d/%s?token=%s", s.approvalBaseURL, approval.ID, decision, url.QueryEscape(approval.Token(s.approvalSecret, decision)))
+}
+
+func (s *service) ListApprovals(ctx context.Context, status domain.ReleaseApprovalStatus) ([]*domain.ReleaseApproval, error) {
+	return s.approvalRepo.List(ctx, status)
+}
+
+// DecideApproval approves or rejects the pending release, approved releases run the actions of their filter
+func (s *service) DecideApproval(ctx context.Context, id int, decision domain.ReleaseApprovalDecision) error {
+	approval, err := s.approvalRepo.FindByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	return s.decideApproval(ctx, approval, decision)
+}
+
+// DecideApprovalWithToken is DecideApproval for the signed links of the approval notification
+func (s *service) DecideApprovalWithToken(ctx context.Context, id int, decision domain.ReleaseApprovalDecision, token string) error {
+	approval, err := s.approvalRepo.FindByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if !approval.VerifyToken(s.approvalSecret, decision, token) {
+		return errors.New("invalid token for release approval: %v", id)
+	}
+
+	return s.decideApproval(ctx, approval, decision)
+}
+
+func (s *service) decideApproval(ctx context.Context, approval *domain.ReleaseApproval, decision domain.ReleaseApprovalDecision) error {
+	// rebuild the release first so an approval whose release or filter is gone stays undecided
+	var release *domain.Release
+	if decision == domain.ReleaseApprovalDecisionApprove {
+		var err error
+		if release, err = s.approvalRelease(ctx, approval); err != nil {
+			return err
+		}
+	}
+
+	if err := approval.Decide(decision, domain.AuditActor(ctx), time.Now()); err != nil {
+		if approval.Status == domain.ReleaseApprovalStatusExpired {
+			if _, updateErr := s.approvalRepo.Update(ctx, approval); updateErr != nil {
+				s.log.Error().Err(updateErr).Msgf("could not update release approval: %v", approval.ID)
+			}
+		}
+
+		return err
+	}
+
+	// the approval was read before deciding, only the decision that updates it while still pending runs the actions
+	updated, err := s.approvalRepo.Update(ctx, approval)
+	if err != nil {
+		return err
+	}
+
+	if !updated {
+		return errors.New("release approval %v is already decided", approval.ID)
+	}
+
+	l := s.log.With().Str("indexer", approval.Indexer).Str("filter", approval.Filter).Str("release", approval.TorrentName).Logger()
+
+	if decision == domain.ReleaseApprovalDecisionReject {
+		l.Info().Msgf("Rejected '%v' (%v) for %v by %v", approval.TorrentName, approval.Filter, approval.Indexer, approval.DecidedBy)
+
+		if err := s.repo.UpdateFilterStatus(ctx, approval.ReleaseID, domain.ReleaseStatusFilterRejected); err != nil {
+			l.Error().Err(err).Msgf("could not update filter status of release: %v", approval.ReleaseID)
+		}
+
+		return nil
+	}
+
+	l.Info().Msgf("Approved '%v' (%v) for %v by %v", approval.TorrentName, approval.Filter, approval.Indexer, approval.DecidedBy)
+	s.addEvent(domain.ReleaseEventAction, release, "", "approved by "+approval.DecidedBy)
+
+	if err := s.repo.UpdateFilterStatus(ctx, approval.ReleaseID, domain.ReleaseStatusFilterApproved); err != nil {
+		l.Error().Err(err).Msgf("could not update filter status of release: %v", approval.ReleaseID)
+	}
+
+	// actions run in the background after the filter delay, don't hold up the request.
+	// Actions depending on another filter are skipped as the other filters are not checked again.
+	s.runActionsAfterDelay(l, release, true)
+
+	return nil
+}
+
+// approvalRelease rebuilds the stored release with the current filter and its actions
+func (s *service) approvalRelease(ctx context.Context, approval *domain.ReleaseApproval) (*domain.Release, error) {
+	stored, err := s.repo.FindByID(ctx, approval.ReleaseID)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not find release: %v", approval.ReleaseID)
+	}
+
+	if stored.TorrentURL == "" {
+		return nil, errors.New("no torrent url stored for release %v, actions can't be run", approval.ReleaseID)
+	}
+
+	f, err := s.filterSvc.FindByID(ctx, approval.FilterID)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not find filter: %v", approval.FilterID)
+	}
+
+	release := stored.Replay()
+	release.ID = stored.ID
+	release.Filter = f
+	release.FilterName = f.Name
+	release.FilterID = f.ID
+
+	return release, nil
+}
+
+// startApprovalExpiry expires the approvals that were not decided on in time
+func (s *service) startApprovalExpiry() {
+	go func() {
+		ticker := time.NewTicker(approvalExpireInterval)
+		defer ticker.Stop()
+
+		for range ticker.C {
+			// with several instances on the same database only the leader expires approvals
+			if !s.instanceSvc.IsLeader() {
+				continue
+			}
+
+			expired, err := s.approvalRepo.Expire(context.Background(), time.Now())
+			if err != nil {
+				s.log.Error().Err(err).Msg("could not expire release approvals")
+				continue
+			}
+
+			if expired > 0 {
+				s.log.Info().Msgf("expired %d release approvals", expired)
+			}
+		}
+	}()
+}
@@ -0,0 +1,108 @@
+package release
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/autobrr/autobrr/internal/domain"
+
+	"github.com/rs/zerolog"
+)
+
+const (
+	dedupPruneInterval = time.Hour
+
+	// dedupRetention is how long approved releases are kept for duplicate checks and the longest window
+	dedupRetention = 30 * 24 * time.Hour
+)
+
+// startDedupPruning deletes the releases kept for duplicate checks once they are past the longest window
+func (s *service) startDedupPruning() {
+	go func() {
+		ticker := time.NewTicker(dedupPruneInterval)
+		defer ticker.Stop()
+
+		for range ticker.C {
+			// with several instances on the same database only the leader prunes
+			if !s.instanceSvc.IsLeader() {
+				continue
+			}
+
+			if _, err := s.repo.DeleteDedup(context.Background(), time.Now().Add(-dedupRetention)); err != nil {
+				s.log.Error().Err(err).Msg("could not delete old release dedup keys")
+			}
+		}
+	}()
+}
+
+// dedupSettings returns the key and window to suppress duplicates of a release matched by the filter.
+// Filters without a key of their own use the key from the config, shared by all filters.
+func (s *service) dedupSettings(f *domain.Filter, release *domain.Release) (string, time.Duration) {
+	key := f.DedupKey
+	scope := fmt.Sprintf("filter:%d", f.ID)
+
+	if key == domain.FilterDedupKeyDefault {
+		key = s.dedupKey
+		scope = "global"
+	}
+
+	if key == domain.FilterDedupKeyDisabled {
+		return "", 0
+	}
+
+	window := s.dedupWindow
+	if f.DedupWindow > 0 {
+		window = time.Duration(f.DedupWindow) * time.Hour
+	}
+
+	if window > dedupRetention {
+		window = dedupRetention
+	}
+
+	value := release.DedupKey(key)
+	if value == "" || window <= 0 {
+		return "", 0
+	}
+
+	return scope + "|" + value, window
+}
+
+// isDuplicate returns true if another release with the same dedup key of the filter, or the global key, was claimed
+// within the window, eg. the same release announced on another indexer. Otherwise the release claims the key, so later
+// announces within the window are duplicates of it, and releaseDedup gives the key up if none of the actions push it.
+// Filters with dedup disabled, releases without a key value and errors never count as duplicates.
+func (s *service) isDuplicate(l zerolog.Logger, release *domain.Release) bool {
+	key, window := s.dedupSettings(release.Filter, release)
+	if key == "" {
+		return false
+	}
+
+	dup, err := s.repo.ClaimDedup(context.Background(), key, time.Now().Add(-window), release)
+	if err != nil {
+		// rather grab twice than miss the release
+		l.Error().Err(err).Msgf("release.Process: could not check for duplicates of release: %v", release.TorrentName)
+		return false
+	}
+
+	if dup == nil {
+		return false
+	}
+
+	l.Info().Msgf("Skipping actions for '%v' (%v), duplicate of '%v' from %v approved at %v", release.TorrentName, release.Filter.Name, dup.TorrentName, dup.Indexer, dup.CreatedAt.Local().Format(time.RFC3339))
+	s.addEvent(domain.ReleaseEventAction, release, "", fmt.Sprintf("skipped: duplicate of %v from %v", dup.TorrentName, dup.Indexer))
+
+	return true
+}
+
+// releaseDedup deletes the key claimed by isDuplicate for the release, if the release still holds it
+func (s *service) releaseDedup(l zerolog.Logger, release *domain.Release) {
+	key, _ := s.dedupSettings(release.Filter, release)
+	if key == "" {
+		return
+	}
+
+	if err := s.repo.DeleteDedupKey(context.Background(), key, release); err != nil {
+		l.Error().Err(err).Msgf("release.Process: could not release duplicate key of release: %v", release.TorrentName)
+	}
+}
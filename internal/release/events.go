@@ -0,0 +1,94 @@
+package release
+
+import (
+	"strings"
+	"sync/atomic"
+
+	"github.com/autobrr/autobrr/internal/domain"
+)
+
+const defaultEventBufferSize = 500
+
+type bufferedEvent struct {
+	seq   uint64
+	event domain.ReleaseEvent
+}
+
+// eventBuffer is a fixed size ring buffer of the latest release events.
+// Writers claim a slot with an atomic counter so announces never wait on a lock.
+type eventBuffer struct {
+	// keep first for 64-bit alignment on 32-bit platforms
+	next  uint64
+	slots []atomic.Value
+}
+
+func newEventBuffer(size int) *eventBuffer {
+	if size <= 0 {
+		size = defaultEventBufferSize
+	}
+
+	return &eventBuffer{
+		slots: make([]atomic.Value, size),
+	}
+}
+
+func (b *eventBuffer) add(event domain.ReleaseEvent) {
+	seq := atomic.AddUint64(&b.next, 1)
+	b.slots[(seq-1)%uint64(len(b.slots))].Store(&bufferedEvent{seq: seq, event: event})
+}
+
+// find returns matching events, newest first
+func (b *eventBuffer) find(params domain.ReleaseEventQueryParams) []domain.ReleaseEvent {
+	newest := atomic.LoadUint64(&b.next)
+	size := uint64(len(b.slots))
+
+	ret := make([]domain.ReleaseEvent, 0)
+
+	for i := uint64(0); i < size && i < newest; i++ {
+		seq := newest - i
+
+		e, ok := b.slots[(seq-1)%size].Load().(*bufferedEvent)
+		if !ok || e.seq != seq {
+			// not stored yet or already overwritten by a newer event
+			continue
+		}
+
+		if !matchEvent(e.event, params) {
+			continue
+		}
+
+		ret = append(ret, e.event)
+
+		if params.Limit > 0 && len(ret) >= params.Limit {
+			break
+		}
+	}
+
+	return ret
+}
+
+func matchEvent(event domain.ReleaseEvent, params domain.ReleaseEventQueryParams) bool {
+	if len(params.Types) > 0 && !containsFold(params.Types, string(event.Type)) {
+		return false
+	}
+
+	if len(params.Indexers) > 0 && !containsFold(params.Indexers, event.Indexer) {
+		return false
+	}
+
+	if !params.Since.IsZero() && !event.Timestamp.After(params.Since) {
+		return false
+	}
+
+	return true
+}
+
+func containsFold(values []string, value string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+
+	return false
+}
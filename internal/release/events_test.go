@@ -0,0 +1,75 @@
+package release
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/autobrr/autobrr/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_eventBuffer_find(t *testing.T) {
+	b := newEventBuffer(3)
+
+	assert.Empty(t, b.find(domain.ReleaseEventQueryParams{}))
+
+	b.add(domain.ReleaseEvent{Type: domain.ReleaseEventAnnounce, Indexer: "mock", TorrentName: "1"})
+	b.add(domain.ReleaseEvent{Type: domain.ReleaseEventMatch, Indexer: "mock", TorrentName: "2"})
+	b.add(domain.ReleaseEvent{Type: domain.ReleaseEventAnnounce, Indexer: "other", TorrentName: "3"})
+	b.add(domain.ReleaseEvent{Type: domain.ReleaseEventError, Indexer: "mock", TorrentName: "4"})
+
+	names := func(events []domain.ReleaseEvent) []string {
+		ret := []string{}
+		for _, e := range events {
+			ret = append(ret, e.TorrentName)
+		}
+		return ret
+	}
+
+	// oldest event is overwritten, newest first
+	assert.Equal(t, []string{"4", "3", "2"}, names(b.find(domain.ReleaseEventQueryParams{})))
+	assert.Equal(t, []string{"4", "3"}, names(b.find(domain.ReleaseEventQueryParams{Limit: 2})))
+	assert.Equal(t, []string{"4", "2"}, names(b.find(domain.ReleaseEventQueryParams{Indexers: []string{"MOCK"}})))
+	assert.Equal(t, []string{"3"}, names(b.find(domain.ReleaseEventQueryParams{Types: []string{"announce"}})))
+	assert.Equal(t, []string{"4"}, names(b.find(domain.ReleaseEventQueryParams{Types: []string{"ERROR", "MATCH"}, Indexers: []string{"mock"}, Limit: 1})))
+}
+
+func Test_eventBuffer_find_since(t *testing.T) {
+	b := newEventBuffer(10)
+
+	now := time.Date(2022, 10, 1, 12, 0, 0, 0, time.UTC)
+	for i := 1; i <= 3; i++ {
+		b.add(domain.ReleaseEvent{Timestamp: now.Add(time.Duration(i) * time.Second), Type: domain.ReleaseEventAnnounce, TorrentName: fmt.Sprint(i)})
+	}
+
+	assert.Len(t, b.find(domain.ReleaseEventQueryParams{Since: now}), 3)
+	assert.Len(t, b.find(domain.ReleaseEventQueryParams{Since: now.Add(2 * time.Second)}), 1)
+	assert.Empty(t, b.find(domain.ReleaseEventQueryParams{Since: now.Add(3 * time.Second)}))
+}
+
+func Test_eventBuffer_concurrent(t *testing.T) {
+	b := newEventBuffer(100)
+
+	var wg sync.WaitGroup
+	for w := 0; w < 8; w++ {
+		wg.Add(2)
+		go func(w int) {
+			defer wg.Done()
+			for i := 0; i < 500; i++ {
+				b.add(domain.ReleaseEvent{Type: domain.ReleaseEventAnnounce, Indexer: fmt.Sprintf("indexer-%d", w)})
+			}
+		}(w)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 50; i++ {
+				assert.LessOrEqual(t, len(b.find(domain.ReleaseEventQueryParams{})), 100)
+			}
+		}()
+	}
+	wg.Wait()
+
+	assert.Len(t, b.find(domain.ReleaseEventQueryParams{}), 100)
+}
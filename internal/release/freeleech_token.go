@@ -0,0 +1,32 @@
+package release
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/autobrr/autobrr/internal/domain"
+
+	"github.com/rs/zerolog"
+)
+
+// spendFreeleechToken spends a freeleech token on the release before the first action pushes it if it matches the indexer settings.
+// The token is only tried once per release, a failed request does not stop the actions.
+func (s *service) spendFreeleechToken(l zerolog.Logger, release *domain.Release) {
+	if !release.UseFreeleechToken() {
+		return
+	}
+
+	err := release.SpendFreeleechToken(context.Background())
+
+	// later filters matching the same release must not spend another token
+	release.FreeleechToken = nil
+
+	if err != nil {
+		l.Warn().Err(err).Msgf("could not spend freeleech token on '%v' (%v)", release.TorrentName, release.Indexer)
+		s.addEvent(domain.ReleaseEventError, release, "", fmt.Sprintf("freeleech token: %v", err))
+		return
+	}
+
+	l.Info().Msgf("Spent freeleech token on '%v' (%v)", release.TorrentName, release.Indexer)
+	s.addEvent(domain.ReleaseEventAction, release, "", "freeleech token used")
+}
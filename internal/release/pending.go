@@ -0,0 +1,206 @@
+package release
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/autobrr/autobrr/internal/domain"
+)
+
+// pendingRelease is a release matched by a filter with smart delay, waiting for competing announces
+type pendingRelease struct {
+	release *domain.Release
+
+	// filter matches and dependency cycles of the Process call the release came from
+	matchedFilters map[int]struct{}
+	unresolved     map[int]struct{}
+}
+
+type pendingGroup struct {
+	filter     domain.Filter
+	candidates []pendingRelease
+}
+
+// pendingQueue holds releases per filter and title until the smart delay window closes
+type pendingQueue struct {
+	mu     sync.Mutex
+	groups map[string]*pendingGroup
+
+	// decide is called with the releases from preferred to least preferred once the window closes
+	decide func(filter domain.Filter, ranked []pendingRelease)
+}
+
+func newPendingQueue(decide func(filter domain.Filter, ranked []pendingRelease)) *pendingQueue {
+	return &pendingQueue{
+		groups: map[string]*pendingGroup{},
+		decide: decide,
+	}
+}
+
+// add queues the release, the first release of a title opens the window for the filter
+func (q *pendingQueue) add(filter domain.Filter, p pendingRelease) {
+	key := pendingKey(filter.ID, p.release)
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if g, ok := q.groups[key]; ok {
+		g.candidates = append(g.candidates, p)
+		return
+	}
+
+	q.groups[key] = &pendingGroup{filter: filter, candidates: []pendingRelease{p}}
+
+	time.AfterFunc(time.Duration(filter.SmartDelay)*time.Second, func() {
+		q.close(key)
+	})
+}
+
+func (q *pendingQueue) close(key string) {
+	q.mu.Lock()
+	g, ok := q.groups[key]
+	delete(q.groups, key)
+	q.mu.Unlock()
+
+	if !ok {
+		return
+	}
+
+	q.decide(g.filter, rankPending(g.filter, g.candidates))
+}
+
+// rankPending orders the candidates from preferred to least preferred
+func rankPending(filter domain.Filter, candidates []pendingRelease) []pendingRelease {
+	remaining := append([]pendingRelease(nil), candidates...)
+	ranked := make([]pendingRelease, 0, len(candidates))
+
+	for len(remaining) > 0 {
+		releases := make([]*domain.Release, 0, len(remaining))
+		for _, c := range remaining {
+			releases = append(releases, c.release)
+		}
+
+		idx := preferredRelease(filter, releases)
+
+		ranked = append(ranked, remaining[idx])
+		remaining = append(remaining[:idx], remaining[idx+1:]...)
+	}
+
+	return ranked
+}
+
+// pendingKey identifies announces of the same title for a filter
+func pendingKey(filterID int, r *domain.Release) string {
+	title := r.Title
+	if title == "" {
+		title = r.TorrentName
+	}
+
+	return fmt.Sprintf("%d|%s|%d|%d|%d", filterID, strings.ToLower(title), r.Year, r.Season, r.Episode)
+}
+
+var resolutionRank = map[string]int{
+	"2160p": 5,
+	"1080p": 4,
+	"1080i": 3,
+	"720p":  2,
+	"576p":  1,
+	"480p":  1,
+}
+
+// sourceRank is keyed on the lowercase source, announces differ in case like Bluray and BluRay
+var sourceRank = map[string]int{
+	"uhd.bluray": 7,
+	"bluray":     6,
+	"web-dl":     5,
+	"web":        4,
+	"webrip":     3,
+	"hdtv":       2,
+	"dvdrip":     1,
+}
+
+var formatRank = map[string]int{
+	"FLAC": 3,
+	"MP3":  2,
+	"AAC":  1,
+}
+
+// qualityRank is compared resolution first, then source, then audio format
+func qualityRank(r *domain.Release) [3]int {
+	format := 0
+	for _, a := range r.Audio {
+		if rank := formatRank[strings.ToUpper(a)]; rank > format {
+			format = rank
+		}
+	}
+
+	return [3]int{resolutionRank[strings.ToLower(r.Resolution)], sourceRank[strings.ToLower(r.Source)], format}
+}
+
+// indexerRank is the position of the indexer in the filter preference list, unlisted indexers come last
+func indexerRank(preferred []string, indexer string) int {
+	for i, p := range preferred {
+		if strings.EqualFold(strings.TrimSpace(p), indexer) {
+			return i
+		}
+	}
+
+	return len(preferred)
+}
+
+// preferredRelease returns the index of the best release. Releases are ranked by quality,
+// then by the indexer order of the filter and then by the size preference.
+// If everything is equal the first announced release wins.
+func preferredRelease(filter domain.Filter, releases []*domain.Release) int {
+	var indexers []string
+	if filter.SmartDelayIndexers != "" {
+		indexers = strings.Split(filter.SmartDelayIndexers, ",")
+	}
+
+	best := 0
+
+	for i := 1; i < len(releases); i++ {
+		a, b := releases[i], releases[best]
+
+		qa, qb := qualityRank(a), qualityRank(b)
+		if qa != qb {
+			if compareRank(qa, qb) > 0 {
+				best = i
+			}
+			continue
+		}
+
+		ia, ib := indexerRank(indexers, a.Indexer), indexerRank(indexers, b.Indexer)
+		if ia != ib {
+			if ia < ib {
+				best = i
+			}
+			continue
+		}
+
+		switch filter.SmartDelayPreferSize {
+		case domain.FilterSizePreferenceSmaller:
+			if a.Size < b.Size {
+				best = i
+			}
+		case domain.FilterSizePreferenceLarger:
+			if a.Size > b.Size {
+				best = i
+			}
+		}
+	}
+
+	return best
+}
+
+func compareRank(a, b [3]int) int {
+	for i := range a {
+		if a[i] != b[i] {
+			return a[i] - b[i]
+		}
+	}
+
+	return 0
+}
@@ -0,0 +1,126 @@
+package release
+
+import (
+	"testing"
+
+	"github.com/autobrr/autobrr/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_preferredRelease(t *testing.T) {
+	webdl := &domain.Release{Indexer: "alpha", Title: "That Show", Resolution: "1080p", Source: "WEB-DL", Size: 4000}
+	webdlSmall := &domain.Release{Indexer: "beta", Title: "That Show", Resolution: "1080p", Source: "WEB-DL", Size: 3000}
+	webdlLarge := &domain.Release{Indexer: "gamma", Title: "That Show", Resolution: "1080p", Source: "WEB-DL", Size: 5000}
+	uhd := &domain.Release{Indexer: "gamma", Title: "That Show", Resolution: "2160p", Source: "WEB-DL", Size: 12000}
+	bluray := &domain.Release{Indexer: "beta", Title: "That Show", Resolution: "1080p", Source: "BluRay", Size: 9000}
+	webrip := &domain.Release{Indexer: "alpha", Title: "That Show", Resolution: "1080p", Source: "WEBRip", Size: 4000}
+	webdlLower := &domain.Release{Indexer: "beta", Title: "That Show", Resolution: "1080P", Source: "web-dl", Size: 4000}
+	blurayMixed := &domain.Release{Indexer: "gamma", Title: "That Show", Resolution: "1080p", Source: "Bluray", Size: 9000}
+	mp3 := &domain.Release{Indexer: "alpha", Title: "That Album", Audio: []string{"MP3", "320"}, Size: 100}
+	flac := &domain.Release{Indexer: "beta", Title: "That Album", Audio: []string{"FLAC", "Lossless"}, Size: 400}
+
+	tests := []struct {
+		name     string
+		filter   domain.Filter
+		releases []*domain.Release
+		want     int
+	}{
+		{
+			name:     "first_announce_wins_when_equal",
+			releases: []*domain.Release{webdl, webdlSmall, webdlLarge},
+			want:     0,
+		},
+		{
+			name:     "higher_resolution",
+			releases: []*domain.Release{webdl, uhd},
+			want:     1,
+		},
+		{
+			name:     "better_source",
+			releases: []*domain.Release{webdl, bluray},
+			want:     1,
+		},
+		{
+			name:     "source_case_insensitive",
+			releases: []*domain.Release{webrip, webdlLower},
+			want:     1,
+		},
+		{
+			name:     "source_case_insensitive_best",
+			releases: []*domain.Release{webdl, webrip, blurayMixed, webdlLower},
+			want:     2,
+		},
+		{
+			name:     "better_audio_format",
+			releases: []*domain.Release{mp3, flac},
+			want:     1,
+		},
+		{
+			name:     "quality_before_indexer",
+			filter:   domain.Filter{SmartDelayIndexers: "alpha,beta"},
+			releases: []*domain.Release{uhd, webdl},
+			want:     0,
+		},
+		{
+			name:     "indexer_priority",
+			filter:   domain.Filter{SmartDelayIndexers: "gamma, beta"},
+			releases: []*domain.Release{webdl, webdlSmall, webdlLarge},
+			want:     2,
+		},
+		{
+			name:     "indexer_priority_before_size",
+			filter:   domain.Filter{SmartDelayIndexers: "alpha", SmartDelayPreferSize: domain.FilterSizePreferenceSmaller},
+			releases: []*domain.Release{webdlSmall, webdl},
+			want:     1,
+		},
+		{
+			name:     "tiebreak_smaller",
+			filter:   domain.Filter{SmartDelayPreferSize: domain.FilterSizePreferenceSmaller},
+			releases: []*domain.Release{webdl, webdlLarge, webdlSmall},
+			want:     2,
+		},
+		{
+			name:     "tiebreak_larger",
+			filter:   domain.Filter{SmartDelayPreferSize: domain.FilterSizePreferenceLarger},
+			releases: []*domain.Release{webdl, webdlLarge, webdlSmall},
+			want:     1,
+		},
+		{
+			name:     "tiebreak_after_quality",
+			filter:   domain.Filter{SmartDelayPreferSize: domain.FilterSizePreferenceSmaller},
+			releases: []*domain.Release{webdlSmall, bluray},
+			want:     1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, preferredRelease(tt.filter, tt.releases))
+		})
+	}
+}
+
+func Test_pendingQueue(t *testing.T) {
+	filter := domain.Filter{ID: 1, Name: "tv", SmartDelay: 3600, SmartDelayPreferSize: domain.FilterSizePreferenceLarger}
+
+	var ranked []string
+
+	q := newPendingQueue(func(f domain.Filter, r []pendingRelease) {
+		for _, p := range r {
+			ranked = append(ranked, p.release.Indexer)
+		}
+	})
+
+	q.add(filter, pendingRelease{release: &domain.Release{Indexer: "alpha", Title: "That Show", Season: 1, Episode: 2, Size: 100}})
+	q.add(filter, pendingRelease{release: &domain.Release{Indexer: "beta", Title: "that show", Season: 1, Episode: 2, Size: 300}})
+	q.add(filter, pendingRelease{release: &domain.Release{Indexer: "gamma", Title: "That Show", Season: 1, Episode: 2, Size: 200}})
+	q.add(filter, pendingRelease{release: &domain.Release{Indexer: "alpha", Title: "That Show", Season: 1, Episode: 3, Size: 100}})
+
+	assert.Len(t, q.groups, 2)
+
+	q.close(pendingKey(filter.ID, &domain.Release{Title: "That Show", Season: 1, Episode: 2}))
+
+	// largest first
+	assert.Equal(t, []string{"beta", "gamma", "alpha"}, ranked)
+	assert.Len(t, q.groups, 1)
+}
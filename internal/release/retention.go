@@ -0,0 +1,67 @@
+package release
+
+import (
+	"context"
+	"time"
+
+	"github.com/autobrr/autobrr/internal/domain"
+)
+
+const (
+	pruneInterval     = time.Hour
+	pruneStartupDelay = 5 * time.Minute
+)
+
+// startPruning prunes the release history hourly when a retention limit is configured
+func (s *service) startPruning() {
+	if !s.retention.Enabled() {
+		s.log.Debug().Msg("release retention disabled")
+		return
+	}
+
+	go func() {
+		// give the instance time to settle leadership and start up before the first prune
+		time.Sleep(pruneStartupDelay)
+
+		ticker := time.NewTicker(pruneInterval)
+		defer ticker.Stop()
+
+		for {
+			s.runPrune(context.Background())
+			<-ticker.C
+		}
+	}()
+}
+
+func (s *service) runPrune(ctx context.Context) {
+	// with several instances on the same database only the leader prunes
+	if !s.instanceSvc.IsLeader() {
+		return
+	}
+
+	if _, err := s.Prune(ctx); err != nil {
+		s.log.Error().Err(err).Msg("could not prune releases")
+	}
+}
+
+// Prune deletes the releases outside of the configured retention, it does nothing if retention is disabled
+func (s *service) Prune(ctx context.Context) (int64, error) {
+	if !s.retention.Enabled() {
+		return 0, nil
+	}
+
+	start := time.Now()
+
+	deleted, err := s.repo.Prune(ctx, s.retention)
+	if err != nil {
+		return 0, err
+	}
+
+	s.log.Info().Msgf("pruned %d releases in %v", deleted, time.Since(start).Round(time.Millisecond))
+
+	return deleted, nil
+}
+
+func (s *service) Retention() domain.ReleaseRetention {
+	return s.retention
+}
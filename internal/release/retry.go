@@ -0,0 +1,166 @@
+package release
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/internal/metrics"
+	"github.com/autobrr/autobrr/pkg/errors"
+)
+
+const retryCheckInterval = 30 * time.Second
+
+// Start runs the due action retries, release pruning, dedup key pruning and approval expiry in the background
+func (s *service) Start() {
+	s.startPruning()
+	s.startDedupPruning()
+	s.startApprovalExpiry()
+
+	if s.retryWindow <= 0 {
+		s.log.Debug().Msg("action retries disabled")
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(retryCheckInterval)
+		defer ticker.Stop()
+
+		for range ticker.C {
+			s.runDueRetries(context.Background())
+		}
+	}()
+}
+
+// queueRetry stores a failed action to be retried with backoff
+func (s *service) queueRetry(release *domain.Release, action *domain.Action, actionErr error) {
+	if s.retryWindow <= 0 || release.ID == 0 || action.ID == 0 {
+		return
+	}
+
+	retry := domain.NewActionRetry(release, action, actionErr, s.retryWindow, time.Now())
+
+	if err := s.retryRepo.Store(context.Background(), retry); err != nil {
+		s.log.Error().Err(err).Msgf("could not queue retry of action %v for release: %v", action.Name, release.TorrentName)
+		return
+	}
+
+	s.log.Debug().Msgf("queued retry of action %v for '%v' at %v", action.Name, release.TorrentName, retry.NextAttemptAt.Format(time.RFC3339))
+}
+
+func (s *service) runDueRetries(ctx context.Context) {
+	// with several instances on the same database only the leader runs actions
+	if !s.instanceSvc.IsLeader() {
+		return
+	}
+
+	retries, err := s.retryRepo.FindDue(ctx)
+	if err != nil {
+		s.log.Error().Err(err).Msg("could not find due action retries")
+		return
+	}
+
+	for _, retry := range retries {
+		if err := s.runRetry(ctx, retry); err != nil {
+			s.log.Debug().Err(err).Msgf("retry %d of action %v for '%v' failed", retry.Attempts, retry.Action, retry.TorrentName)
+		}
+	}
+}
+
+func (s *service) ListRetries(ctx context.Context) ([]*domain.ActionRetry, error) {
+	return s.retryRepo.List(ctx)
+}
+
+// Retry runs a queued action now, also if it ran out of its retry window
+func (s *service) Retry(ctx context.Context, id int) error {
+	retry, err := s.retryRepo.FindByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	// a manual retry gets a new window
+	retry.ExpiresAt = time.Now().Add(s.retryWindow)
+
+	return s.runRetry(ctx, retry)
+}
+
+// DiscardRetry removes an action from the retry queue
+func (s *service) DiscardRetry(ctx context.Context, id int) error {
+	return s.retryRepo.Delete(ctx, id)
+}
+
+// runRetry runs the action again and removes it from the queue unless it failed again
+func (s *service) runRetry(ctx context.Context, retry *domain.ActionRetry) error {
+	release, action, err := s.retryRelease(ctx, retry)
+	if err != nil {
+		// the release, filter or action is gone, nothing left to retry
+		retry.Status = domain.ActionRetryStatusFailed
+		retry.LastError = err.Error()
+
+		if updateErr := s.retryRepo.Update(ctx, retry); updateErr != nil {
+			s.log.Error().Err(updateErr).Msgf("could not update action retry: %v", retry.ID)
+		}
+
+		return err
+	}
+
+	rejections, err := s.actionSvc.RunAction(action, *release)
+	if err != nil {
+		s.addEvent(domain.ReleaseEventError, release, action.Name, "retry failed: "+err.Error())
+		metrics.ActionPushes.Inc(string(action.Type), action.Client.Name, "error")
+
+		if !retry.Failed(err, time.Now()) {
+			s.log.Warn().Msgf("giving up on action %v for '%v' after %d attempts: %v", action.Name, release.TorrentName, retry.Attempts, err)
+		}
+
+		if updateErr := s.retryRepo.Update(ctx, retry); updateErr != nil {
+			s.log.Error().Err(updateErr).Msgf("could not update action retry: %v", retry.ID)
+		}
+
+		return err
+	}
+
+	if len(rejections) > 0 {
+		s.addEvent(domain.ReleaseEventAction, release, action.Name, "retry rejected: "+strings.Join(rejections, ", "))
+		metrics.ActionPushes.Inc(string(action.Type), action.Client.Name, "rejected")
+	} else {
+		s.addEvent(domain.ReleaseEventAction, release, action.Name, "retry approved")
+		metrics.ActionPushes.Inc(string(action.Type), action.Client.Name, "approved")
+	}
+
+	return s.retryRepo.Delete(ctx, retry.ID)
+}
+
+// retryRelease rebuilds the release and finds the action to run again
+func (s *service) retryRelease(ctx context.Context, retry *domain.ActionRetry) (*domain.Release, *domain.Action, error) {
+	stored, err := s.repo.FindByID(ctx, retry.ReleaseID)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "could not find release: %v", retry.ReleaseID)
+	}
+
+	f, err := s.filterSvc.FindByID(ctx, retry.FilterID)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "could not find filter: %v", retry.FilterID)
+	}
+
+	var action *domain.Action
+	for _, a := range f.Actions {
+		if a.ID == retry.ActionID {
+			action = a
+			break
+		}
+	}
+
+	if action == nil {
+		return nil, nil, errors.New("could not find action %v on filter %v", retry.ActionID, f.Name)
+	}
+
+	release := stored.Replay()
+	release.ID = stored.ID
+	release.Filter = f
+	release.FilterName = f.Name
+	release.FilterID = f.ID
+
+	return release, action, nil
+}
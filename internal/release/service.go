@@ -2,28 +2,55 @@ package release
 
 import (
 	"context"
+	"fmt"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/autobrr/autobrr/internal/action"
+	"github.com/autobrr/autobrr/internal/blocklist"
 	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/internal/enrichment"
 	"github.com/autobrr/autobrr/internal/filter"
+	"github.com/autobrr/autobrr/internal/instance"
 	"github.com/autobrr/autobrr/internal/logger"
+	"github.com/autobrr/autobrr/internal/metrics"
+	"github.com/autobrr/autobrr/internal/notification"
 
+	"github.com/asaskevich/EventBus"
 	"github.com/rs/zerolog"
 )
 
+// exportPageSize is the number of releases read at a time for an export
+const exportPageSize = 500
+
 type Service interface {
 	Find(ctx context.Context, query domain.ReleaseQueryParams) (res []*domain.Release, nextCursor int64, count int64, err error)
+	Export(ctx context.Context, query domain.ReleaseQueryParams, fn func(releases []*domain.Release) error) error
 	FindRecent(ctx context.Context) ([]*domain.Release, error)
 	GetIndexerOptions(ctx context.Context) ([]string, error)
 	Stats(ctx context.Context) (*domain.ReleaseStats, error)
 	Store(ctx context.Context, release *domain.Release) error
 	StoreReleaseActionStatus(ctx context.Context, actionStatus *domain.ReleaseActionStatus) error
 	Delete(ctx context.Context) error
+	Prune(ctx context.Context) (int64, error)
+	Retention() domain.ReleaseRetention
+	FindEvents(params domain.ReleaseEventQueryParams) []domain.ReleaseEvent
 
 	Process(release *domain.Release)
-	ProcessMultiple(releases []*domain.Release)
+	Enqueue(release *domain.Release) error
+	EnqueueWait(ctx context.Context, release *domain.Release) error
+	ProcessMultiple(ctx context.Context, releases []*domain.Release) (int, error)
+	Replay(ctx context.Context, req domain.ReleaseReplayRequest) ([]domain.ReleaseReplayResult, error)
+
+	Start()
+	ListRetries(ctx context.Context) ([]*domain.ActionRetry, error)
+	Retry(ctx context.Context, id int) error
+	DiscardRetry(ctx context.Context, id int) error
+
+	ListApprovals(ctx context.Context, status domain.ReleaseApprovalStatus) ([]*domain.ReleaseApproval, error)
+	DecideApproval(ctx context.Context, id int, decision domain.ReleaseApprovalDecision) error
+	DecideApprovalWithToken(ctx context.Context, id int, decision domain.ReleaseApprovalDecision, token string) error
 }
 
 type actionClientTypeKey struct {
@@ -32,26 +59,100 @@ type actionClientTypeKey struct {
 }
 
 type service struct {
-	log  zerolog.Logger
-	repo domain.ReleaseRepo
-
-	actionSvc action.Service
-	filterSvc filter.Service
+	log          zerolog.Logger
+	repo         domain.ReleaseRepo
+	retryRepo    domain.ActionRetryRepo
+	approvalRepo domain.ReleaseApprovalRepo
+
+	actionSvc       action.Service
+	filterSvc       filter.Service
+	blocklistSvc    blocklist.Service
+	instanceSvc     instance.Service
+	enrichmentSvc   enrichment.Service
+	notificationSvc notification.Service
+	bus             EventBus.Bus
+
+	events  *eventBuffer
+	pending *pendingQueue
+	workers *workerPools
+
+	// failed actions are retried for this long, 0 disables retries
+	retryWindow time.Duration
+
+	// default duplicate suppression for filters without their own key
+	dedupKey    domain.FilterDedupKey
+	dedupWindow time.Duration
+
+	retention domain.ReleaseRetention
+
+	// releases of filters requiring approval wait this long for a decision
+	approvalWindow time.Duration
+	// approve and reject links are signed with the secret and point to the base url
+	approvalSecret  string
+	approvalBaseURL string
+
+	// replayed releases run their actions one at a time
+	replayMu sync.Mutex
 }
 
-func NewService(log logger.Logger, repo domain.ReleaseRepo, actionSvc action.Service, filterSvc filter.Service) Service {
-	return &service{
-		log:       log.With().Str("module", "release").Logger(),
-		repo:      repo,
-		actionSvc: actionSvc,
-		filterSvc: filterSvc,
+func NewService(log logger.Logger, config *domain.Config, repo domain.ReleaseRepo, retryRepo domain.ActionRetryRepo, approvalRepo domain.ReleaseApprovalRepo, actionSvc action.Service, filterSvc filter.Service, blocklistSvc blocklist.Service, instanceSvc instance.Service, enrichmentSvc enrichment.Service, notificationSvc notification.Service, bus EventBus.Bus) Service {
+	s := &service{
+		log:             log.With().Str("module", "release").Logger(),
+		repo:            repo,
+		retryRepo:       retryRepo,
+		approvalRepo:    approvalRepo,
+		actionSvc:       actionSvc,
+		filterSvc:       filterSvc,
+		blocklistSvc:    blocklistSvc,
+		instanceSvc:     instanceSvc,
+		enrichmentSvc:   enrichmentSvc,
+		notificationSvc: notificationSvc,
+		bus:             bus,
+		events:          newEventBuffer(defaultEventBufferSize),
+		retryWindow:     time.Duration(config.ActionRetryWindow) * time.Minute,
+		dedupKey:        domain.FilterDedupKey(config.ReleaseDedupKey),
+		dedupWindow:     time.Duration(config.ReleaseDedupWindow) * time.Hour,
+		retention:       domain.NewReleaseRetention(config),
+		approvalWindow:  time.Duration(config.ReleaseApprovalWindow) * time.Hour,
+		approvalSecret:  config.SessionSecret,
+		approvalBaseURL: approvalBaseURL(config),
 	}
+
+	s.pending = newPendingQueue(s.processPending)
+	s.workers = newWorkerPools(config.ReleaseIndexerWorkers, config.ReleaseIndexerQueueSize, s.Process)
+
+	return s
 }
 
 func (s *service) Find(ctx context.Context, query domain.ReleaseQueryParams) (res []*domain.Release, nextCursor int64, count int64, err error) {
 	return s.repo.Find(ctx, query)
 }
 
+// Export hands all releases matching the query to fn, a page at a time and newest first
+func (s *service) Export(ctx context.Context, query domain.ReleaseQueryParams, fn func(releases []*domain.Release) error) error {
+	query.Limit = exportPageSize
+	query.Offset = 0
+
+	for {
+		releases, nextCursor, _, err := s.repo.Find(ctx, query)
+		if err != nil {
+			return err
+		}
+
+		if len(releases) > 0 {
+			if err := fn(releases); err != nil {
+				return err
+			}
+		}
+
+		if nextCursor == 0 {
+			return nil
+		}
+
+		query.Cursor = uint64(nextCursor)
+	}
+}
+
 func (s *service) FindRecent(ctx context.Context) (res []*domain.Release, err error) {
 	return s.repo.FindRecent(ctx)
 }
@@ -61,7 +162,17 @@ func (s *service) GetIndexerOptions(ctx context.Context) ([]string, error) {
 }
 
 func (s *service) Stats(ctx context.Context) (*domain.ReleaseStats, error) {
-	return s.repo.Stats(ctx)
+	stats, err := s.repo.Stats(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// blocked announces are not stored, their count is kept on the blocklist rules
+	if stats.BlockedCount, err = s.blocklistSvc.TotalHits(ctx); err != nil {
+		return nil, err
+	}
+
+	return stats, nil
 }
 
 func (s *service) Store(ctx context.Context, release *domain.Release) error {
@@ -81,19 +192,58 @@ func (s *service) Delete(ctx context.Context) error {
 	return s.repo.Delete(ctx)
 }
 
+// FindEvents returns the latest release events kept in memory, newest first
+func (s *service) FindEvents(params domain.ReleaseEventQueryParams) []domain.ReleaseEvent {
+	return s.events.find(params)
+}
+
+// addEvent keeps the event for debugging and publishes it for the live event stream
+func (s *service) addEvent(eventType domain.ReleaseEventType, release *domain.Release, action string, message string) {
+	event := domain.ReleaseEvent{
+		Timestamp:   time.Now(),
+		Type:        eventType,
+		Indexer:     release.Indexer,
+		TorrentName: release.TorrentName,
+		Filter:      release.FilterName,
+		Action:      action,
+		Message:     message,
+	}
+
+	s.events.add(event)
+
+	s.bus.Publish("release:event", &event)
+}
+
 func (s *service) Process(release *domain.Release) {
 	if release == nil {
 		return
 	}
 
+	s.addEvent(domain.ReleaseEventAnnounce, release, "", string(release.Implementation))
+	metrics.Announces.Inc(release.Indexer)
+
+	// blocked announces never reach the filters or the release history
+	if rule, blocked := s.blocklistSvc.Check(release); blocked {
+		s.log.Debug().Str("indexer", release.Indexer).Str("release", release.TorrentName).Msgf("release.Process: blocked by announce blocklist rule: %v", rule)
+		s.addEvent(domain.ReleaseEventBlocked, release, "", rule.String())
+		metrics.AnnouncesBlocked.Inc(release.Indexer, string(rule.Type))
+
+		// like filter rejections only the leader counts hits to not count them twice
+		if s.instanceSvc.IsLeader() {
+			s.blocklistSvc.RecordHit(rule)
+		}
+
+		return
+	}
+
 	// TODO check in config for "Save all releases"
 	// TODO cross-seed check
-	// TODO dupe checks
 
 	// get filters by priority
 	filters, err := s.filterSvc.FindByIndexerIdentifier(release.Indexer)
 	if err != nil {
 		s.log.Error().Err(err).Msgf("release.Process: error finding filters for indexer: %v", release.Indexer)
+		s.addEvent(domain.ReleaseEventError, release, "", err.Error())
 		return
 	}
 
@@ -101,12 +251,67 @@ func (s *service) Process(release *domain.Release) {
 		return
 	}
 
+	// order filters so that each filter is evaluated after the filters its actions depend on
+	deps, err := s.actionSvc.FindFilterDependencies(context.Background())
+	if err != nil {
+		s.log.Error().Err(err).Msgf("release.Process: error finding filter dependencies for indexer: %v", release.Indexer)
+		s.addEvent(domain.ReleaseEventError, release, "", err.Error())
+		return
+	}
+
+	filters, unresolved := deps.SortFilters(filters)
+	if len(unresolved) > 0 {
+		s.log.Warn().Msgf("release.Process: filter action dependencies form a cycle, dependent actions of filters %v will be skipped", unresolved)
+	}
+
+	s.processFilters(&filterRun{
+		release:            release,
+		filters:            filters,
+		deps:               deps,
+		targets:            deps.Targets(),
+		unresolved:         unresolved,
+		matchedFilters:     map[int]struct{}{},
+		triedActionClients: map[actionClientTypeKey]struct{}{},
+	}, 0)
+}
+
+// filterRun is the state of checking a release against the filters of its indexer,
+// it is carried over when the actions of a filter run after the filter delay
+type filterRun struct {
+	release    *domain.Release
+	filters    []domain.Filter
+	deps       domain.FilterDependencies
+	targets    map[int]struct{}
+	unresolved map[int]struct{}
+
+	// filters that matched this release, checked by actions that depend on another filter
+	matchedFilters map[int]struct{}
+
+	// set once a filter has run all its actions, after that only filters with dependent actions
+	// and the filters they depend on are evaluated, and only the dependent actions run
+	handled bool
+
 	// keep track of action clients to avoid sending the same thing all over again
 	// save both client type and client id to potentially try another client of same type
-	triedActionClients := map[actionClientTypeKey]struct{}{}
+	triedActionClients map[actionClientTypeKey]struct{}
+}
+
+// processFilters checks the release against the filters starting at from. A filter with a delay runs its actions
+// and the remaining filters on a timer, so the delay doesn't hold up a worker.
+func (s *service) processFilters(run *filterRun, from int) {
+	release := run.release
 
 	// loop over and check filters
-	for _, f := range filters {
+	for i := from; i < len(run.filters); i++ {
+		f := run.filters[i]
+
+		_, target := run.targets[f.ID]
+		dependent := len(run.deps[f.ID]) > 0
+
+		if run.handled && !dependent && !target {
+			continue
+		}
+
 		l := s.log.With().Str("indexer", release.Indexer).Str("filter", f.Name).Str("release", release.TorrentName).Logger()
 
 		// save filter on release
@@ -118,6 +323,7 @@ func (s *service) Process(release *domain.Release) {
 		match, err := s.filterSvc.CheckFilter(f, release)
 		if err != nil {
 			l.Error().Err(err).Msg("release.Process: error checking filter")
+			s.addEvent(domain.ReleaseEventError, release, "", err.Error())
 			return
 		}
 
@@ -125,84 +331,522 @@ func (s *service) Process(release *domain.Release) {
 			l.Trace().Msgf("release.Process: indexer: %v, filter: %v release: %v, no match. rejections: %v", release.Indexer, release.Filter.Name, release.TorrentName, release.RejectionsString())
 
 			l.Debug().Msgf("release rejected: %v", release.RejectionsString())
+
+			for _, rejection := range release.Rejections {
+				metrics.FilterRejections.Inc(f.Name, metrics.RejectionReason(rejection))
+			}
+
+			// every instance checks filters, only the leader counts rejections to not count them twice
+			if s.instanceSvc.IsLeader() {
+				s.filterSvc.RecordRejections(f.ID, release.Rejections)
+			}
+
 			continue
 		}
 
 		l.Info().Msgf("Matched '%v' (%v) for %v", release.TorrentName, release.Filter.Name, release.Indexer)
+		run.matchedFilters[f.ID] = struct{}{}
+		s.addEvent(domain.ReleaseEventMatch, release, "", "")
+		metrics.FilterMatches.Inc(release.Indexer, f.Name)
 
-		// save release here to only save those with rejections from actions instead of all releases
-		if release.ID == 0 {
-			release.FilterStatus = domain.ReleaseStatusFilterApproved
-			err = s.Store(context.Background(), release)
-			if err != nil {
-				l.Error().Err(err).Msgf("release.Process: error writing release to database: %+v", release)
-				return
+		// another filter already grabbed the release, the match only counts for dependent actions
+		if run.handled {
+			if !dependent {
+				continue
 			}
+
+			f.Actions = dependentActions(f.Actions)
 		}
 
-		// sleep for the delay period specified in the filter before running actions
-		delay := release.Filter.Delay
-		if delay > 0 {
-			l.Debug().Msgf("Delaying processing of '%v' (%v) for %v by %d seconds as specified in the filter", release.TorrentName, release.Filter.Name, release.Indexer, delay)
-			time.Sleep(time.Duration(delay) * time.Second)
+		// with several instances on the same database only one runs the actions
+		if !s.instanceSvc.ClaimRelease(context.Background(), release) {
+			l.Info().Msgf("Skipping actions for '%v' (%v), instance %v is on standby or another instance handled it", release.TorrentName, release.Filter.Name, s.instanceSvc.Name())
+			s.addEvent(domain.ReleaseEventAction, release, "", "skipped: handled by another instance")
+			run.handled = true
+			continue
 		}
 
-		var rejections []string
+		// hold the release to collect competing announces of the same title, the preferred one is actioned later
+		if f.SmartDelay > 0 {
+			l.Debug().Msgf("Holding '%v' (%v) for %v up to %d seconds to collect competing announces", release.TorrentName, release.Filter.Name, release.Indexer, f.SmartDelay)
 
-		// run actions (watchFolder, test, exec, qBittorrent, Deluge, arr etc.)
-		for _, a := range release.Filter.Actions {
-			// only run enabled actions
-			if !a.Enabled {
-				l.Trace().Msgf("release.Process: indexer: %v, filter: %v release: %v action '%v' not enabled, skip", release.Indexer, release.Filter.Name, release.TorrentName, a.Name)
-				continue
+			pending := *release
+			filter := f
+			pending.Filter = &filter
+
+			// filters are sorted by dependencies so every filter this one depends on has already been checked
+			matched := make(map[int]struct{}, len(run.matchedFilters))
+			for id := range run.matchedFilters {
+				matched[id] = struct{}{}
 			}
 
-			l.Trace().Msgf("release.Process: indexer: %v, filter: %v release: %v , run action: %v", release.Indexer, release.Filter.Name, release.TorrentName, a.Name)
+			s.pending.add(filter, pendingRelease{release: &pending, matchedFilters: matched, unresolved: run.unresolved})
 
-			// keep track of action clients to avoid sending the same thing all over again
-			_, tried := triedActionClients[actionClientTypeKey{Type: a.Type, ClientID: a.ClientID}]
-			if tried {
-				l.Trace().Msgf("release.Process: indexer: %v, filter: %v release: %v action client already tried, skip", release.Indexer, release.Filter.Name, release.TorrentName)
+			run.handled = true
+			continue
+		}
+
+		// the same release from another indexer was already approved. Once handled the release itself
+		// holds the key, the dependent actions are meant to run for it.
+		if !run.handled && s.isDuplicate(l, release) {
+			run.handled = true
+			continue
+		}
+
+		// the actions run once the release is approved
+		if f.RequireApproval {
+			s.requestApproval(l, release)
+			run.handled = true
+			continue
+		}
+
+		// wait for the delay period specified in the filter before running actions, then go on with the next filters
+		if f.Delay > 0 {
+			l.Debug().Msgf("Delaying processing of '%v' (%v) for %v by %d seconds as specified in the filter", release.TorrentName, release.Filter.Name, release.Indexer, f.Delay)
+
+			next := i + 1
+			time.AfterFunc(time.Duration(f.Delay)*time.Second, func() {
+				if s.runFilterActions(l, run) {
+					s.processFilters(run, next)
+				}
+			})
+
+			return
+		}
+
+		if !s.runFilterActions(l, run) {
+			return
+		}
+	}
+}
+
+// dependentActions returns the actions that depend on another filter
+func dependentActions(actions []*domain.Action) []*domain.Action {
+	var dependent []*domain.Action
+	for _, a := range actions {
+		if a.DependsOnFilterID != 0 {
+			dependent = append(dependent, a)
+		}
+	}
+
+	return dependent
+}
+
+// runFilterActions runs the actions of the filter set on the release and marks the release handled
+// unless they were rejected or skipped. It returns false if the remaining filters should not be checked.
+func (s *service) runFilterActions(l zerolog.Logger, run *filterRun) bool {
+	rejections, skipped, attempted, approved, ok := s.runActions(l, run.release, run.matchedFilters, run.unresolved, run.triedActionClients)
+	if approved == 0 {
+		if run.handled {
+			// the duplicate key belongs to the filter that grabbed the release
+			s.instanceSvc.UnclaimRelease(context.Background(), run.release)
+		} else {
+			s.unclaim(l, run.release)
+		}
+	}
+
+	if !ok {
+		return false
+	}
+
+	// if we have rejections from arr, continue to next filter
+	if len(rejections) > 0 {
+		return true
+	}
+
+	// if only dependent or conditional actions were skipped the release is not handled, continue to next filter
+	if skipped > 0 && attempted == 0 {
+		return true
+	}
+
+	// all actions run, only filters with dependent actions are left to evaluate
+	run.handled = true
+
+	return true
+}
+
+// runActionsAfterDelay runs the actions of the release in the background once the filter delay has passed.
+// claimed is set if the release was claimed when it matched, the claims are given up if no action is approved.
+func (s *service) runActionsAfterDelay(l zerolog.Logger, release *domain.Release, claimed bool) {
+	run := func() {
+		_, _, _, approved, _ := s.runActions(l, release, map[int]struct{}{}, map[int]struct{}{}, map[actionClientTypeKey]struct{}{})
+		if claimed && approved == 0 {
+			s.unclaim(l, release)
+		}
+	}
+
+	delay := release.Filter.Delay
+	if delay <= 0 {
+		go run()
+		return
+	}
+
+	l.Debug().Msgf("Delaying processing of '%v' (%v) for %v by %d seconds as specified in the filter", release.TorrentName, release.Filter.Name, release.Indexer, delay)
+	time.AfterFunc(time.Duration(delay)*time.Second, run)
+}
+
+// runActions stores the matched release and runs the actions of its filter, approved counts the actions that pushed it.
+// It returns false if the release could not be stored.
+func (s *service) runActions(l zerolog.Logger, release *domain.Release, matchedFilters map[int]struct{}, unresolved map[int]struct{}, triedActionClients map[actionClientTypeKey]struct{}) (rejections []string, skipped int, attempted int, approved int, ok bool) {
+
+	// enrich the matched release before it is stored and handed to the actions, failed enrichers don't stop the release.
+	// Skip it when every action would be skipped anyway, enrichers like the torrent file download are not free.
+	if hasRunnableAction(release, matchedFilters, unresolved, triedActionClients) {
+		for _, err := range s.enrichmentSvc.Enrich(context.Background(), release) {
+			s.addEvent(domain.ReleaseEventError, release, "", err.Error())
+		}
+	}
+
+	// save release here to only save those with rejections from actions instead of all releases
+	if release.ID == 0 {
+		release.FilterStatus = domain.ReleaseStatusFilterApproved
+		if err := s.Store(context.Background(), release); err != nil {
+			l.Error().Err(err).Msgf("release.Process: error writing release to database: %+v", release)
+			s.addEvent(domain.ReleaseEventError, release, "", err.Error())
+			return nil, 0, 0, 0, false
+		}
+	}
+
+	// run actions (watchFolder, test, exec, qBittorrent, Deluge, arr etc.)
+	for _, a := range release.Filter.Actions {
+		// only run enabled actions
+		if !a.Enabled {
+			l.Trace().Msgf("release.Process: indexer: %v, filter: %v release: %v action '%v' not enabled, skip", release.Indexer, release.Filter.Name, release.TorrentName, a.Name)
+			continue
+		}
+
+		l.Trace().Msgf("release.Process: indexer: %v, filter: %v release: %v , run action: %v", release.Indexer, release.Filter.Name, release.TorrentName, a.Name)
+
+		// only run actions depending on another filter if that filter matched this release too
+		if a.DependsOnFilterID != 0 {
+			_, matched := matchedFilters[a.DependsOnFilterID]
+			_, cyclic := unresolved[release.FilterID]
+			if !matched || cyclic {
+				l.Debug().Msgf("release.Process: indexer: %v, filter: %v release: %v action '%v' depends on filter %v which did not match, skip", release.Indexer, release.Filter.Name, release.TorrentName, a.Name, a.DependsOnFilterID)
+				s.addEvent(domain.ReleaseEventAction, release, a.Name, fmt.Sprintf("skipped: depends on filter %v which did not match", a.DependsOnFilterID))
+				skipped++
 				continue
 			}
+		}
+
+		// only run actions whose run condition matches the release
+		run, err := a.RunConditionMatches(release)
+		if err != nil {
+			l.Error().Err(err).Msgf("release.Process: indexer: %v, filter: %v release: %v action '%v' could not evaluate run condition", release.Indexer, release.Filter.Name, release.TorrentName, a.Name)
+			s.addEvent(domain.ReleaseEventError, release, a.Name, fmt.Sprintf("run condition: %v", err))
+			skipped++
+			continue
+		}
+
+		if !run {
+			l.Debug().Msgf("release.Process: indexer: %v, filter: %v release: %v action '%v' run condition not met, skip", release.Indexer, release.Filter.Name, release.TorrentName, a.Name)
+			s.addEvent(domain.ReleaseEventAction, release, a.Name, "skipped: run condition not met")
+			skipped++
+			continue
+		}
 
-			rejections, err = s.actionSvc.RunAction(a, *release)
-			if err != nil {
-				l.Error().Stack().Err(err).Msgf("release.Process: error running actions for filter: %v", release.Filter.Name)
+		attempted++
+
+		// keep track of action clients to avoid sending the same thing all over again
+		_, tried := triedActionClients[actionClientTypeKey{Type: a.Type, ClientID: a.ClientID}]
+		if tried {
+			l.Trace().Msgf("release.Process: indexer: %v, filter: %v release: %v action client already tried, skip", release.Indexer, release.Filter.Name, release.TorrentName)
+			continue
+		}
+
+		// spend the token only once an action is about to push the release, it is a no-op after the first time
+		s.spendFreeleechToken(l, release)
+
+		rejections, err = s.actionSvc.RunAction(a, *release)
+		if err != nil {
+			l.Error().Stack().Err(err).Msgf("release.Process: error running actions for filter: %v", release.Filter.Name)
+			s.addEvent(domain.ReleaseEventError, release, a.Name, err.Error())
+			metrics.ActionPushes.Inc(string(a.Type), a.Client.Name, "error")
+
+			s.queueRetry(release, a, err)
+			continue
+		}
+
+		if len(rejections) > 0 {
+			s.addEvent(domain.ReleaseEventAction, release, a.Name, "rejected: "+strings.Join(rejections, ", "))
+			metrics.ActionPushes.Inc(string(a.Type), a.Client.Name, "rejected")
+
+			// if we get a rejection, remember which action client it was from
+			triedActionClients[actionClientTypeKey{Type: a.Type, ClientID: a.ClientID}] = struct{}{}
+
+			// log something and fire events
+			l.Debug().Str("action", a.Name).Str("action_type", string(a.Type)).Msgf("release rejected: %v", strings.Join(rejections, ", "))
+			continue
+		}
+
+		// if no rejections consider action approved, run next
+		s.addEvent(domain.ReleaseEventAction, release, a.Name, "approved")
+		metrics.ActionPushes.Inc(string(a.Type), a.Client.Name, "approved")
+		approved++
+		continue
+	}
+
+	return rejections, skipped, attempted, approved, true
+}
+
+// unclaim gives up the instance claim and the duplicate key of a release none of the actions pushed,
+// so the same announce on another instance or indexer can still be grabbed
+func (s *service) unclaim(l zerolog.Logger, release *domain.Release) {
+	s.instanceSvc.UnclaimRelease(context.Background(), release)
+	s.releaseDedup(l, release)
+}
+
+// hasRunnableAction reports whether any action of the release filter is enabled, has its filter dependency met
+// and uses a client that did not reject the release yet
+func hasRunnableAction(release *domain.Release, matchedFilters map[int]struct{}, unresolved map[int]struct{}, triedActionClients map[actionClientTypeKey]struct{}) bool {
+	for _, a := range release.Filter.Actions {
+		if !a.Enabled {
+			continue
+		}
+
+		if a.DependsOnFilterID != 0 {
+			_, matched := matchedFilters[a.DependsOnFilterID]
+			_, cyclic := unresolved[release.FilterID]
+			if !matched || cyclic {
 				continue
 			}
+		}
+
+		if _, tried := triedActionClients[actionClientTypeKey{Type: a.Type, ClientID: a.ClientID}]; tried {
+			continue
+		}
 
-			if len(rejections) > 0 {
-				// if we get a rejection, remember which action client it was from
-				triedActionClients[actionClientTypeKey{Type: a.Type, ClientID: a.ClientID}] = struct{}{}
+		return true
+	}
 
-				// log something and fire events
-				l.Debug().Str("action", a.Name).Str("action_type", string(a.Type)).Msgf("release rejected: %v", strings.Join(rejections, ", "))
-			}
+	return false
+}
+
+// processPending runs the actions for the preferred release once the smart delay window of a filter closes.
+// If no action pushes it, the next release in order of preference is tried.
+func (s *service) processPending(filter domain.Filter, ranked []pendingRelease) {
+	preferred := ranked[0].release
+
+	l := s.log.With().Str("indexer", preferred.Indexer).Str("filter", filter.Name).Str("release", preferred.TorrentName).Logger()
+
+	// the approved release is actioned later, the others can't be tried as a fallback
+	if filter.RequireApproval {
+		s.skipPending(l, ranked[1:], preferred)
+
+		if !s.isDuplicate(l, preferred) {
+			s.requestApproval(l, preferred)
+		}
+		return
+	}
+
+	// this runs on the smart delay timer, not on a worker
+	if delay := filter.Delay; delay > 0 {
+		l.Debug().Msgf("Delaying processing of '%v' (%v) for %v by %d seconds as specified in the filter", preferred.TorrentName, filter.Name, preferred.Indexer, delay)
+		time.Sleep(time.Duration(delay) * time.Second)
+	}
+
+	for i, candidate := range ranked {
+		release := candidate.release
+		l := s.log.With().Str("indexer", release.Indexer).Str("filter", filter.Name).Str("release", release.TorrentName).Logger()
+
+		if i > 0 {
+			l.Info().Msgf("release.Process: smart delay falling back to '%v' from %v", release.TorrentName, release.Indexer)
+		}
+
+		if s.isDuplicate(l, release) {
+			s.skipPending(l, ranked[i+1:], release)
+			return
+		}
+
+		if _, _, _, approved, _ := s.runActions(l, release, candidate.matchedFilters, candidate.unresolved, map[actionClientTypeKey]struct{}{}); approved > 0 {
+			s.skipPending(l, ranked[i+1:], release)
+			return
+		}
+
+		s.unclaim(l, release)
+	}
+}
+
+// skipPending records that the remaining smart delay candidates were skipped in favour of release
+func (s *service) skipPending(l zerolog.Logger, others []pendingRelease, release *domain.Release) {
+	for _, o := range others {
+		l.Debug().Msgf("release.Process: smart delay preferred '%v' from %v over '%v' from %v", release.TorrentName, release.Indexer, o.release.TorrentName, o.release.Indexer)
+		s.addEvent(domain.ReleaseEventAction, o.release, "", fmt.Sprintf("skipped: smart delay preferred %v from %v", release.TorrentName, release.Indexer))
+	}
+}
+
+// Replay checks stored releases against a filter again and optionally runs its actions for the ones that match
+func (s *service) Replay(ctx context.Context, req domain.ReleaseReplayRequest) ([]domain.ReleaseReplayResult, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	// read-only and standby instances never run actions
+	if req.RunActions && !s.instanceSvc.IsLeader() {
+		return nil, domain.ErrReplayNotActive
+	}
+
+	f, err := s.filterSvc.FindByID(ctx, req.FilterID)
+	if err != nil {
+		s.log.Error().Err(err).Msgf("release.Replay: could not find filter: %v", req.FilterID)
+		return nil, err
+	}
 
-			// if no rejections consider action approved, run next
+	results := make([]domain.ReleaseReplayResult, 0, len(req.ReleaseIDs))
+	queued := make([]*domain.Release, 0)
+
+	for _, id := range req.ReleaseIDs {
+		result := domain.ReleaseReplayResult{ReleaseID: id, Rejections: []string{}, Skipped: []string{}}
+
+		stored, err := s.repo.FindByID(ctx, id)
+		if err != nil {
+			result.Error = err.Error()
+			results = append(results, result)
 			continue
 		}
 
-		// if we have rejections from arr, continue to next filter
-		if len(rejections) > 0 {
+		release := stored.Replay()
+		release.Filter = f
+		release.FilterName = f.Name
+		release.FilterID = f.ID
+
+		result.TorrentName = release.TorrentName
+		result.Indexer = release.Indexer
+
+		l := s.log.With().Str("indexer", release.Indexer).Str("filter", f.Name).Str("release", release.TorrentName).Logger()
+
+		match, skipped, err := s.replayCheck(ctx, f, release, req.RunActions)
+		if err != nil {
+			l.Error().Err(err).Msg("release.Replay: error checking filter")
+			result.Error = err.Error()
+			results = append(results, result)
 			continue
 		}
 
-		// all actions run, decide to stop or continue here
-		break
+		// announces are only checked against filters with the indexer enabled
+		if !filterHasIndexer(f, release.Indexer) {
+			release.AddRejectionF("indexer not enabled in filter: %v", release.Indexer)
+			match = false
+		}
+
+		result.Match = match
+		result.Rejections = append(result.Rejections, release.Rejections...)
+		result.Skipped = append(result.Skipped, skipped...)
+
+		l.Debug().Msgf("release.Replay: match: %v rejections: %v", match, release.RejectionsString())
+
+		if match && req.RunActions {
+			if release.TorrentURL == "" {
+				result.Error = "no torrent url stored for release, actions can't be run"
+				results = append(results, result)
+				continue
+			}
+
+			result.ActionsQueued = true
+			queued = append(queued, release)
+		}
+
+		results = append(results, result)
+	}
+
+	if len(queued) > 0 {
+		// actions run in the background after the filter delay, don't hold up the request
+		go s.replayActions(f, queued)
+	}
+
+	return results, nil
+}
+
+// replayCheck checks the replayed release against the filter. Without actions to run it is a dry run,
+// the torrent file is not downloaded and the external script and webhook are not called, those checks are returned as skipped.
+func (s *service) replayCheck(ctx context.Context, f *domain.Filter, release *domain.Release, runActions bool) (bool, []string, error) {
+	if runActions {
+		match, err := s.filterSvc.CheckFilter(*f, release)
+		return match, nil, err
+	}
+
+	result, err := s.filterSvc.DryRunFilter(ctx, *f, release)
+	if err != nil {
+		return false, nil, err
 	}
 
-	return
+	release.Rejections = result.Rejections
+
+	return result.Match, result.Skipped, nil
 }
 
-func (s *service) ProcessMultiple(releases []*domain.Release) {
+// replayActions runs the actions of the replayed releases after the filter delay.
+// Releases are run one at a time, also across replays, so a large replay doesn't push everything at once.
+func (s *service) replayActions(f *domain.Filter, releases []*domain.Release) {
+	if f.Delay > 0 {
+		s.log.Debug().Msgf("Delaying replay of %d releases for filter '%v' by %d seconds as specified in the filter", len(releases), f.Name, f.Delay)
+		time.Sleep(time.Duration(f.Delay) * time.Second)
+	}
+
+	s.replayMu.Lock()
+	defer s.replayMu.Unlock()
+
+	for _, release := range releases {
+		l := s.log.With().Str("indexer", release.Indexer).Str("filter", f.Name).Str("release", release.TorrentName).Logger()
+
+		s.runActions(l, release, map[int]struct{}{}, map[int]struct{}{}, map[actionClientTypeKey]struct{}{})
+	}
+}
+
+func filterHasIndexer(f *domain.Filter, indexer string) bool {
+	for _, i := range f.Indexers {
+		if i.Identifier == indexer {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Enqueue processes the release on a worker of its indexer, the release is dropped and ErrQueueFull returned if the indexer queue is full
+func (s *service) Enqueue(release *domain.Release) error {
+	if release == nil {
+		return nil
+	}
+
+	if !s.workers.enqueue(release) {
+		s.dropped(release)
+		return ErrQueueFull
+	}
+
+	return nil
+}
+
+// EnqueueWait is Enqueue for callers that can wait for room in the indexer queue, like feeds and webhooks.
+// The release is dropped and ErrQueueFull returned if there is no room before ctx is done.
+func (s *service) EnqueueWait(ctx context.Context, release *domain.Release) error {
+	if release == nil {
+		return nil
+	}
+
+	if err := s.workers.enqueueWait(ctx, release); err != nil {
+		s.dropped(release)
+		return ErrQueueFull
+	}
+
+	return nil
+}
+
+func (s *service) dropped(release *domain.Release) {
+	s.log.Warn().Msgf("release queue for indexer %v is full, dropping release: %v", release.Indexer, release.TorrentName)
+	s.addEvent(domain.ReleaseEventError, release, "", "dropped: indexer queue is full")
+}
+
+// ProcessMultiple queues the releases of a feed in order, waiting for room in the indexer queue.
+// It returns the number of releases queued, the ones after them were not.
+func (s *service) ProcessMultiple(ctx context.Context, releases []*domain.Release) (int, error) {
 	s.log.Debug().Msgf("process (%v) new releases from feed", len(releases))
 
-	for _, rls := range releases {
-		if rls == nil {
-			continue
+	for i, rls := range releases {
+		if err := s.EnqueueWait(ctx, rls); err != nil {
+			return i, err
 		}
-		s.Process(rls)
 	}
+
+	return len(releases), nil
 }
@@ -0,0 +1,981 @@
+package release
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/autobrr/autobrr/internal/action"
+	"github.com/autobrr/autobrr/internal/blocklist"
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/internal/enrichment"
+	"github.com/autobrr/autobrr/internal/filter"
+	"github.com/autobrr/autobrr/internal/instance"
+	"github.com/autobrr/autobrr/internal/logger"
+	"github.com/autobrr/autobrr/internal/notification"
+	"github.com/autobrr/autobrr/pkg/errors"
+
+	"github.com/asaskevich/EventBus"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockReleaseRepo struct {
+	domain.ReleaseRepo
+
+	releases map[int64]*domain.Release
+	pruned   []domain.ReleaseRetention
+	dedup    map[string]*domain.ReleaseDedup
+	history  []*domain.Release
+	queries  []domain.ReleaseQueryParams
+}
+
+// Find pages history by cursor like the database, history is ordered by id descending
+func (r *mockReleaseRepo) Find(ctx context.Context, params domain.ReleaseQueryParams) ([]*domain.Release, int64, int64, error) {
+	r.queries = append(r.queries, params)
+
+	var page []*domain.Release
+	for _, release := range r.history {
+		if params.Cursor > 0 && release.ID >= int64(params.Cursor) {
+			continue
+		}
+		if len(page) == int(params.Limit) {
+			break
+		}
+		page = append(page, release)
+	}
+
+	var nextCursor int64
+	if len(page) == int(params.Limit) {
+		nextCursor = page[len(page)-1].ID
+	}
+
+	return page, nextCursor, int64(len(r.history)), nil
+}
+
+func (r *mockReleaseRepo) FindByID(ctx context.Context, id int64) (*domain.Release, error) {
+	release, ok := r.releases[id]
+	if !ok {
+		return nil, errors.New("release not found: %v", id)
+	}
+
+	return release, nil
+}
+
+func (r *mockReleaseRepo) Store(ctx context.Context, release *domain.Release) (*domain.Release, error) {
+	release.ID = 1
+	return release, nil
+}
+
+func (r *mockReleaseRepo) UpdateFilterStatus(ctx context.Context, id int64, status domain.ReleaseFilterStatus) error {
+	if release, ok := r.releases[id]; ok {
+		release.FilterStatus = status
+	}
+	return nil
+}
+
+func (r *mockReleaseRepo) Prune(ctx context.Context, retention domain.ReleaseRetention) (int64, error) {
+	r.pruned = append(r.pruned, retention)
+	return 3, nil
+}
+
+func (r *mockReleaseRepo) ClaimDedup(ctx context.Context, key string, since time.Time, release *domain.Release) (*domain.ReleaseDedup, error) {
+	if r.dedup == nil {
+		r.dedup = map[string]*domain.ReleaseDedup{}
+	}
+
+	if dup, ok := r.dedup[key]; ok && !dup.CreatedAt.Before(since) && !dup.SameRelease(release) {
+		return dup, nil
+	}
+
+	r.dedup[key] = &domain.ReleaseDedup{Key: key, Indexer: release.Indexer, TorrentName: release.TorrentName, CreatedAt: time.Now()}
+
+	return nil, nil
+}
+
+func (r *mockReleaseRepo) DeleteDedupKey(ctx context.Context, key string, release *domain.Release) error {
+	if dup, ok := r.dedup[key]; ok && dup.SameRelease(release) {
+		delete(r.dedup, key)
+	}
+
+	return nil
+}
+
+type mockFilterService struct {
+	filter.Service
+
+	filters    []domain.Filter
+	matches    map[int]bool
+	rejections map[int][]string
+	checked    []int
+	dryRun     []int
+}
+
+func (s *mockFilterService) FindByID(ctx context.Context, filterID int) (*domain.Filter, error) {
+	for i := range s.filters {
+		if s.filters[i].ID == filterID {
+			return &s.filters[i], nil
+		}
+	}
+
+	return nil, errors.New("filter not found: %v", filterID)
+}
+
+func (s *mockFilterService) FindByIndexerIdentifier(indexer string) ([]domain.Filter, error) {
+	return s.filters, nil
+}
+
+func (s *mockFilterService) CheckFilter(f domain.Filter, release *domain.Release) (bool, error) {
+	s.checked = append(s.checked, f.ID)
+
+	if !s.matches[f.ID] {
+		return false, nil
+	}
+
+	release.Filter.Actions = f.Actions
+
+	return true, nil
+}
+
+func (s *mockFilterService) DryRunFilter(ctx context.Context, f domain.Filter, release *domain.Release) (domain.FilterTestResult, error) {
+	s.dryRun = append(s.dryRun, f.ID)
+
+	return domain.FilterTestResult{FilterID: f.ID, FilterName: f.Name, Match: s.matches[f.ID], Rejections: []string{}, Skipped: f.DryRunSkippedChecks(release)}, nil
+}
+
+func (s *mockFilterService) RecordRejections(filterID int, rejections []string) {
+	if s.rejections == nil {
+		s.rejections = map[int][]string{}
+	}
+	s.rejections[filterID] = append(s.rejections[filterID], rejections...)
+}
+
+type mockActionService struct {
+	action.Service
+
+	deps       domain.FilterDependencies
+	rejections map[string][]string
+	ran        []string
+}
+
+func (s *mockActionService) FindFilterDependencies(ctx context.Context) (domain.FilterDependencies, error) {
+	return s.deps, nil
+}
+
+func (s *mockActionService) RunAction(a *domain.Action, release domain.Release) ([]string, error) {
+	s.ran = append(s.ran, a.Name)
+	return s.rejections[a.Name], nil
+}
+
+type mockBlocklistService struct {
+	blocklist.Service
+
+	rules []*domain.AnnounceBlocklistRule
+	hits  []int
+}
+
+func (s *mockBlocklistService) Check(release *domain.Release) (*domain.AnnounceBlocklistRule, bool) {
+	for _, rule := range s.rules {
+		if rule.Matches(release) {
+			return rule, true
+		}
+	}
+	return nil, false
+}
+
+func (s *mockBlocklistService) RecordHit(rule *domain.AnnounceBlocklistRule) {
+	s.hits = append(s.hits, rule.ID)
+}
+
+type mockInstanceService struct {
+	instance.Service
+
+	standby   bool
+	unclaimed []string
+}
+
+func (s *mockInstanceService) Name() string {
+	return "mock"
+}
+
+func (s *mockInstanceService) IsLeader() bool {
+	return !s.standby
+}
+
+func (s *mockInstanceService) ClaimRelease(ctx context.Context, release *domain.Release) bool {
+	return !s.standby
+}
+
+func (s *mockInstanceService) UnclaimRelease(ctx context.Context, release *domain.Release) {
+	s.unclaimed = append(s.unclaimed, release.TorrentName)
+}
+
+func Test_service_Process_actionDependencies(t *testing.T) {
+	grab := domain.Filter{ID: 1, Name: "grab", Actions: []*domain.Action{
+		{Name: "grab-qbit", Type: domain.ActionTypeQbittorrent, Enabled: true, ClientID: 1},
+	}}
+	notify := domain.Filter{ID: 2, Name: "notify", Actions: []*domain.Action{
+		{Name: "notify-webhook", Type: domain.ActionTypeWebhook, Enabled: true, DependsOnFilterID: 1},
+	}}
+	catchAll := domain.Filter{ID: 3, Name: "catch-all", Actions: []*domain.Action{
+		{Name: "catch-all-qbit", Type: domain.ActionTypeQbittorrent, Enabled: true, ClientID: 2},
+	}}
+	notifyCyclic := domain.Filter{ID: 2, Name: "notify", Actions: []*domain.Action{
+		{Name: "notify-webhook", Type: domain.ActionTypeWebhook, Enabled: true, DependsOnFilterID: 4},
+	}}
+	cyclic := domain.Filter{ID: 4, Name: "cyclic", Actions: []*domain.Action{
+		{Name: "cyclic-webhook", Type: domain.ActionTypeWebhook, Enabled: true, DependsOnFilterID: 2},
+	}}
+	mixed := domain.Filter{ID: 5, Name: "mixed", Actions: []*domain.Action{
+		{Name: "mixed-qbit", Type: domain.ActionTypeQbittorrent, Enabled: true, ClientID: 3},
+		{Name: "mixed-webhook", Type: domain.ActionTypeWebhook, Enabled: true, DependsOnFilterID: 1},
+	}}
+
+	tests := []struct {
+		name    string
+		filters []domain.Filter
+		matches map[int]bool
+		deps    domain.FilterDependencies
+		want    []string
+	}{
+		{
+			name:    "dependent_fires",
+			filters: []domain.Filter{grab, notify},
+			matches: map[int]bool{1: true, 2: true},
+			deps:    domain.FilterDependencies{2: {1}},
+			want:    []string{"grab-qbit", "notify-webhook"},
+		},
+		{
+			name:    "dependent_skips",
+			filters: []domain.Filter{grab, notify, catchAll},
+			matches: map[int]bool{1: false, 2: true, 3: true},
+			deps:    domain.FilterDependencies{2: {1}},
+			want:    []string{"catch-all-qbit"},
+		},
+		{
+			name:    "dependency_evaluated_first",
+			filters: []domain.Filter{notify, grab},
+			matches: map[int]bool{1: true, 2: true},
+			deps:    domain.FilterDependencies{2: {1}},
+			want:    []string{"grab-qbit", "notify-webhook"},
+		},
+		{
+			name:    "independent_filters_stop_after_handled",
+			filters: []domain.Filter{grab, catchAll, notify},
+			matches: map[int]bool{1: true, 2: true, 3: true},
+			deps:    domain.FilterDependencies{2: {1}},
+			want:    []string{"grab-qbit", "notify-webhook"},
+		},
+		{
+			name:    "dependency_evaluated_after_handled",
+			filters: []domain.Filter{catchAll, grab, notify},
+			matches: map[int]bool{1: true, 2: true, 3: true},
+			deps:    domain.FilterDependencies{2: {1}},
+			want:    []string{"catch-all-qbit", "notify-webhook"},
+		},
+		{
+			name:    "dependency_after_handled_not_matched",
+			filters: []domain.Filter{catchAll, grab, notify},
+			matches: map[int]bool{1: false, 2: true, 3: true},
+			deps:    domain.FilterDependencies{2: {1}},
+			want:    []string{"catch-all-qbit"},
+		},
+		{
+			name:    "mixed_runs_only_dependent_after_handled",
+			filters: []domain.Filter{grab, mixed},
+			matches: map[int]bool{1: true, 5: true},
+			deps:    domain.FilterDependencies{5: {1}},
+			want:    []string{"grab-qbit", "mixed-webhook"},
+		},
+		{
+			name:    "mixed_grabs_when_not_handled",
+			filters: []domain.Filter{grab, mixed},
+			matches: map[int]bool{1: false, 5: true},
+			deps:    domain.FilterDependencies{5: {1}},
+			want:    []string{"mixed-qbit"},
+		},
+		{
+			name:    "cycle_skips_dependent_actions",
+			filters: []domain.Filter{grab, notifyCyclic, cyclic},
+			matches: map[int]bool{1: true, 2: true, 4: true},
+			deps:    domain.FilterDependencies{2: {4}, 4: {2}},
+			want:    []string{"grab-qbit"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actionSvc := &mockActionService{deps: tt.deps}
+			s := NewService(logger.Mock(), &domain.Config{}, &mockReleaseRepo{}, nil, nil, actionSvc, &mockFilterService{filters: tt.filters, matches: tt.matches}, &mockBlocklistService{}, &mockInstanceService{}, enrichment.NewService(logger.Mock()), nil, EventBus.New())
+
+			s.Process(&domain.Release{Indexer: "mock", TorrentName: "That.Movie.2022.1080p.BluRay.x264-GROUP"})
+
+			assert.Equal(t, tt.want, actionSvc.ran)
+		})
+	}
+}
+
+func Test_service_Process_standby(t *testing.T) {
+	grab := domain.Filter{ID: 1, Name: "grab", Actions: []*domain.Action{
+		{Name: "grab-qbit", Type: domain.ActionTypeQbittorrent, Enabled: true, ClientID: 1},
+	}}
+
+	actionSvc := &mockActionService{}
+	s := NewService(logger.Mock(), &domain.Config{}, &mockReleaseRepo{}, nil, nil, actionSvc, &mockFilterService{filters: []domain.Filter{grab}, matches: map[int]bool{1: true}}, &mockBlocklistService{}, &mockInstanceService{standby: true}, enrichment.NewService(logger.Mock()), nil, EventBus.New())
+
+	s.Process(&domain.Release{Indexer: "mock", TorrentName: "That.Movie.2022.1080p.BluRay.x264-GROUP"})
+
+	assert.Empty(t, actionSvc.ran)
+}
+
+func Test_service_Process_unclaim(t *testing.T) {
+	grab := domain.Filter{ID: 1, Name: "grab", Actions: []*domain.Action{
+		{Name: "grab-sonarr", Type: domain.ActionTypeSonarr, Enabled: true, ClientID: 1},
+	}}
+
+	tests := []struct {
+		name       string
+		rejections map[string][]string
+		want       []string
+	}{
+		{name: "approved", want: nil},
+		{name: "rejected", rejections: map[string][]string{"grab-sonarr": {"unknown series"}}, want: []string{"That.Show.S01E01.1080p.WEB-DL-GROUP"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			instanceSvc := &mockInstanceService{}
+			actionSvc := &mockActionService{rejections: tt.rejections}
+			s := NewService(logger.Mock(), &domain.Config{}, &mockReleaseRepo{}, nil, nil, actionSvc, &mockFilterService{filters: []domain.Filter{grab}, matches: map[int]bool{1: true}}, &mockBlocklistService{}, instanceSvc, enrichment.NewService(logger.Mock()), nil, EventBus.New())
+
+			s.Process(&domain.Release{Indexer: "mock", TorrentName: "That.Show.S01E01.1080p.WEB-DL-GROUP"})
+
+			assert.Equal(t, tt.want, instanceSvc.unclaimed)
+		})
+	}
+}
+
+func Test_service_Process_blocklist(t *testing.T) {
+	grab := domain.Filter{ID: 1, Name: "grab", Actions: []*domain.Action{
+		{Name: "grab-qbit", Type: domain.ActionTypeQbittorrent, Enabled: true, ClientID: 1},
+	}}
+
+	tests := []struct {
+		name     string
+		rule     domain.AnnounceBlocklistRule
+		standby  bool
+		wantRan  []string
+		wantHits []int
+	}{
+		{name: "group", rule: domain.AnnounceBlocklistRule{ID: 1, Name: "bad group", Enabled: true, Type: domain.AnnounceBlocklistTypeGroup, Value: "group"}, wantHits: []int{1}},
+		{name: "regex", rule: domain.AnnounceBlocklistRule{ID: 2, Name: "spam", Enabled: true, Type: domain.AnnounceBlocklistTypeTitleRegex, Value: `\.bluray\.`}, wantHits: []int{2}},
+		{name: "other_indexer", rule: domain.AnnounceBlocklistRule{ID: 3, Name: "other", Enabled: true, Type: domain.AnnounceBlocklistTypeGroup, Value: "group", Indexer: "other"}, wantRan: []string{"grab-qbit"}},
+		{name: "disabled", rule: domain.AnnounceBlocklistRule{ID: 4, Name: "disabled", Type: domain.AnnounceBlocklistTypeGroup, Value: "group"}, wantRan: []string{"grab-qbit"}},
+		{name: "standby_not_counted", rule: domain.AnnounceBlocklistRule{ID: 5, Name: "bad group", Enabled: true, Type: domain.AnnounceBlocklistTypeGroup, Value: "group"}, standby: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.NoError(t, tt.rule.Validate())
+
+			actionSvc := &mockActionService{}
+			blocklistSvc := &mockBlocklistService{rules: []*domain.AnnounceBlocklistRule{&tt.rule}}
+			s := NewService(logger.Mock(), &domain.Config{}, &mockReleaseRepo{}, nil, nil, actionSvc, &mockFilterService{filters: []domain.Filter{grab}, matches: map[int]bool{1: true}}, blocklistSvc, &mockInstanceService{standby: tt.standby}, enrichment.NewService(logger.Mock()), nil, EventBus.New())
+
+			release := &domain.Release{Indexer: "mock", TorrentName: "That.Movie.2022.1080p.BluRay.x264-GROUP"}
+			release.ParseString(release.TorrentName)
+
+			s.Process(release)
+
+			assert.Equal(t, tt.wantRan, actionSvc.ran)
+			assert.Equal(t, tt.wantHits, blocklistSvc.hits)
+		})
+	}
+}
+
+func Test_service_Process_dedup(t *testing.T) {
+	grab := domain.Filter{ID: 1, Name: "grab", Actions: []*domain.Action{
+		{Name: "grab-qbit", Type: domain.ActionTypeQbittorrent, Enabled: true, ClientID: 1},
+	}}
+	other := domain.Filter{ID: 2, Name: "other", DedupKey: domain.FilterDedupKeyDisabled, Actions: []*domain.Action{
+		{Name: "other-qbit", Type: domain.ActionTypeQbittorrent, Enabled: true, ClientID: 2},
+	}}
+
+	tests := []struct {
+		name       string
+		filters    []domain.Filter
+		config     domain.Config
+		rejections map[string][]string
+		want       []string
+	}{
+		{
+			name:    "disabled",
+			filters: []domain.Filter{grab},
+			want:    []string{"grab-qbit", "grab-qbit", "grab-qbit"},
+		},
+		{
+			name:       "rejected_not_recorded",
+			filters:    []domain.Filter{grab},
+			config:     domain.Config{ReleaseDedupKey: string(domain.FilterDedupKeyTitle), ReleaseDedupWindow: 24},
+			rejections: map[string][]string{"grab-qbit": {"not enough space"}},
+			want:       []string{"grab-qbit", "grab-qbit", "grab-qbit"},
+		},
+		{
+			name:    "global",
+			filters: []domain.Filter{grab},
+			config:  domain.Config{ReleaseDedupKey: string(domain.FilterDedupKeyTitle), ReleaseDedupWindow: 24},
+			want:    []string{"grab-qbit"},
+		},
+		{
+			name:    "title_group",
+			filters: []domain.Filter{grab},
+			config:  domain.Config{ReleaseDedupKey: string(domain.FilterDedupKeyTitleGroup), ReleaseDedupWindow: 24},
+			want:    []string{"grab-qbit", "grab-qbit"},
+		},
+		{
+			name:    "filter_disabled",
+			filters: []domain.Filter{other},
+			config:  domain.Config{ReleaseDedupKey: string(domain.FilterDedupKeyTitle), ReleaseDedupWindow: 24},
+			want:    []string{"other-qbit", "other-qbit", "other-qbit"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			matches := map[int]bool{}
+			for _, f := range tt.filters {
+				matches[f.ID] = true
+			}
+
+			actionSvc := &mockActionService{rejections: tt.rejections}
+			s := NewService(logger.Mock(), &tt.config, &mockReleaseRepo{}, nil, nil, actionSvc, &mockFilterService{filters: tt.filters, matches: matches}, &mockBlocklistService{}, &mockInstanceService{}, enrichment.NewService(logger.Mock()), nil, EventBus.New())
+
+			s.Process(&domain.Release{Indexer: "one", TorrentName: "That.Movie.2022.1080p.BluRay.x264-GROUP", Title: "That Movie", Year: 2022, Group: "GROUP"})
+			s.Process(&domain.Release{Indexer: "two", TorrentName: "That Movie 2022 1080p BluRay x264-GROUP", Title: "That Movie", Year: 2022, Group: "GROUP"})
+			s.Process(&domain.Release{Indexer: "three", TorrentName: "That.Movie.2022.1080p.BluRay.x264-OTHER", Title: "That Movie", Year: 2022, Group: "OTHER"})
+
+			assert.Equal(t, tt.want, actionSvc.ran)
+		})
+	}
+}
+
+func Test_service_Process_runCondition(t *testing.T) {
+	routed := domain.Filter{ID: 1, Name: "routed", Actions: []*domain.Action{
+		{Name: "big-qbit", Type: domain.ActionTypeQbittorrent, Enabled: true, ClientID: 1, RunCondition: `release.Size > 10GB`},
+		{Name: "small-qbit", Type: domain.ActionTypeQbittorrent, Enabled: true, ClientID: 2, RunCondition: `release.Size <= 10GB && "internal" in release.Tags`},
+		{Name: "broken-qbit", Type: domain.ActionTypeQbittorrent, Enabled: true, ClientID: 3, RunCondition: `release.Size > "big"`},
+	}}
+	fallback := domain.Filter{ID: 2, Name: "fallback", Actions: []*domain.Action{
+		{Name: "fallback-qbit", Type: domain.ActionTypeQbittorrent, Enabled: true, ClientID: 4},
+	}}
+
+	tests := []struct {
+		name    string
+		release domain.Release
+		want    []string
+	}{
+		{name: "big", release: domain.Release{Size: 20_000_000_000}, want: []string{"big-qbit"}},
+		{name: "small_internal", release: domain.Release{Size: 5_000_000_000, Tags: []string{"Internal"}}, want: []string{"small-qbit"}},
+		{name: "no_condition_met", release: domain.Release{Size: 5_000_000_000}, want: []string{"fallback-qbit"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actionSvc := &mockActionService{}
+			s := NewService(logger.Mock(), &domain.Config{}, &mockReleaseRepo{}, nil, nil, actionSvc, &mockFilterService{filters: []domain.Filter{routed, fallback}, matches: map[int]bool{1: true, 2: true}}, &mockBlocklistService{}, &mockInstanceService{}, enrichment.NewService(logger.Mock()), nil, EventBus.New())
+
+			release := tt.release
+			release.Indexer = "mock"
+			release.TorrentName = "That.Movie.2022.1080p.BluRay.x264-GROUP"
+
+			s.Process(&release)
+
+			assert.Equal(t, tt.want, actionSvc.ran)
+		})
+	}
+}
+
+type countingEnricher struct {
+	enriched []string
+}
+
+func (e *countingEnricher) Name() string { return "counting" }
+
+func (e *countingEnricher) Enabled(release *domain.Release) bool { return true }
+
+func (e *countingEnricher) Enrich(ctx context.Context, release *domain.Release) error {
+	e.enriched = append(e.enriched, release.Filter.Name)
+	return nil
+}
+
+func Test_service_Process_enrichment(t *testing.T) {
+	grab := domain.Filter{ID: 1, Name: "grab", Actions: []*domain.Action{
+		{Name: "grab-qbit", Type: domain.ActionTypeQbittorrent, Enabled: true, ClientID: 1},
+	}}
+	notify := domain.Filter{ID: 2, Name: "notify", Actions: []*domain.Action{
+		{Name: "notify-webhook", Type: domain.ActionTypeWebhook, Enabled: true, DependsOnFilterID: 1},
+	}}
+
+	tests := []struct {
+		name    string
+		matches map[int]bool
+		want    []string
+	}{
+		{name: "runnable_actions", matches: map[int]bool{1: true, 2: true}, want: []string{"grab", "notify"}},
+		{name: "all_actions_skipped", matches: map[int]bool{1: false, 2: true}, want: nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			enricher := &countingEnricher{}
+			actionSvc := &mockActionService{deps: domain.FilterDependencies{2: {1}}}
+			s := NewService(logger.Mock(), &domain.Config{}, &mockReleaseRepo{}, nil, nil, actionSvc, &mockFilterService{filters: []domain.Filter{grab, notify}, matches: tt.matches}, &mockBlocklistService{}, &mockInstanceService{}, enrichment.NewService(logger.Mock(), enricher), nil, EventBus.New())
+
+			s.Process(&domain.Release{Indexer: "mock", TorrentName: "That.Movie.2022.1080p.BluRay.x264-GROUP"})
+
+			assert.Equal(t, tt.want, enricher.enriched)
+		})
+	}
+}
+
+type indexerActionService struct {
+	mockActionService
+
+	reject map[string]bool
+	pushed []string
+}
+
+func (s *indexerActionService) RunAction(a *domain.Action, release domain.Release) ([]string, error) {
+	if s.reject[release.Indexer] {
+		return []string{"rejected by " + release.Indexer}, nil
+	}
+
+	s.pushed = append(s.pushed, release.Indexer)
+	return nil, nil
+}
+
+func Test_service_processPending(t *testing.T) {
+	grab := domain.Filter{ID: 1, Name: "grab", SmartDelay: 60, Actions: []*domain.Action{
+		{Name: "grab-qbit", Type: domain.ActionTypeQbittorrent, Enabled: true, ClientID: 1},
+	}}
+
+	tests := []struct {
+		name   string
+		reject map[string]bool
+		want   []string
+	}{
+		{name: "preferred", want: []string{"alpha"}},
+		{name: "fallback", reject: map[string]bool{"alpha": true}, want: []string{"beta"}},
+		{name: "all_rejected", reject: map[string]bool{"alpha": true, "beta": true, "gamma": true}, want: nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actionSvc := &indexerActionService{reject: tt.reject}
+			s := NewService(logger.Mock(), &domain.Config{}, &mockReleaseRepo{}, nil, nil, actionSvc, &mockFilterService{}, &mockBlocklistService{}, &mockInstanceService{}, enrichment.NewService(logger.Mock()), nil, EventBus.New()).(*service)
+
+			var ranked []pendingRelease
+			for _, indexer := range []string{"alpha", "beta", "gamma"} {
+				ranked = append(ranked, pendingRelease{release: &domain.Release{Indexer: indexer, TorrentName: "That.Show.S01E01.1080p.WEB-DL-GROUP", Filter: &grab, FilterID: grab.ID}})
+			}
+
+			s.processPending(grab, ranked)
+
+			assert.Equal(t, tt.want, actionSvc.pushed)
+		})
+	}
+}
+
+func Test_service_Process_freeleechToken(t *testing.T) {
+	var spent int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		spent++
+	}))
+	defer srv.Close()
+
+	tests := []struct {
+		name         string
+		runCondition string
+		want         int
+	}{
+		{name: "pushed", want: 1},
+		{name: "no_action_runs", runCondition: `release.Size > 100GB`, want: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spent = 0
+
+			grab := domain.Filter{ID: 1, Name: "grab", Actions: []*domain.Action{
+				{Name: "grab-qbit", Type: domain.ActionTypeQbittorrent, Enabled: true, ClientID: 1, RunCondition: tt.runCondition},
+				{Name: "grab-deluge", Type: domain.ActionTypeDelugeV2, Enabled: true, ClientID: 2, RunCondition: tt.runCondition},
+			}}
+
+			s := NewService(logger.Mock(), &domain.Config{}, &mockReleaseRepo{}, nil, nil, &mockActionService{}, &mockFilterService{filters: []domain.Filter{grab}, matches: map[int]bool{1: true}}, &mockBlocklistService{}, &mockInstanceService{}, enrichment.NewService(logger.Mock()), nil, EventBus.New())
+
+			s.Process(&domain.Release{Indexer: "mock", TorrentName: "That.Movie.2022.1080p.BluRay.x264-GROUP", Size: 10_000_000_000, FreeleechToken: &domain.ReleaseFreeleechToken{Method: http.MethodPost, URL: srv.URL}})
+
+			assert.Equal(t, tt.want, spent)
+		})
+	}
+}
+
+func Test_service_Replay(t *testing.T) {
+	f := domain.Filter{ID: 1, Name: "movies", Indexers: []domain.Indexer{{Identifier: "mock"}}}
+
+	repo := &mockReleaseRepo{releases: map[int64]*domain.Release{
+		1: {ID: 1, Indexer: "mock", TorrentName: "That.Movie.2022.1080p.BluRay.x264-GROUP", Size: 1000},
+		2: {ID: 2, Indexer: "other", TorrentName: "That.Movie.2022.1080p.BluRay.x264-GROUP"},
+	}}
+
+	s := NewService(logger.Mock(), &domain.Config{}, repo, nil, nil, &mockActionService{}, &mockFilterService{filters: []domain.Filter{f}, matches: map[int]bool{1: true}}, &mockBlocklistService{}, &mockInstanceService{}, enrichment.NewService(logger.Mock()), nil, EventBus.New())
+
+	results, err := s.Replay(context.Background(), domain.ReleaseReplayRequest{FilterID: 1, ReleaseIDs: []int64{1, 2, 3}})
+	assert.NoError(t, err)
+	assert.Len(t, results, 3)
+
+	assert.True(t, results[0].Match)
+	assert.Equal(t, "That.Movie.2022.1080p.BluRay.x264-GROUP", results[0].TorrentName)
+	assert.Empty(t, results[0].Rejections)
+
+	assert.False(t, results[1].Match)
+	assert.Equal(t, []string{"indexer not enabled in filter: other"}, results[1].Rejections)
+
+	assert.False(t, results[2].Match)
+	assert.NotEmpty(t, results[2].Error)
+
+	_, err = s.Replay(context.Background(), domain.ReleaseReplayRequest{FilterID: 1})
+	assert.Error(t, err)
+
+	// without actions to run, the external checks are skipped and reported
+	filterSvc := &mockFilterService{filters: []domain.Filter{{ID: 1, Name: "movies", Indexers: f.Indexers, ExternalScriptEnabled: true, ExternalScriptCmd: "check.sh", ExternalWebhookEnabled: true, ExternalWebhookHost: "http://localhost/check"}}, matches: map[int]bool{1: true}}
+	s = NewService(logger.Mock(), &domain.Config{}, repo, nil, nil, &mockActionService{}, filterSvc, &mockBlocklistService{}, &mockInstanceService{}, enrichment.NewService(logger.Mock()), nil, EventBus.New())
+
+	results, err = s.Replay(context.Background(), domain.ReleaseReplayRequest{FilterID: 1, ReleaseIDs: []int64{1}})
+	assert.NoError(t, err)
+	assert.True(t, results[0].Match)
+	assert.Equal(t, []string{"external script", "external webhook"}, results[0].Skipped)
+	assert.Empty(t, filterSvc.checked)
+	assert.Equal(t, []int{1}, filterSvc.dryRun)
+
+	// read-only and standby instances don't run actions
+	s = NewService(logger.Mock(), &domain.Config{}, repo, nil, nil, &mockActionService{}, &mockFilterService{filters: []domain.Filter{f}, matches: map[int]bool{1: true}}, &mockBlocklistService{}, &mockInstanceService{standby: true}, enrichment.NewService(logger.Mock()), nil, EventBus.New())
+
+	_, err = s.Replay(context.Background(), domain.ReleaseReplayRequest{FilterID: 1, ReleaseIDs: []int64{1}, RunActions: true})
+	assert.ErrorIs(t, err, domain.ErrReplayNotActive)
+
+	results, err = s.Replay(context.Background(), domain.ReleaseReplayRequest{FilterID: 1, ReleaseIDs: []int64{1}})
+	assert.NoError(t, err)
+	assert.True(t, results[0].Match)
+}
+
+type mockActionRetryRepo struct {
+	domain.ActionRetryRepo
+
+	retries map[int]*domain.ActionRetry
+}
+
+func (r *mockActionRetryRepo) Store(ctx context.Context, retry *domain.ActionRetry) error {
+	retry.ID = len(r.retries) + 1
+	r.retries[retry.ID] = retry
+	return nil
+}
+
+func (r *mockActionRetryRepo) Update(ctx context.Context, retry *domain.ActionRetry) error {
+	r.retries[retry.ID] = retry
+	return nil
+}
+
+func (r *mockActionRetryRepo) FindByID(ctx context.Context, id int) (*domain.ActionRetry, error) {
+	retry, ok := r.retries[id]
+	if !ok {
+		return nil, errors.New("action retry not found: %v", id)
+	}
+
+	return retry, nil
+}
+
+func (r *mockActionRetryRepo) Delete(ctx context.Context, id int) error {
+	delete(r.retries, id)
+	return nil
+}
+
+type failingActionService struct {
+	mockActionService
+
+	failures int
+}
+
+func (s *failingActionService) RunAction(a *domain.Action, release domain.Release) ([]string, error) {
+	s.ran = append(s.ran, a.Name)
+
+	if s.failures > 0 {
+		s.failures--
+		return nil, errors.New("client unreachable")
+	}
+
+	return nil, nil
+}
+
+func Test_service_Retry(t *testing.T) {
+	grab := domain.Filter{ID: 1, Name: "grab", Actions: []*domain.Action{
+		{ID: 5, Name: "grab-qbit", Type: domain.ActionTypeQbittorrent, Enabled: true, ClientID: 1},
+	}}
+
+	repo := &mockReleaseRepo{releases: map[int64]*domain.Release{}}
+	retryRepo := &mockActionRetryRepo{retries: map[int]*domain.ActionRetry{}}
+	actionSvc := &failingActionService{failures: 2}
+
+	s := NewService(logger.Mock(), &domain.Config{ActionRetryWindow: 60}, repo, retryRepo, nil, actionSvc, &mockFilterService{filters: []domain.Filter{grab}, matches: map[int]bool{1: true}}, &mockBlocklistService{}, &mockInstanceService{}, enrichment.NewService(logger.Mock()), nil, EventBus.New())
+
+	release := &domain.Release{Indexer: "mock", TorrentName: "That.Movie.2022.1080p.BluRay.x264-GROUP"}
+	s.Process(release)
+
+	repo.releases[release.ID] = release
+
+	// the failed action is queued
+	assert.Len(t, retryRepo.retries, 1)
+	assert.Equal(t, 5, retryRepo.retries[1].ActionID)
+	assert.Equal(t, domain.ActionRetryStatusPending, retryRepo.retries[1].Status)
+
+	// fails again and stays queued
+	assert.Error(t, s.Retry(context.Background(), 1))
+	assert.Equal(t, 2, retryRepo.retries[1].Attempts)
+
+	// succeeds and is removed from the queue
+	assert.NoError(t, s.Retry(context.Background(), 1))
+	assert.Empty(t, retryRepo.retries)
+
+	assert.Equal(t, []string{"grab-qbit", "grab-qbit", "grab-qbit"}, actionSvc.ran)
+}
+
+type mockReleaseApprovalRepo struct {
+	domain.ReleaseApprovalRepo
+
+	mu        sync.Mutex
+	approvals map[int]*domain.ReleaseApproval
+}
+
+func (r *mockReleaseApprovalRepo) Store(ctx context.Context, approval *domain.ReleaseApproval) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	approval.ID = len(r.approvals) + 1
+	stored := *approval
+	r.approvals[approval.ID] = &stored
+	return nil
+}
+
+func (r *mockReleaseApprovalRepo) Update(ctx context.Context, approval *domain.ReleaseApproval) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.approvals[approval.ID].Status != domain.ReleaseApprovalStatusPending {
+		return false, nil
+	}
+
+	stored := *approval
+	r.approvals[approval.ID] = &stored
+	return true, nil
+}
+
+// FindByID returns a copy like a database read would
+func (r *mockReleaseApprovalRepo) FindByID(ctx context.Context, id int) (*domain.ReleaseApproval, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	approval, ok := r.approvals[id]
+	if !ok {
+		return nil, errors.New("release approval not found: %v", id)
+	}
+
+	found := *approval
+	return &found, nil
+}
+
+type mockNotificationService struct {
+	notification.Service
+
+	sent []domain.NotificationPayload
+}
+
+func (s *mockNotificationService) Send(event domain.NotificationEvent, payload domain.NotificationPayload) {
+	s.sent = append(s.sent, payload)
+}
+
+// channelActionService reports the actions run in the background
+type channelActionService struct {
+	mockActionService
+
+	runs chan string
+}
+
+func (s *channelActionService) RunAction(a *domain.Action, release domain.Release) ([]string, error) {
+	s.runs <- a.Name
+	return nil, nil
+}
+
+func Test_service_Approval(t *testing.T) {
+	grab := domain.Filter{ID: 1, Name: "grab", RequireApproval: true, Actions: []*domain.Action{
+		{ID: 5, Name: "grab-qbit", Type: domain.ActionTypeQbittorrent, Enabled: true, ClientID: 1},
+	}}
+
+	config := &domain.Config{ReleaseApprovalWindow: 24, SessionSecret: "secret", PublicURL: "https://autobrr.example.com/"}
+
+	newService := func() (Service, *mockReleaseRepo, *mockReleaseApprovalRepo, *mockNotificationService, *channelActionService) {
+		repo := &mockReleaseRepo{releases: map[int64]*domain.Release{}}
+		approvalRepo := &mockReleaseApprovalRepo{approvals: map[int]*domain.ReleaseApproval{}}
+		notificationSvc := &mockNotificationService{}
+		actionSvc := &channelActionService{runs: make(chan string, 1)}
+
+		s := NewService(logger.Mock(), config, repo, nil, approvalRepo, actionSvc, &mockFilterService{filters: []domain.Filter{grab}, matches: map[int]bool{1: true}}, &mockBlocklistService{}, &mockInstanceService{}, enrichment.NewService(logger.Mock()), notificationSvc, EventBus.New())
+
+		release := &domain.Release{Indexer: "mock", TorrentName: "That.Movie.2022.1080p.BluRay.x264-GROUP", TorrentURL: "https://mock.local/1.torrent"}
+		s.Process(release)
+
+		repo.releases[release.ID] = release
+
+		return s, repo, approvalRepo, notificationSvc, actionSvc
+	}
+
+	t.Run("approve", func(t *testing.T) {
+		s, repo, approvalRepo, notificationSvc, actionSvc := newService()
+
+		// held for approval, the actions did not run
+		assert.Len(t, approvalRepo.approvals, 1)
+		assert.Equal(t, domain.ReleaseApprovalStatusPending, approvalRepo.approvals[1].Status)
+		assert.Equal(t, domain.ReleaseStatusFilterPendingApproval, repo.releases[1].FilterStatus)
+		assert.Empty(t, actionSvc.runs)
+
+		assert.Len(t, notificationSvc.sent, 1)
+		assert.Contains(t, notificationSvc.sent[0].ApproveURL, "https://autobrr.example.com/api/approval/1/approve?token=")
+
+		assert.NoError(t, s.DecideApproval(context.Background(), 1, domain.ReleaseApprovalDecisionApprove))
+		assert.Equal(t, domain.ReleaseApprovalStatusApproved, approvalRepo.approvals[1].Status)
+		assert.Equal(t, domain.ReleaseStatusFilterApproved, repo.releases[1].FilterStatus)
+
+		select {
+		case name := <-actionSvc.runs:
+			assert.Equal(t, "grab-qbit", name)
+		case <-time.After(time.Second):
+			t.Fatal("actions did not run after approval")
+		}
+
+		// decided once
+		assert.Error(t, s.DecideApproval(context.Background(), 1, domain.ReleaseApprovalDecisionApprove))
+	})
+
+	t.Run("approve_concurrent", func(t *testing.T) {
+		s, _, approvalRepo, _, actionSvc := newService()
+
+		token := approvalRepo.approvals[1].Token("secret", domain.ReleaseApprovalDecisionApprove)
+
+		// both decisions read the pending approval before either is stored
+		first, err := approvalRepo.FindByID(context.Background(), 1)
+		assert.NoError(t, err)
+		second, err := approvalRepo.FindByID(context.Background(), 1)
+		assert.NoError(t, err)
+
+		svc := s.(*service)
+		ctx := context.Background()
+
+		assert.NoError(t, svc.decideApproval(ctx, first, domain.ReleaseApprovalDecisionApprove))
+		assert.EqualError(t, svc.decideApproval(ctx, second, domain.ReleaseApprovalDecisionApprove), "release approval 1 is already decided")
+		assert.Error(t, s.DecideApprovalWithToken(ctx, 1, domain.ReleaseApprovalDecisionApprove, token))
+
+		select {
+		case name := <-actionSvc.runs:
+			assert.Equal(t, "grab-qbit", name)
+		case <-time.After(time.Second):
+			t.Fatal("actions did not run after approval")
+		}
+
+		// the actions ran once
+		select {
+		case name := <-actionSvc.runs:
+			t.Fatalf("actions ran again: %v", name)
+		case <-time.After(100 * time.Millisecond):
+		}
+	})
+
+	t.Run("reject_with_token", func(t *testing.T) {
+		s, repo, approvalRepo, _, actionSvc := newService()
+
+		token := approvalRepo.approvals[1].Token("secret", domain.ReleaseApprovalDecisionReject)
+
+		assert.Error(t, s.DecideApprovalWithToken(context.Background(), 1, domain.ReleaseApprovalDecisionReject, "invalid"))
+		assert.Error(t, s.DecideApprovalWithToken(context.Background(), 1, domain.ReleaseApprovalDecisionApprove, token))
+		assert.Equal(t, domain.ReleaseApprovalStatusPending, approvalRepo.approvals[1].Status)
+
+		assert.NoError(t, s.DecideApprovalWithToken(context.Background(), 1, domain.ReleaseApprovalDecisionReject, token))
+		assert.Equal(t, domain.ReleaseApprovalStatusRejected, approvalRepo.approvals[1].Status)
+		assert.Equal(t, domain.ReleaseStatusFilterRejected, repo.releases[1].FilterStatus)
+		assert.Empty(t, actionSvc.runs)
+	})
+}
+
+func Test_service_Prune(t *testing.T) {
+	tests := []struct {
+		name   string
+		config domain.Config
+		want   []domain.ReleaseRetention
+	}{
+		{name: "disabled", config: domain.Config{ReleaseRetentionKeepApproved: true}, want: nil},
+		{name: "max_age", config: domain.Config{ReleaseRetentionDays: 30}, want: []domain.ReleaseRetention{{MaxAgeDays: 30}}},
+		{name: "max_rows", config: domain.Config{ReleaseRetentionMaxRows: 1000, ReleaseRetentionKeepApproved: true}, want: []domain.ReleaseRetention{{MaxRows: 1000, KeepApproved: true}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &mockReleaseRepo{}
+			s := NewService(logger.Mock(), &tt.config, repo, nil, nil, &mockActionService{}, &mockFilterService{}, &mockBlocklistService{}, &mockInstanceService{}, enrichment.NewService(logger.Mock()), nil, EventBus.New())
+
+			got, err := s.Prune(context.Background())
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, repo.pruned)
+
+			if tt.want != nil {
+				assert.Equal(t, int64(3), got)
+			} else {
+				assert.Equal(t, int64(0), got)
+			}
+		})
+	}
+}
+
+func Test_service_Export(t *testing.T) {
+	tests := []struct {
+		name      string
+		releases  int
+		wantPages []int
+	}{
+		{name: "empty", releases: 0, wantPages: nil},
+		{name: "single_page", releases: 3, wantPages: []int{3}},
+		{name: "full_pages", releases: exportPageSize * 2, wantPages: []int{exportPageSize, exportPageSize}},
+		{name: "partial_last_page", releases: exportPageSize + 1, wantPages: []int{exportPageSize, 1}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &mockReleaseRepo{}
+			for id := tt.releases; id > 0; id-- {
+				repo.history = append(repo.history, &domain.Release{ID: int64(id)})
+			}
+
+			s := NewService(logger.Mock(), &domain.Config{}, repo, nil, nil, &mockActionService{}, &mockFilterService{}, &mockBlocklistService{}, &mockInstanceService{}, enrichment.NewService(logger.Mock()), nil, EventBus.New())
+
+			var pages []int
+			var lastID int64
+			err := s.Export(context.Background(), domain.ReleaseQueryParams{Limit: 20, Offset: 40, Search: "group"}, func(releases []*domain.Release) error {
+				pages = append(pages, len(releases))
+				lastID = releases[len(releases)-1].ID
+				return nil
+			})
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantPages, pages)
+
+			if tt.releases > 0 {
+				assert.Equal(t, int64(1), lastID)
+			}
+
+			// the limit and offset of the request don't apply to the export
+			for _, q := range repo.queries {
+				assert.Equal(t, uint64(exportPageSize), q.Limit)
+				assert.Equal(t, uint64(0), q.Offset)
+				assert.Equal(t, "group", q.Search)
+			}
+		})
+	}
+}
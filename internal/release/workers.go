@@ -0,0 +1,108 @@
+package release
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/autobrr/autobrr/internal/domain"
+	"github.com/autobrr/autobrr/internal/metrics"
+	"github.com/autobrr/autobrr/pkg/errors"
+)
+
+const (
+	defaultIndexerWorkers   = 4
+	defaultIndexerQueueSize = 100
+)
+
+// ErrQueueFull is returned when a release is dropped because the queue of its indexer is full
+var ErrQueueFull = errors.New("release queue is full")
+
+type queuedRelease struct {
+	release  *domain.Release
+	queuedAt time.Time
+}
+
+// workerPools processes releases with a bounded pool of workers and queue per indexer,
+// so slow actions of one indexer never hold up the releases of another.
+type workerPools struct {
+	mu    sync.Mutex
+	pools map[string]chan queuedRelease
+
+	workers   int
+	queueSize int
+
+	process func(release *domain.Release)
+}
+
+func newWorkerPools(workers int, queueSize int, process func(release *domain.Release)) *workerPools {
+	if workers <= 0 {
+		workers = defaultIndexerWorkers
+	}
+	if queueSize <= 0 {
+		queueSize = defaultIndexerQueueSize
+	}
+
+	return &workerPools{
+		pools:     map[string]chan queuedRelease{},
+		workers:   workers,
+		queueSize: queueSize,
+		process:   process,
+	}
+}
+
+// enqueue queues the release on the pool of its indexer, the workers of a pool are started with its first release.
+// It returns false if the queue of the indexer is full and the release was dropped.
+func (w *workerPools) enqueue(release *domain.Release) bool {
+	queue := w.pool(release.Indexer)
+
+	select {
+	case queue <- queuedRelease{release: release, queuedAt: time.Now()}:
+		metrics.ReleaseQueueDepth.Set(float64(len(queue)), release.Indexer)
+		return true
+	default:
+		metrics.ReleaseQueueDropped.Inc(release.Indexer)
+		return false
+	}
+}
+
+// enqueueWait queues the release like enqueue but waits for room in the queue until ctx is done
+func (w *workerPools) enqueueWait(ctx context.Context, release *domain.Release) error {
+	queue := w.pool(release.Indexer)
+
+	select {
+	case queue <- queuedRelease{release: release, queuedAt: time.Now()}:
+		metrics.ReleaseQueueDepth.Set(float64(len(queue)), release.Indexer)
+		return nil
+	case <-ctx.Done():
+		metrics.ReleaseQueueDropped.Inc(release.Indexer)
+		return ctx.Err()
+	}
+}
+
+func (w *workerPools) pool(indexer string) chan queuedRelease {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if queue, ok := w.pools[indexer]; ok {
+		return queue
+	}
+
+	queue := make(chan queuedRelease, w.queueSize)
+	w.pools[indexer] = queue
+
+	for i := 0; i < w.workers; i++ {
+		go w.work(indexer, queue)
+	}
+
+	return queue
+}
+
+func (w *workerPools) work(indexer string, queue chan queuedRelease) {
+	for q := range queue {
+		metrics.ReleaseQueueDepth.Set(float64(len(queue)), indexer)
+		metrics.ReleaseQueueWait.Observe(time.Since(q.queuedAt).Seconds(), indexer)
+
+		w.process(q.release)
+	}
+}
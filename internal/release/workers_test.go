@@ -0,0 +1,73 @@
+package release
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/autobrr/autobrr/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_workerPools(t *testing.T) {
+	block := make(chan struct{})
+	processed := make(chan string, 10)
+
+	pools := newWorkerPools(1, 1, func(release *domain.Release) {
+		if release.Indexer == "slow" {
+			<-block
+		}
+		processed <- release.TorrentName
+	})
+
+	// the only worker of slow is busy, the next release waits in the queue and the one after is dropped
+	assert.True(t, pools.enqueue(&domain.Release{Indexer: "slow", TorrentName: "slow-1"}))
+	assert.Eventually(t, func() bool { return len(pools.pool("slow")) == 0 }, time.Second, time.Millisecond)
+	assert.True(t, pools.enqueue(&domain.Release{Indexer: "slow", TorrentName: "slow-2"}))
+	assert.False(t, pools.enqueue(&domain.Release{Indexer: "slow", TorrentName: "slow-3"}))
+
+	// other indexers are not held up by the slow one
+	assert.True(t, pools.enqueue(&domain.Release{Indexer: "fast", TorrentName: "fast-1"}))
+	select {
+	case name := <-processed:
+		assert.Equal(t, "fast-1", name)
+	case <-time.After(time.Second):
+		t.Fatal("release of fast indexer was not processed")
+	}
+
+	close(block)
+	assert.Equal(t, "slow-1", <-processed)
+	assert.Equal(t, "slow-2", <-processed)
+}
+
+func Test_workerPools_enqueueWait(t *testing.T) {
+	block := make(chan struct{})
+	processed := make(chan string, 10)
+
+	pools := newWorkerPools(1, 1, func(release *domain.Release) {
+		<-block
+		processed <- release.TorrentName
+	})
+
+	assert.True(t, pools.enqueue(&domain.Release{Indexer: "slow", TorrentName: "slow-1"}))
+	assert.Eventually(t, func() bool { return len(pools.pool("slow")) == 0 }, time.Second, time.Millisecond)
+	assert.True(t, pools.enqueue(&domain.Release{Indexer: "slow", TorrentName: "slow-2"}))
+
+	// the queue is full, the release is dropped once the context is done
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	assert.ErrorIs(t, pools.enqueueWait(ctx, &domain.Release{Indexer: "slow", TorrentName: "slow-3"}), context.DeadlineExceeded)
+
+	// waits for room instead of dropping
+	queued := make(chan error, 1)
+	go func() {
+		queued <- pools.enqueueWait(context.Background(), &domain.Release{Indexer: "slow", TorrentName: "slow-4"})
+	}()
+
+	close(block)
+	assert.NoError(t, <-queued)
+	assert.Equal(t, "slow-1", <-processed)
+	assert.Equal(t, "slow-2", <-processed)
+	assert.Equal(t, "slow-4", <-processed)
+}
@@ -6,6 +6,7 @@ import (
 
 	"github.com/autobrr/autobrr/internal/logger"
 	"github.com/autobrr/autobrr/internal/notification"
+	"github.com/autobrr/autobrr/pkg/errors"
 
 	"github.com/robfig/cron/v3"
 	"github.com/rs/zerolog"
@@ -16,6 +17,7 @@ type Service interface {
 	Stop()
 	AddJob(job cron.Job, interval time.Duration, identifier string) (int, error)
 	RemoveJobByIdentifier(id string) error
+	RunJobByIdentifier(id string) error
 }
 
 type service struct {
@@ -110,6 +112,29 @@ func (s *service) RemoveJobByIdentifier(id string) error {
 	return nil
 }
 
+// RunJobByIdentifier runs the scheduled job now in the background, its schedule is unchanged
+func (s *service) RunJobByIdentifier(id string) error {
+	s.m.RLock()
+	v, ok := s.jobs[id]
+	s.m.RUnlock()
+
+	if !ok {
+		return errors.New("no scheduled job: %v", id)
+	}
+
+	entry := s.cron.Entry(v)
+	if !entry.Valid() {
+		return errors.New("no scheduled job: %v", id)
+	}
+
+	s.log.Debug().Msgf("scheduler.Run: running job: %v", id)
+
+	// the job is wrapped to skip if still running, a run in progress is not started twice
+	go entry.WrappedJob.Run()
+
+	return nil
+}
+
 type GenericJob struct {
 	Name string
 	Log  zerolog.Logger
@@ -5,10 +5,17 @@ import (
 
 	"github.com/rs/zerolog"
 
+	"github.com/autobrr/autobrr/internal/backup"
+	"github.com/autobrr/autobrr/internal/blocklist"
+	"github.com/autobrr/autobrr/internal/download_client"
 	"github.com/autobrr/autobrr/internal/feed"
+	"github.com/autobrr/autobrr/internal/filter"
 	"github.com/autobrr/autobrr/internal/indexer"
+	"github.com/autobrr/autobrr/internal/instance"
 	"github.com/autobrr/autobrr/internal/irc"
+	"github.com/autobrr/autobrr/internal/list"
 	"github.com/autobrr/autobrr/internal/logger"
+	"github.com/autobrr/autobrr/internal/release"
 	"github.com/autobrr/autobrr/internal/scheduler"
 )
 
@@ -17,37 +24,76 @@ type Server struct {
 	Hostname string
 	Port     int
 
-	indexerService indexer.Service
-	ircService     irc.Service
-	feedService    feed.Service
-	scheduler      scheduler.Service
+	indexerService        indexer.Service
+	ircService            irc.Service
+	feedService           feed.Service
+	instance              instance.Service
+	scheduler             scheduler.Service
+	downloadClientService download_client.Service
+	releaseService        release.Service
+	backupService         backup.Service
+	filterService         filter.Service
+	listService           list.Service
+	blocklistService      blocklist.Service
 
 	stopWG sync.WaitGroup
 	lock   sync.Mutex
 }
 
-func NewServer(log logger.Logger, ircSvc irc.Service, indexerSvc indexer.Service, feedSvc feed.Service, scheduler scheduler.Service) *Server {
+func NewServer(log logger.Logger, ircSvc irc.Service, indexerSvc indexer.Service, feedSvc feed.Service, instanceSvc instance.Service, scheduler scheduler.Service, downloadClientSvc download_client.Service, releaseSvc release.Service, backupSvc backup.Service, filterSvc filter.Service, listSvc list.Service, blocklistSvc blocklist.Service) *Server {
 	return &Server{
-		log:            log.With().Str("module", "server").Logger(),
-		indexerService: indexerSvc,
-		ircService:     ircSvc,
-		feedService:    feedSvc,
-		scheduler:      scheduler,
+		log:                   log.With().Str("module", "server").Logger(),
+		indexerService:        indexerSvc,
+		ircService:            ircSvc,
+		feedService:           feedSvc,
+		instance:              instanceSvc,
+		scheduler:             scheduler,
+		downloadClientService: downloadClientSvc,
+		releaseService:        releaseSvc,
+		backupService:         backupSvc,
+		filterService:         filterSvc,
+		listService:           listSvc,
+		blocklistService:      blocklistSvc,
 	}
 }
 
 func (s *Server) Start() error {
 	s.log.Info().Msgf("Starting server. Listening on %v:%v", s.Hostname, s.Port)
 
+	// take the lease before announces come in
+	s.instance.Start()
+
 	// start cron scheduler
 	s.scheduler.Start()
 
+	// check download clients health
+	if err := s.downloadClientService.Start(); err != nil {
+		s.log.Error().Err(err).Msg("Could not start download client health checks")
+	}
+
 	// instantiate indexers
 	if err := s.indexerService.Start(); err != nil {
 		s.log.Error().Err(err).Msg("Could not start indexer service")
 		return err
 	}
 
+	// retry failed actions
+	s.releaseService.Start()
+
+	// scheduled database backups
+	s.backupService.Start()
+
+	// store filter rejection counts
+	s.filterService.Start()
+
+	// refresh filter lists
+	if err := s.listService.Start(); err != nil {
+		s.log.Error().Err(err).Msg("Could not start list refresh")
+	}
+
+	// load the announce blocklist before announces come in
+	s.blocklistService.Start()
+
 	// instantiate and start irc networks
 	s.ircService.StartHandlers()
 
@@ -67,4 +113,7 @@ func (s *Server) Shutdown() {
 
 	// stop cron scheduler
 	s.scheduler.Stop()
+
+	// hand over to a standby instance
+	s.instance.Stop()
 }
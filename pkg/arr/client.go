@@ -0,0 +1,230 @@
+// Package arr contains the http client shared by the radarr, sonarr, lidarr and whisparr packages.
+// It handles auth headers and retries transient errors with exponential backoff and Retry-After.
+package arr
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/autobrr/autobrr/pkg/errors"
+)
+
+const (
+	DefaultTimeout       = 30 * time.Second
+	DefaultRetries       = 3
+	DefaultRetryDelay    = 2 * time.Second
+	DefaultMaxRetryDelay = 30 * time.Second
+
+	// DefaultQueuePageSize is the number of queue records fetched for duplicate checks
+	DefaultQueuePageSize = 200
+)
+
+type Config struct {
+	// Name is used in errors and logs, eg. radarr
+	Name string
+
+	Hostname   string
+	APIKey     string
+	APIVersion string
+
+	// basic auth username and password
+	BasicAuth bool
+	Username  string
+	Password  string
+
+	Timeout time.Duration
+
+	// Retries is the number of retries after the first attempt. Zero uses DefaultRetries, negative disables retries.
+	Retries int
+	// RetryDelay is the initial delay between retries, it doubles with every retry.
+	RetryDelay time.Duration
+	// MaxRetryDelay caps the backoff and Retry-After delays.
+	MaxRetryDelay time.Duration
+
+	Log *log.Logger
+}
+
+type Client struct {
+	config Config
+	http   *http.Client
+}
+
+func New(config Config) *Client {
+	if config.Timeout == 0 {
+		config.Timeout = DefaultTimeout
+	}
+	if config.Retries == 0 {
+		config.Retries = DefaultRetries
+	} else if config.Retries < 0 {
+		config.Retries = 0
+	}
+	if config.RetryDelay == 0 {
+		config.RetryDelay = DefaultRetryDelay
+	}
+	if config.MaxRetryDelay == 0 {
+		config.MaxRetryDelay = DefaultMaxRetryDelay
+	}
+	if config.Log == nil {
+		config.Log = log.New(io.Discard, "", log.LstdFlags)
+	}
+
+	return &Client{
+		config: config,
+		http: &http.Client{
+			Timeout: config.Timeout,
+		},
+	}
+}
+
+// Get returns the status code and body of a GET request to the api endpoint
+func (c *Client) Get(ctx context.Context, endpoint string) (int, []byte, error) {
+	return c.do(ctx, http.MethodGet, endpoint, nil)
+}
+
+// Post sends data as json to the api endpoint and returns the status code and body.
+// Bad request responses are returned without error so the caller can read the rejection,
+// other unexpected statuses return an error.
+func (c *Client) Post(ctx context.Context, endpoint string, data interface{}) (int, []byte, error) {
+	jsonData, err := json.Marshal(data)
+	if err != nil {
+		return 0, nil, errors.Wrap(err, "%v: could not marshal data: %+v", c.config.Name, data)
+	}
+
+	status, body, err := c.do(ctx, http.MethodPost, endpoint, jsonData)
+	if err != nil {
+		return status, body, err
+	}
+
+	if status == http.StatusBadRequest {
+		return status, body, nil
+	} else if status < 200 || status > 401 {
+		return status, body, errors.New("%v: bad request: %v (status: %d): %s", c.config.Name, endpoint, status, string(body))
+	}
+
+	return status, body, nil
+}
+
+func (c *Client) do(ctx context.Context, method string, endpoint string, data []byte) (int, []byte, error) {
+	u, err := url.Parse(c.config.Hostname)
+	if err != nil {
+		return 0, nil, errors.Wrap(err, "%v: could not parse host: %v", c.config.Name, c.config.Hostname)
+	}
+
+	// endpoints can carry a query string, eg. queue?pageSize=50
+	endpointPath, rawQuery, _ := strings.Cut(endpoint, "?")
+
+	u.Path = path.Join(u.Path, "/api/"+c.config.APIVersion+"/", endpointPath)
+	u.RawQuery = rawQuery
+	reqUrl := u.String()
+
+	delay := c.config.RetryDelay
+
+	for attempt := 0; ; attempt++ {
+		status, body, retryAfter, err := c.attempt(ctx, method, reqUrl, data)
+		if err == nil && !retryableStatus(status) {
+			return status, body, nil
+		}
+
+		if attempt >= c.config.Retries || ctx.Err() != nil {
+			if err != nil {
+				return status, body, errors.Wrap(err, "%v: request failed after %d attempts: %v", c.config.Name, attempt+1, reqUrl)
+			}
+			return status, body, errors.New("%v: request failed after %d attempts: %v (status: %d)", c.config.Name, attempt+1, reqUrl, status)
+		}
+
+		wait := delay
+		if retryAfter > 0 {
+			wait = retryAfter
+		}
+		if wait > c.config.MaxRetryDelay {
+			wait = c.config.MaxRetryDelay
+		}
+
+		c.config.Log.Printf("%v %v %v failed (status: %d, err: %v), retrying in %v\n", c.config.Name, method, endpoint, status, err, wait)
+
+		select {
+		case <-ctx.Done():
+			return status, body, errors.Wrap(ctx.Err(), "%v: request cancelled: %v", c.config.Name, reqUrl)
+		case <-time.After(wait):
+		}
+
+		delay *= 2
+	}
+}
+
+// attempt does a single request, a returned error means the request can be retried
+func (c *Client) attempt(ctx context.Context, method string, reqUrl string, data []byte) (int, []byte, time.Duration, error) {
+	var body io.Reader = http.NoBody
+	if data != nil {
+		body = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, reqUrl, body)
+	if err != nil {
+		return 0, nil, 0, errors.Wrap(err, "could not build request: %v", reqUrl)
+	}
+
+	if c.config.BasicAuth {
+		req.SetBasicAuth(c.config.Username, c.config.Password)
+	}
+
+	if data != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	req.Header.Set("User-Agent", "autobrr")
+	req.Header.Set("X-Api-Key", c.config.APIKey)
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return 0, nil, 0, errors.Wrap(err, "%v.http.Do(req): %v", c.config.Name, reqUrl)
+	}
+
+	defer resp.Body.Close()
+
+	var buf bytes.Buffer
+	if _, err = io.Copy(&buf, resp.Body); err != nil {
+		return resp.StatusCode, nil, 0, errors.Wrap(err, "%v.io.Copy", c.config.Name)
+	}
+
+	return resp.StatusCode, buf.Bytes(), parseRetryAfter(resp.Header.Get("Retry-After"), time.Now()), nil
+}
+
+func retryableStatus(status int) bool {
+	switch status {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// parseRetryAfter parses the Retry-After header which can be in seconds or a http date
+func parseRetryAfter(value string, now time.Time) time.Duration {
+	if value == "" {
+		return 0
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0
+		}
+		return time.Duration(seconds) * time.Second
+	}
+
+	if t, err := http.ParseTime(value); err == nil {
+		if d := t.Sub(now); d > 0 {
+			return d
+		}
+	}
+
+	return 0
+}
@@ -0,0 +1,136 @@
+package arr
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestClient(url string) *Client {
+	return New(Config{
+		Name:          "test",
+		Hostname:      url,
+		APIKey:        "mock-key",
+		APIVersion:    "v3",
+		Retries:       2,
+		RetryDelay:    10 * time.Millisecond,
+		MaxRetryDelay: 50 * time.Millisecond,
+	})
+}
+
+func TestClient_Retries(t *testing.T) {
+	tests := []struct {
+		name       string
+		statuses   []int
+		retryAfter string
+		post       bool
+		wantStatus int
+		wantCalls  int32
+		wantErr    bool
+	}{
+		{
+			name:       "retry_then_ok",
+			statuses:   []int{http.StatusServiceUnavailable, http.StatusOK},
+			wantStatus: http.StatusOK,
+			wantCalls:  2,
+		},
+		{
+			name:       "retry_after_capped",
+			statuses:   []int{http.StatusTooManyRequests, http.StatusOK},
+			retryAfter: "3600",
+			wantStatus: http.StatusOK,
+			wantCalls:  2,
+		},
+		{
+			name:       "retries_exhausted",
+			statuses:   []int{http.StatusBadGateway, http.StatusBadGateway, http.StatusBadGateway},
+			wantStatus: http.StatusBadGateway,
+			wantCalls:  3,
+			wantErr:    true,
+		},
+		{
+			name:       "not_retryable",
+			statuses:   []int{http.StatusInternalServerError, http.StatusOK},
+			post:       true,
+			wantStatus: http.StatusInternalServerError,
+			wantCalls:  1,
+			wantErr:    true,
+		},
+		{
+			name:       "post_bad_request",
+			statuses:   []int{http.StatusBadRequest},
+			post:       true,
+			wantStatus: http.StatusBadRequest,
+			wantCalls:  1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls int32
+
+			ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				n := atomic.AddInt32(&calls, 1)
+
+				assert.Equal(t, "/api/v3/system/status", r.URL.Path)
+				assert.Equal(t, "mock-key", r.Header.Get("X-Api-Key"))
+
+				if tt.retryAfter != "" {
+					w.Header().Set("Retry-After", tt.retryAfter)
+				}
+				w.WriteHeader(tt.statuses[n-1])
+				w.Write([]byte(`{}`))
+			}))
+			defer ts.Close()
+
+			c := newTestClient(ts.URL)
+
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+
+			var (
+				status int
+				err    error
+			)
+			if tt.post {
+				status, _, err = c.Post(ctx, "system/status", map[string]string{"title": "test"})
+			} else {
+				status, _, err = c.Get(ctx, "system/status")
+			}
+
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.wantStatus, status)
+			assert.Equal(t, tt.wantCalls, atomic.LoadInt32(&calls))
+		})
+	}
+}
+
+func Test_parseRetryAfter(t *testing.T) {
+	now := time.Date(2022, 10, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name  string
+		value string
+		want  time.Duration
+	}{
+		{name: "empty", value: "", want: 0},
+		{name: "seconds", value: "120", want: 120 * time.Second},
+		{name: "negative", value: "-5", want: 0},
+		{name: "http_date", value: now.Add(90 * time.Second).Format(http.TimeFormat), want: 90 * time.Second},
+		{name: "http_date_past", value: now.Add(-time.Minute).Format(http.TimeFormat), want: 0},
+		{name: "invalid", value: "soon", want: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, parseRetryAfter(tt.value, now))
+		})
+	}
+}
@@ -0,0 +1,141 @@
+package arr
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/autobrr/autobrr/pkg/errors"
+)
+
+// QueueRecord is a download in the queue
+type QueueRecord struct {
+	Title                string `json:"title"`
+	Status               string `json:"status"`
+	TrackedDownloadState string `json:"trackedDownloadState"`
+	DownloadID           string `json:"downloadId"`
+}
+
+type QueueResponse struct {
+	Page         int           `json:"page"`
+	PageSize     int           `json:"pageSize"`
+	TotalRecords int           `json:"totalRecords"`
+	Records      []QueueRecord `json:"records"`
+}
+
+// HistoryRecord is a history event like grabbed, imported or failed
+type HistoryRecord struct {
+	SourceTitle string    `json:"sourceTitle"`
+	EventType   string    `json:"eventType"`
+	DownloadID  string    `json:"downloadId"`
+	Date        time.Time `json:"date"`
+}
+
+type HistoryResponse struct {
+	Page         int             `json:"page"`
+	PageSize     int             `json:"pageSize"`
+	TotalRecords int             `json:"totalRecords"`
+	Records      []HistoryRecord `json:"records"`
+}
+
+// Queue returns the first page of the download queue
+func (c *Client) Queue(ctx context.Context, pageSize int) ([]QueueRecord, error) {
+	var res QueueResponse
+	if err := c.getJSON(ctx, fmt.Sprintf("queue?page=1&pageSize=%d", pageSize), &res); err != nil {
+		return nil, errors.Wrap(err, "could not get queue")
+	}
+
+	return res.Records, nil
+}
+
+// History returns the latest history events, newest first
+func (c *Client) History(ctx context.Context, pageSize int) ([]HistoryRecord, error) {
+	var res HistoryResponse
+	if err := c.getJSON(ctx, fmt.Sprintf("history?page=1&pageSize=%d&sortKey=date&sortDirection=descending", pageSize), &res); err != nil {
+		return nil, errors.Wrap(err, "could not get history")
+	}
+
+	return res.Records, nil
+}
+
+func (c *Client) getJSON(ctx context.Context, endpoint string, v interface{}) error {
+	status, body, err := c.Get(ctx, endpoint)
+	if err != nil {
+		return err
+	}
+
+	if status == http.StatusUnauthorized {
+		return errors.New("%v: unauthorized: bad credentials", c.config.Name)
+	} else if status != http.StatusOK {
+		return errors.New("%v: unexpected status: %v (status: %d): %s", c.config.Name, endpoint, status, string(body))
+	}
+
+	if err := json.Unmarshal(body, v); err != nil {
+		return errors.Wrap(err, "%v: could not unmarshal data", c.config.Name)
+	}
+
+	return nil
+}
+
+// FindDuplicate checks the queue and history for a release with the same title.
+// It returns the reason when the release is already queued, grabbed or imported and an empty string otherwise.
+// Grabs that later failed are not counted so the release can be pushed again.
+func FindDuplicate(title string, queue []QueueRecord, history []HistoryRecord) string {
+	normalized := normalizeTitle(title)
+	if normalized == "" {
+		return ""
+	}
+
+	for _, q := range queue {
+		if normalizeTitle(q.Title) == normalized {
+			return fmt.Sprintf("release already in queue: %v (%v)", q.Title, q.Status)
+		}
+	}
+
+	failed := make(map[string]struct{})
+	for _, h := range history {
+		if isFailedEvent(h.EventType) && h.DownloadID != "" {
+			failed[h.DownloadID] = struct{}{}
+		}
+	}
+
+	for _, h := range history {
+		if normalizeTitle(h.SourceTitle) != normalized {
+			continue
+		}
+
+		if _, ok := failed[h.DownloadID]; ok && h.DownloadID != "" {
+			continue
+		}
+
+		if h.EventType == "grabbed" {
+			return fmt.Sprintf("release already grabbed: %v", h.SourceTitle)
+		}
+
+		if strings.Contains(strings.ToLower(h.EventType), "imported") {
+			return fmt.Sprintf("release already imported: %v", h.SourceTitle)
+		}
+	}
+
+	return ""
+}
+
+func isFailedEvent(eventType string) bool {
+	return strings.Contains(strings.ToLower(eventType), "failed")
+}
+
+// normalizeTitle lowercases and strips separators so "That.Show.S01E01" and "That Show S01E01" match
+func normalizeTitle(title string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(title) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			b.WriteRune(r)
+		}
+	}
+
+	return b.String()
+}
@@ -0,0 +1,96 @@
+package arr
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClient_QueueHistory(t *testing.T) {
+	mux := http.NewServeMux()
+	ts := httptest.NewServer(mux)
+	defer ts.Close()
+
+	mux.HandleFunc("/api/v3/queue", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "50", r.URL.Query().Get("pageSize"))
+		w.Write([]byte(`{"page":1,"pageSize":50,"totalRecords":1,"records":[{"title":"That.Show.S01E01.1080p.WEB-DL-GROUP","status":"downloading","downloadId":"ABC"}]}`))
+	})
+	mux.HandleFunc("/api/v3/history", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "descending", r.URL.Query().Get("sortDirection"))
+		w.Write([]byte(`{"page":1,"pageSize":10,"totalRecords":1,"records":[{"sourceTitle":"That.Show.S01E02.1080p.WEB-DL-GROUP","eventType":"grabbed","downloadId":"DEF","date":"2022-08-21T15:36:00Z"}]}`))
+	})
+
+	c := newTestClient(ts.URL)
+
+	queue, err := c.Queue(context.Background(), 50)
+	assert.NoError(t, err)
+	assert.Equal(t, []QueueRecord{{Title: "That.Show.S01E01.1080p.WEB-DL-GROUP", Status: "downloading", DownloadID: "ABC"}}, queue)
+
+	history, err := c.History(context.Background(), 10)
+	assert.NoError(t, err)
+	assert.Len(t, history, 1)
+	assert.Equal(t, "grabbed", history[0].EventType)
+}
+
+func TestClient_Queue_unauthorized(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer ts.Close()
+
+	_, err := newTestClient(ts.URL).Queue(context.Background(), 50)
+	assert.Error(t, err)
+}
+
+func TestFindDuplicate(t *testing.T) {
+	tests := []struct {
+		name    string
+		title   string
+		queue   []QueueRecord
+		history []HistoryRecord
+		want    string
+	}{
+		{
+			name:  "in_queue",
+			title: "That Show S01E01 1080p WEB-DL-GROUP",
+			queue: []QueueRecord{{Title: "That.Show.S01E01.1080p.WEB-DL-GROUP", Status: "downloading"}},
+			want:  "release already in queue: That.Show.S01E01.1080p.WEB-DL-GROUP (downloading)",
+		},
+		{
+			name:    "grabbed",
+			title:   "That.Show.S01E01.1080p.WEB-DL-GROUP",
+			history: []HistoryRecord{{SourceTitle: "That.Show.S01E01.1080p.WEB-DL-GROUP", EventType: "grabbed", DownloadID: "ABC"}},
+			want:    "release already grabbed: That.Show.S01E01.1080p.WEB-DL-GROUP",
+		},
+		{
+			name:    "imported",
+			title:   "That.Movie.2022.1080p.BluRay-GROUP",
+			history: []HistoryRecord{{SourceTitle: "That.Movie.2022.1080p.BluRay-GROUP", EventType: "downloadFolderImported", DownloadID: "ABC"}},
+			want:    "release already imported: That.Movie.2022.1080p.BluRay-GROUP",
+		},
+		{
+			name:  "grab_failed",
+			title: "That.Show.S01E01.1080p.WEB-DL-GROUP",
+			history: []HistoryRecord{
+				{SourceTitle: "That.Show.S01E01.1080p.WEB-DL-GROUP", EventType: "downloadFailed", DownloadID: "ABC"},
+				{SourceTitle: "That.Show.S01E01.1080p.WEB-DL-GROUP", EventType: "grabbed", DownloadID: "ABC"},
+			},
+			want: "",
+		},
+		{
+			name:    "different_release",
+			title:   "That.Show.S01E02.1080p.WEB-DL-GROUP",
+			queue:   []QueueRecord{{Title: "That.Show.S01E01.1080p.WEB-DL-GROUP"}},
+			history: []HistoryRecord{{SourceTitle: "That.Show.S01E01.1080p.WEB-DL-GROUP", EventType: "grabbed"}},
+			want:    "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, FindDuplicate(tt.title, tt.queue, tt.history))
+		})
+	}
+}
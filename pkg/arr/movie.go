@@ -0,0 +1,29 @@
+package arr
+
+import (
+	"context"
+
+	"github.com/autobrr/autobrr/pkg/errors"
+)
+
+// Movie is a movie in radarr
+type Movie struct {
+	ID              int              `json:"id"`
+	TmdbID          int              `json:"tmdbId"`
+	ImdbID          string           `json:"imdbId"`
+	Title           string           `json:"title"`
+	Year            int              `json:"year"`
+	Monitored       bool             `json:"monitored"`
+	HasFile         bool             `json:"hasFile"`
+	AlternateTitles []AlternateTitle `json:"alternateTitles"`
+}
+
+// Movies returns all movies
+func (c *Client) Movies(ctx context.Context) ([]Movie, error) {
+	res := make([]Movie, 0)
+	if err := c.getJSON(ctx, "movie", &res); err != nil {
+		return nil, errors.Wrap(err, "could not get movies")
+	}
+
+	return res, nil
+}
@@ -0,0 +1,26 @@
+package arr
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClient_Movies(t *testing.T) {
+	mux := http.NewServeMux()
+	ts := httptest.NewServer(mux)
+	defer ts.Close()
+
+	mux.HandleFunc("/api/v3/movie", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`[{"id":1,"tmdbId":100,"imdbId":"tt0100","title":"That Movie","year":2020,"monitored":true,"hasFile":false,"alternateTitles":[{"title":"Das Movie"}]}]`))
+	})
+
+	c := newTestClient(ts.URL)
+
+	movies, err := c.Movies(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, []Movie{{ID: 1, TmdbID: 100, ImdbID: "tt0100", Title: "That Movie", Year: 2020, Monitored: true, AlternateTitles: []AlternateTitle{{Title: "Das Movie"}}}}, movies)
+}
@@ -0,0 +1,69 @@
+package arr
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/autobrr/autobrr/pkg/errors"
+)
+
+// Series is a show in sonarr or a site in whisparr
+type Series struct {
+	ID              int              `json:"id"`
+	TvdbID          int              `json:"tvdbId"`
+	Title           string           `json:"title"`
+	Year            int              `json:"year"`
+	Monitored       bool             `json:"monitored"`
+	AlternateTitles []AlternateTitle `json:"alternateTitles"`
+	Seasons         []Season         `json:"seasons"`
+}
+
+type AlternateTitle struct {
+	Title        string `json:"title"`
+	SeasonNumber int    `json:"seasonNumber"`
+}
+
+type Season struct {
+	SeasonNumber int              `json:"seasonNumber"`
+	Monitored    bool             `json:"monitored"`
+	Statistics   SeasonStatistics `json:"statistics"`
+}
+
+type SeasonStatistics struct {
+	EpisodeFileCount  int `json:"episodeFileCount"`
+	EpisodeCount      int `json:"episodeCount"`
+	TotalEpisodeCount int `json:"totalEpisodeCount"`
+}
+
+// EpisodeFile is a file on disk, a file can hold more than one episode
+type EpisodeFile struct {
+	ID           int    `json:"id"`
+	SeriesID     int    `json:"seriesId"`
+	SeasonNumber int    `json:"seasonNumber"`
+	RelativePath string `json:"relativePath"`
+}
+
+// Series returns the series with the tvdb id, or all series if the id is 0
+func (c *Client) Series(ctx context.Context, tvdbID int) ([]Series, error) {
+	endpoint := "series"
+	if tvdbID > 0 {
+		endpoint = fmt.Sprintf("series?tvdbId=%d", tvdbID)
+	}
+
+	res := make([]Series, 0)
+	if err := c.getJSON(ctx, endpoint, &res); err != nil {
+		return nil, errors.Wrap(err, "could not get series")
+	}
+
+	return res, nil
+}
+
+// EpisodeFiles returns the episode files on disk of a series
+func (c *Client) EpisodeFiles(ctx context.Context, seriesID int) ([]EpisodeFile, error) {
+	res := make([]EpisodeFile, 0)
+	if err := c.getJSON(ctx, fmt.Sprintf("episodefile?seriesId=%d", seriesID), &res); err != nil {
+		return nil, errors.Wrap(err, "could not get episode files")
+	}
+
+	return res, nil
+}
@@ -0,0 +1,46 @@
+package arr
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClient_SeriesEpisodeFiles(t *testing.T) {
+	mux := http.NewServeMux()
+	ts := httptest.NewServer(mux)
+	defer ts.Close()
+
+	mux.HandleFunc("/api/v3/series", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("tvdbId") == "" {
+			w.Write([]byte(`[{"id":1,"tvdbId":100,"title":"That Show","year":2020},{"id":2,"tvdbId":200,"title":"Other Show","year":2021}]`))
+			return
+		}
+
+		assert.Equal(t, "100", r.URL.Query().Get("tvdbId"))
+		w.Write([]byte(`[{"id":1,"tvdbId":100,"title":"That Show","year":2020,"seasons":[{"seasonNumber":1,"monitored":true,"statistics":{"episodeFileCount":8,"episodeCount":10,"totalEpisodeCount":10}}]}]`))
+	})
+	mux.HandleFunc("/api/v3/episodefile", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "1", r.URL.Query().Get("seriesId"))
+		w.Write([]byte(`[{"id":10,"seriesId":1,"seasonNumber":1,"relativePath":"Season 01/That.Show.S01E01.mkv"}]`))
+	})
+
+	c := newTestClient(ts.URL)
+
+	all, err := c.Series(context.Background(), 0)
+	assert.NoError(t, err)
+	assert.Len(t, all, 2)
+
+	series, err := c.Series(context.Background(), 100)
+	assert.NoError(t, err)
+	assert.Equal(t, []Series{{ID: 1, TvdbID: 100, Title: "That Show", Year: 2020, Seasons: []Season{
+		{SeasonNumber: 1, Monitored: true, Statistics: SeasonStatistics{EpisodeFileCount: 8, EpisodeCount: 10, TotalEpisodeCount: 10}},
+	}}}, series)
+
+	files, err := c.EpisodeFiles(context.Background(), 1)
+	assert.NoError(t, err)
+	assert.Equal(t, []EpisodeFile{{ID: 10, SeriesID: 1, SeasonNumber: 1, RelativePath: "Season 01/That.Show.S01E01.mkv"}}, files)
+}
@@ -53,6 +53,11 @@ func NewClient(url string, apiKey string) BTNClient {
 	return c
 }
 
+// WithHTTPClient replaces the default http client, eg. to send requests through a proxy
+func (c *Client) WithHTTPClient(client *http.Client) {
+	c.client = client
+}
+
 func (c *Client) Do(req *http.Request) (*http.Response, error) {
 	ctx := context.Background()
 	err := c.Ratelimiter.Wait(ctx) // This is a blocking call. Honors the rate limit
@@ -0,0 +1,327 @@
+// Package expr evaluates small boolean expressions over named values, eg.
+//
+//	release.Size > 10GB && "internal" in release.Tags
+//
+// The grammar in EBNF, from lowest to highest precedence:
+//
+//	expression = and { "||" and } .
+//	and        = not { "&&" not } .
+//	not        = "!" not | comparison .
+//	comparison = primary [ compare primary ] .
+//	compare    = "==" | "!=" | "<" | "<=" | ">" | ">=" | "in" | "matches" .
+//	primary    = number | size | string | "true" | "false" | identifier | list | "(" expression ")" .
+//	list       = "[" [ primary { "," primary } ] "]" .
+//
+//	identifier = ( letter | "_" ) { letter | digit | "_" | "." } .
+//	number     = digit { digit | "." } .
+//	size       = number letter { letter } .
+//	string     = `"` { char } `"` | "'" { char } "'" .
+//
+// Whitespace between tokens is ignored. The identifiers true, false, in and matches are reserved.
+// Numbers are float64, sizes are a number with a unit like 10GB or 700MiB and evaluate to bytes.
+// In strings a backslash takes the next character literally, so "\"" is a quote and "\\s" is \s.
+// Comparisons don't chain, a < b < c is an error.
+//
+// Numbers compare with every operator, strings and booleans only with == and !=.
+// Strings compare case-insensitive. "in" checks if a string is in a list of strings,
+// or if it is a substring of another string. "matches" checks a string against a
+// case-insensitive regular expression. && and || need booleans and short circuit,
+// and the whole expression must result in a boolean.
+package expr
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/autobrr/autobrr/pkg/errors"
+)
+
+// Env holds the values of the identifiers, values are bool, float64, string or []string
+type Env map[string]interface{}
+
+// Program is a parsed expression
+type Program struct {
+	source string
+	root   node
+}
+
+// Compile parses the expression, identifiers are resolved when it is evaluated
+func Compile(source string) (*Program, error) {
+	tokens, err := lex(source)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &parser{tokens: tokens}
+
+	root, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+
+	if t := p.peek(); t.kind != tokenEOF {
+		return nil, errors.New("unexpected %q at position %d", t.text, t.pos)
+	}
+
+	return &Program{source: source, root: root}, nil
+}
+
+func (p *Program) String() string {
+	return p.source
+}
+
+// Eval evaluates the expression, it must result in a boolean
+func (p *Program) Eval(env Env) (bool, error) {
+	v, err := p.root.eval(env)
+	if err != nil {
+		return false, err
+	}
+
+	b, ok := v.(bool)
+	if !ok {
+		return false, errors.New("expression is not a condition, got %v", typeName(v))
+	}
+
+	return b, nil
+}
+
+// Eval compiles and evaluates the expression
+func Eval(source string, env Env) (bool, error) {
+	p, err := Compile(source)
+	if err != nil {
+		return false, err
+	}
+
+	return p.Eval(env)
+}
+
+type node interface {
+	eval(env Env) (interface{}, error)
+}
+
+type literal struct{ value interface{} }
+
+func (n literal) eval(Env) (interface{}, error) {
+	return n.value, nil
+}
+
+type ident struct{ name string }
+
+func (n ident) eval(env Env) (interface{}, error) {
+	v, ok := env[n.name]
+	if !ok {
+		return nil, errors.New("unknown identifier %v", n.name)
+	}
+
+	switch t := v.(type) {
+	case int:
+		return float64(t), nil
+	case int64:
+		return float64(t), nil
+	case uint64:
+		return float64(t), nil
+	}
+
+	return v, nil
+}
+
+type list struct{ items []node }
+
+func (n list) eval(env Env) (interface{}, error) {
+	values := make([]string, 0, len(n.items))
+	for _, item := range n.items {
+		v, err := item.eval(env)
+		if err != nil {
+			return nil, err
+		}
+
+		s, ok := v.(string)
+		if !ok {
+			return nil, errors.New("lists can only hold strings, got %v", typeName(v))
+		}
+		values = append(values, s)
+	}
+
+	return values, nil
+}
+
+type not struct{ operand node }
+
+func (n not) eval(env Env) (interface{}, error) {
+	v, err := n.operand.eval(env)
+	if err != nil {
+		return nil, err
+	}
+
+	b, ok := v.(bool)
+	if !ok {
+		return nil, errors.New("! needs a boolean, got %v", typeName(v))
+	}
+
+	return !b, nil
+}
+
+type logical struct {
+	op          string
+	left, right node
+}
+
+func (n logical) eval(env Env) (interface{}, error) {
+	left, err := evalBool(n.left, env, n.op)
+	if err != nil {
+		return nil, err
+	}
+
+	// short circuit
+	if n.op == "&&" && !left || n.op == "||" && left {
+		return left, nil
+	}
+
+	return evalBool(n.right, env, n.op)
+}
+
+func evalBool(n node, env Env, op string) (bool, error) {
+	v, err := n.eval(env)
+	if err != nil {
+		return false, err
+	}
+
+	b, ok := v.(bool)
+	if !ok {
+		return false, errors.New("%v needs booleans, got %v", op, typeName(v))
+	}
+
+	return b, nil
+}
+
+type compare struct {
+	op          string
+	left, right node
+}
+
+func (n compare) eval(env Env) (interface{}, error) {
+	left, err := n.left.eval(env)
+	if err != nil {
+		return nil, err
+	}
+
+	right, err := n.right.eval(env)
+	if err != nil {
+		return nil, err
+	}
+
+	switch n.op {
+	case "in":
+		return in(left, right)
+	case "matches":
+		return matches(left, right)
+	}
+
+	switch l := left.(type) {
+	case float64:
+		r, ok := right.(float64)
+		if !ok {
+			return nil, mismatch(n.op, left, right)
+		}
+
+		switch n.op {
+		case "==":
+			return l == r, nil
+		case "!=":
+			return l != r, nil
+		case "<":
+			return l < r, nil
+		case "<=":
+			return l <= r, nil
+		case ">":
+			return l > r, nil
+		case ">=":
+			return l >= r, nil
+		}
+
+	case string:
+		r, ok := right.(string)
+		if !ok {
+			return nil, mismatch(n.op, left, right)
+		}
+
+		switch n.op {
+		case "==":
+			return strings.EqualFold(l, r), nil
+		case "!=":
+			return !strings.EqualFold(l, r), nil
+		}
+
+	case bool:
+		r, ok := right.(bool)
+		if !ok {
+			return nil, mismatch(n.op, left, right)
+		}
+
+		switch n.op {
+		case "==":
+			return l == r, nil
+		case "!=":
+			return l != r, nil
+		}
+	}
+
+	return nil, mismatch(n.op, left, right)
+}
+
+func in(left, right interface{}) (interface{}, error) {
+	l, ok := left.(string)
+	if !ok {
+		return nil, mismatch("in", left, right)
+	}
+
+	switch r := right.(type) {
+	case []string:
+		for _, v := range r {
+			if strings.EqualFold(l, v) {
+				return true, nil
+			}
+		}
+		return false, nil
+
+	case string:
+		return strings.Contains(strings.ToLower(r), strings.ToLower(l)), nil
+	}
+
+	return nil, mismatch("in", left, right)
+}
+
+func matches(left, right interface{}) (interface{}, error) {
+	l, lok := left.(string)
+	r, rok := right.(string)
+	if !lok || !rok {
+		return nil, mismatch("matches", left, right)
+	}
+
+	rxp, err := regexp.Compile("(?i)" + r)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid pattern: %v", r)
+	}
+
+	return rxp.MatchString(l), nil
+}
+
+func mismatch(op string, left, right interface{}) error {
+	return errors.New("can't use %v with %v and %v", op, typeName(left), typeName(right))
+}
+
+func typeName(v interface{}) string {
+	switch v.(type) {
+	case bool:
+		return "boolean"
+	case float64:
+		return "number"
+	case string:
+		return "string"
+	case []string:
+		return "list"
+	}
+
+	return fmt.Sprintf("%T", v)
+}
@@ -0,0 +1,115 @@
+package expr
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEval(t *testing.T) {
+	env := Env{
+		"release.Size":       uint64(12_000_000_000),
+		"release.Tags":       []string{"Internal", "FreeLeech"},
+		"release.Resolution": "1080p",
+		"release.Title":      "That Show",
+		"release.Season":     2,
+		"release.Freeleech":  true,
+	}
+
+	tests := []struct {
+		name    string
+		expr    string
+		want    bool
+		wantErr bool
+	}{
+		{name: "size_and_tag", expr: `release.Size > 10GB && "internal" in release.Tags`, want: true},
+		{name: "size_binary_unit", expr: `release.Size < 10GiB`, want: false},
+		{name: "or", expr: `release.Resolution == "2160p" || release.Resolution == "1080P"`, want: true},
+		{name: "not_equal", expr: `release.Resolution != '1080p'`, want: false},
+		{name: "in_list", expr: `release.Resolution in ["720p", "1080p"]`, want: true},
+		{name: "substring", expr: `"show" in release.Title`, want: true},
+		{name: "matches", expr: `release.Title matches "^that\\s"`, want: true},
+		{name: "number", expr: `release.Season >= 2 && release.Season <= 2.5`, want: true},
+		{name: "bool", expr: `release.Freeleech`, want: true},
+		{name: "negate", expr: `!(release.Freeleech && release.Season == 2)`, want: false},
+		{name: "precedence", expr: `true || false && false`, want: true},
+		{name: "short_circuit", expr: `false && release.Missing`, want: false},
+		{name: "unknown_identifier", expr: `release.Missing == 1`, wantErr: true},
+		{name: "type_mismatch", expr: `release.Size > "big"`, wantErr: true},
+		{name: "string_order", expr: `release.Title < "b"`, wantErr: true},
+		{name: "not_a_condition", expr: `release.Size`, wantErr: true},
+		{name: "invalid_pattern", expr: `release.Title matches "("`, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Eval(tt.expr, env)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestCompile_errors(t *testing.T) {
+	tests := []string{
+		``,
+		`release.Size >`,
+		`(release.Size > 1`,
+		`"unterminated`,
+		`release.Size > 10XB`,
+		`release.Size > 1 1`,
+		`release.Tags in ["a" "b"]`,
+		`release.Size # 1`,
+	}
+	for _, tt := range tests {
+		t.Run(tt, func(t *testing.T) {
+			_, err := Compile(tt)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func FuzzParse(f *testing.F) {
+	seeds := []string{
+		`release.Size > 10GB && "internal" in release.Tags`,
+		`release.Resolution in ["720p", "1080p"]`,
+		`release.Title matches "^that\\s"`,
+		`!(release.Freeleech && release.Season == 2)`,
+		`true || false && false`,
+		`release.Size >= 1.5TiB`,
+		`'single' != "double"`,
+		`(release.Size > 1`,
+		`release.Tags in ["a" "b"]`,
+		`"unterminated`,
+	}
+	for _, seed := range seeds {
+		f.Add(seed)
+	}
+
+	env := Env{
+		"release.Size":      uint64(12_000_000_000),
+		"release.Tags":      []string{"Internal", "FreeLeech"},
+		"release.Title":     "That Show",
+		"release.Season":    2,
+		"release.Freeleech": true,
+	}
+
+	f.Fuzz(func(t *testing.T, source string) {
+		p, err := Compile(source)
+		if err != nil {
+			return
+		}
+
+		if p.String() != source {
+			t.Fatalf("program source %q differs from %q", p.String(), source)
+		}
+
+		// evaluating must only ever fail with an error
+		_, _ = p.Eval(env)
+		_, _ = p.Eval(Env{})
+	})
+}
@@ -0,0 +1,310 @@
+package expr
+
+import (
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/autobrr/autobrr/pkg/errors"
+
+	"github.com/dustin/go-humanize"
+)
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenIdent
+	tokenNumber
+	tokenString
+	tokenOp
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	pos  int
+
+	// parsed value of numbers and strings
+	value interface{}
+}
+
+var operators = []string{"||", "&&", "==", "!=", "<=", ">=", "<", ">", "!", "(", ")", "[", "]", ","}
+
+func lex(source string) ([]token, error) {
+	var tokens []token
+
+	runes := []rune(source)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+
+		switch {
+		case unicode.IsSpace(r):
+			i++
+
+		case unicode.IsDigit(r):
+			start := i
+			for i < len(runes) && (unicode.IsDigit(runes[i]) || runes[i] == '.') {
+				i++
+			}
+			number := string(runes[start:i])
+
+			// a unit makes it a size in bytes, eg. 10GB or 700MiB
+			unitStart := i
+			for i < len(runes) && unicode.IsLetter(runes[i]) {
+				i++
+			}
+			unit := string(runes[unitStart:i])
+
+			var value float64
+			if unit != "" {
+				size, err := humanize.ParseBytes(number + unit)
+				if err != nil {
+					return nil, errors.New("invalid size %q at position %d", number+unit, start)
+				}
+				value = float64(size)
+			} else {
+				v, err := strconv.ParseFloat(number, 64)
+				if err != nil {
+					return nil, errors.New("invalid number %q at position %d", number, start)
+				}
+				value = v
+			}
+
+			tokens = append(tokens, token{kind: tokenNumber, text: string(runes[start:i]), pos: start, value: value})
+
+		case unicode.IsLetter(r) || r == '_':
+			start := i
+			for i < len(runes) && (unicode.IsLetter(runes[i]) || unicode.IsDigit(runes[i]) || runes[i] == '_' || runes[i] == '.') {
+				i++
+			}
+
+			tokens = append(tokens, token{kind: tokenIdent, text: string(runes[start:i]), pos: start})
+
+		case r == '"' || r == '\'':
+			start := i
+			i++
+
+			var sb strings.Builder
+			closed := false
+			for i < len(runes) {
+				c := runes[i]
+				if c == '\\' && i+1 < len(runes) {
+					sb.WriteRune(runes[i+1])
+					i += 2
+					continue
+				}
+				i++
+				if c == r {
+					closed = true
+					break
+				}
+				sb.WriteRune(c)
+			}
+
+			if !closed {
+				return nil, errors.New("unterminated string at position %d", start)
+			}
+
+			tokens = append(tokens, token{kind: tokenString, text: string(runes[start:i]), pos: start, value: sb.String()})
+
+		default:
+			matched := false
+			for _, op := range operators {
+				if strings.HasPrefix(string(runes[i:]), op) {
+					tokens = append(tokens, token{kind: tokenOp, text: op, pos: i})
+					i += len([]rune(op))
+					matched = true
+					break
+				}
+			}
+
+			if !matched {
+				return nil, errors.New("unexpected %q at position %d", string(r), i)
+			}
+		}
+	}
+
+	return append(tokens, token{kind: tokenEOF, pos: len(runes)}), nil
+}
+
+type parser struct {
+	tokens []token
+	pos    int
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	t := p.tokens[p.pos]
+	if t.kind != tokenEOF {
+		p.pos++
+	}
+	return t
+}
+
+func (p *parser) isOp(text string) bool {
+	t := p.peek()
+	return t.kind == tokenOp && t.text == text
+}
+
+func (p *parser) expect(text string) error {
+	t := p.next()
+	if t.kind != tokenOp || t.text != text {
+		return unexpected(t, text)
+	}
+	return nil
+}
+
+// parseOr parses: and ("||" and)*
+func (p *parser) parseOr() (node, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.isOp("||") {
+		p.next()
+
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+
+		left = logical{op: "||", left: left, right: right}
+	}
+
+	return left, nil
+}
+
+// parseAnd parses: not ("&&" not)*
+func (p *parser) parseAnd() (node, error) {
+	left, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.isOp("&&") {
+		p.next()
+
+		right, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+
+		left = logical{op: "&&", left: left, right: right}
+	}
+
+	return left, nil
+}
+
+// parseNot parses: "!" not | comparison
+func (p *parser) parseNot() (node, error) {
+	if p.isOp("!") {
+		p.next()
+
+		operand, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+
+		return not{operand: operand}, nil
+	}
+
+	return p.parseComparison()
+}
+
+// parseComparison parses: primary (op primary)?
+func (p *parser) parseComparison() (node, error) {
+	left, err := p.parsePrimary()
+	if err != nil {
+		return nil, err
+	}
+
+	t := p.peek()
+
+	var op string
+	switch {
+	case t.kind == tokenOp && (t.text == "==" || t.text == "!=" || t.text == "<" || t.text == "<=" || t.text == ">" || t.text == ">="):
+		op = t.text
+	case t.kind == tokenIdent && (t.text == "in" || t.text == "matches"):
+		op = t.text
+	default:
+		return left, nil
+	}
+
+	p.next()
+
+	right, err := p.parsePrimary()
+	if err != nil {
+		return nil, err
+	}
+
+	return compare{op: op, left: left, right: right}, nil
+}
+
+func (p *parser) parsePrimary() (node, error) {
+	t := p.next()
+
+	switch t.kind {
+	case tokenNumber, tokenString:
+		return literal{value: t.value}, nil
+
+	case tokenIdent:
+		switch t.text {
+		case "true":
+			return literal{value: true}, nil
+		case "false":
+			return literal{value: false}, nil
+		case "in", "matches":
+			return nil, unexpected(t, "a value")
+		}
+
+		return ident{name: t.text}, nil
+
+	case tokenOp:
+		switch t.text {
+		case "(":
+			n, err := p.parseOr()
+			if err != nil {
+				return nil, err
+			}
+
+			if err := p.expect(")"); err != nil {
+				return nil, err
+			}
+
+			return n, nil
+
+		case "[":
+			var items []node
+			for !p.isOp("]") {
+				if len(items) > 0 {
+					if err := p.expect(","); err != nil {
+						return nil, err
+					}
+				}
+
+				item, err := p.parsePrimary()
+				if err != nil {
+					return nil, err
+				}
+				items = append(items, item)
+			}
+			p.next()
+
+			return list{items: items}, nil
+		}
+	}
+
+	return nil, unexpected(t, "a value")
+}
+
+func unexpected(t token, want string) error {
+	if t.kind == tokenEOF {
+		return errors.New("unexpected end of expression, expected %v", want)
+	}
+	return errors.New("unexpected %q at position %d, expected %v", t.text, t.pos, want)
+}
@@ -46,6 +46,11 @@ func NewClient(url string, apiKey string) Client {
 	return c
 }
 
+// WithHTTPClient replaces the default http client, eg. to send requests through a proxy
+func (c *client) WithHTTPClient(client *http.Client) {
+	c.client = client
+}
+
 type Group struct {
 	BbWikiBody   string   `json:"bbWikiBody"`
 	WikiBody     string   `json:"wikiBody"`
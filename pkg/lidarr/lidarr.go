@@ -7,8 +7,8 @@ import (
 	"log"
 	"net/http"
 	"strings"
-	"time"
 
+	"github.com/autobrr/autobrr/pkg/arr"
 	"github.com/autobrr/autobrr/pkg/errors"
 )
 
@@ -27,32 +27,42 @@ type Config struct {
 type Client interface {
 	Test() (*SystemStatusResponse, error)
 	Push(release Release) ([]string, error)
+	GetQueue() ([]arr.QueueRecord, error)
+	GetHistory(pageSize int) ([]arr.HistoryRecord, error)
+	GetArtists() ([]Artist, error)
+	GetAlbums(artistID int) ([]Album, error)
 }
 
 type client struct {
 	config Config
-	http   *http.Client
+	arr    *arr.Client
 
 	Log *log.Logger
 }
 
 // New create new lidarr client
 func New(config Config) Client {
-
-	httpClient := &http.Client{
-		Timeout: time.Second * 30,
-	}
-
 	c := &client{
 		config: config,
-		http:   httpClient,
 		Log:    config.Log,
 	}
 
 	if config.Log == nil {
+		// if no provided logger then use io.Discard
 		c.Log = log.New(io.Discard, "", log.LstdFlags)
 	}
 
+	c.arr = arr.New(arr.Config{
+		Name:       "lidarr",
+		Hostname:   config.Hostname,
+		APIKey:     config.APIKey,
+		APIVersion: "v1",
+		BasicAuth:  config.BasicAuth,
+		Username:   config.Username,
+		Password:   config.Password,
+		Log:        c.Log,
+	})
+
 	return c
 }
 
@@ -64,6 +74,8 @@ type Release struct {
 	DownloadProtocol string `json:"downloadProtocol"`
 	Protocol         string `json:"protocol"`
 	PublishDate      string `json:"publishDate"`
+	DownloadClientId int32  `json:"downloadClientId,omitempty"`
+	DownloadClient   string `json:"downloadClient,omitempty"`
 }
 
 type PushResponse struct {
@@ -80,6 +92,19 @@ type BadRequestResponse struct {
 	Severity       string `json:"severity"`
 }
 
+type Artist struct {
+	ID         int    `json:"id"`
+	ArtistName string `json:"artistName"`
+	Monitored  bool   `json:"monitored"`
+}
+
+type Album struct {
+	ID        int    `json:"id"`
+	ArtistID  int    `json:"artistId"`
+	Title     string `json:"title"`
+	Monitored bool   `json:"monitored"`
+}
+
 type SystemStatusResponse struct {
 	Version string `json:"version"`
 }
@@ -142,3 +167,39 @@ func (c *client) Push(release Release) ([]string, error) {
 
 	return nil, nil
 }
+
+func (c *client) GetArtists() ([]Artist, error) {
+	status, res, err := c.get("artist")
+	if err != nil {
+		return nil, errors.Wrap(err, "lidarr client get error")
+	}
+
+	if status != http.StatusOK {
+		return nil, errors.New("lidarr artist lookup unexpected status: %v", status)
+	}
+
+	artists := make([]Artist, 0)
+	if err := json.Unmarshal(res, &artists); err != nil {
+		return nil, errors.Wrap(err, "lidarr client error json unmarshal")
+	}
+
+	return artists, nil
+}
+
+func (c *client) GetAlbums(artistID int) ([]Album, error) {
+	status, res, err := c.get(fmt.Sprintf("album?artistId=%d", artistID))
+	if err != nil {
+		return nil, errors.Wrap(err, "lidarr client get error")
+	}
+
+	if status != http.StatusOK {
+		return nil, errors.New("lidarr album lookup unexpected status: %v", status)
+	}
+
+	albums := make([]Album, 0)
+	if err := json.Unmarshal(res, &albums); err != nil {
+		return nil, errors.Wrap(err, "lidarr client error json unmarshal")
+	}
+
+	return albums, nil
+}
@@ -179,3 +179,38 @@ func Test_client_Test(t *testing.T) {
 		})
 	}
 }
+
+func Test_client_GetArtistsAlbums(t *testing.T) {
+	// disable logger
+	zerolog.SetGlobalLevel(zerolog.Disabled)
+
+	mux := http.NewServeMux()
+	ts := httptest.NewServer(mux)
+	defer ts.Close()
+
+	mux.HandleFunc("/api/v1/artist", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`[{"id":1,"artistName":"JR Get Money","monitored":true},{"id":2,"artistName":"Some Band","monitored":false}]`))
+	})
+	mux.HandleFunc("/api/v1/album", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("artistId") != "1" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`[{"id":10,"artistId":1,"title":"Nobody But You","monitored":true}]`))
+	})
+
+	c := New(Config{Hostname: ts.URL})
+
+	artists, err := c.GetArtists()
+	assert.NoError(t, err)
+	assert.Equal(t, []Artist{{ID: 1, ArtistName: "JR Get Money", Monitored: true}, {ID: 2, ArtistName: "Some Band", Monitored: false}}, artists)
+
+	albums, err := c.GetAlbums(1)
+	assert.NoError(t, err)
+	assert.Equal(t, []Album{{ID: 10, ArtistID: 1, Title: "Nobody But You", Monitored: true}}, albums)
+
+	_, err = c.GetAlbums(2)
+	assert.Error(t, err)
+}
@@ -0,0 +1,138 @@
+// Package porla is a small client for the Porla JSON-RPC api.
+package porla
+
+import (
+	"crypto/tls"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/autobrr/autobrr/pkg/errors"
+	"github.com/autobrr/autobrr/pkg/jsonrpc"
+)
+
+type Config struct {
+	Hostname      string
+	AuthToken     string
+	TLSSkipVerify bool
+	Timeout       time.Duration
+}
+
+type Client struct {
+	config Config
+	rpc    jsonrpc.Client
+}
+
+func New(config Config) *Client {
+	timeout := config.Timeout
+	if timeout == 0 {
+		timeout = 30 * time.Second
+	}
+
+	httpClient := &http.Client{
+		Timeout: timeout,
+		Transport: &http.Transport{
+			Proxy:           http.ProxyFromEnvironment,
+			TLSClientConfig: &tls.Config{InsecureSkipVerify: config.TLSSkipVerify},
+		},
+	}
+
+	headers := map[string]string{}
+	if config.AuthToken != "" {
+		headers["Authorization"] = "Bearer " + config.AuthToken
+	}
+
+	return &Client{
+		config: config,
+		rpc: jsonrpc.NewClientWithOpts(strings.TrimRight(config.Hostname, "/")+"/api/v1/jsonrpc", &jsonrpc.ClientOpts{
+			HTTPClient: httpClient,
+			Headers:    headers,
+		}),
+	}
+}
+
+type SysVersions struct {
+	Porla struct {
+		Version   string `json:"version"`
+		Commitish string `json:"commitish"`
+		Branch    string `json:"branch"`
+	} `json:"porla"`
+}
+
+// SysVersions returns the version of porla and its libraries, used to test the connection
+func (c *Client) SysVersions() (*SysVersions, error) {
+	var versions SysVersions
+	if err := c.call("sys.versions", nil, &versions); err != nil {
+		return nil, err
+	}
+
+	return &versions, nil
+}
+
+// TorrentsAddReq adds a torrent by magnet uri or by base64 encoded torrent file in Ti
+type TorrentsAddReq struct {
+	Preset    string   `json:"preset,omitempty"`
+	SavePath  string   `json:"save_path,omitempty"`
+	Tags      []string `json:"tags,omitempty"`
+	Ti        string   `json:"ti,omitempty"`
+	MagnetURI string   `json:"magnet_uri,omitempty"`
+}
+
+// TorrentsAddRes holds the v1 and v2 info hash of the added torrent, a missing hash is null
+type TorrentsAddRes struct {
+	InfoHash []*string `json:"info_hash"`
+}
+
+// Hash returns the v1 info hash, or the v2 hash for v2 only torrents
+func (r *TorrentsAddRes) Hash() string {
+	for _, hash := range r.InfoHash {
+		if hash != nil && *hash != "" {
+			return *hash
+		}
+	}
+
+	return ""
+}
+
+func (c *Client) TorrentsAdd(req *TorrentsAddReq) (*TorrentsAddRes, error) {
+	if req.Ti == "" && req.MagnetURI == "" {
+		return nil, errors.New("torrents.add needs a torrent file or magnet uri")
+	}
+
+	var res TorrentsAddRes
+	if err := c.call("torrents.add", req, &res); err != nil {
+		return nil, err
+	}
+
+	return &res, nil
+}
+
+func (c *Client) call(method string, params interface{}, result interface{}) error {
+	var (
+		res *jsonrpc.RPCResponse
+		err error
+	)
+
+	if params == nil {
+		res, err = c.rpc.Call(method)
+	} else {
+		res, err = c.rpc.Call(method, params)
+	}
+	if err != nil {
+		return errors.Wrap(err, "%v call failed", method)
+	}
+
+	if res.Error != nil {
+		return errors.Wrap(res.Error, "%v failed", method)
+	}
+
+	if result == nil {
+		return nil
+	}
+
+	if err := res.GetObject(result); err != nil {
+		return errors.Wrap(err, "could not read %v result", method)
+	}
+
+	return nil
+}
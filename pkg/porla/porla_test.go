@@ -0,0 +1,102 @@
+package porla
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/autobrr/autobrr/pkg/jsonrpc"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClient_TorrentsAdd(t *testing.T) {
+	var got struct {
+		Method string         `json:"method"`
+		Params TorrentsAddReq `json:"params"`
+	}
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/porla/api/v1/jsonrpc", r.URL.Path)
+
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		json.NewDecoder(r.Body).Decode(&got)
+		w.Write([]byte(`{"jsonrpc":"2.0","id":1,"result":{"info_hash":["abc123",null]}}`))
+	}))
+	defer srv.Close()
+
+	c := New(Config{Hostname: srv.URL + "/porla/", AuthToken: "secret"})
+
+	res, err := c.TorrentsAdd(&TorrentsAddReq{
+		Preset:    "tv",
+		SavePath:  "/data/tv",
+		Tags:      []string{"autobrr", "tv"},
+		MagnetURI: "magnet:?xt=urn:btih:abc123",
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "abc123", res.Hash())
+
+	assert.Equal(t, "torrents.add", got.Method)
+	assert.Equal(t, TorrentsAddReq{
+		Preset:    "tv",
+		SavePath:  "/data/tv",
+		Tags:      []string{"autobrr", "tv"},
+		MagnetURI: "magnet:?xt=urn:btih:abc123",
+	}, got.Params)
+}
+
+func TestClient_TorrentsAdd_errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		handler http.HandlerFunc
+		req     *TorrentsAddReq
+	}{
+		{
+			name:    "unauthorized",
+			handler: func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusUnauthorized) },
+			req:     &TorrentsAddReq{Ti: "ZDEyOmZha2U="},
+		},
+		{
+			name: "rpc_error",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.Write([]byte(`{"jsonrpc":"2.0","id":1,"error":{"code":-3,"message":"Failed to parse torrent"}}`))
+			},
+			req: &TorrentsAddReq{Ti: "ZDEyOmZha2U="},
+		},
+		{
+			name:    "no_torrent",
+			handler: func(w http.ResponseWriter, r *http.Request) { t.Error("unexpected request") },
+			req:     &TorrentsAddReq{SavePath: "/data"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(tt.handler)
+			defer srv.Close()
+
+			_, err := New(Config{Hostname: srv.URL}).TorrentsAdd(tt.req)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestClient_SysVersions(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req jsonrpc.RPCRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		assert.Equal(t, "sys.versions", req.Method)
+		assert.Nil(t, req.Params)
+
+		w.Write([]byte(`{"jsonrpc":"2.0","id":1,"result":{"porla":{"version":"0.37.0","commitish":"c0ffee","branch":"main"}}}`))
+	}))
+	defer srv.Close()
+
+	versions, err := New(Config{Hostname: srv.URL}).SysVersions()
+	assert.NoError(t, err)
+	assert.Equal(t, "0.37.0", versions.Porla.Version)
+}
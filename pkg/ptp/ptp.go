@@ -47,6 +47,11 @@ func NewClient(url string, apiUser string, apiKey string) PTPClient {
 	return c
 }
 
+// WithHTTPClient replaces the default http client, eg. to send requests through a proxy
+func (c *Client) WithHTTPClient(client *http.Client) {
+	c.client = client
+}
+
 type TorrentResponse struct {
 	Page          string    `json:"Page"`
 	Result        string    `json:"Result"`
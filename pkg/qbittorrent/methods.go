@@ -102,6 +102,32 @@ func (c *Client) GetTorrentsFilter(filter TorrentFilter) ([]Torrent, error) {
 	return torrents, nil
 }
 
+// GetTorrentsByHashes returns the torrents in the client with any of the hashes
+func (c *Client) GetTorrentsByHashes(hashes []string) ([]Torrent, error) {
+	opts := map[string]string{
+		"hashes": strings.Join(hashes, "|"),
+	}
+
+	resp, err := c.get("torrents/info", opts)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not get torrents by hashes: %v", hashes)
+	}
+
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not read body")
+	}
+
+	var torrents []Torrent
+	if err := json.Unmarshal(body, &torrents); err != nil {
+		return nil, errors.Wrap(err, "could not unmarshal body")
+	}
+
+	return torrents, nil
+}
+
 func (c *Client) GetTorrentsActiveDownloads() ([]Torrent, error) {
 	var filter = TorrentFilterDownloading
 
@@ -7,8 +7,8 @@ import (
 	"log"
 	"net/http"
 	"strings"
-	"time"
 
+	"github.com/autobrr/autobrr/pkg/arr"
 	"github.com/autobrr/autobrr/pkg/errors"
 )
 
@@ -27,24 +27,21 @@ type Config struct {
 type Client interface {
 	Test() (*SystemStatusResponse, error)
 	Push(release Release) ([]string, error)
+	GetQueue() ([]arr.QueueRecord, error)
+	GetHistory(pageSize int) ([]arr.HistoryRecord, error)
+	GetMovies() ([]arr.Movie, error)
 }
 
 type client struct {
 	config Config
-	http   *http.Client
+	arr    *arr.Client
 
 	Log *log.Logger
 }
 
 func New(config Config) Client {
-
-	httpClient := &http.Client{
-		Timeout: time.Second * 30,
-	}
-
 	c := &client{
 		config: config,
-		http:   httpClient,
 		Log:    config.Log,
 	}
 
@@ -52,6 +49,17 @@ func New(config Config) Client {
 		c.Log = log.New(io.Discard, "", log.LstdFlags)
 	}
 
+	c.arr = arr.New(arr.Config{
+		Name:       "radarr",
+		Hostname:   config.Hostname,
+		APIKey:     config.APIKey,
+		APIVersion: "v3",
+		BasicAuth:  config.BasicAuth,
+		Username:   config.Username,
+		Password:   config.Password,
+		Log:        c.Log,
+	})
+
 	return c
 }
 
@@ -63,8 +71,21 @@ type Release struct {
 	DownloadProtocol string `json:"downloadProtocol"`
 	Protocol         string `json:"protocol"`
 	PublishDate      string `json:"publishDate"`
+	DownloadClientId int32  `json:"downloadClientId,omitempty"`
+	DownloadClient   string `json:"downloadClient,omitempty"`
+	IndexerFlags     int    `json:"indexerFlags,omitempty"`
 }
 
+// Indexer flags are a bitmask of release flags, radarr uses them in custom formats
+const (
+	IndexerFlagFreeleech   = 1
+	IndexerFlagHalfleech   = 2
+	IndexerFlagInternal    = 32
+	IndexerFlagScene       = 128
+	IndexerFlagFreeleech75 = 256
+	IndexerFlagFreeleech25 = 512
+)
+
 type PushResponse struct {
 	Approved     bool     `json:"approved"`
 	Rejected     bool     `json:"rejected"`
@@ -44,6 +44,11 @@ func NewClient(url string, apiKey string) REDClient {
 	return c
 }
 
+// WithHTTPClient replaces the default http client, eg. to send requests through a proxy
+func (c *Client) WithHTTPClient(client *http.Client) {
+	c.client = client
+}
+
 type TorrentDetailsResponse struct {
 	Status   string `json:"status"`
 	Response struct {
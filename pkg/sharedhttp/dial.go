@@ -0,0 +1,140 @@
+package sharedhttp
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"encoding/base64"
+	"net"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/autobrr/autobrr/pkg/errors"
+
+	"golang.org/x/net/proxy"
+)
+
+// ResolveProxy returns the proxy url for proxy, the default proxy when it is empty.
+// Nil means the connection is made directly.
+func ResolveProxy(proxy string) (*url.URL, error) {
+	if proxy != "" {
+		return ParseProxy(proxy)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	return defaultProxy, nil
+}
+
+// DialContext opens a tcp connection to address through the proxy, or directly when proxy is nil.
+// It is for connections that are not http requests, http and https proxies are asked to CONNECT to address.
+func DialContext(ctx context.Context, proxyURL *url.URL, address string) (net.Conn, error) {
+	dialer := &net.Dialer{}
+
+	if proxyURL == nil {
+		return dialer.DialContext(ctx, "tcp", address)
+	}
+
+	switch proxyURL.Scheme {
+	case "socks5":
+		d, err := proxy.FromURL(proxyURL, dialer)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not create socks5 dialer")
+		}
+
+		conn, err := d.(proxy.ContextDialer).DialContext(ctx, "tcp", address)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not connect to %v through socks5 proxy %v", address, proxyURL.Host)
+		}
+
+		return conn, nil
+
+	case "http", "https":
+		return dialConnect(ctx, dialer, proxyURL, address)
+	}
+
+	return nil, errors.New("unsupported proxy scheme %q", proxyURL.Scheme)
+}
+
+// dialConnect connects to address with a CONNECT request to the http proxy
+func dialConnect(ctx context.Context, dialer *net.Dialer, proxyURL *url.URL, address string) (net.Conn, error) {
+	proxyAddr := proxyURL.Host
+	if proxyURL.Port() == "" {
+		port := "80"
+		if proxyURL.Scheme == "https" {
+			port = "443"
+		}
+		proxyAddr = net.JoinHostPort(proxyURL.Hostname(), port)
+	}
+
+	conn, err := dialer.DialContext(ctx, "tcp", proxyAddr)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not connect to proxy %v", proxyURL.Host)
+	}
+
+	if proxyURL.Scheme == "https" {
+		tlsConn := tls.Client(conn, &tls.Config{ServerName: proxyURL.Hostname()})
+		if err := tlsConn.HandshakeContext(ctx); err != nil {
+			conn.Close()
+			return nil, errors.Wrap(err, "could not connect to proxy %v", proxyURL.Host)
+		}
+		conn = tlsConn
+	}
+
+	// the deadline of ctx covers the CONNECT exchange as well
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+		defer conn.SetDeadline(time.Time{})
+	}
+
+	req := &http.Request{
+		Method: http.MethodConnect,
+		URL:    &url.URL{Opaque: address},
+		Host:   address,
+		Header: http.Header{},
+	}
+
+	if proxyURL.User != nil {
+		password, _ := proxyURL.User.Password()
+		credentials := base64.StdEncoding.EncodeToString([]byte(proxyURL.User.Username() + ":" + password))
+		req.Header.Set("Proxy-Authorization", "Basic "+credentials)
+	}
+
+	if err := req.Write(conn); err != nil {
+		conn.Close()
+		return nil, errors.Wrap(err, "could not send CONNECT to proxy %v", proxyURL.Host)
+	}
+
+	br := bufio.NewReader(conn)
+
+	res, err := http.ReadResponse(br, req)
+	if err != nil {
+		conn.Close()
+		return nil, errors.Wrap(err, "could not read CONNECT response from proxy %v", proxyURL.Host)
+	}
+	res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		conn.Close()
+		return nil, errors.New("proxy %v refused CONNECT to %v: %v", proxyURL.Host, address, res.Status)
+	}
+
+	// the server may have sent data right behind the response, it was read into the buffer already
+	if br.Buffered() > 0 {
+		return &bufferedConn{Conn: conn, r: br}, nil
+	}
+
+	return conn, nil
+}
+
+// bufferedConn reads what is left in the buffer before reading from the connection
+type bufferedConn struct {
+	net.Conn
+	r *bufio.Reader
+}
+
+func (c *bufferedConn) Read(b []byte) (int, error) {
+	return c.r.Read(b)
+}
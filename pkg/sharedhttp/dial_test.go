@@ -0,0 +1,206 @@
+package sharedhttp
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// greetServer greets every connection right away like an irc server does, and echoes the lines it receives
+func greetServer(t *testing.T) net.Listener {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+
+			go func() {
+				defer conn.Close()
+				io.WriteString(conn, "hello\r\n")
+				io.Copy(conn, conn)
+			}()
+		}
+	}()
+
+	return ln
+}
+
+// fakeConnectProxy tunnels CONNECT requests with the expected credentials
+func fakeConnectProxy(t *testing.T, wantAuth string) net.Listener {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+
+			go func() {
+				defer conn.Close()
+
+				req, err := http.ReadRequest(bufio.NewReader(conn))
+				if err != nil {
+					return
+				}
+
+				if req.Method != http.MethodConnect || req.Header.Get("Proxy-Authorization") != wantAuth {
+					io.WriteString(conn, "HTTP/1.1 407 Proxy Authentication Required\r\n\r\n")
+					return
+				}
+
+				target, err := net.Dial("tcp", req.Host)
+				if err != nil {
+					io.WriteString(conn, "HTTP/1.1 502 Bad Gateway\r\n\r\n")
+					return
+				}
+				defer target.Close()
+
+				io.WriteString(conn, "HTTP/1.1 200 Connection established\r\n\r\n")
+
+				go io.Copy(target, conn)
+				io.Copy(conn, target)
+			}()
+		}
+	}()
+
+	return ln
+}
+
+// fakeSocks5Proxy tunnels socks5 connect requests for ipv4 addresses without authentication
+func fakeSocks5Proxy(t *testing.T) net.Listener {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+
+			go func() {
+				defer conn.Close()
+
+				// version, number of methods, methods
+				greeting := make([]byte, 2)
+				if _, err := io.ReadFull(conn, greeting); err != nil {
+					return
+				}
+				if _, err := io.ReadFull(conn, make([]byte, greeting[1])); err != nil {
+					return
+				}
+				conn.Write([]byte{0x05, 0x00})
+
+				// version, connect, reserved, ipv4, address, port
+				req := make([]byte, 10)
+				if _, err := io.ReadFull(conn, req); err != nil || req[3] != 0x01 {
+					return
+				}
+
+				addr := net.JoinHostPort(net.IP(req[4:8]).String(), strconv.Itoa(int(binary.BigEndian.Uint16(req[8:10]))))
+				target, err := net.Dial("tcp", addr)
+				if err != nil {
+					conn.Write([]byte{0x05, 0x05, 0x00, 0x01, 0, 0, 0, 0, 0, 0})
+					return
+				}
+				defer target.Close()
+
+				conn.Write([]byte{0x05, 0x00, 0x00, 0x01, 0, 0, 0, 0, 0, 0})
+
+				go io.Copy(target, conn)
+				io.Copy(conn, target)
+			}()
+		}
+	}()
+
+	return ln
+}
+
+func TestDialContext(t *testing.T) {
+	server := greetServer(t)
+	defer server.Close()
+
+	connectProxy := fakeConnectProxy(t, "Basic dXNlcjpwYXNz")
+	defer connectProxy.Close()
+
+	socksProxy := fakeSocks5Proxy(t)
+	defer socksProxy.Close()
+
+	tests := []struct {
+		name    string
+		proxy   string
+		wantErr string
+	}{
+		{name: "direct", proxy: ""},
+		{name: "http", proxy: "http://user:pass@" + connectProxy.Addr().String()},
+		{name: "http_refused", proxy: "http://user:wrong@" + connectProxy.Addr().String(), wantErr: "407 Proxy Authentication Required"},
+		{name: "socks5", proxy: "socks5://" + socksProxy.Addr().String()},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var proxyURL *url.URL
+			if tt.proxy != "" {
+				var err error
+				proxyURL, err = ParseProxy(tt.proxy)
+				assert.NoError(t, err)
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+
+			conn, err := DialContext(ctx, proxyURL, server.Addr().String())
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			defer conn.Close()
+
+			conn.SetDeadline(time.Now().Add(5 * time.Second))
+
+			// the greeting may arrive together with the CONNECT response
+			r := bufio.NewReader(conn)
+			line, err := r.ReadString('\n')
+			assert.NoError(t, err)
+			assert.Equal(t, "hello\r\n", line)
+
+			io.WriteString(conn, "PING :autobrr\r\n")
+			line, err = r.ReadString('\n')
+			assert.NoError(t, err)
+			assert.Equal(t, "PING :autobrr\r\n", line)
+		})
+	}
+}
+
+func TestResolveProxy(t *testing.T) {
+	u, err := ResolveProxy("")
+	assert.NoError(t, err)
+	assert.Nil(t, u)
+
+	assert.NoError(t, SetDefaultProxy("socks5://127.0.0.1:1080"))
+	defer SetDefaultProxy("")
+
+	u, err = ResolveProxy("")
+	assert.NoError(t, err)
+	assert.Equal(t, "socks5://127.0.0.1:1080", u.String())
+
+	// the proxy of the network or indexer goes first
+	u, err = ResolveProxy("http://127.0.0.1:8080")
+	assert.NoError(t, err)
+	assert.Equal(t, "http://127.0.0.1:8080", u.String())
+}
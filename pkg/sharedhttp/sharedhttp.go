@@ -0,0 +1,107 @@
+// Package sharedhttp holds http transports shared between clients so connections are reused,
+// one for requests using the default proxy and one per proxy.
+package sharedhttp
+
+import (
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/autobrr/autobrr/pkg/errors"
+)
+
+var (
+	mu           sync.Mutex
+	transports   = map[string]*http.Transport{}
+	defaultProxy *url.URL
+)
+
+// SetDefaultProxy sets the proxy used when no proxy is given, an empty proxy connects directly.
+// It applies to clients created before the change as well.
+func SetDefaultProxy(proxy string) error {
+	var u *url.URL
+	if proxy != "" {
+		var err error
+		if u, err = ParseProxy(proxy); err != nil {
+			return err
+		}
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	defaultProxy = u
+
+	return nil
+}
+
+// proxyFromDefault is the proxy func of the default transport, it looks up the default proxy on every request
+func proxyFromDefault(req *http.Request) (*url.URL, error) {
+	mu.Lock()
+	u := defaultProxy
+	mu.Unlock()
+
+	if u == nil {
+		return http.ProxyFromEnvironment(req)
+	}
+
+	return u, nil
+}
+
+// ParseProxy validates a proxy url, supported schemes are http, https and socks5
+func ParseProxy(proxy string) (*url.URL, error) {
+	u, err := url.Parse(proxy)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid proxy url")
+	}
+
+	switch u.Scheme {
+	case "http", "https", "socks5":
+	default:
+		return nil, errors.New("unsupported proxy scheme %q, use http, https or socks5", u.Scheme)
+	}
+
+	if u.Host == "" {
+		return nil, errors.New("proxy url is missing host")
+	}
+
+	return u, nil
+}
+
+// Transport returns the shared transport for proxy, an empty proxy returns the transport that follows the default proxy.
+// The transport must not be modified, clone it to change settings.
+func Transport(proxy string) (*http.Transport, error) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	if t, ok := transports[proxy]; ok {
+		return t, nil
+	}
+
+	t := http.DefaultTransport.(*http.Transport).Clone()
+
+	if proxy == "" {
+		t.Proxy = proxyFromDefault
+	} else {
+		u, err := ParseProxy(proxy)
+		if err != nil {
+			return nil, err
+		}
+		t.Proxy = http.ProxyURL(u)
+	}
+
+	transports[proxy] = t
+
+	return t, nil
+}
+
+// Client returns a client using the shared transport for proxy
+func Client(proxy string, timeout time.Duration) (*http.Client, error) {
+	t, err := Transport(proxy)
+	if err != nil {
+		return nil, err
+	}
+
+	return &http.Client{Transport: t, Timeout: timeout}, nil
+}
@@ -1,134 +1,31 @@
 package sonarr
 
 import (
-	"bytes"
-	"encoding/json"
-	"io"
-	"net/http"
-	"net/url"
-	"path"
+	"context"
 
-	"github.com/autobrr/autobrr/pkg/errors"
+	"github.com/autobrr/autobrr/pkg/arr"
 )
 
 func (c *client) get(endpoint string) (int, []byte, error) {
-	u, err := url.Parse(c.config.Hostname)
-	u.Path = path.Join(u.Path, "/api/v3/", endpoint)
-	reqUrl := u.String()
-
-	req, err := http.NewRequest(http.MethodGet, reqUrl, http.NoBody)
-	if err != nil {
-		return 0, nil, errors.Wrap(err, "could not build request")
-	}
-
-	if c.config.BasicAuth {
-		req.SetBasicAuth(c.config.Username, c.config.Password)
-	}
-
-	c.setHeaders(req)
-
-	resp, err := c.http.Do(req)
-	if err != nil {
-		return 0, nil, errors.Wrap(err, "sonarr.http.Do(req): %+v", req)
-	}
-
-	defer resp.Body.Close()
-
-	var buf bytes.Buffer
-	if _, err = io.Copy(&buf, resp.Body); err != nil {
-		return resp.StatusCode, nil, errors.Wrap(err, "sonarr.io.Copy")
-	}
-
-	return resp.StatusCode, buf.Bytes(), nil
-}
-
-func (c *client) post(endpoint string, data interface{}) (*http.Response, error) {
-	u, err := url.Parse(c.config.Hostname)
-	u.Path = path.Join(u.Path, "/api/v3/", endpoint)
-	reqUrl := u.String()
-
-	jsonData, err := json.Marshal(data)
-	if err != nil {
-		return nil, errors.Wrap(err, "could not marshal data: %+v", data)
-	}
-
-	req, err := http.NewRequest(http.MethodPost, reqUrl, bytes.NewBuffer(jsonData))
-	if err != nil {
-		return nil, errors.Wrap(err, "could not build request")
-	}
-
-	if c.config.BasicAuth {
-		req.SetBasicAuth(c.config.Username, c.config.Password)
-	}
-
-	req.Header.Add("X-Api-Key", c.config.APIKey)
-	req.Header.Set("Content-Type", "application/json; charset=UTF-8")
-	req.Header.Set("User-Agent", "autobrr")
-
-	res, err := c.http.Do(req)
-	if err != nil {
-		return nil, errors.Wrap(err, "could not make request: %+v", req)
-	}
-
-	// validate response
-	if res.StatusCode == http.StatusUnauthorized {
-		return nil, errors.New("unauthorized: bad credentials")
-	} else if res.StatusCode != http.StatusOK {
-		return nil, errors.New("sonarr: bad request")
-	}
-
-	// return raw response and let the caller handle json unmarshal of body
-	return res, nil
+	return c.arr.Get(context.Background(), endpoint)
 }
 
 func (c *client) postBody(endpoint string, data interface{}) (int, []byte, error) {
-	u, err := url.Parse(c.config.Hostname)
-	u.Path = path.Join(u.Path, "/api/v3/", endpoint)
-	reqUrl := u.String()
-
-	jsonData, err := json.Marshal(data)
-	if err != nil {
-		return 0, nil, errors.Wrap(err, "could not marshal data: %+v", data)
-	}
-
-	req, err := http.NewRequest(http.MethodPost, reqUrl, bytes.NewBuffer(jsonData))
-	if err != nil {
-		return 0, nil, errors.Wrap(err, "could not build request")
-	}
-
-	if c.config.BasicAuth {
-		req.SetBasicAuth(c.config.Username, c.config.Password)
-	}
-
-	c.setHeaders(req)
-
-	resp, err := c.http.Do(req)
-	if err != nil {
-		return 0, nil, errors.Wrap(err, "sonarr.http.Do(req): %+v", req)
-	}
-
-	defer resp.Body.Close()
-
-	var buf bytes.Buffer
-	if _, err = io.Copy(&buf, resp.Body); err != nil {
-		return resp.StatusCode, nil, errors.Wrap(err, "sonarr.io.Copy")
-	}
-
-	if resp.StatusCode == http.StatusBadRequest {
-		return resp.StatusCode, buf.Bytes(), nil
-	} else if resp.StatusCode < 200 || resp.StatusCode > 401 {
-		return resp.StatusCode, buf.Bytes(), errors.New("sonarr: bad request: %v (status: %s): %s", resp.Request.RequestURI, resp.Status, buf.String())
-	}
+	return c.arr.Post(context.Background(), endpoint, data)
+}
 
-	return resp.StatusCode, buf.Bytes(), nil
+func (c *client) GetQueue() ([]arr.QueueRecord, error) {
+	return c.arr.Queue(context.Background(), arr.DefaultQueuePageSize)
 }
 
-func (c *client) setHeaders(req *http.Request) {
-	if req.Body != nil {
-		req.Header.Set("Content-Type", "application/json")
-	}
+func (c *client) GetHistory(pageSize int) ([]arr.HistoryRecord, error) {
+	return c.arr.History(context.Background(), pageSize)
+}
 
-	req.Header.Set("User-Agent", "autobrr")
+func (c *client) GetSeries(tvdbID int) ([]arr.Series, error) {
+	return c.arr.Series(context.Background(), tvdbID)
+}
 
-	req.Header.Set("X-Api-Key", c.config.APIKey)
+func (c *client) GetEpisodeFiles(seriesID int) ([]arr.EpisodeFile, error) {
+	return c.arr.EpisodeFiles(context.Background(), seriesID)
 }
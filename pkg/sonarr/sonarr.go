@@ -6,10 +6,10 @@ import (
 	"io"
 	"net/http"
 	"strings"
-	"time"
 
 	"log"
 
+	"github.com/autobrr/autobrr/pkg/arr"
 	"github.com/autobrr/autobrr/pkg/errors"
 )
 
@@ -28,25 +28,23 @@ type Config struct {
 type Client interface {
 	Test() (*SystemStatusResponse, error)
 	Push(release Release) ([]string, error)
+	GetQueue() ([]arr.QueueRecord, error)
+	GetHistory(pageSize int) ([]arr.HistoryRecord, error)
+	GetSeries(tvdbID int) ([]arr.Series, error)
+	GetEpisodeFiles(seriesID int) ([]arr.EpisodeFile, error)
 }
 
 type client struct {
 	config Config
-	http   *http.Client
+	arr    *arr.Client
 
 	Log *log.Logger
 }
 
 // New create new sonarr client
 func New(config Config) Client {
-
-	httpClient := &http.Client{
-		Timeout: time.Second * 30,
-	}
-
 	c := &client{
 		config: config,
-		http:   httpClient,
 		Log:    config.Log,
 	}
 
@@ -55,6 +53,17 @@ func New(config Config) Client {
 		c.Log = log.New(io.Discard, "", log.LstdFlags)
 	}
 
+	c.arr = arr.New(arr.Config{
+		Name:       "sonarr",
+		Hostname:   config.Hostname,
+		APIKey:     config.APIKey,
+		APIVersion: "v3",
+		BasicAuth:  config.BasicAuth,
+		Username:   config.Username,
+		Password:   config.Password,
+		Log:        c.Log,
+	})
+
 	return c
 }
 
@@ -66,8 +75,21 @@ type Release struct {
 	DownloadProtocol string `json:"downloadProtocol"`
 	Protocol         string `json:"protocol"`
 	PublishDate      string `json:"publishDate"`
+	DownloadClientId int32  `json:"downloadClientId,omitempty"`
+	DownloadClient   string `json:"downloadClient,omitempty"`
+	IndexerFlags     int    `json:"indexerFlags,omitempty"`
 }
 
+// Indexer flags are a bitmask of release flags, sonarr v4 uses them in custom formats and older versions ignore them
+const (
+	IndexerFlagFreeleech   = 1
+	IndexerFlagHalfleech   = 2
+	IndexerFlagInternal    = 8
+	IndexerFlagScene       = 16
+	IndexerFlagFreeleech75 = 32
+	IndexerFlagFreeleech25 = 64
+)
+
 type PushResponse struct {
 	Approved     bool     `json:"approved"`
 	Rejected     bool     `json:"rejected"`
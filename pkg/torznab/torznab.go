@@ -8,6 +8,7 @@ import (
 	"net/http"
 	"net/http/httputil"
 	"net/url"
+	"strconv"
 	"strings"
 	"time"
 
@@ -16,6 +17,7 @@ import (
 
 type Client interface {
 	GetFeed() ([]FeedItem, error)
+	GetFeedPage(offset int, limit int) ([]FeedItem, error)
 	GetCaps() (*Caps, error)
 }
 
@@ -43,12 +45,16 @@ type Config struct {
 	UseBasicAuth bool
 	BasicAuth    BasicAuth
 
+	// Transport is used for requests when set, eg. to go through a proxy
+	Transport http.RoundTripper
+
 	Log *log.Logger
 }
 
 func NewClient(config Config) Client {
 	httpClient := &http.Client{
-		Timeout: time.Second * 20,
+		Timeout:   time.Second * 20,
+		Transport: config.Transport,
 	}
 
 	c := &client{
@@ -74,6 +80,10 @@ func (c *client) get(endpoint string, opts map[string]string) (int, *Response, e
 		params.Add("apikey", c.ApiKey)
 	}
 
+	for k, v := range opts {
+		params.Set(k, v)
+	}
+
 	u, err := url.Parse(c.Host)
 	u.Path = strings.TrimSuffix(u.Path, "/")
 	u.RawQuery = params.Encode()
@@ -114,7 +124,21 @@ func (c *client) get(endpoint string, opts map[string]string) (int, *Response, e
 }
 
 func (c *client) GetFeed() ([]FeedItem, error) {
-	status, res, err := c.get("", nil)
+	return c.GetFeedPage(0, 0)
+}
+
+// GetFeedPage gets the feed starting at offset. A limit of 0 uses the indexer default page size.
+func (c *client) GetFeedPage(offset int, limit int) ([]FeedItem, error) {
+	opts := map[string]string{}
+
+	if offset > 0 {
+		opts["offset"] = strconv.Itoa(offset)
+	}
+	if limit > 0 {
+		opts["limit"] = strconv.Itoa(limit)
+	}
+
+	status, res, err := c.get("", opts)
 	if err != nil {
 		return nil, errors.Wrap(err, "could not get feed")
 	}
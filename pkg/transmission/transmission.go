@@ -0,0 +1,136 @@
+// Package transmission sets torrent properties added in transmission 4 that the transmissionrpc library does not support yet.
+package transmission
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/autobrr/autobrr/pkg/errors"
+)
+
+const sessionIDHeader = "X-Transmission-Session-Id"
+
+type Config struct {
+	Host     string
+	Port     uint16
+	TLS      bool
+	Username string
+	Password string
+}
+
+type Client struct {
+	config Config
+	http   *http.Client
+
+	sessionID string
+}
+
+func New(config Config) *Client {
+	return &Client{
+		config: config,
+		http:   &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+type request struct {
+	Method    string                 `json:"method"`
+	Arguments map[string]interface{} `json:"arguments"`
+}
+
+type response struct {
+	Result string `json:"result"`
+}
+
+// TorrentSet sets arguments like labels, group and sequentialDownload on the torrents with the given hashes
+func (c *Client) TorrentSet(ctx context.Context, hashes []string, arguments map[string]interface{}) error {
+	args := map[string]interface{}{"ids": hashes}
+	for k, v := range arguments {
+		args[k] = v
+	}
+
+	body, err := json.Marshal(request{Method: "torrent-set", Arguments: args})
+	if err != nil {
+		return errors.Wrap(err, "could not marshal request")
+	}
+
+	// the first request of a session is answered with 409 and the session id to use
+	for attempt := 0; attempt < 2; attempt++ {
+		status, res, err := c.post(ctx, body)
+		if err != nil {
+			return err
+		}
+
+		if status == http.StatusConflict {
+			continue
+		}
+
+		if status != http.StatusOK {
+			return errors.New("torrent-set unexpected status: %v", status)
+		}
+
+		var r response
+		if err := json.Unmarshal(res, &r); err != nil {
+			return errors.Wrap(err, "could not unmarshal response")
+		}
+
+		if r.Result != "success" {
+			return errors.New("torrent-set failed: %v", r.Result)
+		}
+
+		return nil
+	}
+
+	return errors.New("torrent-set failed: could not get session id")
+}
+
+func (c *Client) post(ctx context.Context, body []byte) (int, []byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.url(), bytes.NewReader(body))
+	if err != nil {
+		return 0, nil, errors.Wrap(err, "could not build request")
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	if c.sessionID != "" {
+		req.Header.Set(sessionIDHeader, c.sessionID)
+	}
+	if c.config.Username != "" || c.config.Password != "" {
+		req.SetBasicAuth(c.config.Username, c.config.Password)
+	}
+
+	res, err := c.http.Do(req)
+	if err != nil {
+		return 0, nil, errors.Wrap(err, "torrent-set request failed")
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode == http.StatusConflict {
+		c.sessionID = res.Header.Get(sessionIDHeader)
+	}
+
+	data, err := io.ReadAll(res.Body)
+	if err != nil {
+		return res.StatusCode, nil, errors.Wrap(err, "could not read response")
+	}
+
+	return res.StatusCode, data, nil
+}
+
+// url is built the same way as the transmissionrpc client does
+func (c *Client) url() string {
+	scheme := "http"
+	if c.config.TLS {
+		scheme = "https"
+	}
+
+	port := c.config.Port
+	if port == 0 {
+		port = 9091
+	}
+
+	return fmt.Sprintf("%s://%s:%d/transmission/rpc", scheme, c.config.Host, port)
+}
@@ -0,0 +1,71 @@
+package transmission
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClient_TorrentSet(t *testing.T) {
+	var got request
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/transmission/rpc", r.URL.Path)
+
+		if user, pass, _ := r.BasicAuth(); user != "admin" || pass != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		if r.Header.Get(sessionIDHeader) != "mock-session" {
+			w.Header().Set(sessionIDHeader, "mock-session")
+			w.WriteHeader(http.StatusConflict)
+			return
+		}
+
+		json.NewDecoder(r.Body).Decode(&got)
+		w.Write([]byte(`{"arguments":{},"result":"success"}`))
+	}))
+	defer srv.Close()
+
+	host, port, _ := net.SplitHostPort(srv.Listener.Addr().String())
+	p, _ := strconv.Atoi(port)
+
+	c := New(Config{Host: host, Port: uint16(p), Username: "admin", Password: "secret"})
+
+	err := c.TorrentSet(context.Background(), []string{"abc123"}, map[string]interface{}{
+		"labels":             []string{"autobrr", "tv"},
+		"group":              "slow",
+		"sequentialDownload": true,
+	})
+	assert.NoError(t, err)
+
+	assert.Equal(t, "torrent-set", got.Method)
+	assert.Equal(t, map[string]interface{}{
+		"ids":                []interface{}{"abc123"},
+		"labels":             []interface{}{"autobrr", "tv"},
+		"group":              "slow",
+		"sequentialDownload": true,
+	}, got.Arguments)
+}
+
+func TestClient_TorrentSet_error(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"arguments":{},"result":"invalid argument"}`))
+	}))
+	defer srv.Close()
+
+	host, port, _ := net.SplitHostPort(srv.Listener.Addr().String())
+	p, _ := strconv.Atoi(port)
+
+	c := New(Config{Host: host, Port: uint16(p)})
+
+	err := c.TorrentSet(context.Background(), []string{"abc123"}, map[string]interface{}{"group": "slow"})
+	assert.EqualError(t, err, "torrent-set failed: invalid argument")
+}
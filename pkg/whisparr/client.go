@@ -1,79 +1,31 @@
 package whisparr
 
 import (
-	"bytes"
-	"encoding/json"
-	"net/http"
-	"net/url"
-	"path"
+	"context"
 
-	"github.com/autobrr/autobrr/pkg/errors"
+	"github.com/autobrr/autobrr/pkg/arr"
 )
 
-func (c *client) get(endpoint string) (*http.Response, error) {
-	u, err := url.Parse(c.config.Hostname)
-	u.Path = path.Join(u.Path, "/api/v3/", endpoint)
-	reqUrl := u.String()
-
-	req, err := http.NewRequest(http.MethodGet, reqUrl, http.NoBody)
-	if err != nil {
-		return nil, errors.Wrap(err, "could not build request")
-	}
-
-	if c.config.BasicAuth {
-		req.SetBasicAuth(c.config.Username, c.config.Password)
-	}
-
-	req.Header.Add("X-Api-Key", c.config.APIKey)
-	req.Header.Set("User-Agent", "autobrr")
-
-	res, err := c.http.Do(req)
-	if err != nil {
-		return nil, errors.Wrap(err, "could not make request: %+v", req)
-	}
-
-	if res.StatusCode == http.StatusUnauthorized {
-		return nil, errors.New("unauthorized: bad credentials")
-	}
-
-	return res, nil
+func (c *client) get(endpoint string) (int, []byte, error) {
+	return c.arr.Get(context.Background(), endpoint)
 }
 
-func (c *client) post(endpoint string, data interface{}) (*http.Response, error) {
-	u, err := url.Parse(c.config.Hostname)
-	u.Path = path.Join(u.Path, "/api/v3/", endpoint)
-	reqUrl := u.String()
-
-	jsonData, err := json.Marshal(data)
-	if err != nil {
-		return nil, errors.Wrap(err, "could not marshal data: %+v", data)
-	}
-
-	req, err := http.NewRequest(http.MethodPost, reqUrl, bytes.NewBuffer(jsonData))
-	if err != nil {
-		return nil, errors.Wrap(err, "could not build request")
-	}
-
-	if c.config.BasicAuth {
-		req.SetBasicAuth(c.config.Username, c.config.Password)
-	}
+func (c *client) postBody(endpoint string, data interface{}) (int, []byte, error) {
+	return c.arr.Post(context.Background(), endpoint, data)
+}
 
-	req.Header.Add("X-Api-Key", c.config.APIKey)
-	req.Header.Set("Content-Type", "application/json; charset=UTF-8")
-	req.Header.Set("User-Agent", "autobrr")
+func (c *client) GetQueue() ([]arr.QueueRecord, error) {
+	return c.arr.Queue(context.Background(), arr.DefaultQueuePageSize)
+}
 
-	res, err := c.http.Do(req)
-	if err != nil {
-		return nil, errors.Wrap(err, "could not make request: %+v", req)
-	}
+func (c *client) GetHistory(pageSize int) ([]arr.HistoryRecord, error) {
+	return c.arr.History(context.Background(), pageSize)
+}
 
-	// validate response
-	if res.StatusCode == http.StatusUnauthorized {
-		return nil, errors.New("unauthorized: bad credentials")
-	} else if res.StatusCode != http.StatusOK {
-		return nil, errors.New("whisparr: bad request")
-	}
+func (c *client) GetSeries(tvdbID int) ([]arr.Series, error) {
+	return c.arr.Series(context.Background(), tvdbID)
+}
 
-	// return raw response and let the caller handle json unmarshal of body
-	return res, nil
+func (c *client) GetEpisodeFiles(seriesID int) ([]arr.EpisodeFile, error) {
+	return c.arr.EpisodeFiles(context.Background(), seriesID)
 }
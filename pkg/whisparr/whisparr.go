@@ -6,8 +6,8 @@ import (
 	"log"
 	"net/http"
 	"strings"
-	"time"
 
+	"github.com/autobrr/autobrr/pkg/arr"
 	"github.com/autobrr/autobrr/pkg/errors"
 )
 
@@ -26,31 +26,41 @@ type Config struct {
 type Client interface {
 	Test() (*SystemStatusResponse, error)
 	Push(release Release) ([]string, error)
+	GetQueue() ([]arr.QueueRecord, error)
+	GetHistory(pageSize int) ([]arr.HistoryRecord, error)
+	GetSeries(tvdbID int) ([]arr.Series, error)
+	GetEpisodeFiles(seriesID int) ([]arr.EpisodeFile, error)
 }
 
 type client struct {
 	config Config
-	http   *http.Client
+	arr    *arr.Client
 
 	Log *log.Logger
 }
 
 func New(config Config) Client {
-
-	httpClient := &http.Client{
-		Timeout: time.Second * 30,
-	}
-
 	c := &client{
 		config: config,
-		http:   httpClient,
 		Log:    config.Log,
 	}
 
 	if config.Log == nil {
+		// if no provided logger then use io.Discard
 		c.Log = log.New(io.Discard, "", log.LstdFlags)
 	}
 
+	c.arr = arr.New(arr.Config{
+		Name:       "whisparr",
+		Hostname:   config.Hostname,
+		APIKey:     config.APIKey,
+		APIVersion: "v3",
+		BasicAuth:  config.BasicAuth,
+		Username:   config.Username,
+		Password:   config.Password,
+		Log:        c.Log,
+	})
+
 	return c
 }
 
@@ -62,6 +72,8 @@ type Release struct {
 	DownloadProtocol string `json:"downloadProtocol"`
 	Protocol         string `json:"protocol"`
 	PublishDate      string `json:"publishDate"`
+	DownloadClientId int32  `json:"downloadClientId,omitempty"`
+	DownloadClient   string `json:"downloadClient,omitempty"`
 }
 
 type PushResponse struct {
@@ -76,16 +88,13 @@ type SystemStatusResponse struct {
 }
 
 func (c *client) Test() (*SystemStatusResponse, error) {
-	res, err := c.get("system/status")
+	status, body, err := c.get("system/status")
 	if err != nil {
 		return nil, errors.Wrap(err, "could not test whisparr")
 	}
 
-	defer res.Body.Close()
-
-	body, err := io.ReadAll(res.Body)
-	if err != nil {
-		return nil, errors.Wrap(err, "could not read body")
+	if status == http.StatusUnauthorized {
+		return nil, errors.New("unauthorized: bad credentials")
 	}
 
 	response := SystemStatusResponse{}
@@ -94,26 +103,21 @@ func (c *client) Test() (*SystemStatusResponse, error) {
 		return nil, errors.Wrap(err, "could not unmarshal data")
 	}
 
-	c.Log.Printf("whisparr system/status status: (%v) response: %v\n", res.Status, string(body))
+	c.Log.Printf("whisparr system/status status: (%v) response: %v\n", status, string(body))
 
 	return &response, nil
 }
 
 func (c *client) Push(release Release) ([]string, error) {
-	res, err := c.post("release/push", release)
+	status, body, err := c.postBody("release/push", release)
 	if err != nil {
 		return nil, errors.Wrap(err, "could not push release to whisparr: %+v", release)
 	}
 
-	if res == nil {
-		return nil, nil
-	}
-
-	defer res.Body.Close()
-
-	body, err := io.ReadAll(res.Body)
-	if err != nil {
-		return nil, errors.Wrap(err, "could not read body")
+	if status == http.StatusUnauthorized {
+		return nil, errors.New("unauthorized: bad credentials")
+	} else if status != http.StatusOK {
+		return nil, errors.New("whisparr: bad request: (status: %d): %s", status, string(body))
 	}
 
 	pushResponse := make([]PushResponse, 0)
@@ -122,7 +126,7 @@ func (c *client) Push(release Release) ([]string, error) {
 		return nil, errors.Wrap(err, "could not unmarshal data")
 	}
 
-	c.Log.Printf("whisparr release/push status: (%v) response: %v\n", res.Status, string(body))
+	c.Log.Printf("whisparr release/push status: (%v) response: %v\n", status, string(body))
 
 	// log and return if rejected
 	if pushResponse[0].Rejected {
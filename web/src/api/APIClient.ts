@@ -15,7 +15,8 @@ export async function HttpClient<T>(
 ): Promise<T> {
   const config = {
     method: method,
-    body: body ? JSON.stringify(body) : undefined,
+    // strings are sent as is, eg. a filter export document that might be YAML
+    body: body ? (typeof body === "string" ? body : JSON.stringify(body)) : undefined,
     headers: {
       "Content-Type": "application/json"
     },
@@ -54,6 +55,29 @@ const appClient = {
   Delete: (endpoint: string) => HttpClient<void>(endpoint, "DELETE")
 };
 
+// releaseFilterParams maps the release table filters to the query params of the release api
+function releaseFilterParams(filters?: Array<ReleaseFilter>) {
+  const params = new URLSearchParams();
+
+  filters?.forEach((filter) => {
+    if (!filter.value)
+      return;
+
+    if (filter.id == "indexer")
+      params.append("indexer", filter.value);
+    else if (filter.id == "filter")
+      params.append("filter", filter.value);
+    else if (filter.id === "action_status")
+      params.append("push_status", filter.value);
+    else if (filter.id == "torrent_name")
+      params.append("q", filter.value);
+    else if (["from", "to", "min_size", "max_size"].includes(filter.id))
+      params.append(filter.id, filter.value);
+  });
+
+  return params;
+}
+
 export const APIClient = {
   auth: {
     login: (username: string, password: string) => appClient.Post("api/auth/login", {
@@ -66,18 +90,38 @@ export const APIClient = {
       username: username,
       password: password
     }),
-    canOnboard: () => appClient.Get("api/auth/onboard")
+    canOnboard: () => appClient.Get("api/auth/onboard"),
+    oidcConfig: () => appClient.Get<OIDCConfig>("api/auth/oidc/config")
   },
   actions: {
     create: (action: Action) => appClient.Post("api/actions", action),
     update: (action: Action) => appClient.Put(`api/actions/${action.id}`, action),
     delete: (id: number) => appClient.Delete(`api/actions/${id}`),
-    toggleEnable: (id: number) => appClient.Patch(`api/actions/${id}/toggleEnabled`)
+    toggleEnable: (id: number) => appClient.Patch(`api/actions/${id}/toggleEnabled`),
+    blocklist: () => appClient.Get<TorrentBlocklistEntry[]>("api/actions/blocklist"),
+    createBlocklistEntry: (entry: TorrentBlocklistEntry) => appClient.Post("api/actions/blocklist", entry),
+    deleteBlocklistEntry: (id: number) => appClient.Delete(`api/actions/blocklist/${id}`)
+  },
+  announceBlocklist: {
+    list: () => appClient.Get<AnnounceBlocklistRule[]>("api/blocklist"),
+    create: (rule: AnnounceBlocklistRule) => appClient.Post<AnnounceBlocklistRule>("api/blocklist", rule),
+    update: (rule: AnnounceBlocklistRule) => appClient.Put(`api/blocklist/${rule.id}`, rule),
+    delete: (id: number) => appClient.Delete(`api/blocklist/${id}`)
   },
   apikeys: {
     getAll: () => appClient.Get<APIKey[]>("api/keys"),
-    create: (key: APIKey) => appClient.Post("api/keys", key),
-    delete: (key: string) => appClient.Delete(`api/keys/${key}`)
+    create: (key: APIKey) => appClient.Post<APIKey>("api/keys", key),
+    delete: (id: string) => appClient.Delete(`api/keys/${id}`)
+  },
+  audit: {
+    find: (entityType: AuditEntityType | "") => appClient.Get<AuditEntry[]>(`api/audit${entityType ? `?entity_type=${entityType}` : ""}`)
+  },
+  backups: {
+    list: () => appClient.Get<DatabaseBackup[]>("api/backup"),
+    create: () => appClient.Post<DatabaseBackup>("api/backup"),
+    settings: () => appClient.Get<DatabaseBackupSettings>("api/backup/settings"),
+    downloadUrl: (name: string) => `${baseUrl()}api/backup/${name}`,
+    delete: (name: string) => appClient.Delete(`api/backup/${name}`)
   },
   config: {
     get: () => appClient.Get<Config>("api/config")
@@ -113,7 +157,18 @@ export const APIClient = {
     create: (filter: Filter) => appClient.Post("api/filters", filter),
     update: (filter: Filter) => appClient.Put(`api/filters/${filter.id}`, filter),
     duplicate: (id: number) => appClient.Get<Filter>(`api/filters/${id}/duplicate`),
+    duplicateBulk: (id: number, count: number, suffix: string) =>
+      appClient.Post<Filter[]>(`api/filters/${id}/duplicate`, { count, suffix }),
+    exportUrl: (id: number, format: "json" | "yaml") => `${baseUrl()}api/filters/${id}/export?format=${format}`,
+    import: (document: string, conflict: FilterImportConflict) =>
+      appClient.Post<FilterImportResult>(`api/filters/import?conflict=${conflict}`, document),
     toggleEnable: (id: number, enabled: boolean) => appClient.Put(`api/filters/${id}/enabled`, { enabled }),
+    updateBulk: (ids: number[], update: { enabled?: boolean; priority?: number }) =>
+      appClient.Patch("api/filters/bulk", { ids, ...update }),
+    deleteBulk: (ids: number[]) => appClient.Post("api/filters/bulk/delete", { ids }),
+    test: (req: FilterTestRequest) => appClient.Post<FilterTestResponse>("api/filters/test", req),
+    rejections: (id: number, hours: number) =>
+      appClient.Get<FilterRejectionStats>(`api/filters/${id}/rejections?hours=${hours}`),
     delete: (id: number) => appClient.Delete(`api/filters/${id}`)
   },
   feeds: {
@@ -122,7 +177,8 @@ export const APIClient = {
     toggleEnable: (id: number, enabled: boolean) => appClient.Patch(`api/feeds/${id}/enabled`, { enabled }),
     update: (feed: Feed) => appClient.Put(`api/feeds/${feed.id}`, feed),
     delete: (id: number) => appClient.Delete(`api/feeds/${id}`),
-    test: (feed: Feed) => appClient.Post("api/feeds/test", feed)
+    test: (feed: Feed) => appClient.Post("api/feeds/test", feed),
+    dryRun: (id: number) => appClient.Post<FeedDryRunResponse>(`api/feeds/${id}/dry-run`)
   },
   indexers: {
     // returns indexer options for all currently present/enabled indexers
@@ -133,17 +189,28 @@ export const APIClient = {
     getSchema: () => appClient.Get<IndexerDefinition[]>("api/indexer/schema"),
     create: (indexer: Indexer) => appClient.Post<Indexer>("api/indexer", indexer),
     update: (indexer: Indexer) => appClient.Put("api/indexer", indexer),
-    delete: (id: number) => appClient.Delete(`api/indexer/${id}`)
+    delete: (id: number) => appClient.Delete(`api/indexer/${id}`),
+    testProxy: (req: IndexerProxyTestRequest) => appClient.Post("api/indexer/proxy/test", req)
   },
   irc: {
     getNetworks: () => appClient.Get<IrcNetworkWithHealth[]>("api/irc"),
+    getStatus: () => appClient.Get<IrcNetworkStatus[]>("api/irc/status"),
     createNetwork: (network: IrcNetworkCreate) => appClient.Post("api/irc", network),
     updateNetwork: (network: IrcNetwork) => appClient.Put(`api/irc/network/${network.id}`, network),
     deleteNetwork: (id: number) => appClient.Delete(`api/irc/network/${id}`),
     restartNetwork: (id: number) => appClient.Get(`api/irc/network/${id}/restart`)
   },
   events: {
-    logs: () => new EventSource(`${sseBaseUrl()}api/events?stream=logs`, { withCredentials: true })
+    logs: () => new EventSource(`${sseBaseUrl()}api/events?stream=logs`, { withCredentials: true }),
+    releases: () => new EventSource(`${sseBaseUrl()}api/events?stream=releases`, { withCredentials: true }),
+    irc: () => new EventSource(`${sseBaseUrl()}api/events?stream=irc`, { withCredentials: true })
+  },
+  lists: {
+    list: () => appClient.Get<List[]>("api/lists"),
+    create: (list: List) => appClient.Post<List>("api/lists", list),
+    update: (list: List) => appClient.Put(`api/lists/${list.id}`, list),
+    delete: (id: number) => appClient.Delete(`api/lists/${id}`),
+    refresh: (id: number) => appClient.Post(`api/lists/${id}/refresh`)
   },
   notifications: {
     getAll: () => appClient.Get<Notification[]>("api/notification"),
@@ -152,33 +219,57 @@ export const APIClient = {
     delete: (id: number) => appClient.Delete(`api/notification/${id}`),
     test: (n: Notification) => appClient.Post("api/notification/test", n)
   },
+  quotas: {
+    getAll: () => appClient.Get<Quota[]>("api/quotas"),
+    getByID: (id: number) => appClient.Get<Quota>(`api/quotas/${id}`),
+    create: (quota: Quota) => appClient.Post("api/quotas", quota),
+    update: (quota: Quota) => appClient.Put(`api/quotas/${quota.id}`, quota),
+    delete: (id: number) => appClient.Delete(`api/quotas/${id}`)
+  },
   release: {
     find: (query?: string) => appClient.Get<ReleaseFindResponse>(`api/release${query}`),
     findRecent: () => appClient.Get<ReleaseFindResponse>("api/release/recent"),
     findQuery: (offset?: number, limit?: number, filters?: Array<ReleaseFilter>) => {
-      const params = new URLSearchParams();
+      const params = releaseFilterParams(filters);
       if (offset !== undefined && offset > 0)
         params.append("offset", offset.toString());
 
       if (limit !== undefined)
         params.append("limit", limit.toString());
 
-      filters?.forEach((filter) => {
-        if (!filter.value)
-          return;
-
-        if (filter.id == "indexer")
-          params.append("indexer", filter.value);
-        else if (filter.id === "action_status")
-          params.append("push_status", filter.value);
-        else if (filter.id == "torrent_name")
-          params.append("q", filter.value);
-      });
-
       return appClient.Get<ReleaseFindResponse>(`api/release?${params.toString()}`);
     },
+    exportUrl: (format: "csv" | "json", filters?: Array<ReleaseFilter>) => {
+      const params = releaseFilterParams(filters);
+      params.append("format", format);
+
+      return `${baseUrl()}api/release/export?${params.toString()}`;
+    },
     indexerOptions: () => appClient.Get<string[]>("api/release/indexers"),
+    replay: (req: ReleaseReplayRequest) => appClient.Post<ReleaseReplayResult[]>("api/release/replay", req),
     stats: () => appClient.Get<ReleaseStats>("api/release/stats"),
+    events: (types?: Array<ReleaseEventType>, indexers?: Array<string>, limit?: number) => {
+      const params = new URLSearchParams();
+      types?.forEach((t) => params.append("type", t));
+      indexers?.forEach((i) => params.append("indexer", i));
+      if (limit !== undefined)
+        params.append("limit", limit.toString());
+
+      return appClient.Get<ReleaseEvent[]>(`api/release/events?${params.toString()}`);
+    },
+    retries: {
+      list: () => appClient.Get<ActionRetry[]>("api/release/retries"),
+      retry: (id: number) => appClient.Post(`api/release/retries/${id}/retry`),
+      discard: (id: number) => appClient.Delete(`api/release/retries/${id}`)
+    },
+    approvals: {
+      list: (status: ReleaseApprovalStatus | "" = "PENDING") => appClient.Get<ReleaseApproval[]>(`api/release/approvals?status=${status}`),
+      decide: (id: number, decision: ReleaseApprovalDecision) => appClient.Post(`api/release/approvals/${id}/${decision}`)
+    },
+    retention: {
+      get: () => appClient.Get<ReleaseRetention>("api/release/retention"),
+      prune: () => appClient.Post<ReleasePruneResponse>("api/release/retention/prune")
+    },
     delete: () => appClient.Delete("api/release/all")
   }
 };
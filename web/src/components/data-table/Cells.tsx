@@ -80,6 +80,15 @@ export const ReleaseStatusCell = ({ value }: ReleaseStatusCellProps) => (
                 </p>
               </li>
             ) : null}
+            {v.log ? (
+              <li className="py-1">
+                Output:
+                {" "}
+                <pre className="whitespace-pre-wrap break-all text-xs max-h-48 overflow-y-auto">
+                  {v.log}
+                </pre>
+              </li>
+            ) : null}
           </ol>
         </Tooltip>
       </div>
@@ -6,6 +6,7 @@ import { MultiSelect as RMSC } from "react-multi-select-component";
 
 import { classNames, COL_WIDTHS } from "../../utils";
 import { SettingsContext } from "../../utils/Context";
+import { CrossSeedClientTypes } from "../../domain/constants";
 
 export interface MultiSelectOption {
     value: string | number;
@@ -127,16 +128,30 @@ export const IndexerMultiSelect = ({
   );
 };
 
+// watch folder actions save to the watch folder of any client that has one
+const clientMatchesAction = (client: DownloadClient, action: Action) => {
+  switch (action.type) {
+  case "CROSS_SEED":
+    return CrossSeedClientTypes.includes(client.type);
+  case "WATCH_FOLDER":
+    return !!client.settings?.watch_folder;
+  default:
+    return client.type === action.type;
+  }
+};
+
 interface DownloadClientSelectProps {
     name: string;
     action: Action;
     clients: DownloadClient[];
+    label?: string;
 }
 
 export function DownloadClientSelect({
   name,
   action,
-  clients
+  clients,
+  label = "Client"
 }: DownloadClientSelectProps) {
   return (
     <div className="col-span-6 sm:col-span-6">
@@ -152,7 +167,7 @@ export function DownloadClientSelect({
             {({ open }) => (
               <>
                 <Listbox.Label className="block text-xs font-bold text-gray-700 dark:text-gray-200 uppercase tracking-wide">
-                                    Client
+                  {label}
                 </Listbox.Label>
                 <div className="mt-2 relative">
                   <Listbox.Button className="bg-white dark:bg-gray-800 relative w-full border border-gray-300 dark:border-gray-700 rounded-md shadow-sm pl-3 pr-10 py-2 text-left cursor-default focus:outline-none focus:ring-1 focus:ring-indigo-500 dark:focus:ring-blue-500 focus:border-indigo-500 dark:focus:border-blue-500 dark:text-gray-200 sm:text-sm">
@@ -180,7 +195,7 @@ export function DownloadClientSelect({
                       className="absolute z-10 mt-1 w-full bg-white dark:bg-gray-800 shadow-lg max-h-60 rounded-md py-1 text-base ring-1 ring-black ring-opacity-5 overflow-auto focus:outline-none sm:text-sm"
                     >
                       {clients
-                        .filter((c) => c.type === action.type)
+                        .filter((c) => clientMatchesAction(c, action))
                         .map((client) => (
                           <Listbox.Option
                             key={client.id}
@@ -233,7 +248,7 @@ export function DownloadClientSelect({
 
 interface SelectFieldOption {
     label: string;
-    value: string;
+    value: string | number;
 }
 
 interface SelectFieldProps {
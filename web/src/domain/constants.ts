@@ -186,6 +186,11 @@ export const DownloadClientTypeOptions: RadioFieldsetOption[] = [
     description: "Add torrents directly to Transmission",
     value: "TRANSMISSION"
   },
+  {
+    label: "Porla",
+    description: "Add torrents directly to Porla",
+    value: "PORLA"
+  },
   {
     label: "Radarr",
     description: "Send to Radarr and let it decide",
@@ -214,6 +219,7 @@ export const DownloadClientTypeNameMap: Record<DownloadClientType | string, stri
   "QBITTORRENT": "qBittorrent",
   "RTORRENT": "rTorrent",
   "TRANSMISSION": "Transmission",
+  "PORLA": "Porla",
   "RADARR": "Radarr",
   "SONARR": "Sonarr",
   "LIDARR": "Lidarr",
@@ -230,10 +236,12 @@ export const ActionTypeOptions: RadioFieldsetOption[] = [
   { label: "Deluge v2", description: "Add torrents directly to Deluge 2", value: "DELUGE_V2" },
   { label: "rTorrent", description: "Add torrents directly to rTorrent", value: "RTORRENT" },
   { label: "Transmission", description: "Add torrents directly to Transmission", value: "TRANSMISSION" },
+  { label: "Porla", description: "Add torrents directly to Porla", value: "PORLA" },
   { label: "Radarr", description: "Send to Radarr and let it decide", value: "RADARR" },
   { label: "Sonarr", description: "Send to Sonarr and let it decide", value: "SONARR" },
   { label: "Lidarr", description: "Send to Lidarr and let it decide", value: "LIDARR" },
-  { label: "Whisparr", description: "Send to Whisparr and let it decide", value: "WHISPARR" }
+  { label: "Whisparr", description: "Send to Whisparr and let it decide", value: "WHISPARR" },
+  { label: "Cross-seed", description: "Inject into qBittorrent or Deluge 2 if the content is already downloaded", value: "CROSS_SEED" }
 ];
 
 export const ActionTypeNameMap = {
@@ -246,12 +254,16 @@ export const ActionTypeNameMap = {
   "QBITTORRENT": "qBittorrent",
   "RTORRENT": "rTorrent",
   "TRANSMISSION": "Transmission",
+  "PORLA": "Porla",
   "RADARR": "Radarr",
   "SONARR": "Sonarr",
   "LIDARR": "Lidarr",
-  "WHISPARR": "Whisparr"
+  "WHISPARR": "Whisparr",
+  "CROSS_SEED": "Cross-seed"
 };
 
+export const CrossSeedClientTypes: DownloadClientType[] = ["QBITTORRENT", "DELUGE_V2"];
+
 export const ActionContentLayoutOptions: SelectGenericOption<ActionContentLayout>[] = [
   { label: "Original", description: "Original", value: "ORIGINAL" },
   { label: "Create subfolder", description: "Create subfolder", value: "SUBFOLDER_CREATE" },
@@ -295,6 +307,14 @@ export const NotificationTypeOptions: OptionBasicTyped<NotificationType>[] = [
   {
     label: "Telegram",
     value: "TELEGRAM"
+  },
+  {
+    label: "ntfy",
+    value: "NTFY"
+  },
+  {
+    label: "Apprise",
+    value: "APPRISE"
   }
 ];
 
@@ -325,6 +345,55 @@ export const downloadsPerUnitOptions: OptionBasic[] = [
   }
 ];
 
+export const webhookTypeOptions: OptionBasic[] = [
+  {
+    label: "HTTP",
+    value: "HTTP"
+  },
+  {
+    label: "gRPC (json codec)",
+    value: "GRPC"
+  }
+];
+
+export const sizePreferenceOptions: OptionBasic[] = [
+  {
+    label: "None",
+    value: ""
+  },
+  {
+    label: "Prefer smaller",
+    value: "SMALLER"
+  },
+  {
+    label: "Prefer larger",
+    value: "LARGER"
+  }
+];
+
+export const dedupKeyOptions: OptionBasic[] = [
+  {
+    label: "Default from config",
+    value: ""
+  },
+  {
+    label: "Disabled",
+    value: "DISABLED"
+  },
+  {
+    label: "Title",
+    value: "TITLE"
+  },
+  {
+    label: "Title and group",
+    value: "TITLE_GROUP"
+  },
+  {
+    label: "Title, resolution and source",
+    value: "TITLE_RESOLUTION_SOURCE"
+  }
+];
+
 export interface SelectOption {
     label: string;
     description: string;
@@ -363,9 +432,59 @@ export const EventOptions: SelectOption[] = [
     value: "IRC_RECONNECTED",
     description: "Reconnected to irc network after error"
   },
+  {
+    label: "IRC announces silent",
+    value: "IRC_ANNOUNCE_SILENT",
+    description: "No announces in a channel for longer than usual while connected"
+  },
+  {
+    label: "IRC announces resumed",
+    value: "IRC_ANNOUNCE_RESUMED",
+    description: "Announces came back in a silent channel"
+  },
+  {
+    label: "Download client down",
+    value: "DOWNLOAD_CLIENT_DOWN",
+    description: "Download client failed its health check"
+  },
+  {
+    label: "Download client recovered",
+    value: "DOWNLOAD_CLIENT_RECOVERED",
+    description: "Download client is reachable again"
+  },
+  {
+    label: "Release pending approval",
+    value: "RELEASE_PENDING_APPROVAL",
+    description: "A filter that requires approval matched, with approve and reject links"
+  },
   {
     label: "New update",
     value: "APP_UPDATE_AVAILABLE",
     description: "Get notified on updates"
   }
 ];
+
+export const IrcSaslMechanismOptions: OptionBasic[] = [
+  {
+    label: "PLAIN (NickServ account and password)",
+    value: "PLAIN"
+  },
+  {
+    label: "EXTERNAL (client certificate)",
+    value: "EXTERNAL"
+  }
+];
+
+export const APIKeyScopeOptions: OptionBasic[] = [
+  { label: "Admin", value: "admin" },
+  { label: "Read-only", value: "read" },
+  { label: "Releases", value: "releases" },
+  { label: "Filters and actions", value: "filters" }
+];
+
+export const APIKeyExpiryOptions: OptionBasic[] = [
+  { label: "Never", value: "" },
+  { label: "30 days", value: "30" },
+  { label: "90 days", value: "90" },
+  { label: "1 year", value: "365" }
+];
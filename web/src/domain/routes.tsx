@@ -12,12 +12,18 @@ import Settings from "../screens/Settings";
 import ApplicationSettings from "../screens/settings/Application";
 import DownloadClientSettings from "../screens/settings/DownloadClient";
 import FeedSettings from "../screens/settings/Feed";
+import { FilterTest } from "../screens/settings/FilterTest";
 import IndexerSettings from "../screens/settings/Indexer";
 import { IrcSettings } from "../screens/settings/Irc";
 import NotificationSettings from "../screens/settings/Notifications";
 import { RegexPlayground } from "../screens/settings/RegexPlayground";
 import ReleaseSettings from "../screens/settings/Releases";
 import APISettings from "../screens/settings/Api";
+import BackupSettings from "../screens/settings/Backups";
+import BlocklistSettings from "../screens/settings/Blocklist";
+import ListSettings from "../screens/settings/Lists";
+import ApprovalSettings from "../screens/settings/Approvals";
+import AuditSettings from "../screens/settings/Audit";
 
 import { baseUrl } from "../utils";
 
@@ -43,7 +49,13 @@ export const LocalRouter = ({ isLoggedIn }: { isLoggedIn: boolean }) => (
             <Route path="clients" element={<DownloadClientSettings />} />
             <Route path="notifications" element={<NotificationSettings />} />
             <Route path="releases" element={<ReleaseSettings />} />
+            <Route path="backups" element={<BackupSettings />} />
+            <Route path="approvals" element={<ApprovalSettings />} />
+            <Route path="lists" element={<ListSettings />} />
+            <Route path="blocklist" element={<BlocklistSettings />} />
+            <Route path="audit" element={<AuditSettings />} />
             <Route path="regex-playground" element={<RegexPlayground />} />
+            <Route path="filter-test" element={<FilterTest />} />
           </Route>
         </Route>
       </Routes>
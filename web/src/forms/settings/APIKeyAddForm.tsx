@@ -10,26 +10,48 @@ import { queryClient } from "../../App";
 import { APIClient } from "../../api/APIClient";
 import DEBUG from "../../components/debug";
 import Toast from "../../components/notifications/Toast";
+import { APIKeyExpiryOptions, APIKeyScopeOptions } from "../../domain/constants";
+
+interface apiKeyFormValues {
+  name: string;
+  scopes: string[];
+  expires_in: string;
+}
 
 interface apiKeyAddFormProps {
   isOpen: boolean;
   toggle: () => void;
+  onCreated?: (key: APIKey) => void;
 }
 
-function APIKeyAddForm({ isOpen, toggle }: apiKeyAddFormProps) {
+function APIKeyAddForm({ isOpen, toggle, onCreated }: apiKeyAddFormProps) {
   const mutation = useMutation(
     (apikey: APIKey) => APIClient.apikeys.create(apikey),
     {
-      onSuccess: (_, key) => {
+      onSuccess: (created, key) => {
         queryClient.invalidateQueries("apikeys");
         toast.custom((t) => <Toast type="success" body={`API key ${key.name} was added`} t={t}/>);
 
+        // the key value is only returned once
+        onCreated?.(created);
+
         toggle();
       }
     }
   );
   
-  const handleSubmit = (data: unknown) => mutation.mutate(data as APIKey);
+  const handleSubmit = (data: unknown) => {
+    const values = data as apiKeyFormValues;
+    const key = { name: values.name, scopes: values.scopes } as APIKey;
+
+    if (values.expires_in) {
+      const expiresAt = new Date();
+      expiresAt.setDate(expiresAt.getDate() + parseInt(values.expires_in));
+      key.expires_at = expiresAt.toISOString();
+    }
+
+    mutation.mutate(key);
+  };
   const validate = (values: FormikValues) => {
     const errors = {} as FormikErrors<FormikValues>;
     if (!values.name) {
@@ -59,7 +81,8 @@ function APIKeyAddForm({ isOpen, toggle }: apiKeyAddFormProps) {
                 <Formik
                   initialValues={{
                     name: "",
-                    scopes: []
+                    scopes: [],
+                    expires_in: ""
                   }}
                   onSubmit={handleSubmit}
                   validate={validate}
@@ -121,6 +144,53 @@ function APIKeyAddForm({ isOpen, toggle }: apiKeyAddFormProps) {
                               )}
                             </Field>
                           </div>
+
+                          <div
+                            className="space-y-1 px-4 sm:space-y-0 sm:grid sm:grid-cols-3 sm:gap-4 sm:py-4">
+                            <div>
+                              <span className="block text-sm font-medium text-gray-900 dark:text-white sm:mt-px sm:pt-2">
+                                Scopes
+                              </span>
+                              <p className="text-xs text-gray-500 dark:text-gray-400">No scopes gives full access</p>
+                            </div>
+                            <div className="sm:col-span-2 space-y-2 sm:pt-2">
+                              {APIKeyScopeOptions.map((scope) => (
+                                <label key={scope.value} className="flex items-center space-x-2 text-sm text-gray-900 dark:text-white">
+                                  <Field
+                                    type="checkbox"
+                                    name="scopes"
+                                    value={scope.value}
+                                    className="rounded border-gray-300 dark:border-gray-700 dark:bg-gray-800 text-indigo-600 focus:ring-indigo-500"
+                                  />
+                                  <span>{scope.label}</span>
+                                </label>
+                              ))}
+                            </div>
+                          </div>
+
+                          <div
+                            className="space-y-1 px-4 sm:space-y-0 sm:grid sm:grid-cols-3 sm:gap-4 sm:py-4">
+                            <div>
+                              <label
+                                htmlFor="expires_in"
+                                className="block text-sm font-medium text-gray-900 dark:text-white sm:mt-px sm:pt-2"
+                              >
+                                Expires
+                              </label>
+                            </div>
+                            <div className="sm:col-span-2">
+                              <Field
+                                as="select"
+                                id="expires_in"
+                                name="expires_in"
+                                className="block w-full shadow-sm dark:bg-gray-800 border-gray-300 dark:border-gray-700 sm:text-sm dark:text-white focus:ring-indigo-500 dark:focus:ring-blue-500 focus:border-indigo-500 dark:focus:border-blue-500 rounded-md"
+                              >
+                                {APIKeyExpiryOptions.map((option) => (
+                                  <option key={option.value} value={option.value}>{option.label}</option>
+                                ))}
+                              </Field>
+                            </div>
+                          </div>
                         </div>
                       </div>
 
@@ -3,7 +3,7 @@ import { useMutation } from "react-query";
 import { Dialog, Transition } from "@headlessui/react";
 import { XMarkIcon } from "@heroicons/react/24/solid";
 import { classNames, sleep } from "../../utils";
-import { Form, Formik, useFormikContext } from "formik";
+import { FieldArray, Form, Formik, useFormikContext } from "formik";
 import DEBUG from "../../components/debug";
 import { queryClient } from "../../App";
 import { APIClient } from "../../api/APIClient";
@@ -14,10 +14,13 @@ import Toast from "../../components/notifications/Toast";
 import { useToggle } from "../../hooks/hooks";
 import { DeleteModal } from "../../components/modals";
 import {
+  NumberField,
   NumberFieldWide,
   PasswordFieldWide,
   RadioFieldsetWide,
+  SwitchGroup,
   SwitchGroupWide,
+  TextField,
   TextFieldWide
 } from "../../components/inputs";
 import DownloadClient from "../../screens/settings/DownloadClient";
@@ -33,6 +36,7 @@ interface InitialValuesSettings {
     ignore_slow_torrents?: boolean;
     download_speed_threshold?: number;
     max_active_downloads?: number;
+    trackers?: DownloadClientTrackerRule[];
   };
 }
 
@@ -50,6 +54,16 @@ interface InitialValues {
 }
 
 
+function FormFieldsWatchFolder() {
+  return (
+    <TextFieldWide
+      name="settings.watch_folder"
+      label="Watch folder"
+      help="Folder the client watches for torrent files, used by watch folder actions for this client. Eg. /home/user/rwatch"
+    />
+  );
+}
+
 function FormFieldsDeluge() {
   const {
     values: { tls }
@@ -80,6 +94,8 @@ function FormFieldsDeluge() {
 
       <TextFieldWide name="username" label="Username" />
       <PasswordFieldWide name="password" label="Password" />
+
+      <FormFieldsWatchFolder />
     </div>
   );
 }
@@ -152,6 +168,8 @@ function FormFieldsQbit() {
           <PasswordFieldWide name="settings.basic.password" label="Password" />
         </>
       )}
+
+      <FormFieldsWatchFolder />
     </div>
   );
 }
@@ -164,6 +182,8 @@ function FormFieldsRTorrent() {
         label="Host"
         help="Eg. http(s)://client.domain.ltd/RPC2, http(s)://domain.ltd/client, http(s)://domain.ltd/RPC2"
       />
+
+      <FormFieldsWatchFolder />
     </div>
   );
 }
@@ -194,6 +214,29 @@ function FormFieldsTransmission() {
 
       <TextFieldWide name="username" label="Username" />
       <PasswordFieldWide name="password" label="Password" />
+
+      <FormFieldsWatchFolder />
+    </div>
+  );
+}
+
+function FormFieldsPorla() {
+  return (
+    <div className="flex flex-col space-y-4 px-1 py-6 sm:py-0 sm:space-y-0">
+      <TextFieldWide
+        name="host"
+        label="Host"
+        help="Full url http(s)://domain.ltd:port and/or subfolder"
+      />
+
+      <PasswordFieldWide name="settings.apikey" label="Auth token" help="Generate one with porla auth:token" />
+
+      <SwitchGroupWide
+        name="tls_skip_verify"
+        label="Skip TLS verification (insecure)"
+      />
+
+      <FormFieldsWatchFolder />
     </div>
   );
 }
@@ -208,6 +251,7 @@ export const componentMap: componentMapType = {
   QBITTORRENT: <FormFieldsQbit/>,
   RTORRENT: <FormFieldsRTorrent />,
   TRANSMISSION: <FormFieldsTransmission/>,
+  PORLA: <FormFieldsPorla/>,
   RADARR: <FormFieldsArr/>,
   SONARR: <FormFieldsArr/>,
   LIDARR: <FormFieldsArr/>,
@@ -277,6 +321,72 @@ function FormFieldsRules() {
           )}
         </>
       )}
+
+      <FormFieldsTrackerRules />
+    </div>
+  );
+}
+
+function FormFieldsTrackerRules() {
+  const {
+    values: { settings }
+  } = useFormikContext<InitialValues>();
+
+  const newRule: DownloadClientTrackerRule = {
+    indexer: "",
+    tags: "",
+    paused: false,
+    limit_upload_speed: 0,
+    limit_download_speed: 0,
+    limit_ratio: 0,
+    limit_seed_time: 0
+  };
+
+  return (
+    <div className="pt-5">
+      <div className="px-4 space-y-1">
+        <Dialog.Title className="text-lg font-medium text-gray-900 dark:text-white">
+          Tracker rules
+        </Dialog.Title>
+        <p className="text-sm text-gray-500 dark:text-gray-400">
+          Default tags and limits for torrents from an indexer. Limits set on the action take precedence.
+        </p>
+      </div>
+
+      <FieldArray name="settings.rules.trackers">
+        {({ remove, push }) => (
+          <div className="px-4">
+            {settings.rules?.trackers?.map((_, idx) => (
+              <div key={idx} className="mt-4 grid grid-cols-12 gap-4 border-b border-gray-200 dark:border-gray-700 pb-4">
+                <TextField name={`settings.rules.trackers.${idx}.indexer`} label="Indexer" columns={6} placeholder="indexer identifier" />
+                <TextField name={`settings.rules.trackers.${idx}.tags`} label="Tags" columns={6} placeholder="eg. {{ .Indexer }}" />
+                <NumberField name={`settings.rules.trackers.${idx}.limit_ratio`} label="Ratio limit" step={0.5} />
+                <NumberField name={`settings.rules.trackers.${idx}.limit_seed_time`} label="Seed time limit (minutes)" />
+                <NumberField name={`settings.rules.trackers.${idx}.limit_upload_speed`} label="Limit upload speed (KB/s)" />
+                <NumberField name={`settings.rules.trackers.${idx}.limit_download_speed`} label="Limit download speed (KB/s)" />
+                <div className="col-span-12 flex items-center justify-between">
+                  <SwitchGroup name={`settings.rules.trackers.${idx}.paused`} label="Add paused" />
+                  <button
+                    type="button"
+                    className="text-sm text-red-600 hover:text-red-700"
+                    onClick={() => remove(idx)}
+                  >
+                    Remove
+                  </button>
+                </div>
+              </div>
+            ))}
+
+            <button
+              type="button"
+              className="mt-4 inline-flex items-center px-3 py-1.5 border border-gray-300 dark:border-gray-700 text-sm rounded-md text-gray-700 dark:text-gray-200 bg-white dark:bg-gray-800 hover:bg-gray-50 dark:hover:bg-gray-700"
+              onClick={() => push(newRule)}
+            >
+              Add tracker rule
+            </button>
+          </div>
+        )}
+      </FieldArray>
     </div>
   );
 }
@@ -8,6 +8,7 @@ import { NumberFieldWide, PasswordFieldWide, SwitchGroupWide, TextFieldWide } fr
 import { componentMapType } from "./DownloadClientForms";
 import { sleep } from "../../utils";
 import { useState } from "react";
+import { Field } from "formik";
 import { ImplementationBadges } from "../../screens/settings/Indexer";
 
 interface UpdateProps {
@@ -25,6 +26,12 @@ interface InitialValues {
   url: string;
   api_key: string;
   interval: number;
+  backfill: number;
+  cookie: string;
+  headers: string;
+  username: string;
+  password: string;
+  settings: Record<string, string>;
 }
 
 export function FeedUpdateForm({ isOpen, toggle, feed }: UpdateProps) {
@@ -103,7 +110,13 @@ export function FeedUpdateForm({ isOpen, toggle, feed }: UpdateProps) {
     name: feed.name,
     url: feed.url,
     api_key: feed.api_key,
-    interval: feed.interval
+    interval: feed.interval,
+    backfill: feed.backfill,
+    cookie: feed.cookie,
+    headers: feed.headers,
+    username: feed.username,
+    password: feed.password,
+    settings: feed.settings ?? {}
   };
 
   return (
@@ -164,6 +177,9 @@ function FormFieldsTorznab() {
 
       <NumberFieldWide name="interval" label="Refresh interval"
         help="Minutes. Recommended 15-30. Too low and risk ban." />
+
+      <NumberFieldWide name="backfill" label="Backfill pages"
+        help="Extra pages to fetch when a page only has new items, eg. on first run or after downtime. 0 to disable." />
     </div>
   );
 }
@@ -178,11 +194,56 @@ function FormFieldsRSS() {
       />
 
       <NumberFieldWide name="interval" label="Refresh interval" help="Minutes. Recommended 15-30. Too low and risk ban." />
+
+      <PasswordFieldWide name="cookie" label="Cookie" help="Sent with feed and torrent requests, eg. uid=1234; pass=abcd" />
+
+      <div className="space-y-1 p-4 sm:space-y-0 sm:grid sm:grid-cols-3 sm:gap-4">
+        <div>
+          <label htmlFor="headers" className="block text-sm font-medium text-gray-900 dark:text-white sm:mt-px sm:pt-2">
+            Headers
+          </label>
+        </div>
+        <div className="sm:col-span-2">
+          <Field
+            as="textarea"
+            id="headers"
+            name="headers"
+            rows={3}
+            placeholder="X-Api-Key: abcd"
+            className="block w-full shadow-sm sm:text-sm font-mono focus:ring-indigo-500 dark:focus:ring-blue-500 focus:border-indigo-500 dark:focus:border-blue-500 border-gray-300 dark:border-gray-700 rounded-md dark:bg-gray-800 dark:text-white"
+          />
+          <p className="mt-2 text-sm text-gray-500">One header per line as Name: value</p>
+        </div>
+      </div>
+
+      <TextFieldWide name="username" label="Username" help="Basic auth" />
+      <PasswordFieldWide name="password" label="Password" help="Basic auth" />
+    </div>
+  );
+}
+
+function FormFieldsAPI() {
+  return (
+    <div className="border-t border-gray-200 dark:border-gray-700 py-5">
+      <TextFieldWide name="url" label="URL" help="Url returning the latest torrents as json" />
+
+      <NumberFieldWide name="interval" label="Refresh interval" help="Minutes. Recommended 15-30. Too low and risk ban." />
+
+      <PasswordFieldWide name="cookie" label="Cookie" help="Sent with api and torrent requests" />
+      <PasswordFieldWide name="headers" label="Auth header" help="eg. Authorization: Bearer token" />
+
+      <TextFieldWide name="settings.items" label="Items path" help="Path to the list of torrents, eg. data.torrents. Empty when the response is the list." />
+      <TextFieldWide name="settings.title" label="Title path" required={true} />
+      <TextFieldWide name="settings.download_url" label="Download url path" required={true} help="Path within an item, or a template like https://tracker/download.php?id={{ .id }}" />
+      <TextFieldWide name="settings.size" label="Size path" />
+      <TextFieldWide name="settings.category" label="Category path" />
+      <TextFieldWide name="settings.guid" label="Id path" help="Unique id used to skip seen torrents, the download url is used when empty" />
     </div>
   );
 }
 
 const componentMap: componentMapType = {
   TORZNAB: <FormFieldsTorznab />,
-  RSS: <FormFieldsRSS />
+  RSS: <FormFieldsRSS />,
+  API: <FormFieldsAPI />
 };
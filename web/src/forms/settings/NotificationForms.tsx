@@ -4,7 +4,7 @@ import type {FieldProps} from "formik";
 import {Field, Form, Formik, FormikErrors, FormikValues} from "formik";
 import {XMarkIcon} from "@heroicons/react/24/solid";
 import Select, {components, ControlProps, InputProps, MenuProps, OptionProps} from "react-select";
-import {PasswordFieldWide, SwitchGroupWide, TextFieldWide} from "../../components/inputs";
+import {NumberFieldWide, PasswordFieldWide, SwitchGroupWide, TextFieldWide} from "../../components/inputs";
 import DEBUG from "../../components/debug";
 import {EventOptions, NotificationTypeOptions, SelectOption} from "../../domain/constants";
 import {useMutation} from "react-query";
@@ -119,10 +119,95 @@ function FormFieldsTelegram() {
   );
 }
 
+function FormFieldsNtfy() {
+  return (
+    <div className="border-t border-gray-200 dark:border-gray-700 py-4">
+      <div className="px-4 space-y-1">
+        <Dialog.Title className="text-lg font-medium text-gray-900 dark:text-white">Settings</Dialog.Title>
+        <p className="text-sm text-gray-500 dark:text-gray-400">
+          Publish to a <a href="https://docs.ntfy.sh/publish/" rel="noopener noreferrer" target="_blank" className="font-medium text-blue-500 underline underline-offset-1 hover:text-blue-400">ntfy topic</a> on ntfy.sh or a self-hosted server.
+        </p>
+      </div>
+
+      <TextFieldWide
+        name="host"
+        label="Server URL"
+        help="Leave empty to use https://ntfy.sh"
+        placeholder="https://ntfy.sh"
+      />
+      <TextFieldWide
+        name="topic"
+        label="Topic"
+        help="Topic to publish to"
+        required={true}
+      />
+      <NumberFieldWide
+        name="priority"
+        label="Priority"
+        help="Message priority 1-5. 0 uses the server default."
+      />
+      <PasswordFieldWide
+        name="token"
+        label="Access token"
+        help="Access token. Takes precedence over username and password."
+      />
+      <TextFieldWide
+        name="username"
+        label="Username"
+      />
+      <PasswordFieldWide
+        name="password"
+        label="Password"
+      />
+      <TextFieldWide
+        name="click_url"
+        label="Click URL"
+        help="Opened when the notification is clicked. Supports macros, eg. https://tracker.example/torrents.php?id={{ .Release.TorrentID }}"
+      />
+    </div>
+  );
+}
+
+function FormFieldsApprise() {
+  return (
+    <div className="border-t border-gray-200 dark:border-gray-700 py-4">
+      <div className="px-4 space-y-1">
+        <Dialog.Title className="text-lg font-medium text-gray-900 dark:text-white">Settings</Dialog.Title>
+        <p className="text-sm text-gray-500 dark:text-gray-400">
+          Send through an <a href="https://github.com/caronc/apprise-api" rel="noopener noreferrer" target="_blank" className="font-medium text-blue-500 underline underline-offset-1 hover:text-blue-400">Apprise API</a> server.
+        </p>
+      </div>
+
+      <TextFieldWide
+        name="host"
+        label="Endpoint"
+        help="Stateless /notify or stateful /notify/{key} endpoint"
+        placeholder="http://localhost:8000/notify/autobrr"
+        required={true}
+      />
+      <PasswordFieldWide
+        name="targets"
+        label="URLs"
+        help="Comma separated Apprise URLs. Leave empty when using a stateful key."
+      />
+      <TextFieldWide
+        name="username"
+        label="Username"
+      />
+      <PasswordFieldWide
+        name="password"
+        label="Password"
+      />
+    </div>
+  );
+}
+
 const componentMap: componentMapType = {
   DISCORD: <FormFieldsDiscord />,
   NOTIFIARR: <FormFieldsNotifiarr />,
-  TELEGRAM: <FormFieldsTelegram />
+  TELEGRAM: <FormFieldsTelegram />,
+  NTFY: <FormFieldsNtfy />,
+  APPRISE: <FormFieldsApprise />
 };
 
 interface NotificationAddFormValues {
@@ -205,7 +290,8 @@ export function NotificationAddForm({ isOpen, toggle }: AddProps) {
                     type: "",
                     name: "",
                     webhook: "",
-                    events: []
+                    events: [],
+                    templates: {}
                   }}
                   onSubmit={onSubmit}
                   validate={validate}
@@ -317,6 +403,8 @@ export function NotificationAddForm({ isOpen, toggle }: AddProps) {
                               <EventCheckBoxes />
                             </div>
                           </div>
+
+                          <EventTemplates />
                         </div>
                         {componentMap[values.type]}
                       </div>
@@ -388,6 +476,37 @@ const EventCheckBoxes = () => (
   </fieldset>
 );
 
+const EventTemplates = () => (
+  <div className="border-t border-gray-200 dark:border-gray-700 py-4">
+    <div className="px-4 space-y-1">
+      <Dialog.Title className="text-lg font-medium text-gray-900 dark:text-white">
+        Templates
+      </Dialog.Title>
+      <p className="text-sm text-gray-500 dark:text-gray-400">
+        Optional Go templates for the message body. Leave empty for the default message.
+        Available: {"{{ .ReleaseName }}"}, {"{{ .Filter }}"}, {"{{ .Indexer }}"}, {"{{ .Action }}"}, {"{{ join .Rejections \", \" }}"}, {"{{ bytes .Size }}"} and release fields like {"{{ .Release.Resolution }}"}.
+      </p>
+    </div>
+
+    <div className="space-y-4 px-4 py-4">
+      {EventOptions.map((e) => (
+        <div key={e.value}>
+          <label htmlFor={`templates-${e.value}`} className="block text-sm font-medium text-gray-900 dark:text-white">
+            {e.label}
+          </label>
+          <Field
+            as="textarea"
+            id={`templates-${e.value}`}
+            name={`templates.${e.value}`}
+            rows={2}
+            className="mt-1 block w-full shadow-sm sm:text-sm font-mono focus:ring-blue-500 focus:border-blue-500 border-gray-300 dark:border-gray-700 rounded-md dark:bg-gray-800 dark:text-gray-100"
+          />
+        </div>
+      ))}
+    </div>
+  </div>
+);
+
 interface UpdateProps {
     isOpen: boolean;
     toggle: () => void;
@@ -403,7 +522,15 @@ interface InitialValues {
   token?: string;
   api_key?: string;
   channel?: string;
+  host?: string;
+  username?: string;
+  password?: string;
+  targets?: string;
+  topic?: string;
+  priority?: number;
+  click_url?: string;
   events: NotificationEvent[];
+  templates: Partial<Record<NotificationEvent, string>>;
 }
 
 export function NotificationUpdateForm({ isOpen, toggle, notification }: UpdateProps) {
@@ -458,7 +585,15 @@ export function NotificationUpdateForm({ isOpen, toggle, notification }: UpdateP
     token: notification.token,
     api_key: notification.api_key,
     channel: notification.channel,
-    events: notification.events || []
+    host: notification.host,
+    username: notification.username,
+    password: notification.password,
+    targets: notification.targets,
+    topic: notification.topic,
+    priority: notification.priority,
+    click_url: notification.click_url,
+    events: notification.events || [],
+    templates: notification.templates || {}
   };
 
   return (
@@ -535,6 +670,8 @@ export function NotificationUpdateForm({ isOpen, toggle, notification }: UpdateP
                 <EventCheckBoxes />
               </div>
             </div>
+
+            <EventTemplates />
           </div>
           {componentMap[values.type]}
         </div>
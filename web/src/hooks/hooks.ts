@@ -1,4 +1,4 @@
-import { useState } from "react";
+import { useEffect, useRef, useState } from "react";
 
 export function useToggle(initialValue = false): [boolean, () => void] {
   const [value, setValue] = useState(initialValue);
@@ -6,3 +6,17 @@ export function useToggle(initialValue = false): [boolean, () => void] {
 
   return [value, toggle];
 }
+
+// useEventStream calls onEvent with every event of a server-sent events stream while mounted
+export function useEventStream<T>(open: () => EventSource, onEvent: (event: T) => void) {
+  const handler = useRef(onEvent);
+  handler.current = onEvent;
+
+  useEffect(() => {
+    const es = open();
+
+    es.onmessage = (event) => handler.current(JSON.parse(event.data) as T);
+
+    return () => es.close();
+  }, [open]);
+}
@@ -1,10 +1,16 @@
 import {NavLink, Outlet, useLocation} from "react-router-dom";
 import {
+  ArchiveBoxIcon,
+  BeakerIcon,
   BellIcon,
+  CheckBadgeIcon,
+  ClipboardDocumentListIcon,
   ChatBubbleLeftRightIcon,
   CogIcon,
   FolderArrowDownIcon,
+  ListBulletIcon,
   KeyIcon,
+  NoSymbolIcon,
   RectangleStackIcon,
   RssIcon
 } from "@heroicons/react/24/outline";
@@ -25,7 +31,13 @@ const subNavigation: NavTabType[] = [
   { name: "Clients", href: "clients", icon: FolderArrowDownIcon },
   { name: "Notifications", href: "notifications", icon: BellIcon },
   { name: "API keys", href: "api-keys", icon: KeyIcon },
-  { name: "Releases", href: "releases", icon: RectangleStackIcon }
+  { name: "Releases", href: "releases", icon: RectangleStackIcon },
+  { name: "Approvals", href: "approvals", icon: CheckBadgeIcon },
+  { name: "Backups", href: "backups", icon: ArchiveBoxIcon },
+  { name: "Lists", href: "lists", icon: ListBulletIcon },
+  { name: "Blocklist", href: "blocklist", icon: NoSymbolIcon },
+  { name: "Audit log", href: "audit", icon: ClipboardDocumentListIcon },
+  { name: "Filter test", href: "filter-test", icon: BeakerIcon }
   // {name: 'Regex Playground', href: 'regex-playground', icon: CogIcon, current: false}
   // {name: 'Rules', href: 'rules', icon: ClipboardCheckIcon, current: false},
 ];
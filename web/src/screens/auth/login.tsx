@@ -1,11 +1,12 @@
 import { useEffect } from "react";
 import { useForm } from "react-hook-form";
-import { useNavigate } from "react-router-dom";
-import { useMutation } from "react-query";
+import { useNavigate, useSearchParams } from "react-router-dom";
+import { useMutation, useQuery } from "react-query";
 
 import logo from "../../logo.png";
 import { APIClient } from "../../api/APIClient";
 import { AuthContext } from "../../utils/Context";
+import { baseUrl } from "../../utils";
 import { PasswordInput, TextInput } from "../../components/inputs/text";
 
 type LoginFormFields = {
@@ -19,14 +20,32 @@ export const Login = () => {
     mode: "onBlur"
   });
   const navigate = useNavigate();
+  const [searchParams] = useSearchParams();
   const [, setAuthContext] = AuthContext.use();
 
+  const { data: oidcConfig } = useQuery(
+    "oidcConfig",
+    () => APIClient.auth.oidcConfig(),
+    { retry: false, refetchOnWindowFocus: false }
+  );
+
   useEffect(() => {
     // Check if onboarding is available for this instance
     // and redirect if needed
     APIClient.auth.canOnboard()
       .then(() => navigate("/onboard"))
       .catch(() => { /*don't log to console PAHLLEEEASSSE*/ });
+
+    // OpenID Connect logins come back here with a session already set
+    APIClient.auth.validate()
+      .then(() => {
+        setAuthContext({
+          username: "",
+          isLoggedIn: true
+        });
+        navigate("/");
+      })
+      .catch(() => { /* not logged in */ });
   }, []);
 
   const loginMutation = useMutation(
@@ -87,6 +106,21 @@ export const Login = () => {
               </button>
             </div>
           </form>
+
+          {oidcConfig?.enabled && (
+            <div className="mt-6">
+              <a
+                href={`${baseUrl()}api/auth/oidc/login`}
+                className="w-full flex justify-center py-2 px-4 border border-gray-300 dark:border-gray-700 rounded-md shadow-sm text-sm font-medium text-gray-700 dark:text-gray-200 bg-white dark:bg-gray-800 hover:bg-gray-50 dark:hover:bg-gray-700 focus:outline-none focus:ring-2 focus:ring-offset-2 focus:ring-indigo-500 dark:focus:ring-blue-500"
+              >
+                Sign in with OpenID Connect
+              </a>
+            </div>
+          )}
+
+          {searchParams.get("error") === "oidc" && (
+            <p className="mt-4 text-sm text-center text-red-500">OpenID Connect login failed</p>
+          )}
         </div>
       </div>
     </div>
@@ -42,6 +42,7 @@ export const Stats = () => {
         {/* <StatsItem name="Filter Rejected Releases" stat={data?.filter_rejected_count} /> */}
         <StatsItem name="Rejected Pushes" value={data?.push_rejected_count} />
         <StatsItem name="Approved Pushes" value={data?.push_approved_count} />
+        <StatsItem name="Blocked Announces" value={data?.blocked_count} />
       </dl>
     </div>
   );
@@ -1,11 +1,35 @@
+import { useRef } from "react";
+
 import { Stats } from "./Stats";
 import { ActivityTable } from "./ActivityTable";
+import { APIClient } from "../../api/APIClient";
+import { queryClient } from "../../App";
+import { useEventStream } from "../../hooks/hooks";
+
+export const Dashboard = () => {
+  const refresh = useRef<ReturnType<typeof setTimeout>>();
+
+  // matched releases and action results change the stats and recent activity, refresh at most once a second
+  useEventStream<ReleaseEvent>(APIClient.events.releases, (event) => {
+    if (event.type !== "MATCH" && event.type !== "ACTION")
+      return;
+
+    if (refresh.current)
+      return;
+
+    refresh.current = setTimeout(() => {
+      refresh.current = undefined;
+      queryClient.invalidateQueries("dash_recent_releases");
+      queryClient.invalidateQueries("dash_release_stats");
+    }, 1000);
+  });
 
-export const Dashboard = () => (
-  <main className="py-10">
-    <div className="max-w-screen-xl mx-auto pb-6 px-4 sm:px-6 lg:pb-16 lg:px-8">
-      <Stats />
-      <ActivityTable />
-    </div>
-  </main>
-);
\ No newline at end of file
+  return (
+    <main className="py-10">
+      <div className="max-w-screen-xl mx-auto pb-6 px-4 sm:px-6 lg:pb-16 lg:px-8">
+        <Stats />
+        <ActivityTable />
+      </div>
+    </main>
+  );
+};
\ No newline at end of file
@@ -25,6 +25,12 @@ export function FilterActions({ filter, values }: FilterActionsProps) {
     { refetchOnWindowFocus: false }
   );
 
+  const { data: filters } = useQuery(
+    ["filters"],
+    () => APIClient.filters.getAll(),
+    { refetchOnWindowFocus: false }
+  );
+
   const newAction = {
     name: "new action",
     enabled: true,
@@ -32,11 +38,16 @@ export function FilterActions({ filter, values }: FilterActionsProps) {
     watch_folder: "",
     exec_cmd: "",
     exec_args: "",
+    exec_timeout: 0,
     category: "",
     tags: "",
     label: "",
     save_path: "",
+    move_completed_path: "",
     paused: false,
+    bandwidth_priority: 0,
+    bandwidth_group: "",
+    sequential_download: false,
     ignore_rules: false,
     skip_hash_check: false,
     content_layout: "",
@@ -53,7 +64,8 @@ export function FilterActions({ filter, values }: FilterActionsProps) {
     webhook_type: "",
     webhook_method: "",
     webhook_data: "",
-    webhook_headers: []
+    webhook_headers: [],
+    run_condition: ""
     //   client_id: 0,
   };
 
@@ -84,7 +96,7 @@ export function FilterActions({ filter, values }: FilterActionsProps) {
               {values.actions.length > 0 ?
                 <ul className="divide-y divide-gray-200 dark:divide-gray-700">
                   {values.actions.map((action: Action, index: number) => (
-                    <FilterActionsItem action={action} clients={data ?? []} idx={index} remove={remove} key={index}/>
+                    <FilterActionsItem action={action} clients={data ?? []} filters={(filters ?? []).filter((f) => f.id !== filter.id)} idx={index} remove={remove} key={index}/>
                   ))}
                 </ul>
                 : <EmptyListState text="No actions yet!"/>
@@ -128,16 +140,31 @@ const TypeForm = ({ action, idx, clients }: TypeFormProps) => {
             placeholder="Arguments eg. --test"
           />
         </div>
+        <div className="mt-6 grid grid-cols-12 gap-6">
+          <div className="col-span-6">
+            <NumberField
+              name={`actions.${idx}.exec_timeout`}
+              label="Timeout (seconds)"
+              placeholder="Default 300. Release fields are also passed as AUTOBRR_* environment variables"
+            />
+          </div>
+        </div>
       </div>
     );
   case "WATCH_FOLDER":
     return (
       <div className="mt-6 grid grid-cols-12 gap-6">
+        <DownloadClientSelect
+          name={`actions.${idx}.client_id`}
+          action={action}
+          clients={clients}
+          label="Client (optional)"
+        />
         <TextField
           name={`actions.${idx}.watch_folder`}
           label="Watch folder"
           columns={6}
-          placeholder="Watch directory eg. /home/user/rwatch"
+          placeholder="Watch directory eg. /home/user/rwatch, a subfolder with a client"
         />
       </div>
     );
@@ -193,6 +220,15 @@ const TypeForm = ({ action, idx, clients }: TypeFormProps) => {
           />
         </div>
 
+        <div className="mt-6 grid grid-cols-12 gap-6">
+          <DownloadClientSelect
+            name={`actions.${idx}.fallback_client_id`}
+            action={action}
+            clients={clients}
+            label="Fallback client"
+          />
+        </div>
+
         <CollapsableSection title="Rules" subtitle="client options">
           <div className="col-span-12">
             <div className="mt-6 grid grid-cols-12 gap-6">
@@ -295,12 +331,22 @@ const TypeForm = ({ action, idx, clients }: TypeFormProps) => {
           </div>
         </div>
 
-        <div className="mt-6 col-span-12 sm:col-span-6">
-          <TextField
-            name={`actions.${idx}.label`}
-            label="Label"
-            columns={6}
-          />
+        <div className="mt-6 grid grid-cols-12 gap-6">
+          <div className="col-span-12 sm:col-span-6">
+            <TextField
+              name={`actions.${idx}.label`}
+              label="Label"
+              columns={6}
+            />
+          </div>
+
+          <div className="col-span-12 sm:col-span-6">
+            <TextField
+              name={`actions.${idx}.move_completed_path`}
+              label="Move completed path"
+              columns={6}
+            />
+          </div>
         </div>
 
         <div className="mt-6 grid grid-cols-12 gap-6">
@@ -347,6 +393,24 @@ const TypeForm = ({ action, idx, clients }: TypeFormProps) => {
               name={`actions.${idx}.save_path`}
               label="Save path"
               columns={6}
+              placeholder="eg. /home/user/downloads/{{ .Indexer }}"
+            />
+          </div>
+        </div>
+
+        <div className="mt-6 grid grid-cols-12 gap-6">
+          <div className="col-span-6">
+            <SwitchGroup
+              name={`actions.${idx}.paused`}
+              label="Add stopped"
+              description="Add torrent without starting it"
+            />
+          </div>
+          <div className="col-span-6">
+            <SwitchGroup
+              name={`actions.${idx}.skip_hash_check`}
+              label="Fast resume"
+              description="Seed content already in the save path without checking it. Needs the same path on the autobrr host"
             />
           </div>
         </div>
@@ -367,11 +431,120 @@ const TypeForm = ({ action, idx, clients }: TypeFormProps) => {
               name={`actions.${idx}.save_path`}
               label="Save path"
               columns={6}
+              placeholder="eg. /full/path/to/download_folder"
+            />
+          </div>
+        </div>
+
+        <div className="mt-6 grid grid-cols-12 gap-6">
+          <TextField
+            name={`actions.${idx}.label`}
+            label="Labels"
+            columns={6}
+            placeholder="eg. label1,label2 (transmission 4+)"
+          />
+          <TextField
+            name={`actions.${idx}.bandwidth_group`}
+            label="Bandwidth group"
+            columns={6}
+            placeholder="eg. limited (transmission 4+)"
+          />
+        </div>
+
+        <div className="mt-6 grid grid-cols-12 gap-6">
+          <div className="col-span-6">
+            <NumberField
+              name={`actions.${idx}.bandwidth_priority`}
+              label="Bandwidth priority"
+              placeholder="-1 low, 0 normal, 1 high"
+            />
+          </div>
+        </div>
+
+        <div className="mt-6 grid grid-cols-12 gap-6">
+          <div className="col-span-6">
+            <SwitchGroup
+              name={`actions.${idx}.paused`}
+              label="Add paused"
+            />
+            <SwitchGroup
+              name={`actions.${idx}.sequential_download`}
+              label="Sequential download"
+              description="Download pieces in order (transmission 4+)"
+            />
+          </div>
+        </div>
+      </div>
+    );
+  case "PORLA":
+    return (
+      <div>
+        <div className="mt-6 grid grid-cols-12 gap-6">
+          <DownloadClientSelect
+            name={`actions.${idx}.client_id`}
+            action={action}
+            clients={clients}
+          />
+
+          <div className="col-span-12 sm:col-span-6">
+            <TextField
+              name={`actions.${idx}.save_path`}
+              label="Save path"
+              columns={6}
+              placeholder="eg. /full/path/to/download_folder"
             />
           </div>
         </div>
 
         <div className="mt-6 grid grid-cols-12 gap-6">
+          <TextField
+            name={`actions.${idx}.label`}
+            label="Preset"
+            columns={6}
+            placeholder="eg. default"
+          />
+          <TextField
+            name={`actions.${idx}.tags`}
+            label="Tags"
+            columns={6}
+            placeholder="eg. tag1,tag2"
+          />
+        </div>
+      </div>
+    );
+  case "CROSS_SEED":
+    return (
+      <div>
+        <div className="mt-6 grid grid-cols-12 gap-6">
+          <DownloadClientSelect
+            name={`actions.${idx}.client_id`}
+            action={action}
+            clients={clients}
+          />
+        </div>
+
+        <div className="mt-6 grid grid-cols-12 gap-6">
+          <TextField
+            name={`actions.${idx}.category`}
+            label="Category"
+            columns={6}
+            placeholder="eg. category (qBittorrent)"
+          />
+          <TextField
+            name={`actions.${idx}.tags`}
+            label="Tags"
+            columns={6}
+            placeholder="eg. tag1,tag2 (qBittorrent)"
+          />
+        </div>
+
+        <div className="mt-6 grid grid-cols-12 gap-6">
+          <TextField
+            name={`actions.${idx}.label`}
+            label="Label"
+            columns={6}
+            placeholder="eg. label (Deluge)"
+          />
           <div className="col-span-6">
             <SwitchGroup
               name={`actions.${idx}.paused`}
@@ -386,12 +559,30 @@ const TypeForm = ({ action, idx, clients }: TypeFormProps) => {
   case "LIDARR":
   case "WHISPARR":
     return (
-      <div className="mt-6 grid grid-cols-12 gap-6">
-        <DownloadClientSelect
-          name={`actions.${idx}.client_id`}
-          action={action}
-          clients={clients}
-        />
+      <div>
+        <div className="mt-6 grid grid-cols-12 gap-6">
+          <DownloadClientSelect
+            name={`actions.${idx}.client_id`}
+            action={action}
+            clients={clients}
+          />
+        </div>
+
+        <div className="mt-6 grid grid-cols-12 gap-6">
+          <TextField
+            name={`actions.${idx}.external_download_client`}
+            label="Override download client name"
+            columns={6}
+            placeholder="eg. qBittorrent or qbit-{{ .Indexer }}, supports macros"
+          />
+          <div className="col-span-6">
+            <NumberField
+              name={`actions.${idx}.external_download_client_id`}
+              label="Override download client id"
+              placeholder="Download client id in the arr, takes precedence over the name"
+            />
+          </div>
+        </div>
       </div>
     );
 
@@ -403,11 +594,42 @@ const TypeForm = ({ action, idx, clients }: TypeFormProps) => {
 interface FilterActionsItemProps {
   action: Action;
   clients: DownloadClient[];
+  filters: Filter[];
   idx: number;
   remove: <T>(index: number) => T | undefined;
 }
 
-function FilterActionsItem({ action, clients, idx, remove }: FilterActionsItemProps) {
+interface FilterDependencySelectProps {
+  name: string;
+  filters: Filter[];
+}
+
+const FilterDependencySelect = ({ name, filters }: FilterDependencySelectProps) => (
+  <div className="col-span-6">
+    <Field name={name}>
+      {({ field, form: { setFieldValue } }: FieldProps) => (
+        <>
+          <label htmlFor={name} className="block text-xs font-bold text-gray-700 dark:text-gray-200 uppercase tracking-wide">
+            Only if filter also matched
+          </label>
+          <select
+            id={name}
+            value={field.value ?? 0}
+            onChange={(e) => setFieldValue(name, parseInt(e.target.value))}
+            className="mt-2 block w-full border border-gray-300 dark:border-gray-700 rounded-md shadow-sm py-2.5 bg-white dark:bg-gray-800 dark:text-gray-200 sm:text-sm"
+          >
+            <option value={0}>Always run</option>
+            {filters.map((f) => (
+              <option key={f.id} value={f.id}>{f.name}</option>
+            ))}
+          </select>
+        </>
+      )}
+    </Field>
+  </div>
+);
+
+function FilterActionsItem({ action, clients, filters, idx, remove }: FilterActionsItemProps) {
   const cancelButtonRef = useRef(null);
 
   const [deleteModalIsOpen, toggleDeleteModal] = useToggle(false);
@@ -512,6 +734,16 @@ function FilterActionsItem({ action, clients, idx, remove }: FilterActionsItemPr
               <TextField name={`actions.${idx}.name`} label="Name" columns={6}/>
             </div>
 
+            <div className="mt-6 grid grid-cols-12 gap-6">
+              <FilterDependencySelect name={`actions.${idx}.depends_on_filter_id`} filters={filters}/>
+              <TextField
+                name={`actions.${idx}.run_condition`}
+                label="Run condition"
+                columns={6}
+                placeholder={"Optional, eg. release.Size > 10GB && \"internal\" in release.Tags"}
+              />
+            </div>
+
             <TypeForm action={action} clients={clients} idx={idx}/>
 
             <div className="pt-6 divide-y divide-gray-200">
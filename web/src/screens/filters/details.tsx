@@ -9,6 +9,9 @@ import {
   CODECS_OPTIONS,
   CONTAINER_OPTIONS,
   downloadsPerUnitOptions,
+  sizePreferenceOptions,
+  dedupKeyOptions,
+  webhookTypeOptions,
   FORMATS_OPTIONS,
   HDR_OPTIONS,
   ORIGIN_OPTIONS,
@@ -39,6 +42,7 @@ import {DeleteModal} from "../../components/modals";
 import {TitleSubtitle} from "../../components/headings";
 import {TextArea} from "../../components/inputs/input";
 import {FilterActions} from "./action";
+import {FilterRejections} from "./rejections";
 
 interface tabType {
   name: string;
@@ -51,7 +55,8 @@ const tabs: tabType[] = [
   { name: "Music", href: "music" },
   { name: "Advanced", href: "advanced" },
   { name: "External", href: "external" },
-  { name: "Actions", href: "actions" }
+  { name: "Actions", href: "actions" },
+  { name: "Rejections", href: "rejections" }
 ];
 
 export interface NavLinkProps {
@@ -235,6 +240,8 @@ export default function FilterDetails() {
                 max_downloads_unit: filter.max_downloads_unit,
                 use_regex: filter.use_regex || false,
                 shows: filter.shows,
+                match_list_id: filter.match_list_id ?? 0,
+                except_list_id: filter.except_list_id ?? 0,
                 years: filter.years,
                 resolutions: filter.resolutions || [],
                 sources: filter.sources || [],
@@ -254,6 +261,22 @@ export default function FilterDetails() {
                 except_categories: filter.except_categories,
                 tags: filter.tags,
                 except_tags: filter.except_tags,
+                capture_patterns: filter.capture_patterns,
+                smart_delay: filter.smart_delay,
+                smart_delay_indexers: filter.smart_delay_indexers,
+                smart_delay_prefer_size: filter.smart_delay_prefer_size,
+                dedup_key: filter.dedup_key,
+                dedup_window: filter.dedup_window,
+                arr_skip_duplicates: filter.arr_skip_duplicates,
+                arr_only_monitored: filter.arr_only_monitored,
+                arr_season_pack_threshold: filter.arr_season_pack_threshold,
+                schedule: filter.schedule,
+                torrent_file_check: filter.torrent_file_check,
+                require_approval: filter.require_approval || false,
+                min_files: filter.min_files,
+                max_files: filter.max_files,
+                match_file_extensions: filter.match_file_extensions,
+                except_file_extensions: filter.except_file_extensions,
                 match_uploaders: filter.match_uploaders,
                 except_uploaders: filter.except_uploaders,
                 freeleech: filter.freeleech,
@@ -262,6 +285,7 @@ export default function FilterDetails() {
                 quality: filter.quality || [],
                 media: filter.media || [],
                 match_release_types: filter.match_release_types || [],
+                except_release_types: filter.except_release_types || [],
                 log_score: filter.log_score,
                 log: filter.log,
                 cue: filter.cue,
@@ -279,7 +303,9 @@ export default function FilterDetails() {
                 external_webhook_enabled: filter.external_webhook_enabled || false,
                 external_webhook_host: filter.external_webhook_host || "",
                 external_webhook_data: filter.external_webhook_data ||"",
-                external_webhook_expect_status: filter.external_webhook_expect_status || 0
+                external_webhook_expect_status: filter.external_webhook_expect_status || 0,
+                external_webhook_type: filter.external_webhook_type || "HTTP",
+                external_webhook_parse_body: filter.external_webhook_parse_body || false
               } as Filter}
               onSubmit={handleSubmit}
             >
@@ -293,6 +319,7 @@ export default function FilterDetails() {
                     <Route path="external" element={<External />} />
                     <Route path="actions" element={<FilterActions filter={filter} values={values} />}
                     />
+                    <Route path="rejections" element={<FilterRejections filterID={filter.id} />} />
                   </Routes>
                   <FormButtonsGroup values={values} deleteAction={deleteAction} dirty={dirty} reset={resetForm} />
                   <DEBUG values={values} />
@@ -345,6 +372,52 @@ export function General() {
         </div>
       </div>
 
+      <div className="mt-6 lg:pb-8">
+        <TitleSubtitle title="Schedule" subtitle="Only match during these weekly windows, eg. for freeleech events. Times use the timezone from the config." />
+
+        <div className="mt-6 grid grid-cols-12 gap-6">
+          <TextField name="schedule" label="Active" columns={12} placeholder="eg. sat-sun 00:00-24:00, mon-fri 22:00-06:00" />
+        </div>
+      </div>
+
+      <div className="mt-6 lg:pb-8">
+        <TitleSubtitle title="Smart delay" subtitle="Hold matches for a number of seconds and only grab the best announce of the same title. Ranked by quality, then indexer order, then size." />
+
+        <div className="mt-6 grid grid-cols-12 gap-6">
+          <NumberField name="smart_delay" label="Window (seconds)" placeholder="0 to disable" />
+          <Select name="smart_delay_prefer_size" label="Size tiebreak" options={sizePreferenceOptions} optionDefaultText="None" />
+          <TextField name="smart_delay_indexers" label="Preferred indexers" columns={12} placeholder="eg. indexer1,indexer2" />
+        </div>
+      </div>
+
+      <div className="mt-6 lg:pb-8">
+        <TitleSubtitle title="Duplicates" subtitle="Skip releases when the same release was already approved within the window, eg. the same episode announced on several indexers." />
+
+        <div className="mt-6 grid grid-cols-12 gap-6">
+          <Select name="dedup_key" label="Same release by" options={dedupKeyOptions} optionDefaultText="Default from config" />
+          <NumberField name="dedup_window" label="Window (hours)" placeholder="0 for the default from config" />
+        </div>
+      </div>
+
+      <div className="mt-6 lg:pb-8">
+        <TitleSubtitle title="Manual approval" subtitle="Hold matches until they are approved in Settings or from the links of the Release pending approval notification. The actions run once approved." />
+
+        <div className="mt-6 grid grid-cols-12 gap-6">
+          <div className="col-span-12">
+            <SwitchGroup name="require_approval" label="Require approval" description="Useful for expensive grabs, eg. releases that are not freeleech" />
+          </div>
+        </div>
+      </div>
+
+      <div className="border-t dark:border-gray-700">
+        <SwitchGroup name="arr_skip_duplicates" label="Skip arr duplicates" description="Check the Sonarr, Radarr, Lidarr and Whisparr queue and history before pushing and skip releases already grabbed or imported" />
+        <SwitchGroup name="arr_only_monitored" label="Only monitored in Lidarr" description="Look up the artist and album in Lidarr before pushing and skip releases that are not monitored" />
+      </div>
+
+      <div className="mt-6 grid grid-cols-12 gap-6">
+        <NumberField name="arr_season_pack_threshold" label="Skip season packs in Sonarr and Whisparr at (% of episodes on disk)" placeholder="0 to disable" />
+      </div>
+
       <div className="border-t dark:border-gray-700">
         <SwitchGroup name="enabled" label="Enabled" description="Enable or disable this filter" />
       </div>
@@ -354,6 +427,13 @@ export function General() {
 }
 
 export function MoviesTv() {
+  const { data: lists } = useQuery("lists", () => APIClient.lists.list(), { refetchOnWindowFocus: false });
+
+  const listOptions = [
+    { label: "None", value: 0 },
+    ...(lists ?? []).map((l) => ({ label: l.name, value: l.id }))
+  ];
+
   return (
     <div>
       <div className="mt-6 grid grid-cols-12 gap-6">
@@ -361,6 +441,11 @@ export function MoviesTv() {
         <TextField name="years" label="Years" columns={4} placeholder="eg. 2018,2019-2021" />
       </div>
 
+      <div className="mt-6 grid grid-cols-12 gap-6">
+        <Select name="match_list_id" label="Match list" optionDefaultText="Select list" options={listOptions} />
+        <Select name="except_list_id" label="Except list" optionDefaultText="Select list" options={listOptions} />
+      </div>
+
       <div className="mt-6 lg:pb-8">
         <TitleSubtitle title="Seasons and Episodes" subtitle="Set season and episode match constraints" />
 
@@ -417,6 +502,7 @@ export function Music() {
         <div className="mt-6 grid grid-cols-12 gap-6">
           <MultiSelect name="media" options={SOURCES_MUSIC_OPTIONS} label="Media" columns={6} />
           <MultiSelect name="match_release_types" options={RELEASE_TYPE_MUSIC_OPTIONS} label="Type" columns={6} />
+          <MultiSelect name="except_release_types" options={RELEASE_TYPE_MUSIC_OPTIONS} label="Except type" columns={6} />
         </div>
 
         <div className="mt-6 grid grid-cols-12 gap-6">
@@ -475,6 +561,14 @@ export function Advanced() {
             <SwitchGroup name="use_regex" label="Use Regex" />
           </div>
         </div>
+
+        <TextArea
+          name="capture_patterns"
+          label="Capture patterns"
+          columns={6}
+          rows={3}
+          placeholder={"One regex per line, prefix with tags: to match tags. eg. ^(?P<show>.+?)\\.S\\d+\nCapture groups are available as {{ .Match.show }} or {{ .Match.group1 }} in actions"}
+        />
       </CollapsableSection>
 
       <CollapsableSection defaultOpen={true} title="Groups" subtitle="Match only certain groups and/or ignore other groups">
@@ -507,6 +601,17 @@ export function Advanced() {
 
         <TextField name="freeleech_percent" label="Freeleech percent" columns={6} />
       </CollapsableSection>
+
+      <CollapsableSection defaultOpen={true} title="Torrent file" subtitle="Download the torrent file before actions run and check the actual size and files. Magnet links are rejected.">
+        <div className="col-span-12">
+          <SwitchGroup name="torrent_file_check" label="Check torrent file" description="Min and max size are checked against the torrent file too" />
+        </div>
+
+        <NumberField name="min_files" label="Min files" placeholder="0 to disable" />
+        <NumberField name="max_files" label="Max files" placeholder="0 to disable" />
+        <TextField name="match_file_extensions" label="Match file extensions" columns={6} placeholder="eg. mkv,srt,nfo" />
+        <TextField name="except_file_extensions" label="Except file extensions" columns={6} placeholder="eg. rar,exe" />
+      </CollapsableSection>
     </div>
   );
 }
@@ -581,7 +686,7 @@ export function External() {
 
       <div className="mt-6">
         <div className="border-t dark:border-gray-700">
-          <SwitchGroup name="external_webhook_enabled" heading={true} label="Webhook" description="Run external webhook and check status as part of filtering" />
+          <SwitchGroup name="external_webhook_enabled" heading={true} label="Webhook" description="Run external webhook or gRPC check as part of filtering. Checks can reply with {\"approved\": bool, \"reason\": \"...\"}" />
         </div>
 
         <div className="mt-6 grid grid-cols-12 gap-6">
@@ -590,14 +695,20 @@ export function External() {
               name="external_webhook_host"
               label="Host"
               columns={6}
-              placeholder="Host eg. http://localhost/webhook"
+              placeholder="Host eg. http://localhost/webhook or grpc://localhost:50051/autobrr.ExternalFilter/Check"
               disabled={!values.external_webhook_enabled}
             />
+            <Select name="external_webhook_type" label="Type" options={webhookTypeOptions} optionDefaultText="HTTP" />
             <NumberField
               name="external_webhook_expect_status"
               label="Expected http status"
               placeholder="200"
-              disabled={!values.external_webhook_enabled}
+              disabled={!values.external_webhook_enabled || values.external_webhook_type === "GRPC"}
+            />
+            <CheckboxField
+              name="external_webhook_parse_body"
+              label="Parse response"
+              sublabel="Read approved and reason from the json response body"
             />
           </div>
 
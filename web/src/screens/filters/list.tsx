@@ -1,9 +1,10 @@
-import {Dispatch, FC, Fragment, MouseEventHandler, useReducer, useRef, useState} from "react";
+import {ChangeEvent, Dispatch, FC, Fragment, MouseEventHandler, useReducer, useRef, useState} from "react";
 import {Link} from "react-router-dom";
 import {toast} from "react-hot-toast";
 import {Listbox, Menu, Switch, Transition} from "@headlessui/react";
 import {useMutation, useQuery, useQueryClient} from "react-query";
 import {
+  ArrowDownTrayIcon,
   ArrowsRightLeftIcon,
   CheckIcon,
   ChevronDownIcon,
@@ -72,6 +73,35 @@ const FilterListReducer = (state: FilterListState, action: Actions): FilterListS
 
 export default function Filters() {
   const [createFilterIsOpen, toggleCreateFilter] = useToggle(false);
+  const [importConflict, setImportConflict] = useState<FilterImportConflict>("rename");
+  const importInputRef = useRef<HTMLInputElement>(null);
+
+  const importMutation = useMutation(
+    (document: string) => APIClient.filters.import(document, importConflict),
+    {
+      onSuccess: (result) => {
+        queryClient.invalidateQueries(["filters"]);
+
+        const missing = result.missing_indexers?.length ? `, missing indexers: ${result.missing_indexers.join(", ")}` : "";
+        toast.custom((t) => <Toast type="success" body={`Filter ${result.filter.name} imported${missing}`} t={t} />);
+      },
+      onError: (error: Error) => {
+        toast.custom((t) => <Toast type="error" body={`Could not import filter: ${error.message}`} t={t} />);
+      }
+    }
+  );
+
+  const onImportFile = (e: ChangeEvent<HTMLInputElement>) => {
+    const file = e.target.files?.[0];
+    if (!file) {
+      return;
+    }
+
+    file.text().then((document) => importMutation.mutate(document));
+
+    // allow importing the same file again
+    e.target.value = "";
+  };
 
   return (
     <main>
@@ -82,7 +112,31 @@ export default function Filters() {
           <h1 className="text-3xl font-bold text-black dark:text-white">
             Filters
           </h1>
-          <div className="flex-shrink-0">
+          <div className="flex flex-shrink-0 space-x-2">
+            <select
+              className="block pl-3 pr-10 py-2 text-sm rounded-md border-gray-300 dark:border-gray-700 bg-white dark:bg-gray-800 text-gray-900 dark:text-gray-100"
+              title="When a filter with the same name exists"
+              value={importConflict}
+              onChange={(e) => setImportConflict(e.target.value as FilterImportConflict)}
+            >
+              <option value="rename">Rename on conflict</option>
+              <option value="overwrite">Overwrite on conflict</option>
+              <option value="merge">Merge on conflict</option>
+            </select>
+            <input
+              ref={importInputRef}
+              type="file"
+              accept=".json,.yaml,.yml"
+              className="hidden"
+              onChange={onImportFile}
+            />
+            <button
+              type="button"
+              className="relative inline-flex items-center px-4 py-2 border border-gray-300 dark:border-gray-700 shadow-sm text-sm font-medium rounded-md text-gray-700 dark:text-gray-200 bg-white dark:bg-gray-800 hover:bg-gray-50 dark:hover:bg-gray-700 focus:outline-none focus:ring-2 focus:ring-offset-2 focus:ring-indigo-500 dark:focus:ring-blue-500"
+              onClick={() => importInputRef.current?.click()}
+            >
+              Import
+            </button>
             <button
               type="button"
               className="relative inline-flex items-center px-4 py-2 border border-transparent shadow-sm text-sm font-medium rounded-md text-white bg-indigo-600 dark:bg-blue-600 hover:bg-indigo-700 dark:hover:bg-blue-700 focus:outline-none focus:ring-2 focus:ring-offset-2 focus:ring-indigo-500 dark:focus:ring-blue-500"
@@ -328,6 +382,48 @@ const FilterItemDropdown = ({
                 </button>
               )}
             </Menu.Item>
+            <Menu.Item>
+              {({ active }) => (
+                <a
+                  href={APIClient.filters.exportUrl(filter.id, "json")}
+                  download
+                  className={classNames(
+                    active ? "bg-blue-600 text-white" : "text-gray-900 dark:text-gray-300",
+                    "font-medium group flex rounded-md items-center w-full px-2 py-2 text-sm"
+                  )}
+                >
+                  <ArrowDownTrayIcon
+                    className={classNames(
+                      active ? "text-white" : "text-blue-500",
+                      "w-5 h-5 mr-2"
+                    )}
+                    aria-hidden="true"
+                  />
+                  Export JSON
+                </a>
+              )}
+            </Menu.Item>
+            <Menu.Item>
+              {({ active }) => (
+                <a
+                  href={APIClient.filters.exportUrl(filter.id, "yaml")}
+                  download
+                  className={classNames(
+                    active ? "bg-blue-600 text-white" : "text-gray-900 dark:text-gray-300",
+                    "font-medium group flex rounded-md items-center w-full px-2 py-2 text-sm"
+                  )}
+                >
+                  <ArrowDownTrayIcon
+                    className={classNames(
+                      active ? "text-white" : "text-blue-500",
+                      "w-5 h-5 mr-2"
+                    )}
+                    aria-hidden="true"
+                  />
+                  Export YAML
+                </a>
+              )}
+            </Menu.Item>
           </div>
           <div className="px-1 py-1">
             <Menu.Item>
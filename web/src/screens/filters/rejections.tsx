@@ -0,0 +1,63 @@
+import { useState } from "react";
+import { useQuery } from "react-query";
+
+import { APIClient } from "../../api/APIClient";
+import { TitleSubtitle } from "../../components/headings";
+
+const windowOptions = [
+  { label: "Last hour", value: 1 },
+  { label: "Last 24 hours", value: 24 },
+  { label: "Last 7 days", value: 168 },
+  { label: "Last 30 days", value: 720 }
+];
+
+interface FilterRejectionsProps {
+  filterID: number;
+}
+
+export const FilterRejections = ({ filterID }: FilterRejectionsProps) => {
+  const [hours, setHours] = useState(24);
+
+  const { isLoading, data } = useQuery(
+    ["filters", filterID, "rejections", hours],
+    () => APIClient.filters.rejections(filterID, hours),
+    { refetchOnWindowFocus: false }
+  );
+
+  return (
+    <div className="mt-6">
+      <div className="flex items-start justify-between">
+        <TitleSubtitle title="Rejections" subtitle="Why releases were rejected by this filter. Use it to find the checks that reject the most." />
+        <select
+          value={hours}
+          onChange={(e) => setHours(Number(e.target.value))}
+          className="ml-4 block dark:bg-gray-800 border border-gray-300 dark:border-gray-700 rounded-md shadow-sm py-2 px-3 focus:outline-none focus:ring-blue-500 focus:border-blue-500 dark:text-gray-100 sm:text-sm"
+        >
+          {windowOptions.map((o) => (
+            <option key={o.value} value={o.value}>{o.label}</option>
+          ))}
+        </select>
+      </div>
+
+      {isLoading ? null : !data || data.reasons.length === 0 ? (
+        <p className="mt-6 text-sm text-gray-500 dark:text-gray-400">No rejections in this period.</p>
+      ) : (
+        <ul className="mt-6 divide-y divide-gray-200 dark:divide-gray-700">
+          {data.reasons.map((r) => (
+            <li key={r.reason} className="py-2">
+              <div className="flex justify-between text-sm">
+                <span className="text-gray-900 dark:text-white">{r.reason}</span>
+                <span className="text-gray-500 dark:text-gray-400">
+                  {r.count} ({Math.round((r.count / data.total) * 100)}%)
+                </span>
+              </div>
+              <div className="mt-1 h-1.5 w-full rounded bg-gray-200 dark:bg-gray-700">
+                <div className="h-1.5 rounded bg-blue-500" style={{ width: `${(r.count / data.total) * 100}%` }} />
+              </div>
+            </li>
+          ))}
+        </ul>
+      )}
+    </div>
+  );
+};
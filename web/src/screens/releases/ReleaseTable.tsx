@@ -2,6 +2,7 @@ import * as React from "react";
 import {useQuery} from "react-query";
 import {Column, useFilters, usePagination, useSortBy, useTable} from "react-table";
 import {
+  ArrowDownTrayIcon,
   ChevronDoubleLeftIcon,
   ChevronDoubleRightIcon,
   ChevronLeftIcon,
@@ -15,6 +16,7 @@ import * as Icons from "../../components/Icons";
 import * as DataTable from "../../components/data-table";
 
 import {IndexerSelectColumnFilter, PushStatusSelectColumnFilter, SearchColumnFilter} from "./Filters";
+import {ReplayCell} from "./Replay";
 
 type TableState = {
     queryPageIndex: number;
@@ -83,6 +85,11 @@ export const ReleaseTable = () => {
       Cell: DataTable.TitleCell,
       Filter: IndexerSelectColumnFilter,
       filter: "equal"
+    },
+    {
+      Header: "Replay",
+      accessor: "id",
+      Cell: ReplayCell
     }
   ] as Column<Release>[], []);
 
@@ -181,6 +188,19 @@ export const ReleaseTable = () => {
             ) : null
           ))
         )}
+        <div className="flex items-center gap-x-2 mt-1 sm:ml-auto">
+          {(["csv", "json"] as const).map((format) => (
+            <a
+              key={format}
+              href={APIClient.release.exportUrl(format, queryFilters)}
+              title={`Export the filtered releases as ${format.toUpperCase()}`}
+              className="inline-flex items-center px-3 py-2 text-sm font-medium text-gray-700 dark:text-gray-400 bg-white dark:bg-gray-800 rounded-lg shadow-md hover:bg-gray-50 dark:hover:bg-gray-700"
+            >
+              <ArrowDownTrayIcon className="w-4 h-4 mr-1" aria-hidden="true" />
+              {format.toUpperCase()}
+            </a>
+          ))}
+        </div>
       </div>
       <div className="bg-white shadow-lg dark:bg-gray-800 rounded-lg">
         <table {...getTableProps()} className="min-w-full divide-y divide-gray-200 dark:divide-gray-700">
@@ -0,0 +1,129 @@
+import { Fragment, useState } from "react";
+import { useMutation, useQuery } from "react-query";
+import { Dialog, Transition } from "@headlessui/react";
+import { ArrowPathIcon } from "@heroicons/react/24/outline";
+
+import { APIClient } from "../../api/APIClient";
+import { useToggle } from "../../hooks/hooks";
+import { classNames } from "../../utils";
+
+interface ReplayCellProps {
+  row: {
+    original: Release;
+  };
+}
+
+// ReplayCell checks a stored release against a filter again to see why it matched or not
+export const ReplayCell = ({ row }: ReplayCellProps) => {
+  const [isOpen, toggle] = useToggle(false);
+
+  return (
+    <>
+      <button
+        type="button"
+        title="Replay against a filter"
+        className="text-gray-500 hover:text-gray-900 dark:text-gray-400 dark:hover:text-gray-100"
+        onClick={toggle}
+      >
+        <ArrowPathIcon className="w-5 h-5" aria-hidden="true" />
+      </button>
+      {isOpen && <ReplayDialog release={row.original} isOpen={isOpen} toggle={toggle} />}
+    </>
+  );
+};
+
+interface ReplayDialogProps {
+  release: Release;
+  isOpen: boolean;
+  toggle: () => void;
+}
+
+const ReplayDialog = ({ release, isOpen, toggle }: ReplayDialogProps) => {
+  const [filterID, setFilterID] = useState(0);
+  const [runActions, setRunActions] = useState(false);
+
+  const { data: filters } = useQuery(["filters"], () => APIClient.filters.getAll(), { refetchOnWindowFocus: false });
+
+  const replayMutation = useMutation(
+    () => APIClient.release.replay({ filter_id: filterID, release_ids: [release.id], run_actions: runActions })
+  );
+
+  const result = replayMutation.data?.[0];
+
+  return (
+    <Transition.Root show={isOpen} as={Fragment}>
+      <Dialog as="div" static className="fixed z-10 inset-0 overflow-y-auto" open={isOpen} onClose={toggle}>
+        <div className="flex items-center justify-center min-h-screen px-4">
+          <Dialog.Overlay className="fixed inset-0 bg-gray-700/60 dark:bg-black/60 transition-opacity" />
+
+          <div className="relative w-full max-w-lg rounded-lg bg-white dark:bg-gray-800 shadow-xl p-6 space-y-4">
+            <Dialog.Title as="h3" className="text-lg font-medium text-gray-900 dark:text-white">
+              Replay release
+            </Dialog.Title>
+            <p className="text-sm text-gray-500 dark:text-gray-400 break-all">{release.torrent_name}</p>
+
+            <select
+              className="block w-full text-sm rounded-md border-gray-300 dark:border-gray-700 bg-white dark:bg-gray-800 text-gray-900 dark:text-gray-100"
+              value={filterID}
+              onChange={(e) => setFilterID(parseInt(e.target.value))}
+            >
+              <option value={0}>Select filter</option>
+              {filters?.map((f) => (
+                <option key={f.id} value={f.id}>{f.name}</option>
+              ))}
+            </select>
+
+            <label className="flex items-center space-x-2 text-sm text-gray-700 dark:text-gray-300">
+              <input
+                type="checkbox"
+                className="rounded border-gray-300 dark:border-gray-700"
+                checked={runActions}
+                onChange={(e) => setRunActions(e.target.checked)}
+              />
+              <span>Run the filter actions if it matches</span>
+            </label>
+
+            {result && (
+              <div className="text-sm">
+                <p className={classNames(result.match ? "text-green-500" : "text-red-500", "font-medium")}>
+                  {result.match ? "Match" : "No match"}
+                  {result.actions_queued && ", actions queued"}
+                </p>
+                {result.error && <p className="text-red-500">{result.error}</p>}
+                <ul className="mt-1 list-disc list-inside text-gray-700 dark:text-gray-300">
+                  {result.rejections.map((r, idx) => <li key={idx}>{r}</li>)}
+                </ul>
+                {result.skipped.length > 0 && (
+                  <p className="mt-1 text-xs text-gray-500 dark:text-gray-400">
+                    Not checked without running actions: {result.skipped.join(", ")}
+                  </p>
+                )}
+              </div>
+            )}
+            {replayMutation.error instanceof Error && (
+              <p className="text-sm text-red-500">{replayMutation.error.message}</p>
+            )}
+
+            <div className="flex justify-end space-x-2">
+              <button
+                type="button"
+                className="px-4 py-2 rounded-md border border-gray-300 dark:border-gray-600 text-sm font-medium text-gray-700 dark:text-gray-200 bg-white dark:bg-gray-700 hover:bg-gray-50 dark:hover:bg-gray-600"
+                onClick={toggle}
+              >
+                Close
+              </button>
+              <button
+                type="button"
+                disabled={filterID === 0 || replayMutation.isLoading}
+                className="px-4 py-2 rounded-md border border-transparent text-sm font-medium text-white bg-indigo-600 dark:bg-blue-600 hover:bg-indigo-700 dark:hover:bg-blue-700 disabled:opacity-50"
+                onClick={() => replayMutation.mutate()}
+              >
+                Replay
+              </button>
+            </div>
+          </div>
+        </div>
+      </Dialog>
+    </Transition.Root>
+  );
+};
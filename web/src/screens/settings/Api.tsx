@@ -1,5 +1,5 @@
 import {queryClient} from "../../App";
-import {useRef} from "react";
+import {useRef, useState} from "react";
 import {useMutation, useQuery} from "react-query";
 import {KeyField} from "../../components/fields/text";
 import {DeleteModal} from "../../components/modals";
@@ -14,6 +14,7 @@ import {EmptySimple} from "../../components/emptystates";
 
 function APISettings() {
   const [addFormIsOpen, toggleAddForm] = useToggle(false);
+  const [createdKey, setCreatedKey] = useState<APIKey | null>(null);
 
   const { isLoading, data } = useQuery(
     ["apikeys"],
@@ -28,7 +29,7 @@ function APISettings() {
   return (
     <div className="divide-y divide-gray-200 dark:divide-gray-700 lg:col-span-9">
       <div className="pb-6 py-6 px-4 sm:p-6 lg:pb-8">
-        <APIKeyAddForm isOpen={addFormIsOpen} toggle={toggleAddForm}/>
+        <APIKeyAddForm isOpen={addFormIsOpen} toggle={toggleAddForm} onCreated={setCreatedKey}/>
 
         <div className="-ml-4 -mt-4 flex justify-between items-center flex-wrap sm:flex-nowrap">
           <div className="ml-4 mt-4">
@@ -48,6 +49,15 @@ function APISettings() {
           </div>
         </div>
 
+        {createdKey?.key && (
+          <div className="mt-6 rounded-md bg-green-50 dark:bg-green-900/30 p-4 text-sm text-gray-900 dark:text-white">
+            <p className="mb-2">
+              Copy the API key <span className="font-medium">{createdKey.name}</span> now, it will not be shown again.
+            </p>
+            <KeyField value={createdKey.key}/>
+          </div>
+        )}
+
         {data && data.length > 0 ?
           <section className="mt-6 light:bg-white dark:bg-gray-800 light:shadow sm:rounded-md">
             <ol className="min-w-full relative">
@@ -61,7 +71,7 @@ function APISettings() {
               </li>
 
               {data && data.map((k) => (
-                <APIListItem key={k.key} apikey={k}/>
+                <APIListItem key={k.id} apikey={k}/>
               ))}
             </ol>
           </section>
@@ -81,11 +91,10 @@ function APIListItem({ apikey }: ApiKeyItemProps) {
   const [deleteModalIsOpen, toggleDeleteModal] = useToggle(false);
 
   const deleteMutation = useMutation(
-    (key: string) => APIClient.apikeys.delete(key),
+    (id: string) => APIClient.apikeys.delete(id),
     {
       onSuccess: () => {
         queryClient.invalidateQueries(["apikeys"]);
-        queryClient.invalidateQueries(["apikeys", apikey.key]);
 
         toast.custom((t) => <Toast type="success" body={`API key ${apikey?.name} was deleted`} t={t}/>);
       }
@@ -99,7 +108,7 @@ function APIListItem({ apikey }: ApiKeyItemProps) {
         toggle={toggleDeleteModal}
         buttonRef={cancelModalButtonRef}
         deleteAction={() => {
-          deleteMutation.mutate(apikey.key);
+          deleteMutation.mutate(apikey.id);
           toggleDeleteModal();
         }}
         title={`Remove API key: ${apikey.name}`}
@@ -107,11 +116,15 @@ function APIListItem({ apikey }: ApiKeyItemProps) {
       />
 
       <div className="grid grid-cols-12 gap-4 items-center py-2">
-        <div className="col-span-5 flex items-center text-sm font-medium text-gray-900 dark:text-white">
+        <div className="col-span-5 flex flex-col text-sm font-medium text-gray-900 dark:text-white">
           {apikey.name}
+          <span className="text-xs font-normal text-gray-500 dark:text-gray-400">
+            {apikey.scopes?.length ? apikey.scopes.join(", ") : "full access"}
+            {apikey.expires_at && ` · expires ${new Date(apikey.expires_at).toLocaleDateString()}`}
+          </span>
         </div>
         <div className="col-span-6 flex items-center text-sm font-medium text-gray-900 dark:text-white">
-          <KeyField value={apikey.key}/>
+          <span className="font-mono">{apikey.key_hint}</span>
         </div>
 
         <div className="col-span-1 flex items-center justify-end text-sm font-medium text-gray-900 dark:text-white">
@@ -1,6 +1,7 @@
 import { useQuery } from "react-query";
 import { APIClient } from "../../api/APIClient";
 import { Checkbox } from "../../components/Checkbox";
+import { AlertWarning } from "../../components/alerts";
 import { SettingsContext } from "../../utils/Context";
 
 function ApplicationSettings() {
@@ -22,10 +23,15 @@ function ApplicationSettings() {
         <div>
           <h2 className="text-lg leading-6 font-medium text-gray-900 dark:text-white">Application</h2>
           <p className="mt-1 text-sm text-gray-500 dark:text-gray-400">
-            Application settings. Change in config.toml and restart to take effect.
+            Application settings. Change in config.toml or with AUTOBRR__ environment variables.
+            Log level and proxy are reloaded on save or SIGHUP, other settings need a restart.
           </p>
         </div>
 
+        {data?.errors?.map((e, idx) => (
+          <AlertWarning key={idx} title="Invalid setting" text={e} />
+        ))}
+
         <form className="divide-y divide-gray-200 dark:divide-gray-700 lg:col-span-9" action="#" method="POST">
           {!isLoading && data && (
             <div className="mt-6 grid grid-cols-12 gap-6">
@@ -0,0 +1,109 @@
+import { useState } from "react";
+import { useMutation, useQuery } from "react-query";
+import { toast } from "react-hot-toast";
+import { CheckIcon, XMarkIcon } from "@heroicons/react/24/outline";
+
+import { APIClient } from "../../api/APIClient";
+import Toast from "../../components/notifications/Toast";
+import { EmptySimple } from "../../components/emptystates";
+import { queryClient } from "../../App";
+import { simplifyDate } from "../../utils";
+
+const statusOptions: { label: string; value: ReleaseApprovalStatus | "" }[] = [
+  { label: "Pending", value: "PENDING" },
+  { label: "Approved", value: "APPROVED" },
+  { label: "Rejected", value: "REJECTED" },
+  { label: "Expired", value: "EXPIRED" },
+  { label: "All", value: "" }
+];
+
+const inputClassName = "block dark:bg-gray-800 border border-gray-300 dark:border-gray-700 rounded-md shadow-sm py-2 px-3 focus:outline-none focus:ring-blue-500 focus:border-blue-500 dark:text-gray-100 sm:text-sm";
+
+function ApprovalSettings() {
+  const [status, setStatus] = useState<ReleaseApprovalStatus | "">("PENDING");
+
+  const { data: approvals } = useQuery(
+    ["release_approvals", status],
+    () => APIClient.release.approvals.list(status),
+    { refetchOnWindowFocus: false }
+  );
+
+  const decideMutation = useMutation(
+    ({ id, decision }: { id: number; decision: ReleaseApprovalDecision }) => APIClient.release.approvals.decide(id, decision),
+    {
+      onSuccess: (_, { decision }) => {
+        toast.custom((t) => <Toast type="success" body={decision === "approve" ? "Release approved, actions are running" : "Release rejected"} t={t}/>);
+      },
+      onError: () => {
+        toast.custom((t) => <Toast type="error" body="Could not decide on the release, it might have expired" t={t}/>);
+      },
+      onSettled: () => {
+        queryClient.invalidateQueries("release_approvals");
+      }
+    }
+  );
+
+  return (
+    <div className="divide-y divide-gray-200 dark:divide-gray-700 lg:col-span-9">
+      <div className="py-6 px-4 sm:p-6 lg:pb-8">
+        <div className="flex items-start justify-between gap-4">
+          <div>
+            <h2 className="text-lg leading-6 font-medium text-gray-900 dark:text-white">Approvals</h2>
+            <p className="mt-1 text-sm text-gray-500 dark:text-gray-400">
+              Matches of filters that require approval wait here until they are approved or rejected.
+              The actions of the filter run once a release is approved.
+            </p>
+          </div>
+          <select value={status} onChange={(e) => setStatus(e.target.value as ReleaseApprovalStatus | "")} className={inputClassName}>
+            {statusOptions.map((o) => (
+              <option key={o.value} value={o.value}>{o.label}</option>
+            ))}
+          </select>
+        </div>
+
+        {approvals && approvals.length > 0 ? (
+          <ul className="mt-6 divide-y divide-gray-200 dark:divide-gray-700">
+            {approvals.map((a) => (
+              <li key={a.id} className="py-3 flex items-center justify-between text-sm">
+                <div>
+                  <p className="font-medium text-gray-900 dark:text-white break-all">{a.torrent_name}</p>
+                  <p className="text-gray-500 dark:text-gray-400">
+                    {a.filter} · {a.indexer} · {simplifyDate(a.created_at)}
+                    {a.status === "PENDING" ? ` · expires ${simplifyDate(a.expires_at)}` : ` · ${a.status.toLowerCase()}`}
+                    {a.decided_by ? ` by ${a.decided_by}` : ""}
+                  </p>
+                </div>
+                {a.status === "PENDING" && (
+                  <div className="flex items-center gap-3">
+                    <button
+                      type="button"
+                      title="Approve"
+                      disabled={decideMutation.isLoading}
+                      onClick={() => decideMutation.mutate({ id: a.id, decision: "approve" })}
+                      className="text-gray-500 hover:text-green-600 dark:text-gray-400 dark:hover:text-green-500 disabled:opacity-50"
+                    >
+                      <CheckIcon className="h-5 w-5" aria-hidden="true"/>
+                    </button>
+                    <button
+                      type="button"
+                      title="Reject"
+                      disabled={decideMutation.isLoading}
+                      onClick={() => decideMutation.mutate({ id: a.id, decision: "reject" })}
+                      className="text-gray-500 hover:text-red-600 dark:text-gray-400 dark:hover:text-red-500 disabled:opacity-50"
+                    >
+                      <XMarkIcon className="h-5 w-5" aria-hidden="true"/>
+                    </button>
+                  </div>
+                )}
+              </li>
+            ))}
+          </ul>
+        ) : (
+          <EmptySimple title="No approvals" subtitle="Enable Require approval on a filter to hold its matches here"/>
+        )}
+      </div>
+    </div>
+  );
+}
+
+export default ApprovalSettings;
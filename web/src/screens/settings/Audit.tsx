@@ -0,0 +1,90 @@
+import { useState } from "react";
+import { useQuery } from "react-query";
+
+import { APIClient } from "../../api/APIClient";
+import { EmptySimple } from "../../components/emptystates";
+import { simplifyDate } from "../../utils";
+
+const entityOptions: { label: string; value: AuditEntityType | "" }[] = [
+  { label: "All", value: "" },
+  { label: "Filters", value: "FILTER" },
+  { label: "Indexers", value: "INDEXER" },
+  { label: "IRC networks", value: "IRC_NETWORK" },
+  { label: "Download clients", value: "DOWNLOAD_CLIENT" },
+  { label: "Notifications", value: "NOTIFICATION" },
+  { label: "API keys", value: "API_KEY" },
+  { label: "Lists", value: "LIST" },
+  { label: "Feeds", value: "FEED" },
+  { label: "Announce blocklist", value: "ANNOUNCE_BLOCKLIST" },
+  { label: "Quotas", value: "QUOTA" }
+];
+
+const formatValue = (value: unknown) => {
+  if (value === undefined || value === null) {
+    return "—";
+  }
+
+  return typeof value === "string" ? value : JSON.stringify(value);
+};
+
+function AuditSettings() {
+  const [entityType, setEntityType] = useState<AuditEntityType | "">("");
+
+  const { data: entries } = useQuery(
+    ["audit", entityType],
+    () => APIClient.audit.find(entityType),
+    { refetchOnWindowFocus: false }
+  );
+
+  return (
+    <div className="divide-y divide-gray-200 dark:divide-gray-700 lg:col-span-9">
+      <div className="py-6 px-4 sm:p-6 lg:pb-8">
+        <div className="flex flex-wrap items-start justify-between gap-3">
+          <div>
+            <h2 className="text-lg leading-6 font-medium text-gray-900 dark:text-white">Audit log</h2>
+            <p className="mt-1 text-sm text-gray-500 dark:text-gray-400">
+              Who created, changed or deleted filters, indexers, IRC networks, download clients, notifications and API keys.
+              Secrets are redacted.
+            </p>
+          </div>
+
+          <select
+            value={entityType}
+            onChange={(e) => setEntityType(e.target.value as AuditEntityType | "")}
+            className="block dark:bg-gray-800 border border-gray-300 dark:border-gray-700 rounded-md shadow-sm py-2 px-3 focus:outline-none focus:ring-blue-500 focus:border-blue-500 dark:text-gray-100 sm:text-sm"
+          >
+            {entityOptions.map((o) => (
+              <option key={o.value} value={o.value}>{o.label}</option>
+            ))}
+          </select>
+        </div>
+
+        {entries && entries.length > 0 ? (
+          <ul className="mt-6 divide-y divide-gray-200 dark:divide-gray-700">
+            {entries.map((e) => (
+              <li key={e.id} className="py-3 text-sm">
+                <p className="font-medium text-gray-900 dark:text-white">
+                  {e.actor} {e.action.toLowerCase()}d {entityOptions.find((o) => o.value === e.entity_type)?.label.replace(/s$/, "").toLowerCase() ?? e.entity_type} {e.entity_name || e.entity_id}
+                </p>
+                <p className="text-gray-500 dark:text-gray-400">{simplifyDate(e.created_at)}</p>
+                {e.action === "UPDATE" && e.changes.length > 0 && (
+                  <ul className="mt-1 text-gray-600 dark:text-gray-300">
+                    {e.changes.map((c) => (
+                      <li key={c.field} className="break-all">
+                        <span className="font-mono">{c.field}</span>: {formatValue(c.old)} → {formatValue(c.new)}
+                      </li>
+                    ))}
+                  </ul>
+                )}
+              </li>
+            ))}
+          </ul>
+        ) : (
+          <EmptySimple title="No changes recorded" subtitle="Configuration changes show up here once they are made"/>
+        )}
+      </div>
+    </div>
+  );
+}
+
+export default AuditSettings;
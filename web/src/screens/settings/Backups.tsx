@@ -0,0 +1,117 @@
+import { useMutation, useQuery } from "react-query";
+import { toast } from "react-hot-toast";
+import { ArrowDownTrayIcon, TrashIcon } from "@heroicons/react/24/outline";
+
+import { APIClient } from "../../api/APIClient";
+import Toast from "../../components/notifications/Toast";
+import { EmptySimple } from "../../components/emptystates";
+import { queryClient } from "../../App";
+import { simplifyDate } from "../../utils";
+
+const formatSize = (bytes: number) => {
+  const units = ["B", "KB", "MB", "GB"];
+  let size = bytes;
+  let unit = 0;
+  while (size >= 1024 && unit < units.length - 1) {
+    size /= 1024;
+    unit++;
+  }
+  return `${size.toFixed(unit === 0 ? 0 : 1)} ${units[unit]}`;
+};
+
+function BackupSettings() {
+  const { data: settings } = useQuery(
+    "backup_settings",
+    () => APIClient.backups.settings(),
+    { refetchOnWindowFocus: false }
+  );
+
+  const { data: backups } = useQuery(
+    "backups",
+    () => APIClient.backups.list(),
+    { refetchOnWindowFocus: false }
+  );
+
+  const createMutation = useMutation(() => APIClient.backups.create(), {
+    onSuccess: (backup) => {
+      toast.custom((t) => <Toast type="success" body={`Backup ${backup.name} created`} t={t}/>);
+      queryClient.invalidateQueries("backups");
+    },
+    onError: () => {
+      toast.custom((t) => <Toast type="error" body="Backup failed, check the logs" t={t}/>);
+    }
+  });
+
+  const deleteMutation = useMutation((name: string) => APIClient.backups.delete(name), {
+    onSuccess: () => {
+      queryClient.invalidateQueries("backups");
+    }
+  });
+
+  return (
+    <div className="divide-y divide-gray-200 dark:divide-gray-700 lg:col-span-9">
+      <div className="py-6 px-4 sm:p-6 lg:pb-8">
+        <div className="-ml-4 -mt-4 flex justify-between items-center flex-wrap sm:flex-nowrap">
+          <div className="ml-4 mt-4">
+            <h2 className="text-lg leading-6 font-medium text-gray-900 dark:text-white">Backups</h2>
+            <p className="mt-1 text-sm text-gray-500 dark:text-gray-400">
+              Consistent copies of the database, safe to make while autobrr is running.
+              To restore a sqlite backup, stop autobrr, copy it to <code>autobrr.db.restore</code> next to autobrr.db and start autobrr again.
+            </p>
+            {settings && (
+              <p className="mt-1 text-sm text-gray-500 dark:text-gray-400">
+                Stored in <code>{settings.dir}</code>.{" "}
+                {settings.interval > 0 ? `A backup is made every ${settings.interval} hours` : "Scheduled backups are disabled"}
+                {settings.retain > 0 ? `, the newest ${settings.retain} are kept.` : "."}
+              </p>
+            )}
+          </div>
+          <div className="ml-4 mt-4 flex-shrink-0">
+            <button
+              type="button"
+              disabled={createMutation.isLoading}
+              onClick={() => createMutation.mutate()}
+              className="relative inline-flex items-center px-4 py-2 border border-transparent shadow-sm text-sm font-medium rounded-md text-white bg-blue-600 dark:bg-blue-600 hover:bg-blue-700 dark:hover:bg-blue-700 disabled:opacity-50 focus:outline-none focus:ring-2 focus:ring-offset-2 focus:ring-blue-500"
+            >
+              Create backup
+            </button>
+          </div>
+        </div>
+
+        {backups && backups.length > 0 ? (
+          <ul className="mt-6 divide-y divide-gray-200 dark:divide-gray-700">
+            {backups.map((b) => (
+              <li key={b.name} className="py-3 flex items-center justify-between text-sm">
+                <div>
+                  <p className="font-medium text-gray-900 dark:text-white">{b.name}</p>
+                  <p className="text-gray-500 dark:text-gray-400">{simplifyDate(b.created_at)} · {formatSize(b.size)}</p>
+                </div>
+                <div className="flex items-center space-x-3">
+                  <a
+                    href={APIClient.backups.downloadUrl(b.name)}
+                    title="Download"
+                    className="text-gray-500 hover:text-blue-600 dark:text-gray-400 dark:hover:text-blue-500"
+                  >
+                    <ArrowDownTrayIcon className="h-5 w-5" aria-hidden="true"/>
+                  </a>
+                  <button
+                    type="button"
+                    title="Delete"
+                    onClick={() => deleteMutation.mutate(b.name)}
+                    className="text-gray-500 hover:text-red-600 dark:text-gray-400 dark:hover:text-red-500"
+                  >
+                    <TrashIcon className="h-5 w-5" aria-hidden="true"/>
+                  </button>
+                </div>
+              </li>
+            ))}
+          </ul>
+        ) : (
+          <EmptySimple title="No backups" subtitle="Create a backup or enable scheduled backups in config.toml"/>
+        )}
+      </div>
+    </div>
+  );
+}
+
+export default BackupSettings;
@@ -0,0 +1,267 @@
+import { useState } from "react";
+import { useMutation, useQuery } from "react-query";
+import { toast } from "react-hot-toast";
+import { TrashIcon } from "@heroicons/react/24/outline";
+
+import { APIClient } from "../../api/APIClient";
+import Toast from "../../components/notifications/Toast";
+import { EmptySimple } from "../../components/emptystates";
+import { queryClient } from "../../App";
+import { simplifyDate } from "../../utils";
+
+const typeOptions: { label: string; value: TorrentBlocklistType }[] = [
+  { label: "Info hash", value: "INFOHASH" },
+  { label: "Group", value: "GROUP" }
+];
+
+const announceTypeOptions: { label: string; value: AnnounceBlocklistType; placeholder: string }[] = [
+  { label: "Group", value: "GROUP", placeholder: "Groups, eg. GRP,OTHER*" },
+  { label: "Uploader", value: "UPLOADER", placeholder: "Uploaders, eg. spam*" },
+  { label: "Category", value: "CATEGORY", placeholder: "Categories, eg. *CAM*" },
+  { label: "Title regex", value: "TITLE_REGEX", placeholder: "Regex, eg. \\b(cam|ts)\\b" }
+];
+
+const inputClassName = "block dark:bg-gray-800 border border-gray-300 dark:border-gray-700 rounded-md shadow-sm py-2 px-3 focus:outline-none focus:ring-blue-500 focus:border-blue-500 dark:text-gray-100 sm:text-sm";
+
+function AnnounceBlocklist() {
+  const [name, setName] = useState("");
+  const [type, setType] = useState<AnnounceBlocklistType>("GROUP");
+  const [value, setValue] = useState("");
+  const [indexer, setIndexer] = useState("");
+
+  const { data: rules } = useQuery(
+    "announce_blocklist",
+    () => APIClient.announceBlocklist.list(),
+    { refetchOnWindowFocus: false }
+  );
+
+  const { data: indexers } = useQuery(
+    "indexers_options",
+    () => APIClient.indexers.getOptions(),
+    { refetchOnWindowFocus: false }
+  );
+
+  const createMutation = useMutation((rule: AnnounceBlocklistRule) => APIClient.announceBlocklist.create(rule), {
+    onSuccess: () => {
+      toast.custom((t) => <Toast type="success" body={`Rule ${name} was added`} t={t}/>);
+      setName("");
+      setValue("");
+      queryClient.invalidateQueries("announce_blocklist");
+    },
+    onError: () => {
+      toast.custom((t) => <Toast type="error" body="Could not add the rule, check the value" t={t}/>);
+    }
+  });
+
+  const updateMutation = useMutation((rule: AnnounceBlocklistRule) => APIClient.announceBlocklist.update(rule), {
+    onSettled: () => {
+      queryClient.invalidateQueries("announce_blocklist");
+    }
+  });
+
+  const deleteMutation = useMutation((id: number) => APIClient.announceBlocklist.delete(id), {
+    onSuccess: () => {
+      queryClient.invalidateQueries("announce_blocklist");
+    }
+  });
+
+  return (
+    <div className="py-6 px-4 sm:p-6 lg:pb-8">
+      <div>
+        <h2 className="text-lg leading-6 font-medium text-gray-900 dark:text-white">Announce blocklist</h2>
+        <p className="mt-1 text-sm text-gray-500 dark:text-gray-400">
+          Announces matching a rule are dropped before any filter is checked and are not stored in the release history.
+          Group, uploader and category take a comma separated list with wildcards.
+        </p>
+      </div>
+
+      <form
+        className="mt-6 flex flex-wrap gap-3"
+        onSubmit={(e) => {
+          e.preventDefault();
+          createMutation.mutate({ id: 0, name, enabled: true, type, value, indexer, hits: 0 });
+        }}
+      >
+        <input
+          type="text"
+          value={name}
+          placeholder="Name"
+          onChange={(e) => setName(e.target.value)}
+          className={inputClassName}
+        />
+        <select value={type} onChange={(e) => setType(e.target.value as AnnounceBlocklistType)} className={inputClassName}>
+          {announceTypeOptions.map((o) => (
+            <option key={o.value} value={o.value}>{o.label}</option>
+          ))}
+        </select>
+        <input
+          type="text"
+          value={value}
+          placeholder={announceTypeOptions.find((o) => o.value === type)?.placeholder}
+          onChange={(e) => setValue(e.target.value)}
+          className={`flex-1 ${inputClassName}`}
+        />
+        <select value={indexer} onChange={(e) => setIndexer(e.target.value)} className={inputClassName}>
+          <option value="">All indexers</option>
+          {indexers?.map((i) => (
+            <option key={i.identifier} value={i.identifier}>{i.name}</option>
+          ))}
+        </select>
+        <button
+          type="submit"
+          disabled={name === "" || value === "" || createMutation.isLoading}
+          className="relative inline-flex items-center px-4 py-2 border border-transparent shadow-sm text-sm font-medium rounded-md text-white bg-blue-600 dark:bg-blue-600 hover:bg-blue-700 dark:hover:bg-blue-700 disabled:opacity-50 focus:outline-none focus:ring-2 focus:ring-offset-2 focus:ring-blue-500"
+        >
+          Add
+        </button>
+      </form>
+
+      {rules && rules.length > 0 ? (
+        <ul className="mt-6 divide-y divide-gray-200 dark:divide-gray-700">
+          {rules.map((r) => (
+            <li key={r.id} className="py-3 flex items-center justify-between text-sm">
+              <div className={r.enabled ? "" : "opacity-50"}>
+                <p className="font-medium text-gray-900 dark:text-white break-all">{r.name}</p>
+                <p className="text-gray-500 dark:text-gray-400 break-all">
+                  {announceTypeOptions.find((o) => o.value === r.type)?.label ?? r.type}: {r.value}
+                  {` · ${r.indexer || "all indexers"}`}
+                  {` · ${r.hits} ${r.hits === 1 ? "hit" : "hits"}`}
+                  {r.last_hit_at ? `, last ${simplifyDate(r.last_hit_at)}` : ""}
+                </p>
+              </div>
+              <div className="flex items-center gap-3">
+                <button
+                  type="button"
+                  onClick={() => updateMutation.mutate({ ...r, enabled: !r.enabled })}
+                  className="text-gray-500 hover:text-blue-600 dark:text-gray-400 dark:hover:text-blue-500"
+                >
+                  {r.enabled ? "Disable" : "Enable"}
+                </button>
+                <button
+                  type="button"
+                  title="Delete"
+                  onClick={() => deleteMutation.mutate(r.id)}
+                  className="text-gray-500 hover:text-red-600 dark:text-gray-400 dark:hover:text-red-500"
+                >
+                  <TrashIcon className="h-5 w-5" aria-hidden="true"/>
+                </button>
+              </div>
+            </li>
+          ))}
+        </ul>
+      ) : (
+        <EmptySimple title="No announce blocklist rules" subtitle="Add a rule to drop announces of known bad groups or spam"/>
+      )}
+    </div>
+  );
+}
+
+function BlocklistSettings() {
+  const [type, setType] = useState<TorrentBlocklistType>("INFOHASH");
+  const [value, setValue] = useState("");
+  const [reason, setReason] = useState("");
+
+  const { data: entries } = useQuery(
+    "torrent_blocklist",
+    () => APIClient.actions.blocklist(),
+    { refetchOnWindowFocus: false }
+  );
+
+  const createMutation = useMutation((entry: TorrentBlocklistEntry) => APIClient.actions.createBlocklistEntry(entry), {
+    onSuccess: () => {
+      toast.custom((t) => <Toast type="success" body={`${value} added to the blocklist`} t={t}/>);
+      setValue("");
+      setReason("");
+      queryClient.invalidateQueries("torrent_blocklist");
+    },
+    onError: () => {
+      toast.custom((t) => <Toast type="error" body="Could not add to the blocklist, check the value" t={t}/>);
+    }
+  });
+
+  const deleteMutation = useMutation((id: number) => APIClient.actions.deleteBlocklistEntry(id), {
+    onSuccess: () => {
+      queryClient.invalidateQueries("torrent_blocklist");
+    }
+  });
+
+  return (
+    <div className="divide-y divide-gray-200 dark:divide-gray-700 lg:col-span-9">
+      <div className="py-6 px-4 sm:p-6 lg:pb-8">
+        <div>
+          <h2 className="text-lg leading-6 font-medium text-gray-900 dark:text-white">Blocklist</h2>
+          <p className="mt-1 text-sm text-gray-500 dark:text-gray-400">
+            Downloaded torrents matching an info hash or release group are rejected before they are sent to a download client.
+            Torrents already in the client are rejected as well.
+          </p>
+        </div>
+
+        <form
+          className="mt-6 flex flex-wrap gap-3"
+          onSubmit={(e) => {
+            e.preventDefault();
+            createMutation.mutate({ id: 0, type, value, reason });
+          }}
+        >
+          <select value={type} onChange={(e) => setType(e.target.value as TorrentBlocklistType)} className={inputClassName}>
+            {typeOptions.map((o) => (
+              <option key={o.value} value={o.value}>{o.label}</option>
+            ))}
+          </select>
+          <input
+            type="text"
+            value={value}
+            placeholder={type === "INFOHASH" ? "Info hash" : "Group"}
+            onChange={(e) => setValue(e.target.value)}
+            className={`flex-1 ${inputClassName}`}
+          />
+          <input
+            type="text"
+            value={reason}
+            placeholder="Reason (optional)"
+            onChange={(e) => setReason(e.target.value)}
+            className={`flex-1 ${inputClassName}`}
+          />
+          <button
+            type="submit"
+            disabled={value === "" || createMutation.isLoading}
+            className="relative inline-flex items-center px-4 py-2 border border-transparent shadow-sm text-sm font-medium rounded-md text-white bg-blue-600 dark:bg-blue-600 hover:bg-blue-700 dark:hover:bg-blue-700 disabled:opacity-50 focus:outline-none focus:ring-2 focus:ring-offset-2 focus:ring-blue-500"
+          >
+            Add
+          </button>
+        </form>
+
+        {entries && entries.length > 0 ? (
+          <ul className="mt-6 divide-y divide-gray-200 dark:divide-gray-700">
+            {entries.map((e) => (
+              <li key={e.id} className="py-3 flex items-center justify-between text-sm">
+                <div>
+                  <p className="font-medium text-gray-900 dark:text-white break-all">{e.value}</p>
+                  <p className="text-gray-500 dark:text-gray-400">
+                    {typeOptions.find((o) => o.value === e.type)?.label ?? e.type}
+                    {e.reason ? ` · ${e.reason}` : ""}
+                    {e.created_at ? ` · ${simplifyDate(e.created_at)}` : ""}
+                  </p>
+                </div>
+                <button
+                  type="button"
+                  title="Delete"
+                  onClick={() => deleteMutation.mutate(e.id)}
+                  className="text-gray-500 hover:text-red-600 dark:text-gray-400 dark:hover:text-red-500"
+                >
+                  <TrashIcon className="h-5 w-5" aria-hidden="true"/>
+                </button>
+              </li>
+            ))}
+          </ul>
+        ) : (
+          <EmptySimple title="Blocklist is empty" subtitle="Add an info hash or release group to reject its torrents"/>
+        )}
+      </div>
+
+      <AnnounceBlocklist/>
+    </div>
+  );
+}
+
+export default BlocklistSettings;
@@ -64,6 +64,16 @@ function DownloadClientSettingsListItem({ client, idx }: DLSettingsItemProps) {
       <td className="px-6 py-4 whitespace-nowrap text-sm font-medium text-gray-900 dark:text-white">{client.name}</td>
       <td className="px-6 py-4 whitespace-nowrap text-sm text-gray-500 dark:text-gray-400">{client.host}</td>
       <td className="px-6 py-4 whitespace-nowrap text-sm text-gray-500 dark:text-gray-400">{DownloadClientTypeNameMap[client.type]}</td>
+      <td className="px-6 py-4 whitespace-nowrap text-sm">
+        <span
+          title={client.health?.error}
+          className={classNames(
+            client.health?.status === "DOWN" ? "text-red-500" : client.health?.status === "OK" ? "text-green-500" : "text-gray-500 dark:text-gray-400"
+          )}
+        >
+          {client.health?.status ?? "UNKNOWN"}
+        </span>
+      </td>
       <td className="px-6 py-4 whitespace-nowrap text-right text-sm font-medium">
         <span className="text-indigo-600 dark:text-gray-300 hover:text-indigo-900 cursor-pointer" onClick={toggleUpdateClient}>
                     Edit
@@ -141,6 +151,12 @@ function DownloadClientSettings() {
                         >
                           Type
                         </th>
+                        <th
+                          scope="col"
+                          className="px-6 py-3 text-left text-xs font-medium text-gray-500 dark:text-gray-400 uppercase tracking-wider"
+                        >
+                          Health
+                        </th>
                         <th scope="col" className="relative px-6 py-3">
                           <span className="sr-only">Edit</span>
                         </th>
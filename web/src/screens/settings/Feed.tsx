@@ -3,13 +3,13 @@ import {useMutation, useQuery, useQueryClient} from "react-query";
 import {APIClient} from "../../api/APIClient";
 import {Menu, Switch, Transition} from "@headlessui/react";
 
-import {classNames} from "../../utils";
+import {classNames, IsEmptyDate} from "../../utils";
 import {Fragment, useRef, useState} from "react";
 import {toast} from "react-hot-toast";
 import Toast from "../../components/notifications/Toast";
 import {queryClient} from "../../App";
 import {DeleteModal} from "../../components/modals";
-import {ArrowsRightLeftIcon, EllipsisHorizontalIcon, PencilSquareIcon, TrashIcon} from "@heroicons/react/24/outline";
+import {ArrowsRightLeftIcon, BeakerIcon, EllipsisHorizontalIcon, PencilSquareIcon, TrashIcon, XMarkIcon} from "@heroicons/react/24/outline";
 import {FeedUpdateForm} from "../../forms/settings/FeedForms";
 import {EmptySimple} from "../../components/emptystates";
 import {ImplementationBadges} from "./Indexer";
@@ -28,7 +28,7 @@ function FeedSettings() {
           <div className="ml-4 mt-4">
             <h3 className="text-lg leading-6 font-medium text-gray-900 dark:text-white">Feeds</h3>
             <p className="mt-1 text-sm text-gray-500 dark:text-gray-400">
-              Manage Torznab feeds.
+              Manage Torznab and RSS feeds.
             </p>
           </div>
         </div>
@@ -91,6 +91,15 @@ function ListItem({ feed }: ListItemProps) {
     updateMutation.mutate(status);
   };
 
+  const dryRunMutation = useMutation(
+    () => APIClient.feeds.dryRun(feed.id),
+    {
+      onError: () => {
+        toast.custom((t) => <Toast type="error" body={`Could not fetch ${feed.name} for a dry run`} t={t}/>);
+      }
+    }
+  );
+
   return (
     <li key={feed.id} className="text-gray-500 dark:text-gray-400">
       <FeedUpdateForm isOpen={updateFormIsOpen} toggle={toggleUpdateForm} feed={feed}/>
@@ -115,8 +124,9 @@ function ListItem({ feed }: ListItemProps) {
             />
           </Switch>
         </div>
-        <div className="col-span-4 flex items-center sm:px-6 text-sm font-medium text-gray-900 dark:text-white">
+        <div className="col-span-4 flex flex-col justify-center sm:px-6 text-sm font-medium text-gray-900 dark:text-white">
           {feed.name}
+          <FeedStatus feed={feed} />
         </div>
         <div className="col-span-3 flex items-center sm:px-6 text-sm font-medium text-gray-900 dark:text-gray-500">
           {feed.indexer}
@@ -129,23 +139,102 @@ function ListItem({ feed }: ListItemProps) {
             feed={feed}
             onToggle={toggleActive}
             toggleUpdate={toggleUpdateForm}
+            onDryRun={() => dryRunMutation.mutate()}
           />
         </div>
       </div>
+
+      {dryRunMutation.isLoading && (
+        <p className="px-6 pb-4 text-sm">Fetching {feed.name} and checking the items against the filters...</p>
+      )}
+      {dryRunMutation.data && (
+        <FeedDryRunReport report={dryRunMutation.data} onClose={() => dryRunMutation.reset()}/>
+      )}
     </li>
   );
 }
 
+function FeedStatus({ feed }: { feed: Feed }) {
+  if (feed.last_error) {
+    return (
+      <span className="text-xs font-normal text-red-500 truncate" title={feed.last_error}>
+        {feed.last_error_at ? `Failed ${IsEmptyDate(feed.last_error_at)}: ` : ""}{feed.last_error}
+      </span>
+    );
+  }
+
+  return (
+    <span className="text-xs font-normal text-gray-500 dark:text-gray-400">
+      Last success {feed.last_success_at ? IsEmptyDate(feed.last_success_at) : "n/a"}
+    </span>
+  );
+}
+
+interface FeedDryRunReportProps {
+  report: FeedDryRunResponse;
+  onClose: () => void;
+}
+
+function FeedDryRunReport({ report, onClose }: FeedDryRunReportProps) {
+  const matched = report.items.filter((i) => i.matched.length > 0).length;
+
+  return (
+    <div className="mx-6 mb-4 p-4 rounded-md border border-gray-200 dark:border-gray-700">
+      <div className="flex items-start justify-between">
+        <div>
+          <h4 className="text-sm font-medium text-gray-900 dark:text-white">Dry run</h4>
+          <p className="text-xs">
+            {matched} of {report.items.length} items match a filter. Nothing was downloaded or stored and the items are still new for the next poll.
+          </p>
+        </div>
+        <button type="button" title="Close" onClick={onClose} className="hover:text-gray-900 dark:hover:text-white">
+          <XMarkIcon className="h-5 w-5" aria-hidden="true"/>
+        </button>
+      </div>
+
+      <ul className="mt-2 divide-y divide-gray-200 dark:divide-gray-700">
+        {report.items.map((item, idx) => (
+          <li key={idx} className="py-2 text-sm">
+            <div className="flex items-center justify-between gap-4">
+              <span className="text-gray-900 dark:text-white break-all">{item.title || "Untitled item"}</span>
+              <span
+                className={classNames(
+                  item.matched.length > 0 ? "bg-green-100 text-green-800" : "bg-red-100 text-red-800",
+                  "px-2 py-0.5 rounded text-xs font-medium whitespace-nowrap"
+                )}
+              >
+                {item.matched.length > 0 ? item.matched.join(", ") : "no match"}
+              </span>
+            </div>
+            {item.error && <p className="mt-1 text-xs text-red-500">{item.error}</p>}
+            {item.matched.length === 0 && item.filters.length > 0 && (
+              <ul className="mt-1 text-xs">
+                {item.filters.map((f) => (
+                  <li key={f.filter_id}>
+                    <span className="font-medium">{f.filter_name}:</span> {f.rejections.join(", ")}
+                  </li>
+                ))}
+              </ul>
+            )}
+          </li>
+        ))}
+      </ul>
+    </div>
+  );
+}
+
 interface FeedItemDropdownProps {
     feed: Feed;
     onToggle: (newState: boolean) => void;
     toggleUpdate: () => void;
+    onDryRun: () => void;
 }
 
 const FeedItemDropdown = ({
   feed,
   onToggle,
-  toggleUpdate
+  toggleUpdate,
+  onDryRun
 }: FeedItemDropdownProps) => {
   const cancelModalButtonRef = useRef(null);
 
@@ -236,6 +325,26 @@ const FeedItemDropdown = ({
                 </button>
               )}
             </Menu.Item>
+            <Menu.Item>
+              {({ active }) => (
+                <button
+                  className={classNames(
+                    active ? "bg-blue-600 text-white" : "text-gray-900 dark:text-gray-300",
+                    "font-medium group flex rounded-md items-center w-full px-2 py-2 text-sm"
+                  )}
+                  onClick={() => onDryRun()}
+                >
+                  <BeakerIcon
+                    className={classNames(
+                      active ? "text-white" : "text-blue-500",
+                      "w-5 h-5 mr-2"
+                    )}
+                    aria-hidden="true"
+                  />
+                  Dry run
+                </button>
+              )}
+            </Menu.Item>
           </div>
           <div className="px-1 py-1">
             <Menu.Item>
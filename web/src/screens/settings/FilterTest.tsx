@@ -0,0 +1,184 @@
+import { useState } from "react";
+import { useMutation, useQuery } from "react-query";
+import { toast } from "react-hot-toast";
+
+import { APIClient } from "../../api/APIClient";
+import Toast from "../../components/notifications/Toast";
+import { classNames } from "../../utils";
+
+const releaseFields = [
+  "torrent_name",
+  "title",
+  "category",
+  "season",
+  "episode",
+  "year",
+  "resolution",
+  "source",
+  "codec",
+  "container",
+  "hdr",
+  "group",
+  "size",
+  "uploader"
+];
+
+const inputClass = "mt-1 block w-full dark:bg-gray-800 border border-gray-300 dark:border-gray-700 rounded-md shadow-sm py-2 px-3 focus:outline-none focus:ring-blue-500 focus:border-blue-500 dark:text-gray-100 sm:text-sm";
+
+const errorMessage = (error: Error) => {
+  try {
+    return JSON.parse(error.message).message ?? error.message;
+  } catch {
+    return error.message;
+  }
+};
+
+export const FilterTest = () => {
+  const [indexer, setIndexer] = useState("");
+  const [mode, setMode] = useState<"announce" | "release_name">("announce");
+  const [input, setInput] = useState("");
+
+  const { data: indexers } = useQuery(
+    "indexers_options",
+    () => APIClient.indexers.getOptions(),
+    { refetchOnWindowFocus: false }
+  );
+
+  const testMutation = useMutation(
+    (req: FilterTestRequest) => APIClient.filters.test(req),
+    {
+      onError: (error: Error) => {
+        toast.custom((t) => <Toast type="error" body={errorMessage(error)} t={t}/>);
+      }
+    }
+  );
+
+  const onSubmit = (e: React.FormEvent) => {
+    e.preventDefault();
+    testMutation.mutate(mode === "announce" ? { indexer, announce: input } : { indexer, release_name: input });
+  };
+
+  const result = testMutation.data;
+
+  return (
+    <div className="divide-y divide-gray-200 dark:divide-gray-700 lg:col-span-9">
+      <div className="py-6 px-4 sm:p-6 lg:pb-8">
+        <div>
+          <h2 className="text-lg leading-6 font-medium text-gray-900 dark:text-white">Filter test</h2>
+          <p className="mt-1 text-sm text-gray-500 dark:text-gray-400">
+            Paste an announce or a release name to see how it is parsed and which filters would match. Nothing is downloaded or sent to clients.
+          </p>
+        </div>
+      </div>
+
+      <form className="px-6 py-4" onSubmit={onSubmit}>
+        <div className="grid grid-cols-12 gap-6">
+          <div className="col-span-6">
+            <label htmlFor="test-indexer" className="block text-sm font-medium text-gray-600 dark:text-gray-300">
+              Indexer
+            </label>
+            <select
+              id="test-indexer"
+              value={indexer}
+              onChange={(e) => setIndexer(e.target.value)}
+              className={inputClass}
+            >
+              <option value="">Select indexer</option>
+              {indexers?.map((i) => (
+                <option key={i.identifier} value={i.identifier}>{i.name}</option>
+              ))}
+            </select>
+          </div>
+          <div className="col-span-6">
+            <label htmlFor="test-mode" className="block text-sm font-medium text-gray-600 dark:text-gray-300">
+              Input
+            </label>
+            <select
+              id="test-mode"
+              value={mode}
+              onChange={(e) => setMode(e.target.value as "announce" | "release_name")}
+              className={inputClass}
+            >
+              <option value="announce">Announce lines</option>
+              <option value="release_name">Release name</option>
+            </select>
+          </div>
+        </div>
+
+        <label htmlFor="test-input" className="mt-4 block text-sm font-medium text-gray-600 dark:text-gray-300">
+          {mode === "announce" ? "Announce, one line per announce line" : "Release name"}
+        </label>
+        <textarea
+          id="test-input"
+          rows={mode === "announce" ? 4 : 1}
+          value={input}
+          onChange={(e) => setInput(e.target.value)}
+          className={classNames(inputClass, "font-mono")}
+        />
+
+        <div className="mt-4 flex justify-end">
+          <button
+            type="submit"
+            disabled={!indexer || !input || testMutation.isLoading}
+            className="inline-flex items-center px-4 py-2 border border-transparent text-sm font-medium rounded-md shadow-sm text-white bg-blue-600 hover:bg-blue-700 disabled:opacity-50 focus:outline-none focus:ring-2 focus:ring-offset-2 focus:ring-blue-500"
+          >
+            Test
+          </button>
+        </div>
+      </form>
+
+      {result && (
+        <div className="px-6 py-4">
+          <h3 className="text-md leading-6 font-medium text-gray-900 dark:text-white">Parsed release</h3>
+          <dl className="mt-2 grid grid-cols-2 sm:grid-cols-3 gap-x-4 gap-y-2 text-sm">
+            {releaseFields.map((field) => {
+              const value = result.release[field];
+              if (value === undefined || value === null || value === "" || value === 0 || (Array.isArray(value) && value.length === 0))
+                return null;
+
+              return (
+                <div key={field}>
+                  <dt className="text-gray-500 dark:text-gray-400">{field}</dt>
+                  <dd className="text-gray-900 dark:text-gray-100 break-all">{Array.isArray(value) ? value.join(", ") : String(value)}</dd>
+                </div>
+              );
+            })}
+          </dl>
+
+          <h3 className="mt-6 text-md leading-6 font-medium text-gray-900 dark:text-white">Filters</h3>
+          {result.filters.length === 0 ? (
+            <p className="mt-2 text-sm text-gray-500 dark:text-gray-400">No enabled filters for this indexer.</p>
+          ) : (
+            <ul className="mt-2 divide-y divide-gray-200 dark:divide-gray-700">
+              {result.filters.map((f) => (
+                <li key={f.filter_id} className="py-3">
+                  <div className="flex items-center justify-between">
+                    <span className="text-sm font-medium text-gray-900 dark:text-white">{f.filter_name}</span>
+                    <span
+                      className={classNames(
+                        f.match ? "bg-green-100 text-green-800" : "bg-red-100 text-red-800",
+                        "px-2 py-0.5 rounded text-xs font-medium"
+                      )}
+                    >
+                      {f.match ? "match" : "rejected"}
+                    </span>
+                  </div>
+                  {f.rejections.length > 0 && (
+                    <ul className="mt-1 list-disc list-inside text-sm text-gray-600 dark:text-gray-400">
+                      {f.rejections.map((r, idx) => <li key={idx}>{r}</li>)}
+                    </ul>
+                  )}
+                  {f.skipped.length > 0 && (
+                    <p className="mt-1 text-xs text-gray-500 dark:text-gray-400">
+                      Not checked in a test: {f.skipped.join(", ")}
+                    </p>
+                  )}
+                </li>
+              ))}
+            </ul>
+          )}
+        </div>
+      )}
+    </div>
+  );
+};
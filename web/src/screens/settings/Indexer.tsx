@@ -31,10 +31,19 @@ const ImplementationBadgeRSS = () => (
   </span>
 );
 
+const ImplementationBadgeAPI = () => (
+  <span
+    className="inline-flex items-center px-2.5 py-0.5 rounded-md text-sm font-medium bg-sky-200 dark:bg-sky-400 text-sky-800 dark:text-sky-800"
+  >
+    API
+  </span>
+);
+
 export const ImplementationBadges: componentMapType = {
   "irc": <ImplementationBadgeIRC/>,
   "torznab": <ImplementationBadgeTorznab />,
-  "rss": <ImplementationBadgeRSS />
+  "rss": <ImplementationBadgeRSS />,
+  "api": <ImplementationBadgeAPI />
 };
 
 interface ListItemProps {
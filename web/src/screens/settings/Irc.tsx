@@ -2,7 +2,7 @@ import { useMutation, useQuery, useQueryClient } from "react-query";
 
 import { classNames, IsEmptyDate, simplifyDate } from "../../utils";
 import { IrcNetworkAddForm, IrcNetworkUpdateForm } from "../../forms";
-import { useToggle } from "../../hooks/hooks";
+import { useEventStream, useToggle } from "../../hooks/hooks";
 import { APIClient } from "../../api/APIClient";
 import { EmptySimple } from "../../components/emptystates";
 import { LockClosedIcon, LockOpenIcon } from "@heroicons/react/24/solid";
@@ -25,12 +25,16 @@ export const IrcSettings = () => {
   const [expandNetworks, toggleExpand] = useToggle(false);
   const [addNetworkIsOpen, toggleAddNetwork] = useToggle(false);
 
+  const queryClient = useQueryClient();
+
   const { data } = useQuery("networks", () => APIClient.irc.getNetworks(), {
     refetchOnWindowFocus: false,
-    // Refetch every 3 seconds
-    refetchInterval: 3000
+    // connection and channel changes come from the event stream, refetch now and then for the last announce times
+    refetchInterval: 60000
   });
 
+  useEventStream<IrcStatusEvent>(APIClient.events.irc, () => queryClient.invalidateQueries("networks"));
+
   return (
     <div className="lg:col-span-9">
       <IrcNetworkAddForm isOpen={addNetworkIsOpen} toggle={toggleAddNetwork} />
@@ -155,7 +159,7 @@ const ListItem = ({ idx, network, expanded }: ListItemProps) => {
           <div className="flex">
             <span className="relative inline-flex items-center ml-1">
               {network.enabled ? (
-                network.healthy ? (
+                network.healthy && !network.channels.some((c) => c.silent) ? (
                   <span
                     className="mr-3 flex h-3 w-3 relative"
                     title={`Connected since: ${simplifyDate(network.connected_since)}`}
@@ -166,7 +170,7 @@ const ListItem = ({ idx, network, expanded }: ListItemProps) => {
                 ) : (
                   <span
                     className="mr-3 flex items-center"
-                    title={network.connection_errors.toString()}
+                    title={network.healthy ? "No announces in some channels" : network.connection_errors.toString()}
                   >
                     <ExclamationCircleIcon className="h-4 w-4 text-yellow-400 hover:text-yellow-600" />
                   </span>
@@ -246,7 +250,11 @@ const ListItem = ({ idx, network, expanded }: ListItemProps) => {
                       <div className="col-span-4 flex items-center sm:px-6 ">
                         <span className="relative inline-flex items-center">
                           {network.enabled ? (
-                            c.monitoring ? (
+                            c.silent ? (
+                              <span className="mr-3 flex items-center" title="No announces for longer than usual">
+                                <ExclamationCircleIcon className="h-4 w-4 text-yellow-400 hover:text-yellow-600" />
+                              </span>
+                            ) : c.monitoring ? (
                               <span
                                 className="mr-3 flex h-3 w-3 relative"
                                 title="monitoring"
@@ -0,0 +1,231 @@
+import { useState } from "react";
+import { useMutation, useQuery } from "react-query";
+import { toast } from "react-hot-toast";
+import { ArrowPathIcon, TrashIcon } from "@heroicons/react/24/outline";
+
+import { APIClient } from "../../api/APIClient";
+import Toast from "../../components/notifications/Toast";
+import { EmptySimple } from "../../components/emptystates";
+import { queryClient } from "../../App";
+import { classNames, simplifyDate } from "../../utils";
+
+const typeOptions: { label: string; value: ListType }[] = [
+  { label: "Sonarr", value: "SONARR" },
+  { label: "Radarr", value: "RADARR" },
+  { label: "Trakt", value: "TRAKT" },
+  { label: "Mdblist", value: "MDBLIST" },
+  { label: "Plain text URL", value: "PLAINTEXT" }
+];
+
+const isArrType = (type: ListType) => type === "SONARR" || type === "RADARR";
+
+const inputClassName = "block dark:bg-gray-800 border border-gray-300 dark:border-gray-700 rounded-md shadow-sm py-2 px-3 focus:outline-none focus:ring-blue-500 focus:border-blue-500 dark:text-gray-100 sm:text-sm";
+
+const emptyList: List = {
+  id: 0,
+  name: "",
+  type: "SONARR",
+  enabled: true,
+  client_id: 0,
+  url: "",
+  api_key: "",
+  include_unmonitored: false,
+  item_count: 0,
+  last_refresh_status: "",
+  last_refresh_error: ""
+};
+
+function ListSettings() {
+  const [list, setList] = useState<List>(emptyList);
+
+  const { data: lists } = useQuery(
+    "lists",
+    () => APIClient.lists.list(),
+    { refetchOnWindowFocus: false }
+  );
+
+  const { data: clients } = useQuery(
+    "downloadClients",
+    () => APIClient.download_clients.getAll(),
+    { refetchOnWindowFocus: false }
+  );
+
+  const arrClients = (clients ?? []).filter((c) => c.type === list.type);
+
+  const createMutation = useMutation((l: List) => APIClient.lists.create(l), {
+    onSuccess: () => {
+      toast.custom((t) => <Toast type="success" body={`List ${list.name} was added`} t={t}/>);
+      setList(emptyList);
+      queryClient.invalidateQueries("lists");
+    },
+    onError: () => {
+      toast.custom((t) => <Toast type="error" body="Could not add the list, check the settings" t={t}/>);
+    }
+  });
+
+  const toggleMutation = useMutation((l: List) => APIClient.lists.update(l), {
+    onSuccess: () => {
+      queryClient.invalidateQueries("lists");
+    }
+  });
+
+  const refreshMutation = useMutation((id: number) => APIClient.lists.refresh(id), {
+    onSuccess: () => {
+      toast.custom((t) => <Toast type="success" body="List was refreshed" t={t}/>);
+    },
+    onError: () => {
+      toast.custom((t) => <Toast type="error" body="Could not refresh the list" t={t}/>);
+    },
+    onSettled: () => {
+      queryClient.invalidateQueries("lists");
+    }
+  });
+
+  const deleteMutation = useMutation((id: number) => APIClient.lists.delete(id), {
+    onSuccess: () => {
+      queryClient.invalidateQueries("lists");
+      queryClient.invalidateQueries("filters");
+    }
+  });
+
+  return (
+    <div className="divide-y divide-gray-200 dark:divide-gray-700 lg:col-span-9">
+      <div className="py-6 px-4 sm:p-6 lg:pb-8">
+        <div>
+          <h2 className="text-lg leading-6 font-medium text-gray-900 dark:text-white">Lists</h2>
+          <p className="mt-1 text-sm text-gray-500 dark:text-gray-400">
+            Lists sync titles from Sonarr, Radarr, Trakt, Mdblist or a plain text URL every 6 hours.
+            Filters can match or exclude releases by the titles of a list.
+          </p>
+        </div>
+
+        <form
+          className="mt-6 flex flex-wrap gap-3"
+          onSubmit={(e) => {
+            e.preventDefault();
+            createMutation.mutate(list);
+          }}
+        >
+          <input
+            type="text"
+            value={list.name}
+            placeholder="Name"
+            onChange={(e) => setList({ ...list, name: e.target.value })}
+            className={`flex-1 ${inputClassName}`}
+          />
+          <select
+            value={list.type}
+            onChange={(e) => setList({ ...list, type: e.target.value as ListType, client_id: 0 })}
+            className={inputClassName}
+          >
+            {typeOptions.map((o) => (
+              <option key={o.value} value={o.value}>{o.label}</option>
+            ))}
+          </select>
+          {isArrType(list.type) ? (
+            <>
+              <select
+                value={list.client_id}
+                onChange={(e) => setList({ ...list, client_id: parseInt(e.target.value) })}
+                className={`flex-1 ${inputClassName}`}
+              >
+                <option value={0}>Select client</option>
+                {arrClients.map((c) => (
+                  <option key={c.id} value={c.id}>{c.name}</option>
+                ))}
+              </select>
+              <label className="inline-flex items-center gap-2 text-sm text-gray-700 dark:text-gray-300">
+                <input
+                  type="checkbox"
+                  checked={list.include_unmonitored}
+                  onChange={(e) => setList({ ...list, include_unmonitored: e.target.checked })}
+                  className="rounded border-gray-300 dark:border-gray-700 text-blue-600 focus:ring-blue-500"
+                />
+                Include unmonitored
+              </label>
+            </>
+          ) : (
+            <>
+              <input
+                type="text"
+                value={list.url}
+                placeholder="URL"
+                onChange={(e) => setList({ ...list, url: e.target.value })}
+                className={`flex-1 ${inputClassName}`}
+              />
+              {list.type === "TRAKT" && (
+                <input
+                  type="password"
+                  value={list.api_key}
+                  placeholder="Trakt client ID"
+                  onChange={(e) => setList({ ...list, api_key: e.target.value })}
+                  className={`flex-1 ${inputClassName}`}
+                />
+              )}
+            </>
+          )}
+          <button
+            type="submit"
+            disabled={list.name === "" || createMutation.isLoading}
+            className="relative inline-flex items-center px-4 py-2 border border-transparent shadow-sm text-sm font-medium rounded-md text-white bg-blue-600 dark:bg-blue-600 hover:bg-blue-700 dark:hover:bg-blue-700 disabled:opacity-50 focus:outline-none focus:ring-2 focus:ring-offset-2 focus:ring-blue-500"
+          >
+            Add
+          </button>
+        </form>
+
+        {lists && lists.length > 0 ? (
+          <ul className="mt-6 divide-y divide-gray-200 dark:divide-gray-700">
+            {lists.map((l) => (
+              <li key={l.id} className="py-3 flex items-center justify-between text-sm">
+                <div>
+                  <p className="font-medium text-gray-900 dark:text-white break-all">{l.name}</p>
+                  <p className="text-gray-500 dark:text-gray-400">
+                    {typeOptions.find((o) => o.value === l.type)?.label ?? l.type}
+                    {` · ${l.item_count} titles`}
+                    {l.last_refresh_time ? ` · refreshed ${simplifyDate(l.last_refresh_time)}` : " · not refreshed yet"}
+                  </p>
+                  {l.last_refresh_status === "ERROR" && (
+                    <p className="text-red-600 dark:text-red-500 break-all">{l.last_refresh_error}</p>
+                  )}
+                </div>
+                <div className="flex items-center gap-3">
+                  <button
+                    type="button"
+                    onClick={() => toggleMutation.mutate({ ...l, enabled: !l.enabled })}
+                    className={classNames(
+                      l.enabled ? "text-green-600 dark:text-green-500" : "text-gray-500 dark:text-gray-400",
+                      "text-xs font-medium uppercase"
+                    )}
+                  >
+                    {l.enabled ? "Enabled" : "Disabled"}
+                  </button>
+                  <button
+                    type="button"
+                    title="Refresh"
+                    disabled={refreshMutation.isLoading}
+                    onClick={() => refreshMutation.mutate(l.id)}
+                    className="text-gray-500 hover:text-blue-600 dark:text-gray-400 dark:hover:text-blue-500 disabled:opacity-50"
+                  >
+                    <ArrowPathIcon className="h-5 w-5" aria-hidden="true"/>
+                  </button>
+                  <button
+                    type="button"
+                    title="Delete"
+                    onClick={() => deleteMutation.mutate(l.id)}
+                    className="text-gray-500 hover:text-red-600 dark:text-gray-400 dark:hover:text-red-500"
+                  >
+                    <TrashIcon className="h-5 w-5" aria-hidden="true"/>
+                  </button>
+                </div>
+              </li>
+            ))}
+          </ul>
+        ) : (
+          <EmptySimple title="No lists" subtitle="Add a list to match filters against its titles"/>
+        )}
+      </div>
+    </div>
+  );
+}
+
+export default ListSettings;
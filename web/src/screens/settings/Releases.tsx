@@ -1,5 +1,5 @@
 import { useRef } from "react";
-import { useMutation } from "react-query";
+import { useMutation, useQuery } from "react-query";
 import { toast } from "react-hot-toast";
 
 import { APIClient } from "../../api/APIClient";
@@ -25,6 +25,24 @@ function ReleaseSettings() {
     deleteMutation.mutate();
   };
 
+  const { data: retention } = useQuery(
+    "release_retention",
+    () => APIClient.release.retention.get(),
+    { refetchOnWindowFocus: false }
+  );
+
+  const pruneMutation = useMutation(() => APIClient.release.retention.prune(), {
+    onSuccess: (res) => {
+      toast.custom((t) => (
+        <Toast type="success" body={`Pruned ${res.deleted} releases`} t={t}/>
+      ));
+
+      queryClient.invalidateQueries("releases");
+    }
+  });
+
+  const retentionEnabled = retention !== undefined && (retention.max_age_days > 0 || retention.max_rows > 0);
+
   const cancelModalButtonRef = useRef(null);
 
   return (
@@ -53,6 +71,45 @@ function ReleaseSettings() {
         </div>
       </div>
 
+      <div className="px-4 py-5 sm:p-6 border-t border-gray-200 dark:border-gray-700">
+        <div>
+          <h3 className="text-lg leading-6 font-medium text-gray-900 dark:text-white">
+            Retention
+          </h3>
+          <p className="mt-1 text-sm text-gray-500 dark:text-gray-400">
+            Old releases are pruned every hour. Configure <code>releaseRetentionDays</code>, <code>releaseRetentionMaxRows</code> and <code>releaseRetentionKeepApproved</code> in config.toml.
+          </p>
+        </div>
+
+        {retention && (
+          <dl className="mt-4 grid grid-cols-3 gap-4 text-sm">
+            <div>
+              <dt className="text-gray-500 dark:text-gray-400">Max age</dt>
+              <dd className="text-gray-900 dark:text-gray-100">{retention.max_age_days > 0 ? `${retention.max_age_days} days` : "unlimited"}</dd>
+            </div>
+            <div>
+              <dt className="text-gray-500 dark:text-gray-400">Max releases</dt>
+              <dd className="text-gray-900 dark:text-gray-100">{retention.max_rows > 0 ? retention.max_rows : "unlimited"}</dd>
+            </div>
+            <div>
+              <dt className="text-gray-500 dark:text-gray-400">Keep approved</dt>
+              <dd className="text-gray-900 dark:text-gray-100">{retention.keep_approved ? "yes" : "no"}</dd>
+            </div>
+          </dl>
+        )}
+
+        <div className="mt-4 flex justify-end">
+          <button
+            type="button"
+            disabled={!retentionEnabled || pruneMutation.isLoading}
+            onClick={() => pruneMutation.mutate()}
+            className="inline-flex items-center px-4 py-2 border border-transparent text-sm font-medium rounded-md shadow-sm text-white bg-blue-600 hover:bg-blue-700 disabled:opacity-50 focus:outline-none focus:ring-2 focus:ring-offset-2 focus:ring-blue-500"
+          >
+            Prune now
+          </button>
+        </div>
+      </div>
+
       <div className="pb-6 divide-y divide-gray-200 dark:divide-gray-700">
         <div className="px-4 py-5 sm:p-0">
           <div className="px-4 py-5 sm:p-6">
@@ -1,6 +1,14 @@
 interface APIKey {
+  id: string;
   name: string;
-  key: string;
+  // only set in the response when the key is created
+  key?: string;
+  key_hint?: string;
   scopes: string[];
+  expires_at?: string;
   created_at: Date;
-}
\ No newline at end of file
+}
+
+interface OIDCConfig {
+  enabled: boolean;
+}
@@ -0,0 +1,20 @@
+type AuditAction = "CREATE" | "UPDATE" | "DELETE";
+
+type AuditEntityType = "FILTER" | "INDEXER" | "IRC_NETWORK" | "DOWNLOAD_CLIENT" | "NOTIFICATION" | "API_KEY" | "LIST" | "FEED" | "ANNOUNCE_BLOCKLIST" | "QUOTA";
+
+interface AuditChange {
+  field: string;
+  old?: unknown;
+  new?: unknown;
+}
+
+interface AuditEntry {
+  id: number;
+  actor: string;
+  action: AuditAction;
+  entity_type: AuditEntityType;
+  entity_id: string;
+  entity_name: string;
+  changes: AuditChange[];
+  created_at: string;
+}
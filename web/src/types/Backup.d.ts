@@ -0,0 +1,11 @@
+interface DatabaseBackup {
+  name: string;
+  size: number;
+  created_at: string;
+}
+
+interface DatabaseBackupSettings {
+  dir: string;
+  interval: number;
+  retain: number;
+}
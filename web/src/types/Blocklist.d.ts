@@ -0,0 +1,24 @@
+type TorrentBlocklistType = "INFOHASH" | "GROUP";
+
+interface TorrentBlocklistEntry {
+  id: number;
+  type: TorrentBlocklistType;
+  value: string;
+  reason: string;
+  created_at?: string;
+}
+
+type AnnounceBlocklistType = "GROUP" | "UPLOADER" | "CATEGORY" | "TITLE_REGEX";
+
+interface AnnounceBlocklistRule {
+  id: number;
+  name: string;
+  enabled: boolean;
+  type: AnnounceBlocklistType;
+  value: string;
+  indexer?: string;
+  hits: number;
+  last_hit_at?: string;
+  created_at?: string;
+  updated_at?: string;
+}
@@ -4,6 +4,7 @@ type DownloadClientType =
     "DELUGE_V2" |
     "RTORRENT" |
     "TRANSMISSION" |
+    "PORLA" |
     "RADARR" |
     "SONARR" |
     "LIDARR" |
@@ -24,6 +25,17 @@ interface DownloadClientRules {
   max_active_downloads: number;
   ignore_slow_torrents: boolean;
   download_speed_threshold: number;
+  trackers?: DownloadClientTrackerRule[];
+}
+
+interface DownloadClientTrackerRule {
+  indexer: string;
+  tags?: string;
+  paused?: boolean;
+  limit_upload_speed?: number;
+  limit_download_speed?: number;
+  limit_ratio?: number;
+  limit_seed_time?: number;
 }
 
 interface DownloadClientBasicAuth {
@@ -36,6 +48,7 @@ interface DownloadClientSettings {
   apikey?: string;
   basic?: DownloadClientBasicAuth;
   rules?: DownloadClientRules;
+  watch_folder?: string;
 }
 
 interface DownloadClient {
@@ -50,4 +63,11 @@ interface DownloadClient {
   username: string;
   password: string;
   settings?: DownloadClientSettings;
+  health?: DownloadClientHealth;
+}
+
+interface DownloadClientHealth {
+  status: "UNKNOWN" | "OK" | "DOWN";
+  error?: string;
+  checked_at?: string;
 }
\ No newline at end of file
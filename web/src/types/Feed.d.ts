@@ -6,12 +6,36 @@ interface Feed {
   enabled: boolean;
   url: string;
   interval: number;
+  backfill: number;
   api_key: string;
+  cookie: string;
+  headers: string;
+  username: string;
+  password: string;
+  settings?: Record<string, string>;
+  last_success_at?: string;
+  last_error: string;
+  last_error_at?: string;
   created_at: Date;
   updated_at: Date;
 }
 
-type FeedType = "TORZNAB" | "RSS";
+type FeedType = "TORZNAB" | "RSS" | "API";
+
+interface FeedDryRunItem {
+  title: string;
+  release?: Record<string, string | number | boolean | string[] | null>;
+  matched: string[];
+  filters: FilterTestResult[];
+  error?: string;
+}
+
+interface FeedDryRunResponse {
+  feed_id: number;
+  feed: string;
+  indexer: string;
+  items: FeedDryRunItem[];
+}
 
 interface FeedCreate {
   indexer: string;
@@ -21,5 +45,8 @@ interface FeedCreate {
   url: string;
   interval: number;
   api_key?: string;
+  cookie?: string;
+  headers?: string;
+  settings?: Record<string, string>;
   indexer_id: number;
 }
@@ -21,6 +21,8 @@ interface Filter {
   freeleech: boolean;
   freeleech_percent: string;
   shows: string;
+  match_list_id?: number;
+  except_list_id?: number;
   seasons: string;
   episodes: string;
   resolutions: string[];
@@ -51,6 +53,22 @@ interface Filter {
   except_tags: string;
   tags_any: string;
   except_tags_any: string;
+  capture_patterns: string;
+  smart_delay: number;
+  smart_delay_indexers: string;
+  smart_delay_prefer_size: FilterSizePreference;
+  dedup_key: FilterDedupKey;
+  dedup_window: number;
+  arr_skip_duplicates: boolean;
+  arr_only_monitored: boolean;
+  arr_season_pack_threshold: number;
+  schedule: string;
+  torrent_file_check: boolean;
+  require_approval: boolean;
+  min_files: number;
+  max_files: number;
+  match_file_extensions: string;
+  except_file_extensions: string;
   actions_count: number;
   actions: Action[];
   indexers: Indexer[];
@@ -62,6 +80,8 @@ interface Filter {
   external_webhook_host: string;
   external_webhook_data: string;
   external_webhook_expect_status: number;
+  external_webhook_type: FilterWebhookType;
+  external_webhook_parse_body: boolean;
 }
 
 interface Action {
@@ -71,12 +91,17 @@ interface Action {
   enabled: boolean;
   exec_cmd?: string;
   exec_args?: string;
+  exec_timeout?: number;
   watch_folder?: string;
   category?: string;
   tags?: string;
   label?: string;
   save_path?: string;
+  move_completed_path?: string;
   paused?: boolean;
+  bandwidth_priority?: number;
+  bandwidth_group?: string;
+  sequential_download?: boolean;
   ignore_rules?: boolean;
   skip_hash_check: boolean;
   content_layout?: ActionContentLayout;
@@ -95,8 +120,60 @@ interface Action {
   webhook_headers: string[];
   filter_id?: number;
   client_id?: number;
+  depends_on_filter_id?: number;
+  fallback_client_id?: number;
+  external_download_client_id?: number;
+  external_download_client?: string;
+  run_condition?: string;
 }
 
 type ActionContentLayout = "ORIGINAL" | "SUBFOLDER_CREATE" | "SUBFOLDER_NONE";
 
-type ActionType = "TEST" | "EXEC" | "WATCH_FOLDER" | "WEBHOOK" | DownloadClientType;
+type ActionType = "TEST" | "EXEC" | "WATCH_FOLDER" | "WEBHOOK" | "CROSS_SEED" | DownloadClientType;
+
+type FilterSizePreference = "" | "SMALLER" | "LARGER";
+
+type FilterDedupKey = "" | "DISABLED" | "TITLE" | "TITLE_GROUP" | "TITLE_RESOLUTION_SOURCE";
+
+type FilterWebhookType = "HTTP" | "GRPC";
+
+type FilterImportConflict = "rename" | "overwrite" | "merge";
+
+interface FilterImportResult {
+  filter: Filter;
+  conflict?: FilterImportConflict;
+  missing_indexers?: string[];
+}
+
+interface FilterTestRequest {
+  indexer: string;
+  announce?: string;
+  release_name?: string;
+}
+
+interface FilterTestResult {
+  filter_id: number;
+  filter_name: string;
+  priority: number;
+  match: boolean;
+  rejections: string[];
+  skipped: string[];
+}
+
+interface FilterRejectionReason {
+  reason: string;
+  count: number;
+}
+
+interface FilterRejectionStats {
+  filter_id: number;
+  from: string;
+  to: string;
+  total: number;
+  reasons: FilterRejectionReason[];
+}
+
+interface FilterTestResponse {
+  release: Record<string, string | number | boolean | string[] | null>;
+  filters: FilterTestResult[];
+}
@@ -5,6 +5,7 @@ interface Indexer {
   enabled: boolean;
   implementation: string;
   settings: Array<IndexerSetting>;
+  proxy: string;
 }
 
 interface IndexerDefinition {
@@ -23,7 +24,14 @@ interface IndexerDefinition {
   irc: IndexerIRC;
   torznab: IndexerTorznab;
   rss: IndexerFeed;
+  api: IndexerFeed;
   parse: IndexerParse;
+  proxy?: string;
+}
+
+interface IndexerProxyTestRequest {
+  identifier: string;
+  proxy: string;
 }
 
 interface IndexerSetting {
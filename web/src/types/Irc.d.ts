@@ -8,6 +8,11 @@ interface IrcNetwork {
   pass: string;
   invite_command: string;
   nickserv?: NickServ; // optional
+  sasl_mechanism?: IrcSaslMechanism;
+  tls_client_cert?: string;
+  tls_client_key?: string;
+  bouncer?: boolean;
+  proxy?: string;
   channels: IrcChannel[];
   connected: boolean;
   connected_since: string;
@@ -22,6 +27,11 @@ interface IrcNetworkCreate {
   pass: string;
   invite_command: string;
   nickserv?: NickServ; // optional
+  sasl_mechanism?: IrcSaslMechanism;
+  tls_client_cert?: string;
+  tls_client_key?: string;
+  bouncer?: boolean;
+  proxy?: string;
   channels: IrcChannel[];
   connected: boolean;
 }
@@ -38,6 +48,7 @@ interface IrcChannel {
 interface IrcChannelWithHealth extends IrcChannel {
   monitoring_since: string;
   last_announce: string;
+  silent: boolean;
 }
 
 interface IrcNetworkWithHealth {
@@ -50,6 +61,11 @@ interface IrcNetworkWithHealth {
   pass: string;
   invite_command: string;
   nickserv?: NickServ; // optional
+  sasl_mechanism?: IrcSaslMechanism;
+  tls_client_cert?: string;
+  tls_client_key?: string;
+  bouncer?: boolean;
+  proxy?: string;
   channels: IrcChannelWithHealth[];
   connected: boolean;
   connected_since: string;
@@ -57,9 +73,31 @@ interface IrcNetworkWithHealth {
   healthy: boolean;
 }
 
+interface IrcChannelStatus {
+  name: string;
+  monitoring: boolean;
+  monitoring_since: string;
+  last_announce: string;
+  silent: boolean;
+}
+
+interface IrcNetworkStatus {
+  id: number;
+  name: string;
+  server: string;
+  enabled: boolean;
+  connected: boolean;
+  connected_since: string;
+  current_nick: string;
+  preferred_nick: string;
+  channels: IrcChannelStatus[];
+  last_activity: string;
+}
+
 interface NickServ {
   account?: string; // optional
   password?: string; // optional
+  regain?: string; // optional
 }
 
 interface Config {
@@ -71,4 +109,17 @@ interface Config {
   version: string;
   commit: string;
   date: string;
-}
\ No newline at end of file
+  errors: string[];
+}
+
+type IrcSaslMechanism = "" | "PLAIN" | "EXTERNAL";
+
+type IrcStatusEventType = "CONNECTED" | "DISCONNECTED" | "JOINED" | "PARTED" | "ANNOUNCE_SILENT" | "ANNOUNCE_RESUMED";
+
+interface IrcStatusEvent {
+  timestamp: string;
+  type: IrcStatusEventType;
+  network_id: number;
+  network: string;
+  channel?: string;
+}
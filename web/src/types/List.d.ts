@@ -0,0 +1,20 @@
+type ListType = "SONARR" | "RADARR" | "TRAKT" | "MDBLIST" | "PLAINTEXT";
+
+type ListRefreshStatus = "SUCCESS" | "ERROR" | "";
+
+interface List {
+  id: number;
+  name: string;
+  type: ListType;
+  enabled: boolean;
+  client_id: number;
+  url: string;
+  api_key: string;
+  include_unmonitored: boolean;
+  item_count: number;
+  last_refresh_time?: string;
+  last_refresh_status: ListRefreshStatus;
+  last_refresh_error: string;
+  created_at?: string;
+  updated_at?: string;
+}
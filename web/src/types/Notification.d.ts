@@ -1,5 +1,5 @@
-type NotificationType = "DISCORD" | "NOTIFIARR" | "TELEGRAM";
-type NotificationEvent = "PUSH_APPROVED" | "PUSH_REJECTED" | "PUSH_ERROR" | "IRC_DISCONNECTED" | "IRC_RECONNECTED" | "APP_UPDATE_AVAILABLE";
+type NotificationType = "DISCORD" | "NOTIFIARR" | "TELEGRAM" | "NTFY" | "APPRISE";
+type NotificationEvent = "PUSH_APPROVED" | "PUSH_REJECTED" | "PUSH_ERROR" | "IRC_DISCONNECTED" | "IRC_RECONNECTED" | "IRC_ANNOUNCE_SILENT" | "IRC_ANNOUNCE_RESUMED" | "DOWNLOAD_CLIENT_DOWN" | "DOWNLOAD_CLIENT_RECOVERED" | "RELEASE_PENDING_APPROVAL" | "APP_UPDATE_AVAILABLE";
 
 interface Notification {
   id: number;
@@ -11,4 +11,12 @@ interface Notification {
   token?: string;
   api_key?: string;
   channel?: string;
+  host?: string;
+  username?: string;
+  password?: string;
+  targets?: string;
+  topic?: string;
+  priority?: number;
+  click_url?: string;
+  templates?: Partial<Record<NotificationEvent, string>>;
 }
\ No newline at end of file
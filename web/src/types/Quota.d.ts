@@ -0,0 +1,16 @@
+type QuotaScope = "FILTER" | "INDEXER";
+type QuotaPeriod = "HOUR" | "DAY" | "WEEK" | "MONTH" | "EVER";
+
+interface Quota {
+  id: number;
+  name: string;
+  enabled: boolean;
+  scope: QuotaScope;
+  filter_id?: number;
+  indexer?: string;
+  max_downloads: number;
+  max_size: string;
+  period: QuotaPeriod;
+  created_at?: Date;
+  updated_at?: Date;
+}
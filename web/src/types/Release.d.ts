@@ -20,6 +20,7 @@ interface ReleaseActionStatus {
   client: string;
   filter: string;
   rejections: string[];
+  log?: string;
   timestamp: string
 }
 
@@ -35,9 +36,88 @@ interface ReleaseStats {
   filter_rejected_count: number;
   push_approved_count: number;
   push_rejected_count: number;
+  blocked_count: number;
 }
 
 interface ReleaseFilter {
   id: string;
   value: string;
 }
+
+type ReleaseEventType = "ANNOUNCE" | "MATCH" | "ACTION" | "ERROR" | "BLOCKED";
+
+interface ReleaseEvent {
+  timestamp: string;
+  type: ReleaseEventType;
+  indexer: string;
+  torrent_name: string;
+  filter?: string;
+  action?: string;
+  message?: string;
+}
+
+interface ReleaseReplayRequest {
+  filter_id: number;
+  release_ids: number[];
+  run_actions: boolean;
+}
+
+interface ReleaseReplayResult {
+  release_id: number;
+  torrent_name: string;
+  indexer: string;
+  match: boolean;
+  rejections: string[];
+  skipped: string[];
+  actions_queued: boolean;
+  error?: string;
+}
+
+type ActionRetryStatus = "PENDING" | "FAILED";
+
+interface ActionRetry {
+  id: number;
+  release_id: number;
+  filter_id: number;
+  action_id: number;
+  torrent_name: string;
+  indexer: string;
+  filter: string;
+  action: string;
+  action_type: ActionType;
+  status: ActionRetryStatus;
+  attempts: number;
+  last_error: string;
+  next_attempt_at: string;
+  expires_at: string;
+  created_at: string;
+}
+
+type ReleaseApprovalStatus = "PENDING" | "APPROVED" | "REJECTED" | "EXPIRED";
+
+type ReleaseApprovalDecision = "approve" | "reject";
+
+interface ReleaseApproval {
+  id: number;
+  release_id: number;
+  filter_id: number;
+  torrent_name: string;
+  indexer: string;
+  filter: string;
+  size: number;
+  status: ReleaseApprovalStatus;
+  decided_by: string;
+  decided_at?: string;
+  expires_at: string;
+  created_at: string;
+}
+
+interface ReleaseRetention {
+  max_age_days: number;
+  max_rows: number;
+  keep_approved: boolean;
+}
+
+interface ReleasePruneResponse {
+  deleted: number;
+}